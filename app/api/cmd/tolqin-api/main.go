@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"os"
 
+	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
 	config "github.com/ztimes2/tolqin/app/api/internal/api/config"
 	"github.com/ztimes2/tolqin/app/api/internal/api/router"
 	serviceauth "github.com/ztimes2/tolqin/app/api/internal/api/service/auth"
@@ -11,63 +16,329 @@ import (
 	"github.com/ztimes2/tolqin/app/api/internal/api/service/surfing"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
 	authpsql "github.com/ztimes2/tolqin/app/api/internal/pkg/auth/psql"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus/nats"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast/openmeteo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo/nominatim"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/media"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/inmemory"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/psql"
+	surfsqlite "github.com/ztimes2/tolqin/app/api/internal/pkg/surf/sqlite"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide/worldtides"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/webhook"
+	"github.com/ztimes2/tolqin/app/api/migrations"
 	"github.com/ztimes2/tolqin/app/api/pkg/httpserver"
 	logx "github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
 	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil/migrate"
 )
 
+// spotStore is a spot store that both of the spot services can use.
+type spotStore interface {
+	surfing.SpotStore
+	management.SpotStore
+}
+
 func main() {
-	conf, err := config.Load()
+	var (
+		configPath  = flag.String("config", "", "path to a YAML config file; environment variables override its values")
+		printConfig = flag.Bool("print-config", false, "print the loaded config along with sources of its values, and exit")
+	)
+	flag.Parse()
+
+	conf, err := loadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	logger, err := logx.New(conf.LogLevel, conf.LogFormat)
+	if *printConfig {
+		if err := conf.Print(os.Stdout); err != nil {
+			log.Fatalf("failed to print config: %v", err)
+		}
+		return
+	}
+
+	if err := conf.Validate(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+
+	logger, err := logx.New(
+		conf.LogLevel,
+		conf.LogFormat,
+		logx.WithWarnSampling(conf.LogWarnSampleLimit, conf.LogWarnSampleInterval),
+	)
 	if err != nil {
 		log.Fatalf("failed to initialize logger: %v", err)
 	}
 
-	db, err := psqlutil.NewDB(psqlutil.DriverNamePQ, psqlutil.Config{
-		Host:         conf.Database.Host,
-		Port:         conf.Database.Port,
-		Username:     conf.Database.Username,
-		Password:     conf.Database.Password,
-		DatabaseName: conf.Database.Name,
-		SSLMode:      psqlutil.NewSSLMode(conf.Database.SSLMode),
-	})
-	if err != nil {
-		logger.WithError(err).Fatalf("failed to connect to database: %v", err)
+	var (
+		db     *sqlx.DB
+		reader psqlutil.Queryer
+	)
+	switch conf.Database.Driver {
+	case config.DatabaseDriverPostgres:
+		cluster := connectPostgres(conf, logger)
+		defer cluster.Close()
+
+		db, reader = cluster.Primary(), cluster
+	case config.DatabaseDriverSQLite:
+		db, err = surfsqlite.Open(conf.Database.Name)
+		if err != nil {
+			logger.WithError(err).Fatalf("failed to open database: %v", err)
+		}
+		defer db.Close()
+	}
+
+	metricsRegistry := metrics.NewRegistry()
+
+	var spotStore spotStore
+	switch conf.Store {
+	case config.StorePSQL:
+		if conf.Database.Driver == config.DatabaseDriverSQLite {
+			var spotStoreOpts []surfsqlite.SpotStoreOption
+			if conf.DeletedSlugsReserved {
+				spotStoreOpts = append(spotStoreOpts, surfsqlite.WithDeletedSlugsReserved())
+			}
+
+			spotStore = surfsqlite.NewSpotStore(db, spotStoreOpts...)
+			break
+		}
+
+		spotStoreOpts := []psql.SpotStoreOption{
+			psql.WithMetrics(metricsRegistry),
+			psql.WithReader(reader),
+		}
+		if conf.Database.QueryTaggingEnabled {
+			spotStoreOpts = append(spotStoreOpts, psql.WithRequestIDTagging(router.RequestIDFromContext))
+		}
+		if conf.DeletedSlugsReserved {
+			spotStoreOpts = append(spotStoreOpts, psql.WithDeletedSlugsReserved())
+		}
+
+		spotStore = psql.NewSpotStore(db, spotStoreOpts...)
+	case config.StoreMemory:
+		logger.Warn("spots are kept in memory and will be lost once the server stops")
+		var spotStoreOpts []inmemory.SpotStoreOption
+		if conf.DeletedSlugsReserved {
+			spotStoreOpts = append(spotStoreOpts, inmemory.WithDeletedSlugsReserved())
+		}
+
+		spotStore = inmemory.NewSpotStore(spotStoreOpts...)
+	default:
+		logger.Fatalf("unsupported store: %q", conf.Store)
 	}
-	defer db.Close()
 
-	spotStore := psql.NewSpotStore(db)
+	nominatimClient := nominatim.New(nominatim.Config{
+		BaseURL: conf.Nominatim.BaseURL,
+		Timeout: conf.Nominatim.Timeout,
+	}, nominatim.WithMetrics(metricsRegistry))
+
+	// Both services share the cache and the rate limiter, so that the limit holds
+	// across all of the requests to Nominatim.
+	geocoder := geo.NewLocationCache(
+		geo.NewCircuitBreaker(
+			geo.NewRateLimiter(nominatimClient, conf.Nominatim.RateInterval),
+			geo.WithBreakerThreshold(conf.Nominatim.BreakerThreshold),
+			geo.WithBreakerCooldown(conf.Nominatim.BreakerCooldown),
+		),
+		conf.Nominatim.CacheSize,
+	)
+
+	managementOpts := []management.Option{
+		management.WithIdempotencyKeyTTL(conf.IdempotencyKeyTTL),
+		management.WithLocationsConcurrency(conf.Nominatim.MaxConcurrency),
+	}
+	if conf.DiagnosticsEnabled {
+		managementOpts = append(managementOpts, management.WithDiagnostics())
+	}
+	if conf.StrictCountryValidationEnabled {
+		managementOpts = append(managementOpts, management.WithStrictCountryValidation())
+	}
+	if conf.SlugRegenerationEnabled {
+		managementOpts = append(managementOpts, management.WithSlugRegeneration())
+	}
+
+	surfingOpts := []surfing.Option{
+		surfing.WithLocalizer(geocoder),
+	}
+
+	if conf.Forecast.MarineBaseURL != "" {
+		surfingOpts = append(surfingOpts,
+			surfing.WithForecastSource(openmeteo.New(openmeteo.Config{
+				MarineBaseURL:  conf.Forecast.MarineBaseURL,
+				WeatherBaseURL: conf.Forecast.WeatherBaseURL,
+				Timeout:        conf.Forecast.Timeout,
+			}, openmeteo.WithMetrics(metricsRegistry))),
+			surfing.WithForecastCacheTTL(conf.Forecast.CacheTTL),
+		)
+	}
+
+	if conf.Tides.APIKey != "" {
+		surfingOpts = append(surfingOpts, surfing.WithTideSource(worldtides.New(worldtides.Config{
+			BaseURL: conf.Tides.BaseURL,
+			APIKey:  conf.Tides.APIKey,
+			Timeout: conf.Tides.Timeout,
+		}, worldtides.WithMetrics(metricsRegistry))))
+	}
+
+	var mediaStorage *media.LocalStorage
+	if conf.Media.Dir != "" {
+		mediaStorage = media.NewLocalStorage(conf.Media.Dir, conf.Media.BaseURL)
+		surfingOpts = append(surfingOpts, surfing.WithMediaStorage(mediaStorage))
+		managementOpts = append(managementOpts, management.WithMediaStorage(mediaStorage))
+	}
+
+	var (
+		publishers    []eventbus.Publisher
+		shutdownFuncs []func(context.Context) error
+	)
+	if len(conf.Webhook.URLs) > 0 {
+		dispatcher := webhook.NewDispatcher(webhook.Config{
+			URLs:   conf.Webhook.URLs,
+			Secret: conf.Webhook.Secret,
+		}, webhook.WithLogger(logger))
+		// The dispatcher is closed by the server, so that flushing the queued
+		// events shares the shutdown timeout.
+		shutdownFuncs = append(shutdownFuncs, dispatcher.Close)
+
+		publishers = append(publishers, dispatcher)
+	}
+	if conf.EventBus.NATSURL != "" {
+		natsPublisher, err := nats.NewPublisher(nats.Config{
+			URL:           conf.EventBus.NATSURL,
+			SubjectPrefix: conf.EventBus.NATSSubjectPrefix,
+		}, nats.WithLogger(logger))
+		if err != nil {
+			logger.WithError(err).Fatalf("failed to initialize nats publisher: %v", err)
+		}
+		defer natsPublisher.Close()
+
+		publishers = append(publishers, natsPublisher)
+	}
+	if len(publishers) > 0 {
+		managementOpts = append(managementOpts, management.WithEventPublisher(eventbus.Fanout(publishers...)))
+	}
 
 	jwtEncodeDecoder := jwt.NewEncodeDecoder(conf.JWTSigningKey, conf.JWTExpiry)
 
+	routerOpts := []router.Option{
+		router.WithCORS(router.CORSConfig{
+			AllowedOrigins:   conf.CORS.AllowedOrigins,
+			AllowedMethods:   conf.CORS.AllowedMethods,
+			AllowedHeaders:   conf.CORS.AllowedHeaders,
+			AllowCredentials: conf.CORS.AllowCredentials,
+			MaxAge:           conf.CORS.MaxAge,
+		}),
+		router.WithMetrics(metricsRegistry),
+		router.WithDataEnvelope(router.APIGroupSurfingV1, conf.SurfingDataEnvelope),
+		router.WithDataEnvelope(router.APIGroupManagementV1, conf.ManagementDataEnvelope),
+		router.WithRequestTimeout(conf.ServerRequestTimeout),
+		router.WithMaxUploadSize(conf.Media.MaxUploadSize),
+		router.WithReadinessCheck("database", db.PingContext),
+		router.WithReadinessCheck("nominatim", nominatimClient.Ping),
+	}
+	if conf.ResponseSizeMetricsEnabled {
+		routerOpts = append(routerOpts, router.WithResponseSizeMetrics())
+	}
+	if conf.AccessLogEnabled {
+		routerOpts = append(routerOpts, router.WithAccessLog())
+	}
+	if mediaStorage != nil {
+		routerOpts = append(routerOpts, router.WithMediaHandler(mediaStorage.Handler()))
+	}
+
 	router := router.New(
 		serviceauth.NewService(
 			auth.NewPasswordSalter(),
 			auth.NewPasswordHasher(),
 			jwtEncodeDecoder,
+			// Queries of the user store are portable, so it works on top of SQLite
+			// as well.
 			authpsql.NewUserStore(db),
 		),
-		surfing.NewService(spotStore),
-		management.NewService(
-			spotStore,
-			nominatim.New(nominatim.Config{
-				BaseURL: conf.Nominatim.BaseURL,
-				Timeout: conf.Nominatim.Timeout,
-			}),
-		),
+		surfing.NewService(spotStore, surfingOpts...),
+		management.NewService(spotStore, geocoder, managementOpts...),
 		jwtEncodeDecoder,
 		logger,
+		routerOpts...,
 	)
 
-	server := httpserver.New(conf.ServerPort, router, httpserver.WithLogger(logger))
+	serverOpts := []httpserver.Option{
+		httpserver.WithLogger(logger),
+		httpserver.WithShutdownTimeout(conf.ShutdownTimeout),
+		httpserver.WithHTTP2(conf.ServerHTTP2Enabled),
+		httpserver.WithKeepAlives(conf.ServerKeepAlivesEnabled),
+		httpserver.WithIdleTimeout(conf.ServerIdleTimeout),
+	}
+	for _, fn := range shutdownFuncs {
+		serverOpts = append(serverOpts, httpserver.WithShutdownFunc(fn))
+	}
+	if conf.TLSCertFile != "" || conf.TLSKeyFile != "" {
+		serverOpts = append(serverOpts, httpserver.WithTLS(conf.TLSCertFile, conf.TLSKeyFile))
+	}
+
+	server := httpserver.New(conf.ServerPort, router, serverOpts...)
 	if err := server.ListenAndServe(); err != nil {
 		logger.WithError(err).Fatalf("server failure: %v", err)
 	}
 }
+
+func loadConfig(path string) (config.Config, error) {
+	if path == "" {
+		return config.Load()
+	}
+	return config.LoadFromFile(path)
+}
+
+// connectPostgres connects to the PostgreSQL primary and its replicas, and applies
+// pending migrations if configured to.
+func connectPostgres(conf config.Config, logger *logrus.Logger) *psqlutil.Cluster {
+	dbConfig := psqlutil.Config{
+		Host:         conf.Database.Host,
+		Port:         conf.Database.Port,
+		Username:     conf.Database.Username,
+		Password:     conf.Database.Password,
+		DatabaseName: conf.Database.Name,
+		SSLMode:      psqlutil.NewSSLMode(conf.Database.SSLMode),
+
+		MaxOpenConns:    conf.Database.MaxOpenConns,
+		MaxIdleConns:    conf.Database.MaxIdleConns,
+		ConnMaxLifetime: conf.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: conf.Database.ConnMaxIdleTime,
+	}
+
+	var replicaConfigs []psqlutil.Config
+	for _, host := range conf.Database.ReplicaHosts {
+		replicaConfig := dbConfig
+		replicaConfig.Host = host
+		replicaConfigs = append(replicaConfigs, replicaConfig)
+	}
+
+	cluster, err := psqlutil.NewCluster(
+		psqlutil.DriverNamePQ,
+		dbConfig,
+		replicaConfigs,
+		psqlutil.WithConnectRetry(conf.Database.ConnectAttempts, conf.Database.ConnectBackoff),
+		psqlutil.WithConnectTimeout(conf.Database.ConnectTimeout),
+		psqlutil.WithLogger(logger),
+	)
+	if err != nil {
+		logger.WithError(err).Fatalf("failed to connect to database: %v", err)
+	}
+
+	if conf.Database.MigrateOnStartup {
+		migrator, err := migrate.New(cluster.Primary(), migrations.FS, migrate.WithLogger(logger))
+		if err != nil {
+			logger.WithError(err).Fatalf("failed to load migrations: %v", err)
+		}
+
+		if err := migrator.Up(context.Background()); err != nil {
+			logger.WithError(err).Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	return cluster
+}
@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/lib/pq"
 	"github.com/ztimes2/tolqin/app/api/internal/cli/cmd"
 )
 
 func main() {
-	if err := cmd.New().Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		// Once the first signal is received, the default behaviour is restored, so
+		// that a second one terminates the tool right away.
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := cmd.New().ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ oops... %s\n", err.Error())
 		os.Exit(1)
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/heetch/confita"
@@ -10,54 +11,339 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/ztimes2/tolqin/app/api/pkg/dotenv"
 	"github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/yamlenv"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	defaultRequestTimeout  = 10 * time.Second
+
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+
+	defaultDatabaseMaxOpenConns    = 25
+	defaultDatabaseMaxIdleConns    = 10
+	defaultDatabaseConnMaxLifetime = 30 * time.Minute
+	defaultDatabaseConnMaxIdleTime = 5 * time.Minute
+	defaultDatabaseConnectAttempts = 10
+	defaultDatabaseConnectBackoff  = time.Second
+	defaultDatabaseConnectTimeout  = 5 * time.Second
+
+	defaultNominatimCacheSize    = 10000
+	defaultNominatimRateInterval = time.Second
+
+	defaultForecastCacheTTL = 15 * time.Minute
+
+	defaultMediaBaseURL       = "/media"
+	defaultMediaMaxUploadSize = 10 << 20
+)
+
+const (
+	// StorePSQL keeps spots in the database, which is either PostgreSQL or SQLite
+	// depending on DATABASE_DRIVER.
+	StorePSQL = "psql"
+
+	// StoreMemory keeps spots in memory, so they are lost once the server stops.
+	// It is meant for local development.
+	StoreMemory = "memory"
+)
+
+const (
+	// DatabaseDriverPostgres connects to a PostgreSQL server.
+	DatabaseDriverPostgres = "postgres"
+
+	// DatabaseDriverSQLite opens an SQLite database file, so that the server can
+	// run as a single binary without a database server.
+	DatabaseDriverSQLite = "sqlite"
 )
 
 type Config struct {
 	Database
 	Logger
 	Nominatim
+	Forecast
+	Tides
+	CORS
+	Webhook
+	EventBus
+	Media
 
 	ServerPort string `config:"SERVER_PORT,required"`
 
+	// Store selects where spots are kept: either psql or memory. It defaults to
+	// psql, which keeps spots in the database selected by DATABASE_DRIVER. Users
+	// are kept in the database regardless of it.
+	Store string `config:"STORE"`
+
+	// ServerHTTP2Enabled, ServerKeepAlivesEnabled, and ServerIdleTimeout tune how
+	// the server handles connections. They default to Go's standard behavior.
+	ServerHTTP2Enabled      bool          `config:"SERVER_HTTP2_ENABLED"`
+	ServerKeepAlivesEnabled bool          `config:"SERVER_KEEP_ALIVES_ENABLED"`
+	ServerIdleTimeout       time.Duration `config:"SERVER_IDLE_TIMEOUT"`
+
+	// ServerRequestTimeout limits how long a single request may take before its
+	// context gets canceled. Zero disables the limit.
+	ServerRequestTimeout time.Duration `config:"SERVER_REQUEST_TIMEOUT"`
+
+	// ShutdownTimeout limits how long the server waits for in-flight requests to
+	// complete during a graceful shutdown.
+	ShutdownTimeout time.Duration `config:"SHUTDOWN_TIMEOUT"`
+
+	// TLSCertFile and TLSKeyFile enable serving HTTPS when both are set.
+	TLSCertFile string `config:"TLS_CERT_FILE"`
+	TLSKeyFile  string `config:"TLS_KEY_FILE"`
+
 	JWTSigningKey string        `config:"JWT_SIGNING_KEY,required"`
 	JWTExpiry     time.Duration `config:"JWT_EXPIRY,required"`
+
+	// IdempotencyKeyTTL is how long spots created with an Idempotency-Key header
+	// are returned to repeated requests with the same key.
+	IdempotencyKeyTTL time.Duration `config:"IDEMPOTENCY_KEY_TTL"`
+
+	// DiagnosticsEnabled enables diagnostic capabilities for admins, such as
+	// capturing query plans.
+	DiagnosticsEnabled bool `config:"DIAGNOSTICS_ENABLED"`
+
+	// StrictCountryValidationEnabled verifies via the geocoder that country codes
+	// of spots updated without moving them match their coordinates.
+	StrictCountryValidationEnabled bool `config:"STRICT_COUNTRY_VALIDATION_ENABLED"`
+
+	// SlugRegenerationEnabled regenerates slugs of spots whose names get updated.
+	SlugRegenerationEnabled bool `config:"SLUG_REGENERATION_ENABLED"`
+
+	// DeletedSlugsReserved keeps slugs of soft-deleted spots from being reused by
+	// other spots, so that restored spots keep their URLs.
+	DeletedSlugsReserved bool `config:"DELETED_SLUGS_RESERVED"`
+
+	// ResponseSizeMetricsEnabled records sizes of response bodies per route in
+	// addition to the other HTTP metrics.
+	ResponseSizeMetricsEnabled bool `config:"RESPONSE_SIZE_METRICS_ENABLED"`
+
+	// AccessLogEnabled logs every handled request. It is enabled by default.
+	AccessLogEnabled bool `config:"ACCESS_LOG_ENABLED"`
+
+	// SurfingDataEnvelope and ManagementDataEnvelope control whether successful
+	// responses of the respective APIs are wrapped in {"data": ...}.
+	SurfingDataEnvelope    bool `config:"SURFING_DATA_ENVELOPE"`
+	ManagementDataEnvelope bool `config:"MANAGEMENT_DATA_ENVELOPE"`
+
+	// sources holds names of backends that values were loaded from by their keys.
+	sources map[string]string
 }
 
 type Database struct {
-	Host     string `config:"DB_HOST,required"`
-	Port     string `config:"DB_PORT,required"`
+	// Driver selects the database: either postgres or sqlite. It defaults to
+	// postgres. Name is a path of the database file for sqlite, and the rest of
+	// the settings apply to postgres only.
+	Driver string `config:"DATABASE_DRIVER"`
+
+	// Host and Port are required for postgres, which Validate checks.
+	Host     string `config:"DB_HOST"`
+	Port     string `config:"DB_PORT"`
 	Username string `config:"DB_USERNAME"`
 	Password string `config:"DB_PASSWORD"`
 	Name     string `config:"DB_NAME,required"`
 	SSLMode  string `config:"DB_SSLMODE"`
+
+	// ReplicaHosts lists hosts of read replicas that read-only spot queries are
+	// routed to. Replicas share the rest of the settings with the primary.
+	ReplicaHosts []string `config:"DB_REPLICA_HOSTS"`
+
+	// MaxOpenConns, MaxIdleConns, ConnMaxLifetime, and ConnMaxIdleTime tune the
+	// connection pool. Zero values fall back to Go's defaults.
+	MaxOpenConns    int           `config:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `config:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `config:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `config:"DB_CONN_MAX_IDLE_TIME"`
+
+	// ConnectAttempts, ConnectBackoff, and ConnectTimeout control how long the
+	// server waits for the database to become reachable on startup. The timeout
+	// applies to each of the attempts.
+	ConnectAttempts int           `config:"DB_CONNECT_ATTEMPTS"`
+	ConnectBackoff  time.Duration `config:"DB_CONNECT_BACKOFF"`
+	ConnectTimeout  time.Duration `config:"DB_CONNECT_TIMEOUT"`
+
+	// MigrateOnStartup applies pending migrations before the server starts.
+	MigrateOnStartup bool `config:"DB_MIGRATE_ON_STARTUP"`
+
+	// QueryTaggingEnabled prefixes queries with a comment holding an ID of the
+	// request that issued them.
+	QueryTaggingEnabled bool `config:"DB_QUERY_TAGGING_ENABLED"`
 }
 
 type Logger struct {
 	LogLevel  string `config:"LOG_LEVEL"`
 	LogFormat string `config:"LOG_FORMAT"`
+
+	// LogWarnSampleLimit caps a number of identical warn logs that are written
+	// per LogWarnSampleInterval. Zero disables sampling.
+	LogWarnSampleLimit    int           `config:"LOG_WARN_SAMPLE_LIMIT"`
+	LogWarnSampleInterval time.Duration `config:"LOG_WARN_SAMPLE_INTERVAL"`
 }
 
 type Nominatim struct {
 	BaseURL string        `config:"NOMINATIM_BASE_URL,required"`
 	Timeout time.Duration `config:"NOMINATIM_TIMEOUT"`
+
+	// CacheSize caps a number of localized locations that are cached in memory.
+	// Zero disables caching.
+	CacheSize int `config:"NOMINATIM_CACHE_SIZE"`
+
+	// RateInterval is a minimum interval between requests, which defaults to a
+	// second as the usage policy of the public Nominatim requires. Zero disables
+	// rate limiting, e.g. for a self-hosted instance.
+	RateInterval time.Duration `config:"NOMINATIM_RATE_INTERVAL"`
+
+	// BreakerThreshold is a number of consecutive failed requests after which
+	// requests stop being sent for BreakerCooldown. Zero values fall back to the
+	// breaker's defaults.
+	BreakerThreshold int           `config:"NOMINATIM_BREAKER_THRESHOLD"`
+	BreakerCooldown  time.Duration `config:"NOMINATIM_BREAKER_COOLDOWN"`
+
+	// MaxConcurrency caps a number of locations of a batch that are looked up at
+	// the same time. Zero falls back to the service's default.
+	MaxConcurrency int `config:"NOMINATIM_MAX_CONCURRENCY"`
+}
+
+type Forecast struct {
+	// MarineBaseURL and WeatherBaseURL point to the Open-Meteo marine and weather
+	// APIs that waves and wind are forecasted by respectively. Forecasts are
+	// unavailable when they are empty.
+	MarineBaseURL  string        `config:"FORECAST_MARINE_BASE_URL"`
+	WeatherBaseURL string        `config:"FORECAST_WEATHER_BASE_URL"`
+	Timeout        time.Duration `config:"FORECAST_TIMEOUT"`
+
+	// CacheTTL is how long forecasts are cached in memory per spot. Zero disables
+	// caching.
+	CacheTTL time.Duration `config:"FORECAST_CACHE_TTL"`
+}
+
+type Tides struct {
+	// BaseURL and APIKey are used for connecting to the WorldTides API. Tides are
+	// unavailable when the API key is empty.
+	BaseURL string        `config:"TIDES_BASE_URL"`
+	APIKey  string        `config:"TIDES_API_KEY"`
+	Timeout time.Duration `config:"TIDES_TIMEOUT"`
+}
+
+type CORS struct {
+	AllowedOrigins   []string      `config:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string      `config:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string      `config:"CORS_ALLOWED_HEADERS"`
+	AllowCredentials bool          `config:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `config:"CORS_MAX_AGE"`
+}
+
+type Webhook struct {
+	// URLs lists endpoints that are notified about created, updated, and deleted
+	// spots. Notifications are disabled when it is empty.
+	URLs []string `config:"WEBHOOK_URLS"`
+
+	// Secret is used for signing notifications.
+	Secret string `config:"WEBHOOK_SECRET"`
+}
+
+type EventBus struct {
+	// NATSURL is an address of a NATS server such as nats://localhost:4222 that
+	// events about created, updated, and deleted spots are published to. Events
+	// are not published when it is empty.
+	NATSURL string `config:"EVENTBUS_NATS_URL"`
+
+	// NATSSubjectPrefix is prepended to types of events in order to form subjects,
+	// e.g. tolqin.spot.created.
+	NATSSubjectPrefix string `config:"EVENTBUS_NATS_SUBJECT_PREFIX"`
 }
 
+type Media struct {
+	// Dir is a directory in which content of photos of spots is kept and from
+	// which it is served under /media. Photos are unavailable when it is empty.
+	Dir string `config:"MEDIA_DIR"`
+
+	// BaseURL is prepended to keys of photos in order to form their URLs. It may
+	// point to a CDN in front of the server.
+	BaseURL string `config:"MEDIA_BASE_URL"`
+
+	// MaxUploadSize limits sizes of uploaded photos in bytes.
+	MaxUploadSize int64 `config:"MEDIA_MAX_UPLOAD_SIZE"`
+}
+
+// Load loads configuration from environment variables and a .env file, which take
+// precedence in that order. Unset optional variables keep their defaults. Loaded
+// values are expected to be checked using Validate.
 func Load() (Config, error) {
+	return load()
+}
+
+// LoadFromFile loads configuration in the same way as Load does, and falls back to
+// the YAML file at the given path for variables that are set neither in the
+// environment nor in a .env file. See yamlenv.YAMLEnv for the format of the file.
+func LoadFromFile(path string) (Config, error) {
+	file, err := yamlenv.NewBackend(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	return load(file)
+}
+
+func load(fallbacks ...backend.Backend) (Config, error) {
 	cfg := Config{
+		Store:                   StorePSQL,
+		ShutdownTimeout:         defaultShutdownTimeout,
+		ServerRequestTimeout:    defaultRequestTimeout,
+		IdempotencyKeyTTL:       defaultIdempotencyKeyTTL,
+		ServerHTTP2Enabled:      true,
+		ServerKeepAlivesEnabled: true,
+		SurfingDataEnvelope:     true,
+		AccessLogEnabled:        true,
+		ManagementDataEnvelope:  true,
+		Database: Database{
+			Driver:          DatabaseDriverPostgres,
+			MaxOpenConns:    defaultDatabaseMaxOpenConns,
+			MaxIdleConns:    defaultDatabaseMaxIdleConns,
+			ConnMaxLifetime: defaultDatabaseConnMaxLifetime,
+			ConnMaxIdleTime: defaultDatabaseConnMaxIdleTime,
+			ConnectAttempts: defaultDatabaseConnectAttempts,
+			ConnectBackoff:  defaultDatabaseConnectBackoff,
+			ConnectTimeout:  defaultDatabaseConnectTimeout,
+		},
+		Nominatim: Nominatim{
+			CacheSize:    defaultNominatimCacheSize,
+			RateInterval: defaultNominatimRateInterval,
+		},
+		Forecast: Forecast{
+			CacheTTL: defaultForecastCacheTTL,
+		},
+		Media: Media{
+			BaseURL:       defaultMediaBaseURL,
+			MaxUploadSize: defaultMediaMaxUploadSize,
+		},
 		Logger: Logger{
-			LogLevel:  logrus.InfoLevel.String(),
-			LogFormat: log.FormatJSON,
+			LogLevel:              logrus.InfoLevel.String(),
+			LogFormat:             log.FormatJSON,
+			LogWarnSampleInterval: time.Second,
 		},
 	}
 
-	backends := []backend.Backend{
+	backends := append([]backend.Backend{
 		env.NewBackend(),
 		dotenv.NewBackend(),
+	}, fallbacks...)
+
+	// Confita stops querying backends for a key once one of them has it, so the
+	// first backend that returns a key is the one whose value is kept.
+	sources := make(map[string]string)
+	for i, b := range backends {
+		backends[i] = &sourceRecorder{
+			Backend: b,
+			sources: sources,
+		}
 	}
 
 	if err := confita.NewLoader(backends...).Load(context.Background(), &cfg); err != nil {
 		return Config{}, err
 	}
 
+	cfg.sources = sources
+
 	return cfg, nil
 }
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
+	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(
+		"SERVER_PORT: 8080\n"+
+			"DB_HOST: file-host\n"+
+			"DB_PORT: 5432\n"+
+			"DB_NAME: tolqin\n"+
+			"NOMINATIM_BASE_URL: http://localhost:8088\n"+
+			"JWT_SIGNING_KEY: secret\n"+
+			"JWT_EXPIRY: 1h\n",
+	), 0o600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Setenv("DB_HOST", "env-host")
+
+	cfg, err := LoadFromFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "env-host", cfg.Database.Host)
+	assert.Equal(t, "5432", cfg.Database.Port)
+	assert.Equal(t, defaultShutdownTimeout, cfg.ShutdownTimeout)
+	assert.Equal(t, defaultRequestTimeout, cfg.ServerRequestTimeout)
+	assert.Equal(t, defaultIdempotencyKeyTTL, cfg.IdempotencyKeyTTL)
+	assert.Equal(t, int64(defaultMediaMaxUploadSize), cfg.Media.MaxUploadSize)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, cfg.Print(&buf)) {
+		return
+	}
+
+	output := buf.String()
+	assert.Contains(t, output, "DB_HOST=env-host (env)\n")
+	assert.Contains(t, output, "DB_PORT=5432 ("+path+")\n")
+	assert.Contains(t, output, "SHUTDOWN_TIMEOUT=30s (default)\n")
+	assert.Contains(t, output, "JWT_SIGNING_KEY=****** ("+path+")\n")
+	assert.NotContains(t, output, "secret")
+}
+
+func validConfig() Config {
+	return Config{
+		ServerPort:        "8080",
+		Store:             StorePSQL,
+		ShutdownTimeout:   defaultShutdownTimeout,
+		JWTSigningKey:     "secret",
+		JWTExpiry:         time.Hour,
+		IdempotencyKeyTTL: defaultIdempotencyKeyTTL,
+		Database: Database{
+			Driver: DatabaseDriverPostgres,
+			Host:   "localhost",
+			Port:   "5432",
+			Name:   "tolqin",
+		},
+		Logger: Logger{
+			LogLevel:  "info",
+			LogFormat: "json",
+		},
+		Nominatim: Nominatim{
+			BaseURL: "https://nominatim.openstreetmap.org",
+		},
+		Media: Media{
+			BaseURL:       defaultMediaBaseURL,
+			MaxUploadSize: defaultMediaMaxUploadSize,
+		},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(*Config)
+		expectedKeys []string
+	}{
+		{
+			name:   "return no error for valid config",
+			modify: func(c *Config) {},
+		},
+		{
+			name: "return no error for sqlite without host and port",
+			modify: func(c *Config) {
+				c.Database = Database{
+					Driver: DatabaseDriverSQLite,
+					Name:   "tolqin.db",
+				}
+			},
+		},
+		{
+			name: "return errors for invalid server settings",
+			modify: func(c *Config) {
+				c.ServerPort = "http"
+				c.Store = "redis"
+				c.ServerRequestTimeout = -time.Second
+				c.TLSCertFile = "cert.pem"
+			},
+			expectedKeys: []string{"SERVER_PORT", "STORE", "SERVER_REQUEST_TIMEOUT", "TLS_CERT_FILE"},
+		},
+		{
+			name: "return errors for invalid jwt settings",
+			modify: func(c *Config) {
+				c.JWTSigningKey = ""
+				c.JWTExpiry = -time.Minute
+				c.IdempotencyKeyTTL = 0
+			},
+			expectedKeys: []string{"JWT_SIGNING_KEY", "JWT_EXPIRY", "IDEMPOTENCY_KEY_TTL"},
+		},
+		{
+			name: "return errors for invalid database settings",
+			modify: func(c *Config) {
+				c.Database.Host = ""
+				c.Database.Port = "99999"
+				c.Database.MaxOpenConns = -1
+				c.Database.ConnectTimeout = -time.Second
+			},
+			expectedKeys: []string{"DB_HOST", "DB_PORT", "DB_MAX_OPEN_CONNS", "DB_CONNECT_TIMEOUT"},
+		},
+		{
+			name: "return error for unsupported database driver",
+			modify: func(c *Config) {
+				c.Database.Driver = "mysql"
+			},
+			expectedKeys: []string{"DATABASE_DRIVER"},
+		},
+		{
+			name: "return errors for invalid logger settings",
+			modify: func(c *Config) {
+				c.LogLevel = "loud"
+				c.LogFormat = "xml"
+				c.LogWarnSampleLimit = 10
+			},
+			expectedKeys: []string{"LOG_LEVEL", "LOG_FORMAT", "LOG_WARN_SAMPLE_INTERVAL"},
+		},
+		{
+			name: "return errors for invalid urls",
+			modify: func(c *Config) {
+				c.Nominatim.BaseURL = "nominatim.openstreetmap.org"
+				c.Webhook.URLs = []string{"ftp://example.com"}
+				c.EventBus.NATSURL = "localhost:4222"
+			},
+			expectedKeys: []string{"NOMINATIM_BASE_URL", "WEBHOOK_URLS", "WEBHOOK_SECRET", "EVENTBUS_NATS_URL"},
+		},
+		{
+			name: "return errors for invalid nominatim settings",
+			modify: func(c *Config) {
+				c.Nominatim.CacheSize = -1
+				c.Nominatim.RateInterval = -time.Second
+				c.Nominatim.MaxConcurrency = -1
+			},
+			expectedKeys: []string{"NOMINATIM_CACHE_SIZE", "NOMINATIM_RATE_INTERVAL", "NOMINATIM_MAX_CONCURRENCY"},
+		},
+		{
+			name: "return errors for invalid forecast settings",
+			modify: func(c *Config) {
+				c.Forecast.MarineBaseURL = "marine-api.open-meteo.com"
+				c.Forecast.Timeout = -time.Second
+				c.Forecast.CacheTTL = -time.Minute
+			},
+			expectedKeys: []string{
+				"FORECAST_MARINE_BASE_URL",
+				"FORECAST_WEATHER_BASE_URL",
+				"FORECAST_TIMEOUT",
+				"FORECAST_CACHE_TTL",
+			},
+		},
+		{
+			name: "return errors for invalid tides settings",
+			modify: func(c *Config) {
+				c.Tides.APIKey = "key"
+				c.Tides.BaseURL = ""
+				c.Tides.Timeout = -time.Second
+			},
+			expectedKeys: []string{"TIDES_BASE_URL", "TIDES_TIMEOUT"},
+		},
+		{
+			name: "return errors for invalid media settings",
+			modify: func(c *Config) {
+				c.Media.Dir = "media"
+				c.Media.BaseURL = ""
+				c.Media.MaxUploadSize = 0
+			},
+			expectedKeys: []string{"MEDIA_BASE_URL", "MEDIA_MAX_UPLOAD_SIZE"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validConfig()
+			test.modify(&cfg)
+
+			err := cfg.Validate()
+			if len(test.expectedKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var vErr *valerra.Errors
+			if !assert.ErrorAs(t, err, &vErr) {
+				return
+			}
+
+			var keys []string
+			for _, e := range vErr.Errors() {
+				var fErr *valerrautil.FieldError
+				if assert.ErrorAs(t, e, &fErr) {
+					keys = append(keys, fErr.Field)
+				}
+			}
+			assert.Equal(t, test.expectedKeys, keys)
+		})
+	}
+}
+
+func TestConfig_Validate_Message(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerPort = ""
+	cfg.JWTSigningKey = ""
+
+	assert.EqualError(t, cfg.Validate(), "SERVER_PORT: must be a port number; JWT_SIGNING_KEY: must not be empty")
+}
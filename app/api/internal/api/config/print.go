@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/heetch/confita/backend"
+)
+
+// sourceDefault is reported as a source of values that no backend has.
+const sourceDefault = "default"
+
+// secretKeys holds keys whose values are masked when printed.
+var secretKeys = map[string]bool{
+	"DB_PASSWORD":     true,
+	"JWT_SIGNING_KEY": true,
+	"TIDES_API_KEY":   true,
+	"WEBHOOK_SECRET":  true,
+}
+
+// sourceRecorder wraps a backend and records its name for every key it returns.
+type sourceRecorder struct {
+	backend.Backend
+	sources map[string]string
+}
+
+func (r *sourceRecorder) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.Backend.Get(ctx, key)
+	if err == nil {
+		r.sources[key] = r.Backend.Name()
+	}
+	return value, err
+}
+
+// Print writes every key of the configuration along with its value and the source
+// the value came from, which is either a backend such as env or .env, a path of the
+// config file, or default. Values of secrets are masked.
+func (c Config) Print(w io.Writer) error {
+	var lines []string
+	walkKeys(reflect.ValueOf(c), func(key string, v reflect.Value) {
+		source, ok := c.sources[key]
+		if !ok {
+			source = sourceDefault
+		}
+
+		value := formatValue(v)
+		if secretKeys[key] && value != "" {
+			value = "******"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s=%s (%s)", key, value, source))
+	})
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// walkKeys calls the given function for every field of the given struct, including
+// fields of embedded structs, that has a config tag.
+func walkKeys(v reflect.Value, fn func(key string, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			walkKeys(v.Field(i), fn)
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup("config")
+		if !ok {
+			continue
+		}
+		fn(strings.Split(tag, ",")[0], v.Field(i))
+	}
+}
+
+func formatValue(v reflect.Value) string {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	if v.Kind() == reflect.Slice {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
+)
+
+// Validate checks values of the config and returns errors of all the keys that
+// failed validation at once as valerrautil.FieldErrors, whose underlying errors
+// are *valerrautil.FieldError.
+func (c Config) Validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerrautil.IsPort(c.ServerPort), fieldError("SERVER_PORT", "must be a port number"))
+	v.IfFalse(valerrautil.IsOneOf(c.Store, StorePSQL, StoreMemory), fieldError("STORE", "must be psql or memory"))
+	v.IfFalse(nonNegative(int64(c.ServerIdleTimeout)), fieldError("SERVER_IDLE_TIMEOUT", "must not be negative"))
+	v.IfFalse(nonNegative(int64(c.ServerRequestTimeout)), fieldError("SERVER_REQUEST_TIMEOUT", "must not be negative"))
+	v.IfFalse(positive(int64(c.ShutdownTimeout)), fieldError("SHUTDOWN_TIMEOUT", "must be positive"))
+	v.IfFalse(func() bool {
+		return (c.TLSCertFile == "") == (c.TLSKeyFile == "")
+	}, fieldError("TLS_CERT_FILE", "must be set along with TLS_KEY_FILE"))
+	v.IfFalse(valerra.StringNotEmpty(c.JWTSigningKey), fieldError("JWT_SIGNING_KEY", "must not be empty"))
+	v.IfFalse(positive(int64(c.JWTExpiry)), fieldError("JWT_EXPIRY", "must be positive"))
+	v.IfFalse(positive(int64(c.IdempotencyKeyTTL)), fieldError("IDEMPOTENCY_KEY_TTL", "must be positive"))
+
+	c.Database.validate(v)
+	c.Logger.validate(v)
+	c.Nominatim.validate(v)
+	c.Forecast.validate(v)
+	c.Tides.validate(v)
+	c.CORS.validate(v)
+	c.Webhook.validate(v)
+	c.EventBus.validate(v)
+	c.Media.validate(v)
+
+	return valerrautil.ValidateFields(v)
+}
+
+func (d Database) validate(v *valerra.Validator) {
+	v.IfFalse(
+		valerrautil.IsOneOf(d.Driver, DatabaseDriverPostgres, DatabaseDriverSQLite),
+		fieldError("DATABASE_DRIVER", "must be postgres or sqlite"),
+	)
+	v.IfFalse(valerra.StringNotEmpty(d.Name), fieldError("DB_NAME", "must not be empty"))
+
+	if d.Driver != DatabaseDriverPostgres {
+		return
+	}
+
+	v.IfFalse(valerra.StringNotEmpty(d.Host), fieldError("DB_HOST", "must not be empty for postgres"))
+	v.IfFalse(valerrautil.IsPort(d.Port), fieldError("DB_PORT", "must be a port number"))
+	v.IfFalse(func() bool {
+		for _, h := range d.ReplicaHosts {
+			if h == "" {
+				return false
+			}
+		}
+		return true
+	}, fieldError("DB_REPLICA_HOSTS", "must not contain empty hosts"))
+	v.IfFalse(nonNegative(int64(d.MaxOpenConns)), fieldError("DB_MAX_OPEN_CONNS", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.MaxIdleConns)), fieldError("DB_MAX_IDLE_CONNS", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.ConnMaxLifetime)), fieldError("DB_CONN_MAX_LIFETIME", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.ConnMaxIdleTime)), fieldError("DB_CONN_MAX_IDLE_TIME", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.ConnectAttempts)), fieldError("DB_CONNECT_ATTEMPTS", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.ConnectBackoff)), fieldError("DB_CONNECT_BACKOFF", "must not be negative"))
+	v.IfFalse(nonNegative(int64(d.ConnectTimeout)), fieldError("DB_CONNECT_TIMEOUT", "must not be negative"))
+}
+
+func (l Logger) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		_, err := logrus.ParseLevel(l.LogLevel)
+		return err == nil
+	}, fieldError("LOG_LEVEL", "must be a log level such as debug, info, or warn"))
+	v.IfFalse(
+		valerrautil.IsOneOf(l.LogFormat, log.FormatJSON, log.FormatText),
+		fieldError("LOG_FORMAT", "must be json or text"),
+	)
+	v.IfFalse(nonNegative(int64(l.LogWarnSampleLimit)), fieldError("LOG_WARN_SAMPLE_LIMIT", "must not be negative"))
+	v.IfFalse(func() bool {
+		return l.LogWarnSampleLimit == 0 || l.LogWarnSampleInterval > 0
+	}, fieldError("LOG_WARN_SAMPLE_INTERVAL", "must be positive when sampling is enabled"))
+}
+
+func (n Nominatim) validate(v *valerra.Validator) {
+	v.IfFalse(
+		valerrautil.IsURL(n.BaseURL, "http", "https"),
+		fieldError("NOMINATIM_BASE_URL", "must be an http or https URL"),
+	)
+	v.IfFalse(nonNegative(int64(n.Timeout)), fieldError("NOMINATIM_TIMEOUT", "must not be negative"))
+	v.IfFalse(nonNegative(int64(n.CacheSize)), fieldError("NOMINATIM_CACHE_SIZE", "must not be negative"))
+	v.IfFalse(nonNegative(int64(n.RateInterval)), fieldError("NOMINATIM_RATE_INTERVAL", "must not be negative"))
+	v.IfFalse(nonNegative(int64(n.BreakerThreshold)), fieldError("NOMINATIM_BREAKER_THRESHOLD", "must not be negative"))
+	v.IfFalse(nonNegative(int64(n.BreakerCooldown)), fieldError("NOMINATIM_BREAKER_COOLDOWN", "must not be negative"))
+	v.IfFalse(nonNegative(int64(n.MaxConcurrency)), fieldError("NOMINATIM_MAX_CONCURRENCY", "must not be negative"))
+}
+
+func (f Forecast) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		return f.MarineBaseURL == "" || valerrautil.IsURL(f.MarineBaseURL, "http", "https")()
+	}, fieldError("FORECAST_MARINE_BASE_URL", "must be an http or https URL"))
+	v.IfFalse(func() bool {
+		return f.WeatherBaseURL == "" || valerrautil.IsURL(f.WeatherBaseURL, "http", "https")()
+	}, fieldError("FORECAST_WEATHER_BASE_URL", "must be an http or https URL"))
+	v.IfFalse(func() bool {
+		return (f.MarineBaseURL == "") == (f.WeatherBaseURL == "")
+	}, fieldError("FORECAST_WEATHER_BASE_URL", "must be set along with FORECAST_MARINE_BASE_URL"))
+	v.IfFalse(nonNegative(int64(f.Timeout)), fieldError("FORECAST_TIMEOUT", "must not be negative"))
+	v.IfFalse(nonNegative(int64(f.CacheTTL)), fieldError("FORECAST_CACHE_TTL", "must not be negative"))
+}
+
+func (t Tides) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		return t.APIKey == "" || valerrautil.IsURL(t.BaseURL, "http", "https")()
+	}, fieldError("TIDES_BASE_URL", "must be an http or https URL when tides are enabled"))
+	v.IfFalse(nonNegative(int64(t.Timeout)), fieldError("TIDES_TIMEOUT", "must not be negative"))
+}
+
+func (c CORS) validate(v *valerra.Validator) {
+	v.IfFalse(nonNegative(int64(c.MaxAge)), fieldError("CORS_MAX_AGE", "must not be negative"))
+}
+
+func (w Webhook) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		for _, u := range w.URLs {
+			if !valerrautil.IsURL(u, "http", "https")() {
+				return false
+			}
+		}
+		return true
+	}, fieldError("WEBHOOK_URLS", "must be http or https URLs"))
+	v.IfFalse(func() bool {
+		return len(w.URLs) == 0 || w.Secret != ""
+	}, fieldError("WEBHOOK_SECRET", "must not be empty when webhooks are enabled"))
+}
+
+func (e EventBus) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		return e.NATSURL == "" || valerrautil.IsURL(e.NATSURL, "nats", "tls")()
+	}, fieldError("EVENTBUS_NATS_URL", "must be a nats or tls URL"))
+}
+
+func (m Media) validate(v *valerra.Validator) {
+	v.IfFalse(func() bool {
+		return m.Dir == "" || m.BaseURL != ""
+	}, fieldError("MEDIA_BASE_URL", "must not be empty when photos are enabled"))
+	v.IfFalse(positive(m.MaxUploadSize), fieldError("MEDIA_MAX_UPLOAD_SIZE", "must be positive"))
+}
+
+func fieldError(key, reason string) error {
+	return valerrautil.NewFieldError(key, reason)
+}
+
+func positive(n int64) valerra.Condition {
+	return func() bool {
+		return n > 0
+	}
+}
+
+func nonNegative(n int64) valerra.Condition {
+	return func() bool {
+		return n >= 0
+	}
+}
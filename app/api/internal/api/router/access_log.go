@@ -0,0 +1,61 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// withAccessLog logs every request once it is handled with its method, path,
+// response status code, duration, number of written bytes, remote IP, and ID.
+// Requests are logged at info level, whereas 4xx and 5xx responses are logged at
+// warning and error levels respectively.
+func withAccessLog(l *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusResponseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(sw, r)
+
+			status := sw.statusCode()
+			entry := l.WithFields(logrus.Fields{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      status,
+				"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes":       sw.size,
+				"remote_ip":   remoteIP(r),
+			})
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				entry = entry.WithField("request_id", id)
+			}
+
+			level := logrus.InfoLevel
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = logrus.ErrorLevel
+			case status >= http.StatusBadRequest:
+				level = logrus.WarnLevel
+			}
+
+			entry.Logf(level, "%s %s %d", r.Method, r.URL.Path, status)
+		})
+	}
+}
+
+// remoteIP returns an IP address of the client that sent the request without its
+// port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestWithAccessLog(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedLevel  string
+		expectedStatus float64
+	}{
+		{
+			name:           "log successful request at info level",
+			path:           "/health",
+			expectedLevel:  "info",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "log client error at warning level",
+			path:           "/unknown",
+			expectedLevel:  "warning",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "log server error at error level",
+			path:           "/surfing/v1/spots/1",
+			expectedLevel:  "error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&buf)
+			logger.SetFormatter(&logrus.JSONFormatter{})
+
+			service := newMockSurfingService()
+			service.
+				On("Spot", mock.Anything, "1").
+				Return(surf.Spot{}, errors.New("something went wrong"))
+
+			server := httptest.NewServer(newRouter(nil, service, nil, nil, logger, WithAccessLog()))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			req.Header.Set(headerRequestID, "abc-123")
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp.Body.Close()
+
+			// The access log entry is the last one since it is written once the
+			// request is handled.
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+
+			var entry map[string]interface{}
+			if !assert.NoError(t, json.Unmarshal(lines[len(lines)-1], &entry)) {
+				return
+			}
+
+			assert.Equal(t, test.expectedLevel, entry["level"])
+			assert.Equal(t, http.MethodGet, entry["method"])
+			assert.Equal(t, test.path, entry["path"])
+			assert.Equal(t, test.expectedStatus, entry["status"])
+			assert.Equal(t, "127.0.0.1", entry["remote_ip"])
+			assert.Equal(t, "abc-123", entry["request_id"])
+			assert.Contains(t, entry, "duration_ms")
+			assert.Contains(t, entry, "bytes")
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerOrigin                        = "Origin"
+	headerVary                          = "Vary"
+	headerAccessControlRequestMethod    = "Access-Control-Request-Method"
+	headerAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	headerAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAccessControlMaxAge           = "Access-Control-Max-Age"
+
+	originWildcard = "*"
+)
+
+var (
+	defaultCORSAllowedMethods = []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+	defaultCORSAllowedHeaders = []string{
+		"Authorization",
+		"Content-Type",
+		"Idempotency-Key",
+	}
+)
+
+// CORSConfig holds configuration of Cross-Origin Resource Sharing.
+//
+// By default, no origins are allowed. A wildcard origin "*" allows any origin,
+// and must be explicitly listed in AllowedOrigins in order to be used. Default
+// methods and headers are used when AllowedMethods and AllowedHeaders are empty.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func withCORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	var (
+		origins     = make(map[string]struct{}, len(cfg.AllowedOrigins))
+		anyOrigin   bool
+		methods     = cfg.AllowedMethods
+		headers     = cfg.AllowedHeaders
+		credentials = strconv.FormatBool(cfg.AllowCredentials)
+		maxAge      = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	)
+
+	for _, o := range cfg.AllowedOrigins {
+		o = strings.TrimSpace(o)
+		if o == originWildcard {
+			anyOrigin = true
+			continue
+		}
+		origins[strings.ToLower(o)] = struct{}{}
+	}
+
+	if len(methods) == 0 {
+		methods = defaultCORSAllowedMethods
+	}
+	if len(headers) == 0 {
+		headers = defaultCORSAllowedHeaders
+	}
+
+	isAllowed := func(origin string) bool {
+		if anyOrigin {
+			return true
+		}
+		_, ok := origins[strings.ToLower(origin)]
+		return ok
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get(headerOrigin)
+			isPreflight := r.Method == http.MethodOptions && r.Header.Get(headerAccessControlRequestMethod) != ""
+
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add(headerVary, headerOrigin)
+
+			if isAllowed(origin) {
+				// Browsers reject a wildcard origin for requests with credentials,
+				// so the origin has to be echoed back in such a case.
+				if anyOrigin && !cfg.AllowCredentials {
+					w.Header().Set(headerAccessControlAllowOrigin, originWildcard)
+				} else {
+					w.Header().Set(headerAccessControlAllowOrigin, origin)
+				}
+
+				if cfg.AllowCredentials {
+					w.Header().Set(headerAccessControlAllowCredentials, credentials)
+				}
+
+				if isPreflight {
+					w.Header().Set(headerAccessControlAllowMethods, strings.Join(methods, ", "))
+					w.Header().Set(headerAccessControlAllowHeaders, strings.Join(headers, ", "))
+					if cfg.MaxAge > 0 {
+						w.Header().Set(headerAccessControlMaxAge, maxAge)
+					}
+				}
+			}
+
+			if isPreflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCORS(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             CORSConfig
+		requestFn          func(*http.Request)
+		method             string
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:   "respond without cors headers for same-origin request",
+			config: CORSConfig{AllowedOrigins: []string{"https://tolqin.kz"}},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin: "",
+				headerVary:                     "",
+			},
+		},
+		{
+			name:   "respond without cors headers by default",
+			config: CORSConfig{},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://tolqin.kz")
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin: "",
+				headerVary:                     headerOrigin,
+			},
+		},
+		{
+			name:   "respond without cors headers for disallowed origin",
+			config: CORSConfig{AllowedOrigins: []string{"https://tolqin.kz"}},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://example.com")
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin: "",
+				headerVary:                     headerOrigin,
+			},
+		},
+		{
+			name: "respond with cors headers for allowed origin",
+			config: CORSConfig{
+				AllowedOrigins:   []string{"https://tolqin.kz"},
+				AllowCredentials: true,
+			},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://tolqin.kz")
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:      "https://tolqin.kz",
+				headerAccessControlAllowCredentials: "true",
+				headerAccessControlAllowMethods:     "",
+				headerAccessControlAllowHeaders:     "",
+				headerVary:                          headerOrigin,
+			},
+		},
+		{
+			name:   "respond with wildcard cors headers when any origin is allowed",
+			config: CORSConfig{AllowedOrigins: []string{"*"}},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://example.com")
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:      "*",
+				headerAccessControlAllowCredentials: "",
+			},
+		},
+		{
+			name: "respond with echoed origin when any origin is allowed with credentials",
+			config: CORSConfig{
+				AllowedOrigins:   []string{"*"},
+				AllowCredentials: true,
+			},
+			method: http.MethodGet,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://example.com")
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:      "https://example.com",
+				headerAccessControlAllowCredentials: "true",
+			},
+		},
+		{
+			name:   "respond with 204 status code and default cors headers for preflight request",
+			config: CORSConfig{AllowedOrigins: []string{"https://tolqin.kz"}},
+			method: http.MethodOptions,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://tolqin.kz")
+				r.Header.Set(headerAccessControlRequestMethod, http.MethodPatch)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:      "https://tolqin.kz",
+				headerAccessControlAllowMethods:     "GET, POST, PUT, PATCH, DELETE",
+				headerAccessControlAllowHeaders:     "Authorization, Content-Type, Idempotency-Key",
+				headerAccessControlAllowCredentials: "",
+				headerAccessControlMaxAge:           "",
+			},
+		},
+		{
+			name: "respond with 204 status code and configured cors headers for preflight request",
+			config: CORSConfig{
+				AllowedOrigins:   []string{"https://tolqin.kz"},
+				AllowedMethods:   []string{http.MethodGet},
+				AllowedHeaders:   []string{"Authorization"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			},
+			method: http.MethodOptions,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://tolqin.kz")
+				r.Header.Set(headerAccessControlRequestMethod, http.MethodGet)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:      "https://tolqin.kz",
+				headerAccessControlAllowMethods:     "GET",
+				headerAccessControlAllowHeaders:     "Authorization",
+				headerAccessControlAllowCredentials: "true",
+				headerAccessControlMaxAge:           "600",
+			},
+		},
+		{
+			name:   "respond with 204 status code and without cors headers for disallowed preflight request",
+			config: CORSConfig{AllowedOrigins: []string{"https://tolqin.kz"}},
+			method: http.MethodOptions,
+			requestFn: func(r *http.Request) {
+				r.Header.Set(headerOrigin, "https://example.com")
+				r.Header.Set(headerAccessControlRequestMethod, http.MethodGet)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				headerAccessControlAllowOrigin:  "",
+				headerAccessControlAllowMethods: "",
+				headerAccessControlAllowHeaders: "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil, WithCORS(test.config))
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+"/health", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			for key, value := range test.expectedHeaders {
+				assert.Equal(t, value, resp.Header.Get(key), key)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+)
+
+// APIGroup identifies a versioned group of routes served by the router.
+type APIGroup string
+
+const (
+	// APIGroupSurfingV1 is the group of routes under /surfing/v1.
+	APIGroupSurfingV1 APIGroup = "surfing/v1"
+	// APIGroupManagementV1 is the group of routes under /management/v1.
+	APIGroupManagementV1 APIGroup = "management/v1"
+)
+
+// WithDataEnvelope controls whether successful responses of the given API group
+// are wrapped in the top-level {"data": ...} object. By default, every group uses
+// the envelope. Error responses are not affected.
+func WithDataEnvelope(g APIGroup, enabled bool) Option {
+	return func(o *options) {
+		if o.dataEnvelopes == nil {
+			o.dataEnvelopes = make(map[APIGroup]bool)
+		}
+		o.dataEnvelopes[g] = enabled
+	}
+}
+
+func (o options) dataEnvelope(g APIGroup) bool {
+	enabled, ok := o.dataEnvelopes[g]
+	if !ok {
+		return true
+	}
+	return enabled
+}
+
+func withDataEnvelope(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(httputil.ContextWithDataEnvelope(r.Context(), enabled))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package router
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestDataEnvelope(t *testing.T) {
+	spot := surf.Spot{
+		ID:   "1",
+		Name: "Spot 1",
+	}
+
+	newSurfingService := func() surfingService {
+		m := newMockSurfingService()
+		m.On("Spot", mock.Anything, "1").Return(spot, nil)
+		m.On("Spot", mock.Anything, "2").Return(surf.Spot{}, surf.ErrSpotNotFound)
+		return m
+	}
+
+	newManagementService := func() managementService {
+		m := newMockManagementService()
+		m.On("Spot", mock.Anything, "1").Return(spot, nil)
+		return m
+	}
+
+	tests := []struct {
+		name             string
+		opts             []Option
+		path             string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "wrap surfing response by default",
+			path:             "/surfing/v1/spots/1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"data":{"id":"1","name":"Spot 1","latitude":0,"longitude":0,"locality":"","country_code":"","country_name":""}}`,
+		},
+		{
+			name:             "wrap management response by default",
+			path:             "/management/v1/spots/1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"data":{"id":"1","name":"Spot 1","latitude":0,"longitude":0,"locality":"","country_code":"","country_name":""}}`,
+		},
+		{
+			name: "respond with bare surfing response when disabled for surfing api",
+			opts: []Option{
+				WithDataEnvelope(APIGroupSurfingV1, false),
+			},
+			path:             "/surfing/v1/spots/1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"id":"1","name":"Spot 1","latitude":0,"longitude":0,"locality":"","country_code":"","country_name":""}`,
+		},
+		{
+			name: "keep management envelope when disabled only for surfing api",
+			opts: []Option{
+				WithDataEnvelope(APIGroupSurfingV1, false),
+			},
+			path:             "/management/v1/spots/1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"data":{"id":"1","name":"Spot 1","latitude":0,"longitude":0,"locality":"","country_code":"","country_name":""}}`,
+		},
+		{
+			name: "respond with bare management response when disabled for management api",
+			opts: []Option{
+				WithDataEnvelope(APIGroupManagementV1, false),
+			},
+			path:             "/management/v1/spots/1",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"id":"1","name":"Spot 1","latitude":0,"longitude":0,"locality":"","country_code":"","country_name":""}`,
+		},
+		{
+			name: "keep error envelope when disabled",
+			opts: []Option{
+				WithDataEnvelope(APIGroupSurfingV1, false),
+			},
+			path:             "/surfing/v1/spots/2",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Such spot doesn't exist."}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(newRouter(nil, newSurfingService(), newManagementService(), nil, nil, test.opts...))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + test.path)
+			assert.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.JSONEq(t, test.expectedResponse, string(body))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// spotETag returns a strong entity tag of the given spot which changes every
+// time the spot gets updated or rated. Rating a spot does not bump its updated_at,
+// so the ratings are hashed separately.
+func spotETag(s surf.Spot) string {
+	h := sha256.New()
+	h.Write([]byte(s.ID))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(s.UpdatedAt.UnixNano(), 10)))
+	if s.DeletedAt != nil {
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.FormatInt(s.DeletedAt.UnixNano(), 10)))
+	}
+	if s.Ratings.Count > 0 {
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.Itoa(s.Ratings.Count)))
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.FormatFloat(s.Ratings.Average, 'g', -1, 64)))
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// payloadETag returns a strong entity tag of the given serialized payload.
+func payloadETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// writeOKWithETag writes a 200 OK HTTP status code and the given data to the
+// response, tagging it with an entity tag of the serialized data. A 304 Not Modified
+// HTTP status code with an empty body is written instead when the request's
+// If-None-Match header matches the tag. It suits responses, such as lists, that
+// have no cheaper source of a version.
+func writeOKWithETag(w http.ResponseWriter, r *http.Request, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	if writeNotModified(w, r, payloadETag(body)) {
+		return
+	}
+
+	httputil.WriteOK(w, r, json.RawMessage(body))
+}
+
+// writeNotModified sets the given entity tag on the response and, when the
+// request's If-None-Match header matches it, responds with 304 Not Modified.
+// It reports whether the response has been written.
+func writeNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set(headerETag, etag)
+
+	if !etagMatches(r.Header.Get(headerIfNoneMatch), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether the given If-None-Match header value matches the
+// given entity tag using the weak comparison required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
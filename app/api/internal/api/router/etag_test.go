@@ -0,0 +1,234 @@
+package router
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/surfing"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestSpotETag(t *testing.T) {
+	s := surf.Spot{
+		ID:        "1",
+		UpdatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+	}
+
+	etag := spotETag(s)
+	assert.Equal(t, etag, spotETag(s))
+
+	updated := s
+	updated.UpdatedAt = updated.UpdatedAt.Add(time.Second)
+	assert.NotEqual(t, etag, spotETag(updated))
+
+	other := s
+	other.ID = "2"
+	assert.NotEqual(t, etag, spotETag(other))
+
+	rated := s
+	rated.Ratings = surf.SpotRatings{Average: 5, Count: 1}
+	assert.NotEqual(t, etag, spotETag(rated))
+
+	rerated := rated
+	rerated.Ratings = surf.SpotRatings{Average: 4, Count: 1}
+	assert.NotEqual(t, spotETag(rated), spotETag(rerated))
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name          string
+		ifNoneMatch   string
+		etag          string
+		expectedMatch bool
+	}{
+		{
+			name:          "empty header",
+			ifNoneMatch:   "",
+			etag:          `"abc"`,
+			expectedMatch: false,
+		},
+		{
+			name:          "single matching tag",
+			ifNoneMatch:   `"abc"`,
+			etag:          `"abc"`,
+			expectedMatch: true,
+		},
+		{
+			name:          "single non-matching tag",
+			ifNoneMatch:   `"xyz"`,
+			etag:          `"abc"`,
+			expectedMatch: false,
+		},
+		{
+			name:          "list with matching tag",
+			ifNoneMatch:   `"xyz", "abc"`,
+			etag:          `"abc"`,
+			expectedMatch: true,
+		},
+		{
+			name:          "weak matching tag",
+			ifNoneMatch:   `W/"abc"`,
+			etag:          `"abc"`,
+			expectedMatch: true,
+		},
+		{
+			name:          "wildcard",
+			ifNoneMatch:   "*",
+			etag:          `"abc"`,
+			expectedMatch: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedMatch, etagMatches(test.ifNoneMatch, test.etag))
+		})
+	}
+}
+
+func TestConditionalGET(t *testing.T) {
+	spots := []surf.Spot{
+		{
+			ID:        "1",
+			Name:      "Spot 1",
+			CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+			UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		method    string
+		newRouter func(ok bool) (http.Handler, *mock.Mock)
+	}{
+		{
+			name:   "surfing spot",
+			path:   "/surfing/v1/spots/1",
+			method: "Spot",
+			newRouter: func(ok bool) (http.Handler, *mock.Mock) {
+				m := newMockSurfingService()
+				if ok {
+					m.On("Spot", mock.Anything, "1").Return(spots[0], nil)
+				} else {
+					m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, errors.New("something went wrong"))
+				}
+				return newRouter(nil, m, nil, nil, nil), &m.Mock
+			},
+		},
+		{
+			name:   "surfing spots",
+			path:   "/surfing/v1/spots",
+			method: "Spots",
+			newRouter: func(ok bool) (http.Handler, *mock.Mock) {
+				m := newMockSurfingService()
+				if ok {
+					m.On("Spots", mock.Anything, surfing.SpotsParams{}).Return(spots, nil)
+				} else {
+					m.On("Spots", mock.Anything, surfing.SpotsParams{}).Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				}
+				return newRouter(nil, m, nil, nil, nil), &m.Mock
+			},
+		},
+		{
+			name:   "management spot",
+			path:   "/management/v1/spots/1",
+			method: "Spot",
+			newRouter: func(ok bool) (http.Handler, *mock.Mock) {
+				m := newMockManagementService()
+				if ok {
+					m.On("Spot", mock.Anything, "1").Return(spots[0], nil)
+				} else {
+					m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, errors.New("something went wrong"))
+				}
+				return newRouter(nil, newMockSurfingService(), m, nil, nil), &m.Mock
+			},
+		},
+		{
+			name:   "management spots",
+			path:   "/management/v1/spots",
+			method: "Spots",
+			newRouter: func(ok bool) (http.Handler, *mock.Mock) {
+				m := newMockManagementService()
+				if ok {
+					m.On("Spots", mock.Anything, management.SpotsParams{}).Return(spots, nil)
+				} else {
+					m.On("Spots", mock.Anything, management.SpotsParams{}).Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				}
+				return newRouter(nil, newMockSurfingService(), m, nil, nil), &m.Mock
+			},
+		},
+	}
+
+	do := func(t *testing.T, url, ifNoneMatch string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.NoError(t, err)
+		if ifNoneMatch != "" {
+			req.Header.Set(headerIfNoneMatch, ifNoneMatch)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+": respond with 304 status code for matching etag", func(t *testing.T) {
+			h, m := test.newRouter(true)
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			first := do(t, server.URL+test.path, "")
+			first.Body.Close()
+			assert.Equal(t, http.StatusOK, first.StatusCode)
+			etag := first.Header.Get(headerETag)
+			assert.NotEmpty(t, etag)
+
+			resp := do(t, server.URL+test.path, etag)
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get(headerETag))
+			assert.Empty(t, body)
+			m.AssertNumberOfCalls(t, test.method, 2)
+		})
+
+		t.Run(test.name+": respond with 200 status code for non-matching etag", func(t *testing.T) {
+			h, m := test.newRouter(true)
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			resp := do(t, server.URL+test.path, `"stale"`)
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.NotEmpty(t, resp.Header.Get(headerETag))
+			assert.NotEqual(t, `"stale"`, resp.Header.Get(headerETag))
+			assert.NotEmpty(t, body)
+			m.AssertNumberOfCalls(t, test.method, 1)
+		})
+
+		t.Run(test.name+": respond without etag for error", func(t *testing.T) {
+			h, _ := test.newRouter(false)
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			resp := do(t, server.URL+test.path, "*")
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get(headerETag))
+		})
+	}
+}
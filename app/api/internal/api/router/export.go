@@ -0,0 +1,195 @@
+package router
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+const (
+	exportFormatCSV     = "csv"
+	exportFormatGeoJSON = "geojson"
+
+	auditFormatJSON = "json"
+	auditFormatCSV  = "csv"
+)
+
+// spotEncoder writes spots to an output in a certain format one by one.
+type spotEncoder interface {
+	contentType() string
+	fileExtension() string
+	begin() error
+	encode(surf.Spot) error
+	end() error
+}
+
+func newSpotEncoder(format string, w io.Writer) (spotEncoder, bool) {
+	switch format {
+	case exportFormatCSV:
+		return &csvSpotEncoder{writer: csv.NewWriter(w)}, true
+	case exportFormatGeoJSON:
+		return &geoJSONSpotEncoder{writer: w}, true
+	default:
+		return nil, false
+	}
+}
+
+// csvSpotEncoder writes spots using the same column layout that the importer
+// expects, so that exported files can be imported back. Spots are exported with
+// their external references, or with their IDs if they have none, so that the
+// exported spots get updated rather than duplicated when imported in upsert mode.
+type csvSpotEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvSpotEncoder) contentType() string   { return "text/csv; charset=utf-8" }
+func (e *csvSpotEncoder) fileExtension() string { return "csv" }
+
+func (e *csvSpotEncoder) begin() error {
+	return e.writer.Write([]string{"name", "latitude", "longitude", "locality", "country_code", "external_ref"})
+}
+
+func (e *csvSpotEncoder) encode(s surf.Spot) error {
+	externalRef := s.ExternalRef
+	if externalRef == "" {
+		externalRef = s.ID
+	}
+
+	return e.writer.Write([]string{
+		s.Name,
+		formatCoordinate(s.Location.Coordinates.Latitude),
+		formatCoordinate(s.Location.Coordinates.Longitude),
+		s.Location.Locality,
+		s.Location.CountryCode,
+		externalRef,
+	})
+}
+
+func (e *csvSpotEncoder) end() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// geoJSONSpotEncoder writes spots as Point features of a GeoJSON FeatureCollection.
+type geoJSONSpotEncoder struct {
+	writer  io.Writer
+	written bool
+}
+
+func (e *geoJSONSpotEncoder) contentType() string   { return "application/geo+json" }
+func (e *geoJSONSpotEncoder) fileExtension() string { return "geojson" }
+
+func (e *geoJSONSpotEncoder) begin() error {
+	_, err := io.WriteString(e.writer, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (e *geoJSONSpotEncoder) encode(s surf.Spot) error {
+	b, err := json.Marshal(geoJSONFeature{
+		Type: "Feature",
+		ID:   s.ID,
+		Geometry: geoJSONGeometry{
+			Type: "Point",
+			// GeoJSON positions are ordered as longitude followed by latitude.
+			Coordinates: [2]float64{
+				s.Location.Coordinates.Longitude,
+				s.Location.Coordinates.Latitude,
+			},
+		},
+		Properties: geoJSONProperties{
+			Name:        s.Name,
+			Locality:    s.Location.Locality,
+			CountryCode: s.Location.CountryCode,
+			CountryName: countryName(s.Location.CountryCode),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.written {
+		if _, err := io.WriteString(e.writer, ","); err != nil {
+			return err
+		}
+	}
+	e.written = true
+
+	_, err = e.writer.Write(b)
+	return err
+}
+
+func (e *geoJSONSpotEncoder) end() error {
+	_, err := io.WriteString(e.writer, "]}")
+	return err
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Geometry   geoJSONGeometry   `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Name        string `json:"name"`
+	Locality    string `json:"locality"`
+	CountryCode string `json:"country_code"`
+	CountryName string `json:"country_name"`
+}
+
+func formatCoordinate(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func writeExportHeaders(w http.ResponseWriter, enc spotEncoder) {
+	w.Header().Set("Content-Type", enc.contentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="spots.%s"`, enc.fileExtension()))
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeAuditEntriesCSV writes the given audit entries as a CSV attachment, one row
+// per entry. Changes of an entry are held in a single column as a JSON array shaped
+// like in the JSON representation, since their fields vary between entries.
+func writeAuditEntriesCSV(w http.ResponseWriter, entries []surf.AuditEntry) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "actor_id", "action", "spot_id", "changes", "created_at"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		resp := toAuditEntryResponse(e)
+
+		changes, err := json.Marshal(resp.Changes)
+		if err != nil {
+			return err
+		}
+
+		if err := writer.Write([]string{
+			resp.ID,
+			resp.ActorID,
+			resp.Action,
+			resp.SpotID,
+			string(changes),
+			resp.CreatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
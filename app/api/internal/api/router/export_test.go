@@ -0,0 +1,66 @@
+package router
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	surfcsv "github.com/ztimes2/tolqin/app/api/internal/pkg/surf/csv"
+)
+
+func TestCSVSpotEncoder_RoundTrip(t *testing.T) {
+	spots := []surf.Spot{
+		{
+			ID:          "1",
+			Name:        "Spot 1",
+			ExternalRef: "ref-1",
+			Location: geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+		},
+		{
+			ID:   "2",
+			Name: "Spot 2",
+			Location: geo.Location{
+				Locality:    "Locality, 2",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  -4.56,
+					Longitude: 6.54,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	enc, ok := newSpotEncoder(exportFormatCSV, &buf)
+	assert.True(t, ok)
+
+	assert.NoError(t, enc.begin())
+	for _, s := range spots {
+		assert.NoError(t, enc.encode(s))
+	}
+	assert.NoError(t, enc.end())
+
+	entries, err := surf.ReadAllSpotCreationEntries(surfcsv.NewSpotCreationEntrySource(&buf))
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.SpotCreationEntry{
+		{
+			Name:        "Spot 1",
+			ExternalRef: "ref-1",
+			Location:    spots[0].Location,
+		},
+		{
+			Name:        "Spot 2",
+			ExternalRef: "2",
+			Location:    spots[1].Location,
+		},
+	}, entries)
+}
@@ -24,11 +24,13 @@ func newAuthHandler(s authService) *authHandler {
 	}
 }
 
+type tokenPayload struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 func (h *authHandler) token(w http.ResponseWriter, r *http.Request) {
-	var payload struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
+	var payload tokenPayload
 
 	defer r.Body.Close()
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
@@ -1,6 +1,23 @@
 package router
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
+)
+
+const (
+	// defaultReadinessTimeout limits the duration of readiness checks so that a
+	// hung dependency does not stall a readiness probe.
+	defaultReadinessTimeout = 2 * time.Second
+
+	dependencyStatusUp   = "up"
+	dependencyStatusDown = "down"
+)
 
 func handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	// TODO respond with a dedicated response body containing information about
@@ -9,3 +26,69 @@ func handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	// Simply indicates if the server is up and running.
 	w.WriteHeader(http.StatusOK)
 }
+
+// ReadinessCheck checks whether a dependency of the application is available,
+// and returns an error if it is not.
+type ReadinessCheck func(ctx context.Context) error
+
+type readinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+type readinessHandler struct {
+	checks  []readinessCheck
+	timeout time.Duration
+}
+
+func newReadinessHandler(checks []readinessCheck, timeout time.Duration) *readinessHandler {
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	return &readinessHandler{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+func (h *readinessHandler) ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	errs := make([]error, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func(i int, c readinessCheck) {
+			defer wg.Done()
+			errs[i] = c.check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	var (
+		resp = readinessResponse{
+			Dependencies: make(map[string]string, len(h.checks)),
+		}
+		unavailable []string
+	)
+	for i, c := range h.checks {
+		if errs[i] != nil {
+			if logger := log.FromContext(r.Context()); logger != nil {
+				logger.WithError(errs[i]).Warnf("dependency %q is unavailable: %s", c.name, errs[i])
+			}
+			unavailable = append(unavailable, c.name)
+			resp.Dependencies[c.name] = dependencyStatusDown
+			continue
+		}
+		resp.Dependencies[c.name] = dependencyStatusUp
+	}
+
+	if len(unavailable) > 0 {
+		httputil.WriteUnavailableError(w, r, "Some of the dependencies are unavailable.", unavailable)
+		return
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
@@ -1,10 +1,13 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,3 +29,92 @@ func TestHandleHealthCheck(t *testing.T) {
 
 	assert.Equal(t, "", string(body))
 }
+
+func TestHandleHealthz(t *testing.T) {
+	r := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestReadinessHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name               string
+		opts               []Option
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "respond with 200 without checks",
+			opts:               nil,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"dependencies":{}}}`,
+		},
+		{
+			name: "respond with 200 when all dependencies are available",
+			opts: []Option{
+				WithReadinessCheck("database", func(ctx context.Context) error {
+					return nil
+				}),
+				WithReadinessCheck("nominatim", func(ctx context.Context) error {
+					return nil
+				}),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"dependencies":{"database":"up","nominatim":"up"}}}`,
+		},
+		{
+			name: "respond with 503 when some of dependencies are unavailable",
+			opts: []Option{
+				WithReadinessCheck("database", func(ctx context.Context) error {
+					return errors.New("something went wrong")
+				}),
+				WithReadinessCheck("nominatim", func(ctx context.Context) error {
+					return nil
+				}),
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody: `{"error":{"code":"unavailable","description":"Some of the dependencies ` +
+				`are unavailable.","dependencies":["database"]}}`,
+		},
+		{
+			name: "respond with 503 when dependency check times out",
+			opts: []Option{
+				WithReadinessTimeout(10 * time.Millisecond),
+				WithReadinessCheck("nominatim", func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody: `{"error":{"code":"unavailable","description":"Some of the dependencies ` +
+				`are unavailable.","dependencies":["nominatim"]}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil, test.opts...)
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/readyz")
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.JSONEq(t, test.expectedBody, string(body))
+		})
+	}
+}
@@ -11,31 +11,83 @@ import (
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
+const (
+	// debugModeExplain is used for capturing a query plan alongside results.
+	debugModeExplain = "explain"
+
+	// headerIdempotencyKey lets clients retry creating a spot without creating
+	// duplicates. Updates and deletions of a spot do not accept it: updates set
+	// absolute values and deletions of a deleted spot respond with 404, so their
+	// retries are already safe, while replaying their responses would require
+	// storing them along with the keys.
+	headerIdempotencyKey = "Idempotency-Key"
+)
+
 type managementService interface {
 	Spot(ctx context.Context, id string) (surf.Spot, error)
+	SpotBySlug(ctx context.Context, slug string) (surf.Spot, error)
+	SpotWithNearby(context.Context, management.SpotWithNearbyParams) (management.SpotWithNearby, error)
 	Spots(context.Context, management.SpotsParams) ([]surf.Spot, error)
+	SpotsQueryPlan(context.Context, management.SpotsParams) ([]byte, error)
+	ExportSpots(context.Context, management.ExportSpotsParams, func(surf.Spot) error) error
 	CreateSpot(context.Context, management.CreateSpotParams) (surf.Spot, error)
+	CreateSpotIdempotently(ctx context.Context, key string, p management.CreateSpotParams) (surf.Spot, error)
+	CreateSpots(context.Context, []management.CreateSpotParams) (management.CreateSpotsResult, error)
 	UpdateSpot(context.Context, management.UpdateSpotParams) (surf.Spot, error)
 	DeleteSpot(ctx context.Context, id string) error
+	DeleteSpots(ctx context.Context, ids []string) (management.DeleteSpotsResult, error)
+	RestoreSpot(ctx context.Context, id string) (surf.Spot, error)
+	SetSpotTags(context.Context, management.SetSpotTagsParams) (surf.Spot, error)
+	UploadSpotPhoto(context.Context, management.UploadSpotPhotoParams) (management.SpotPhoto, error)
+	DeleteSpotPhoto(ctx context.Context, spotID, photoID string) error
+	Countries(context.Context, management.CountriesParams) ([]surf.CountrySpotCount, error)
+	SpotCountsByCountry(context.Context) ([]surf.CountrySpotCount, error)
+	Localities(context.Context, management.LocalitiesParams) ([]string, error)
+	SpotsOverTime(context.Context, management.SpotsOverTimeParams) ([]surf.SpotCreationCount, error)
 	Location(context.Context, geo.Coordinates) (geo.Location, error)
+	Locations(context.Context, []geo.Coordinates) ([]management.LocationsEntryResult, error)
+	SuggestSpotLocation(ctx context.Context, id string) (management.SpotLocationSuggestion, error)
+	ApplySpotLocationSuggestion(context.Context, management.ApplySpotLocationSuggestionParams) (surf.Spot, error)
+	ReplaceReferenceSet(context.Context, management.ReplaceReferenceSetParams) (int, error)
+	BackfillReferenceDistances(ctx context.Context, set string) (int, error)
+	SpotSuggestions(context.Context, management.SpotSuggestionsParams) ([]surf.SpotSuggestion, error)
+	ApproveSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error)
+	RejectSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error)
+	AuditEntries(context.Context, management.AuditEntriesParams) ([]surf.AuditEntry, error)
 }
 
 type managementHandler struct {
-	service managementService
+	service       managementService
+	maxUploadSize int64
 }
 
-func newManagementHandler(s managementService) *managementHandler {
+func newManagementHandler(s managementService, maxUploadSize int64) *managementHandler {
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
 	return &managementHandler{
-		service: s,
+		service:       s,
+		maxUploadSize: maxUploadSize,
 	}
 }
 
 func (h *managementHandler) spot(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, paramKeySpotID)
 
+	nearby, err := includesNearby(r)
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField(paramKeyInclude, msgInvalidInclude))
+		return
+	}
+	if nearby {
+		h.spotWithNearby(w, r, id)
+		return
+	}
+
 	spot, err := h.service.Spot(r.Context(), id)
 	if err != nil {
 		var vErr *valerra.Errors
@@ -57,9 +109,79 @@ func (h *managementHandler) spot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if writeNotModified(w, r, spotETag(spot)) {
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotResponse(spot))
+}
+
+func (h *managementHandler) spotBySlug(w http.ResponseWriter, r *http.Request) {
+	spot, err := h.service.SpotBySlug(r.Context(), chi.URLParam(r, paramKeySlug))
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSlug, httputil.NewInvalidField(paramKeySlug, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	if writeNotModified(w, r, spotETag(spot)) {
+		return
+	}
+
 	httputil.WriteOK(w, r, toSpotResponse(spot))
 }
 
+// spotWithNearby responds with a spot along with the spots nearest to it. No ETag
+// is set since nearby spots can change without the spot itself being updated.
+func (h *managementHandler) spotWithNearby(w http.ResponseWriter, r *http.Request, id string) {
+	radius, err := httputil.QueryParamFloat(r, "radius_km")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("radius_km", "Must be a valid number."))
+		return
+	}
+
+	swn, err := h.service.SpotWithNearby(r.Context(), management.SpotWithNearbyParams{
+		SpotID:   id,
+		RadiusKm: radius,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidRadius, httputil.NewInvalidField("radius_km", "Must be a positive number."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotWithNearbyResponse(swn.Spot, swn.Nearby))
+}
+
 func (h *managementHandler) spots(w http.ResponseWriter, r *http.Request) {
 	limit, err := httputil.QueryParamInt(r, "limit")
 	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
@@ -95,24 +217,54 @@ func (h *managementHandler) spots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	spots, err := h.service.Spots(r.Context(), management.SpotsParams{
-		Limit:       limit,
-		Offset:      offset,
-		CountryCode: countryCode,
-		SearchQuery: query,
-		Bounds:      bounds,
-	})
+	includeDeleted, err := httputil.QueryParamBool(r, "include_deleted")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("include_deleted", "Must be a valid boolean."))
+		return
+	}
+
+	var explain bool
+	if debug := httputil.QueryParam(r, "debug"); debug != "" {
+		if debug != debugModeExplain {
+			httputil.WriteFieldError(w, r, httputil.NewInvalidField("debug", "Must be one of: explain."))
+			return
+		}
+		explain = true
+	}
+
+	params := management.SpotsParams{
+		Limit:          limit,
+		Offset:         offset,
+		CountryCode:    countryCode,
+		SearchQuery:    query,
+		SearchMode:     httputil.QueryParam(r, "search_mode"),
+		Bounds:         bounds,
+		IncludeDeleted: includeDeleted,
+		Sort:           httputil.QueryParam(r, "sort"),
+		Tags:           httputil.QueryParamList(r, "tags"),
+		TagsMatch:      httputil.QueryParam(r, "tags_match"),
+		ReferenceSet:   httputil.QueryParam(r, paramKeyReferenceSet),
+	}
+
+	spots, err := h.service.Spots(r.Context(), params)
 	if err != nil {
 		var vErr *valerra.Errors
 		if errors.As(err, &vErr) {
 			f := httputil.NewInvalidFields()
 			for _, e := range vErr.Errors() {
 				f.Is(e, management.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+				f.Is(e, management.ErrInvalidSearchMode, httputil.NewInvalidField("search_mode", "Must be either substring or fulltext."))
 				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
 				f.Is(e, management.ErrInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
 				f.Is(e, management.ErrInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
 				f.Is(e, management.ErrInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
 				f.Is(e, management.ErrInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+				f.Is(e, management.ErrInvalidBounds, httputil.NewInvalidField("ne_lat", "Must not be less than sw_lat."))
+				f.Is(e, management.ErrInvalidBounds, httputil.NewInvalidField("sw_lat", "Must not be greater than ne_lat."))
+				f.Is(e, management.ErrInvalidSort, httputil.NewInvalidField("sort", "Must be one of: -updated_at, reference_distance."))
+				f.Is(e, management.ErrInvalidTags, httputil.NewInvalidField("tags", "Must contain at most 10 tags of at most 30 characters."))
+				f.Is(e, management.ErrInvalidTagsMatch, httputil.NewInvalidField("tags_match", "Must be either all or any."))
+				f.Is(e, management.ErrInvalidReferenceSet, httputil.NewInvalidField(paramKeyReferenceSet, msgInvalidReferenceSet))
 			}
 			httputil.WriteFieldErrors(w, r, f)
 			return
@@ -130,25 +282,142 @@ func (h *managementHandler) spots(w http.ResponseWriter, r *http.Request) {
 		resp.Items[i] = toSpotResponse(s)
 	}
 
-	httputil.WriteOK(w, r, resp)
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(spots))
+
+	if explain {
+		plan, err := h.service.SpotsQueryPlan(r.Context(), params)
+		if err != nil {
+			if errors.Is(err, management.ErrDiagnosticsDisabled) {
+				httputil.WriteFieldError(w, r, httputil.NewInvalidField("debug", "Diagnostics are disabled."))
+				return
+			}
+
+			httputil.WriteUnexpectedError(w, r, err)
+			return
+		}
+		resp.QueryPlan = plan
+
+		httputil.WriteOK(w, r, resp)
+		return
+	}
+
+	writeOKWithETag(w, r, resp)
 }
 
-func (h *managementHandler) createSpot(w http.ResponseWriter, r *http.Request) {
-	var payload struct {
-		Name        string  `json:"name"`
-		Latitude    float64 `json:"latitude"`
-		Longitude   float64 `json:"longitude"`
-		Locality    string  `json:"locality"`
-		CountryCode string  `json:"country_code"`
+func (h *managementHandler) exportSpots(w http.ResponseWriter, r *http.Request) {
+	format := httputil.QueryParam(r, "format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+
+	enc, ok := newSpotEncoder(format, w)
+	if !ok {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("format", "Must be one of: csv, geojson."))
+		return
+	}
+
+	bounds, vErr := parseBounds(
+		httputil.QueryParam(r, "ne_lat"),
+		httputil.QueryParam(r, "ne_lon"),
+		httputil.QueryParam(r, "sw_lat"),
+		httputil.QueryParam(r, "sw_lon"),
+	)
+	if vErr != nil {
+		f := httputil.NewInvalidFields()
+		for _, e := range vErr.Errors() {
+			f.Is(e, errInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+			f.Is(e, errInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+		}
+		httputil.WriteFieldErrors(w, r, f)
+		return
+	}
+
+	// The response is started lazily once the first spot is received, so that
+	// errors which occur before that can still be responded with properly.
+	var started bool
+	begin := func() error {
+		started = true
+		writeExportHeaders(w, enc)
+		return enc.begin()
+	}
+
+	err := h.service.ExportSpots(r.Context(), management.ExportSpotsParams{
+		CountryCode: httputil.QueryParam(r, "country"),
+		SearchQuery: httputil.QueryParam(r, "query"),
+		Bounds:      bounds,
+	}, func(s surf.Spot) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return enc.encode(s)
+	})
+	if err != nil {
+		if started {
+			// The status code and a part of the body have already been written,
+			// so the error can only be logged.
+			if logger := log.FromContext(r.Context()); logger != nil {
+				logger.WithError(err).Errorf("failed to export spots: %s", err)
+			}
+			return
+		}
+
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+				f.Is(e, management.ErrInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+				f.Is(e, management.ErrInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+				f.Is(e, management.ErrInvalidBounds, httputil.NewInvalidField("ne_lat", "Must not be less than sw_lat."))
+				f.Is(e, management.ErrInvalidBounds, httputil.NewInvalidField("sw_lat", "Must not be greater than ne_lat."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	if !started {
+		if err := begin(); err != nil {
+			return
+		}
 	}
 
+	if err := enc.end(); err != nil {
+		if logger := log.FromContext(r.Context()); logger != nil {
+			logger.WithError(err).Errorf("failed to export spots: %s", err)
+		}
+	}
+}
+
+type createSpotPayload struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Locality    string  `json:"locality"`
+	CountryCode string  `json:"country_code"`
+}
+
+func (h *managementHandler) createSpot(w http.ResponseWriter, r *http.Request) {
+	var payload createSpotPayload
+
 	defer r.Body.Close()
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		httputil.WritePayloadError(w, r)
 		return
 	}
 
-	spot, err := h.service.CreateSpot(r.Context(), management.CreateSpotParams{
+	params := management.CreateSpotParams{
 		Name: payload.Name,
 		Location: geo.Location{
 			Coordinates: geo.Coordinates{
@@ -158,17 +427,87 @@ func (h *managementHandler) createSpot(w http.ResponseWriter, r *http.Request) {
 			Locality:    payload.Locality,
 			CountryCode: payload.CountryCode,
 		},
-	})
+	}
+
+	var (
+		spot surf.Spot
+		err  error
+	)
+	if key := r.Header.Get(headerIdempotencyKey); key != "" {
+		spot, err = h.service.CreateSpotIdempotently(r.Context(), key, params)
+	} else {
+		spot, err = h.service.CreateSpot(r.Context(), params)
+	}
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			httputil.WriteFieldErrors(w, r, createSpotInvalidFields(vErr))
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			// The spot that was created with the idempotency key has been deleted
+			// since then.
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surf.ErrDuplicateSpot) {
+			writeDuplicateSpotError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteCreated(w, r, toSpotResponse(spot))
+}
+
+func createSpotInvalidFields(vErr *valerra.Errors) *httputil.InvalidFields {
+	f := httputil.NewInvalidFields()
+	for _, e := range vErr.Errors() {
+		f.Is(e, management.ErrInvalidSpotName, httputil.NewInvalidField("name", "Must be a non empty string."))
+		f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country_code", "Must be a valid ISO-2 country code."))
+		f.Is(e, management.ErrInvalidLocality, httputil.NewInvalidField("locality", "Must be a non empty string."))
+		f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
+		f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
+		f.Is(e, management.ErrInvalidIdempotencyKey, httputil.NewInvalidField(headerIdempotencyKey, "Must be a non empty string of at most 255 characters."))
+	}
+	return f
+}
+
+func (h *managementHandler) createSpots(w http.ResponseWriter, r *http.Request) {
+	var payload []createSpotPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	params := make([]management.CreateSpotParams, len(payload))
+	for i, p := range payload {
+		params[i] = management.CreateSpotParams{
+			Name: p.Name,
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  p.Latitude,
+					Longitude: p.Longitude,
+				},
+				Locality:    p.Locality,
+				CountryCode: p.CountryCode,
+			},
+		}
+	}
+
+	res, err := h.service.CreateSpots(r.Context(), params)
 	if err != nil {
 		var vErr *valerra.Errors
 		if errors.As(err, &vErr) {
 			f := httputil.NewInvalidFields()
 			for _, e := range vErr.Errors() {
-				f.Is(e, management.ErrInvalidSpotName, httputil.NewInvalidField("name", "Must be a non empty string."))
-				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country_code", "Must be a valid ISO-2 country code."))
-				f.Is(e, management.ErrInvalidLocality, httputil.NewInvalidField("locality", "Must be a non empty string."))
-				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
-				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
+				f.Is(e, management.ErrInvalidSpotEntries, httputil.NewInvalidField("spots", "Must contain from 1 to 100 spots."))
 			}
 			httputil.WriteFieldErrors(w, r, f)
 			return
@@ -178,33 +517,89 @@ func (h *managementHandler) createSpot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteCreated(w, r, toSpotResponse(spot))
+	resp := createSpotsResponse{
+		Created: res.Created,
+		Items:   make([]createSpotsItemResponse, len(res.Entries)),
+	}
+
+	failed := false
+	for i, e := range res.Entries {
+		resp.Items[i] = createSpotsItemResponse{
+			Index:  i,
+			Status: http.StatusCreated,
+		}
+
+		if e.Err == nil {
+			continue
+		}
+
+		failed = true
+		resp.Items[i].Status = http.StatusBadRequest
+
+		var vErr *valerra.Errors
+		if errors.As(e.Err, &vErr) {
+			resp.Items[i].Error = httputil.NewFieldErrorsBody(r, createSpotInvalidFields(vErr))
+		}
+	}
+
+	if failed {
+		httputil.WriteMultiStatus(w, r, resp)
+		return
+	}
+
+	httputil.WriteCreated(w, r, resp)
+}
+
+// updateSpotPayload holds fields of a spot to update. Absent fields are left
+// unchanged. Setting locality, country_code, or region to null clears them, whereas
+// null is treated the same as absence for the rest of the fields, which cannot be
+// cleared.
+type updateSpotPayload struct {
+	Name        *string  `json:"name"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Locality    *string  `json:"locality"`
+	CountryCode *string  `json:"country_code"`
+	Region      *string  `json:"region"`
 }
 
 func (h *managementHandler) updateSpot(w http.ResponseWriter, r *http.Request) {
 	spotID := chi.URLParam(r, paramKeySpotID)
 
-	var payload struct {
-		Name        *string  `json:"name"`
-		Latitude    *float64 `json:"latitude"`
-		Longitude   *float64 `json:"longitude"`
-		Locality    *string  `json:"locality"`
-		CountryCode *string  `json:"country_code"`
-	}
+	var raw json.RawMessage
 
 	defer r.Body.Close()
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	// The payload is decoded once again into raw fields, since pointers alone
+	// cannot tell absent fields apart from the ones explicitly set to null.
+	var (
+		payload updateSpotPayload
+		fields  map[string]json.RawMessage
+	)
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
 		httputil.WritePayloadError(w, r)
 		return
 	}
 
 	spot, err := h.service.UpdateSpot(r.Context(), management.UpdateSpotParams{
-		ID:          spotID,
-		Name:        payload.Name,
-		Latitude:    payload.Latitude,
-		Longitude:   payload.Longitude,
-		Locality:    payload.Locality,
-		CountryCode: payload.CountryCode,
+		ID:               spotID,
+		Name:             payload.Name,
+		Latitude:         payload.Latitude,
+		Longitude:        payload.Longitude,
+		Locality:         payload.Locality,
+		CountryCode:      payload.CountryCode,
+		Region:           payload.Region,
+		ClearLocality:    isJSONNull(fields["locality"]),
+		ClearCountryCode: isJSONNull(fields["country_code"]),
+		ClearRegion:      isJSONNull(fields["region"]),
 	})
 	if err != nil {
 		var vErr *valerra.Errors
@@ -214,6 +609,7 @@ func (h *managementHandler) updateSpot(w http.ResponseWriter, r *http.Request) {
 				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
 				f.Is(e, management.ErrInvalidSpotName, httputil.NewInvalidField("name", "Must be a non empty string."))
 				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country_code", "Must be a valid ISO-2 country code."))
+				f.Is(e, management.ErrLocationMismatch, httputil.NewInvalidField("country_code", "Must match the country at the spot's coordinates."))
 				f.Is(e, management.ErrInvalidLocality, httputil.NewInvalidField("locality", "Must be a non empty string."))
 				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
 				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
@@ -232,6 +628,16 @@ func (h *managementHandler) updateSpot(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		if errors.Is(err, surf.ErrDuplicateSpot) {
+			writeDuplicateSpotError(w, r)
+			return
+		}
+
 		httputil.WriteUnexpectedError(w, r, err)
 		return
 	}
@@ -239,6 +645,10 @@ func (h *managementHandler) updateSpot(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteOK(w, r, toSpotResponse(spot))
 }
 
+func isJSONNull(raw json.RawMessage) bool {
+	return string(raw) == "null"
+}
+
 func (h *managementHandler) deleteSpot(w http.ResponseWriter, r *http.Request) {
 	spotID := chi.URLParam(r, paramKeySpotID)
 
@@ -265,50 +675,856 @@ func (h *managementHandler) deleteSpot(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteNoContent(w, r)
 }
 
-func (h *managementHandler) location(w http.ResponseWriter, r *http.Request) {
-	latitude, err := httputil.QueryParamFloat(r, "lat")
-	if err != nil {
-		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lat", "Must be a valid latitude."))
-		return
-	}
+func (h *managementHandler) deleteSpots(w http.ResponseWriter, r *http.Request) {
+	var ids []string
 
-	longitude, err := httputil.QueryParamFloat(r, "lon")
-	if err != nil {
-		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+		httputil.WritePayloadError(w, r)
 		return
 	}
 
-	l, err := h.service.Location(r.Context(), geo.Coordinates{
-		Latitude:  latitude,
-		Longitude: longitude,
-	})
+	res, err := h.service.DeleteSpots(r.Context(), ids)
 	if err != nil {
 		var vErr *valerra.Errors
 		if errors.As(err, &vErr) {
 			f := httputil.NewInvalidFields()
 			for _, e := range vErr.Errors() {
-				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("lat", "Must be a valid latitude."))
-				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+				f.Is(e, management.ErrInvalidSpotIDs, httputil.NewInvalidField("ids", "Must contain from 1 to 100 spot ids."))
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField("ids", "Must contain only non empty strings."))
 			}
 			httputil.WriteFieldErrors(w, r, f)
 			return
 		}
 
-		if errors.Is(err, geo.ErrLocationNotFound) {
-			httputil.WriteNotFoundError(w, r, "Location was not found.")
-			return
-		}
-
 		httputil.WriteUnexpectedError(w, r, err)
 		return
 	}
 
-	resp := locationResponse{
-		Latitude:    l.Coordinates.Latitude,
-		Longitude:   l.Coordinates.Longitude,
-		Locality:    l.Locality,
-		CountryCode: l.CountryCode,
+	httputil.WriteOK(w, r, deleteSpotsResponse{
+		DeletedCount: res.DeletedCount,
+		NotFoundIDs:  res.NotFoundIDs,
+	})
+}
+
+func (h *managementHandler) restoreSpot(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	spot, err := h.service.RestoreSpot(r.Context(), spotID)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such deleted spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotResponse(spot))
+}
+
+func (h *managementHandler) uploadSpotPhoto(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	content, err := readPhoto(r, h.maxUploadSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, errPayloadTooLarge):
+			httputil.WritePayloadTooLargeError(w, r)
+		case errors.Is(err, errMissingPhoto):
+			httputil.WriteFieldError(w, r, httputil.NewInvalidField(formKeyPhoto, "Must be a file."))
+		default:
+			httputil.WritePayloadError(w, r)
+		}
+		return
+	}
+
+	photo, err := h.service.UploadSpotPhoto(r.Context(), management.UploadSpotPhotoParams{
+		SpotID:  spotID,
+		Content: content,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidPhoto, httputil.NewInvalidField(formKeyPhoto, "Must be a non empty file."))
+				f.Is(e, management.ErrUnsupportedPhotoType, httputil.NewInvalidField(formKeyPhoto, "Must be a JPEG, PNG, or WebP image."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, management.ErrPhotosUnavailable) {
+			writePhotosUnavailableError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteCreated(w, r, toSpotPhotoResponse(photo.SpotPhoto, photo.URL))
+}
+
+func (h *managementHandler) deleteSpotPhoto(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+	photoID := chi.URLParam(r, paramKeyPhotoID)
+
+	if err := h.service.DeleteSpotPhoto(r.Context(), spotID, photoID); err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidPhotoID, httputil.NewInvalidField(paramKeyPhotoID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotPhotoNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such photo doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, management.ErrPhotosUnavailable) {
+			writePhotosUnavailableError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteNoContent(w, r)
+}
+
+type setSpotTagsPayload struct {
+	Tags []string `json:"tags"`
+}
+
+func (h *managementHandler) setSpotTags(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	var payload setSpotTagsPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	spot, err := h.service.SetSpotTags(r.Context(), management.SetSpotTagsParams{
+		SpotID: spotID,
+		Tags:   payload.Tags,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidTags, httputil.NewInvalidField("tags", "Must contain at most 10 tags of at most 30 characters."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotResponse(spot))
+}
+
+func (h *managementHandler) countries(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.service.Countries(r.Context(), management.CountriesParams{
+		SearchQuery: httputil.QueryParam(r, "query"),
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toCountriesResponse(counts))
+}
+
+func (h *managementHandler) spotCountsByCountry(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.service.SpotCountsByCountry(r.Context())
+	if err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toCountriesResponse(counts))
+}
+
+func (h *managementHandler) localities(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	localities, err := h.service.Localities(r.Context(), management.LocalitiesParams{
+		Prefix:      httputil.QueryParam(r, "prefix"),
+		CountryCode: httputil.QueryParam(r, "country"),
+		Limit:       limit,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidLocalityPrefix, httputil.NewInvalidField("prefix", "Must contain at least 2 characters."))
+				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toLocalitiesResponse(localities))
+}
+
+func (h *managementHandler) spotsOverTime(w http.ResponseWriter, r *http.Request) {
+	from, err := httputil.QueryParamTime(r, "from")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("from", "Must be a valid RFC3339 time."))
+		return
+	}
+
+	to, err := httputil.QueryParamTime(r, "to")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("to", "Must be a valid RFC3339 time."))
+		return
+	}
+
+	counts, err := h.service.SpotsOverTime(r.Context(), management.SpotsOverTimeParams{
+		From:     from,
+		To:       to,
+		Interval: httputil.QueryParam(r, "interval"),
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidFrom, httputil.NewInvalidField("from", "Must be a valid RFC3339 time."))
+				f.Is(e, management.ErrInvalidTo, httputil.NewInvalidField("to", "Must be a valid RFC3339 time after from."))
+				f.Is(e, management.ErrInvalidInterval, httputil.NewInvalidField("interval", "Must be one of: day, week, month."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotsOverTimeResponse(counts))
+}
+
+func (h *managementHandler) location(w http.ResponseWriter, r *http.Request) {
+	latitude, err := httputil.QueryParamFloat(r, "lat")
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+		return
+	}
+
+	longitude, err := httputil.QueryParamFloat(r, "lon")
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+		return
+	}
+
+	l, err := h.service.Location(r.Context(), geo.Coordinates{
+		Latitude:  latitude,
+		Longitude: longitude,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, geo.ErrLocationNotFound) {
+			httputil.WriteNotFoundError(w, r, "Location was not found.")
+			return
+		}
+
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		var uErr *geo.UnavailableError
+		if errors.As(err, &uErr) {
+			writeGeocoderUnavailableError(w, r, uErr.RetryAfter)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toLocationResponse(l))
+}
+
+type coordinatesPayload struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// locations responds with a location for each of the coordinates of the payload.
+// Coordinates that are invalid or have no location get their errors embedded into
+// the items, in which case 207 Multi-Status is written.
+func (h *managementHandler) locations(w http.ResponseWriter, r *http.Request) {
+	var payload []coordinatesPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	coords := make([]geo.Coordinates, len(payload))
+	for i, p := range payload {
+		coords[i] = geo.Coordinates{
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		}
+	}
+
+	results, err := h.service.Locations(r.Context(), coords)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidCoordinates, httputil.NewInvalidField("coordinates", "Must contain from 1 to 50 coordinates."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		var uErr *geo.UnavailableError
+		if errors.As(err, &uErr) {
+			writeGeocoderUnavailableError(w, r, uErr.RetryAfter)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := locationsResponse{
+		Items: make([]locationsItemResponse, len(results)),
+	}
+
+	failed := false
+	for i, res := range results {
+		resp.Items[i] = locationsItemResponse{
+			Index:  i,
+			Status: http.StatusOK,
+		}
+
+		if res.Err == nil {
+			l := toLocationResponse(res.Location)
+			resp.Items[i].Location = &l
+			continue
+		}
+
+		failed = true
+
+		var vErr *valerra.Errors
+		if errors.As(res.Err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
+			}
+			resp.Items[i].Status = http.StatusBadRequest
+			resp.Items[i].Error = httputil.NewFieldErrorsBody(r, f)
+			continue
+		}
+
+		resp.Items[i].Status = http.StatusNotFound
+		resp.Items[i].Error = httputil.NewErrorBody(r, "not_found", "Location was not found.")
+	}
+
+	if failed {
+		httputil.WriteMultiStatus(w, r, resp)
+		return
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *managementHandler) suggestSpotLocation(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	suggestion, err := h.service.SuggestSpotLocation(r.Context(), spotID)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, geo.ErrLocationNotFound) {
+			httputil.WriteNotFoundError(w, r, "Location was not found.")
+			return
+		}
+
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, spotLocationSuggestionResponse{
+		SpotID: suggestion.Spot.ID,
+		Current: locationSuggestionResponse{
+			Locality:    suggestion.Spot.Location.Locality,
+			CountryCode: suggestion.Spot.Location.CountryCode,
+			CountryName: countryName(suggestion.Spot.Location.CountryCode),
+			Region:      suggestion.Spot.Location.Region,
+		},
+		Suggested: locationSuggestionResponse{
+			Locality:    suggestion.Locality,
+			CountryCode: suggestion.CountryCode,
+			CountryName: countryName(suggestion.CountryCode),
+			Region:      suggestion.Region,
+		},
+	})
+}
+
+type applySpotLocationSuggestionPayload struct {
+	Locality    bool `json:"locality"`
+	CountryCode bool `json:"country_code"`
+	Region      bool `json:"region"`
+}
+
+func (h *managementHandler) applySpotLocationSuggestion(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	var payload applySpotLocationSuggestionPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	spot, err := h.service.ApplySpotLocationSuggestion(r.Context(), management.ApplySpotLocationSuggestionParams{
+		SpotID:      spotID,
+		Locality:    payload.Locality,
+		CountryCode: payload.CountryCode,
+		Region:      payload.Region,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrEmptySuggestionSelection, httputil.NewInvalidField("locality", "At least one suggestion must be chosen."))
+				f.Is(e, management.ErrEmptySuggestionSelection, httputil.NewInvalidField("country_code", "At least one suggestion must be chosen."))
+				f.Is(e, management.ErrEmptySuggestionSelection, httputil.NewInvalidField("region", "At least one suggestion must be chosen."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, geo.ErrLocationNotFound) {
+			httputil.WriteNotFoundError(w, r, "Location was not found.")
+			return
+		}
+
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		if errors.Is(err, surf.ErrDuplicateSpot) {
+			writeDuplicateSpotError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotResponse(spot))
+}
+
+func (h *managementHandler) spotSuggestions(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	offset, err := httputil.QueryParamInt(r, "offset")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("offset", "Must be a valid integer."))
+		return
+	}
+
+	params := management.SpotSuggestionsParams{
+		Limit:  limit,
+		Offset: offset,
+		Status: httputil.QueryParam(r, "status"),
+	}
+
+	suggestions, err := h.service.SpotSuggestions(r.Context(), params)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSuggestionStatus, httputil.NewInvalidField("status", "Must be one of: pending, applied, rejected."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := spotSuggestionsResponse{
+		Items: make([]spotSuggestionResponse, len(suggestions)),
+	}
+
+	for i, s := range suggestions {
+		resp.Items[i] = toSpotSuggestionResponse(s)
+	}
+
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(suggestions))
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *managementHandler) auditEntries(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	offset, err := httputil.QueryParamInt(r, "offset")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("offset", "Must be a valid integer."))
+		return
+	}
+
+	from, err := httputil.QueryParamTime(r, "from")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("from", "Must be a valid RFC3339 time."))
+		return
+	}
+
+	to, err := httputil.QueryParamTime(r, "to")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("to", "Must be a valid RFC3339 time."))
+		return
+	}
+
+	format := httputil.QueryParam(r, "format")
+	if format != "" && format != auditFormatJSON && format != auditFormatCSV {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("format", "Must be one of: json, csv."))
+		return
+	}
+
+	params := management.AuditEntriesParams{
+		Limit:   limit,
+		Offset:  offset,
+		SpotID:  httputil.QueryParam(r, paramKeySpotID),
+		ActorID: httputil.QueryParam(r, "actor"),
+		From:    from,
+		To:      to,
+	}
+
+	entries, err := h.service.AuditEntries(r.Context(), params)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidTo, httputil.NewInvalidField("to", "Must be a valid RFC3339 time after from."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(entries))
+
+	if format == auditFormatCSV {
+		if err := writeAuditEntriesCSV(w, entries); err != nil {
+			if logger := log.FromContext(r.Context()); logger != nil {
+				logger.WithError(err).Errorf("failed to write audit entries: %s", err)
+			}
+		}
+		return
+	}
+
+	resp := auditEntriesResponse{
+		Items: make([]auditEntryResponse, len(entries)),
+	}
+
+	for i, e := range entries {
+		resp.Items[i] = toAuditEntryResponse(e)
 	}
 
 	httputil.WriteOK(w, r, resp)
 }
+
+func (h *managementHandler) approveSpotSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestionID := chi.URLParam(r, paramKeySuggestionID)
+
+	suggestion, err := h.service.ApproveSpotSuggestion(r.Context(), suggestionID)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			// The suggestion's values get validated once again while being applied,
+			// so they can turn out invalid if validation rules have become stricter
+			// since the suggestion was made.
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSuggestionID, httputil.NewInvalidField(paramKeySuggestionID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidSpotName, httputil.NewInvalidField("name", "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country_code", "Must be a valid ISO-2 country code."))
+				f.Is(e, management.ErrLocationMismatch, httputil.NewInvalidField("country_code", "Must match the country at the spot's coordinates."))
+				f.Is(e, management.ErrInvalidLocality, httputil.NewInvalidField("locality", "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotSuggestionNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such suggestion doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotSuggestionResolved) {
+			httputil.WriteConflictError(w, r, "Suggestion has already been resolved.")
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, management.ErrLocationTimeout) {
+			writeGeocoderTimeoutError(w, r)
+			return
+		}
+
+		if errors.Is(err, surf.ErrDuplicateSpot) {
+			writeDuplicateSpotError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotSuggestionResponse(suggestion))
+}
+
+func (h *managementHandler) rejectSpotSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestionID := chi.URLParam(r, paramKeySuggestionID)
+
+	suggestion, err := h.service.RejectSpotSuggestion(r.Context(), suggestionID)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSuggestionID, httputil.NewInvalidField(paramKeySuggestionID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotSuggestionNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such suggestion doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotSuggestionResolved) {
+			httputil.WriteConflictError(w, r, "Suggestion has already been resolved.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotSuggestionResponse(suggestion))
+}
+
+// msgInvalidReferenceSet describes names of reference sets that the management
+// service accepts.
+const msgInvalidReferenceSet = "Must contain up to 50 characters of a-z, 0-9, - and _."
+
+type replaceReferenceSetPayload struct {
+	Points []referencePointPayload `json:"points"`
+}
+
+type referencePointPayload struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (h *managementHandler) replaceReferenceSet(w http.ResponseWriter, r *http.Request) {
+	set := chi.URLParam(r, paramKeyReferenceSet)
+
+	var payload replaceReferenceSetPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	points := make([]surf.ReferencePoint, len(payload.Points))
+	for i, p := range payload.Points {
+		points[i] = surf.ReferencePoint{
+			Name: p.Name,
+			Coordinates: geo.Coordinates{
+				Latitude:  p.Latitude,
+				Longitude: p.Longitude,
+			},
+		}
+	}
+
+	count, err := h.service.ReplaceReferenceSet(r.Context(), management.ReplaceReferenceSetParams{
+		Set:    set,
+		Points: points,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidReferenceSet, httputil.NewInvalidField(paramKeyReferenceSet, msgInvalidReferenceSet))
+				f.Is(e, management.ErrInvalidReferencePoints, httputil.NewInvalidField("points", "Must contain 1 to 1000 uniquely named valid points."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, backfillReferenceDistancesResponse{
+		BackfilledCount: count,
+	})
+}
+
+func (h *managementHandler) backfillReferenceDistances(w http.ResponseWriter, r *http.Request) {
+	set := chi.URLParam(r, paramKeyReferenceSet)
+
+	count, err := h.service.BackfillReferenceDistances(r.Context(), set)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidReferenceSet, httputil.NewInvalidField(paramKeyReferenceSet, msgInvalidReferenceSet))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, management.ErrReferenceSetNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such reference set doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, backfillReferenceDistancesResponse{
+		BackfilledCount: count,
+	})
+}
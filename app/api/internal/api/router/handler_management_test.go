@@ -1,12 +1,16 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -34,11 +38,36 @@ func (m *mockManagementService) Spot(ctx context.Context, id string) (surf.Spot,
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
+func (m *mockManagementService) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockManagementService) SpotWithNearby(ctx context.Context, p management.SpotWithNearbyParams) (management.SpotWithNearby, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(management.SpotWithNearby), args.Error(1)
+}
+
 func (m *mockManagementService) Spots(ctx context.Context, p management.SpotsParams) ([]surf.Spot, error) {
 	args := m.Called(ctx, p)
 	return args.Get(0).([]surf.Spot), args.Error(1)
 }
 
+func (m *mockManagementService) SpotsQueryPlan(ctx context.Context, p management.SpotsParams) ([]byte, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockManagementService) ExportSpots(ctx context.Context, p management.ExportSpotsParams, fn func(surf.Spot) error) error {
+	args := m.Called(ctx, p)
+	for _, s := range args.Get(0).([]surf.Spot) {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *mockManagementService) CreateSpot(ctx context.Context, p management.CreateSpotParams) (surf.Spot, error) {
 	args := m.Called(ctx, p)
 	return args.Get(0).(surf.Spot), args.Error(1)
@@ -54,17 +83,132 @@ func (m *mockManagementService) DeleteSpot(ctx context.Context, id string) error
 	return args.Error(0)
 }
 
+func (m *mockManagementService) CreateSpotIdempotently(ctx context.Context, key string, p management.CreateSpotParams) (surf.Spot, error) {
+	args := m.Called(ctx, key, p)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockManagementService) CreateSpots(ctx context.Context, ps []management.CreateSpotParams) (management.CreateSpotsResult, error) {
+	args := m.Called(ctx, ps)
+	return args.Get(0).(management.CreateSpotsResult), args.Error(1)
+}
+
+func (m *mockManagementService) DeleteSpots(ctx context.Context, ids []string) (management.DeleteSpotsResult, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(management.DeleteSpotsResult), args.Error(1)
+}
+
+func (m *mockManagementService) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockManagementService) SetSpotTags(ctx context.Context, p management.SetSpotTagsParams) (surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockManagementService) UploadSpotPhoto(ctx context.Context, p management.UploadSpotPhotoParams) (management.SpotPhoto, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(management.SpotPhoto), args.Error(1)
+}
+
+func (m *mockManagementService) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) error {
+	args := m.Called(ctx, spotID, photoID)
+	return args.Error(0)
+}
+
+func (m *mockManagementService) ReplaceReferenceSet(ctx context.Context, p management.ReplaceReferenceSetParams) (int, error) {
+	args := m.Called(ctx, p)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockManagementService) BackfillReferenceDistances(ctx context.Context, set string) (int, error) {
+	args := m.Called(ctx, set)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockManagementService) SpotSuggestions(ctx context.Context, p management.SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockManagementService) ApproveSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockManagementService) RejectSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockManagementService) Locations(ctx context.Context, cs []geo.Coordinates) ([]management.LocationsEntryResult, error) {
+	args := m.Called(ctx, cs)
+	return args.Get(0).([]management.LocationsEntryResult), args.Error(1)
+}
+
+func (m *mockManagementService) AuditEntries(ctx context.Context, p management.AuditEntriesParams) ([]surf.AuditEntry, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.AuditEntry), args.Error(1)
+}
+
+func (m *mockManagementService) Localities(ctx context.Context, p management.LocalitiesParams) ([]string, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockManagementService) SpotsOverTime(ctx context.Context, p management.SpotsOverTimeParams) ([]surf.SpotCreationCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotCreationCount), args.Error(1)
+}
+
+func (m *mockManagementService) Countries(ctx context.Context, p management.CountriesParams) ([]surf.CountrySpotCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.CountrySpotCount), args.Error(1)
+}
+
+func (m *mockManagementService) SpotCountsByCountry(ctx context.Context) ([]surf.CountrySpotCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]surf.CountrySpotCount), args.Error(1)
+}
+
 func (m *mockManagementService) Location(ctx context.Context, c geo.Coordinates) (geo.Location, error) {
 	args := m.Called(ctx, c)
 	return args.Get(0).(geo.Location), args.Error(1)
 }
 
+func (m *mockManagementService) SuggestSpotLocation(ctx context.Context, id string) (management.SpotLocationSuggestion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(management.SpotLocationSuggestion), args.Error(1)
+}
+
+func (m *mockManagementService) ApplySpotLocationSuggestion(ctx context.Context, p management.ApplySpotLocationSuggestionParams) (surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+var etagTestSpot = surf.Spot{
+	Location: geo.Location{
+		Coordinates: geo.Coordinates{
+			Latitude:  1.23,
+			Longitude: 3.21,
+		},
+		Locality:    "Locality 1",
+		CountryCode: "Country code 1",
+	},
+	ID:        "1",
+	Name:      "Spot 1",
+	CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+	UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+}
+
 func TestManagementHandler_Spot(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
 		id                 string
+		requestFn          func(r *http.Request)
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
@@ -76,8 +220,7 @@ func TestManagementHandler_Spot(t *testing.T) {
 					Return(surf.Spot{}, errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
@@ -106,8 +249,7 @@ func TestManagementHandler_Spot(t *testing.T) {
 					Return(surf.Spot{}, surf.ErrSpotNotFound)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
@@ -136,8 +278,7 @@ func TestManagementHandler_Spot(t *testing.T) {
 					Return(surf.Spot{}, valerra.NewErrors(management.ErrInvalidSpotID))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -187,10 +328,70 @@ func TestManagementHandler_Spot(t *testing.T) {
 					)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "Country code 1",
+							"country_name": ""
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 304 status code and empty body for matching etag",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(etagTestSpot, nil)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Header.Set("If-None-Match", `"stale", `+spotETag(etagTestSpot))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotModified, r.StatusCode)
+				assert.Equal(t, spotETag(etagTestSpot), r.Header.Get("ETag"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+				assert.Empty(t, body)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body for non-matching etag",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(etagTestSpot, nil)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Header.Set("If-None-Match", `"stale"`)
+			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, spotETag(etagTestSpot), r.Header.Get("ETag"))
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -205,7 +406,9 @@ func TestManagementHandler_Spot(t *testing.T) {
 							"latitude": 1.23,
 							"longitude": 3.21,
 							"locality": "Locality 1",
-							"country_code": "Country code 1"
+							"country_code": "Country code 1",
+							"country_name": "",
+							"updated_at": "2021-01-02T01:01:01Z"
 						}
 					}`,
 					string(body),
@@ -216,41 +419,45 @@ func TestManagementHandler_Spot(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
 			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/spots/"+test.id, nil)
 			assert.NoError(t, err)
 
+			if test.requestFn != nil {
+				test.requestFn(req)
+			}
+
 			resp, err := http.DefaultClient.Do(req)
 			assert.NoError(t, err)
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestManagementHandler_Spots(t *testing.T) {
+func TestManagementHandler_SpotBySlug(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
-		requestFn          func(r *http.Request)
+		slug               string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
-			name:    "respond with 400 status code and error body for invalid limit",
-			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"a"},
-					"offset": []string{"0"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotBySlug", mock.Anything, "spot-1").
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			slug: "spot-1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -260,14 +467,8 @@ func TestManagementHandler_Spots(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "limit",
-									"reason": "Must be a valid integer."
-								}
-							]
+							"code": "unexpected",
+							"description": "Something went wrong..."
 						}
 					}`,
 					string(body),
@@ -275,18 +476,17 @@ func TestManagementHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid offset",
-			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"a"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotBySlug", mock.Anything, "spot-1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			slug: "spot-1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -296,14 +496,8 @@ func TestManagementHandler_Spots(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "offset",
-									"reason": "Must be a valid integer."
-								}
-							]
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
 						}
 					}`,
 					string(body),
@@ -311,20 +505,15 @@ func TestManagementHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid north-east latitude",
-			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"a"},
-					"ne_lon": []string{"180"},
-					"sw_lat": []string{"-90"},
-					"sw_lon": []string{"-180"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 400 status code and error body for invalid slug",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotBySlug", mock.Anything, " ").
+					Return(surf.Spot{}, valerra.NewErrors(management.ErrInvalidSlug))
+				return m
+			}(),
+			slug: "%20",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -340,8 +529,8 @@ func TestManagementHandler_Spots(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "ne_lat",
-									"reason": "Must be a valid latitude."
+									"key": "slug",
+									"reason": "Must be a non empty string."
 								}
 							]
 						}
@@ -351,22 +540,33 @@ func TestManagementHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid north-east longitude",
-			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"90"},
-					"ne_lon": []string{"a"},
-					"sw_lat": []string{"-90"},
-					"sw_lon": []string{"-180"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 200 status code and spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotBySlug", mock.Anything, "spot-1").
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "Country code 1",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							Slug:      "spot-1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			slug: "spot-1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusOK, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -375,25 +575,261 @@ func TestManagementHandler_Spots(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "ne_lon",
-									"reason": "Must be a valid longitude."
-								}
-							]
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"slug": "spot-1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "Country code 1",
+							"country_name": ""
 						}
 					}`,
 					string(body),
 				)
 			},
 		},
-		{
-			name:    "respond with 400 status code and error body for invalid south-west latitude",
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/management/v1/spots/slug/" + test.slug)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Spot_ContextErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedStatus   int
+		expectedResponse string
+		expectedLevel    string
+	}{
+		{
+			name:           "respond with 499 status code and log at debug level for canceled request",
+			err:            fmt.Errorf("failed to execute query: %w", context.Canceled),
+			expectedStatus: 499,
+			expectedResponse: `{
+				"error": {
+					"code": "canceled",
+					"description": "Request was canceled."
+				}
+			}`,
+			expectedLevel: `"level":"debug"`,
+		},
+		{
+			name:           "respond with 503 status code and log at warning level for timed out request",
+			err:            fmt.Errorf("failed to execute query: %w", context.DeadlineExceeded),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedResponse: `{
+				"error": {
+					"code": "timeout",
+					"description": "Request timed out."
+				}
+			}`,
+			expectedLevel: `"level":"warning"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&buf)
+			logger.SetFormatter(&logrus.JSONFormatter{})
+			logger.SetLevel(logrus.DebugLevel)
+
+			service := newMockManagementService()
+			service.
+				On("Spot", mock.Anything, "1").
+				Return(surf.Spot{}, test.err)
+
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), service, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/management/v1/spots/1")
+			assert.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.JSONEq(t, test.expectedResponse, string(body))
+			assert.Contains(t, buf.String(), test.expectedLevel)
+			assert.NotContains(t, buf.String(), `"level":"error"`)
+		})
+	}
+}
+
+func TestManagementHandler_Spots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid limit",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"a"},
+					"offset": []string{"0"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "limit",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid offset",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "offset",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid north-east latitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"a"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid north-east longitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"90"},
+					"ne_lon": []string{"a"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid south-west latitude",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
 					"limit":  []string{"10"},
@@ -433,7 +869,6 @@ func TestManagementHandler_Spots(t *testing.T) {
 		{
 			name:    "respond with 400 status code and error body for invalid south-west longitude",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
 					"limit":  []string{"10"},
@@ -490,7 +925,6 @@ func TestManagementHandler_Spots(t *testing.T) {
 					))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
 					"limit":   []string{"10"},
@@ -556,7 +990,6 @@ func TestManagementHandler_Spots(t *testing.T) {
 					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
 					"limit":  []string{"10"},
@@ -584,23 +1017,59 @@ func TestManagementHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and empty spot list body",
+			name:    "respond with 400 status code and error body for invalid debug mode",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"debug": []string{"unknown"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "debug",
+									"reason": "Must be one of: explain."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for disabled diagnostics",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Spots", mock.Anything, management.SpotsParams{
-						Limit:  0,
-						Offset: 0,
-					}).
+					On("Spots", mock.Anything, management.SpotsParams{}).
 					Return(([]surf.Spot)(nil), nil)
+				m.
+					On("SpotsQueryPlan", mock.Anything, management.SpotsParams{}).
+					Return(([]byte)(nil), management.ErrDiagnosticsDisabled)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				// Omit query parameters
+				vals := url.Values{
+					"debug": []string{"explain"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -609,8 +1078,15 @@ func TestManagementHandler_Spots(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"data": {
-							"items":[]
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "debug",
+									"reason": "Diagnostics are disabled."
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -618,25 +1094,268 @@ func TestManagementHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and spot list body",
+			name: "respond with 500 status code and error body for query plan failure",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Spots", mock.Anything, management.SpotsParams{
-						Limit:       10,
-						Offset:      0,
-						CountryCode: "kz",
-						SearchQuery: "query",
-						Bounds: &geo.Bounds{
-							NorthEast: geo.Coordinates{
-								Latitude:  90,
-								Longitude: 180,
-							},
-							SouthWest: geo.Coordinates{
-								Latitude:  -90,
-								Longitude: -180,
-							},
-						},
+					On("Spots", mock.Anything, management.SpotsParams{}).
+					Return(([]surf.Spot)(nil), nil)
+				m.
+					On("SpotsQueryPlan", mock.Anything, management.SpotsParams{}).
+					Return(([]byte)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"debug": []string{"explain"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot list body with query plan",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{}).
+					Return(([]surf.Spot)(nil), nil)
+				m.
+					On("SpotsQueryPlan", mock.Anything, management.SpotsParams{}).
+					Return([]byte(`[{"Plan":{"Node Type":"Limit"}}]`), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"debug": []string{"explain"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [],
+							"query_plan": [{"Plan":{"Node Type":"Limit"}}]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty spot list body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Limit:  0,
+						Offset: 0,
+					}).
+					Return(([]surf.Spot)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				// Omit query parameters
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items":[]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid include_deleted flag",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"include_deleted": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "include_deleted",
+									"reason": "Must be a valid boolean."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid sort",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Sort: "name",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(management.ErrInvalidSort))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"sort": []string{"name"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "sort",
+									"reason": "Must be one of: -updated_at, reference_distance."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid search mode",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						SearchQuery: "kazakh beach",
+						SearchMode:  "regex",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(management.ErrInvalidSearchMode))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query":       []string{"kazakh beach"},
+					"search_mode": []string{"regex"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "search_mode",
+									"reason": "Must be either substring or fulltext."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spots found using full-text search",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						SearchQuery: "kazakh beach",
+						SearchMode:  "fulltext",
+					}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query":       []string{"kazakh beach"},
+					"search_mode": []string{"fulltext"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and spot list body sorted by update time",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Sort: management.SortUpdatedAtDesc,
 					}).
 					Return(
 						[]surf.Spot{
@@ -652,41 +1371,4669 @@ func TestManagementHandler_Spots(t *testing.T) {
 								ID:        "1",
 								Name:      "Spot 1",
 								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 2",
-									CountryCode: "kz",
-								},
-								ID:        "2",
-								Name:      "Spot 2",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+								UpdatedAt: time.Date(2021, 3, 1, 1, 1, 1, 1, time.UTC),
 							},
 						},
 						nil,
 					)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
-					"limit":   []string{"10"},
-					"offset":  []string{"0"},
-					"country": []string{"kz"},
-					"query":   []string{"query"},
-					"ne_lat":  []string{"90"},
-					"ne_lon":  []string{"180"},
-					"sw_lat":  []string{"-90"},
-					"sw_lon":  []string{"-180"},
+					"sort": []string{"-updated_at"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"updated_at": "2021-03-01T01:01:01Z"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot list body including deleted spots",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						IncludeDeleted: true,
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+								DeletedAt: func() *time.Time {
+									t := time.Date(2021, 2, 1, 1, 1, 1, 1, time.UTC)
+									return &t
+								}(),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"include_deleted": []string{"true"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"deleted_at": "2021-02-01T01:01:01Z"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot list body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "kz",
+						SearchQuery: "query",
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:        "2",
+								Name:      "Spot 2",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":   []string{"10"},
+					"offset":  []string{"0"},
+					"country": []string{"kz"},
+					"query":   []string{"query"},
+					"ne_lat":  []string{"90"},
+					"ne_lon":  []string{"180"},
+					"sw_lat":  []string{"-90"},
+					"sw_lon":  []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								},
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 2",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid tags",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Tags:      []string{"reef", "point"},
+						TagsMatch: "some",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(management.ErrInvalidTags, management.ErrInvalidTagsMatch))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"tags":       []string{"reef,point"},
+					"tags_match": []string{"some"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "tags",
+									"reason": "Must contain at most 10 tags of at most 30 characters."
+								},
+								{
+									"key": "tags_match",
+									"reason": "Must be either all or any."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spots filtered by tags",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Tags:      []string{"reef", "point"},
+						TagsMatch: "any",
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Name: "Spot 1",
+							Tags: []string{"point", "reef"},
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"tags":       []string{"reef,point"},
+					"tags_match": []string{"any"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"tags": ["point", "reef"]
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spots nearest to reference set",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spots", mock.Anything, management.SpotsParams{
+						Sort:         "reference_distance",
+						ReferenceSet: "airports",
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+							NearestReference: &surf.ReferenceDistance{
+								PointName:  "Aktau",
+								DistanceKm: 24.21880889,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"sort":          []string{"reference_distance"},
+					"reference_set": []string{"airports"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"nearest_reference": {
+										"name": "Aktau",
+										"distance_km": 24.22
+									}
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/spots", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_SpotWithNearby(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid include",
+			service: newMockManagementService(),
+			query:   "include=nearby,tags",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "include",
+									"reason": "Must be one of: nearby."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid radius format",
+			service: newMockManagementService(),
+			query:   "include=nearby&radius_km=a",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "radius_km",
+									"reason": "Must be a valid number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotWithNearby", mock.Anything, management.SpotWithNearbyParams{
+						SpotID:   "1",
+						RadiusKm: -1,
+					}).
+					Return(management.SpotWithNearby{}, valerra.NewErrors(management.ErrInvalidRadius))
+				return m
+			}(),
+			query: "include=nearby&radius_km=-1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "radius_km",
+									"reason": "Must be a positive number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotWithNearby", mock.Anything, management.SpotWithNearbyParams{
+						SpotID: "1",
+					}).
+					Return(management.SpotWithNearby{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			query: "include=nearby",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body with nearby spots",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotWithNearby", mock.Anything, management.SpotWithNearbyParams{
+						SpotID:   "1",
+						RadiusKm: 10,
+					}).
+					Return(management.SpotWithNearby{
+						Spot: surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:   "1",
+							Name: "Spot 1",
+						},
+						Nearby: []surf.NearbySpot{
+							{
+								Spot: surf.Spot{
+									Location: geo.Location{
+										Coordinates: geo.Coordinates{
+											Latitude:  1.24,
+											Longitude: 3.22,
+										},
+										Locality:    "Locality 1",
+										CountryCode: "kz",
+									},
+									ID:   "2",
+									Name: "Spot 2",
+								},
+								DistanceKm: 1.5678,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			query: "include=nearby&radius_km=10",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Empty(t, r.Header.Get("ETag"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan",
+							"nearby": [
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.24,
+									"longitude": 3.22,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 1.57
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/management/v1/spots/1?" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Spot_Head(t *testing.T) {
+	tests := []struct {
+		name           string
+		service        managementService
+		id             string
+		expectedStatus int
+		expectedLength string
+	}{
+		{
+			name: "respond with 200 status code and headers of spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(etagTestSpot, nil)
+				return m
+			}(),
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "respond with 404 status code for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Spot", mock.Anything, "2").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id:             "2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, nil))
+			defer server.Close()
+
+			getResp, err := http.Get(server.URL + "/management/v1/spots/" + test.id)
+			if !assert.NoError(t, err) {
+				return
+			}
+			getBody, err := ioutil.ReadAll(getResp.Body)
+			getResp.Body.Close()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			resp, err := http.Head(server.URL + "/management/v1/spots/" + test.id)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, getResp.Header.Get("Content-Type"), resp.Header.Get("Content-Type"))
+			assert.Equal(t, getResp.Header.Get("ETag"), resp.Header.Get("ETag"))
+			assert.Equal(t, strconv.Itoa(len(getBody)), resp.Header.Get(headerContentLength))
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.Empty(t, body)
+		})
+	}
+}
+
+func TestManagementHandler_Spots_PaginationHeaders(t *testing.T) {
+	service := newMockManagementService()
+	service.
+		On("Spots", mock.Anything, management.SpotsParams{
+			Offset:      10,
+			CountryCode: "kz",
+		}).
+		Return(make([]surf.Spot, 10), nil)
+
+	server := httptest.NewServer(newRouter(nil, newMockSurfingService(), service, nil, nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/management/v1/spots?country=kz&offset=10")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get(headerLimit))
+	assert.Equal(t, "10", resp.Header.Get(headerOffset))
+	assert.Equal(
+		t,
+		`</management/v1/spots?country=kz&limit=10&offset=20>; rel="next", `+
+			`</management/v1/spots?country=kz&limit=10&offset=0>; rel="prev"`,
+		resp.Header.Get(headerLink),
+	)
+}
+
+func TestManagementHandler_ExportSpots(t *testing.T) {
+	spots := []surf.Spot{
+		{
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+			},
+			ID:        "1",
+			Name:      "Spot 1",
+			CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+		},
+		{
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  -4.56,
+					Longitude: 6.54,
+				},
+				Locality:    "Locality, 2",
+				CountryCode: "kz",
+			},
+			ID:        "2",
+			Name:      "Spot 2",
+			CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid format",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"format": []string{"xml"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "format",
+									"reason": "Must be one of: csv, geojson."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid bounds",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ne_lat": []string{"a"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"b"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "sw_lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid input parameters",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{
+						CountryCode: "zz",
+						SearchQuery: "query",
+					}).
+					Return(
+						[]surf.Spot{},
+						valerra.NewErrors(
+							management.ErrInvalidSearchQuery,
+							management.ErrInvalidCountryCode,
+						),
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"country": []string{"zz"},
+					"query":   []string{"query"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "query",
+									"reason": "Must not exceed character limit."
+								},
+								{
+									"key": "country",
+									"reason": "Must be a valid ISO-2 country code."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{}).
+					Return([]surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				// Omit query parameters
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and csv body with header only for no spots",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				// Omit query parameters
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "text/csv; charset=utf-8", r.Header.Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="spots.csv"`, r.Header.Get("Content-Disposition"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.Equal(t, "name,latitude,longitude,locality,country_code,external_ref\n", string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and csv body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{
+						CountryCode: "kz",
+						SearchQuery: "query",
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return(spots, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"format":  []string{"csv"},
+					"country": []string{"kz"},
+					"query":   []string{"query"},
+					"ne_lat":  []string{"90"},
+					"ne_lon":  []string{"180"},
+					"sw_lat":  []string{"-90"},
+					"sw_lon":  []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "text/csv; charset=utf-8", r.Header.Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="spots.csv"`, r.Header.Get("Content-Disposition"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.Equal(
+					t,
+					"name,latitude,longitude,locality,country_code,external_ref\n"+
+						"Spot 1,1.23,3.21,Locality 1,kz,1\n"+
+						"Spot 2,-4.56,6.54,\"Locality, 2\",kz,2\n",
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and geojson body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{
+						CountryCode: "kz",
+					}).
+					Return(spots, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"format":  []string{"geojson"},
+					"country": []string{"kz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "application/geo+json", r.Header.Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="spots.geojson"`, r.Header.Get("Content-Disposition"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"type": "FeatureCollection",
+						"features": [
+							{
+								"type": "Feature",
+								"id": "1",
+								"geometry": {
+									"type": "Point",
+									"coordinates": [3.21, 1.23]
+								},
+								"properties": {
+									"name": "Spot 1",
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								}
+							},
+							{
+								"type": "Feature",
+								"id": "2",
+								"geometry": {
+									"type": "Point",
+									"coordinates": [6.54, -4.56]
+								},
+								"properties": {
+									"name": "Spot 2",
+									"locality": "Locality, 2",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								}
+							}
+						]
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty geojson feature collection for no spots",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ExportSpots", mock.Anything, management.ExportSpotsParams{}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"format": []string{"geojson"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"type": "FeatureCollection", "features": []}`, string(body))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/spots/export", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_CreateSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				// Omit request body
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpot", mock.Anything, management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(
+						management.ErrInvalidSpotName,
+						management.ErrInvalidCountryCode,
+						management.ErrInvalidLocality,
+						management.ErrInvalidLatitude,
+						management.ErrInvalidLongitude,
+					))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "name",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "country_code",
+									"reason": "Must be a valid ISO-2 country code."
+								},
+								{
+									"key": "locality",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "latitude",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "longitude",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 409 status code and error body for duplicate spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpot", mock.Anything, management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(surf.Spot{}, &surf.StoreError{
+						Kind: surf.ErrDuplicateSpot,
+						Code: "23505",
+						Err:  errors.New("duplicate key value violates unique constraint"),
+					})
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusConflict, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "duplicate_spot",
+							"description": "Such spot already exists."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpot", mock.Anything, management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 201 status code and spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpot", mock.Anything, management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "Country code 1",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "Country code 1",
+							"country_name": ""
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid idempotency key",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpotIdempotently", mock.Anything, strings.Repeat("a", 256), management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(management.ErrInvalidIdempotencyKey))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Header.Set("Idempotency-Key", strings.Repeat("a", 256))
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "Idempotency-Key",
+									"reason": "Must be a non empty string of at most 255 characters."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for idempotency key of deleted spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpotIdempotently", mock.Anything, "key", management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Header.Set("Idempotency-Key", "key")
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 201 status code and spot body for idempotency key",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpotIdempotently", mock.Anything, "key", management.CreateSpotParams{
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						Name: "Spot 1",
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:   "1",
+							Name: "Spot 1",
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Header.Set("Idempotency-Key", "key")
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_UpdateSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			id:      "1",
+			requestFn: func(r *http.Request) {
+				// Omit request body
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Latitude:  pconv.Float64(1.23),
+						Longitude: pconv.Float64(3.21),
+						Name:      pconv.String(""),
+						ID:        "1",
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(
+						management.ErrInvalidSpotID,
+						management.ErrInvalidSpotName,
+						management.ErrInvalidCountryCode,
+						management.ErrInvalidLocality,
+						management.ErrInvalidLatitude,
+						management.ErrInvalidLongitude,
+					))
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "",
+						"latitude": 1.23,
+						"longitude": 3.21
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "spot_id",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "name",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "country_code",
+									"reason": "Must be a valid ISO-2 country code."
+								},
+								{
+									"key": "locality",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "latitude",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "longitude",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for country code that mismatches coordinates",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						ID:          "1",
+						CountryCode: pconv.String("tr"),
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(management.ErrLocationMismatch))
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{"country_code": "tr"}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "country_code",
+									"reason": "Must match the country at the spot's coordinates."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for empty input",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						ID: "1",
+					}).
+					Return(surf.Spot{}, surf.ErrEmptySpotUpdateEntry)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Nothing to update.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Latitude:  pconv.Float64(1.23),
+						Longitude: pconv.Float64(3.21),
+						Name:      pconv.String("Spot 1"),
+						ID:        "1",
+					}).
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 409 status code and error body for duplicate spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Latitude:  pconv.Float64(1.23),
+						Longitude: pconv.Float64(3.21),
+						Name:      pconv.String("Spot 1"),
+						ID:        "1",
+					}).
+					Return(surf.Spot{}, &surf.StoreError{
+						Kind: surf.ErrDuplicateSpot,
+						Code: "23505",
+						Err:  errors.New("duplicate key value violates unique constraint"),
+					})
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusConflict, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "duplicate_spot",
+							"description": "Such spot already exists."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Latitude:  pconv.Float64(1.23),
+						Longitude: pconv.Float64(3.21),
+						Name:      pconv.String("Spot 1"),
+						ID:        "1",
+					}).
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body for partial input",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Name:      pconv.String("Spot 1"),
+						Latitude:  pconv.Float64(1.23),
+						Longitude: pconv.Float64(3.21),
+						ID:        "1",
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body for partial input",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Locality:    pconv.String("Locality 1"),
+						CountryCode: pconv.String("kz"),
+						ID:          "1",
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body for full input",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						Name:        pconv.String("Spot 1"),
+						Latitude:    pconv.Float64(1.23),
+						Longitude:   pconv.Float64(3.21),
+						Locality:    pconv.String("Locality 1"),
+						CountryCode: pconv.String("kz"),
+						ID:          "1",
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Locality 1",
+						"country_code": "kz"
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body for input with nulls",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
+						ID:               "1",
+						ClearLocality:    true,
+						ClearCountryCode: true,
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"name": null,
+						"locality": null,
+						"country_code": null
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "",
+							"country_code": "",
+							"country_name": ""
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPatch, server.URL+"/management/v1/spots/"+test.id, nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_DeleteSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(surf.ErrSpotNotFound)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid spot id",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(valerra.NewErrors(management.ErrInvalidSpotID))
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "spot_id",
+									"reason": "Must be a non empty string."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 204 status code",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNoContent, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.Equal(t, "", string(body))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodDelete, server.URL+"/management/v1/spots/"+test.id, nil)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_CreateSpots(t *testing.T) {
+	params := []management.CreateSpotParams{
+		{
+			Name: "Spot 1",
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+			},
+		},
+		{
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				Locality:    "Locality 2",
+				CountryCode: "kz",
+			},
+		},
+	}
+
+	payload := `[
+		{
+			"name": "Spot 1",
+			"latitude": 1.23,
+			"longitude": 3.21,
+			"locality": "Locality 1",
+			"country_code": "kz"
+		},
+		{
+			"latitude": 1.23,
+			"longitude": 3.21,
+			"locality": "Locality 2",
+			"country_code": "kz"
+		}
+	]`
+
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{"name": "Spot 1"}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid number of entries",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpots", mock.Anything, []management.CreateSpotParams{}).
+					Return(management.CreateSpotsResult{}, valerra.NewErrors(management.ErrInvalidSpotEntries))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`[]`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "spots",
+									"reason": "Must contain from 1 to 100 spots."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpots", mock.Anything, params).
+					Return(management.CreateSpotsResult{}, errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(payload))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 207 status code and per-item statuses for partial failure",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpots", mock.Anything, params).
+					Return(management.CreateSpotsResult{
+						Created: 1,
+						Entries: []management.CreateSpotsEntryResult{
+							{Err: nil},
+							{Err: valerra.NewErrors(management.ErrInvalidSpotName)},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(payload))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusMultiStatus, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"created": 1,
+							"items": [
+								{
+									"index": 0,
+									"status": 201
+								},
+								{
+									"index": 1,
+									"status": 400,
+									"error": {
+										"code": "invalid_input",
+										"description": "Invalid input parameters.",
+										"fields": [
+											{
+												"key": "name",
+												"reason": "Must be a non empty string."
+											}
+										]
+									}
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 201 status code and per-item statuses when all entries are created",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("CreateSpots", mock.Anything, params).
+					Return(management.CreateSpotsResult{
+						Created: 2,
+						Entries: []management.CreateSpotsEntryResult{
+							{Err: nil},
+							{Err: nil},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(payload))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"created": 2,
+							"items": [
+								{
+									"index": 0,
+									"status": 201
+								},
+								{
+									"index": 1,
+									"status": 201
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots/batch", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_DeleteSpots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{"ids": ["1"]}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpots", mock.Anything, []string{}).
+					Return(management.DeleteSpotsResult{}, valerra.NewErrors(management.ErrInvalidSpotIDs))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`[]`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ids",
+									"reason": "Must contain from 1 to 100 spot ids."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpots", mock.Anything, []string{"1"}).
+					Return(management.DeleteSpotsResult{}, errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`["1"]`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and deletion result body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpots", mock.Anything, []string{"1", "2", "3"}).
+					Return(management.DeleteSpotsResult{
+						DeletedCount: 2,
+						NotFoundIDs:  []string{"2"},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`["1", "2", "3"]`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"deleted_count": 2,
+							"not_found_ids": ["2"]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots/bulk-delete", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_RestoreSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 404 status code and error body when deleted spot is not found",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("RestoreSpot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such deleted spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("RestoreSpot", mock.Anything, "1").
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and restored spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("RestoreSpot", mock.Anything, "1").
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots/"+test.id+"/restore", nil)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Location(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid latitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"a"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for empty latitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid longitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for empty longitude",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Location", mock.Anything, geo.Coordinates{
+						Latitude:  -91,
+						Longitude: -181,
+					}).
+					Return(geo.Location{}, valerra.NewErrors(
+						management.ErrInvalidLatitude,
+						management.ErrInvalidLongitude,
+					))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"-91"},
+					"lon": []string{"-181"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Location", mock.Anything, geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body when location is not found",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Location", mock.Anything, geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Location was not found."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and location body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Location", mock.Anything, geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(
+						geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 503 status code, retry after header and error body for unavailable geocoder",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Location", mock.Anything, geo.Coordinates{Latitude: 1.23, Longitude: 3.21}).
+					Return(geo.Location{}, &geo.UnavailableError{RetryAfter: 1500 * time.Millisecond})
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+				assert.Equal(t, "2", r.Header.Get(headerRetryAfter))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "geocoder_unavailable",
+							"description": "Geocoder is temporarily unavailable."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/geo/location", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_SuggestSpotLocation(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 404 status code and error body when spot is not found",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SuggestSpotLocation", mock.Anything, "1").
+					Return(management.SpotLocationSuggestion{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body when location is not found",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SuggestSpotLocation", mock.Anything, "1").
+					Return(management.SpotLocationSuggestion{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Location was not found."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SuggestSpotLocation", mock.Anything, "1").
+					Return(management.SpotLocationSuggestion{}, errors.New("something went wrong"))
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and suggestion body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SuggestSpotLocation", mock.Anything, "1").
+					Return(
+						management.SpotLocationSuggestion{
+							Spot: surf.Spot{
+								ID:   "1",
+								Name: "Spot 1",
+								Location: geo.Location{
+									Locality:    "Old locality",
+									CountryCode: "ru",
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+								},
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+							Region:      "Region 1",
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"spot_id": "1",
+							"current": {
+								"locality": "Old locality",
+								"country_code": "ru",
+								"country_name": "Russia",
+								"region": ""
+							},
+							"suggested": {
+								"locality": "Locality 1",
+								"country_code": "kz",
+								"country_name": "Kazakhstan",
+								"region": "Region 1"
+							}
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/spots/"+test.id+"/location-suggestion", nil)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_ApplySpotLocationSuggestion(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			id:      "1",
+			requestFn: func(r *http.Request) {
+				// Omit request body
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for empty selection",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ApplySpotLocationSuggestion", mock.Anything, management.ApplySpotLocationSuggestionParams{
+						SpotID: "1",
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(management.ErrEmptySuggestionSelection))
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "locality",
+									"reason": "At least one suggestion must be chosen."
+								},
+								{
+									"key": "country_code",
+									"reason": "At least one suggestion must be chosen."
+								},
+								{
+									"key": "region",
+									"reason": "At least one suggestion must be chosen."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body when spot is not found",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ApplySpotLocationSuggestion", mock.Anything, management.ApplySpotLocationSuggestionParams{
+						SpotID:   "1",
+						Locality: true,
+					}).
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(`{"locality": true}`))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and updated spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ApplySpotLocationSuggestion", mock.Anything, management.ApplySpotLocationSuggestionParams{
+						SpotID:      "1",
+						Locality:    true,
+						CountryCode: true,
+						Region:      true,
+					}).
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Region:      "Region 1",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
+			requestFn: func(r *http.Request) {
+				r.Body = ioutil.NopCloser(strings.NewReader(
+					`{
+						"locality": true,
+						"country_code": true,
+						"region": true
+					}`,
+				))
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan",
+							"region": "Region 1"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots/"+test.id+"/location-suggestion/apply", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Countries(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 400 status code and error body for invalid search query",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Countries", mock.Anything, management.CountriesParams{
+						SearchQuery: "query",
+					}).
+					Return(([]surf.CountrySpotCount)(nil), valerra.NewErrors(management.ErrInvalidSearchQuery))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query": []string{"query"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "query",
+									"reason": "Must not exceed character limit."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Countries", mock.Anything, management.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty items when no spots match",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Countries", mock.Anything, management.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and countries body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Countries", mock.Anything, management.CountriesParams{
+						SearchQuery: "query",
+					}).
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
+						},
+						{
+							CountryCode: "us",
+							Count:       3,
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query": []string{"query"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"country_code": "kz",
+									"count": 12
+								},
+								{
+									"country_code": "us",
+									"count": 3
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/countries", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_SpotCountsByCountry(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotCountsByCountry", mock.Anything).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and counts body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotCountsByCountry", mock.Anything).
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
+						},
+						{
+							CountryCode: management.CountryCodeUnknown,
+							Count:       3,
+						},
+					}, nil)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"country_code": "kz",
+									"count": 12
+								},
+								{
+									"country_code": "zz",
+									"count": 3
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/management/v1/spots/counts-by-country")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_SetSpotTags(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		id                 string
+		body               string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			id:      "1",
+			body:    `{"tags": "reef"}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid tags",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SetSpotTags", mock.Anything, management.SetSpotTagsParams{
+						SpotID: "1",
+						Tags:   []string{"reef"},
+					}).
+					Return(surf.Spot{}, valerra.NewErrors(management.ErrInvalidTags))
+				return m
+			}(),
+			id:   "1",
+			body: `{"tags": ["reef"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "tags",
+									"reason": "Must contain at most 10 tags of at most 30 characters."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SetSpotTags", mock.Anything, management.SetSpotTagsParams{
+						SpotID: "1",
+						Tags:   []string{"reef"},
+					}).
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id:   "1",
+			body: `{"tags": ["reef"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SetSpotTags", mock.Anything, management.SetSpotTagsParams{
+						SpotID: "1",
+						Tags:   []string{"reef"},
+					}).
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			id:   "1",
+			body: `{"tags": ["reef"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SetSpotTags", mock.Anything, management.SetSpotTagsParams{
+						SpotID: "1",
+						Tags:   []string{"reef", "point"},
+					}).
+					Return(surf.Spot{
+						ID:   "1",
+						Name: "Spot 1",
+						Tags: []string{"point", "reef"},
+						Location: geo.Location{
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			id:   "1",
+			body: `{"tags": ["reef", "point"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan",
+							"tags": ["point", "reef"]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPut,
+				server.URL+"/management/v1/spots/"+test.id+"/tags",
+				strings.NewReader(test.body),
+			)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func newPhotoForm(t *testing.T, field string, content []byte) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fw, err := mw.CreateFormFile(field, "photo.png")
+	if !assert.NoError(t, err) {
+		return nil, ""
+	}
+	_, err = fw.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, mw.Close())
+
+	return &buf, mw.FormDataContentType()
+}
+
+func TestManagementHandler_UploadSpotPhoto(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	tests := []struct {
+		name               string
+		service            managementService
+		maxUploadSize      int64
+		bodyFn             func(t *testing.T) (*bytes.Buffer, string)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for non multipart payload",
+			service: newMockManagementService(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return bytes.NewBufferString(`{}`), "application/json"
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for missing photo",
+			service: newMockManagementService(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, "image", png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "photo",
+									"reason": "Must be a file."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:          "respond with 413 status code and error body for too large photo",
+			service:       newMockManagementService(),
+			maxUploadSize: 4,
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "payload_too_large",
+							"description": "Payload is too large."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UploadSpotPhoto", mock.Anything, management.UploadSpotPhotoParams{
+						SpotID:  "1",
+						Content: png,
+					}).
+					Return(management.SpotPhoto{}, valerra.NewErrors(management.ErrUnsupportedPhotoType))
+				return m
+			}(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "photo",
+									"reason": "Must be a JPEG, PNG, or WebP image."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UploadSpotPhoto", mock.Anything, mock.Anything).
+					Return(management.SpotPhoto{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 503 status code and error body for unavailable photos",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UploadSpotPhoto", mock.Anything, mock.Anything).
+					Return(management.SpotPhoto{}, management.ErrPhotosUnavailable)
+				return m
+			}(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "photos_unavailable",
+							"description": "Photos are unavailable."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UploadSpotPhoto", mock.Anything, mock.Anything).
+					Return(management.SpotPhoto{}, errors.New("something went wrong"))
+				return m
+			}(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 201 status code and photo",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("UploadSpotPhoto", mock.Anything, management.UploadSpotPhotoParams{
+						SpotID:  "1",
+						Content: png,
+					}).
+					Return(management.SpotPhoto{
+						SpotPhoto: surf.SpotPhoto{
+							ID:          "2",
+							SpotID:      "1",
+							ContentType: "image/png",
+							Size:        8,
+							CreatedAt:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+						URL: "/media/spots/1/2.png",
+					}, nil)
+				return m
+			}(),
+			bodyFn: func(t *testing.T) (*bytes.Buffer, string) {
+				return newPhotoForm(t, formKeyPhoto, png)
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "2",
+							"url": "/media/spots/1/2.png",
+							"content_type": "image/png",
+							"size": 8,
+							"created_at": "2021-01-01T00:00:00Z"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger,
+				WithMaxUploadSize(test.maxUploadSize)))
+			defer server.Close()
+
+			body, contentType := test.bodyFn(t)
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots/1/photos", body)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_DeleteSpotPhoto(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 404 status code and error body for unexisting photo",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(surf.ErrSpotPhotoNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such photo doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(valerra.NewErrors(management.ErrInvalidSpotID, management.ErrInvalidPhotoID))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "spot_id",
+									"reason": "Must be a non empty string."
+								},
+								{
+									"key": "photo_id",
+									"reason": "Must be a non empty string."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 204 status code",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(nil)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNoContent, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.Equal(t, "", string(body))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodDelete, server.URL+"/management/v1/spots/1/photos/2", nil)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Localities(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid limit",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix": []string{"ka"},
+					"limit":  []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "limit",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Localities", mock.Anything, management.LocalitiesParams{
+						Prefix:      "k",
+						CountryCode: "zz",
+					}).
+					Return(([]string)(nil), valerra.NewErrors(management.ErrInvalidLocalityPrefix, management.ErrInvalidCountryCode))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix":  []string{"k"},
+					"country": []string{"zz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "prefix",
+									"reason": "Must contain at least 2 characters."
+								},
+								{
+									"key": "country",
+									"reason": "Must be a valid ISO-2 country code."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Localities", mock.Anything, management.LocalitiesParams{
+						Prefix: "ka",
+					}).
+					Return(([]string)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix": []string{"ka"},
 				}
 				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -695,39 +6042,82 @@ func TestManagementHandler_Spots(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"data": {
-							"items": [
-								{
-									"id": "1",
-									"name": "Spot 1",
-									"latitude": 1.23,
-									"longitude": 3.21,
-									"locality": "Locality 1",
-									"country_code": "kz"
-								},
-								{
-									"id": "2",
-									"name": "Spot 2",
-									"latitude": 1.23,
-									"longitude": 3.21,
-									"locality": "Locality 2",
-									"country_code": "kz"
-								}
-							]
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
 						}
 					}`,
 					string(body),
 				)
 			},
 		},
+		{
+			name: "respond with 200 status code and empty items when no localities match",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Localities", mock.Anything, management.LocalitiesParams{
+						Prefix: "ka",
+					}).
+					Return(([]string)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix": []string{"ka"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and localities body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Localities", mock.Anything, management.LocalitiesParams{
+						Prefix:      "ka",
+						CountryCode: "kz",
+						Limit:       10,
+					}).
+					Return([]string{"Kapchagay", "Karaganda"}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix":  []string{"ka"},
+					"country": []string{"kz"},
+					"limit":   []string{"10"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": ["Kapchagay", "Karaganda"]}}`, string(body))
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/spots", nil)
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/localities", nil)
 			assert.NoError(t, err)
 
 			test.requestFn(req)
@@ -736,24 +6126,30 @@ func TestManagementHandler_Spots(t *testing.T) {
 			assert.NoError(t, err)
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestManagementHandler_CreateSpot(t *testing.T) {
+func TestManagementHandler_SpotsOverTime(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
 		requestFn          func(r *http.Request)
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
-			name:    "respond with 400 status code and error body for invalid request body format",
+			name:    "respond with 400 status code and error body for invalid from",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				// Omit request body
+				vals := url.Values{
+					"from": []string{"2021-01-01"},
+					"to":   []string{"2021-04-01T00:00:00Z"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
@@ -767,8 +6163,13 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 					`{
 						"error": {
 							"code": "invalid_input",
-							"description": "Invalid payload.",
-							"fields": []
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "from",
+									"reason": "Must be a valid RFC3339 time."
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -776,39 +6177,61 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for validation error",
+			name:    "respond with 400 status code and error body for invalid to",
+			service: newMockManagementService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"from": []string{"2021-01-01T00:00:00Z"},
+					"to":   []string{"tomorrow"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "to",
+									"reason": "Must be a valid RFC3339 time."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("CreateSpot", mock.Anything, management.CreateSpotParams{
-						Location: geo.Location{
-							Coordinates: geo.Coordinates{
-								Latitude:  1.23,
-								Longitude: 3.21,
-							},
-							Locality:    "Locality 1",
-							CountryCode: "kz",
-						},
+					On("SpotsOverTime", mock.Anything, management.SpotsOverTimeParams{
+						To:       from,
+						Interval: "year",
 					}).
-					Return(surf.Spot{}, valerra.NewErrors(
-						management.ErrInvalidSpotName,
-						management.ErrInvalidCountryCode,
-						management.ErrInvalidLocality,
-						management.ErrInvalidLatitude,
-						management.ErrInvalidLongitude,
-					))
+					Return(
+						([]surf.SpotCreationCount)(nil),
+						valerra.NewErrors(management.ErrInvalidFrom, management.ErrInvalidTo, management.ErrInvalidInterval),
+					)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"latitude": 1.23,
-						"longitude": 3.21,
-						"locality": "Locality 1",
-						"country_code": "kz"
-					}`,
-				))
+				vals := url.Values{
+					"to":       []string{"2021-01-01T00:00:00Z"},
+					"interval": []string{"year"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
@@ -825,24 +6248,16 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "name",
-									"reason": "Must be a non empty string."
-								},
-								{
-									"key": "country_code",
-									"reason": "Must be a valid ISO-2 country code."
-								},
-								{
-									"key": "locality",
-									"reason": "Must be a non empty string."
+									"key": "from",
+									"reason": "Must be a valid RFC3339 time."
 								},
 								{
-									"key": "latitude",
-									"reason": "Must be a valid latitude."
+									"key": "to",
+									"reason": "Must be a valid RFC3339 time after from."
 								},
 								{
-									"key": "longitude",
-									"reason": "Must be a valid longitude."
+									"key": "interval",
+									"reason": "Must be one of: day, week, month."
 								}
 							]
 						}
@@ -856,31 +6271,19 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("CreateSpot", mock.Anything, management.CreateSpotParams{
-						Location: geo.Location{
-							Coordinates: geo.Coordinates{
-								Latitude:  1.23,
-								Longitude: 3.21,
-							},
-							Locality:    "Locality 1",
-							CountryCode: "kz",
-						},
-						Name: "Spot 1",
+					On("SpotsOverTime", mock.Anything, management.SpotsOverTimeParams{
+						From: from,
+						To:   to,
 					}).
-					Return(surf.Spot{}, errors.New("something went wrong"))
+					Return(([]surf.SpotCreationCount)(nil), errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21,
-						"locality": "Locality 1",
-						"country_code": "kz"
-					}`,
-				))
+				vals := url.Values{
+					"from": []string{"2021-01-01T00:00:00Z"},
+					"to":   []string{"2021-04-01T00:00:00Z"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
@@ -902,53 +6305,66 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 201 status code and spot body",
+			name: "respond with 200 status code and empty items when no spots were created",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("CreateSpot", mock.Anything, management.CreateSpotParams{
-						Location: geo.Location{
-							Coordinates: geo.Coordinates{
-								Latitude:  1.23,
-								Longitude: 3.21,
-							},
-							Locality:    "Locality 1",
-							CountryCode: "kz",
-						},
-						Name: "Spot 1",
+					On("SpotsOverTime", mock.Anything, management.SpotsOverTimeParams{
+						From: from,
+						To:   to,
 					}).
-					Return(
-						surf.Spot{
-							Location: geo.Location{
-								Coordinates: geo.Coordinates{
-									Latitude:  1.23,
-									Longitude: 3.21,
-								},
-								Locality:    "Locality 1",
-								CountryCode: "Country code 1",
-							},
-							ID:        "1",
-							Name:      "Spot 1",
-							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+					Return(([]surf.SpotCreationCount)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"from": []string{"2021-01-01T00:00:00Z"},
+					"to":   []string{"2021-04-01T00:00:00Z"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and series body",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotsOverTime", mock.Anything, management.SpotsOverTimeParams{
+						From:     from,
+						To:       to,
+						Interval: "month",
+					}).
+					Return([]surf.SpotCreationCount{
+						{
+							BucketStart: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+							Count:       4,
 						},
-						nil,
-					)
+						{
+							BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+							Count:       9,
+						},
+					}, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21,
-						"locality": "Locality 1",
-						"country_code": "kz"
-					}`,
-				))
+				vals := url.Values{
+					"from":     []string{"2021-01-01T00:00:00Z"},
+					"to":       []string{"2021-04-01T00:00:00Z"},
+					"interval": []string{"month"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusCreated, r.StatusCode)
+				assert.Equal(t, http.StatusOK, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -958,12 +6374,16 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 					t,
 					`{
 						"data": {
-							"id": "1",
-							"name": "Spot 1",
-							"latitude": 1.23,
-							"longitude": 3.21,
-							"locality": "Locality 1",
-							"country_code": "Country code 1"
+							"items": [
+								{
+									"bucket_start": "2021-01-01T00:00:00Z",
+									"count": 4
+								},
+								{
+									"bucket_start": "2021-03-01T00:00:00Z",
+									"count": 9
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -974,10 +6394,11 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodPost, server.URL+"/management/v1/spots", nil)
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/stats/spots-over-time", nil)
 			assert.NoError(t, err)
 
 			test.requestFn(req)
@@ -986,27 +6407,24 @@ func TestManagementHandler_CreateSpot(t *testing.T) {
 			assert.NoError(t, err)
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestManagementHandler_UpdateSpot(t *testing.T) {
+func TestManagementHandler_ReplaceReferenceSet(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
-		id                 string
-		requestFn          func(r *http.Request)
+		set                string
+		body               string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
 			name:    "respond with 400 status code and error body for invalid request body format",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			id:      "1",
-			requestFn: func(r *http.Request) {
-				// Omit request body
-			},
+			set:     "airports",
+			body:    `{"points": "Aktau"}`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1028,37 +6446,19 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for validation error",
+			name: "respond with 400 status code and error body for invalid params",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Latitude:  pconv.Float64(1.23),
-						Longitude: pconv.Float64(3.21),
-						Name:      pconv.String(""),
-						ID:        "1",
+					On("ReplaceReferenceSet", mock.Anything, management.ReplaceReferenceSetParams{
+						Set:    "Air Ports",
+						Points: []surf.ReferencePoint{},
 					}).
-					Return(surf.Spot{}, valerra.NewErrors(
-						management.ErrInvalidSpotID,
-						management.ErrInvalidSpotName,
-						management.ErrInvalidCountryCode,
-						management.ErrInvalidLocality,
-						management.ErrInvalidLatitude,
-						management.ErrInvalidLongitude,
-					))
+					Return(0, valerra.NewErrors(management.ErrInvalidReferenceSet, management.ErrInvalidReferencePoints))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"name": "",
-						"latitude": 1.23,
-						"longitude": 3.21
-					}`,
-				))
-			},
+			set:  "Air%20Ports",
+			body: `{"points": []}`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1074,28 +6474,12 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "spot_id",
-									"reason": "Must be a non empty string."
-								},
-								{
-									"key": "name",
-									"reason": "Must be a non empty string."
-								},
-								{
-									"key": "country_code",
-									"reason": "Must be a valid ISO-2 country code."
-								},
-								{
-									"key": "locality",
-									"reason": "Must be a non empty string."
-								},
-								{
-									"key": "latitude",
-									"reason": "Must be a valid latitude."
+									"key": "reference_set",
+									"reason": "Must contain up to 50 characters of a-z, 0-9, - and _."
 								},
 								{
-									"key": "longitude",
-									"reason": "Must be a valid longitude."
+									"key": "points",
+									"reason": "Must contain 1 to 1000 uniquely named valid points."
 								}
 							]
 						}
@@ -1105,23 +6489,21 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for empty input",
+			name: "respond with 500 status code and error body for unexpected error",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						ID: "1",
+					On("ReplaceReferenceSet", mock.Anything, management.ReplaceReferenceSetParams{
+						Set:    "airports",
+						Points: []surf.ReferencePoint{},
 					}).
-					Return(surf.Spot{}, surf.ErrEmptySpotUpdateEntry)
+					Return(0, errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(`{}`))
-			},
+			set:  "airports",
+			body: `{"points": []}`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1131,9 +6513,8 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Nothing to update.",
-							"fields": []
+							"code": "unexpected",
+							"description": "Something went wrong..."
 						}
 					}`,
 					string(body),
@@ -1141,30 +6522,121 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 404 status code and error body for unexisting spot",
+			name: "respond with 200 status code and backfilled count",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Latitude:  pconv.Float64(1.23),
-						Longitude: pconv.Float64(3.21),
-						Name:      pconv.String("Spot 1"),
-						ID:        "1",
+					On("ReplaceReferenceSet", mock.Anything, management.ReplaceReferenceSetParams{
+						Set: "airports",
+						Points: []surf.ReferencePoint{
+							{
+								Name: "Aktau",
+								Coordinates: geo.Coordinates{
+									Latitude:  43.86,
+									Longitude: 51.09,
+								},
+							},
+						},
 					}).
-					Return(surf.Spot{}, surf.ErrSpotNotFound)
+					Return(3, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
+			set:  "airports",
+			body: `{"points": [{"name": "Aktau", "latitude": 43.86, "longitude": 51.09}]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
 					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21
+						"data": {
+							"backfilled_count": 3
+						}
 					}`,
-				))
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPut,
+				server.URL+"/management/v1/reference-sets/"+test.set,
+				strings.NewReader(test.body),
+			)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_BackfillReferenceDistances(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		set                string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 400 status code and error body for invalid reference set",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("BackfillReferenceDistances", mock.Anything, "air.ports").
+					Return(0, valerra.NewErrors(management.ErrInvalidReferenceSet))
+				return m
+			}(),
+			set: "air.ports",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "reference_set",
+									"reason": "Must contain up to 50 characters of a-z, 0-9, - and _."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
 			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting reference set",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("BackfillReferenceDistances", mock.Anything, "airports").
+					Return(0, management.ErrReferenceSetNotFound)
+				return m
+			}(),
+			set: "airports",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
@@ -1177,7 +6649,7 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 					`{
 						"error": {
 							"code": "not_found",
-							"description": "Such spot doesn't exist."
+							"description": "Such reference set doesn't exist."
 						}
 					}`,
 					string(body),
@@ -1189,26 +6661,11 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Latitude:  pconv.Float64(1.23),
-						Longitude: pconv.Float64(3.21),
-						Name:      pconv.String("Spot 1"),
-						ID:        "1",
-					}).
-					Return(surf.Spot{}, errors.New("something went wrong"))
+					On("BackfillReferenceDistances", mock.Anything, "airports").
+					Return(0, errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21
-					}`,
-				))
-			},
+			set: "airports",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
@@ -1229,47 +6686,70 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and spot body for partial input",
+			name: "respond with 200 status code and backfilled count",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Name:      pconv.String("Spot 1"),
-						Latitude:  pconv.Float64(1.23),
-						Longitude: pconv.Float64(3.21),
-						ID:        "1",
-					}).
-					Return(
-						surf.Spot{
-							Location: geo.Location{
-								Coordinates: geo.Coordinates{
-									Latitude:  1.23,
-									Longitude: 3.21,
-								},
-								Locality:    "Locality 1",
-								CountryCode: "kz",
-							},
-							ID:        "1",
-							Name:      "Spot 1",
-							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-						},
-						nil,
-					)
+					On("BackfillReferenceDistances", mock.Anything, "airports").
+					Return(5, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
+			set: "airports",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
 					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21
+						"data": {
+							"backfilled_count": 5
+						}
 					}`,
-				))
+					string(body),
+				)
 			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPost,
+				server.URL+"/management/v1/reference-sets/"+test.set+"/backfill",
+				nil,
+			)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_SpotSuggestions(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid limit",
+			service: newMockManagementService(),
+			query:   "limit=abc",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1278,13 +6758,15 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"data": {
-							"id": "1",
-							"name": "Spot 1",
-							"latitude": 1.23,
-							"longitude": 3.21,
-							"locality": "Locality 1",
-							"country_code": "kz"
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "limit",
+									"reason": "Must be a valid integer."
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -1292,45 +6774,19 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and spot body for partial input",
+			name: "respond with 400 status code and error body for invalid status",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Locality:    pconv.String("Locality 1"),
-						CountryCode: pconv.String("kz"),
-						ID:          "1",
+					On("SpotSuggestions", mock.Anything, management.SpotSuggestionsParams{
+						Status: "approved",
 					}).
-					Return(
-						surf.Spot{
-							Location: geo.Location{
-								Coordinates: geo.Coordinates{
-									Latitude:  1.23,
-									Longitude: 3.21,
-								},
-								Locality:    "Locality 1",
-								CountryCode: "kz",
-							},
-							ID:        "1",
-							Name:      "Spot 1",
-							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-						},
-						nil,
-					)
+					Return(([]surf.SpotSuggestion)(nil), valerra.NewErrors(management.ErrInvalidSuggestionStatus))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"locality": "Locality 1",
-						"country_code": "kz"
-					}`,
-				))
-			},
+			query: "status=approved",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1339,13 +6795,15 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"data": {
-							"id": "1",
-							"name": "Spot 1",
-							"latitude": 1.23,
-							"longitude": 3.21,
-							"locality": "Locality 1",
-							"country_code": "kz"
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "status",
+									"reason": "Must be one of: pending, applied, rejected."
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -1353,51 +6811,52 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and spot body for full input",
+			name: "respond with 500 status code and error body for unexpected error",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("UpdateSpot", mock.Anything, management.UpdateSpotParams{
-						Name:        pconv.String("Spot 1"),
-						Latitude:    pconv.Float64(1.23),
-						Longitude:   pconv.Float64(3.21),
-						Locality:    pconv.String("Locality 1"),
-						CountryCode: pconv.String("kz"),
-						ID:          "1",
+					On("SpotSuggestions", mock.Anything, management.SpotSuggestionsParams{}).
+					Return(([]surf.SpotSuggestion)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and suggestions",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("SpotSuggestions", mock.Anything, management.SpotSuggestionsParams{
+						Limit:  2,
+						Offset: 4,
+						Status: "applied",
 					}).
-					Return(
-						surf.Spot{
-							Location: geo.Location{
-								Coordinates: geo.Coordinates{
-									Latitude:  1.23,
-									Longitude: 3.21,
-								},
-								Locality:    "Locality 1",
-								CountryCode: "kz",
+					Return([]surf.SpotSuggestion{
+						{
+							ID:     "11",
+							Status: surf.SpotSuggestionStatusApplied,
+							Change: surf.SpotUpdateEntry{
+								ID:          "1",
+								Name:        pconv.String("Spot 1"),
+								CountryCode: pconv.String("kz"),
+								Locality:    pconv.String(""),
 							},
-							ID:        "1",
-							Name:      "Spot 1",
-							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+							ResolvedAt: func() *time.Time {
+								t := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+								return &t
+							}(),
 						},
-						nil,
-					)
+					}, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
-			requestFn: func(r *http.Request) {
-				r.Body = ioutil.NopCloser(strings.NewReader(
-					`{
-						"name": "Spot 1",
-						"latitude": 1.23,
-						"longitude": 3.21,
-						"locality": "Locality 1",
-						"country_code": "kz"
-					}`,
-				))
-			},
+			query: "limit=2&offset=4&status=applied",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "2", r.Header.Get(headerLimit))
+				assert.Equal(t, "4", r.Header.Get(headerOffset))
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1407,12 +6866,21 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 					t,
 					`{
 						"data": {
-							"id": "1",
-							"name": "Spot 1",
-							"latitude": 1.23,
-							"longitude": 3.21,
-							"locality": "Locality 1",
-							"country_code": "kz"
+							"items": [
+								{
+									"id": "11",
+									"spot_id": "1",
+									"status": "applied",
+									"changes": {
+										"name": "Spot 1",
+										"country_code": "kz"
+									},
+									"cleared": ["locality"],
+									"note": "",
+									"created_at": "2021-01-01T00:00:00Z",
+									"resolved_at": "2021-01-02T00:00:00Z"
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -1423,43 +6891,57 @@ func TestManagementHandler_UpdateSpot(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodPatch, server.URL+"/management/v1/spots/"+test.id, nil)
-			assert.NoError(t, err)
-
-			test.requestFn(req)
-
-			resp, err := http.DefaultClient.Do(req)
-			assert.NoError(t, err)
+			resp, err := http.Get(server.URL + "/management/v1/suggestions?" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestManagementHandler_DeleteSpot(t *testing.T) {
+func TestManagementHandler_ResolveSpotSuggestion(t *testing.T) {
+	suggestion := func(status surf.SpotSuggestionStatus) surf.SpotSuggestion {
+		return surf.SpotSuggestion{
+			ID:     "11",
+			Status: status,
+			Change: surf.SpotUpdateEntry{
+				ID:       "1",
+				Latitude: pconv.Float64(1.23),
+			},
+			Note:      "Note 1",
+			CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			ResolvedAt: func() *time.Time {
+				t := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+				return &t
+			}(),
+		}
+	}
+
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
-		id                 string
+		action             string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
-			name: "respond with 500 status code and error body for unexpected error",
+			name: "respond with 400 status code and error body for invalid approved values",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("DeleteSpot", mock.Anything, "1").
-					Return(errors.New("something went wrong"))
+					On("ApproveSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, valerra.NewErrors(management.ErrLocationMismatch))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			action: "approve",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1469,8 +6951,43 @@ func TestManagementHandler_DeleteSpot(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "unexpected",
-							"description": "Something went wrong..."
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "country_code",
+									"reason": "Must match the country at the spot's coordinates."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting suggestion",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("RejectSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound)
+				return m
+			}(),
+			action: "reject",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such suggestion doesn't exist."
 						}
 					}`,
 					string(body),
@@ -1482,12 +6999,11 @@ func TestManagementHandler_DeleteSpot(t *testing.T) {
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("DeleteSpot", mock.Anything, "1").
-					Return(surf.ErrSpotNotFound)
+					On("ApproveSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, surf.ErrSpotNotFound)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			action: "approve",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
@@ -1508,18 +7024,17 @@ func TestManagementHandler_DeleteSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for invalid spot id",
+			name: "respond with 409 status code and error body for already approved suggestion",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("DeleteSpot", mock.Anything, "1").
-					Return(valerra.NewErrors(management.ErrInvalidSpotID))
+					On("ApproveSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			action: "approve",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusConflict, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1529,14 +7044,8 @@ func TestManagementHandler_DeleteSpot(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "spot_id",
-									"reason": "Must be a non empty string."
-								}
-							]
+							"code": "conflict",
+							"description": "Suggestion has already been resolved."
 						}
 					}`,
 					string(body),
@@ -1544,63 +7053,135 @@ func TestManagementHandler_DeleteSpot(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 204 status code",
+			name: "respond with 409 status code and error body for already rejected suggestion",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("DeleteSpot", mock.Anything, "1").
-					Return(nil)
+					On("RejectSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			action: "reject",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusNoContent, r.StatusCode)
+				assert.Equal(t, http.StatusConflict, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ApproveSpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, errors.New("something went wrong"))
+				return m
+			}(),
+			action: "approve",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and applied suggestion",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("ApproveSpotSuggestion", mock.Anything, "11").
+					Return(suggestion(surf.SpotSuggestionStatusApplied), nil)
+				return m
+			}(),
+			action: "approve",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
 				assert.NoError(t, err)
 
-				assert.Equal(t, "", string(body))
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "11",
+							"spot_id": "1",
+							"status": "applied",
+							"changes": {
+								"latitude": 1.23
+							},
+							"note": "Note 1",
+							"created_at": "2021-01-01T00:00:00Z",
+							"resolved_at": "2021-01-02T00:00:00Z"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and rejected suggestion",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("RejectSpotSuggestion", mock.Anything, "11").
+					Return(suggestion(surf.SpotSuggestionStatusRejected), nil)
+				return m
+			}(),
+			action: "reject",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "11",
+							"spot_id": "1",
+							"status": "rejected",
+							"changes": {
+								"latitude": 1.23
+							},
+							"note": "Note 1",
+							"created_at": "2021-01-01T00:00:00Z",
+							"resolved_at": "2021-01-02T00:00:00Z"
+						}
+					}`,
+					string(body),
+				)
 			},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodDelete, server.URL+"/management/v1/spots/"+test.id, nil)
-			assert.NoError(t, err)
-
-			resp, err := http.DefaultClient.Do(req)
-			assert.NoError(t, err)
+			resp, err := http.Post(server.URL+"/management/v1/suggestions/11/"+test.action, "application/json", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestManagementHandler_Location(t *testing.T) {
+func TestManagementHandler_AuditEntries(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            managementService
-		logger             *logrus.Logger
-		requestFn          func(r *http.Request)
+		query              string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
-			name:    "respond with 400 status code and error body for invalid latitude",
+			name:    "respond with 400 status code and error body for invalid offset",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"a"},
-					"lon": []string{"3.21"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			query:   "offset=abc",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1616,8 +7197,8 @@ func TestManagementHandler_Location(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "lat",
-									"reason": "Must be a valid latitude."
+									"key": "offset",
+									"reason": "Must be a valid integer."
 								}
 							]
 						}
@@ -1627,17 +7208,55 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for empty latitude",
-			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lon": []string{"3.21"},
-				}
-				r.URL.RawQuery = vals.Encode()
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("AuditEntries", mock.Anything, management.AuditEntriesParams{}).
+					Return(([]surf.AuditEntry)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 			},
+		},
+		{
+			name: "respond with 200 status code and audit entries",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("AuditEntries", mock.Anything, management.AuditEntriesParams{
+						Limit:  2,
+						Offset: 4,
+						SpotID: "1",
+					}).
+					Return([]surf.AuditEntry{
+						{
+							ID:      "22",
+							ActorID: "admin-1",
+							Action:  surf.AuditActionUpdate,
+							SpotID:  "1",
+							Changes: []surf.AuditChange{
+								{Field: "name", Before: "Spot 1", After: "Spot 2"},
+								{Field: "latitude", Before: 1.23, After: 2.34},
+							},
+							CreatedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							ID:        "21",
+							ActorID:   "admin-1",
+							Action:    surf.AuditActionDelete,
+							SpotID:    "1",
+							CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				return m
+			}(),
+			query: "limit=2&offset=4&spot_id=1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "2", r.Header.Get(headerLimit))
+				assert.Equal(t, "4", r.Header.Get(headerOffset))
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1646,13 +7265,26 @@ func TestManagementHandler_Location(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
+						"data": {
+							"items": [
 								{
-									"key": "lat",
-									"reason": "Must be a valid latitude."
+									"id": "22",
+									"actor_id": "admin-1",
+									"action": "update",
+									"spot_id": "1",
+									"changes": [
+										{"field": "name", "before": "Spot 1", "after": "Spot 2"},
+										{"field": "latitude", "before": 1.23, "after": 2.34}
+									],
+									"created_at": "2021-01-02T00:00:00Z"
+								},
+								{
+									"id": "21",
+									"actor_id": "admin-1",
+									"action": "delete",
+									"spot_id": "1",
+									"changes": [],
+									"created_at": "2021-01-01T00:00:00Z"
 								}
 							]
 						}
@@ -1662,16 +7294,9 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid longitude",
+			name:    "respond with 400 status code and error body for invalid from",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"1.23"},
-					"lon": []string{"a"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			query:   "from=yesterday",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1687,8 +7312,8 @@ func TestManagementHandler_Location(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "lon",
-									"reason": "Must be a valid longitude."
+									"key": "from",
+									"reason": "Must be a valid RFC3339 time."
 								}
 							]
 						}
@@ -1698,15 +7323,9 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for empty longitude",
+			name:    "respond with 400 status code and error body for invalid format",
 			service: newMockManagementService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"1.23"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			query:   "format=xml",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1722,8 +7341,8 @@ func TestManagementHandler_Location(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "lon",
-									"reason": "Must be a valid longitude."
+									"key": "format",
+									"reason": "Must be one of: json, csv."
 								}
 							]
 						}
@@ -1733,28 +7352,18 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for validation error",
+			name: "respond with 400 status code and error body for period ending before it starts",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Location", mock.Anything, geo.Coordinates{
-						Latitude:  -91,
-						Longitude: -181,
+					On("AuditEntries", mock.Anything, management.AuditEntriesParams{
+						From: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+						To:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
 					}).
-					Return(geo.Location{}, valerra.NewErrors(
-						management.ErrInvalidLatitude,
-						management.ErrInvalidLongitude,
-					))
+					Return(([]surf.AuditEntry)(nil), valerra.NewErrors(management.ErrInvalidTo))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"-91"},
-					"lon": []string{"-181"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			query: "from=2021-01-02T00:00:00Z&to=2021-01-01T00:00:00Z",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -1770,12 +7379,8 @@ func TestManagementHandler_Location(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "lat",
-									"reason": "Must be a valid latitude."
-								},
-								{
-									"key": "lon",
-									"reason": "Must be a valid longitude."
+									"key": "to",
+									"reason": "Must be a valid RFC3339 time after from."
 								}
 							]
 						}
@@ -1785,27 +7390,87 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 500 status code and error body for unexpected error",
+			name: "respond with 200 status code and audit entries of actor within period as csv",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Location", mock.Anything, geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
+					On("AuditEntries", mock.Anything, management.AuditEntriesParams{
+						ActorID: "admin-1",
+						From:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						To:      time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
 					}).
-					Return(geo.Location{}, errors.New("something went wrong"))
+					Return([]surf.AuditEntry{
+						{
+							ID:      "22",
+							ActorID: "admin-1",
+							Action:  surf.AuditActionUpdate,
+							SpotID:  "1",
+							Changes: []surf.AuditChange{
+								{Field: "name", Before: "Spot 1", After: "Spot 2"},
+							},
+							CreatedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							ID:        "21",
+							ActorID:   "admin-1",
+							Action:    surf.AuditActionRestore,
+							SpotID:    "1",
+							CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+					}, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"1.23"},
-					"lon": []string{"3.21"},
-				}
-				r.URL.RawQuery = vals.Encode()
+			query: "actor=admin-1&from=2021-01-01T00:00:00Z&to=2021-01-03T00:00:00Z&format=csv",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "text/csv; charset=utf-8", r.Header.Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="audit.csv"`, r.Header.Get("Content-Disposition"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.Equal(
+					t,
+					"id,actor_id,action,spot_id,changes,created_at\n"+
+						`22,admin-1,update,1,"[{""field"":""name"",""before"":""Spot 1"",""after"":""Spot 2""}]",2021-01-02T00:00:00Z`+"\n"+
+						"21,admin-1,restore,1,[],2021-01-01T00:00:00Z\n",
+					string(body),
+				)
 			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/management/v1/audit?" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestManagementHandler_Locations(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            managementService
+		body               string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockManagementService(),
+			body:    `{}`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1815,8 +7480,9 @@ func TestManagementHandler_Location(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "unexpected",
-							"description": "Something went wrong..."
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
 						}
 					}`,
 					string(body),
@@ -1824,27 +7490,17 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 404 status code and error body when location is not found",
+			name: "respond with 400 status code and error body for too many coordinates",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Location", mock.Anything, geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
-					}).
-					Return(geo.Location{}, geo.ErrLocationNotFound)
+					On("Locations", mock.Anything, []geo.Coordinates{}).
+					Return(([]management.LocationsEntryResult)(nil), valerra.NewErrors(management.ErrInvalidCoordinates))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"1.23"},
-					"lon": []string{"3.21"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			body: `[]`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -1854,8 +7510,14 @@ func TestManagementHandler_Location(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "not_found",
-							"description": "Location was not found."
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "coordinates",
+									"reason": "Must contain from 1 to 50 coordinates."
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -1863,35 +7525,135 @@ func TestManagementHandler_Location(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and location body",
+			name: "respond with 504 status code and error body for geocoder timeout",
 			service: func() managementService {
 				m := newMockManagementService()
 				m.
-					On("Location", mock.Anything, geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
+					On("Locations", mock.Anything, []geo.Coordinates{{Latitude: 1.23, Longitude: 3.21}}).
+					Return(([]management.LocationsEntryResult)(nil), management.ErrLocationTimeout)
+				return m
+			}(),
+			body: `[{"latitude": 1.23, "longitude": 3.21}]`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusGatewayTimeout, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Locations", mock.Anything, []geo.Coordinates{{Latitude: 1.23, Longitude: 3.21}}).
+					Return(([]management.LocationsEntryResult)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			body: `[{"latitude": 1.23, "longitude": 3.21}]`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 207 status code and items with errors",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Locations", mock.Anything, []geo.Coordinates{
+						{Latitude: 1.23, Longitude: 3.21},
+						{Latitude: 91, Longitude: 3.21},
+						{Latitude: 4.56, Longitude: 6.54},
 					}).
-					Return(
-						geo.Location{
-							Coordinates: geo.Coordinates{
-								Latitude:  1.23,
-								Longitude: 3.21,
+					Return([]management.LocationsEntryResult{
+						{
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{Latitude: 1.23, Longitude: 3.21},
 							},
-							Locality:    "Locality 1",
-							CountryCode: "kz",
 						},
-						nil,
-					)
+						{
+							Err: valerra.NewErrors(management.ErrInvalidLatitude),
+						},
+						{
+							Err: geo.ErrLocationNotFound,
+						},
+					}, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"lat": []string{"1.23"},
-					"lon": []string{"3.21"},
-				}
-				r.URL.RawQuery = vals.Encode()
+			body: `[
+				{"latitude": 1.23, "longitude": 3.21},
+				{"latitude": 91, "longitude": 3.21},
+				{"latitude": 4.56, "longitude": 6.54}
+			]`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusMultiStatus, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"index": 0,
+									"status": 200,
+									"location": {
+										"latitude": 1.23,
+										"longitude": 3.21,
+										"locality": "Locality 1",
+										"country_code": "kz",
+										"country_name": "Kazakhstan"
+									}
+								},
+								{
+									"index": 1,
+									"status": 400,
+									"error": {
+										"code": "invalid_input",
+										"description": "Invalid input parameters.",
+										"fields": [
+											{
+												"key": "latitude",
+												"reason": "Must be a valid latitude."
+											}
+										]
+									}
+								},
+								{
+									"index": 2,
+									"status": 404,
+									"error": {
+										"code": "not_found",
+										"description": "Location was not found."
+									}
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
 			},
+		},
+		{
+			name: "respond with 200 status code and locations",
+			service: func() managementService {
+				m := newMockManagementService()
+				m.
+					On("Locations", mock.Anything, []geo.Coordinates{{Latitude: 1.23, Longitude: 3.21}}).
+					Return([]management.LocationsEntryResult{
+						{
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{Latitude: 1.23, Longitude: 3.21},
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			body: `[{"latitude": 1.23, "longitude": 3.21}]`,
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusOK, r.StatusCode)
 
@@ -1903,10 +7665,19 @@ func TestManagementHandler_Location(t *testing.T) {
 					t,
 					`{
 						"data": {
-							"latitude": 1.23,
-							"longitude": 3.21,
-							"locality": "Locality 1",
-							"country_code": "kz"
+							"items": [
+								{
+									"index": 0,
+									"status": 200,
+									"location": {
+										"latitude": 1.23,
+										"longitude": 3.21,
+										"locality": "Locality 1",
+										"country_code": "kz",
+										"country_name": "Kazakhstan"
+									}
+								}
+							]
 						}
 					}`,
 					string(body),
@@ -1917,18 +7688,17 @@ func TestManagementHandler_Location(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, test.logger))
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), test.service, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/geo/location", nil)
-			assert.NoError(t, err)
-
-			test.requestFn(req)
-
-			resp, err := http.DefaultClient.Do(req)
-			assert.NoError(t, err)
+			resp, err := http.Post(server.URL+"/management/v1/geo/locations", "application/json", strings.NewReader(test.body))
+			if !assert.NoError(t, err) {
+				return
+			}
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
@@ -1,20 +1,47 @@
 package router
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
 	"github.com/ztimes2/tolqin/app/api/internal/api/service/surfing"
 	surfer "github.com/ztimes2/tolqin/app/api/internal/api/service/surfing"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
 	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
 type surfingService interface {
-	Spot(id string) (surf.Spot, error)
-	Spots(surfing.SpotsParams) ([]surf.Spot, error)
+	Spot(ctx context.Context, id string) (surf.Spot, error)
+	LocalizedSpot(context.Context, surfing.LocalizedSpotParams) (surfing.LocalizedSpot, error)
+	SpotWithNearby(context.Context, surfing.SpotWithNearbyParams) (surfing.SpotWithNearby, error)
+	Spots(context.Context, surfing.SpotsParams) ([]surf.Spot, error)
+	SpotMarkers(context.Context, surfing.SpotMarkersParams) ([]surf.SpotMarker, error)
+	RandomSpots(context.Context, surfing.RandomSpotsParams) ([]surf.Spot, error)
+	SpotsCentroid(context.Context, surfing.SpotsCentroidParams) (*geo.Coordinates, error)
+	NearbySpots(context.Context, surfing.NearbySpotsParams) ([]surf.NearbySpot, error)
+	SpotRoute(context.Context, surfing.SpotRouteParams) ([]surfing.RouteStop, error)
+	Countries(context.Context, surfing.CountriesParams) ([]surf.CountrySpotCount, error)
+	SpotDirectory(context.Context, surfing.SpotDirectoryParams) ([]surf.DirectoryCountry, error)
+	Localities(context.Context, surfing.LocalitiesParams) ([]string, error)
+	SpotPhotos(ctx context.Context, spotID string) ([]surfing.SpotPhoto, error)
+	SpotForecast(context.Context, surfing.SpotForecastParams) ([]forecast.Day, error)
+	SpotTides(context.Context, surfing.SpotTidesParams) ([]tide.Extreme, error)
+	RateSpot(context.Context, surfing.RateSpotParams) (surf.SpotRatings, error)
+	SuggestSpotChange(context.Context, surfing.SuggestSpotChangeParams) (surf.SpotSuggestion, error)
+	FavoriteSpot(ctx context.Context, spotID string) error
+	UnfavoriteSpot(ctx context.Context, spotID string) error
+	FavoriteSpots(context.Context, surfing.FavoriteSpotsParams) ([]surf.Spot, error)
+	FavoritedSpotIDs(ctx context.Context, spotIDs []string) (map[string]bool, error)
+	ApplyFavoriteBatch(context.Context, surfing.FavoriteBatchParams) (surfing.FavoriteBatchResult, error)
 }
 
 type surfingHandler struct {
@@ -30,20 +57,766 @@ func newSurfingHandler(s surfingService) *surfingHandler {
 func (h *surfingHandler) spot(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, paramKeySpotID)
 
-	spot, err := h.service.Spot(id)
+	if language := r.URL.Query().Get(paramKeyLocalityLanguage); language != "" {
+		h.localizedSpot(w, r, id, language)
+		return
+	}
+
+	nearby, err := includesNearby(r)
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField(paramKeyInclude, msgInvalidInclude))
+		return
+	}
+	if nearby {
+		h.spotWithNearby(w, r, id)
+		return
+	}
+
+	spot, err := h.service.Spot(r.Context(), id)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := toSpotResponse(spot)
+
+	marked, err := h.markFavorited(r, &resp)
+	if err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+	if marked {
+		// Favoriting a spot doesn't change its entity tag, so a response that tells
+		// whether the spot is favorited is tagged by its content instead.
+		writeOKWithETag(w, r, resp)
+		return
+	}
+
+	if writeNotModified(w, r, spotETag(spot)) {
+		return
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+// markFavorited tells, on every one of the given spot responses, whether the user
+// that the request is authenticated as has favorited the spot. The responses are
+// left untouched for anonymous requests. It reports whether they got marked.
+func (h *surfingHandler) markFavorited(r *http.Request, spots ...*spotResponse) (bool, error) {
+	if _, ok := jwt.FromContext(r.Context()); !ok {
+		return false, nil
+	}
+
+	ids := make([]string, len(spots))
+	for i, s := range spots {
+		ids[i] = s.ID
+	}
+
+	favorited, err := h.service.FavoritedSpotIDs(r.Context(), ids)
+	if err != nil {
+		return false, err
+	}
+
+	for _, s := range spots {
+		f := favorited[s.ID]
+		s.Favorited = &f
+	}
+
+	return true, nil
+}
+
+// writeUnauthenticatedError writes a 401 Unauthorized HTTP status code for
+// requests that must but do not carry a valid access token.
+func writeUnauthenticatedError(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteUnauthorizedError(w, r, "Valid access token is required.")
+}
+
+func (h *surfingHandler) favoriteSpot(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.FavoriteSpot(r.Context(), chi.URLParam(r, paramKeySpotID)); err != nil {
+		if errors.Is(err, jwt.ErrClaimsNotFound) {
+			writeUnauthenticatedError(w, r)
+			return
+		}
+
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteNoContent(w, r)
+}
+
+func (h *surfingHandler) unfavoriteSpot(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.UnfavoriteSpot(r.Context(), chi.URLParam(r, paramKeySpotID)); err != nil {
+		if errors.Is(err, jwt.ErrClaimsNotFound) {
+			writeUnauthenticatedError(w, r)
+			return
+		}
+
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteNoContent(w, r)
+}
+
+func (h *surfingHandler) favoriteSpots(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	offset, err := httputil.QueryParamInt(r, "offset")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("offset", "Must be a valid integer."))
+		return
+	}
+
+	params := surfer.FavoriteSpotsParams{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	spots, err := h.service.FavoriteSpots(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, jwt.ErrClaimsNotFound) {
+			writeUnauthenticatedError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := spotsResponse{
+		Items: make([]spotResponse, len(spots)),
+	}
+
+	favorited := true
+	for i, s := range spots {
+		resp.Items[i] = toSpotResponse(s)
+		resp.Items[i].Favorited = &favorited
+	}
+
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(spots))
+
+	httputil.WriteOK(w, r, resp)
+}
+
+type favoriteBatchPayload struct {
+	Favorite   []string `json:"favorite"`
+	Unfavorite []string `json:"unfavorite"`
+}
+
+// favoriteBatch favorites and unfavorites multiple spots at once. Spots to favorite
+// that don't exist are reported in the response instead of failing the request.
+func (h *surfingHandler) favoriteBatch(w http.ResponseWriter, r *http.Request) {
+	var payload favoriteBatchPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	res, err := h.service.ApplyFavoriteBatch(r.Context(), surfer.FavoriteBatchParams{
+		Favorite:   payload.Favorite,
+		Unfavorite: payload.Unfavorite,
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrClaimsNotFound) {
+			writeUnauthenticatedError(w, r)
+			return
+		}
+
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotIDs, httputil.NewInvalidField("favorite", "Must contain from 1 to 100 spot ids along with unfavorite."))
+				f.Is(e, surfer.ErrConflictingSpotIDs, httputil.NewInvalidField("unfavorite", "Must not contain spot ids present in favorite."))
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField("favorite", "Must contain only non empty strings."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, favoriteBatchResponse{
+		FavoritedIDs:   res.FavoritedIDs,
+		UnfavoritedIDs: res.UnfavoritedIDs,
+		NotFoundIDs:    res.NotFoundIDs,
+	})
+}
+
+func (h *surfingHandler) spotPhotos(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	photos, err := h.service.SpotPhotos(r.Context(), spotID)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surfer.ErrPhotosUnavailable) {
+			writePhotosUnavailableError(w, r)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	items := make([]spotPhotoResponse, len(photos))
+	for i, p := range photos {
+		items[i] = toSpotPhotoResponse(p.SpotPhoto, p.URL)
+	}
+
+	httputil.WriteOK(w, r, spotPhotosResponse{
+		Items: items,
+	})
+}
+
+func (h *surfingHandler) spotForecast(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	days, err := httputil.QueryParamInt(r, "days")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("days", "Must be a valid integer."))
+		return
+	}
+
+	forecastDays, err := h.service.SpotForecast(r.Context(), surfer.SpotForecastParams{
+		SpotID: spotID,
+		Days:   days,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidForecastDays, httputil.NewInvalidField("days", "Must be an integer from 1 to 7."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surfer.ErrForecastUnavailable) {
+			writeForecastUnavailableError(w, r, err)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	items := make([]forecastDayResponse, len(forecastDays))
+	for i, d := range forecastDays {
+		items[i] = toForecastDayResponse(d)
+	}
+
+	httputil.WriteOK(w, r, spotForecastResponse{
+		Items: items,
+	})
+}
+
+func (h *surfingHandler) spotTides(w http.ResponseWriter, r *http.Request) {
+	params := surfer.SpotTidesParams{
+		SpotID: chi.URLParam(r, paramKeySpotID),
+		Date:   httputil.QueryParam(r, "date"),
+	}
+
+	extremes, err := h.service.SpotTides(r.Context(), params)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidTideDate, httputil.NewInvalidField("date", "Must be a YYYY-MM-DD date within 7 days from today."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		if errors.Is(err, surfer.ErrTidesUnavailable) {
+			writeTidesUnavailableError(w, r, err)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	items := make([]tideExtremeResponse, len(extremes))
+	for i, e := range extremes {
+		items[i] = toTideExtremeResponse(e)
+	}
+
+	httputil.WriteOK(w, r, spotTidesResponse{
+		Items: items,
+	})
+}
+
+type rateSpotPayload struct {
+	Score int `json:"score"`
+}
+
+// rateSpot records a rating of a spot on behalf of the client. Clients are told
+// apart by their IP addresses, so that each of them counts once a day per spot.
+func (h *surfingHandler) rateSpot(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	var payload rateSpotPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	ratings, err := h.service.RateSpot(r.Context(), surfer.RateSpotParams{
+		SpotID:   spotID,
+		Score:    payload.Score,
+		ClientIP: remoteIP(r),
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidScore, httputil.NewInvalidField("score", "Must be an integer from 1 to 5."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toSpotRatingsResponse(ratings))
+}
+
+// suggestSpotChangePayload holds proposed values of a spot's fields in the same
+// way as updateSpotPayload does, along with an optional note explaining them.
+type suggestSpotChangePayload struct {
+	Name        *string  `json:"name"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Locality    *string  `json:"locality"`
+	CountryCode *string  `json:"country_code"`
+	Region      *string  `json:"region"`
+	Note        string   `json:"note"`
+}
+
+// suggestSpotChange records a change of a spot proposed by a surfer, which gets
+// applied only once an admin approves it. The proposed values are validated by the
+// same rules as updates of spots made by admins.
+func (h *surfingHandler) suggestSpotChange(w http.ResponseWriter, r *http.Request) {
+	spotID := chi.URLParam(r, paramKeySpotID)
+
+	var raw json.RawMessage
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	// The payload is decoded once again into raw fields, since pointers alone
+	// cannot tell absent fields apart from the ones explicitly set to null.
+	var (
+		payload suggestSpotChangePayload
+		fields  map[string]json.RawMessage
+	)
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		httputil.WritePayloadError(w, r)
+		return
+	}
+
+	suggestion, err := h.service.SuggestSpotChange(r.Context(), surfer.SuggestSpotChangeParams{
+		Change: management.UpdateSpotParams{
+			ID:               spotID,
+			Name:             payload.Name,
+			Latitude:         payload.Latitude,
+			Longitude:        payload.Longitude,
+			Locality:         payload.Locality,
+			CountryCode:      payload.CountryCode,
+			Region:           payload.Region,
+			ClearLocality:    isJSONNull(fields["locality"]),
+			ClearCountryCode: isJSONNull(fields["country_code"]),
+			ClearRegion:      isJSONNull(fields["region"]),
+		},
+		Note: payload.Note,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, management.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidSpotName, httputil.NewInvalidField("name", "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidCountryCode, httputil.NewInvalidField("country_code", "Must be a valid ISO-2 country code."))
+				f.Is(e, management.ErrInvalidLocality, httputil.NewInvalidField("locality", "Must be a non empty string."))
+				f.Is(e, management.ErrInvalidLatitude, httputil.NewInvalidField("latitude", "Must be a valid latitude."))
+				f.Is(e, management.ErrInvalidLongitude, httputil.NewInvalidField("longitude", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidSuggestionNote, httputil.NewInvalidField("note", "Must not exceed 500 characters."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surfer.ErrEmptySpotChange) {
+			httputil.WriteValidationError(w, r, "Nothing to update.")
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteCreated(w, r, toSpotSuggestionResponse(suggestion))
+}
+
+// spotWithNearby responds with a spot along with the spots nearest to it. No ETag
+// is set since nearby spots can change without the spot itself being updated.
+func (h *surfingHandler) spotWithNearby(w http.ResponseWriter, r *http.Request, id string) {
+	radius, err := httputil.QueryParamFloat(r, "radius_km")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("radius_km", "Must be a valid number."))
+		return
+	}
+
+	swn, err := h.service.SpotWithNearby(r.Context(), surfing.SpotWithNearbyParams{
+		SpotID:   id,
+		RadiusKm: radius,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidRadius, httputil.NewInvalidField("radius_km", "Must be a positive number."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := toSpotWithNearbyResponse(swn.Spot, swn.Nearby)
+
+	if _, err := h.markFavorited(r, &resp.spotResponse); err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *surfingHandler) localizedSpot(w http.ResponseWriter, r *http.Request, id, language string) {
+	ls, err := h.service.LocalizedSpot(r.Context(), surfing.LocalizedSpotParams{
+		SpotID:   id,
+		Language: language,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidLanguage, httputil.NewInvalidField(paramKeyLocalityLanguage, "Must be a valid language tag."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		if errors.Is(err, surfer.ErrLocalizationUnavailable) {
+			httputil.WriteFieldError(w, r, httputil.NewInvalidField(paramKeyLocalityLanguage, "Localization is unavailable."))
+			return
+		}
+
+		if errors.Is(err, surf.ErrSpotNotFound) {
+			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := localizedSpotResponse{
+		spotResponse:      toSpotResponse(ls.Spot),
+		LocalizedLocality: ls.LocalizedLocality,
+	}
+
+	if _, err := h.markFavorited(r, &resp.spotResponse); err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	writeOKWithETag(w, r, resp)
+}
+
+func (h *surfingHandler) spots(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	offset, err := httputil.QueryParamInt(r, "offset")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("offset", "Must be a valid integer."))
+		return
+	}
+
+	countryCode := httputil.QueryParam(r, "country")
+
+	query := httputil.QueryParam(r, "query")
+
+	bounds, vErr := parseBounds(
+		httputil.QueryParam(r, "ne_lat"),
+		httputil.QueryParam(r, "ne_lon"),
+		httputil.QueryParam(r, "sw_lat"),
+		httputil.QueryParam(r, "sw_lon"),
+	)
+	if vErr != nil {
+		f := httputil.NewInvalidFields()
+		for _, e := range vErr.Errors() {
+			f.Is(e, errInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+			f.Is(e, errInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+		}
+		httputil.WriteFieldErrors(w, r, f)
+		return
+	}
+
+	params := surfer.SpotsParams{
+		Limit:       limit,
+		Offset:      offset,
+		CountryCode: countryCode,
+		SearchQuery: query,
+		SearchMode:  httputil.QueryParam(r, "search_mode"),
+		Bounds:      bounds,
+		Tags:        httputil.QueryParamList(r, "tags"),
+		TagsMatch:   httputil.QueryParam(r, "tags_match"),
+	}
+
+	spots, err := h.service.Spots(r.Context(), params)
 	if err != nil {
 		var vErr *valerra.Errors
 		if errors.As(err, &vErr) {
 			f := httputil.NewInvalidFields()
 			for _, e := range vErr.Errors() {
-				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField(paramKeySpotID, "Must be a non empty string."))
+				f.Is(e, surfer.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+				f.Is(e, surfer.ErrInvalidSearchMode, httputil.NewInvalidField("search_mode", "Must be either substring or fulltext."))
+				f.Is(e, surfer.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+				f.Is(e, surfer.ErrInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("ne_lat", "Must not be less than sw_lat."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("sw_lat", "Must not be greater than ne_lat."))
+				f.Is(e, surfer.ErrInvalidTags, httputil.NewInvalidField("tags", "Must contain at most 10 tags of at most 30 characters."))
+				f.Is(e, surfer.ErrInvalidTagsMatch, httputil.NewInvalidField("tags_match", "Must be either all or any."))
 			}
 			httputil.WriteFieldErrors(w, r, f)
 			return
 		}
 
-		if errors.Is(err, surf.ErrSpotNotFound) {
-			httputil.WriteNotFoundError(w, r, "Such spot doesn't exist.")
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := spotsResponse{
+		Items: make([]spotResponse, len(spots)),
+	}
+
+	items := make([]*spotResponse, len(spots))
+	for i, s := range spots {
+		resp.Items[i] = toSpotResponse(s)
+		items[i] = &resp.Items[i]
+	}
+
+	if _, err := h.markFavorited(r, items...); err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(spots))
+
+	writeOKWithETag(w, r, resp)
+}
+
+func (h *surfingHandler) randomSpots(w http.ResponseWriter, r *http.Request) {
+	count, err := httputil.QueryParamInt(r, "count")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("count", "Must be a valid integer."))
+		return
+	}
+
+	spots, err := h.service.RandomSpots(r.Context(), surfer.RandomSpotsParams{
+		Count:       count,
+		CountryCode: httputil.QueryParam(r, "country"),
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := spotsResponse{
+		Items: make([]spotResponse, len(spots)),
+	}
+
+	for i, s := range spots {
+		resp.Items[i] = toSpotResponse(s)
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *surfingHandler) spotsCentroid(w http.ResponseWriter, r *http.Request) {
+	bounds, vErr := parseBounds(
+		httputil.QueryParam(r, "ne_lat"),
+		httputil.QueryParam(r, "ne_lon"),
+		httputil.QueryParam(r, "sw_lat"),
+		httputil.QueryParam(r, "sw_lon"),
+	)
+	if vErr != nil {
+		f := httputil.NewInvalidFields()
+		for _, e := range vErr.Errors() {
+			f.Is(e, errInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+			f.Is(e, errInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+			f.Is(e, errInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+		}
+		httputil.WriteFieldErrors(w, r, f)
+		return
+	}
+
+	centroid, err := h.service.SpotsCentroid(r.Context(), surfer.SpotsCentroidParams{
+		CountryCode: httputil.QueryParam(r, "country"),
+		SearchQuery: httputil.QueryParam(r, "query"),
+		Bounds:      bounds,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+				f.Is(e, surfer.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+				f.Is(e, surfer.ErrInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("ne_lat", "Must not be less than sw_lat."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("sw_lat", "Must not be greater than ne_lat."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
 			return
 		}
 
@@ -51,10 +824,32 @@ func (h *surfingHandler) spot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteOK(w, r, toSpotResponse(spot))
+	httputil.WriteOK(w, r, toSpotsCentroidResponse(centroid))
 }
 
-func (h *surfingHandler) spots(w http.ResponseWriter, r *http.Request) {
+func (h *surfingHandler) countries(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.service.Countries(r.Context(), surfer.CountriesParams{
+		SearchQuery: httputil.QueryParam(r, "query"),
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toCountriesResponse(counts))
+}
+
+func (h *surfingHandler) directory(w http.ResponseWriter, r *http.Request) {
 	limit, err := httputil.QueryParamInt(r, "limit")
 	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
 		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
@@ -67,10 +862,195 @@ func (h *surfingHandler) spots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	countryCode := httputil.QueryParam(r, "country")
+	localitiesLimit, err := httputil.QueryParamInt(r, "localities_limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("localities_limit", "Must be a valid integer."))
+		return
+	}
 
-	query := httputil.QueryParam(r, "query")
+	spotsLimit, err := httputil.QueryParamInt(r, "spots_limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("spots_limit", "Must be a valid integer."))
+		return
+	}
+
+	params := surfer.SpotDirectoryParams{
+		Limit:           limit,
+		Offset:          offset,
+		LocalitiesLimit: localitiesLimit,
+		SpotsLimit:      spotsLimit,
+	}
+
+	countries, err := h.service.SpotDirectory(r.Context(), params)
+	if err != nil {
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	limit, offset = params.Page()
+	writePaginationHeaders(w, r, limit, offset, len(countries))
+
+	writeOKWithETag(w, r, toDirectoryResponse(countries))
+}
+
+func (h *surfingHandler) localities(w http.ResponseWriter, r *http.Request) {
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	localities, err := h.service.Localities(r.Context(), surfer.LocalitiesParams{
+		Prefix:      httputil.QueryParam(r, "prefix"),
+		CountryCode: httputil.QueryParam(r, "country"),
+		Limit:       limit,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidLocalityPrefix, httputil.NewInvalidField("prefix", "Must contain at least 2 characters."))
+				f.Is(e, surfer.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	httputil.WriteOK(w, r, toLocalitiesResponse(localities))
+}
+
+func (h *surfingHandler) nearbySpots(w http.ResponseWriter, r *http.Request) {
+	lat, err := httputil.QueryParamFloat(r, "lat")
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+		return
+	}
+
+	lon, err := httputil.QueryParamFloat(r, "lon")
+	if err != nil {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+		return
+	}
+
+	radius, err := httputil.QueryParamFloat(r, "radius_km")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("radius_km", "Must be a valid number."))
+		return
+	}
+
+	limit, err := httputil.QueryParamInt(r, "limit")
+	if err != nil && !errors.Is(err, httputil.ErrParamNotFound) {
+		httputil.WriteFieldError(w, r, httputil.NewInvalidField("limit", "Must be a valid integer."))
+		return
+	}
+
+	spots, err := h.service.NearbySpots(r.Context(), surfer.NearbySpotsParams{
+		Coordinates: geo.Coordinates{
+			Latitude:  lat,
+			Longitude: lon,
+		},
+		RadiusKm: radius,
+		Limit:    limit,
+	})
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidLatitude, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidLongitude, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidRadius, httputil.NewInvalidField("radius_km", "Must be a positive number."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := nearbySpotsResponse{
+		Items: make([]nearbySpotResponse, len(spots)),
+	}
+
+	for i, s := range spots {
+		resp.Items[i] = toNearbySpotResponse(s)
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *surfingHandler) spotRoute(w http.ResponseWriter, r *http.Request) {
+	params := surfer.SpotRouteParams{
+		SpotIDs: httputil.QueryParamList(r, "ids"),
+	}
+
+	// The start point is optional, but requires both of its coordinates once any
+	// of them is given.
+	if httputil.QueryParam(r, "lat") != "" || httputil.QueryParam(r, "lon") != "" {
+		lat, err := httputil.QueryParamFloat(r, "lat")
+		if err != nil {
+			httputil.WriteFieldError(w, r, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+			return
+		}
+
+		lon, err := httputil.QueryParamFloat(r, "lon")
+		if err != nil {
+			httputil.WriteFieldError(w, r, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+			return
+		}
+
+		params.Start = &geo.Coordinates{
+			Latitude:  lat,
+			Longitude: lon,
+		}
+	}
+
+	stops, err := h.service.SpotRoute(r.Context(), params)
+	if err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			f := httputil.NewInvalidFields()
+			for _, e := range vErr.Errors() {
+				f.Is(e, surfer.ErrInvalidSpotIDs, httputil.NewInvalidField("ids", "Must contain from 1 to 25 spot ids."))
+				f.Is(e, surfer.ErrInvalidSpotID, httputil.NewInvalidField("ids", "Must contain only non empty strings."))
+				f.Is(e, surfer.ErrUnknownSpotID, httputil.NewInvalidField("ids", "Must contain only ids of existing spots."))
+				f.Is(e, surfer.ErrInvalidLatitude, httputil.NewInvalidField("lat", "Must be a valid latitude."))
+				f.Is(e, surfer.ErrInvalidLongitude, httputil.NewInvalidField("lon", "Must be a valid longitude."))
+			}
+			httputil.WriteFieldErrors(w, r, f)
+			return
+		}
+
+		httputil.WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	resp := spotRouteResponse{
+		Items: make([]routeStopResponse, len(stops)),
+	}
+
+	for i, s := range stops {
+		resp.Items[i] = routeStopResponse{
+			spotResponse:         toSpotResponse(s.Spot),
+			DistanceKm:           roundKm(s.DistanceKm),
+			CumulativeDistanceKm: roundKm(s.CumulativeDistanceKm),
+		}
+	}
 
+	if len(stops) > 0 {
+		resp.TotalDistanceKm = resp.Items[len(stops)-1].CumulativeDistanceKm
+	}
+
+	httputil.WriteOK(w, r, resp)
+}
+
+func (h *surfingHandler) spotMarkers(w http.ResponseWriter, r *http.Request) {
 	bounds, vErr := parseBounds(
 		httputil.QueryParam(r, "ne_lat"),
 		httputil.QueryParam(r, "ne_lon"),
@@ -89,24 +1069,20 @@ func (h *surfingHandler) spots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	spots, err := h.service.Spots(surfer.SpotsParams{
-		Limit:       limit,
-		Offset:      offset,
-		CountryCode: countryCode,
-		SearchQuery: query,
-		Bounds:      bounds,
+	markers, err := h.service.SpotMarkers(r.Context(), surfer.SpotMarkersParams{
+		Bounds: bounds,
 	})
 	if err != nil {
 		var vErr *valerra.Errors
 		if errors.As(err, &vErr) {
 			f := httputil.NewInvalidFields()
 			for _, e := range vErr.Errors() {
-				f.Is(e, surfer.ErrInvalidSearchQuery, httputil.NewInvalidField("query", "Must not exceed character limit."))
-				f.Is(e, surfer.ErrInvalidCountryCode, httputil.NewInvalidField("country", "Must be a valid ISO-2 country code."))
 				f.Is(e, surfer.ErrInvalidNorthEastLatitude, httputil.NewInvalidField("ne_lat", "Must be a valid latitude."))
 				f.Is(e, surfer.ErrInvalidNorthEastLongitude, httputil.NewInvalidField("ne_lon", "Must be a valid longitude."))
 				f.Is(e, surfer.ErrInvalidSouthWestLatitude, httputil.NewInvalidField("sw_lat", "Must be a valid latitude."))
 				f.Is(e, surfer.ErrInvalidSouthWestLongitude, httputil.NewInvalidField("sw_lon", "Must be a valid longitude."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("ne_lat", "Must not be less than sw_lat."))
+				f.Is(e, surfer.ErrInvalidBounds, httputil.NewInvalidField("sw_lat", "Must not be greater than ne_lat."))
 			}
 			httputil.WriteFieldErrors(w, r, f)
 			return
@@ -116,12 +1092,12 @@ func (h *surfingHandler) spots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := spotsResponse{
-		Items: make([]spotResponse, len(spots)),
+	resp := spotMarkersResponse{
+		Items: make([]spotMarkerResponse, len(markers)),
 	}
 
-	for i, s := range spots {
-		resp.Items[i] = toSpotResponse(s)
+	for i, m := range markers {
+		resp.Items[i] = toSpotMarkerResponse(m)
 	}
 
 	httputil.WriteOK(w, r, resp)
@@ -1,20 +1,29 @@
 package router
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
 	"github.com/ztimes2/tolqin/app/api/internal/api/service/surfing"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
+	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
@@ -26,21 +35,120 @@ func newMockSurfingService() *mockSurfingService {
 	return &mockSurfingService{}
 }
 
-func (m *mockSurfingService) Spot(id string) (surf.Spot, error) {
-	args := m.Called(id)
+func (m *mockSurfingService) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
-func (m *mockSurfingService) Spots(p surfing.SpotsParams) ([]surf.Spot, error) {
-	args := m.Called(p)
+func (m *mockSurfingService) LocalizedSpot(ctx context.Context, p surfing.LocalizedSpotParams) (surfing.LocalizedSpot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surfing.LocalizedSpot), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotWithNearby(ctx context.Context, p surfing.SpotWithNearbyParams) (surfing.SpotWithNearby, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surfing.SpotWithNearby), args.Error(1)
+}
+
+func (m *mockSurfingService) Spots(ctx context.Context, p surfing.SpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.Spot), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotMarkers(ctx context.Context, p surfing.SpotMarkersParams) ([]surf.SpotMarker, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotMarker), args.Error(1)
+}
+
+func (m *mockSurfingService) RandomSpots(ctx context.Context, p surfing.RandomSpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.Spot), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotsCentroid(ctx context.Context, p surfing.SpotsCentroidParams) (*geo.Coordinates, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(*geo.Coordinates), args.Error(1)
+}
+
+func (m *mockSurfingService) NearbySpots(ctx context.Context, p surfing.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.NearbySpot), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotRoute(ctx context.Context, p surfing.SpotRouteParams) ([]surfing.RouteStop, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surfing.RouteStop), args.Error(1)
+}
+
+func (m *mockSurfingService) Localities(ctx context.Context, p surfing.LocalitiesParams) ([]string, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotPhotos(ctx context.Context, spotID string) ([]surfing.SpotPhoto, error) {
+	args := m.Called(ctx, spotID)
+	return args.Get(0).([]surfing.SpotPhoto), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotForecast(ctx context.Context, p surfing.SpotForecastParams) ([]forecast.Day, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]forecast.Day), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotTides(ctx context.Context, p surfing.SpotTidesParams) ([]tide.Extreme, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]tide.Extreme), args.Error(1)
+}
+
+func (m *mockSurfingService) RateSpot(ctx context.Context, p surfing.RateSpotParams) (surf.SpotRatings, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surf.SpotRatings), args.Error(1)
+}
+
+func (m *mockSurfingService) SuggestSpotChange(ctx context.Context, p surfing.SuggestSpotChangeParams) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockSurfingService) FavoriteSpot(ctx context.Context, spotID string) error {
+	args := m.Called(ctx, spotID)
+	return args.Error(0)
+}
+
+func (m *mockSurfingService) UnfavoriteSpot(ctx context.Context, spotID string) error {
+	args := m.Called(ctx, spotID)
+	return args.Error(0)
+}
+
+func (m *mockSurfingService) FavoriteSpots(ctx context.Context, p surfing.FavoriteSpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
 	return args.Get(0).([]surf.Spot), args.Error(1)
 }
 
+func (m *mockSurfingService) ApplyFavoriteBatch(ctx context.Context, p surfing.FavoriteBatchParams) (surfing.FavoriteBatchResult, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surfing.FavoriteBatchResult), args.Error(1)
+}
+
+func (m *mockSurfingService) FavoritedSpotIDs(ctx context.Context, spotIDs []string) (map[string]bool, error) {
+	args := m.Called(ctx, spotIDs)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *mockSurfingService) SpotDirectory(ctx context.Context, p surfing.SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.DirectoryCountry), args.Error(1)
+}
+
+func (m *mockSurfingService) Countries(ctx context.Context, p surfing.CountriesParams) ([]surf.CountrySpotCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.CountrySpotCount), args.Error(1)
+}
+
 func TestSurfingHandler_Spot(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            surfingService
-		logger             *logrus.Logger
 		id                 string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
@@ -49,12 +157,11 @@ func TestSurfingHandler_Spot(t *testing.T) {
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(surf.Spot{}, errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
@@ -79,12 +186,11 @@ func TestSurfingHandler_Spot(t *testing.T) {
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(surf.Spot{}, surf.ErrSpotNotFound)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
@@ -109,12 +215,11 @@ func TestSurfingHandler_Spot(t *testing.T) {
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spot", "invalid").
+					On("Spot", mock.Anything, "invalid").
 					Return(surf.Spot{}, valerra.NewErrors(surfing.ErrInvalidSpotID))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "invalid",
+			id: "invalid",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -145,7 +250,7 @@ func TestSurfingHandler_Spot(t *testing.T) {
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(
 						surf.Spot{
 							Location: geo.Location{
@@ -164,8 +269,59 @@ func TestSurfingHandler_Spot(t *testing.T) {
 					)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
-			id:     "1",
+			id: "1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "Country code 1",
+							"country_name": ""
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body with ratings",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:   "1",
+							Name: "Spot 1",
+							Ratings: surf.SpotRatings{
+								Average: 13.0 / 3,
+								Count:   3,
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: "1",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusOK, r.StatusCode)
 
@@ -182,7 +338,10 @@ func TestSurfingHandler_Spot(t *testing.T) {
 							"latitude": 1.23,
 							"longitude": 3.21,
 							"locality": "Locality 1",
-							"country_code": "Country code 1"
+							"country_code": "kz",
+							"country_name": "Kazakhstan",
+							"average_rating": 4.33,
+							"ratings_count": 3
 						}
 					}`,
 					string(body),
@@ -193,7 +352,8 @@ func TestSurfingHandler_Spot(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, test.logger)) // TODO replace nil
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger)) // TODO replace nil
 			defer server.Close()
 
 			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/"+test.id, nil)
@@ -203,31 +363,32 @@ func TestSurfingHandler_Spot(t *testing.T) {
 			assert.NoError(t, err)
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
 
-func TestSurfingHandler_Spots(t *testing.T) {
+func TestSurfingHandler_LocalizedSpot(t *testing.T) {
 	tests := []struct {
 		name               string
 		service            surfingService
-		logger             *logrus.Logger
-		requestFn          func(r *http.Request)
+		id                 string
+		language           string
 		expectedResponseFn func(t *testing.T, r *http.Response)
 	}{
 		{
-			name:    "respond with 400 status code and error body for invalid limit",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"a"},
-					"offset": []string{"0"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("LocalizedSpot", mock.Anything, surfing.LocalizedSpotParams{SpotID: "1", Language: "ru"}).
+					Return(surfing.LocalizedSpot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			id:       "1",
+			language: "ru",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -237,14 +398,8 @@ func TestSurfingHandler_Spots(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "limit",
-									"reason": "Must be a valid integer."
-								}
-							]
+							"code": "unexpected",
+							"description": "Something went wrong..."
 						}
 					}`,
 					string(body),
@@ -252,18 +407,18 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid offset",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"a"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("LocalizedSpot", mock.Anything, surfing.LocalizedSpotParams{SpotID: "1", Language: "ru"}).
+					Return(surfing.LocalizedSpot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id:       "1",
+			language: "ru",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -273,14 +428,8 @@ func TestSurfingHandler_Spots(t *testing.T) {
 					t,
 					`{
 						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "offset",
-									"reason": "Must be a valid integer."
-								}
-							]
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
 						}
 					}`,
 					string(body),
@@ -288,20 +437,16 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid north-east latitude",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"a"},
-					"ne_lon": []string{"180"},
-					"sw_lat": []string{"-90"},
-					"sw_lon": []string{"-180"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 400 status code and error body for invalid language",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("LocalizedSpot", mock.Anything, surfing.LocalizedSpotParams{SpotID: "1", Language: "russian"}).
+					Return(surfing.LocalizedSpot{}, valerra.NewErrors(surfing.ErrInvalidLanguage))
+				return m
+			}(),
+			id:       "1",
+			language: "russian",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -317,8 +462,8 @@ func TestSurfingHandler_Spots(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "ne_lat",
-									"reason": "Must be a valid latitude."
+									"key": "locality_language",
+									"reason": "Must be a valid language tag."
 								}
 							]
 						}
@@ -328,20 +473,16 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid north-east longitude",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"90"},
-					"ne_lon": []string{"a"},
-					"sw_lat": []string{"-90"},
-					"sw_lon": []string{"-180"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 400 status code and error body for unavailable localization",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("LocalizedSpot", mock.Anything, surfing.LocalizedSpotParams{SpotID: "1", Language: "ru"}).
+					Return(surfing.LocalizedSpot{}, surfing.ErrLocalizationUnavailable)
+				return m
+			}(),
+			id:       "1",
+			language: "ru",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
 
@@ -357,8 +498,8 @@ func TestSurfingHandler_Spots(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "ne_lon",
-									"reason": "Must be a valid longitude."
+									"key": "locality_language",
+									"reason": "Localization is unavailable."
 								}
 							]
 						}
@@ -368,22 +509,37 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name:    "respond with 400 status code and error body for invalid south-west latitude",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"90"},
-					"ne_lon": []string{"180"},
-					"sw_lat": []string{"a"},
-					"sw_lon": []string{"-180"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
+			name: "respond with 200 status code and localized spot body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("LocalizedSpot", mock.Anything, surfing.LocalizedSpotParams{SpotID: "1", Language: "ru"}).
+					Return(
+						surfing.LocalizedSpot{
+							Spot: surf.Spot{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Aktau",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+							LocalizedLocality: "Актау",
+						},
+						nil,
+					)
+				return m
+			}(),
+			id:       "1",
+			language: "ru",
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.NotEmpty(t, r.Header.Get(headerETag))
 
 				body, err := ioutil.ReadAll(r.Body)
 				defer r.Body.Close()
@@ -392,53 +548,4267 @@ func TestSurfingHandler_Spots(t *testing.T) {
 				assert.JSONEq(
 					t,
 					`{
-						"error": {
-							"code": "invalid_input",
-							"description": "Invalid input parameters.",
-							"fields": [
-								{
-									"key": "sw_lat",
-									"reason": "Must be a valid latitude."
-								}
-							]
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Aktau",
+							"localized_locality": "Актау",
+							"country_code": "kz",
+							"country_name": "Kazakhstan"
 						}
 					}`,
 					string(body),
 				)
 			},
 		},
-		{
-			name:    "respond with 400 status code and error body for invalid south-west longitude",
-			service: newMockSurfingService(),
-			logger:  nil, // FIXME catch error logs
-			requestFn: func(r *http.Request) {
-				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
-					"ne_lat": []string{"90"},
-					"ne_lon": []string{"180"},
-					"sw_lat": []string{"-90"},
-					"sw_lon": []string{"a"},
-				}
-				r.URL.RawQuery = vals.Encode()
-			},
-			expectedResponseFn: func(t *testing.T, r *http.Response) {
-				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+	}
 
-				body, err := ioutil.ReadAll(r.Body)
-				defer r.Body.Close()
-				assert.NoError(t, err)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
 
-				assert.JSONEq(
-					t,
-					`{
-						"error": {
+			req, err := http.NewRequest(
+				http.MethodGet,
+				server.URL+"/surfing/v1/spots/"+test.id+"?locality_language="+test.language,
+				nil,
+			)
+			assert.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotWithNearby(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid include",
+			service: newMockSurfingService(),
+			query:   "include=nearby,tags",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "include",
+									"reason": "Must be one of: nearby."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid radius format",
+			service: newMockSurfingService(),
+			query:   "include=nearby&radius_km=a",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "radius_km",
+									"reason": "Must be a valid number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotWithNearby", mock.Anything, surfing.SpotWithNearbyParams{
+						SpotID:   "1",
+						RadiusKm: -1,
+					}).
+					Return(surfing.SpotWithNearby{}, valerra.NewErrors(surfing.ErrInvalidRadius))
+				return m
+			}(),
+			query: "include=nearby&radius_km=-1",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "radius_km",
+									"reason": "Must be a positive number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotWithNearby", mock.Anything, surfing.SpotWithNearbyParams{
+						SpotID: "1",
+					}).
+					Return(surfing.SpotWithNearby{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			query: "include=nearby",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot body with nearby spots",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotWithNearby", mock.Anything, surfing.SpotWithNearbyParams{
+						SpotID:   "1",
+						RadiusKm: 10,
+					}).
+					Return(surfing.SpotWithNearby{
+						Spot: surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:   "1",
+							Name: "Spot 1",
+						},
+						Nearby: []surf.NearbySpot{
+							{
+								Spot: surf.Spot{
+									Location: geo.Location{
+										Coordinates: geo.Coordinates{
+											Latitude:  1.24,
+											Longitude: 3.22,
+										},
+										Locality:    "Locality 1",
+										CountryCode: "kz",
+									},
+									ID:   "2",
+									Name: "Spot 2",
+								},
+								DistanceKm: 1.5678,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			query: "include=nearby&radius_km=10",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Empty(t, r.Header.Get("ETag"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "1",
+							"name": "Spot 1",
+							"latitude": 1.23,
+							"longitude": 3.21,
+							"locality": "Locality 1",
+							"country_code": "kz",
+							"country_name": "Kazakhstan",
+							"nearby": [
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.24,
+									"longitude": 3.22,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 1.57
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/surfing/v1/spots/1?" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotForecast(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid days",
+			service: newMockSurfingService(),
+			query:   "?days=abc",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "days",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotForecast", mock.Anything, surfing.SpotForecastParams{
+						SpotID: "1",
+						Days:   8,
+					}).
+					Return([]forecast.Day(nil), valerra.NewErrors(surfing.ErrInvalidForecastDays))
+				return m
+			}(),
+			query: "?days=8",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "days",
+									"reason": "Must be an integer from 1 to 7."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotForecast", mock.Anything, surfing.SpotForecastParams{
+						SpotID: "1",
+					}).
+					Return([]forecast.Day(nil), surf.ErrSpotNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 503 status code and error body for unavailable forecast",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotForecast", mock.Anything, surfing.SpotForecastParams{
+						SpotID: "1",
+						Days:   3,
+					}).
+					Return([]forecast.Day(nil), fmt.Errorf("%w: upstream timed out", surfing.ErrForecastUnavailable))
+				return m
+			}(),
+			query: "?days=3",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "forecast_unavailable",
+							"description": "Forecast is unavailable."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotForecast", mock.Anything, surfing.SpotForecastParams{
+						SpotID: "1",
+					}).
+					Return([]forecast.Day(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and forecast",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotForecast", mock.Anything, surfing.SpotForecastParams{
+						SpotID: "1",
+						Days:   2,
+					}).
+					Return([]forecast.Day{
+						{
+							Date:          time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+							WaveHeightMax: pconv.Float64(1.5),
+							WavePeriodMax: pconv.Float64(9.8),
+							WaveDirection: pconv.Float64(270),
+							WindSpeedMax:  pconv.Float64(20.5),
+							WindGustsMax:  pconv.Float64(35.1),
+							WindDirection: pconv.Float64(90),
+						},
+						{
+							Date:          time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+							WindSpeedMax:  pconv.Float64(12),
+							WindDirection: pconv.Float64(45),
+						},
+					}, nil)
+				return m
+			}(),
+			query: "?days=2",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				golden, err := ioutil.ReadFile("testdata/spot_forecast.json")
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.JSONEq(t, string(golden), string(body))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/surfing/v1/spots/1/forecast" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotTides(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotTides", mock.Anything, surfing.SpotTidesParams{
+						SpotID: "1",
+						Date:   "2021-13-01",
+					}).
+					Return([]tide.Extreme(nil), valerra.NewErrors(surfing.ErrInvalidTideDate))
+				return m
+			}(),
+			query: "?date=2021-13-01",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "date",
+									"reason": "Must be a YYYY-MM-DD date within 7 days from today."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotTides", mock.Anything, surfing.SpotTidesParams{
+						SpotID: "1",
+					}).
+					Return([]tide.Extreme(nil), surf.ErrSpotNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 503 status code and error body for unavailable tides",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotTides", mock.Anything, surfing.SpotTidesParams{
+						SpotID: "1",
+					}).
+					Return([]tide.Extreme(nil), fmt.Errorf("%w: upstream timed out", surfing.ErrTidesUnavailable))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "tides_unavailable",
+							"description": "Tides are unavailable."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotTides", mock.Anything, surfing.SpotTidesParams{
+						SpotID: "1",
+					}).
+					Return([]tide.Extreme(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and tides",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotTides", mock.Anything, surfing.SpotTidesParams{
+						SpotID: "1",
+						Date:   "2021-01-01",
+					}).
+					Return([]tide.Extreme{
+						{
+							Time:   time.Date(2021, 1, 1, 0, 39, 9, 0, time.UTC),
+							Type:   tide.TypeHigh,
+							Height: pconv.Float64(0.913),
+						},
+						{
+							Time: time.Date(2021, 1, 1, 6, 44, 33, 0, time.UTC),
+							Type: tide.TypeLow,
+						},
+					}, nil)
+				return m
+			}(),
+			query: "?date=2021-01-01",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"time": "2021-01-01T00:39:09Z",
+									"type": "high",
+									"height_m": 0.913
+								},
+								{
+									"time": "2021-01-01T06:44:33Z",
+									"type": "low",
+									"height_m": null
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/surfing/v1/spots/1/tides" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotPhotos(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surfing.SpotPhoto(nil), surf.ErrSpotNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 503 status code and error body for unavailable photos",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surfing.SpotPhoto(nil), surfing.ErrPhotosUnavailable)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "photos_unavailable",
+							"description": "Photos are unavailable."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surfing.SpotPhoto(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty photos",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surfing.SpotPhoto{}, nil)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and photos",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surfing.SpotPhoto{
+						{
+							SpotPhoto: surf.SpotPhoto{
+								ID:          "2",
+								SpotID:      "1",
+								ContentType: "image/jpeg",
+								Size:        10,
+								CreatedAt:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+							},
+							URL: "/media/spots/1/2.jpg",
+						},
+					}, nil)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "2",
+									"url": "/media/spots/1/2.jpg",
+									"content_type": "image/jpeg",
+									"size": 10,
+									"created_at": "2021-01-01T00:00:00Z"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/surfing/v1/spots/1/photos")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_RateSpot(t *testing.T) {
+	params := surfing.RateSpotParams{SpotID: "1", Score: 4, ClientIP: "127.0.0.1"}
+
+	tests := []struct {
+		name               string
+		service            surfingService
+		body               string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for malformed payload",
+			service: newMockSurfingService(),
+			body:    `{"score": "four"}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid score",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RateSpot", mock.Anything, surfing.RateSpotParams{SpotID: "1", Score: 6, ClientIP: "127.0.0.1"}).
+					Return(surf.SpotRatings{}, valerra.NewErrors(surfing.ErrInvalidScore))
+				return m
+			}(),
+			body: `{"score": 6}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "score",
+									"reason": "Must be an integer from 1 to 5."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RateSpot", mock.Anything, params).
+					Return(surf.SpotRatings{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			body: `{"score": 4}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RateSpot", mock.Anything, params).
+					Return(surf.SpotRatings{}, errors.New("something went wrong"))
+				return m
+			}(),
+			body: `{"score": 4}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 200 status code and ratings",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RateSpot", mock.Anything, params).
+					Return(surf.SpotRatings{Average: 4.666666, Count: 3}, nil)
+				return m
+			}(),
+			body: `{"score": 4}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"average_rating": 4.67,
+							"ratings_count": 3
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/surfing/v1/spots/1/ratings", "application/json", strings.NewReader(test.body))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SuggestSpotChange(t *testing.T) {
+	params := surfing.SuggestSpotChangeParams{
+		Change: management.UpdateSpotParams{
+			ID:          "1",
+			Latitude:    pconv.Float64(1.23),
+			Longitude:   pconv.Float64(3.21),
+			ClearRegion: true,
+		},
+		Note: "Coordinates point at the parking lot",
+	}
+	body := `{"latitude": 1.23, "longitude": 3.21, "region": null, "note": "Coordinates point at the parking lot"}`
+
+	tests := []struct {
+		name               string
+		service            surfingService
+		body               string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for malformed payload",
+			service: newMockSurfingService(),
+			body:    `{"latitude": "north"}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid values",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SuggestSpotChange", mock.Anything, surfing.SuggestSpotChangeParams{
+						Change: management.UpdateSpotParams{
+							ID:          "1",
+							Latitude:    pconv.Float64(91),
+							CountryCode: pconv.String("zz"),
+						},
+						Note: strings.Repeat("a", 501),
+					}).
+					Return(surf.SpotSuggestion{}, valerra.NewErrors(
+						management.ErrInvalidLatitude,
+						management.ErrInvalidCountryCode,
+						surfing.ErrInvalidSuggestionNote,
+					))
+				return m
+			}(),
+			body: `{"latitude": 91, "country_code": "zz", "note": "` + strings.Repeat("a", 501) + `"}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "latitude",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "country_code",
+									"reason": "Must be a valid ISO-2 country code."
+								},
+								{
+									"key": "note",
+									"reason": "Must not exceed 500 characters."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for empty change",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SuggestSpotChange", mock.Anything, surfing.SuggestSpotChangeParams{
+						Change: management.UpdateSpotParams{ID: "1"},
+						Note:   "Note 1",
+					}).
+					Return(surf.SpotSuggestion{}, surfing.ErrEmptySpotChange)
+				return m
+			}(),
+			body: `{"note": "Note 1"}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Nothing to update.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SuggestSpotChange", mock.Anything, params).
+					Return(surf.SpotSuggestion{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			body: body,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body during service failure",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SuggestSpotChange", mock.Anything, params).
+					Return(surf.SpotSuggestion{}, errors.New("something went wrong"))
+				return m
+			}(),
+			body: body,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 201 status code and pending suggestion",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SuggestSpotChange", mock.Anything, params).
+					Return(surf.SpotSuggestion{
+						ID:     "11",
+						Status: surf.SpotSuggestionStatusPending,
+						Change: surf.SpotUpdateEntry{
+							ID:        "1",
+							Latitude:  pconv.Float64(1.23),
+							Longitude: pconv.Float64(3.21),
+							Region:    pconv.String(""),
+						},
+						Note:      "Coordinates point at the parking lot",
+						CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					}, nil)
+				return m
+			}(),
+			body: body,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"id": "11",
+							"spot_id": "1",
+							"status": "pending",
+							"changes": {
+								"latitude": 1.23,
+								"longitude": 3.21
+							},
+							"cleared": ["region"],
+							"note": "Coordinates point at the parking lot",
+							"created_at": "2021-01-01T00:00:00Z"
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/surfing/v1/spots/1/suggestions", "application/json", strings.NewReader(test.body))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_FavoriteSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 401 status code and error body for unauthenticated request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpot", mock.Anything, "1").
+					Return(jwt.ErrClaimsNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusUnauthorized, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unauthorized",
+							"description": "Valid access token is required."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 404 status code and error body for unexisting spot",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpot", mock.Anything, "1").
+					Return(surf.ErrSpotNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNotFound, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "not_found",
+							"description": "Such spot doesn't exist."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpot", mock.Anything, "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 204 status code",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusNoContent, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+				assert.Empty(t, body)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPut, server.URL+"/surfing/v1/spots/1/favorite", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_UnfavoriteSpot(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		expectedStatusCode int
+	}{
+		{
+			name: "respond with 401 status code for unauthenticated request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("UnfavoriteSpot", mock.Anything, "1").
+					Return(jwt.ErrClaimsNotFound)
+				return m
+			}(),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "respond with 500 status code for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("UnfavoriteSpot", mock.Anything, "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "respond with 204 status code",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("UnfavoriteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodDelete, server.URL+"/surfing/v1/spots/1/favorite", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_FavoriteSpots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		query              string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid offset",
+			service: newMockSurfingService(),
+			query:   "offset=a",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "offset",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 401 status code and error body for unauthenticated request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpots", mock.Anything, surfing.FavoriteSpotsParams{}).
+					Return(([]surf.Spot)(nil), jwt.ErrClaimsNotFound)
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusUnauthorized, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unauthorized",
+							"description": "Valid access token is required."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpots", mock.Anything, surfing.FavoriteSpotsParams{}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and favorited spots",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("FavoriteSpots", mock.Anything, surfing.FavoriteSpotsParams{Limit: 1, Offset: 2}).
+					Return(
+						[]surf.Spot{
+							{
+								ID:   "1",
+								Name: "Spot 1",
+								Location: geo.Location{
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+									Coordinates: geo.Coordinates{Latitude: 1.23, Longitude: 3.21},
+								},
+								UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			query: "limit=1&offset=2",
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"updated_at": "2021-01-01T00:00:00Z",
+									"favorited": true
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/surfing/v1/favorites?" + test.query)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_FavoriteBatch(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		body               string
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid request body format",
+			service: newMockSurfingService(),
+			body:    `["1"]`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid payload.",
+							"fields": []
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 401 status code for unauthenticated request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surfing.FavoriteBatchParams{
+						Favorite: []string{"1"},
+					}).
+					Return(surfing.FavoriteBatchResult{}, jwt.ErrClaimsNotFound)
+				return m
+			}(),
+			body: `{"favorite": ["1"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surfing.FavoriteBatchParams{
+						Favorite:   []string{"1", ""},
+						Unfavorite: []string{"1"},
+					}).
+					Return(surfing.FavoriteBatchResult{}, valerra.NewErrors(
+						surfing.ErrConflictingSpotIDs,
+						surfing.ErrInvalidSpotID,
+					))
+				return m
+			}(),
+			body: `{"favorite": ["1", ""], "unfavorite": ["1"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "unfavorite",
+									"reason": "Must not contain spot ids present in favorite."
+								},
+								{
+									"key": "favorite",
+									"reason": "Must contain only non empty strings."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surfing.FavoriteBatchParams{
+						Favorite: []string{"1"},
+					}).
+					Return(surfing.FavoriteBatchResult{}, errors.New("something went wrong"))
+				return m
+			}(),
+			body: `{"favorite": ["1"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and batch result body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surfing.FavoriteBatchParams{
+						Favorite:   []string{"1", "2"},
+						Unfavorite: []string{"3"},
+					}).
+					Return(surfing.FavoriteBatchResult{
+						FavoritedIDs:   []string{"1"},
+						UnfavoritedIDs: []string{"3"},
+						NotFoundIDs:    []string{"2"},
+					}, nil)
+				return m
+			}(),
+			body: `{"favorite": ["1", "2"], "unfavorite": ["3"]}`,
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"favorited_ids": ["1"],
+							"unfavorited_ids": ["3"],
+							"not_found_ids": ["2"]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPost,
+				server.URL+"/surfing/v1/favorites/batch",
+				strings.NewReader(test.body),
+			)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_Favorited(t *testing.T) {
+	spot := surf.Spot{
+		ID:   "1",
+		Name: "Spot 1",
+		Location: geo.Location{
+			Locality:    "Locality 1",
+			CountryCode: "kz",
+			Coordinates: geo.Coordinates{Latitude: 1.23, Longitude: 3.21},
+		},
+		UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	j := jwt.NewEncodeDecoder("secret", time.Hour)
+	token, err := j.EncodeJWT(auth.User{ID: "user", Role: auth.RoleAdmin})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name              string
+		service           surfingService
+		path              string
+		authenticated     bool
+		expectedStatus    int
+		expectedFavorited []interface{}
+	}{
+		{
+			name: "leave spot unmarked for anonymous request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			path:              "/surfing/v1/spots/1",
+			expectedStatus:    http.StatusOK,
+			expectedFavorited: []interface{}{nil},
+		},
+		{
+			name: "mark spot for authenticated request",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("FavoritedSpotIDs", mock.Anything, []string{"1"}).
+					Return(map[string]bool{"1": true}, nil)
+				return m
+			}(),
+			path:              "/surfing/v1/spots/1",
+			authenticated:     true,
+			expectedStatus:    http.StatusOK,
+			expectedFavorited: []interface{}{true},
+		},
+		{
+			name: "respond with 500 status code once favorites fail to be read",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("FavoritedSpotIDs", mock.Anything, []string{"1"}).
+					Return((map[string]bool)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			path:           "/surfing/v1/spots/1",
+			authenticated:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "mark spots for authenticated request",
+			service: func() surfingService {
+				other := spot
+				other.ID = "2"
+
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{}).
+					Return([]surf.Spot{spot, other}, nil)
+				m.
+					On("FavoritedSpotIDs", mock.Anything, []string{"1", "2"}).
+					Return(map[string]bool{"2": true}, nil)
+				return m
+			}(),
+			path:              "/surfing/v1/spots",
+			authenticated:     true,
+			expectedStatus:    http.StatusOK,
+			expectedFavorited: []interface{}{false, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, j, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if test.authenticated {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assertErrorLogs(t, resp, hook)
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body)) {
+				return
+			}
+
+			var items []map[string]interface{}
+			if strings.HasSuffix(test.path, "/spots") {
+				var list struct {
+					Items []map[string]interface{} `json:"items"`
+				}
+				assert.NoError(t, json.Unmarshal(body.Data, &list))
+				items = list.Items
+			} else {
+				var item map[string]interface{}
+				assert.NoError(t, json.Unmarshal(body.Data, &item))
+				items = []map[string]interface{}{item}
+			}
+
+			favorited := make([]interface{}, len(items))
+			for i, item := range items {
+				favorited[i] = item["favorited"]
+			}
+			assert.Equal(t, test.expectedFavorited, favorited)
+		})
+	}
+}
+
+func TestSurfingHandler_Spots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid limit",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"a"},
+					"offset": []string{"0"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "limit",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid offset",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "offset",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid north-east latitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"a"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid north-east longitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"90"},
+					"ne_lon": []string{"a"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid south-west latitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"90"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"a"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "sw_lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid south-west longitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+					"ne_lat": []string{"90"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "sw_lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "zz",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(
+						surfing.ErrInvalidSearchQuery,
+						surfing.ErrInvalidCountryCode,
+						surfing.ErrInvalidNorthEastLatitude,
+						surfing.ErrInvalidNorthEastLongitude,
+						surfing.ErrInvalidSouthWestLatitude,
+						surfing.ErrInvalidSouthWestLongitude,
+						surfing.ErrInvalidBounds,
+					))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":   []string{"10"},
+					"offset":  []string{"0"},
+					"country": []string{"zz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "query",
+									"reason": "Must not exceed character limit."
+								},
+								{
+									"key": "country",
+									"reason": "Must be a valid ISO-2 country code."
+								},
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "ne_lon",
+									"reason": "Must be a valid longitude."
+								},
+								{
+									"key": "sw_lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "sw_lon",
+									"reason": "Must be a valid longitude."
+								},
+								{
+									"key": "ne_lat",
+									"reason": "Must not be less than sw_lat."
+								},
+								{
+									"key": "sw_lat",
+									"reason": "Must not be greater than ne_lat."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for expected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":  []string{"10"},
+					"offset": []string{"0"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty spot list body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Limit:  0,
+						Offset: 0,
+					}).
+					Return(([]surf.Spot)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				// Omit query parameters
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items":[]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spot list body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "kz",
+						SearchQuery: "query",
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:        "2",
+								Name:      "Spot 2",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":   []string{"10"},
+					"offset":  []string{"0"},
+					"country": []string{"kz"},
+					"query":   []string{"query"},
+					"ne_lat":  []string{"90"},
+					"ne_lon":  []string{"180"},
+					"sw_lat":  []string{"-90"},
+					"sw_lon":  []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								},
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 2",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid tags",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Tags:      []string{"reef", "point"},
+						TagsMatch: "some",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(surfing.ErrInvalidTags, surfing.ErrInvalidTagsMatch))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"tags":       []string{"reef,point"},
+					"tags_match": []string{"some"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "tags",
+									"reason": "Must contain at most 10 tags of at most 30 characters."
+								},
+								{
+									"key": "tags_match",
+									"reason": "Must be either all or any."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid search mode",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						SearchQuery: "kazakh beach",
+						SearchMode:  "regex",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(surfing.ErrInvalidSearchMode))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query":       []string{"kazakh beach"},
+					"search_mode": []string{"regex"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "search_mode",
+									"reason": "Must be either substring or fulltext."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and spots found using full-text search",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						SearchQuery: "kazakh beach",
+						SearchMode:  "fulltext",
+					}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query":       []string{"kazakh beach"},
+					"search_mode": []string{"fulltext"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and spots filtered by tags",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Spots", mock.Anything, surfing.SpotsParams{
+						Tags:      []string{"reef", "point"},
+						TagsMatch: "any",
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Name: "Spot 1",
+							Tags: []string{"point", "reef"},
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"tags":       []string{"reef,point"},
+					"tags_match": []string{"any"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"tags": ["point", "reef"]
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger)) // TODO replace nil
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_Spots_PaginationHeaders(t *testing.T) {
+	service := newMockSurfingService()
+	service.
+		On("Spots", mock.Anything, surfing.SpotsParams{
+			Limit:       2,
+			Offset:      2,
+			CountryCode: "kz",
+			SearchQuery: "bay",
+		}).
+		Return([]surf.Spot{{ID: "3"}, {ID: "4"}}, nil)
+
+	server := httptest.NewServer(newRouter(nil, service, nil, nil, nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/surfing/v1/spots?country=kz&query=bay&limit=2&offset=2")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(headerLimit))
+	assert.Equal(t, "2", resp.Header.Get(headerOffset))
+	assert.Equal(
+		t,
+		`</surfing/v1/spots?country=kz&limit=2&offset=4&query=bay>; rel="next", `+
+			`</surfing/v1/spots?country=kz&limit=2&offset=0&query=bay>; rel="prev"`,
+		resp.Header.Get(headerLink),
+	)
+}
+
+func TestSurfingHandler_SpotMarkers(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for unparsable bounds",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ne_lat": []string{"a"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"b"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "sw_lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid bounds",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotMarkers", mock.Anything, surfing.SpotMarkersParams{
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  91,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return(
+						([]surf.SpotMarker)(nil),
+						valerra.NewErrors(surfing.ErrInvalidNorthEastLatitude),
+					)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ne_lat": []string{"91"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotMarkers", mock.Anything, surfing.SpotMarkersParams{}).
+					Return(([]surf.SpotMarker)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and markers containing only ids and coordinates",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotMarkers", mock.Anything, surfing.SpotMarkersParams{
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return([]surf.SpotMarker{
+						{
+							ID: "1",
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+						},
+						{
+							ID: "2",
+							Coordinates: geo.Coordinates{
+								Latitude:  2.34,
+								Longitude: 4.32,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ne_lat": []string{"90"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"lat": 1.23,
+									"lon": 3.21
+								},
+								{
+									"id": "2",
+									"lat": 2.34,
+									"lon": 4.32
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/markers", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_RandomSpots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid count",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"count": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "count",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid country",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RandomSpots", mock.Anything, surfing.RandomSpotsParams{
+						Count:       3,
+						CountryCode: "zz",
+					}).
+					Return(([]surf.Spot)(nil), valerra.NewErrors(surfing.ErrInvalidCountryCode))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"count":   []string{"3"},
+					"country": []string{"zz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "country",
+									"reason": "Must be a valid ISO-2 country code."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RandomSpots", mock.Anything, surfing.RandomSpotsParams{}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and random spots body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("RandomSpots", mock.Anything, surfing.RandomSpotsParams{
+						Count:       2,
+						CountryCode: "kz",
+					}).
+					Return([]surf.Spot{
+						{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							ID:        "1",
+							Name:      "Spot 1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"count":   []string{"2"},
+					"country": []string{"kz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.23,
+									"longitude": 3.21,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan"
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/random", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotsCentroid(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for unparsable bounds",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ne_lat": []string{"a"},
+					"ne_lon": []string{"180"},
+					"sw_lat": []string{"-90"},
+					"sw_lon": []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ne_lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid country",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotsCentroid", mock.Anything, surfing.SpotsCentroidParams{
+						CountryCode: "zz",
+					}).
+					Return((*geo.Coordinates)(nil), valerra.NewErrors(surfing.ErrInvalidCountryCode))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"country": []string{"zz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "country",
+									"reason": "Must be a valid ISO-2 country code."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotsCentroid", mock.Anything, surfing.SpotsCentroidParams{}).
+					Return((*geo.Coordinates)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty centroid when no spots match",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotsCentroid", mock.Anything, surfing.SpotsCentroidParams{
+						CountryCode: "kz",
+					}).
+					Return((*geo.Coordinates)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"country": []string{"kz"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"centroid": null}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and centroid body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotsCentroid", mock.Anything, surfing.SpotsCentroidParams{
+						CountryCode: "kz",
+						SearchQuery: "query",
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return(&geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"country": []string{"kz"},
+					"query":   []string{"query"},
+					"ne_lat":  []string{"90"},
+					"ne_lon":  []string{"180"},
+					"sw_lat":  []string{"-90"},
+					"sw_lon":  []string{"-180"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"centroid": {
+								"latitude": 1.23,
+								"longitude": 3.21
+							}
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/centroid", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_NearbySpots(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for missing latitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid longitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name:    "respond with 400 status code and error body for invalid radius",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat":       []string{"1.23"},
+					"lon":       []string{"3.21"},
+					"radius_km": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "radius_km",
+									"reason": "Must be a valid number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for validation error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("NearbySpots", mock.Anything, surfing.NearbySpotsParams{
+						Coordinates: geo.Coordinates{
+							Latitude:  91,
+							Longitude: 181,
+						},
+						RadiusKm: -1,
+					}).
+					Return(([]surf.NearbySpot)(nil), valerra.NewErrors(
+						surfing.ErrInvalidLatitude,
+						surfing.ErrInvalidLongitude,
+						surfing.ErrInvalidRadius,
+					))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat":       []string{"91"},
+					"lon":       []string{"181"},
+					"radius_km": []string{"-1"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								},
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								},
+								{
+									"key": "radius_km",
+									"reason": "Must be a positive number."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("NearbySpots", mock.Anything, surfing.NearbySpotsParams{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					}).
+					Return(([]surf.NearbySpot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and nearby spots body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("NearbySpots", mock.Anything, surfing.NearbySpotsParams{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						RadiusKm: 25.5,
+						Limit:    5,
+					}).
+					Return([]surf.NearbySpot{
+						{
+							Spot: surf.Spot{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.24,
+										Longitude: 3.22,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:   "1",
+								Name: "Spot 1",
+							},
+							DistanceKm: 1.5725,
+						},
+						{
+							Spot: surf.Spot{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.33,
+										Longitude: 3.31,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:   "2",
+								Name: "Spot 2",
+							},
+							DistanceKm: 15.7248,
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"lat":       []string{"1.23"},
+					"lon":       []string{"3.21"},
+					"radius_km": []string{"25.5"},
+					"limit":     []string{"5"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.24,
+									"longitude": 3.22,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 1.57
+								},
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.33,
+									"longitude": 3.31,
+									"locality": "Locality 2",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 15.72
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/nearby", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_SpotRoute(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for start point without longitude",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ids": []string{"1,2"},
+					"lat": []string{"1.23"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "lon",
+									"reason": "Must be a valid longitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 400 status code and error body for invalid params",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotRoute", mock.Anything, surfing.SpotRouteParams{
+						SpotIDs: []string{"1", "2"},
+						Start: &geo.Coordinates{
+							Latitude:  91,
+							Longitude: 3.21,
+						},
+					}).
+					Return(([]surfing.RouteStop)(nil), valerra.NewErrors(
+						surfing.ErrUnknownSpotID,
+						surfing.ErrInvalidLatitude,
+					))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ids": []string{"1,2"},
+					"lat": []string{"91"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "ids",
+									"reason": "Must contain only ids of existing spots."
+								},
+								{
+									"key": "lat",
+									"reason": "Must be a valid latitude."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotRoute", mock.Anything, surfing.SpotRouteParams{
+						SpotIDs: []string{"1"},
+					}).
+					Return(([]surfing.RouteStop)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ids": []string{"1"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and route body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotRoute", mock.Anything, surfing.SpotRouteParams{
+						SpotIDs: []string{"1", "2"},
+						Start: &geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					}).
+					Return([]surfing.RouteStop{
+						{
+							Spot: surf.Spot{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.33,
+										Longitude: 3.31,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:   "2",
+								Name: "Spot 2",
+							},
+							DistanceKm:           15.7248,
+							CumulativeDistanceKm: 15.7248,
+						},
+						{
+							Spot: surf.Spot{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.24,
+										Longitude: 3.22,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:   "1",
+								Name: "Spot 1",
+							},
+							DistanceKm:           14.1523,
+							CumulativeDistanceKm: 29.8771,
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"ids": []string{"1,2"},
+					"lat": []string{"1.23"},
+					"lon": []string{"3.21"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"id": "2",
+									"name": "Spot 2",
+									"latitude": 1.33,
+									"longitude": 3.31,
+									"locality": "Locality 2",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 15.72,
+									"cumulative_distance_km": 15.72
+								},
+								{
+									"id": "1",
+									"name": "Spot 1",
+									"latitude": 1.24,
+									"longitude": 3.22,
+									"locality": "Locality 1",
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"distance_km": 14.15,
+									"cumulative_distance_km": 29.88
+								}
+							],
+							"total_distance_km": 29.88
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots/route", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_Countries(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name: "respond with 400 status code and error body for invalid search query",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Countries", mock.Anything, surfing.CountriesParams{
+						SearchQuery: "query",
+					}).
+					Return(([]surf.CountrySpotCount)(nil), valerra.NewErrors(surfing.ErrInvalidSearchQuery))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query": []string{"query"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "query",
+									"reason": "Must not exceed character limit."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Countries", mock.Anything, surfing.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and empty items when no spots match",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Countries", mock.Anything, surfing.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
+			},
+		},
+		{
+			name: "respond with 200 status code and countries body",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("Countries", mock.Anything, surfing.CountriesParams{
+						SearchQuery: "query",
+					}).
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
+						},
+						{
+							CountryCode: "us",
+							Count:       3,
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"query": []string{"query"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"country_code": "kz",
+									"count": 12
+								},
+								{
+									"country_code": "us",
+									"count": 3
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/countries", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_Directory(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid spots limit",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"spots_limit": []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "invalid_input",
+							"description": "Invalid input parameters.",
+							"fields": [
+								{
+									"key": "spots_limit",
+									"reason": "Must be a valid integer."
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 500 status code and error body for unexpected error",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotDirectory", mock.Anything, surfing.SpotDirectoryParams{}).
+					Return(([]surf.DirectoryCountry)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			requestFn: func(r *http.Request) {},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
+							"code": "unexpected",
+							"description": "Something went wrong..."
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+		{
+			name: "respond with 200 status code and countries grouped by localities",
+			service: func() surfingService {
+				m := newMockSurfingService()
+				m.
+					On("SpotDirectory", mock.Anything, surfing.SpotDirectoryParams{
+						Limit:           1,
+						Offset:          2,
+						LocalitiesLimit: 3,
+						SpotsLimit:      4,
+					}).
+					Return([]surf.DirectoryCountry{
+						{
+							CountryCode:   "kz",
+							SpotCount:     3,
+							LocalityCount: 2,
+							Localities: []surf.DirectoryLocality{
+								{
+									Locality:  "Aktau",
+									SpotCount: 2,
+									Spots: []surf.Spot{
+										{
+											ID:   "1",
+											Name: "Aktau Beach",
+											Location: geo.Location{
+												Coordinates: geo.Coordinates{
+													Latitude:  43.6,
+													Longitude: 51.2,
+												},
+												Locality:    "Aktau",
+												CountryCode: "kz",
+											},
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"limit":            []string{"1"},
+					"offset":           []string{"2"},
+					"localities_limit": []string{"3"},
+					"spots_limit":      []string{"4"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusOK, r.StatusCode)
+				assert.Equal(t, "1", r.Header.Get("X-Limit"))
+				assert.Equal(t, "2", r.Header.Get("X-Offset"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"data": {
+							"items": [
+								{
+									"country_code": "kz",
+									"country_name": "Kazakhstan",
+									"spot_count": 3,
+									"locality_count": 2,
+									"localities": [
+										{
+											"locality": "Aktau",
+											"spot_count": 2,
+											"spots": [
+												{
+													"id": "1",
+													"name": "Aktau Beach",
+													"latitude": 43.6,
+													"longitude": 51.2
+												}
+											]
+										}
+									]
+								}
+							]
+						}
+					}`,
+					string(body),
+				)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/directory", nil)
+			assert.NoError(t, err)
+
+			test.requestFn(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
+		})
+	}
+}
+
+func TestSurfingHandler_Localities(t *testing.T) {
+	tests := []struct {
+		name               string
+		service            surfingService
+		requestFn          func(r *http.Request)
+		expectedResponseFn func(t *testing.T, r *http.Response)
+	}{
+		{
+			name:    "respond with 400 status code and error body for invalid limit",
+			service: newMockSurfingService(),
+			requestFn: func(r *http.Request) {
+				vals := url.Values{
+					"prefix": []string{"ka"},
+					"limit":  []string{"a"},
+				}
+				r.URL.RawQuery = vals.Encode()
+			},
+			expectedResponseFn: func(t *testing.T, r *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+				body, err := ioutil.ReadAll(r.Body)
+				defer r.Body.Close()
+				assert.NoError(t, err)
+
+				assert.JSONEq(
+					t,
+					`{
+						"error": {
 							"code": "invalid_input",
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "sw_lon",
-									"reason": "Must be a valid longitude."
+									"key": "limit",
+									"reason": "Must be a valid integer."
 								}
 							]
 						}
@@ -448,30 +4818,20 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 400 status code and error body for validation error",
+			name: "respond with 400 status code and error body for invalid params",
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spots", surfing.SpotsParams{
-						Limit:       10,
-						Offset:      0,
+					On("Localities", mock.Anything, surfing.LocalitiesParams{
+						Prefix:      "k",
 						CountryCode: "zz",
 					}).
-					Return(([]surf.Spot)(nil), valerra.NewErrors(
-						surfing.ErrInvalidSearchQuery,
-						surfing.ErrInvalidCountryCode,
-						surfing.ErrInvalidNorthEastLatitude,
-						surfing.ErrInvalidNorthEastLongitude,
-						surfing.ErrInvalidSouthWestLatitude,
-						surfing.ErrInvalidSouthWestLongitude,
-					))
+					Return(([]string)(nil), valerra.NewErrors(surfing.ErrInvalidLocalityPrefix, surfing.ErrInvalidCountryCode))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
-					"limit":   []string{"10"},
-					"offset":  []string{"0"},
+					"prefix":  []string{"k"},
 					"country": []string{"zz"},
 				}
 				r.URL.RawQuery = vals.Encode()
@@ -491,28 +4851,12 @@ func TestSurfingHandler_Spots(t *testing.T) {
 							"description": "Invalid input parameters.",
 							"fields": [
 								{
-									"key": "query",
-									"reason": "Must not exceed character limit."
+									"key": "prefix",
+									"reason": "Must contain at least 2 characters."
 								},
 								{
 									"key": "country",
 									"reason": "Must be a valid ISO-2 country code."
-								},
-								{
-									"key": "ne_lat",
-									"reason": "Must be a valid latitude."
-								},
-								{
-									"key": "ne_lon",
-									"reason": "Must be a valid longitude."
-								},
-								{
-									"key": "sw_lat",
-									"reason": "Must be a valid latitude."
-								},
-								{
-									"key": "sw_lon",
-									"reason": "Must be a valid longitude."
 								}
 							]
 						}
@@ -522,22 +4866,19 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 500 status code and error body for expected error",
+			name: "respond with 500 status code and error body for unexpected error",
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spots", surfing.SpotsParams{
-						Limit:  10,
-						Offset: 0,
+					On("Localities", mock.Anything, surfing.LocalitiesParams{
+						Prefix: "ka",
 					}).
-					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+					Return(([]string)(nil), errors.New("something went wrong"))
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
-					"limit":  []string{"10"},
-					"offset": []string{"0"},
+					"prefix": []string{"ka"},
 				}
 				r.URL.RawQuery = vals.Encode()
 			},
@@ -561,20 +4902,21 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			},
 		},
 		{
-			name: "respond with 200 status code and empty spot list body",
+			name: "respond with 200 status code and empty items when no localities match",
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spots", surfing.SpotsParams{
-						Limit:  0,
-						Offset: 0,
+					On("Localities", mock.Anything, surfing.LocalitiesParams{
+						Prefix: "ka",
 					}).
-					Return(([]surf.Spot)(nil), nil)
+					Return(([]string)(nil), nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
-				// Omit query parameters
+				vals := url.Values{
+					"prefix": []string{"ka"},
+				}
+				r.URL.RawQuery = vals.Encode()
 			},
 			expectedResponseFn: func(t *testing.T, r *http.Response) {
 				assert.Equal(t, http.StatusOK, r.StatusCode)
@@ -583,82 +4925,27 @@ func TestSurfingHandler_Spots(t *testing.T) {
 				defer r.Body.Close()
 				assert.NoError(t, err)
 
-				assert.JSONEq(
-					t,
-					`{
-						"data": {
-							"items":[]
-						}
-					}`,
-					string(body),
-				)
+				assert.JSONEq(t, `{"data": {"items": []}}`, string(body))
 			},
 		},
 		{
-			name: "respond with 200 status code and spot list body",
+			name: "respond with 200 status code and localities body",
 			service: func() surfingService {
 				m := newMockSurfingService()
 				m.
-					On("Spots", surfing.SpotsParams{
-						Limit:       10,
-						Offset:      0,
+					On("Localities", mock.Anything, surfing.LocalitiesParams{
+						Prefix:      "ka",
 						CountryCode: "kz",
-						SearchQuery: "query",
-						Bounds: &geo.Bounds{
-							NorthEast: geo.Coordinates{
-								Latitude:  90,
-								Longitude: 180,
-							},
-							SouthWest: geo.Coordinates{
-								Latitude:  -90,
-								Longitude: -180,
-							},
-						},
+						Limit:       10,
 					}).
-					Return(
-						[]surf.Spot{
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 1",
-									CountryCode: "kz",
-								},
-								ID:        "1",
-								Name:      "Spot 1",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 2",
-									CountryCode: "kz",
-								},
-								ID:        "2",
-								Name:      "Spot 2",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-						},
-						nil,
-					)
+					Return([]string{"Kapchagay", "Karaganda"}, nil)
 				return m
 			}(),
-			logger: nil, // FIXME catch error logs
 			requestFn: func(r *http.Request) {
 				vals := url.Values{
-					"limit":   []string{"10"},
-					"offset":  []string{"0"},
+					"prefix":  []string{"ka"},
 					"country": []string{"kz"},
-					"query":   []string{"query"},
-					"ne_lat":  []string{"90"},
-					"ne_lon":  []string{"180"},
-					"sw_lat":  []string{"-90"},
-					"sw_lon":  []string{"-180"},
+					"limit":   []string{"10"},
 				}
 				r.URL.RawQuery = vals.Encode()
 			},
@@ -669,42 +4956,18 @@ func TestSurfingHandler_Spots(t *testing.T) {
 				defer r.Body.Close()
 				assert.NoError(t, err)
 
-				assert.JSONEq(
-					t,
-					`{
-						"data": {
-							"items": [
-								{
-									"id": "1",
-									"name": "Spot 1",
-									"latitude": 1.23,
-									"longitude": 3.21,
-									"locality": "Locality 1",
-									"country_code": "kz"
-								},
-								{
-									"id": "2",
-									"name": "Spot 2",
-									"latitude": 1.23,
-									"longitude": 3.21,
-									"locality": "Locality 2",
-									"country_code": "kz"
-								}
-							]
-						}
-					}`,
-					string(body),
-				)
+				assert.JSONEq(t, `{"data": {"items": ["Kapchagay", "Karaganda"]}}`, string(body))
 			},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, test.logger)) // TODO replace nil
+			logger, hook := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, test.service, nil, nil, logger))
 			defer server.Close()
 
-			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/spots", nil)
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/surfing/v1/localities", nil)
 			assert.NoError(t, err)
 
 			test.requestFn(req)
@@ -713,6 +4976,7 @@ func TestSurfingHandler_Spots(t *testing.T) {
 			assert.NoError(t, err)
 
 			test.expectedResponseFn(t, resp)
+			assertErrorLogs(t, resp, hook)
 		})
 	}
 }
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+const (
+	headerContentLength = "Content-Length"
+	headerContentType   = "Content-Type"
+)
+
+// withHead serves HEAD requests by handlers of the respective GET routes, so that
+// clients can check existence and size of resources cheaply instead of getting 405
+// Method Not Allowed. The response body is discarded, and its length is reported
+// via Content-Length unless the handler sets it on its own.
+func withHead(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			rctx.RouteMethod = http.MethodGet
+		}
+
+		hw := &headResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		next.ServeHTTP(hw, r)
+		hw.writeHeader()
+	})
+}
+
+// headResponseWriter counts the bytes of the response body instead of writing
+// them, and holds the status code back until the handler is done, so that the
+// Content-Length header can still be set by then. Content-Type is sniffed from the
+// body the same way net/http does for GET requests when the handler does not set
+// it.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.length == 0 && len(b) > 0 && w.Header().Get(headerContentType) == "" {
+		w.Header().Set(headerContentType, http.DetectContentType(b))
+	}
+	w.length += len(b)
+	return len(b), nil
+}
+
+func (w *headResponseWriter) writeHeader() {
+	if w.Header().Get(headerContentLength) == "" && isBodyAllowed(w.status) {
+		w.Header().Set(headerContentLength, strconv.Itoa(w.length))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func isBodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,69 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHead(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(withHead)
+	router.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	})
+	router.Get("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	router.Post("/ok", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedStatus  int
+		expectedHeaders http.Header
+		expectedBody    string
+	}{
+		{
+			name:           "respond to head request with headers of get route",
+			method:         http.MethodHead,
+			path:           "/ok",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: http.Header{
+				headerContentType:   []string{"application/json"},
+				headerContentLength: []string{"11"},
+			},
+		},
+		{
+			name:            "respond to head request without content length for no content",
+			method:          http.MethodHead,
+			path:            "/empty",
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: http.Header{},
+		},
+		{
+			name:            "respond to get request with body",
+			method:          http.MethodGet,
+			path:            "/ok",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: http.Header{headerContentType: []string{"application/json"}},
+			expectedBody:    `{"ok":true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedHeaders, w.Header())
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
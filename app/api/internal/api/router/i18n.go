@@ -0,0 +1,238 @@
+package router
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+)
+
+const headerAcceptLanguage = "Accept-Language"
+
+const (
+	languageEnglish = "en"
+	languageRussian = "ru"
+	languageKazakh  = "kk"
+)
+
+// catalogues holds translations of error descriptions and field reasons keyed by
+// language and original English text. English needs no catalogue since messages
+// are written in English as is.
+var catalogues = map[string]map[string]string{
+	languageRussian: {
+		"Something went wrong...":                                "Что-то пошло не так...",
+		"Request was canceled.":                                  "Запрос был отменён.",
+		"Request timed out.":                                     "Время ожидания запроса истекло.",
+		"Invalid input parameters.":                              "Неверные входные параметры.",
+		"Invalid payload.":                                       "Неверное тело запроса.",
+		"Payload is too large.":                                  "Тело запроса слишком большое.",
+		"Invalid credentials.":                                   "Неверные учётные данные.",
+		"Valid access token is required.":                        "Требуется действительный токен доступа.",
+		"Nothing to update.":                                     "Нечего обновлять.",
+		"Diagnostics are disabled.":                              "Диагностика отключена.",
+		"Localization is unavailable.":                           "Локализация недоступна.",
+		"Photos are unavailable.":                                "Фотографии недоступны.",
+		"Forecast is unavailable.":                               "Прогноз недоступен.",
+		"Tides are unavailable.":                                 "Данные о приливах недоступны.",
+		"At least one suggestion must be chosen.":                "Необходимо выбрать хотя бы одно предложение.",
+		"Such spot doesn't exist.":                               "Такого спота не существует.",
+		"Such deleted spot doesn't exist.":                       "Такого удалённого спота не существует.",
+		"Such spot already exists.":                              "Такой спот уже существует.",
+		"Such photo doesn't exist.":                              "Такой фотографии не существует.",
+		"Such suggestion doesn't exist.":                         "Такого предложения не существует.",
+		"Suggestion has already been resolved.":                  "Предложение уже рассмотрено.",
+		"Such reference set doesn't exist.":                      "Такого опорного набора не существует.",
+		"Location was not found.":                                "Местоположение не найдено.",
+		"Geocoder is temporarily unavailable.":                   "Геокодер временно недоступен.",
+		"Geocoder took too long to respond.":                     "Геокодер не ответил вовремя.",
+		"Some of the dependencies are unavailable.":              "Некоторые зависимости недоступны.",
+		"Must be a non empty string.":                            "Должно быть непустой строкой.",
+		"Must be a non empty string of at most 255 characters.":  "Должно быть непустой строкой длиной не более 255 символов.",
+		"Must be a non empty file.":                              "Должно быть непустым файлом.",
+		"Must be a file.":                                        "Должно быть файлом.",
+		"Must be a JPEG, PNG, or WebP image.":                    "Должно быть изображением JPEG, PNG или WebP.",
+		"Must be an integer from 1 to 5.":                        "Должно быть целым числом от 1 до 5.",
+		"Must be an integer from 1 to 7.":                        "Должно быть целым числом от 1 до 7.",
+		"Must be a positive number.":                             "Должно быть положительным числом.",
+		"Must be a valid number.":                                "Должно быть корректным числом.",
+		"Must be a valid integer.":                               "Должно быть корректным целым числом.",
+		"Must be a valid language tag.":                          "Должно быть корректным языковым тегом.",
+		"Must be a valid boolean.":                               "Должно быть корректным логическим значением.",
+		"Must be a valid latitude.":                              "Должно быть корректной широтой.",
+		"Must be a valid longitude.":                             "Должно быть корректной долготой.",
+		"Must be a valid ISO-2 country code.":                    "Должно быть корректным кодом страны ISO-2.",
+		"Must be a valid RFC3339 time.":                          "Должно быть корректным временем RFC3339.",
+		"Must be a YYYY-MM-DD date within 7 days from today.":    "Должно быть датой в формате YYYY-MM-DD в пределах 7 дней от сегодняшнего дня.",
+		"Must be a valid RFC3339 time after from.":               "Должно быть корректным временем RFC3339 после from.",
+		"Must be either all or any.":                             "Должно быть all или any.",
+		"Must be either substring or fulltext.":                  "Должно быть substring или fulltext.",
+		"Must be one of: -updated_at, reference_distance.":       "Должно быть одним из: -updated_at, reference_distance.",
+		"Must be one of: csv, geojson.":                          "Должно быть одним из: csv, geojson.",
+		"Must be one of: day, week, month.":                      "Должно быть одним из: day, week, month.",
+		"Must be one of: explain.":                               "Должно быть одним из: explain.",
+		"Must be one of: nearby.":                                "Должно быть одним из: nearby.",
+		"Must be one of: pending, applied, rejected.":            "Должно быть одним из: pending, applied, rejected.",
+		"Must match the country at the spot's coordinates.":      "Должно совпадать со страной по координатам спота.",
+		"Must contain at least 2 characters.":                    "Должно содержать не менее 2 символов.",
+		"Must contain at most 10 tags of at most 30 characters.": "Должно содержать не более 10 тегов длиной не более 30 символов.",
+		"Must contain from 1 to 25 spot ids.":                    "Должно содержать от 1 до 25 идентификаторов спотов.",
+		"Must contain from 1 to 100 spot ids.":                   "Должно содержать от 1 до 100 идентификаторов спотов.",
+		"Must contain from 1 to 100 spots.":                      "Должно содержать от 1 до 100 спотов.",
+		"Must contain from 1 to 50 coordinates.":                 "Должно содержать от 1 до 50 координат.",
+		"Must contain 1 to 1000 uniquely named valid points.":    "Должно содержать от 1 до 1000 корректных точек с уникальными названиями.",
+		"Must contain up to 50 characters of a-z, 0-9, - and _.": "Должно содержать до 50 символов из a-z, 0-9, - и _.",
+		"Must contain only non empty strings.":                   "Должно содержать только непустые строки.",
+		"Must contain only ids of existing spots.":               "Должно содержать только идентификаторы существующих спотов.",
+		"Must not be greater than ne_lat.":                       "Не должно быть больше ne_lat.",
+		"Must not be less than sw_lat.":                          "Не должно быть меньше sw_lat.",
+		"Must not exceed character limit.":                       "Не должно превышать лимит символов.",
+		"Must not exceed 500 characters.":                        "Не должно превышать 500 символов.",
+	},
+	languageKazakh: {
+		"Something went wrong...":                                "Бірдеңе дұрыс болмады...",
+		"Request was canceled.":                                  "Сұраныс тоқтатылды.",
+		"Request timed out.":                                     "Сұраныстың күту уақыты өтті.",
+		"Invalid input parameters.":                              "Енгізілген параметрлер жарамсыз.",
+		"Invalid payload.":                                       "Сұраныс денесі жарамсыз.",
+		"Payload is too large.":                                  "Сұраныс денесі тым үлкен.",
+		"Invalid credentials.":                                   "Тіркелгі деректері жарамсыз.",
+		"Valid access token is required.":                        "Жарамды қол жеткізу токені қажет.",
+		"Nothing to update.":                                     "Жаңартатын ештеңе жоқ.",
+		"Diagnostics are disabled.":                              "Диагностика өшірілген.",
+		"Localization is unavailable.":                           "Локализация қолжетімсіз.",
+		"Photos are unavailable.":                                "Фотосуреттер қолжетімсіз.",
+		"Forecast is unavailable.":                               "Болжам қолжетімсіз.",
+		"Tides are unavailable.":                                 "Тасу деректері қолжетімсіз.",
+		"At least one suggestion must be chosen.":                "Кемінде бір ұсыныс таңдалуы керек.",
+		"Such spot doesn't exist.":                               "Мұндай спот жоқ.",
+		"Such deleted spot doesn't exist.":                       "Мұндай жойылған спот жоқ.",
+		"Such spot already exists.":                              "Мұндай спот бұрыннан бар.",
+		"Such photo doesn't exist.":                              "Мұндай фотосурет жоқ.",
+		"Such suggestion doesn't exist.":                         "Мұндай ұсыныс жоқ.",
+		"Suggestion has already been resolved.":                  "Ұсыныс қаралып қойған.",
+		"Such reference set doesn't exist.":                      "Мұндай тірек жиыны жоқ.",
+		"Location was not found.":                                "Орналасқан жер табылмады.",
+		"Geocoder is temporarily unavailable.":                   "Геокодер уақытша қолжетімсіз.",
+		"Geocoder took too long to respond.":                     "Геокодер уақытында жауап бермеді.",
+		"Some of the dependencies are unavailable.":              "Кейбір тәуелділіктер қолжетімсіз.",
+		"Must be a non empty string.":                            "Бос емес жол болуы керек.",
+		"Must be a non empty string of at most 255 characters.":  "Ұзындығы 255 таңбадан аспайтын бос емес жол болуы керек.",
+		"Must be a non empty file.":                              "Бос емес файл болуы керек.",
+		"Must be a file.":                                        "Файл болуы керек.",
+		"Must be a JPEG, PNG, or WebP image.":                    "JPEG, PNG немесе WebP суреті болуы керек.",
+		"Must be an integer from 1 to 5.":                        "1-ден 5-ке дейінгі бүтін сан болуы керек.",
+		"Must be an integer from 1 to 7.":                        "1-ден 7-ге дейінгі бүтін сан болуы керек.",
+		"Must be a positive number.":                             "Оң сан болуы керек.",
+		"Must be a valid number.":                                "Жарамды сан болуы керек.",
+		"Must be a valid integer.":                               "Жарамды бүтін сан болуы керек.",
+		"Must be a valid language tag.":                          "Жарамды тіл тегі болуы керек.",
+		"Must be a valid boolean.":                               "Жарамды логикалық мән болуы керек.",
+		"Must be a valid latitude.":                              "Жарамды ендік болуы керек.",
+		"Must be a valid longitude.":                             "Жарамды бойлық болуы керек.",
+		"Must be a valid ISO-2 country code.":                    "Жарамды ISO-2 ел коды болуы керек.",
+		"Must be a valid RFC3339 time.":                          "Жарамды RFC3339 уақыты болуы керек.",
+		"Must be a YYYY-MM-DD date within 7 days from today.":    "Бүгіннен бастап 7 күн ішіндегі YYYY-MM-DD форматындағы күн болуы керек.",
+		"Must be a valid RFC3339 time after from.":               "from-нан кейінгі жарамды RFC3339 уақыты болуы керек.",
+		"Must be either all or any.":                             "all немесе any болуы керек.",
+		"Must be either substring or fulltext.":                  "substring немесе fulltext болуы керек.",
+		"Must be one of: -updated_at, reference_distance.":       "Мыналардың бірі болуы керек: -updated_at, reference_distance.",
+		"Must be one of: csv, geojson.":                          "Мыналардың бірі болуы керек: csv, geojson.",
+		"Must be one of: day, week, month.":                      "Мыналардың бірі болуы керек: day, week, month.",
+		"Must be one of: explain.":                               "Мыналардың бірі болуы керек: explain.",
+		"Must be one of: nearby.":                                "Мыналардың бірі болуы керек: nearby.",
+		"Must be one of: pending, applied, rejected.":            "Мыналардың бірі болуы керек: pending, applied, rejected.",
+		"Must match the country at the spot's coordinates.":      "Спот координаттарындағы елге сәйкес келуі керек.",
+		"Must contain at least 2 characters.":                    "Кемінде 2 таңбадан тұруы керек.",
+		"Must contain at most 10 tags of at most 30 characters.": "Ұзындығы 30 таңбадан аспайтын 10-нан көп емес тег болуы керек.",
+		"Must contain from 1 to 25 spot ids.":                    "1-ден 25-ке дейін спот идентификаторы болуы керек.",
+		"Must contain from 1 to 100 spot ids.":                   "1-ден 100-ге дейін спот идентификаторы болуы керек.",
+		"Must contain from 1 to 100 spots.":                      "1-ден 100-ге дейін спот болуы керек.",
+		"Must contain from 1 to 50 coordinates.":                 "1-ден 50-ге дейін координат болуы керек.",
+		"Must contain 1 to 1000 uniquely named valid points.":    "Атаулары бірегей 1-ден 1000-ға дейін жарамды нүкте болуы керек.",
+		"Must contain up to 50 characters of a-z, 0-9, - and _.": "a-z, 0-9, - және _ таңбаларынан 50-ге дейін таңба болуы керек.",
+		"Must contain only non empty strings.":                   "Тек бос емес жолдар болуы керек.",
+		"Must contain only ids of existing spots.":               "Тек бар споттардың идентификаторлары болуы керек.",
+		"Must not be greater than ne_lat.":                       "ne_lat-тан үлкен болмауы керек.",
+		"Must not be less than sw_lat.":                          "sw_lat-тан кіші болмауы керек.",
+		"Must not exceed character limit.":                       "Таңба шегінен аспауы керек.",
+		"Must not exceed 500 characters.":                        "500 таңбадан аспауы керек.",
+	},
+}
+
+// withMessages puts translations of the language that best matches the request's
+// Accept-Language header into the request context. Requests that do not prefer
+// any of the supported languages get English messages.
+func withMessages(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := preferredLanguage(r.Header.Get(headerAcceptLanguage))
+		if messages, ok := catalogues[lang]; ok {
+			r = r.WithContext(httputil.ContextWithMessages(r.Context(), messages))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preferredLanguage returns the supported language with the highest quality value
+// in the given Accept-Language header value. Malformed entries and unsupported
+// languages are skipped. English is returned if nothing else matches.
+func preferredLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(entry, ";")
+
+		tag := strings.ToLower(strings.TrimSpace(parts[0]))
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			tag = tag[:i]
+		}
+		if !isSupportedLanguage(tag) {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			quality = q
+		}
+		if quality == 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			lang:    tag,
+			quality: quality,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return languageEnglish
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].lang
+}
+
+func isSupportedLanguage(lang string) bool {
+	if lang == languageEnglish {
+		return true
+	}
+	_, ok := catalogues[lang]
+	return ok
+}
@@ -0,0 +1,171 @@
+package router
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestLocalizedErrors(t *testing.T) {
+	newSurfingService := func() surfingService {
+		m := newMockSurfingService()
+		m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, surf.ErrSpotNotFound)
+		return m
+	}
+
+	tests := []struct {
+		name             string
+		path             string
+		acceptLanguage   string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "respond with english not found error by default",
+			path:             "/surfing/v1/spots/1",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Such spot doesn't exist."}}`,
+		},
+		{
+			name:             "respond with russian not found error",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   "ru-RU,ru;q=0.9,en;q=0.8",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Такого спота не существует."}}`,
+		},
+		{
+			name:             "respond with kazakh not found error",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   "kk",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Мұндай спот жоқ."}}`,
+		},
+		{
+			name:             "respond with english field error",
+			path:             "/surfing/v1/spots?limit=abc",
+			acceptLanguage:   "en-US",
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"error":{"code":"invalid_input","description":"Invalid input parameters.","fields":[{"key":"limit","reason":"Must be a valid integer."}]}}`,
+		},
+		{
+			name:             "respond with russian field error",
+			path:             "/surfing/v1/spots?limit=abc",
+			acceptLanguage:   "ru",
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"error":{"code":"invalid_input","description":"Неверные входные параметры.","fields":[{"key":"limit","reason":"Должно быть корректным целым числом."}]}}`,
+		},
+		{
+			name:             "respond with kazakh field error",
+			path:             "/surfing/v1/spots?limit=abc",
+			acceptLanguage:   "kk-KZ",
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"error":{"code":"invalid_input","description":"Енгізілген параметрлер жарамсыз.","fields":[{"key":"limit","reason":"Жарамды бүтін сан болуы керек."}]}}`,
+		},
+		{
+			name:             "respond with preferred language by quality",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   "ru;q=0.5,kk;q=0.8",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Мұндай спот жоқ."}}`,
+		},
+		{
+			name:             "fall back to next supported language",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   "de-DE,de;q=0.9,ru;q=0.5",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Такого спота не существует."}}`,
+		},
+		{
+			name:             "fall back to english for unknown language",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   "fr-FR",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Such spot doesn't exist."}}`,
+		},
+		{
+			name:             "fall back to english for malformed header",
+			path:             "/surfing/v1/spots/1",
+			acceptLanguage:   ";;q=abc,,",
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: `{"error":{"code":"not_found","description":"Such spot doesn't exist."}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(newRouter(nil, newSurfingService(), nil, nil, nil))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+			assert.NoError(t, err)
+			if test.acceptLanguage != "" {
+				req.Header.Set(headerAcceptLanguage, test.acceptLanguage)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.JSONEq(t, test.expectedResponse, string(body))
+		})
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{
+			name:     "return english for empty header",
+			header:   "",
+			expected: languageEnglish,
+		},
+		{
+			name:     "return language ignoring region",
+			header:   "RU-ru",
+			expected: languageRussian,
+		},
+		{
+			name:     "return language with highest quality",
+			header:   "en;q=0.3,kk;q=0.7,ru;q=0.6",
+			expected: languageKazakh,
+		},
+		{
+			name:     "return first language among equal qualities",
+			header:   "kk,ru",
+			expected: languageKazakh,
+		},
+		{
+			name:     "skip language with zero quality",
+			header:   "ru;q=0,kk;q=0.1",
+			expected: languageKazakh,
+		},
+		{
+			name:     "skip language with invalid quality",
+			header:   "ru;q=2,kk;q=0.1",
+			expected: languageKazakh,
+		},
+		{
+			name:     "return english for wildcard",
+			header:   "*",
+			expected: languageEnglish,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, preferredLanguage(test.header))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package router
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+)
+
+// testLogHook captures entries written by a logger.
+type testLogHook struct {
+	mu      sync.Mutex
+	entries []logrus.Entry
+}
+
+func (h *testLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *testLogHook) Fire(e *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, *e)
+	return nil
+}
+
+// entriesAt returns captured entries of the given level.
+func (h *testLogHook) entriesAt(level logrus.Level) []logrus.Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var entries []logrus.Entry
+	for _, e := range h.entries {
+		if e.Level == level {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// newTestLogger returns a logger that discards its output and captures entries
+// into the returned hook instead.
+func newTestLogger() (*logrus.Logger, *testLogHook) {
+	hook := &testLogHook{}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.AddHook(hook)
+
+	return logger, hook
+}
+
+// assertErrorLogs asserts that a single error along with the underlying cause is
+// logged for a response with 500 status code, and that nothing is logged as an
+// error otherwise.
+func assertErrorLogs(t *testing.T, r *http.Response, hook *testLogHook) bool {
+	entries := hook.entriesAt(logrus.ErrorLevel)
+
+	if r.StatusCode != http.StatusInternalServerError {
+		return assert.Empty(t, entries)
+	}
+
+	if !assert.Len(t, entries, 1) {
+		return false
+	}
+	return assert.Error(t, entries[0].Data[logrus.ErrorKey].(error))
+}
+
+func TestWithLogger(t *testing.T) {
+	logger, hook := newTestLogger()
+	err := errors.New("something went wrong")
+
+	handler := withRequestID(withLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteUnexpectedError(w, r, err)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/surfing/v1/spots?limit=5", nil)
+	req.Header.Set(headerRequestID, "abc")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	entries := hook.entriesAt(logrus.ErrorLevel)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+
+	assert.Equal(t, "unexpected error: something went wrong", entries[0].Message)
+	assert.Equal(t, logrus.Fields{
+		logrus.ErrorKey: err,
+		"request_id":    "abc",
+		"method":        http.MethodGet,
+		"path":          "/surfing/v1/spots",
+	}, entries[0].Data)
+}
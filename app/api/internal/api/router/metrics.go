@@ -0,0 +1,113 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+const (
+	metricHTTPRequestsTotal          = "http_requests_total"
+	metricHTTPRequestDurationSeconds = "http_request_duration_seconds"
+	metricHTTPResponseSizeBytes      = "http_response_size_bytes"
+
+	// routeUnmatched is used as a route label for requests that did not match
+	// any of the registered routes in order to keep cardinality of the label low.
+	routeUnmatched = "unmatched"
+)
+
+// defaultResponseSizeBuckets holds upper bounds of response size buckets in bytes
+// ranging from 256 bytes to 4 megabytes.
+var defaultResponseSizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+type responseSizeMetrics struct {
+	buckets []float64
+}
+
+func withMetrics(reg *metrics.Registry, rsm *responseSizeMetrics) func(http.Handler) http.Handler {
+	var (
+		requests = reg.Counter(
+			metricHTTPRequestsTotal,
+			"Total number of HTTP requests.",
+			"method", "route", "status",
+		)
+		durations = reg.Histogram(
+			metricHTTPRequestDurationSeconds,
+			"Duration of HTTP requests in seconds.",
+			nil,
+			"method", "route",
+		)
+		sizes *metrics.Histogram
+	)
+
+	if rsm != nil {
+		buckets := rsm.buckets
+		if len(buckets) == 0 {
+			buckets = defaultResponseSizeBuckets
+		}
+		sizes = reg.Histogram(
+			metricHTTPResponseSizeBytes,
+			"Size of HTTP response bodies in bytes.",
+			buckets,
+			"method", "route",
+		)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusResponseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(sw, r)
+
+			route := routePattern(r)
+			requests.Inc(r.Method, route, strconv.Itoa(sw.statusCode()))
+			durations.Observe(time.Since(start).Seconds(), r.Method, route)
+			sizes.Observe(float64(sw.size), r.Method, route)
+		})
+	}
+}
+
+// routePattern returns a pattern of the route that the given request has matched,
+// or routeUnmatched if it has matched none. The pattern is complete only once the
+// request has been routed.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return routeUnmatched
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+func (w *statusResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,105 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+func TestWithMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	r := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil, WithMetrics(reg))
+
+	for _, path := range []string{"/health", "/health", "/unknown"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	requests := reg.Counter(metricHTTPRequestsTotal, "", "method", "route", "status")
+	assert.Equal(t, float64(2), requests.Value(http.MethodGet, "/health", "200"))
+	assert.Equal(t, float64(1), requests.Value(http.MethodGet, routeUnmatched, "404"))
+
+	durations := reg.Histogram(metricHTTPRequestDurationSeconds, "", nil, "method", "route")
+	assert.Equal(t, uint64(2), durations.Count(http.MethodGet, "/health"))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `http_requests_total{method="GET",route="/health",status="200"} 2`)
+}
+
+func TestWithResponseSizeMetrics(t *testing.T) {
+	newSurfingService := func() surfingService {
+		m := newMockSurfingService()
+		m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, surf.ErrSpotNotFound)
+		return m
+	}
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		expectedMetrics []string
+		expectedMissing []string
+	}{
+		{
+			name: "record response sizes using default buckets",
+			opts: []Option{WithResponseSizeMetrics()},
+			expectedMetrics: []string{
+				`http_response_size_bytes_bucket{method="GET",route="/surfing/v1/spots/{spot_id}",le="256"} 1`,
+				`http_response_size_bytes_sum{method="GET",route="/surfing/v1/spots/{spot_id}"} 71`,
+				`http_response_size_bytes_count{method="GET",route="/surfing/v1/spots/{spot_id}"} 1`,
+			},
+		},
+		{
+			name: "record response sizes using custom buckets",
+			opts: []Option{WithResponseSizeMetrics(10, 100)},
+			expectedMetrics: []string{
+				`http_response_size_bytes_bucket{method="GET",route="/surfing/v1/spots/{spot_id}",le="10"} 0`,
+				`http_response_size_bytes_bucket{method="GET",route="/surfing/v1/spots/{spot_id}",le="100"} 1`,
+				`http_response_size_bytes_sum{method="GET",route="/surfing/v1/spots/{spot_id}"} 71`,
+			},
+		},
+		{
+			name:            "do not record response sizes by default",
+			opts:            nil,
+			expectedMissing: []string{metricHTTPResponseSizeBytes},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := append([]Option{WithMetrics(metrics.NewRegistry())}, test.opts...)
+			r := newRouter(nil, newSurfingService(), newMockManagementService(), nil, nil, opts...)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/surfing/v1/spots/1", nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, 71, rec.Body.Len())
+
+			rec = httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			for _, m := range test.expectedMetrics {
+				assert.Contains(t, rec.Body.String(), m)
+			}
+			for _, m := range test.expectedMissing {
+				assert.NotContains(t, rec.Body.String(), m)
+			}
+		})
+	}
+}
+
+func TestWithoutMetrics(t *testing.T) {
+	r := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,57 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerLink   = "Link"
+	headerLimit  = "X-Limit"
+	headerOffset = "X-Offset"
+
+	relationNext = "next"
+	relationPrev = "prev"
+)
+
+// writePaginationHeaders writes X-Limit and X-Offset headers with the given limit
+// and offset of a page, and a Link header as described in RFC 5988 with URLs of
+// the next and the previous pages. The URLs keep the rest of the request's query
+// parameters, such as filters, as is.
+//
+// Since the total number of items is unknown, the next page is only linked when
+// the given count of items on the current page reaches the limit.
+func writePaginationHeaders(w http.ResponseWriter, r *http.Request, limit, offset, count int) {
+	w.Header().Set(headerLimit, strconv.Itoa(limit))
+	w.Header().Set(headerOffset, strconv.Itoa(offset))
+
+	var links []string
+	if count >= limit {
+		links = append(links, pageLink(r.URL, limit, offset+limit, relationNext))
+	}
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, pageLink(r.URL, limit, prevOffset, relationPrev))
+	}
+
+	if len(links) > 0 {
+		w.Header().Set(headerLink, strings.Join(links, ", "))
+	}
+}
+
+func pageLink(u *url.URL, limit, offset int, rel string) string {
+	q := u.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+
+	link := url.URL{
+		Path:     u.Path,
+		RawQuery: q.Encode(),
+	}
+	return `<` + link.String() + `>; rel="` + rel + `"`
+}
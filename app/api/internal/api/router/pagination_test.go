@@ -0,0 +1,75 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		limit          int
+		offset         int
+		count          int
+		expectedLimit  string
+		expectedOffset string
+		expectedLink   string
+	}{
+		{
+			name:           "link only next page for full first page",
+			url:            "/spots?country=kz",
+			limit:          10,
+			offset:         0,
+			count:          10,
+			expectedLimit:  "10",
+			expectedOffset: "0",
+			expectedLink:   `</spots?country=kz&limit=10&offset=10>; rel="next"`,
+		},
+		{
+			name:           "link next and previous pages for middle page",
+			url:            "/spots?country=kz&query=bay&ne_lat=1&ne_lon=2&sw_lat=-1&sw_lon=-2&limit=10&offset=20",
+			limit:          10,
+			offset:         20,
+			count:          10,
+			expectedLimit:  "10",
+			expectedOffset: "20",
+			expectedLink: `</spots?country=kz&limit=10&ne_lat=1&ne_lon=2&offset=30&query=bay&sw_lat=-1&sw_lon=-2>; rel="next", ` +
+				`</spots?country=kz&limit=10&ne_lat=1&ne_lon=2&offset=10&query=bay&sw_lat=-1&sw_lon=-2>; rel="prev"`,
+		},
+		{
+			name:           "link only previous page for last page",
+			url:            "/spots?offset=5",
+			limit:          10,
+			offset:         5,
+			count:          3,
+			expectedLimit:  "10",
+			expectedOffset: "5",
+			expectedLink:   `</spots?limit=10&offset=0>; rel="prev"`,
+		},
+		{
+			name:           "link no pages for single page",
+			url:            "/spots",
+			limit:          10,
+			offset:         0,
+			count:          3,
+			expectedLimit:  "10",
+			expectedOffset: "0",
+			expectedLink:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writePaginationHeaders(rec, httptest.NewRequest(http.MethodGet, test.url, nil), test.limit, test.offset, test.count)
+
+			assert.Equal(t, test.expectedLimit, rec.Header().Get(headerLimit))
+			assert.Equal(t, test.expectedOffset, rec.Header().Get(headerOffset))
+			assert.Equal(t, test.expectedLink, rec.Header().Get(headerLink))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+const metricHTTPPanicsTotal = "http_panics_total"
+
+// withRecoverer recovers from panics of handlers and responds with an unexpected
+// error instead of dropping the connection. The panic gets logged along with its
+// stack trace, and counted when the given registry is not nil.
+//
+// When the handler has already started the response, the error cannot be written
+// anymore, so the response is aborted with http.ErrAbortHandler after logging the
+// panic. http.ErrAbortHandler panicked by handlers is panicked again, since it is
+// meant to abort the response without logging anything.
+func withRecoverer(reg *metrics.Registry) func(http.Handler) http.Handler {
+	var panics *metrics.Counter
+	if reg != nil {
+		panics = reg.Counter(
+			metricHTTPPanicsTotal,
+			"Total number of panics recovered from HTTP handlers.",
+			"method", "route",
+		)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &statusResponseWriter{ResponseWriter: w}
+
+			defer func() {
+				rcv := recover()
+				if rcv == nil {
+					return
+				}
+				if rcv == http.ErrAbortHandler {
+					panic(rcv)
+				}
+
+				panics.Inc(r.Method, routePattern(r))
+
+				err := &panicError{
+					value: rcv,
+					stack: debug.Stack(),
+				}
+
+				if sw.status != 0 {
+					if logger := log.FromContext(r.Context()); logger != nil {
+						log.WithError(logger, err).Errorf("unexpected error after response started: %s", err)
+					}
+					panic(http.ErrAbortHandler)
+				}
+
+				httputil.WriteUnexpectedError(w, r, err)
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// panicError holds a value recovered from a panic along with the stack trace of
+// the panicking goroutine, which gets logged as a separate field.
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+func (e *panicError) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"stack": string(e.stack),
+	}
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+func TestWithRecoverer(t *testing.T) {
+	logger, hook := newTestLogger()
+	reg := metrics.NewRegistry()
+
+	service := newMockManagementService()
+	service.
+		On("AuditEntries", mock.Anything, management.AuditEntriesParams{Limit: 1}).
+		Run(func(mock.Arguments) {
+			panic("something went wrong")
+		}).
+		Return(([]surf.AuditEntry)(nil), nil)
+	service.
+		On("AuditEntries", mock.Anything, management.AuditEntriesParams{Limit: 2}).
+		Return([]surf.AuditEntry{}, nil)
+
+	server := httptest.NewServer(newRouter(nil, newMockSurfingService(), service, nil, logger, WithMetrics(reg)))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/audit?limit=1", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	req.Header.Set(headerRequestID, "abc")
+
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	defer resp.Body.Close()
+	assert.NoError(t, err)
+
+	assert.JSONEq(
+		t,
+		`{
+			"error": {
+				"code": "unexpected",
+				"description": "Something went wrong..."
+			}
+		}`,
+		string(body),
+	)
+
+	entries := hook.entriesAt(logrus.ErrorLevel)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.Equal(t, "unexpected error: panic: something went wrong", entries[0].Message)
+	assert.Equal(t, "abc", entries[0].Data["request_id"])
+	assert.Contains(t, entries[0].Data["stack"], "runtime/debug.Stack")
+
+	panics := reg.Counter(metricHTTPPanicsTotal, "", "method", "route")
+	assert.Equal(t, float64(1), panics.Value(http.MethodGet, "/management/v1/audit"))
+
+	// The server keeps serving requests after a panic.
+	resp, err = http.Get(server.URL + "/management/v1/audit?limit=2")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestWithRecoverer_AbortHandler(t *testing.T) {
+	h := withRecoverer(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestWithRecoverer_ResponseStarted(t *testing.T) {
+	logger, hook := newTestLogger()
+
+	h := withRecoverer(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		panic("something went wrong")
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(log.ContextWith(r.Context(), logrus.NewEntry(logger)))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(w, r)
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	entries := hook.entriesAt(logrus.ErrorLevel)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.Equal(t, "unexpected error after response started: panic: something went wrong", entries[0].Message)
+	assert.Contains(t, entries[0].Data["stack"], "runtime/debug.Stack")
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+const (
+	headerRequestID = "X-Request-ID"
+
+	// maxRequestIDLength limits the length of a request ID that can be accepted
+	// from a client in order to prevent flooding logs with arbitrary data.
+	maxRequestIDLength = 128
+)
+
+type requestIDContextKey struct{}
+
+var keyRequestID requestIDContextKey = struct{}{}
+
+func contextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keyRequestID, id)
+}
+
+// RequestIDFromContext retrieves an ID of the request from the given context if
+// available. If the context does not contain a request ID, an empty string is
+// returned.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(keyRequestID).(string)
+	return id
+}
+
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(headerRequestID)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(headerRequestID, id)
+		r = r.WithContext(contextWithRequestID(r.Context(), id))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read is not expected to fail on supported platforms.
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
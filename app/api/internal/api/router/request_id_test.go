@@ -0,0 +1,94 @@
+package router
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestWithRequestID(t *testing.T) {
+	tests := []struct {
+		name         string
+		requestID    string
+		expectedIDFn func(t *testing.T, id string)
+	}{
+		{
+			name:      "reuse incoming request id",
+			requestID: "abc-123",
+			expectedIDFn: func(t *testing.T, id string) {
+				assert.Equal(t, "abc-123", id)
+			},
+		},
+		{
+			name:      "generate request id when missing",
+			requestID: "",
+			expectedIDFn: func(t *testing.T, id string) {
+				assert.Regexp(t, uuidPattern, id)
+			},
+		},
+		{
+			name:      "generate request id when incoming one is invalid",
+			requestID: "abc 123",
+			expectedIDFn: func(t *testing.T, id string) {
+				assert.Regexp(t, uuidPattern, id)
+			},
+		},
+		{
+			name:      "generate request id when incoming one is too long",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			expectedIDFn: func(t *testing.T, id string) {
+				assert.Regexp(t, uuidPattern, id)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ctxID string
+			h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.requestID != "" {
+				req.Header.Set(headerRequestID, test.requestID)
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			test.expectedIDFn(t, ctxID)
+			assert.Equal(t, ctxID, rec.Header().Get(headerRequestID))
+		})
+	}
+}
+
+func TestWithLogger_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+
+	h := withRequestID(withLogger(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteUnexpectedError(w, r, errors.New("something went wrong"))
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(headerRequestID, "abc-123")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, buf.String(), `"request_id":"abc-123"`)
+}
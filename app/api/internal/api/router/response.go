@@ -1,6 +1,15 @@
 package router
 
-import "github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
+)
 
 type spotResponse struct {
 	ID          string  `json:"id"`
@@ -9,9 +18,60 @@ type spotResponse struct {
 	Longitude   float64 `json:"longitude"`
 	Locality    string  `json:"locality"`
 	CountryCode string  `json:"country_code"`
+
+	// CountryName is empty when the country code is unknown.
+	CountryName string `json:"country_name"`
+
+	// Region is omitted when unknown.
+	Region string `json:"region,omitempty"`
+
+	// Slug is omitted when the spot has none.
+	Slug string `json:"slug,omitempty"`
+
+	// Tags are omitted when the spot has none.
+	Tags []string `json:"tags,omitempty"`
+
+	// UpdatedAt is formatted in RFC3339. It is omitted when unknown.
+	UpdatedAt string `json:"updated_at,omitempty"`
+
+	// DeletedAt is formatted in RFC3339. It is only populated for soft-deleted
+	// spots.
+	DeletedAt *string `json:"deleted_at,omitempty"`
+
+	// NearestReference is only populated when spots are listed along with a
+	// reference set and the spot's distance to it has been backfilled.
+	NearestReference *nearestReferenceResponse `json:"nearest_reference,omitempty"`
+
+	// AverageRating and RatingsCount are omitted when the spot has no ratings.
+	AverageRating float64 `json:"average_rating,omitempty"`
+	RatingsCount  int     `json:"ratings_count,omitempty"`
+
+	// Favorited is only populated for requests that carry a valid access token.
+	Favorited *bool `json:"favorited,omitempty"`
+}
+
+type nearestReferenceResponse struct {
+	Name string `json:"name"`
+
+	// DistanceKm is rounded to two decimal places.
+	DistanceKm float64 `json:"distance_km"`
 }
 
 func toSpotResponse(s surf.Spot) spotResponse {
+	var deletedAt *string
+	if s.DeletedAt != nil {
+		t := formatTime(*s.DeletedAt)
+		deletedAt = &t
+	}
+
+	var nearestReference *nearestReferenceResponse
+	if s.NearestReference != nil {
+		nearestReference = &nearestReferenceResponse{
+			Name:       s.NearestReference.PointName,
+			DistanceKm: roundKm(s.NearestReference.DistanceKm),
+		}
+	}
+
 	return spotResponse{
 		ID:          s.ID,
 		Name:        s.Name,
@@ -19,11 +79,300 @@ func toSpotResponse(s surf.Spot) spotResponse {
 		Longitude:   s.Location.Coordinates.Longitude,
 		Locality:    s.Location.Locality,
 		CountryCode: s.Location.CountryCode,
+		CountryName: countryName(s.Location.CountryCode),
+		Region:      s.Location.Region,
+		Slug:        s.Slug,
+		Tags:        s.Tags,
+		UpdatedAt:   formatTime(s.UpdatedAt),
+		DeletedAt:   deletedAt,
+
+		NearestReference: nearestReference,
+
+		AverageRating: roundRating(s.Ratings.Average),
+		RatingsCount:  s.Ratings.Count,
+	}
+}
+
+func countryName(code string) string {
+	name, _ := geo.CountryName(code)
+	return name
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type localizedSpotResponse struct {
+	spotResponse
+
+	// LocalizedLocality is empty when the geocoder knows no locality at the spot's
+	// coordinates.
+	LocalizedLocality string `json:"localized_locality"`
 }
 
 type spotsResponse struct {
 	Items []spotResponse `json:"items"`
+
+	// QueryPlan is only populated for diagnostic requests.
+	QueryPlan json.RawMessage `json:"query_plan,omitempty"`
+}
+
+type createSpotsResponse struct {
+	Created int                       `json:"created"`
+	Items   []createSpotsItemResponse `json:"items"`
+}
+
+type createSpotsItemResponse struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Error  interface{} `json:"error,omitempty"`
+}
+
+type deleteSpotsResponse struct {
+	DeletedCount int      `json:"deleted_count"`
+	NotFoundIDs  []string `json:"not_found_ids"`
+}
+
+type favoriteBatchResponse struct {
+	FavoritedIDs   []string `json:"favorited_ids"`
+	UnfavoritedIDs []string `json:"unfavorited_ids"`
+	NotFoundIDs    []string `json:"not_found_ids"`
+}
+
+type backfillReferenceDistancesResponse struct {
+	BackfilledCount int `json:"backfilled_count"`
+}
+
+type nearbySpotResponse struct {
+	spotResponse
+
+	// DistanceKm is rounded to two decimal places.
+	DistanceKm float64 `json:"distance_km"`
+}
+
+func toNearbySpotResponse(s surf.NearbySpot) nearbySpotResponse {
+	return nearbySpotResponse{
+		spotResponse: toSpotResponse(s.Spot),
+		DistanceKm:   roundKm(s.DistanceKm),
+	}
+}
+
+type spotWithNearbyResponse struct {
+	spotResponse
+	Nearby []nearbySpotResponse `json:"nearby"`
+}
+
+func toSpotWithNearbyResponse(s surf.Spot, nearby []surf.NearbySpot) spotWithNearbyResponse {
+	resp := spotWithNearbyResponse{
+		spotResponse: toSpotResponse(s),
+		Nearby:       make([]nearbySpotResponse, len(nearby)),
+	}
+	for i, n := range nearby {
+		resp.Nearby[i] = toNearbySpotResponse(n)
+	}
+	return resp
+}
+
+type nearbySpotsResponse struct {
+	Items []nearbySpotResponse `json:"items"`
+}
+
+type routeStopResponse struct {
+	spotResponse
+
+	// DistanceKm and CumulativeDistanceKm are rounded to two decimal places.
+	DistanceKm           float64 `json:"distance_km"`
+	CumulativeDistanceKm float64 `json:"cumulative_distance_km"`
+}
+
+type spotRouteResponse struct {
+	Items           []routeStopResponse `json:"items"`
+	TotalDistanceKm float64             `json:"total_distance_km"`
+}
+
+func roundKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+func roundRating(r float64) float64 {
+	return math.Round(r*100) / 100
+}
+
+type spotRatingsResponse struct {
+	AverageRating float64 `json:"average_rating"`
+	RatingsCount  int     `json:"ratings_count"`
+}
+
+func toSpotRatingsResponse(r surf.SpotRatings) spotRatingsResponse {
+	return spotRatingsResponse{
+		AverageRating: roundRating(r.Average),
+		RatingsCount:  r.Count,
+	}
+}
+
+type spotMarkerResponse struct {
+	ID        string  `json:"id"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+func toSpotMarkerResponse(m surf.SpotMarker) spotMarkerResponse {
+	return spotMarkerResponse{
+		ID:        m.ID,
+		Latitude:  m.Coordinates.Latitude,
+		Longitude: m.Coordinates.Longitude,
+	}
+}
+
+type spotMarkersResponse struct {
+	Items []spotMarkerResponse `json:"items"`
+}
+
+type spotsCentroidResponse struct {
+	// Centroid is null when no spots match.
+	Centroid *coordinatesResponse `json:"centroid"`
+}
+
+type coordinatesResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func toSpotsCentroidResponse(c *geo.Coordinates) spotsCentroidResponse {
+	if c == nil {
+		return spotsCentroidResponse{}
+	}
+	return spotsCentroidResponse{
+		Centroid: &coordinatesResponse{
+			Latitude:  c.Latitude,
+			Longitude: c.Longitude,
+		},
+	}
+}
+
+type countriesResponse struct {
+	Items []countryResponse `json:"items"`
+}
+
+type countryResponse struct {
+	CountryCode string `json:"country_code"`
+	Count       int    `json:"count"`
+}
+
+func toCountriesResponse(counts []surf.CountrySpotCount) countriesResponse {
+	items := make([]countryResponse, len(counts))
+	for i, c := range counts {
+		items[i] = countryResponse{
+			CountryCode: c.CountryCode,
+			Count:       c.Count,
+		}
+	}
+	return countriesResponse{
+		Items: items,
+	}
+}
+
+type directoryResponse struct {
+	Items []directoryCountryResponse `json:"items"`
+}
+
+type directoryCountryResponse struct {
+	CountryCode string `json:"country_code"`
+
+	// CountryName is empty when the country code is unknown.
+	CountryName string `json:"country_name"`
+
+	// SpotCount and LocalityCount cover all spots and localities of the country,
+	// including the ones cut off by limits.
+	SpotCount     int `json:"spot_count"`
+	LocalityCount int `json:"locality_count"`
+
+	Localities []directoryLocalityResponse `json:"localities"`
+}
+
+type directoryLocalityResponse struct {
+	Locality  string                  `json:"locality"`
+	SpotCount int                     `json:"spot_count"`
+	Spots     []directorySpotResponse `json:"spots"`
+}
+
+type directorySpotResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func toDirectoryResponse(countries []surf.DirectoryCountry) directoryResponse {
+	items := make([]directoryCountryResponse, len(countries))
+	for i, c := range countries {
+		localities := make([]directoryLocalityResponse, len(c.Localities))
+		for j, l := range c.Localities {
+			spots := make([]directorySpotResponse, len(l.Spots))
+			for k, s := range l.Spots {
+				spots[k] = directorySpotResponse{
+					ID:        s.ID,
+					Name:      s.Name,
+					Latitude:  s.Location.Coordinates.Latitude,
+					Longitude: s.Location.Coordinates.Longitude,
+				}
+			}
+			localities[j] = directoryLocalityResponse{
+				Locality:  l.Locality,
+				SpotCount: l.SpotCount,
+				Spots:     spots,
+			}
+		}
+		items[i] = directoryCountryResponse{
+			CountryCode:   c.CountryCode,
+			CountryName:   countryName(c.CountryCode),
+			SpotCount:     c.SpotCount,
+			LocalityCount: c.LocalityCount,
+			Localities:    localities,
+		}
+	}
+	return directoryResponse{
+		Items: items,
+	}
+}
+
+type localitiesResponse struct {
+	Items []string `json:"items"`
+}
+
+func toLocalitiesResponse(localities []string) localitiesResponse {
+	if localities == nil {
+		localities = []string{}
+	}
+	return localitiesResponse{
+		Items: localities,
+	}
+}
+
+type spotsOverTimeResponse struct {
+	Items []spotsOverTimeItemResponse `json:"items"`
+}
+
+type spotsOverTimeItemResponse struct {
+	// BucketStart is formatted in RFC3339.
+	BucketStart string `json:"bucket_start"`
+	Count       int    `json:"count"`
+}
+
+func toSpotsOverTimeResponse(counts []surf.SpotCreationCount) spotsOverTimeResponse {
+	items := make([]spotsOverTimeItemResponse, len(counts))
+	for i, c := range counts {
+		items[i] = spotsOverTimeItemResponse{
+			BucketStart: formatTime(c.BucketStart),
+			Count:       c.Count,
+		}
+	}
+	return spotsOverTimeResponse{
+		Items: items,
+	}
 }
 
 type locationResponse struct {
@@ -31,8 +380,253 @@ type locationResponse struct {
 	Longitude   float64 `json:"longitude"`
 	Locality    string  `json:"locality"`
 	CountryCode string  `json:"country_code"`
+
+	// CountryName is empty when the country code is unknown.
+	CountryName string `json:"country_name"`
+}
+
+func toLocationResponse(l geo.Location) locationResponse {
+	return locationResponse{
+		Latitude:    l.Coordinates.Latitude,
+		Longitude:   l.Coordinates.Longitude,
+		Locality:    l.Locality,
+		CountryCode: l.CountryCode,
+		CountryName: countryName(l.CountryCode),
+	}
+}
+
+type locationsResponse struct {
+	Items []locationsItemResponse `json:"items"`
+}
+
+// locationsItemResponse holds either a location or an error of a single item out
+// of multiple ones.
+type locationsItemResponse struct {
+	Index    int               `json:"index"`
+	Status   int               `json:"status"`
+	Location *locationResponse `json:"location,omitempty"`
+	Error    interface{}       `json:"error,omitempty"`
 }
 
 type tokenResponse struct {
 	AccessToken string `json:"access_token"`
 }
+
+type readinessResponse struct {
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+type spotLocationSuggestionResponse struct {
+	SpotID    string                     `json:"spot_id"`
+	Current   locationSuggestionResponse `json:"current"`
+	Suggested locationSuggestionResponse `json:"suggested"`
+}
+
+type locationSuggestionResponse struct {
+	Locality    string `json:"locality"`
+	CountryCode string `json:"country_code"`
+
+	// CountryName is empty when the country code is unknown.
+	CountryName string `json:"country_name"`
+	Region      string `json:"region"`
+}
+
+type spotPhotoResponse struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+
+	// CreatedAt is formatted in RFC3339.
+	CreatedAt string `json:"created_at"`
+}
+
+func toSpotPhotoResponse(p surf.SpotPhoto, url string) spotPhotoResponse {
+	return spotPhotoResponse{
+		ID:          p.ID,
+		URL:         url,
+		ContentType: p.ContentType,
+		Size:        p.Size,
+		CreatedAt:   formatTime(p.CreatedAt),
+	}
+}
+
+type spotPhotosResponse struct {
+	Items []spotPhotoResponse `json:"items"`
+}
+
+type forecastDayResponse struct {
+	// Date is formatted as YYYY-MM-DD in UTC.
+	Date string `json:"date"`
+
+	// Values are null when the forecast source has no data for them.
+	WaveHeightM      *float64 `json:"wave_height_m"`
+	WavePeriodS      *float64 `json:"wave_period_s"`
+	WaveDirectionDeg *float64 `json:"wave_direction_deg"`
+	WindSpeedKmh     *float64 `json:"wind_speed_kmh"`
+	WindGustsKmh     *float64 `json:"wind_gusts_kmh"`
+	WindDirectionDeg *float64 `json:"wind_direction_deg"`
+}
+
+func toForecastDayResponse(d forecast.Day) forecastDayResponse {
+	return forecastDayResponse{
+		Date:             d.Date.UTC().Format("2006-01-02"),
+		WaveHeightM:      d.WaveHeightMax,
+		WavePeriodS:      d.WavePeriodMax,
+		WaveDirectionDeg: d.WaveDirection,
+		WindSpeedKmh:     d.WindSpeedMax,
+		WindGustsKmh:     d.WindGustsMax,
+		WindDirectionDeg: d.WindDirection,
+	}
+}
+
+type spotForecastResponse struct {
+	Items []forecastDayResponse `json:"items"`
+}
+
+type tideExtremeResponse struct {
+	// Time is formatted in RFC3339.
+	Time string `json:"time"`
+
+	// Type is either high or low.
+	Type string `json:"type"`
+
+	// HeightM is null when the tide source has no data for it.
+	HeightM *float64 `json:"height_m"`
+}
+
+func toTideExtremeResponse(e tide.Extreme) tideExtremeResponse {
+	return tideExtremeResponse{
+		Time:    formatTime(e.Time),
+		Type:    e.Type,
+		HeightM: e.Height,
+	}
+}
+
+type spotTidesResponse struct {
+	Items []tideExtremeResponse `json:"items"`
+}
+
+type spotSuggestionResponse struct {
+	ID     string `json:"id"`
+	SpotID string `json:"spot_id"`
+	Status string `json:"status"`
+
+	// Changes holds only the proposed values.
+	Changes spotChangeResponse `json:"changes"`
+
+	// Cleared lists optional fields of the spot's location that are proposed to be
+	// cleared. It is omitted when none are.
+	Cleared []string `json:"cleared,omitempty"`
+
+	Note string `json:"note"`
+
+	// CreatedAt is formatted in RFC3339.
+	CreatedAt string `json:"created_at"`
+
+	// ResolvedAt is formatted in RFC3339. It is only populated for suggestions that
+	// are no longer pending.
+	ResolvedAt *string `json:"resolved_at,omitempty"`
+}
+
+type spotChangeResponse struct {
+	Name        *string  `json:"name,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Locality    *string  `json:"locality,omitempty"`
+	CountryCode *string  `json:"country_code,omitempty"`
+	Region      *string  `json:"region,omitempty"`
+}
+
+func toSpotSuggestionResponse(s surf.SpotSuggestion) spotSuggestionResponse {
+	var resolvedAt *string
+	if s.ResolvedAt != nil {
+		t := formatTime(*s.ResolvedAt)
+		resolvedAt = &t
+	}
+
+	resp := spotSuggestionResponse{
+		ID:     s.ID,
+		SpotID: s.Change.ID,
+		Status: string(s.Status),
+		Changes: spotChangeResponse{
+			Name:      s.Change.Name,
+			Latitude:  s.Change.Latitude,
+			Longitude: s.Change.Longitude,
+		},
+		Note:       s.Note,
+		CreatedAt:  formatTime(s.CreatedAt),
+		ResolvedAt: resolvedAt,
+	}
+
+	// Empty strings of optional location fields mean that they are proposed to be
+	// cleared.
+	if l := s.Change.Locality; l != nil {
+		if *l == "" {
+			resp.Cleared = append(resp.Cleared, "locality")
+		} else {
+			resp.Changes.Locality = l
+		}
+	}
+	if c := s.Change.CountryCode; c != nil {
+		if *c == "" {
+			resp.Cleared = append(resp.Cleared, "country_code")
+		} else {
+			resp.Changes.CountryCode = c
+		}
+	}
+	if r := s.Change.Region; r != nil {
+		if *r == "" {
+			resp.Cleared = append(resp.Cleared, "region")
+		} else {
+			resp.Changes.Region = r
+		}
+	}
+
+	return resp
+}
+
+type spotSuggestionsResponse struct {
+	Items []spotSuggestionResponse `json:"items"`
+}
+
+type auditEntryResponse struct {
+	ID      string                `json:"id"`
+	ActorID string                `json:"actor_id"`
+	Action  string                `json:"action"`
+	SpotID  string                `json:"spot_id"`
+	Changes []auditChangeResponse `json:"changes"`
+
+	// CreatedAt is formatted in RFC3339.
+	CreatedAt string `json:"created_at"`
+}
+
+// auditChangeResponse holds values of a field of a spot before and after it got
+// changed. Values of a spot that does not exist, such as before its creation, are
+// null.
+type auditChangeResponse struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func toAuditEntryResponse(e surf.AuditEntry) auditEntryResponse {
+	resp := auditEntryResponse{
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		Action:    string(e.Action),
+		SpotID:    e.SpotID,
+		Changes:   make([]auditChangeResponse, len(e.Changes)),
+		CreatedAt: formatTime(e.CreatedAt),
+	}
+
+	for i, c := range e.Changes {
+		resp.Changes[i] = auditChangeResponse(c)
+	}
+
+	return resp
+}
+
+type auditEntriesResponse struct {
+	Items []auditEntryResponse `json:"items"`
+}
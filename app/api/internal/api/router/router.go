@@ -1,8 +1,8 @@
 package router
 
 import (
-	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/sirupsen/logrus"
@@ -12,10 +12,28 @@ import (
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
 	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
 	"github.com/ztimes2/tolqin/app/api/pkg/log"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+	"github.com/ztimes2/tolqin/app/api/pkg/openapi"
 )
 
 const (
-	paramKeySpotID = "spot_id"
+	paramKeySpotID       = "spot_id"
+	paramKeyReferenceSet = "reference_set"
+	paramKeyPhotoID      = "photo_id"
+	paramKeySuggestionID = "suggestion_id"
+	paramKeySlug         = "slug"
+
+	// pathMedia is a path under which content of photos is served when the router
+	// is given a media handler.
+	pathMedia = "/media"
+
+	// paramKeyLocalityLanguage is a query parameter for requesting a spot's locality
+	// in another language.
+	paramKeyLocalityLanguage = "locality_language"
+
+	// paramKeyInclude is a query parameter for requesting related resources to be
+	// embedded into a spot.
+	paramKeyInclude = "include"
 )
 
 // New returns an HTTP router that serves various APIs of the application.
@@ -24,9 +42,110 @@ func New(
 	ss *surfing.Service,
 	ms *management.Service,
 	j *jwt.EncodeDecoder,
-	l *logrus.Logger) http.Handler {
+	l *logrus.Logger,
+	opts ...Option) http.Handler {
+
+	return newRouter(as, ss, ms, j, l, opts...)
+}
+
+// Option is an optional function for the router.
+type Option func(*options)
+
+type options struct {
+	cors             CORSConfig
+	metrics          *metrics.Registry
+	responseSizes    *responseSizeMetrics
+	readinessChecks  []readinessCheck
+	readinessTimeout time.Duration
+	requestTimeout   time.Duration
+	dataEnvelopes    map[APIGroup]bool
+	accessLog        bool
+	maxUploadSize    int64
+	mediaHandler     http.Handler
+}
+
+// WithCORS sets a custom Cross-Origin Resource Sharing configuration for the
+// router. By default, cross-origin requests are not allowed.
+func WithCORS(cfg CORSConfig) Option {
+	return func(o *options) {
+		o.cors = cfg
+	}
+}
+
+// WithMetrics enables instrumentation of HTTP requests using the given registry,
+// and exposes its metrics at the /metrics endpoint in the Prometheus text format.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(o *options) {
+		o.metrics = reg
+	}
+}
+
+// WithResponseSizeMetrics additionally records sizes of response bodies per route
+// into a histogram with the given bucket upper bounds in bytes, which helps to find
+// endpoints returning oversized payloads. Default buckets ranging from 256 bytes to
+// 4 megabytes are used when none are given. It has no effect unless WithMetrics is
+// used as well.
+func WithResponseSizeMetrics(buckets ...float64) Option {
+	return func(o *options) {
+		o.responseSizes = &responseSizeMetrics{
+			buckets: buckets,
+		}
+	}
+}
+
+// WithAccessLog enables logging of every handled request using the logger of the
+// router. Responses with 4xx and 5xx status codes are logged at warning and error
+// levels respectively.
+func WithAccessLog() Option {
+	return func(o *options) {
+		o.accessLog = true
+	}
+}
 
-	return newRouter(as, ss, ms, j, l)
+// WithReadinessCheck adds a check of a dependency with the given name to the
+// /readyz endpoint. The endpoint responds with 503 Service Unavailable if at least
+// one of the checks fails.
+func WithReadinessCheck(name string, check ReadinessCheck) Option {
+	return func(o *options) {
+		o.readinessChecks = append(o.readinessChecks, readinessCheck{
+			name:  name,
+			check: check,
+		})
+	}
+}
+
+// WithReadinessTimeout sets a custom timeout for the checks of the /readyz endpoint.
+// By default, the checks time out after 2 seconds.
+func WithReadinessTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.readinessTimeout = d
+	}
+}
+
+// WithRequestTimeout sets a duration after which contexts of requests get canceled,
+// so that requests waiting for slow dependencies respond with 503 Service
+// Unavailable instead of hanging. By default, requests are not bounded.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.requestTimeout = d
+	}
+}
+
+// WithMaxUploadSize sets a custom limit of sizes of uploaded photos in bytes.
+// Requests exceeding it respond with 413 Request Entity Too Large. By default,
+// photos are limited to 10 megabytes.
+func WithMaxUploadSize(n int64) Option {
+	return func(o *options) {
+		o.maxUploadSize = n
+	}
+}
+
+// WithMediaHandler serves content of photos using the given handler under the
+// /media path. By default, the content is expected to be served elsewhere.
+func WithMediaHandler(h http.Handler) Option {
+	return func(o *options) {
+		o.mediaHandler = h
+	}
 }
 
 func newRouter(
@@ -34,7 +153,13 @@ func newRouter(
 	ss surfingService,
 	ms managementService,
 	j *jwt.EncodeDecoder,
-	l *logrus.Logger) http.Handler {
+	l *logrus.Logger,
+	opts ...Option) http.Handler {
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
 
 	router := chi.NewRouter()
 
@@ -42,28 +167,105 @@ func newRouter(
 		w.WriteHeader(http.StatusNotFound)
 	})
 
+	if o.metrics != nil {
+		router.Use(withMetrics(o.metrics, o.responseSizes))
+	}
+
+	router.Use(
+		withCORS(o.cors),
+		withRequestID,
+	)
+
+	if o.accessLog {
+		router.Use(withAccessLog(l))
+	}
+
 	router.Use(
 		withLogger(l),
-		withPanicRecoverer,
+		withMessages,
+		withTimeout(o.requestTimeout),
+		withRecoverer(o.metrics),
 		withJWTClaims(j),
+		withHead,
 	)
 
 	router.Get("/health", handleHealthCheck)
+	router.Get("/healthz", handleHealthCheck)
+
+	rh := newReadinessHandler(o.readinessChecks, o.readinessTimeout)
+	router.Get("/readyz", rh.ready)
+
+	if o.metrics != nil {
+		router.Method(http.MethodGet, "/metrics", metrics.Handler(o.metrics))
+	}
+
+	if o.mediaHandler != nil {
+		router.Method(http.MethodGet, pathMedia+"/*", http.StripPrefix(pathMedia, o.mediaHandler))
+	}
+
+	router.Method(http.MethodGet, pathOpenAPI, openapi.Handler(newSpec(o)))
+	router.Method(http.MethodGet, pathDocs, openapi.DocsHandler(specTitle, pathOpenAPI))
 
 	ah := newAuthHandler(as)
 	router.Post("/auth/v1/token", ah.token)
 
 	sh := newSurfingHandler(ss)
-	router.Get("/surfing/v1/spots", sh.spots)
-	router.Get("/surfing/v1/spots/{"+paramKeySpotID+"}", sh.spot)
+	router.Route("/"+string(APIGroupSurfingV1), func(r chi.Router) {
+		r.Use(withDataEnvelope(o.dataEnvelope(APIGroupSurfingV1)))
+
+		r.Get("/spots", sh.spots)
+		r.Get("/spots/markers", sh.spotMarkers)
+		r.Get("/spots/random", sh.randomSpots)
+		r.Get("/spots/centroid", sh.spotsCentroid)
+		r.Get("/spots/nearby", sh.nearbySpots)
+		r.Get("/spots/route", sh.spotRoute)
+		r.Get("/spots/{"+paramKeySpotID+"}", sh.spot)
+		r.Get("/spots/{"+paramKeySpotID+"}/photos", sh.spotPhotos)
+		r.Get("/spots/{"+paramKeySpotID+"}/forecast", sh.spotForecast)
+		r.Get("/spots/{"+paramKeySpotID+"}/tides", sh.spotTides)
+		r.Post("/spots/{"+paramKeySpotID+"}/ratings", sh.rateSpot)
+		r.Post("/spots/{"+paramKeySpotID+"}/suggestions", sh.suggestSpotChange)
+		r.Put("/spots/{"+paramKeySpotID+"}/favorite", sh.favoriteSpot)
+		r.Delete("/spots/{"+paramKeySpotID+"}/favorite", sh.unfavoriteSpot)
+		r.Get("/favorites", sh.favoriteSpots)
+		r.Post("/favorites/batch", sh.favoriteBatch)
+		r.Get("/countries", sh.countries)
+		r.Get("/directory", sh.directory)
+		r.Get("/localities", sh.localities)
+	})
 
-	mh := newManagementHandler(ms)
-	router.Get("/management/v1/spots", mh.spots)
-	router.Get("/management/v1/spots/{"+paramKeySpotID+"}", mh.spot)
-	router.Post("/management/v1/spots", mh.createSpot)
-	router.Patch("/management/v1/spots/{"+paramKeySpotID+"}", mh.updateSpot)
-	router.Delete("/management/v1/spots/{"+paramKeySpotID+"}", mh.deleteSpot)
-	router.Get("/management/v1/geo/location", mh.location)
+	mh := newManagementHandler(ms, o.maxUploadSize)
+	router.Route("/"+string(APIGroupManagementV1), func(r chi.Router) {
+		r.Use(withDataEnvelope(o.dataEnvelope(APIGroupManagementV1)))
+
+		r.Get("/spots", mh.spots)
+		r.Get("/spots/export", mh.exportSpots)
+		r.Get("/spots/counts-by-country", mh.spotCountsByCountry)
+		r.Get("/spots/{"+paramKeySpotID+"}", mh.spot)
+		r.Get("/spots/slug/{"+paramKeySlug+"}", mh.spotBySlug)
+		r.Post("/spots", mh.createSpot)
+		r.Post("/spots/batch", mh.createSpots)
+		r.Post("/spots/bulk-delete", mh.deleteSpots)
+		r.Patch("/spots/{"+paramKeySpotID+"}", mh.updateSpot)
+		r.Delete("/spots/{"+paramKeySpotID+"}", mh.deleteSpot)
+		r.Post("/spots/{"+paramKeySpotID+"}/restore", mh.restoreSpot)
+		r.Put("/spots/{"+paramKeySpotID+"}/tags", mh.setSpotTags)
+		r.Post("/spots/{"+paramKeySpotID+"}/photos", mh.uploadSpotPhoto)
+		r.Delete("/spots/{"+paramKeySpotID+"}/photos/{"+paramKeyPhotoID+"}", mh.deleteSpotPhoto)
+		r.Get("/spots/{"+paramKeySpotID+"}/location-suggestion", mh.suggestSpotLocation)
+		r.Post("/spots/{"+paramKeySpotID+"}/location-suggestion/apply", mh.applySpotLocationSuggestion)
+		r.Get("/suggestions", mh.spotSuggestions)
+		r.Post("/suggestions/{"+paramKeySuggestionID+"}/approve", mh.approveSpotSuggestion)
+		r.Post("/suggestions/{"+paramKeySuggestionID+"}/reject", mh.rejectSpotSuggestion)
+		r.Get("/audit", mh.auditEntries)
+		r.Get("/countries", mh.countries)
+		r.Get("/localities", mh.localities)
+		r.Get("/stats/spots-over-time", mh.spotsOverTime)
+		r.Get("/geo/location", mh.location)
+		r.Post("/geo/locations", mh.locations)
+		r.Put("/reference-sets/{"+paramKeyReferenceSet+"}", mh.replaceReferenceSet)
+		r.Post("/reference-sets/{"+paramKeyReferenceSet+"}/backfill", mh.backfillReferenceDistances)
+	})
 
 	return router
 }
@@ -71,9 +273,15 @@ func newRouter(
 func withLogger(l *logrus.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// TODO can be improved by setting fields with request details.
 			if l != nil {
-				r = r.WithContext(log.ContextWith(r.Context(), logrus.NewEntry(l)))
+				entry := l.WithFields(logrus.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				})
+				if id := RequestIDFromContext(r.Context()); id != "" {
+					entry = entry.WithField("request_id", id)
+				}
+				r = r.WithContext(log.ContextWith(r.Context(), entry))
 			}
 
 			next.ServeHTTP(w, r)
@@ -81,18 +289,6 @@ func withLogger(l *logrus.Logger) func(http.Handler) http.Handler {
 	}
 }
 
-func withPanicRecoverer(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if rcv := recover(); rcv != nil && rcv != http.ErrAbortHandler {
-			// TODO add stack trace
-			httputil.WriteUnexpectedError(w, r, fmt.Errorf("panic: %s", rcv))
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 func withJWTClaims(j *jwt.EncodeDecoder) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,769 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/openapi"
+)
+
+const (
+	specTitle   = "Tolqin API"
+	specVersion = "v1"
+
+	specSecuritySchemeBearer = "bearerAuth"
+
+	specTagInternal   = "internal"
+	specTagAuth       = "auth"
+	specTagSurfing    = "surfing"
+	specTagManagement = "management"
+
+	pathOpenAPI = "/openapi.json"
+	pathDocs    = "/docs"
+)
+
+// errorEnvelopeSchema mirrors the shape of error responses written by the httputil
+// package, so that its schema can be derived by openapi.SchemaOf.
+type errorEnvelopeSchema struct {
+	Error struct {
+		Code        string `json:"code"`
+		Description string `json:"description"`
+
+		// Fields are only populated for validation errors.
+		Fields []struct {
+			Key    string `json:"key"`
+			Reason string `json:"reason"`
+		} `json:"fields,omitempty"`
+
+		// Dependencies are only populated for unavailability errors.
+		Dependencies []string `json:"dependencies,omitempty"`
+	} `json:"error"`
+}
+
+// specBuilder builds an OpenAPI document describing the routes of the router.
+// Routes are expected to be described here whenever they are registered in
+// newRouter.
+type specBuilder struct {
+	doc *openapi.Document
+	o   options
+}
+
+func newSpec(o options) *openapi.Document {
+	b := &specBuilder{
+		doc: openapi.NewDocument(specTitle, specVersion),
+		o:   o,
+	}
+
+	b.doc.AddSecurityScheme(specSecuritySchemeBearer, openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "Access token issued by POST /auth/v1/token.",
+	})
+
+	b.addInternal()
+	b.addAuth()
+	b.addSurfing()
+	b.addManagement()
+
+	return b.doc
+}
+
+func (b *specBuilder) addInternal() {
+	health := openapi.Operation{
+		Tags:      []string{specTagInternal},
+		Summary:   "Check whether the server is up",
+		Responses: b.responses(true, http.StatusOK, nil),
+	}
+	b.doc.AddOperation(http.MethodGet, "/health", health)
+	b.doc.AddOperation(http.MethodGet, "/healthz", health)
+
+	b.doc.AddOperation(http.MethodGet, "/readyz", openapi.Operation{
+		Tags:      []string{specTagInternal},
+		Summary:   "Check whether dependencies of the server are available",
+		Responses: b.responses(true, http.StatusOK, readinessResponse{}, http.StatusServiceUnavailable),
+	})
+
+	if b.o.metrics != nil {
+		b.doc.AddOperation(http.MethodGet, "/metrics", openapi.Operation{
+			Tags:    []string{specTagInternal},
+			Summary: "Read metrics in the Prometheus text format",
+			Responses: map[string]openapi.Response{
+				strconv.Itoa(http.StatusOK): {
+					Description: http.StatusText(http.StatusOK),
+					Content: map[string]openapi.MediaType{
+						"text/plain": {Schema: openapi.String()},
+					},
+				},
+			},
+		})
+	}
+}
+
+func (b *specBuilder) addAuth() {
+	b.doc.AddOperation(http.MethodPost, "/auth/v1/token", openapi.Operation{
+		Tags:        []string{specTagAuth},
+		Summary:     "Issue an access token",
+		RequestBody: requestBody(tokenPayload{}),
+		Responses:   b.responses(true, http.StatusOK, tokenResponse{}, http.StatusBadRequest),
+	})
+}
+
+func (b *specBuilder) addSurfing() {
+	var (
+		prefix = "/" + string(APIGroupSurfingV1)
+		env    = b.o.dataEnvelope(APIGroupSurfingV1)
+		tags   = []string{specTagSurfing}
+	)
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots", openapi.Operation{
+		Tags:    tags,
+		Summary: "List spots",
+		Parameters: append([]openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of spots."),
+			queryParam("offset", openapi.Integer(), "Number of spots to skip."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("query", openapi.String(), "Search query matched against names and localities."),
+			queryParam("search_mode", enum("substring", "fulltext"), "Search mode of the query."),
+			queryParam("tags", openapi.String(), "Comma-separated list of tags."),
+			queryParam("tags_match", enum("all", "any"), "Whether spots must have all or any of the tags."),
+		}, boundsParams()...),
+		Responses: b.responses(env, http.StatusOK, spotsResponse{}, http.StatusNotModified, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/markers", openapi.Operation{
+		Tags:       tags,
+		Summary:    "List markers of spots within bounds",
+		Parameters: boundsParams(),
+		Responses:  b.responses(env, http.StatusOK, spotMarkersResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/random", openapi.Operation{
+		Tags:    tags,
+		Summary: "List randomly picked spots",
+		Parameters: []openapi.Parameter{
+			queryParam("count", openapi.Integer(), "Number of spots."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotsResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/centroid", openapi.Operation{
+		Tags:    tags,
+		Summary: "Compute the centroid of spots",
+		Parameters: append([]openapi.Parameter{
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("query", openapi.String(), "Search query matched against names and localities."),
+		}, boundsParams()...),
+		Responses: b.responses(env, http.StatusOK, spotsCentroidResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/nearby", openapi.Operation{
+		Tags:    tags,
+		Summary: "List spots near a point",
+		Parameters: []openapi.Parameter{
+			requiredQueryParam("lat", openapi.Number(), "Latitude of the point."),
+			requiredQueryParam("lon", openapi.Number(), "Longitude of the point."),
+			queryParam("radius_km", openapi.Number(), "Search radius in kilometers."),
+			queryParam("limit", openapi.Integer(), "Maximum number of spots."),
+		},
+		Responses: b.responses(env, http.StatusOK, nearbySpotsResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/route", openapi.Operation{
+		Tags:    tags,
+		Summary: "Plan a route across spots ordered by the nearest next spot",
+		Parameters: []openapi.Parameter{
+			requiredQueryParam("ids", openapi.String(), "Comma-separated list of up to 25 spot ids."),
+			queryParam("lat", openapi.Number(), "Latitude of the start point. Defaults to the first spot."),
+			queryParam("lon", openapi.Number(), "Longitude of the start point. Defaults to the first spot."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotRouteResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/{"+paramKeySpotID+"}", openapi.Operation{
+		Tags:    tags,
+		Summary: "Read a spot",
+		Parameters: []openapi.Parameter{
+			spotIDParam(),
+			queryParam(paramKeyLocalityLanguage, openapi.String(),
+				"IETF language tag such as ru or kk. When set, the spot is returned along with "+
+					"its locality in that language looked up by the geocoder as localized_locality."),
+			includeNearbyParam(),
+			nearbyRadiusParam(),
+		},
+		Responses: b.responses(env, http.StatusOK, spotResponse{},
+			http.StatusNotModified, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/{"+paramKeySpotID+"}/photos", openapi.Operation{
+		Tags:       tags,
+		Summary:    "List photos of a spot",
+		Parameters: []openapi.Parameter{spotIDParam()},
+		Responses: b.responses(env, http.StatusOK, spotPhotosResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/{"+paramKeySpotID+"}/forecast", openapi.Operation{
+		Tags:    tags,
+		Summary: "Forecast conditions at a spot",
+		Description: "Daily maximum wave height and period, maximum wind speed and gusts, and their dominant directions " +
+			"starting from today in UTC. Forecasts are cached per spot for a short time.",
+		Parameters: []openapi.Parameter{
+			spotIDParam(),
+			queryParam("days", openapi.Integer(), "Number of days from 1 to 7. Defaults to 3."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotForecastResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/spots/{"+paramKeySpotID+"}/tides", openapi.Operation{
+		Tags:        tags,
+		Summary:     "List high and low tides at a spot",
+		Description: "Tides are listed in chronological order within the given day in UTC. Heights are null when the tide source lacks them.",
+		Parameters: []openapi.Parameter{
+			spotIDParam(),
+			queryParam("date", openapi.String(), "Date in YYYY-MM-DD format within 7 days from today. Defaults to today."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotTidesResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable),
+	})
+
+	b.doc.AddOperation(http.MethodPost, prefix+"/spots/{"+paramKeySpotID+"}/ratings", openapi.Operation{
+		Tags:        tags,
+		Summary:     "Rate a spot",
+		Description: "The score must be from 1 to 5. Every client counts once a day per spot, so rating the same spot again on the same day replaces the previous score.",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: requestBody(rateSpotPayload{}),
+		Responses:   b.responses(env, http.StatusOK, spotRatingsResponse{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	b.doc.AddOperation(http.MethodPost, prefix+"/spots/{"+paramKeySpotID+"}/suggestions", openapi.Operation{
+		Tags:    tags,
+		Summary: "Suggest a change of a spot",
+		Description: "Proposed values are validated in the same way as updates of spots, and get applied only once an admin approves them. " +
+			"Setting locality, country_code, or region to null proposes to clear them.",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: requestBody(suggestSpotChangePayload{}),
+		Responses:   b.responses(env, http.StatusCreated, spotSuggestionResponse{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	bearer := []openapi.SecurityRequirement{{specSecuritySchemeBearer: {}}}
+
+	b.doc.AddOperation(http.MethodPut, prefix+"/spots/{"+paramKeySpotID+"}/favorite", openapi.Operation{
+		Tags:        tags,
+		Summary:     "Favorite a spot",
+		Description: "Favoriting a spot that is already favorited has no effect.",
+		Security:    bearer,
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		Responses: b.responses(env, http.StatusNoContent, nil,
+			http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound),
+	})
+
+	b.doc.AddOperation(http.MethodDelete, prefix+"/spots/{"+paramKeySpotID+"}/favorite", openapi.Operation{
+		Tags:        tags,
+		Summary:     "Unfavorite a spot",
+		Description: "Unfavoriting a spot that is not favorited has no effect.",
+		Security:    bearer,
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		Responses:   b.responses(env, http.StatusNoContent, nil, http.StatusBadRequest, http.StatusUnauthorized),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/favorites", openapi.Operation{
+		Tags:        tags,
+		Summary:     "List favorite spots",
+		Description: "Spots are listed from the most recently favorited one.",
+		Security:    bearer,
+		Parameters: []openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of spots."),
+			queryParam("offset", openapi.Integer(), "Number of spots to skip."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotsResponse{}, http.StatusBadRequest, http.StatusUnauthorized),
+	})
+
+	b.doc.AddOperation(http.MethodPost, prefix+"/favorites/batch", openapi.Operation{
+		Tags:        tags,
+		Summary:     "Favorite and unfavorite multiple spots",
+		Description: "Up to 100 spots are applied at once in one transaction. Spots to favorite that don't exist are reported by their ids instead of failing the request.",
+		Security:    bearer,
+		RequestBody: requestBody(favoriteBatchPayload{}),
+		Responses:   b.responses(env, http.StatusOK, favoriteBatchResponse{}, http.StatusBadRequest, http.StatusUnauthorized),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/countries", openapi.Operation{
+		Tags:    tags,
+		Summary: "List countries with spot counts",
+		Parameters: []openapi.Parameter{
+			queryParam("query", openapi.String(), "Search query matched against names and localities of spots."),
+		},
+		Responses: b.responses(env, http.StatusOK, countriesResponse{}, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/directory", openapi.Operation{
+		Tags:    tags,
+		Summary: "List spots grouped by countries and localities",
+		Parameters: []openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of countries."),
+			queryParam("offset", openapi.Integer(), "Number of countries to skip."),
+			queryParam("localities_limit", openapi.Integer(), "Maximum number of localities of each country."),
+			queryParam("spots_limit", openapi.Integer(), "Maximum number of spots of each locality."),
+		},
+		Responses: b.responses(env, http.StatusOK, directoryResponse{}, http.StatusNotModified, http.StatusBadRequest),
+	})
+
+	b.doc.AddOperation(http.MethodGet, prefix+"/localities", openapi.Operation{
+		Tags:    tags,
+		Summary: "List localities of spots",
+		Parameters: []openapi.Parameter{
+			queryParam("prefix", openapi.String(), "Prefix of localities of at least 2 characters."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("limit", openapi.Integer(), "Maximum number of localities."),
+		},
+		Responses: b.responses(env, http.StatusOK, localitiesResponse{}, http.StatusBadRequest),
+	})
+}
+
+func (b *specBuilder) addManagement() {
+	var (
+		prefix   = "/" + string(APIGroupManagementV1)
+		env      = b.o.dataEnvelope(APIGroupManagementV1)
+		spotPath = prefix + "/spots/{" + paramKeySpotID + "}"
+	)
+
+	add := func(method, path string, op openapi.Operation) {
+		op.Tags = []string{specTagManagement}
+		op.Security = []openapi.SecurityRequirement{{specSecuritySchemeBearer: {}}}
+		b.doc.AddOperation(method, path, op)
+	}
+
+	add(http.MethodGet, prefix+"/spots", openapi.Operation{
+		Summary: "List spots",
+		Parameters: append([]openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of spots."),
+			queryParam("offset", openapi.Integer(), "Number of spots to skip."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("query", openapi.String(), "Search query matched against names and localities."),
+			queryParam("search_mode", enum("substring", "fulltext"), "Search mode of the query."),
+			queryParam("include_deleted", openapi.Boolean(), "Whether to include soft-deleted spots."),
+			queryParam("sort", enum("-updated_at", "reference_distance"), "Order of spots."),
+			queryParam(paramKeyReferenceSet, openapi.String(),
+				"Reference set to read distances of spots to, which is required for sorting by reference_distance."),
+			queryParam("tags", openapi.String(), "Comma-separated list of tags."),
+			queryParam("tags_match", enum("all", "any"), "Whether spots must have all or any of the tags."),
+			queryParam("debug", enum(debugModeExplain), "Capture a query plan alongside spots."),
+		}, boundsParams()...),
+		Responses: b.responses(env, http.StatusOK, spotsResponse{}, http.StatusNotModified, http.StatusBadRequest),
+	})
+
+	geoJSONSchema := openapi.Object(map[string]*openapi.Schema{
+		"type":     enum("FeatureCollection"),
+		"features": openapi.ArrayOf(openapi.SchemaOf(geoJSONFeature{})),
+	})
+	add(http.MethodGet, prefix+"/spots/export", openapi.Operation{
+		Summary: "Export spots as a file",
+		Parameters: append([]openapi.Parameter{
+			queryParam("format", enum(exportFormatCSV, exportFormatGeoJSON), "Format of the file."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("query", openapi.String(), "Search query matched against names and localities."),
+		}, boundsParams()...),
+		Responses: map[string]openapi.Response{
+			strconv.Itoa(http.StatusOK): {
+				Description: http.StatusText(http.StatusOK),
+				Content: map[string]openapi.MediaType{
+					(&csvSpotEncoder{}).contentType():     {Schema: openapi.String()},
+					(&geoJSONSpotEncoder{}).contentType(): {Schema: geoJSONSchema},
+				},
+			},
+			strconv.Itoa(http.StatusBadRequest): errorResponse(http.StatusBadRequest),
+			"default":                           errorResponse(http.StatusInternalServerError),
+		},
+	})
+
+	add(http.MethodGet, prefix+"/spots/counts-by-country", openapi.Operation{
+		Summary:     "Count spots per country",
+		Description: "Spots without a country code are counted under zz, the code of an unknown region.",
+		Responses:   b.responses(env, http.StatusOK, countriesResponse{}),
+	})
+
+	add(http.MethodGet, spotPath, openapi.Operation{
+		Summary:    "Read a spot",
+		Parameters: []openapi.Parameter{spotIDParam(), includeNearbyParam(), nearbyRadiusParam()},
+		Responses: b.responses(env, http.StatusOK, spotResponse{},
+			http.StatusNotModified, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	add(http.MethodGet, prefix+"/spots/slug/{"+paramKeySlug+"}", openapi.Operation{
+		Summary:     "Read a spot by its slug",
+		Description: "Slugs are generated from names and localities of spots, and matched case-insensitively.",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        paramKeySlug,
+				In:          openapi.InPath,
+				Description: "Slug of the spot.",
+				Required:    true,
+				Schema:      openapi.String(),
+			},
+		},
+		Responses: b.responses(env, http.StatusOK, spotResponse{},
+			http.StatusNotModified, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	add(http.MethodPost, prefix+"/spots", openapi.Operation{
+		Summary: "Create a spot",
+		Description: "Repeating a request with the same Idempotency-Key header within its TTL responds " +
+			"with the spot created by the first request instead of creating a new one.",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        headerIdempotencyKey,
+				In:          openapi.InHeader,
+				Description: "Unique key of the creation of at most 255 characters.",
+				Schema:      openapi.String(),
+			},
+		},
+		RequestBody: requestBody(createSpotPayload{}),
+		Responses:   b.responses(env, http.StatusCreated, spotResponse{}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+
+	createSpotsResponses := b.responses(env, http.StatusCreated, createSpotsResponse{}, http.StatusBadRequest)
+	createSpotsResponses[strconv.Itoa(http.StatusMultiStatus)] = b.response(env, http.StatusMultiStatus, createSpotsResponse{})
+	add(http.MethodPost, prefix+"/spots/batch", openapi.Operation{
+		Summary:     "Create multiple spots",
+		Description: "Responds with 207 Multi-Status when some of the spots are invalid.",
+		RequestBody: requestBody([]createSpotPayload{}),
+		Responses:   createSpotsResponses,
+	})
+
+	add(http.MethodPost, prefix+"/spots/bulk-delete", openapi.Operation{
+		Summary:     "Delete multiple spots",
+		RequestBody: requestBody([]string{}),
+		Responses:   b.responses(env, http.StatusOK, deleteSpotsResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodPatch, spotPath, openapi.Operation{
+		Summary: "Update a spot",
+		Description: "Absent fields are left unchanged. Setting locality, country_code, or region to null clears it. " +
+			"Fields are set to the given values rather than changed relative to the current ones, so a repeated " +
+			"request leaves the spot as the first one did and needs no Idempotency-Key header.",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: requestBody(updateSpotPayload{}),
+		Responses: b.responses(env, http.StatusOK, spotResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusGatewayTimeout),
+	})
+
+	add(http.MethodDelete, spotPath, openapi.Operation{
+		Summary: "Delete a spot",
+		Description: "A repeated request responds with 404 once the spot has been deleted, which clients " +
+			"can treat as done, so no Idempotency-Key header is needed.",
+		Parameters: []openapi.Parameter{spotIDParam()},
+		Responses:  b.responses(env, http.StatusNoContent, nil, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	add(http.MethodPost, spotPath+"/restore", openapi.Operation{
+		Summary:    "Restore a soft-deleted spot",
+		Parameters: []openapi.Parameter{spotIDParam()},
+		Responses:  b.responses(env, http.StatusOK, spotResponse{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	add(http.MethodPut, spotPath+"/tags", openapi.Operation{
+		Summary:     "Replace tags of a spot",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: requestBody(setSpotTagsPayload{}),
+		Responses:   b.responses(env, http.StatusOK, spotResponse{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	add(http.MethodPost, spotPath+"/photos", openapi.Operation{
+		Summary:     "Upload a photo of a spot",
+		Description: "The photo must be a JPEG, PNG, or WebP image sent as the photo field of a multipart form.",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"multipart/form-data": {Schema: openapi.Object(map[string]*openapi.Schema{
+					formKeyPhoto: {Type: "string", Format: "binary"},
+				})},
+			},
+		},
+		Responses: b.responses(env, http.StatusCreated, spotPhotoResponse{}, http.StatusBadRequest,
+			http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusServiceUnavailable),
+	})
+
+	add(http.MethodDelete, spotPath+"/photos/{"+paramKeyPhotoID+"}", openapi.Operation{
+		Summary: "Delete a photo of a spot",
+		Parameters: []openapi.Parameter{
+			spotIDParam(),
+			{
+				Name:        paramKeyPhotoID,
+				In:          openapi.InPath,
+				Description: "ID of the photo.",
+				Required:    true,
+				Schema:      openapi.String(),
+			},
+		},
+		Responses: b.responses(env, http.StatusNoContent, nil,
+			http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable),
+	})
+
+	add(http.MethodGet, spotPath+"/location-suggestion", openapi.Operation{
+		Summary:    "Suggest a location of a spot by its coordinates",
+		Parameters: []openapi.Parameter{spotIDParam()},
+		Responses: b.responses(env, http.StatusOK, spotLocationSuggestionResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusGatewayTimeout),
+	})
+
+	add(http.MethodPost, spotPath+"/location-suggestion/apply", openapi.Operation{
+		Summary:     "Apply a suggested location to a spot",
+		Parameters:  []openapi.Parameter{spotIDParam()},
+		RequestBody: requestBody(applySpotLocationSuggestionPayload{}),
+		Responses: b.responses(env, http.StatusOK, spotResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusGatewayTimeout),
+	})
+
+	suggestionPath := prefix + "/suggestions/{" + paramKeySuggestionID + "}"
+	suggestionIDParam := openapi.Parameter{
+		Name:        paramKeySuggestionID,
+		In:          openapi.InPath,
+		Description: "ID of the suggestion.",
+		Required:    true,
+		Schema:      openapi.String(),
+	}
+
+	add(http.MethodGet, prefix+"/suggestions", openapi.Operation{
+		Summary:     "List suggested changes of spots",
+		Description: "Suggestions are listed from the most recently suggested one.",
+		Parameters: []openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of suggestions."),
+			queryParam("offset", openapi.Integer(), "Number of suggestions to skip."),
+			queryParam("status", enum(
+				string(surf.SpotSuggestionStatusPending),
+				string(surf.SpotSuggestionStatusApplied),
+				string(surf.SpotSuggestionStatusRejected),
+			), "Status of suggestions."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotSuggestionsResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodPost, suggestionPath+"/approve", openapi.Operation{
+		Summary:     "Approve a suggested change of a spot",
+		Description: "The change is applied to the spot in the same way as updates of spots.",
+		Parameters:  []openapi.Parameter{suggestionIDParam},
+		Responses: b.responses(env, http.StatusOK, spotSuggestionResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusGatewayTimeout),
+	})
+
+	add(http.MethodPost, suggestionPath+"/reject", openapi.Operation{
+		Summary:    "Reject a suggested change of a spot",
+		Parameters: []openapi.Parameter{suggestionIDParam},
+		Responses: b.responses(env, http.StatusOK, spotSuggestionResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+
+	add(http.MethodGet, prefix+"/audit", openapi.Operation{
+		Summary: "List the audit log of spots",
+		Description: "Creations, updates, deletions, and restorations of spots are listed from the most recent one. " +
+			"With format=csv, entries are returned as a CSV attachment instead, with changes encoded as JSON.",
+		Parameters: []openapi.Parameter{
+			queryParam("limit", openapi.Integer(), "Maximum number of entries."),
+			queryParam("offset", openapi.Integer(), "Number of entries to skip."),
+			queryParam(paramKeySpotID, openapi.String(), "ID of the spot whose entries to list."),
+			queryParam("actor", openapi.String(), "ID of the admin whose entries to list."),
+			queryParam("from", dateTime(), "Inclusive start of the period in RFC3339 to list entries of."),
+			queryParam("to", dateTime(), "Exclusive end of the period in RFC3339 to list entries of."),
+			queryParam("format", openapi.String(), "Format of the response: json (default) or csv."),
+		},
+		Responses: b.responses(env, http.StatusOK, auditEntriesResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodGet, prefix+"/countries", openapi.Operation{
+		Summary: "List countries with spot counts",
+		Parameters: []openapi.Parameter{
+			queryParam("query", openapi.String(), "Search query matched against names and localities of spots."),
+		},
+		Responses: b.responses(env, http.StatusOK, countriesResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodGet, prefix+"/localities", openapi.Operation{
+		Summary: "List localities of spots",
+		Parameters: []openapi.Parameter{
+			queryParam("prefix", openapi.String(), "Prefix of localities of at least 2 characters."),
+			queryParam("country", openapi.String(), "ISO-2 country code."),
+			queryParam("limit", openapi.Integer(), "Maximum number of localities."),
+		},
+		Responses: b.responses(env, http.StatusOK, localitiesResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodGet, prefix+"/stats/spots-over-time", openapi.Operation{
+		Summary: "Count created spots over time",
+		Parameters: []openapi.Parameter{
+			requiredQueryParam("from", dateTime(), "Start of the period in RFC3339."),
+			requiredQueryParam("to", dateTime(), "End of the period in RFC3339."),
+			queryParam("interval", enum("day", "week", "month"), "Size of buckets."),
+		},
+		Responses: b.responses(env, http.StatusOK, spotsOverTimeResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodGet, prefix+"/geo/location", openapi.Operation{
+		Summary: "Look up a location by coordinates",
+		Parameters: []openapi.Parameter{
+			requiredQueryParam("lat", openapi.Number(), "Latitude."),
+			requiredQueryParam("lon", openapi.Number(), "Longitude."),
+		},
+		Responses: b.responses(env, http.StatusOK, locationResponse{},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable, http.StatusGatewayTimeout),
+	})
+
+	locationsResponses := b.responses(env, http.StatusOK, locationsResponse{},
+		http.StatusBadRequest, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+	locationsResponses[strconv.Itoa(http.StatusMultiStatus)] = b.response(env, http.StatusMultiStatus, locationsResponse{})
+	add(http.MethodPost, prefix+"/geo/locations", openapi.Operation{
+		Summary: "Look up locations of multiple coordinates",
+		Description: "Locations are listed in the same order as the coordinates. Responds with 207 Multi-Status " +
+			"when some of the coordinates are invalid or have no location.",
+		RequestBody: requestBody([]coordinatesPayload{}),
+		Responses:   locationsResponses,
+	})
+
+	referenceSetPath := prefix + "/reference-sets/{" + paramKeyReferenceSet + "}"
+	referenceSetParam := openapi.Parameter{
+		Name:        paramKeyReferenceSet,
+		In:          openapi.InPath,
+		Description: "Name of the reference set, such as airports or cities.",
+		Required:    true,
+		Schema:      openapi.String(),
+	}
+
+	add(http.MethodPut, referenceSetPath, openapi.Operation{
+		Summary:     "Replace points of a reference set",
+		Description: "Distances of all spots to the nearest points of the set get backfilled as well.",
+		Parameters:  []openapi.Parameter{referenceSetParam},
+		RequestBody: requestBody(replaceReferenceSetPayload{}),
+		Responses:   b.responses(env, http.StatusOK, backfillReferenceDistancesResponse{}, http.StatusBadRequest),
+	})
+
+	add(http.MethodPost, referenceSetPath+"/backfill", openapi.Operation{
+		Summary:    "Backfill distances of spots to a reference set",
+		Parameters: []openapi.Parameter{referenceSetParam},
+		Responses: b.responses(env, http.StatusOK, backfillReferenceDistancesResponse{},
+			http.StatusBadRequest, http.StatusNotFound),
+	})
+}
+
+// responses returns responses of an operation that succeeds with the given status
+// and data, and fails with the given statuses. Data can be nil for successful
+// responses without a body. Statuses below 400 among the failing ones are treated
+// as responses without a body, such as 304 Not Modified.
+func (b *specBuilder) responses(
+	enveloped bool,
+	status int,
+	data interface{},
+	otherStatuses ...int) map[string]openapi.Response {
+
+	resps := map[string]openapi.Response{
+		strconv.Itoa(status): b.response(enveloped, status, data),
+		"default":            errorResponse(http.StatusInternalServerError),
+	}
+
+	for _, s := range otherStatuses {
+		if s < http.StatusBadRequest {
+			resps[strconv.Itoa(s)] = openapi.Response{
+				Description: http.StatusText(s),
+			}
+			continue
+		}
+		resps[strconv.Itoa(s)] = errorResponse(s)
+	}
+
+	return resps
+}
+
+func (b *specBuilder) response(enveloped bool, status int, data interface{}) openapi.Response {
+	resp := openapi.Response{
+		Description: http.StatusText(status),
+	}
+	if data == nil {
+		return resp
+	}
+
+	schema := openapi.SchemaOf(data)
+	if enveloped {
+		schema = openapi.Object(map[string]*openapi.Schema{
+			"data": schema,
+		})
+	}
+	resp.Content = openapi.JSON(schema)
+
+	return resp
+}
+
+func errorResponse(status int) openapi.Response {
+	desc := http.StatusText(status)
+	if status == http.StatusInternalServerError {
+		desc = "Unexpected error"
+	}
+	return openapi.Response{
+		Description: desc,
+		Content:     openapi.JSON(openapi.SchemaOf(errorEnvelopeSchema{})),
+	}
+}
+
+func requestBody(payload interface{}) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.SchemaOf(payload)),
+	}
+}
+
+func spotIDParam() openapi.Parameter {
+	return openapi.Parameter{
+		Name:        paramKeySpotID,
+		In:          openapi.InPath,
+		Description: "ID of the spot.",
+		Required:    true,
+		Schema:      openapi.String(),
+	}
+}
+
+func includeNearbyParam() openapi.Parameter {
+	return queryParam(paramKeyInclude, enum(includeNearby),
+		"When set to nearby, the spot is returned along with up to 10 spots closest to it as nearby.")
+}
+
+func nearbyRadiusParam() openapi.Parameter {
+	return queryParam("radius_km", openapi.Number(), "Search radius of nearby spots in kilometers.")
+}
+
+func queryParam(name string, s *openapi.Schema, desc string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          openapi.InQuery,
+		Description: desc,
+		Schema:      s,
+	}
+}
+
+func requiredQueryParam(name string, s *openapi.Schema, desc string) openapi.Parameter {
+	p := queryParam(name, s, desc)
+	p.Required = true
+	return p
+}
+
+func boundsParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		queryParam("ne_lat", openapi.Number(), "Latitude of the north-east corner of bounds."),
+		queryParam("ne_lon", openapi.Number(), "Longitude of the north-east corner of bounds."),
+		queryParam("sw_lat", openapi.Number(), "Latitude of the south-west corner of bounds."),
+		queryParam("sw_lon", openapi.Number(), "Longitude of the south-west corner of bounds."),
+	}
+}
+
+func enum(values ...string) *openapi.Schema {
+	s := openapi.String()
+	s.Enum = values
+	return s
+}
+
+func dateTime() *openapi.Schema {
+	s := openapi.String()
+	s.Format = "date-time"
+	return s
+}
@@ -0,0 +1,123 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+	"github.com/ztimes2/tolqin/app/api/pkg/openapi"
+)
+
+func TestSpec_DescribesRoutes(t *testing.T) {
+	opts := []Option{WithMetrics(metrics.NewRegistry())}
+
+	r, ok := newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil, opts...).(chi.Routes)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	spec := newSpec(o)
+
+	registered := make(map[string]bool)
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route == pathOpenAPI || route == pathDocs {
+			return nil
+		}
+
+		registered[method+" "+route] = true
+
+		_, ok := spec.Operation(method, route)
+		assert.True(t, ok, "route %s %s is not described in the spec", method, route)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	for path, item := range spec.Paths {
+		for method := range item {
+			key := strings.ToUpper(method) + " " + path
+			assert.True(t, registered[key], "spec describes unregistered route %s", key)
+		}
+	}
+}
+
+func TestSpec_DataEnvelope(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []Option
+		expectedEnvelope bool
+	}{
+		{
+			name:             "wrap response schemas in data envelope by default",
+			opts:             nil,
+			expectedEnvelope: true,
+		},
+		{
+			name:             "describe response schemas without data envelope",
+			opts:             []Option{WithDataEnvelope(APIGroupSurfingV1, false)},
+			expectedEnvelope: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var o options
+			for _, opt := range test.opts {
+				opt(&o)
+			}
+
+			op, ok := newSpec(o).Operation(http.MethodGet, "/surfing/v1/spots/{spot_id}")
+			if !assert.True(t, ok) {
+				return
+			}
+
+			schema := op.Responses["200"].Content["application/json"].Schema
+			if test.expectedEnvelope {
+				assert.Equal(t, []string{"data"}, schema.Required)
+				schema = schema.Properties["data"]
+			}
+			assert.Equal(t, openapi.String(), schema.Properties["id"])
+			assert.Contains(t, schema.Required, "id")
+			assert.NotContains(t, schema.Required, "tags")
+
+			errSchema := op.Responses["404"].Content["application/json"].Schema
+			assert.Contains(t, errSchema.Properties["error"].Properties, "fields")
+		})
+	}
+}
+
+func TestSpecEndpoints(t *testing.T) {
+	server := httptest.NewServer(newRouter(nil, newMockSurfingService(), newMockManagementService(), nil, nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + pathOpenAPI)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var doc openapi.Document
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/auth/v1/token")
+
+	resp, err = http.Get(server.URL + pathDocs)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+}
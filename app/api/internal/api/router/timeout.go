@@ -0,0 +1,25 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// withTimeout cancels contexts of requests that take longer than the given
+// duration, so that handlers waiting for dependencies give up and respond with
+// an error. Requests are not bounded when the duration is not positive.
+func withTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
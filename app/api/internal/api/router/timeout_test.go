@@ -0,0 +1,129 @@
+package router
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+)
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		timeout          time.Duration
+		expectedDeadline bool
+	}{
+		{
+			name:             "set deadline for positive timeout",
+			timeout:          time.Minute,
+			expectedDeadline: true,
+		},
+		{
+			name:             "set no deadline for zero timeout",
+			timeout:          0,
+			expectedDeadline: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var hasDeadline bool
+			h := withTimeout(test.timeout)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+			}))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expectedDeadline, hasDeadline)
+		})
+	}
+}
+
+// sleepingLocationSource is a geo.LocationSource that responds only after a delay.
+type sleepingLocationSource struct {
+	delay time.Duration
+}
+
+func (s sleepingLocationSource) Location(c geo.Coordinates) (geo.Location, error) {
+	time.Sleep(s.delay)
+	return geo.Location{Coordinates: c}, nil
+}
+
+func TestRouter_SlowLocationSource(t *testing.T) {
+	const sourceDelay = 2 * time.Second
+
+	tests := []struct {
+		name               string
+		serviceOpts        []management.Option
+		routerOpts         []Option
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "respond with 504 status code once location source times out",
+			serviceOpts:        []management.Option{management.WithLocationTimeout(50 * time.Millisecond)},
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedBody: `{
+				"error": {
+					"code": "upstream_timeout",
+					"description": "Geocoder took too long to respond."
+				}
+			}`,
+		},
+		{
+			name:               "respond with 503 status code once request times out",
+			serviceOpts:        []management.Option{management.WithLocationTimeout(0)},
+			routerOpts:         []Option{WithRequestTimeout(50 * time.Millisecond)},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody: `{
+				"error": {
+					"code": "timeout",
+					"description": "Request timed out."
+				}
+			}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := jwt.NewEncodeDecoder("secret", time.Hour)
+			token, err := j.EncodeJWT(auth.User{ID: "1", Role: auth.RoleAdmin})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ms := management.NewService(nil, sleepingLocationSource{delay: sourceDelay}, test.serviceOpts...)
+
+			logger, _ := newTestLogger()
+			server := httptest.NewServer(newRouter(nil, newMockSurfingService(), ms, j, logger, test.routerOpts...))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/management/v1/geo/location?lat=1.23&lon=3.21", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			start := time.Now()
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Less(t, int64(time.Since(start)), int64(sourceDelay))
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedBody, string(body))
+		})
+	}
+}
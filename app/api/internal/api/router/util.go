@@ -2,17 +2,49 @@ package router
 
 import (
 	"errors"
+	"io"
+	"math"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/pkg/httputil"
+	"github.com/ztimes2/tolqin/app/api/pkg/log"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
+const headerRetryAfter = "Retry-After"
+
+// includeNearby is a value of the include query parameter for embedding nearby
+// spots into a spot.
+const includeNearby = "nearby"
+
+// msgInvalidInclude describes values of the include query parameter.
+const msgInvalidInclude = "Must be one of: nearby."
+
+const (
+	// formKeyPhoto is a key of a multipart form field holding content of a photo.
+	formKeyPhoto = "photo"
+
+	// defaultMaxUploadSize limits sizes of uploaded photos unless configured
+	// otherwise.
+	defaultMaxUploadSize = 10 << 20
+
+	// multipartOverhead is allowed on top of the size of a photo for boundaries,
+	// headers, and other fields of a multipart form.
+	multipartOverhead = 64 << 10
+)
+
 var (
 	errInvalidNorthEastLatitude  = errors.New("invalid north-east latitude")
 	errInvalidNorthEastLongitude = errors.New("invalid north-east longitude")
 	errInvalidSouthWestLatitude  = errors.New("invalid south-west latitude")
 	errInvalidSouthWestLongitude = errors.New("invalid south-west longitude")
+	errInvalidInclude            = errors.New("invalid include")
+	errInvalidMultipartForm      = errors.New("invalid multipart form")
+	errMissingPhoto              = errors.New("missing photo")
+	errPayloadTooLarge           = errors.New("payload too large")
 )
 
 func parseBounds(neLat, neLon, swLat, swLon string) (*geo.Bounds, *valerra.Errors) {
@@ -52,3 +84,139 @@ func parseBounds(neLat, neLon, swLat, swLon string) (*geo.Bounds, *valerra.Error
 
 	return nil, valerra.NewErrors(errs...)
 }
+
+// includesNearby reports whether nearby spots are requested to be embedded via the
+// include query parameter of the given request. errInvalidInclude is returned when
+// the parameter contains anything else.
+func includesNearby(r *http.Request) (bool, error) {
+	values := httputil.QueryParamList(r, paramKeyInclude)
+	for _, v := range values {
+		if v != includeNearby {
+			return false, errInvalidInclude
+		}
+	}
+	return len(values) > 0, nil
+}
+
+// writeDuplicateSpotError writes a 409 Conflict HTTP status code and an error
+// using 'duplicate_spot' error code to the response.
+func writeDuplicateSpotError(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteError(w, r, http.StatusConflict, "duplicate_spot", "Such spot already exists.")
+}
+
+// writeGeocoderUnavailableError writes a 503 Service Unavailable HTTP status code
+// and an error using 'geocoder_unavailable' error code to the response, along with
+// a Retry-After header of the given duration rounded up to whole seconds.
+func writeGeocoderUnavailableError(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set(headerRetryAfter, strconv.Itoa(seconds))
+
+	httputil.WriteError(w, r, http.StatusServiceUnavailable, "geocoder_unavailable", "Geocoder is temporarily unavailable.")
+}
+
+// writeGeocoderTimeoutError writes a 504 Gateway Timeout HTTP status code and an
+// error telling that the geocoder took too long to respond.
+func writeGeocoderTimeoutError(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteUpstreamTimeoutError(w, r, "Geocoder took too long to respond.")
+}
+
+// writePhotosUnavailableError writes a 503 Service Unavailable HTTP status code and
+// an error using 'photos_unavailable' error code to the response.
+func writePhotosUnavailableError(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteError(w, r, http.StatusServiceUnavailable, "photos_unavailable", "Photos are unavailable.")
+}
+
+// writeForecastUnavailableError writes a 503 Service Unavailable HTTP status code
+// and an error using 'forecast_unavailable' error code to the response. The given
+// error is logged as a warning, since failures of the forecast source are not
+// failures of the API itself.
+func writeForecastUnavailableError(w http.ResponseWriter, r *http.Request, err error) {
+	if logger := log.FromContext(r.Context()); logger != nil {
+		logger.WithError(err).Warnf("forecast is unavailable: %s", err)
+	}
+
+	httputil.WriteError(w, r, http.StatusServiceUnavailable, "forecast_unavailable", "Forecast is unavailable.")
+}
+
+// writeTidesUnavailableError writes a 503 Service Unavailable HTTP status code and
+// an error using 'tides_unavailable' error code to the response. The given error
+// is logged as a warning in the same way as for forecasts.
+func writeTidesUnavailableError(w http.ResponseWriter, r *http.Request, err error) {
+	if logger := log.FromContext(r.Context()); logger != nil {
+		logger.WithError(err).Warnf("tides are unavailable: %s", err)
+	}
+
+	httputil.WriteError(w, r, http.StatusServiceUnavailable, "tides_unavailable", "Tides are unavailable.")
+}
+
+// readPhoto reads content of a photo from the field of a multipart form of the
+// given request. errPayloadTooLarge is returned when the photo is larger than the
+// given size in bytes, errMissingPhoto when the form has no such field, and
+// errInvalidMultipartForm when the request body is not a multipart form.
+func readPhoto(r *http.Request, maxSize int64) ([]byte, error) {
+	body := &limitedBody{
+		r: r.Body,
+		n: maxSize + multipartOverhead,
+	}
+	defer r.Body.Close()
+	r.Body = io.NopCloser(body)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, errInvalidMultipartForm
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errMissingPhoto
+		}
+		if err != nil {
+			if body.exceeded {
+				return nil, errPayloadTooLarge
+			}
+			return nil, errInvalidMultipartForm
+		}
+
+		if part.FormName() != formKeyPhoto {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+		if err != nil {
+			if body.exceeded {
+				return nil, errPayloadTooLarge
+			}
+			return nil, errInvalidMultipartForm
+		}
+		if int64(len(content)) > maxSize {
+			return nil, errPayloadTooLarge
+		}
+		return content, nil
+	}
+}
+
+// limitedBody reads up to n bytes from the underlying reader and fails afterwards,
+// recording that the limit was exceeded. Unlike http.MaxBytesReader, it allows to
+// tell the limit apart from other errors which multipart.Reader does not wrap.
+type limitedBody struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.n <= 0 {
+		b.exceeded = true
+		return 0, errPayloadTooLarge
+	}
+	if int64(len(p)) > b.n {
+		p = p[:b.n]
+	}
+	n, err := b.r.Read(p)
+	b.n -= int64(n)
+	return n, err
+}
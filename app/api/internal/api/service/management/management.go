@@ -1,17 +1,24 @@
 package management
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/media"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
 	"github.com/ztimes2/tolqin/app/api/pkg/paging"
 	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
+	"github.com/ztimes2/tolqin/app/api/pkg/slug"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
@@ -23,6 +30,81 @@ const (
 	minOffset = 0
 
 	maxSearchQueryChars = 100
+
+	// maxDeleteSpotIDs caps a number of spots that can be deleted at once.
+	maxDeleteSpotIDs = 100
+
+	// maxCreateSpotEntries caps a number of spots that can be created at once.
+	maxCreateSpotEntries = 100
+
+	// maxLocationCoordinates caps a number of coordinates whose locations can be
+	// looked up at once.
+	maxLocationCoordinates = 50
+
+	// defaultLocationsConcurrency caps a number of locations of a batch that are
+	// looked up at the same time, so that the batch does not overwhelm the geocoder.
+	defaultLocationsConcurrency = 4
+
+	maxTags     = 10
+	maxTagChars = 30
+
+	// minLocalityPrefixChars prevents autocompleting localities by a prefix that
+	// is too short to narrow them down.
+	minLocalityPrefixChars = 2
+	maxLocalitiesLimit     = 50
+
+	maxReferenceSetChars       = 50
+	maxReferencePoints         = 1000
+	maxReferencePointNameChars = 100
+
+	maxNearbyRadiusKm     = 500
+	defaultNearbyRadiusKm = 50
+
+	// maxSpotNearbyCount caps a number of nearby spots embedded into a spot.
+	maxSpotNearbyCount = 10
+
+	// defaultIdempotencyKeyTTL is how long spots created with idempotency keys
+	// are replayed for retries.
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	maxIdempotencyKeyChars   = 255
+
+	// defaultLocationTimeout bounds calls of the location source, which is meant
+	// to be shorter than a timeout of a whole request.
+	defaultLocationTimeout = 3 * time.Second
+
+	// IntervalDay, IntervalWeek, and IntervalMonth are used for aggregating spots
+	// by days, weeks, and months respectively.
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+
+	// SearchModeSubstring is used for searching for spots whose names, localities,
+	// or IDs contain the search query.
+	SearchModeSubstring = "substring"
+
+	// SearchModeFullText is used for searching for spots whose names or
+	// localities contain words of the search query in any order, ranked by
+	// relevance. IDs are still matched as substrings.
+	SearchModeFullText = "fulltext"
+
+	// SortUpdatedAtDesc is used for sorting spots by their update time in the
+	// descending order.
+	SortUpdatedAtDesc = "-updated_at"
+
+	// SortReferenceDistance is used for sorting spots by their distance to the
+	// nearest point of a reference set, from the closest one.
+	SortReferenceDistance = "reference_distance"
+
+	// CountryCodeUnknown is used for counting spots without a country code. It is
+	// the code that ISO 3166 reserves for unknown regions, so it never collides
+	// with a code of a real country.
+	CountryCodeUnknown = "zz"
+
+	// TagsMatchAll is used for filtering spots that have all of the given tags.
+	TagsMatchAll = "all"
+
+	// TagsMatchAny is used for filtering spots that have any of the given tags.
+	TagsMatchAny = "any"
 )
 
 var (
@@ -35,25 +117,232 @@ var (
 	ErrInvalidNorthEastLongitude = errors.New("invalid north-east longitude")
 	ErrInvalidSouthWestLatitude  = errors.New("invalid south-west latitude")
 	ErrInvalidSouthWestLongitude = errors.New("invalid south-west longitude")
+	ErrInvalidBounds             = errors.New("invalid bounds")
 	ErrInvalidSpotName           = errors.New("invalid spot name")
 	ErrInvalidSpotID             = errors.New("invalid spot id")
+	ErrInvalidSort               = errors.New("invalid sort")
+	ErrInvalidSpotIDs            = errors.New("invalid spot ids")
+	ErrInvalidSpotEntries        = errors.New("invalid spot entries")
+	ErrInvalidCoordinates        = errors.New("invalid coordinates")
+	ErrInvalidTags               = errors.New("invalid tags")
+	ErrInvalidTagsMatch          = errors.New("invalid tags match")
+	ErrInvalidLocalityPrefix     = errors.New("invalid locality prefix")
+	ErrInvalidFrom               = errors.New("invalid from")
+	ErrInvalidTo                 = errors.New("invalid to")
+	ErrInvalidInterval           = errors.New("invalid interval")
+	ErrInvalidSearchMode         = errors.New("invalid search mode")
+	ErrInvalidReferenceSet       = errors.New("invalid reference set")
+	ErrInvalidReferencePoints    = errors.New("invalid reference points")
+	ErrInvalidRadius             = errors.New("invalid radius")
+	ErrInvalidPhotoID            = errors.New("invalid photo id")
+	ErrInvalidPhoto              = errors.New("invalid photo")
+	ErrInvalidIdempotencyKey     = errors.New("invalid idempotency key")
+	ErrInvalidSuggestionID       = errors.New("invalid suggestion id")
+	ErrInvalidSuggestionStatus   = errors.New("invalid suggestion status")
+	ErrInvalidSlug               = errors.New("invalid slug")
+
+	// ErrUnsupportedPhotoType is used when content of a photo is neither JPEG,
+	// PNG, nor WebP.
+	ErrUnsupportedPhotoType = errors.New("unsupported photo type")
+
+	// ErrPhotosUnavailable is used when photos of spots are requested to be
+	// written but Service has no media storage.
+	ErrPhotosUnavailable = errors.New("photos unavailable")
+
+	// ErrEmptySuggestionSelection is used when none of suggested values is chosen
+	// to be applied.
+	ErrEmptySuggestionSelection = errors.New("empty suggestion selection")
+
+	// ErrLocationMismatch is used when a spot's country code is updated to one that
+	// does not match the country at the spot's coordinates.
+	ErrLocationMismatch = errors.New("location mismatch")
+
+	// ErrDiagnosticsDisabled is used when diagnostics are requested while being
+	// disabled.
+	ErrDiagnosticsDisabled = errors.New("diagnostics disabled")
+
+	// ErrReferenceSetNotFound is used when a reference set has no points.
+	ErrReferenceSetNotFound = errors.New("reference set not found")
+
+	// ErrLocationTimeout is used when the location source fails to respond within
+	// the location timeout.
+	ErrLocationTimeout = errors.New("location timeout")
 )
 
 type SpotStore interface {
 	surf.SpotReader
+	surf.SpotSlugReader
+	surf.NearbySpotReader
 	surf.SpotWriter
+	surf.MultiSpotWriter
+	surf.SpotTagWriter
+	surf.SpotPhotoWriter
+	surf.SpotCountryReader
+	surf.LocalityReader
+	surf.SpotStatsReader
+	surf.SpotIterator
+	surf.SpotQueryExplainer
+	surf.ReferenceStore
+	surf.IdempotencyKeyStore
+	surf.SpotSuggestionReader
+	surf.SpotSuggestionResolver
+	surf.AuditReader
 }
 
 type Service struct {
-	spotStore      SpotStore
-	locationSource geo.LocationSource
+	spotStore               SpotStore
+	locationSource          geo.LocationSource
+	eventPublisher          eventbus.Publisher
+	diagnosticsEnabled      bool
+	strictCountryValidation bool
+	slugRegeneration        bool
+	locationTimeout         time.Duration
+	locationsConcurrency    int
+	idempotencyKeyTTL       time.Duration
+	mediaStorage            media.Storage
+	now                     func() time.Time
+}
+
+func NewService(s SpotStore, l geo.LocationSource, opts ...Option) *Service {
+	svc := &Service{
+		spotStore:            s,
+		locationSource:       l,
+		locationTimeout:      defaultLocationTimeout,
+		locationsConcurrency: defaultLocationsConcurrency,
+		idempotencyKeyTTL:    defaultIdempotencyKeyTTL,
+		now:                  time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// Option is an optional function for Service.
+type Option func(*Service)
+
+// WithDiagnostics enables diagnostic capabilities of Service, such as capturing
+// query plans. Diagnostics are disabled by default.
+func WithDiagnostics() Option {
+	return func(s *Service) {
+		s.diagnosticsEnabled = true
+	}
+}
+
+// WithStrictCountryValidation enables verifying that a country code of a spot
+// which is updated without moving the spot matches the country that the geocoder
+// reports at the spot's coordinates. It is disabled by default, so that spots can
+// be edited while the geocoder is unavailable.
+func WithStrictCountryValidation() Option {
+	return func(s *Service) {
+		s.strictCountryValidation = true
+	}
+}
+
+// WithSlugRegeneration enables regenerating slugs of spots whose names get updated,
+// so that slugs keep reflecting the names. It is disabled by default, so that URLs
+// containing slugs keep working after spots get renamed.
+func WithSlugRegeneration() Option {
+	return func(s *Service) {
+		s.slugRegeneration = true
+	}
+}
+
+// WithEventPublisher sets a publisher that is notified once spots get created,
+// updated, restored, or deleted one by one. Spots created in batches are not
+// reported. No events are published by default.
+func WithEventPublisher(p eventbus.Publisher) Option {
+	return func(s *Service) {
+		s.eventPublisher = p
+	}
+}
+
+// WithLocationTimeout sets a custom duration after which calls of the location
+// source are abandoned with ErrLocationTimeout, so that a slow geocoder does not
+// consume the whole time budget of a request. By default, the calls time out
+// after 3 seconds. Zero disables the timeout.
+func WithLocationTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.locationTimeout = d
+	}
+}
+
+// WithLocationsConcurrency sets a custom number of locations of a batch that are
+// looked up at the same time. By default, up to 4 locations are looked up at the
+// same time. Non-positive numbers are ignored.
+func WithLocationsConcurrency(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.locationsConcurrency = n
+		}
+	}
+}
+
+// WithIdempotencyKeyTTL sets a custom duration for which spots created with
+// idempotency keys are returned to retries instead of creating new ones. By
+// default, idempotency keys expire after 24 hours.
+func WithIdempotencyKeyTTL(d time.Duration) Option {
+	return func(s *Service) {
+		s.idempotencyKeyTTL = d
+	}
+}
+
+// WithMediaStorage sets a storage in which content of photos of spots is kept.
+// Photos are unavailable by default.
+func WithMediaStorage(m media.Storage) Option {
+	return func(s *Service) {
+		s.mediaStorage = m
+	}
+}
+
+// location fetches a location by the given coordinates from the location source
+// within the location timeout, or until the given context is done. The location
+// source is not aware of contexts, so its call keeps running in the background
+// once abandoned.
+func (s *Service) location(ctx context.Context, c geo.Coordinates) (geo.Location, error) {
+	type result struct {
+		location geo.Location
+		err      error
+	}
+
+	var timeout <-chan time.Time
+	if s.locationTimeout > 0 {
+		timer := time.NewTimer(s.locationTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		l, err := s.locationSource.Location(c)
+		results <- result{location: l, err: err}
+	}()
+
+	select {
+	case res := <-results:
+		return res.location, res.err
+	case <-timeout:
+		return geo.Location{}, ErrLocationTimeout
+	case <-ctx.Done():
+		return geo.Location{}, ctx.Err()
+	}
 }
 
-func NewService(s SpotStore, l geo.LocationSource) *Service {
-	return &Service{
-		spotStore:      s,
-		locationSource: l,
+// publish notifies the event publisher, if any, about a change of the given spot
+// made by the user of the given claims.
+func (s *Service) publish(t eventbus.EventType, c jwt.Claims, spot surf.Spot) {
+	if s.eventPublisher == nil {
+		return
 	}
+
+	s.eventPublisher.Publish(eventbus.Event{
+		Type:       t,
+		ActorID:    c.Subject,
+		Spot:       spot,
+		OccurredAt: s.now(),
+	})
 }
 
 func (s *Service) Spot(ctx context.Context, id string) (surf.Spot, error) {
@@ -67,7 +356,83 @@ func (s *Service) Spot(ctx context.Context, id string) (surf.Spot, error) {
 		return surf.Spot{}, err
 	}
 
-	return s.spotStore.Spot(id)
+	return s.spotStore.Spot(ctx, id)
+}
+
+// SpotBySlug returns a spot by the given slug. Slugs are matched
+// case-insensitively.
+func (s *Service) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return surf.Spot{}, err
+	}
+
+	slug = strings.ToLower(strings.TrimSpace(slug))
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(slug), ErrInvalidSlug); err != nil {
+		return surf.Spot{}, err
+	}
+
+	return s.spotStore.SpotBySlug(ctx, slug)
+}
+
+// SpotWithNearby returns a spot by the given ID along with up to 10 spots that
+// are the closest to it within the given radius, excluding the spot itself.
+func (s *Service) SpotWithNearby(ctx context.Context, p SpotWithNearbyParams) (SpotWithNearby, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	nearby, err := surf.NearbySpotsOf(ctx, s.spotStore, spot, p.RadiusKm, maxSpotNearbyCount)
+	if err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	return SpotWithNearby{
+		Spot:   spot,
+		Nearby: nearby,
+	}, nil
+}
+
+type SpotWithNearbyParams struct {
+	SpotID   string
+	RadiusKm float64
+}
+
+func (p SpotWithNearbyParams) sanitize() SpotWithNearbyParams {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	if p.RadiusKm == 0 {
+		p.RadiusKm = defaultNearbyRadiusKm
+	}
+	if p.RadiusKm > maxNearbyRadiusKm {
+		p.RadiusKm = maxNearbyRadiusKm
+	}
+	return p
+}
+
+func (p SpotWithNearbyParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return p.RadiusKm > 0 }, ErrInvalidRadius)
+
+	return v.Validate()
+}
+
+// SpotWithNearby holds a spot along with spots located near it.
+type SpotWithNearby struct {
+	Spot   surf.Spot
+	Nearby []surf.NearbySpot
 }
 
 func (s *Service) Spots(ctx context.Context, p SpotsParams) ([]surf.Spot, error) {
@@ -81,42 +446,144 @@ func (s *Service) Spots(ctx context.Context, p SpotsParams) ([]surf.Spot, error)
 		return nil, err
 	}
 
-	sp := surf.SpotsParams{
-		Limit:       p.Limit,
-		Offset:      p.Offset,
+	return s.spotStore.Spots(ctx, p.toSurf())
+}
+
+// ExportSpots calls the given function for every spot that matches the given
+// parameters without loading all of them into memory at once.
+func (s *Service) ExportSpots(ctx context.Context, p ExportSpotsParams, fn func(surf.Spot) error) error {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return err
+	}
+
+	sp := SpotsParams{
 		CountryCode: p.CountryCode,
+		SearchQuery: p.SearchQuery,
 		Bounds:      p.Bounds,
+	}.sanitize()
+
+	if err := sp.validate(); err != nil {
+		return err
 	}
-	if p.SearchQuery != "" {
-		sp.SearchQuery = surf.SpotSearchQuery{
-			Query:      p.SearchQuery,
-			WithSpotID: true,
-		}
-	}
 
-	return s.spotStore.Spots(sp)
+	surfParams := sp.toSurf()
+
+	return s.spotStore.IterateSpots(ctx, surf.SpotIterationParams{
+		CountryCode: surfParams.CountryCode,
+		SearchQuery: surfParams.SearchQuery,
+		Bounds:      surfParams.Bounds,
+	}, fn)
 }
 
-type SpotsParams struct {
-	Limit       int
-	Offset      int
+// ExportSpotsParams holds filters of spots to export. Unlike SpotsParams, it is
+// not paginated.
+type ExportSpotsParams struct {
 	CountryCode string
 	SearchQuery string
 	Bounds      *geo.Bounds
 }
 
+// SpotsQueryPlan returns an execution plan, in a JSON format, of the query that
+// reads spots matching the given parameters. ErrDiagnosticsDisabled is returned
+// unless diagnostics are enabled.
+func (s *Service) SpotsQueryPlan(ctx context.Context, p SpotsParams) ([]byte, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if !s.diagnosticsEnabled {
+		return nil, ErrDiagnosticsDisabled
+	}
+
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.ExplainSpots(ctx, p.toSurf())
+}
+
+type SpotsParams struct {
+	Limit          int
+	Offset         int
+	CountryCode    string
+	SearchQuery    string
+	Bounds         *geo.Bounds
+	IncludeDeleted bool
+
+	// SearchMode can be optionally set to either SearchModeSubstring, which is
+	// the default, or SearchModeFullText.
+	SearchMode string
+
+	// Sort can be optionally set to SortUpdatedAtDesc, or to SortReferenceDistance
+	// along with ReferenceSet.
+	Sort string
+
+	// ReferenceSet can be optionally set to read spots along with their
+	// distances to the nearest point of the reference set.
+	ReferenceSet string
+
+	// Tags can be optionally set to filter spots by tags. TagsMatch can be
+	// optionally set to either TagsMatchAll, which is the default, or TagsMatchAny.
+	Tags      []string
+	TagsMatch string
+}
+
+// Page returns the limit and the offset that spots are listed with once defaults
+// and boundaries are applied to the given ones.
+func (p SpotsParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
+}
+
 func (p SpotsParams) sanitize() SpotsParams {
-	p.Limit = paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit)
-	p.Offset = paging.Offset(p.Offset, minOffset)
+	p.Limit, p.Offset = p.Page()
 	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
 	p.SearchQuery = strings.TrimSpace(p.SearchQuery)
+	p.SearchMode = strings.ToLower(strings.TrimSpace(p.SearchMode))
+	p.Sort = strings.TrimSpace(p.Sort)
+	p.Tags = sanitizeTags(p.Tags)
+	p.TagsMatch = strings.ToLower(strings.TrimSpace(p.TagsMatch))
+	p.ReferenceSet = strings.ToLower(strings.TrimSpace(p.ReferenceSet))
 	return p
 }
 
+func (p SpotsParams) toSurf() surf.SpotsParams {
+	sp := surf.SpotsParams{
+		Limit:          p.Limit,
+		Offset:         p.Offset,
+		CountryCode:    p.CountryCode,
+		Bounds:         p.Bounds,
+		IncludeDeleted: p.IncludeDeleted,
+		Tags:           p.Tags,
+		ReferenceSet:   p.ReferenceSet,
+	}
+	if p.TagsMatch == TagsMatchAny {
+		sp.TagsMatch = surf.SpotTagsMatchAny
+	}
+	switch p.Sort {
+	case SortUpdatedAtDesc:
+		sp.Order = surf.SpotOrderUpdatedAtDesc
+	case SortReferenceDistance:
+		sp.Order = surf.SpotOrderReferenceDistance
+	}
+	if p.SearchQuery != "" {
+		sp.SearchQuery = surf.SpotSearchQuery{
+			Query:      p.SearchQuery,
+			WithSpotID: true,
+			FullText:   p.SearchMode == SearchModeFullText,
+		}
+	}
+	return sp
+}
+
 func (p SpotsParams) validate() error {
 	v := valerra.New()
 
 	v.IfFalse(valerra.StringLessOrEqual(p.SearchQuery, maxSearchQueryChars), ErrInvalidSearchQuery)
+	v.IfFalse(func() bool {
+		return p.SearchMode == "" || p.SearchMode == SearchModeSubstring || p.SearchMode == SearchModeFullText
+	}, ErrInvalidSearchMode)
 	if p.CountryCode != "" {
 		v.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
 	}
@@ -125,23 +592,80 @@ func (p SpotsParams) validate() error {
 		v.IfFalse(valerrautil.IsLongitude(p.Bounds.NorthEast.Longitude), ErrInvalidNorthEastLongitude)
 		v.IfFalse(valerrautil.IsLatitude(p.Bounds.SouthWest.Latitude), ErrInvalidSouthWestLatitude)
 		v.IfFalse(valerrautil.IsLongitude(p.Bounds.SouthWest.Longitude), ErrInvalidSouthWestLongitude)
+		v.IfFalse(valerrautil.AreConsistentBounds(*p.Bounds), ErrInvalidBounds)
+	}
+	v.IfFalse(func() bool {
+		return p.Sort == "" || p.Sort == SortUpdatedAtDesc || (p.Sort == SortReferenceDistance && p.ReferenceSet != "")
+	}, ErrInvalidSort)
+	if p.ReferenceSet != "" {
+		v.IfFalse(isValidReferenceSet(p.ReferenceSet), ErrInvalidReferenceSet)
 	}
+	v.IfFalse(areValidTags(p.Tags), ErrInvalidTags)
+	v.IfFalse(func() bool {
+		return p.TagsMatch == "" || p.TagsMatch == TagsMatchAll || p.TagsMatch == TagsMatchAny
+	}, ErrInvalidTagsMatch)
 
 	return v.Validate()
 }
 
+// CreateSpot creates a spot along with a slug that is generated from its name and
+// locality.
 func (s *Service) CreateSpot(ctx context.Context, p CreateSpotParams) (surf.Spot, error) {
-	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
 		return surf.Spot{}, err
 	}
 
-	p = p.sanitize()
+	entry, err := p.entry()
+	if err != nil {
+		return surf.Spot{}, err
+	}
 
-	if err := p.validate(); err != nil {
+	spot, err := s.spotStore.CreateSpot(surf.ContextWithAuditActor(ctx, claims.Subject), entry)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	s.publish(eventbus.EventSpotCreated, claims, spot)
+	return spot, nil
+}
+
+// CreateSpotIdempotently creates a spot in the same way as CreateSpot does, unless
+// a spot has already been created with the given idempotency key within the
+// idempotency key TTL. In that case, the previously created spot is returned, so
+// that clients can safely retry creations whose responses got lost. The spot and
+// the key are saved at once, so concurrent retries create a single spot.
+func (s *Service) CreateSpotIdempotently(ctx context.Context, key string, p CreateSpotParams) (surf.Spot, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
 		return surf.Spot{}, err
 	}
 
-	return s.spotStore.CreateSpot(surf.SpotCreationEntry(p))
+	key = strings.TrimSpace(key)
+
+	if err := valerra.IfFalse(func() bool {
+		return key != "" && len(key) <= maxIdempotencyKeyChars
+	}, ErrInvalidIdempotencyKey); err != nil {
+		return surf.Spot{}, err
+	}
+
+	entry, err := p.entry()
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	now := s.now()
+
+	spot, created, err := s.spotStore.CreateSpotIdempotently(
+		surf.ContextWithAuditActor(ctx, claims.Subject), key, entry, now.Add(-s.idempotencyKeyTTL), now)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	if created {
+		s.publish(eventbus.EventSpotCreated, claims, spot)
+	}
+	return spot, nil
 }
 
 type CreateSpotParams surf.SpotCreationEntry
@@ -153,6 +677,20 @@ func (p CreateSpotParams) sanitize() CreateSpotParams {
 	return p
 }
 
+// entry sanitizes and validates the parameters, and returns an entry for creating
+// a spot with them.
+func (p CreateSpotParams) entry() (surf.SpotCreationEntry, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return surf.SpotCreationEntry{}, err
+	}
+
+	entry := surf.SpotCreationEntry(p)
+	entry.Slug = slug.Make(p.Name, p.Location.Locality)
+	return entry, nil
+}
+
 func (p CreateSpotParams) validate() error {
 	v := valerra.New()
 
@@ -165,8 +703,65 @@ func (p CreateSpotParams) validate() error {
 	return v.Validate()
 }
 
+// CreateSpots creates multiple spots at once and returns a result for each of the
+// given entries in the same order. Invalid entries are skipped and reported in
+// their results, while the valid ones get created together in a single transaction.
+func (s *Service) CreateSpots(ctx context.Context, ps []CreateSpotParams) (CreateSpotsResult, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
+		return CreateSpotsResult{}, err
+	}
+
+	if err := valerra.IfFalse(func() bool {
+		return len(ps) > 0 && len(ps) <= maxCreateSpotEntries
+	}, ErrInvalidSpotEntries); err != nil {
+		return CreateSpotsResult{}, err
+	}
+
+	res := CreateSpotsResult{
+		Entries: make([]CreateSpotsEntryResult, len(ps)),
+	}
+
+	var entries []surf.SpotCreationEntry
+	for i, p := range ps {
+		p = p.sanitize()
+
+		if err := p.validate(); err != nil {
+			res.Entries[i].Err = err
+			continue
+		}
+
+		entries = append(entries, surf.SpotCreationEntry(p))
+	}
+
+	if len(entries) == 0 {
+		return res, nil
+	}
+
+	stats, err := s.spotStore.CreateSpots(surf.ContextWithAuditActor(ctx, claims.Subject), entries, surf.SpotCreationModeInsert)
+	if err != nil {
+		return CreateSpotsResult{}, err
+	}
+
+	res.Created = stats.Inserted
+	return res, nil
+}
+
+// CreateSpotsResult holds results of creating multiple spots.
+type CreateSpotsResult struct {
+	Created int
+	Entries []CreateSpotsEntryResult
+}
+
+// CreateSpotsEntryResult holds a result of creating a single spot out of multiple
+// ones. Err holds validation errors of an entry that didn't get created.
+type CreateSpotsEntryResult struct {
+	Err error
+}
+
 func (s *Service) UpdateSpot(ctx context.Context, p UpdateSpotParams) (surf.Spot, error) {
-	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
 		return surf.Spot{}, err
 	}
 
@@ -176,26 +771,120 @@ func (s *Service) UpdateSpot(ctx context.Context, p UpdateSpotParams) (surf.Spot
 		return surf.Spot{}, err
 	}
 
-	return s.spotStore.UpdateSpot(surf.SpotUpdateEntry(p))
+	if s.strictCountryValidation && p.CountryCode != nil && p.Latitude == nil && p.Longitude == nil {
+		if err := s.validateCountry(ctx, p.ID, *p.CountryCode); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+
+	entry := p.toSurf()
+	if s.slugRegeneration && p.Name != nil {
+		if entry.Slug, err = s.regeneratedSlug(ctx, p); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+
+	spot, err := s.spotStore.UpdateSpot(surf.ContextWithAuditActor(ctx, claims.Subject), entry)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	s.publish(eventbus.EventSpotUpdated, claims, spot)
+	return spot, nil
+}
+
+// regeneratedSlug generates a slug of a spot from the name and locality that the
+// spot is about to be updated with. A current locality of the spot is read when
+// the update leaves it unchanged. Nil is returned when the slug cannot be made
+// of them, so that the current one is kept.
+func (s *Service) regeneratedSlug(ctx context.Context, p UpdateSpotParams) (*string, error) {
+	var locality string
+	switch {
+	case p.ClearLocality:
+	case p.Locality != nil:
+		locality = *p.Locality
+	default:
+		spot, err := s.spotStore.Spot(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		locality = spot.Location.Locality
+	}
+
+	generated := slug.Make(*p.Name, locality)
+	if generated == "" {
+		return nil, nil
+	}
+	return &generated, nil
+}
+
+// validateCountry checks if the given country code matches the country that the
+// geocoder reports at coordinates of a spot by the given ID. Countries of spots
+// whose coordinates the geocoder knows no location at are not validated.
+func (s *Service) validateCountry(ctx context.Context, spotID, countryCode string) error {
+	spot, err := s.spotStore.Spot(ctx, spotID)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(spot.Location.CountryCode, countryCode) {
+		return nil
+	}
+
+	l, err := s.location(ctx, spot.Location.Coordinates)
+	if err != nil {
+		if errors.Is(err, geo.ErrLocationNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return valerra.IfFalse(func() bool {
+		return strings.EqualFold(l.CountryCode, countryCode)
+	}, ErrLocationMismatch)
 }
 
-type UpdateSpotParams surf.SpotUpdateEntry
+// UpdateSpotParams holds parameters for updating a spot. Nil fields are left
+// unchanged.
+//
+// Optional fields of a spot's location can be cleared by setting their Clear flags
+// instead of their values, since setting them to empty strings is treated as a
+// mistake and fails validation. Values of cleared fields are ignored.
+type UpdateSpotParams struct {
+	ID          string
+	Name        *string
+	Latitude    *float64
+	Longitude   *float64
+	Locality    *string
+	CountryCode *string
+	Region      *string
+
+	ClearLocality    bool
+	ClearCountryCode bool
+	ClearRegion      bool
+}
 
 func (p UpdateSpotParams) sanitize() UpdateSpotParams {
 	sanitized := UpdateSpotParams{
-		ID:        strings.TrimSpace(p.ID),
-		Latitude:  p.Latitude,
-		Longitude: p.Longitude,
+		ID:               strings.TrimSpace(p.ID),
+		Latitude:         p.Latitude,
+		Longitude:        p.Longitude,
+		ClearLocality:    p.ClearLocality,
+		ClearCountryCode: p.ClearCountryCode,
+		ClearRegion:      p.ClearRegion,
 	}
 	if p.Name != nil {
 		sanitized.Name = pconv.String(strings.TrimSpace(*p.Name))
 	}
-	if p.Locality != nil {
+	if p.Locality != nil && !p.ClearLocality {
 		sanitized.Locality = pconv.String(strings.TrimSpace(*p.Locality))
 	}
-	if p.CountryCode != nil {
+	if p.CountryCode != nil && !p.ClearCountryCode {
 		sanitized.CountryCode = pconv.String(strings.TrimSpace(*p.CountryCode))
 	}
+	if p.Region != nil && !p.ClearRegion {
+		sanitized.Region = pconv.String(strings.TrimSpace(*p.Region))
+	}
 	return sanitized
 }
 
@@ -222,8 +911,71 @@ func (p UpdateSpotParams) validate() error {
 	return v.Validate()
 }
 
+// toSurf converts the parameters to surf.SpotUpdateEntry, in which cleared fields
+// are set to empty strings.
+func (p UpdateSpotParams) toSurf() surf.SpotUpdateEntry {
+	entry := surf.SpotUpdateEntry{
+		ID:          p.ID,
+		Name:        p.Name,
+		Latitude:    p.Latitude,
+		Longitude:   p.Longitude,
+		Locality:    p.Locality,
+		CountryCode: p.CountryCode,
+		Region:      p.Region,
+	}
+	if p.ClearLocality {
+		entry.Locality = pconv.String("")
+	}
+	if p.ClearCountryCode {
+		entry.CountryCode = pconv.String("")
+	}
+	if p.ClearRegion {
+		entry.Region = pconv.String("")
+	}
+	return entry
+}
+
+// SpotChange sanitizes and validates the given parameters by the same rules that
+// spots are updated with, and converts them to surf.SpotUpdateEntry. It allows
+// changes of spots that are applied later, such as suggested ones, to be validated
+// up front.
+func SpotChange(p UpdateSpotParams) (surf.SpotUpdateEntry, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return surf.SpotUpdateEntry{}, err
+	}
+
+	return p.toSurf(), nil
+}
+
+// updateSpotParamsFromSurf is the reverse of UpdateSpotParams.toSurf, in which
+// empty strings of optional location fields are turned into their Clear flags.
+func updateSpotParamsFromSurf(e surf.SpotUpdateEntry) UpdateSpotParams {
+	p := UpdateSpotParams{
+		ID:          e.ID,
+		Name:        e.Name,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		Locality:    e.Locality,
+		CountryCode: e.CountryCode,
+		Region:      e.Region,
+	}
+	if e.Locality != nil && *e.Locality == "" {
+		p.Locality, p.ClearLocality = nil, true
+	}
+	if e.CountryCode != nil && *e.CountryCode == "" {
+		p.CountryCode, p.ClearCountryCode = nil, true
+	}
+	if e.Region != nil && *e.Region == "" {
+		p.Region, p.ClearRegion = nil, true
+	}
+	return p
+}
+
 func (s *Service) DeleteSpot(ctx context.Context, id string) error {
-	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
 		return err
 	}
 
@@ -233,7 +985,223 @@ func (s *Service) DeleteSpot(ctx context.Context, id string) error {
 		return err
 	}
 
-	return s.spotStore.DeleteSpot(id)
+	if err := s.spotStore.DeleteSpot(surf.ContextWithAuditActor(ctx, claims.Subject), id); err != nil {
+		return err
+	}
+
+	s.publish(eventbus.EventSpotDeleted, claims, surf.Spot{ID: id})
+	return nil
+}
+
+// DeleteSpots soft-deletes multiple spots by the given IDs at once. IDs of spots
+// that are not found don't fail the deletion and are reported back instead.
+func (s *Service) DeleteSpots(ctx context.Context, ids []string) (DeleteSpotsResult, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
+		return DeleteSpotsResult{}, err
+	}
+
+	ids = sanitizeSpotIDs(ids)
+
+	v := valerra.New()
+	v.IfFalse(func() bool { return len(ids) > 0 && len(ids) <= maxDeleteSpotIDs }, ErrInvalidSpotIDs)
+	v.IfFalse(func() bool {
+		for _, id := range ids {
+			if id == "" {
+				return false
+			}
+		}
+		return true
+	}, ErrInvalidSpotID)
+	if err := v.Validate(); err != nil {
+		return DeleteSpotsResult{}, err
+	}
+
+	deleted, err := s.spotStore.DeleteSpots(surf.ContextWithAuditActor(ctx, claims.Subject), ids)
+	if err != nil {
+		return DeleteSpotsResult{}, err
+	}
+
+	deletedSet := make(map[string]struct{}, len(deleted))
+	for _, id := range deleted {
+		deletedSet[id] = struct{}{}
+		s.publish(eventbus.EventSpotDeleted, claims, surf.Spot{ID: id})
+	}
+
+	res := DeleteSpotsResult{
+		DeletedCount: len(deleted),
+		NotFoundIDs:  make([]string, 0),
+	}
+	for _, id := range ids {
+		if _, ok := deletedSet[id]; !ok {
+			res.NotFoundIDs = append(res.NotFoundIDs, id)
+		}
+	}
+
+	return res, nil
+}
+
+// sanitizeSpotIDs trims the given IDs and drops duplicates while preserving their
+// order.
+func sanitizeSpotIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	sanitized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		sanitized = append(sanitized, id)
+	}
+	return sanitized
+}
+
+// DeleteSpotsResult holds results of deleting multiple spots.
+type DeleteSpotsResult struct {
+	DeletedCount int
+	NotFoundIDs  []string
+}
+
+func (s *Service) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	id = strings.TrimSpace(id)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(id), ErrInvalidSpotID); err != nil {
+		return surf.Spot{}, err
+	}
+
+	spot, err := s.spotStore.RestoreSpot(surf.ContextWithAuditActor(ctx, claims.Subject), id)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	s.publish(eventbus.EventSpotUpdated, claims, spot)
+	return spot, nil
+}
+
+// Countries returns countries of spots that match the given parameters along with
+// numbers of such spots in them.
+func (s *Service) Countries(ctx context.Context, p CountriesParams) ([]surf.CountrySpotCount, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	p.SearchQuery = strings.TrimSpace(p.SearchQuery)
+
+	if err := valerra.IfFalse(
+		valerra.StringLessOrEqual(p.SearchQuery, maxSearchQueryChars),
+		ErrInvalidSearchQuery,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.CountriesWithSpotCounts(ctx, surf.CountriesParams{
+		SearchQuery: surf.SpotSearchQuery{
+			Query: p.SearchQuery,
+		},
+	})
+}
+
+type CountriesParams struct {
+	SearchQuery string
+}
+
+// SpotCountsByCountry returns numbers of spots per country, ordered from the
+// country with the most spots. Spots without a country code are counted under
+// CountryCodeUnknown.
+func (s *Service) SpotCountsByCountry(ctx context.Context) ([]surf.CountrySpotCount, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.spotStore.CountriesWithSpotCounts(ctx, surf.CountriesParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range counts {
+		if counts[i].CountryCode == "" {
+			counts[i].CountryCode = CountryCodeUnknown
+		}
+	}
+
+	return counts, nil
+}
+
+func (s *Service) Localities(ctx context.Context, p LocalitiesParams) ([]string, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	p.Prefix = strings.TrimSpace(p.Prefix)
+	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
+	p.Limit = paging.Limit(p.Limit, minLimit, maxLocalitiesLimit, defaultLimit)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringGreaterOrEqual(p.Prefix, minLocalityPrefixChars), ErrInvalidLocalityPrefix)
+	if p.CountryCode != "" {
+		v.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.Localities(ctx, surf.LocalitiesParams(p))
+}
+
+type LocalitiesParams struct {
+	Prefix      string
+	CountryCode string
+	Limit       int
+}
+
+// SpotsOverTime returns numbers of spots created within time buckets of the given
+// interval between the given times, ordered from the earliest bucket.
+func (s *Service) SpotsOverTime(ctx context.Context, p SpotsOverTimeParams) ([]surf.SpotCreationCount, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	p.Interval = strings.ToLower(strings.TrimSpace(p.Interval))
+
+	v := valerra.New()
+	v.IfFalse(func() bool { return !p.From.IsZero() }, ErrInvalidFrom)
+	v.IfFalse(func() bool { return !p.To.IsZero() && p.To.After(p.From) }, ErrInvalidTo)
+	v.IfFalse(func() bool {
+		return p.Interval == "" || p.Interval == IntervalDay || p.Interval == IntervalWeek || p.Interval == IntervalMonth
+	}, ErrInvalidInterval)
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	sp := surf.SpotCreationCountsParams{
+		From:     p.From.UTC(),
+		To:       p.To.UTC(),
+		Interval: surf.TimeIntervalMonth,
+	}
+	switch p.Interval {
+	case IntervalDay:
+		sp.Interval = surf.TimeIntervalDay
+	case IntervalWeek:
+		sp.Interval = surf.TimeIntervalWeek
+	}
+
+	return s.spotStore.SpotCreationCounts(ctx, sp)
+}
+
+type SpotsOverTimeParams struct {
+	// From is inclusive while To is exclusive.
+	From time.Time
+	To   time.Time
+
+	// Interval can be optionally set to IntervalDay, IntervalWeek, or IntervalMonth,
+	// which is the default.
+	Interval string
 }
 
 func (s *Service) Location(ctx context.Context, c geo.Coordinates) (geo.Location, error) {
@@ -248,10 +1216,617 @@ func (s *Service) Location(ctx context.Context, c geo.Coordinates) (geo.Location
 		return geo.Location{}, err
 	}
 
-	l, err := s.locationSource.Location(c)
+	l, err := s.location(ctx, c)
 	if err != nil {
 		return geo.Location{}, err
 	}
 
 	return l, nil
 }
+
+// Locations looks up locations of multiple coordinates at once and returns a
+// result for each of the given coordinates in the same order. Invalid coordinates
+// and the ones without a location are reported in their results, whereas any other
+// failure of the geocoder fails the whole batch and stops looking up the rest.
+func (s *Service) Locations(ctx context.Context, cs []geo.Coordinates) ([]LocationsEntryResult, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := valerra.IfFalse(func() bool {
+		return len(cs) > 0 && len(cs) <= maxLocationCoordinates
+	}, ErrInvalidCoordinates); err != nil {
+		return nil, err
+	}
+
+	var (
+		results = make([]LocationsEntryResult, len(cs))
+		valid   []geo.Coordinates
+		indexes []int
+	)
+
+	for i, c := range cs {
+		v := valerra.New()
+		v.IfFalse(valerrautil.IsLatitude(c.Latitude), ErrInvalidLatitude)
+		v.IfFalse(valerrautil.IsLongitude(c.Longitude), ErrInvalidLongitude)
+		if err := v.Validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		valid = append(valid, c)
+		indexes = append(indexes, i)
+	}
+
+	locations, err := geo.ConcurrentLocations(ctx, s.location, valid, s.locationsConcurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, l := range locations {
+		results[indexes[i]] = LocationsEntryResult(l)
+	}
+
+	return results, nil
+}
+
+// LocationsEntryResult holds a result of looking up a location out of multiple
+// ones. Err holds either validation errors of the coordinates or
+// geo.ErrLocationNotFound, in which case Location is empty.
+type LocationsEntryResult struct {
+	Location geo.Location
+	Err      error
+}
+
+// SuggestSpotLocation returns location values of a spot by the given ID that are
+// suggested by the geocoder based on the spot's coordinates. The suggested values
+// are not applied to the spot, so that they can be reviewed first.
+func (s *Service) SuggestSpotLocation(ctx context.Context, id string) (SpotLocationSuggestion, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return SpotLocationSuggestion{}, err
+	}
+
+	id = strings.TrimSpace(id)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(id), ErrInvalidSpotID); err != nil {
+		return SpotLocationSuggestion{}, err
+	}
+
+	return s.suggestSpotLocation(ctx, id)
+}
+
+func (s *Service) suggestSpotLocation(ctx context.Context, id string) (SpotLocationSuggestion, error) {
+	spot, err := s.spotStore.Spot(ctx, id)
+	if err != nil {
+		return SpotLocationSuggestion{}, err
+	}
+
+	l, err := s.location(ctx, spot.Location.Coordinates)
+	if err != nil {
+		return SpotLocationSuggestion{}, err
+	}
+
+	return SpotLocationSuggestion{
+		Spot:        spot,
+		Locality:    l.Locality,
+		CountryCode: l.CountryCode,
+		Region:      l.Region,
+	}, nil
+}
+
+// SpotLocationSuggestion holds location values suggested for a spot along with
+// the spot itself, so that the suggested values can be compared with the current
+// ones.
+type SpotLocationSuggestion struct {
+	Spot        surf.Spot
+	Locality    string
+	CountryCode string
+	Region      string
+}
+
+// ApplySpotLocationSuggestion updates a spot with the chosen location values that
+// are suggested by the geocoder, and returns the updated spot. The spot is returned
+// as is when the geocoder suggests none of the chosen values.
+func (s *Service) ApplySpotLocationSuggestion(ctx context.Context, p ApplySpotLocationSuggestionParams) (surf.Spot, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	p.SpotID = strings.TrimSpace(p.SpotID)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return p.Locality || p.CountryCode || p.Region }, ErrEmptySuggestionSelection)
+	if err := v.Validate(); err != nil {
+		return surf.Spot{}, err
+	}
+
+	suggestion, err := s.suggestSpotLocation(ctx, p.SpotID)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	// Values that the geocoder could not suggest are left untouched.
+	entry := surf.SpotUpdateEntry{
+		ID: p.SpotID,
+	}
+	if p.Locality && suggestion.Locality != "" {
+		entry.Locality = pconv.String(suggestion.Locality)
+	}
+	if p.CountryCode && suggestion.CountryCode != "" {
+		entry.CountryCode = pconv.String(suggestion.CountryCode)
+	}
+	if p.Region && suggestion.Region != "" {
+		entry.Region = pconv.String(suggestion.Region)
+	}
+	if entry.Locality == nil && entry.CountryCode == nil && entry.Region == nil {
+		return suggestion.Spot, nil
+	}
+
+	spot, err := s.spotStore.UpdateSpot(surf.ContextWithAuditActor(ctx, claims.Subject), entry)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	s.publish(eventbus.EventSpotUpdated, claims, spot)
+	return spot, nil
+}
+
+// ApplySpotLocationSuggestionParams holds an ID of a spot and flags telling which
+// of the suggested location values must be applied to it.
+type ApplySpotLocationSuggestionParams struct {
+	SpotID      string
+	Locality    bool
+	CountryCode bool
+	Region      bool
+}
+
+// SpotSuggestions returns changes of spots suggested by surfers that match the
+// given parameters, from the most recently suggested one.
+func (s *Service) SpotSuggestions(ctx context.Context, p SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	p.Status = strings.ToLower(strings.TrimSpace(p.Status))
+
+	if err := valerra.IfFalse(func() bool {
+		switch surf.SpotSuggestionStatus(p.Status) {
+		case "", surf.SpotSuggestionStatusPending, surf.SpotSuggestionStatusApplied, surf.SpotSuggestionStatusRejected:
+			return true
+		}
+		return false
+	}, ErrInvalidSuggestionStatus); err != nil {
+		return nil, err
+	}
+
+	limit, offset := p.Page()
+
+	return s.spotStore.SpotSuggestions(ctx, surf.SpotSuggestionsParams{
+		Limit:  limit,
+		Offset: offset,
+		Status: surf.SpotSuggestionStatus(p.Status),
+	})
+}
+
+type SpotSuggestionsParams struct {
+	Limit  int
+	Offset int
+
+	// Status can be optionally set to list only suggestions that are either
+	// pending, applied, or rejected.
+	Status string
+}
+
+// Page returns the limit and the offset that spot suggestions are listed with once
+// defaults and boundaries are applied to the given ones.
+func (p SpotSuggestionsParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
+}
+
+// ApproveSpotSuggestion applies a pending spot suggestion by the given ID to its
+// spot in the same way as UpdateSpot does, and marks the suggestion as applied.
+// surf.ErrSpotSuggestionResolved is returned when the suggestion is no longer
+// pending.
+func (s *Service) ApproveSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	id = strings.TrimSpace(id)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(id), ErrInvalidSuggestionID); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	suggestion, err := s.spotStore.SpotSuggestion(ctx, id)
+	if err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	if suggestion.Status != surf.SpotSuggestionStatusPending {
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved
+	}
+
+	if _, err := s.UpdateSpot(ctx, updateSpotParamsFromSurf(suggestion.Change)); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	return s.spotStore.ResolveSpotSuggestion(ctx, id, surf.SpotSuggestionStatusApplied)
+}
+
+// RejectSpotSuggestion marks a pending spot suggestion by the given ID as rejected
+// without changing its spot. surf.ErrSpotSuggestionResolved is returned when the
+// suggestion is no longer pending.
+func (s *Service) RejectSpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	id = strings.TrimSpace(id)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(id), ErrInvalidSuggestionID); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	return s.spotStore.ResolveSpotSuggestion(ctx, id, surf.SpotSuggestionStatusRejected)
+}
+
+// AuditEntries returns entries of the audit log that match the given parameters,
+// from the most recent one. Mutations of spots made by admins, including bulk
+// ones, are recorded in it along with the admins who made them.
+func (s *Service) AuditEntries(ctx context.Context, p AuditEntriesParams) ([]surf.AuditEntry, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := valerra.IfFalse(func() bool {
+		return p.From.IsZero() || p.To.IsZero() || p.To.After(p.From)
+	}, ErrInvalidTo); err != nil {
+		return nil, err
+	}
+
+	limit, offset := p.Page()
+
+	return s.spotStore.AuditEntries(ctx, surf.AuditEntriesParams{
+		Limit:   limit,
+		Offset:  offset,
+		SpotID:  strings.TrimSpace(p.SpotID),
+		ActorID: strings.TrimSpace(p.ActorID),
+		From:    p.From,
+		To:      p.To,
+	})
+}
+
+type AuditEntriesParams struct {
+	Limit  int
+	Offset int
+
+	// SpotID can be optionally set to list only entries of the given spot.
+	SpotID string
+
+	// ActorID can be optionally set to list only entries of the given admin.
+	ActorID string
+
+	// From and To can be optionally set to list only entries created within the
+	// given period. From is inclusive while To is exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// Page returns the limit and the offset that audit entries are listed with once
+// defaults and boundaries are applied to the given ones.
+func (p AuditEntriesParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
+}
+
+// SpotPhoto is a photo of a spot along with a URL at which its content is served.
+type SpotPhoto struct {
+	surf.SpotPhoto
+	URL string
+}
+
+// UploadSpotPhotoParams holds parameters for uploading a photo of a spot.
+type UploadSpotPhotoParams struct {
+	SpotID  string
+	Content []byte
+}
+
+// UploadSpotPhoto creates a new photo of a spot with the given content and returns
+// it. The content type of the photo is detected from its content rather than taken
+// from clients, and has to be either JPEG, PNG, or WebP.
+func (s *Service) UploadSpotPhoto(ctx context.Context, p UploadSpotPhotoParams) (SpotPhoto, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return SpotPhoto{}, err
+	}
+
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	contentType := http.DetectContentType(p.Content)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool {
+		return len(p.Content) > 0
+	}, ErrInvalidPhoto)
+	v.IfFalse(func() bool {
+		return len(p.Content) == 0 || surf.IsSpotPhotoContentType(contentType)
+	}, ErrUnsupportedPhotoType)
+	if err := v.Validate(); err != nil {
+		return SpotPhoto{}, err
+	}
+
+	if s.mediaStorage == nil {
+		return SpotPhoto{}, ErrPhotosUnavailable
+	}
+
+	photo, err := s.spotStore.CreateSpotPhoto(ctx, surf.SpotPhotoEntry{
+		SpotID:      p.SpotID,
+		ContentType: contentType,
+		Size:        int64(len(p.Content)),
+	})
+	if err != nil {
+		return SpotPhoto{}, err
+	}
+
+	if err := s.mediaStorage.Put(ctx, photo.Key(), bytes.NewReader(p.Content)); err != nil {
+		// The photo is deleted, so that it does not refer to missing content.
+		if _, dErr := s.spotStore.DeleteSpotPhoto(ctx, photo.SpotID, photo.ID); dErr != nil {
+			return SpotPhoto{}, fmt.Errorf("failed to put photo: %w (failed to delete photo: %s)", err, dErr)
+		}
+		return SpotPhoto{}, fmt.Errorf("failed to put photo: %w", err)
+	}
+
+	return SpotPhoto{
+		SpotPhoto: photo,
+		URL:       s.mediaStorage.URL(photo.Key()),
+	}, nil
+}
+
+// DeleteSpotPhoto deletes a photo by the given ID of a spot by the given ID along
+// with its content. surf.ErrSpotPhotoNotFound is returned when the photo is not
+// found. Content that fails to be deleted is reported as an error, but the photo
+// stays deleted anyway.
+func (s *Service) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) error {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return err
+	}
+
+	spotID = strings.TrimSpace(spotID)
+	photoID = strings.TrimSpace(photoID)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringNotEmpty(spotID), ErrInvalidSpotID)
+	v.IfFalse(valerra.StringNotEmpty(photoID), ErrInvalidPhotoID)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+
+	if s.mediaStorage == nil {
+		return ErrPhotosUnavailable
+	}
+
+	photo, err := s.spotStore.DeleteSpotPhoto(ctx, spotID, photoID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mediaStorage.Delete(ctx, photo.Key()); err != nil {
+		return fmt.Errorf("failed to delete photo content: %w", err)
+	}
+
+	return nil
+}
+
+// SetSpotTags replaces tags of a spot with the given ones and returns the spot if
+// the replacement succeeds. Passing no tags removes all tags of the spot.
+func (s *Service) SetSpotTags(ctx context.Context, p SetSpotTagsParams) (surf.Spot, error) {
+	claims, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	p.Tags = sanitizeTags(p.Tags)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(areValidTags(p.Tags), ErrInvalidTags)
+	if err := v.Validate(); err != nil {
+		return surf.Spot{}, err
+	}
+
+	if err := s.spotStore.SetSpotTags(surf.ContextWithAuditActor(ctx, claims.Subject), p.SpotID, p.Tags); err != nil {
+		return surf.Spot{}, err
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	s.publish(eventbus.EventSpotUpdated, claims, spot)
+	return spot, nil
+}
+
+// SetSpotTagsParams holds an ID of a spot and tags that must replace its current
+// ones.
+type SetSpotTagsParams struct {
+	SpotID string
+	Tags   []string
+}
+
+// sanitizeTags trims and lowercases the given tags, and drops empty and duplicate
+// ones while preserving their order.
+func sanitizeTags(tags []string) []string {
+	var (
+		sanitized []string
+		seen      = make(map[string]struct{}, len(tags))
+	)
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		sanitized = append(sanitized, t)
+	}
+	return sanitized
+}
+
+func areValidTags(tags []string) valerra.Condition {
+	return func() bool {
+		if len(tags) > maxTags {
+			return false
+		}
+		for _, t := range tags {
+			if !valerra.StringLessOrEqual(t, maxTagChars)() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ReplaceReferenceSet replaces points of a reference set with the given ones, and
+// backfills distances of all spots to the nearest of them. A number of spots whose
+// distances got backfilled is returned.
+func (s *Service) ReplaceReferenceSet(ctx context.Context, p ReplaceReferenceSetParams) (int, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return 0, err
+	}
+
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+
+	if err := s.spotStore.ReplaceReferencePoints(ctx, p.Set, p.Points); err != nil {
+		return 0, err
+	}
+
+	return s.backfillReferenceDistances(ctx, p.Set, p.Points)
+}
+
+// ReplaceReferenceSetParams holds a name of a reference set, such as airports or
+// cities, and points that must replace its current ones.
+type ReplaceReferenceSetParams struct {
+	Set    string
+	Points []surf.ReferencePoint
+}
+
+func (p ReplaceReferenceSetParams) sanitize() ReplaceReferenceSetParams {
+	p.Set = strings.ToLower(strings.TrimSpace(p.Set))
+
+	points := make([]surf.ReferencePoint, len(p.Points))
+	for i, point := range p.Points {
+		point.Name = strings.TrimSpace(point.Name)
+		points[i] = point
+	}
+	p.Points = points
+
+	return p
+}
+
+func (p ReplaceReferenceSetParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(isValidReferenceSet(p.Set), ErrInvalidReferenceSet)
+	v.IfFalse(func() bool {
+		if len(p.Points) == 0 || len(p.Points) > maxReferencePoints {
+			return false
+		}
+
+		names := make(map[string]struct{}, len(p.Points))
+		for _, point := range p.Points {
+			if !valerra.StringNotEmpty(point.Name)() ||
+				!valerra.StringLessOrEqual(point.Name, maxReferencePointNameChars)() ||
+				!valerrautil.IsLatitude(point.Coordinates.Latitude)() ||
+				!valerrautil.IsLongitude(point.Coordinates.Longitude)() {
+				return false
+			}
+			if _, ok := names[point.Name]; ok {
+				return false
+			}
+			names[point.Name] = struct{}{}
+		}
+		return true
+	}, ErrInvalidReferencePoints)
+
+	return v.Validate()
+}
+
+// BackfillReferenceDistances recomputes distances of all spots to the nearest point
+// of a reference set, which is needed once spots get created or moved after the
+// set is replaced. A number of spots whose distances got backfilled is returned.
+// ErrReferenceSetNotFound is returned when the set has no points.
+func (s *Service) BackfillReferenceDistances(ctx context.Context, set string) (int, error) {
+	if _, err := jwt.WithRoleFromContext(ctx, auth.RoleAdmin); err != nil {
+		return 0, err
+	}
+
+	set = strings.ToLower(strings.TrimSpace(set))
+
+	if err := valerra.IfFalse(isValidReferenceSet(set), ErrInvalidReferenceSet); err != nil {
+		return 0, err
+	}
+
+	points, err := s.spotStore.ReferencePoints(ctx, set)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(points) == 0 {
+		return 0, ErrReferenceSetNotFound
+	}
+
+	return s.backfillReferenceDistances(ctx, set, points)
+}
+
+func (s *Service) backfillReferenceDistances(ctx context.Context, set string, points []surf.ReferencePoint) (int, error) {
+	var distances []surf.SpotReferenceDistance
+	err := s.spotStore.IterateSpots(ctx, surf.SpotIterationParams{}, func(spot surf.Spot) error {
+		point, distance, ok := surf.NearestReferencePoint(spot.Location.Coordinates, points)
+		if !ok {
+			return nil
+		}
+
+		distances = append(distances, surf.SpotReferenceDistance{
+			SpotID: spot.ID,
+			ReferenceDistance: surf.ReferenceDistance{
+				PointName:  point.Name,
+				DistanceKm: distance,
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.spotStore.ReplaceSpotReferenceDistances(ctx, set, distances); err != nil {
+		return 0, err
+	}
+
+	return len(distances), nil
+}
+
+// isValidReferenceSet checks that a name of a reference set consists of lowercase
+// letters, digits, hyphens, and underscores, so that it is safe to be used in URLs.
+func isValidReferenceSet(set string) valerra.Condition {
+	return func() bool {
+		if set == "" || len(set) > maxReferenceSetChars {
+			return false
+		}
+		for _, r := range set {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+				return false
+			}
+		}
+		return true
+	}
+}
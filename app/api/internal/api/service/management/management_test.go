@@ -3,18 +3,26 @@ package management
 import (
 	"context"
 	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	jwtgo "github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/media"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
 	"github.com/ztimes2/tolqin/app/api/pkg/strutil"
 	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
 type mockSpotStore struct {
@@ -25,31 +33,149 @@ func newMockSpotStore() *mockSpotStore {
 	return &mockSpotStore{}
 }
 
-func (m *mockSpotStore) Spot(id string) (surf.Spot, error) {
-	args := m.Called(id)
+func (m *mockSpotStore) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
-func (m *mockSpotStore) Spots(p surf.SpotsParams) ([]surf.Spot, error) {
-	args := m.Called(p)
+func (m *mockSpotStore) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockSpotStore) NearbySpots(ctx context.Context, p surf.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.NearbySpot), args.Error(1)
+}
+
+func (m *mockSpotStore) Spots(ctx context.Context, p surf.SpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
 	return args.Get(0).([]surf.Spot), args.Error(1)
 }
 
-func (m *mockSpotStore) CreateSpot(p surf.SpotCreationEntry) (surf.Spot, error) {
-	args := m.Called(p)
+func (m *mockSpotStore) CreateSpot(ctx context.Context, p surf.SpotCreationEntry) (surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(surf.Spot), args.Error(1)
+}
+
+func (m *mockSpotStore) CreateSpots(ctx context.Context, es []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+	args := m.Called(ctx, es, mode)
+	return args.Get(0).(surf.SpotCreationStats), args.Error(1)
+}
+
+func (m *mockSpotStore) UpdateSpot(ctx context.Context, p surf.SpotUpdateEntry) (surf.Spot, error) {
+	args := m.Called(ctx, p)
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
-func (m *mockSpotStore) UpdateSpot(p surf.SpotUpdateEntry) (surf.Spot, error) {
-	args := m.Called(p)
+func (m *mockSpotStore) DeleteSpot(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) SetSpotTags(ctx context.Context, id string, tags []string) error {
+	args := m.Called(ctx, id, tags)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) CreateSpotPhoto(ctx context.Context, e surf.SpotPhotoEntry) (surf.SpotPhoto, error) {
+	args := m.Called(ctx, e)
+	return args.Get(0).(surf.SpotPhoto), args.Error(1)
+}
+
+func (m *mockSpotStore) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) (surf.SpotPhoto, error) {
+	args := m.Called(ctx, spotID, photoID)
+	return args.Get(0).(surf.SpotPhoto), args.Error(1)
+}
+
+func (m *mockSpotStore) Localities(ctx context.Context, p surf.LocalitiesParams) ([]string, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotCreationCounts(ctx context.Context, p surf.SpotCreationCountsParams) ([]surf.SpotCreationCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotCreationCount), args.Error(1)
+}
+
+func (m *mockSpotStore) CountriesWithSpotCounts(ctx context.Context, p surf.CountriesParams) ([]surf.CountrySpotCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.CountrySpotCount), args.Error(1)
+}
+
+func (m *mockSpotStore) DeleteSpots(ctx context.Context, ids []string) ([]string, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockSpotStore) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
-func (m *mockSpotStore) DeleteSpot(id string) error {
-	args := m.Called(id)
+func (m *mockSpotStore) IterateSpots(ctx context.Context, p surf.SpotIterationParams, fn func(surf.Spot) error) error {
+	args := m.Called(ctx, p, fn)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) ExplainSpots(ctx context.Context, p surf.SpotsParams) ([]byte, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockSpotStore) ReferencePoints(ctx context.Context, set string) ([]surf.ReferencePoint, error) {
+	args := m.Called(ctx, set)
+	return args.Get(0).([]surf.ReferencePoint), args.Error(1)
+}
+
+func (m *mockSpotStore) ReplaceReferencePoints(ctx context.Context, set string, points []surf.ReferencePoint) error {
+	args := m.Called(ctx, set, points)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) ReplaceSpotReferenceDistances(ctx context.Context, set string, distances []surf.SpotReferenceDistance) error {
+	args := m.Called(ctx, set, distances)
 	return args.Error(0)
 }
 
+func (m *mockSpotStore) CreateSpotIdempotently(
+	ctx context.Context,
+	key string,
+	e surf.SpotCreationEntry,
+	since, now time.Time) (surf.Spot, bool, error) {
+
+	args := m.Called(ctx, key, e, since, now)
+	return args.Get(0).(surf.Spot), args.Bool(1), args.Error(2)
+}
+
+func (m *mockSpotStore) SpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotSuggestions(ctx context.Context, p surf.SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockSpotStore) ResolveSpotSuggestion(ctx context.Context, id string, status surf.SpotSuggestionStatus) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, id, status)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockSpotStore) AuditEntries(ctx context.Context, p surf.AuditEntriesParams) ([]surf.AuditEntry, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.AuditEntry), args.Error(1)
+}
+
+// auditedBy matches contexts holding the given audit actor.
+func auditedBy(actorID string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		id, ok := surf.AuditActorFromContext(ctx)
+		return ok && id == actorID
+	})
+}
+
 type mockLocationSource struct {
 	mock.Mock
 }
@@ -63,6 +189,28 @@ func (m *mockLocationSource) Location(c geo.Coordinates) (geo.Location, error) {
 	return args.Get(0).(geo.Location), args.Error(1)
 }
 
+type mockMediaStorage struct {
+	mock.Mock
+}
+
+func newMockMediaStorage() *mockMediaStorage {
+	return &mockMediaStorage{}
+}
+
+func (m *mockMediaStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	args := m.Called(ctx, key, r)
+	return args.Error(0)
+}
+
+func (m *mockMediaStorage) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *mockMediaStorage) URL(key string) string {
+	return "/media/" + key
+}
+
 func TestService_Spot(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -116,7 +264,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(surf.Spot{}, errors.New("something went wrong"))
 				return m
 			}(),
@@ -134,7 +282,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(
 						surf.Spot{
 							Location: geo.Location{
@@ -179,7 +327,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(
 						surf.Spot{
 							Location: geo.Location{
@@ -227,6 +375,224 @@ func TestService_Spot(t *testing.T) {
 	}
 }
 
+func TestService_SpotBySlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		slug          string
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			slug:          "",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			slug:          "",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid slug",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			slug:          " ",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSlug),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotBySlug", mock.Anything, "spot-1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			slug:          "spot-1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return spot using sanitized slug without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotBySlug", mock.Anything, "spot-1").
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Slug: "spot-1",
+						},
+						nil,
+					)
+				return m
+			}(),
+			slug: " Spot-1 ",
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Slug: "spot-1",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			spot, err := s.SpotBySlug(test.ctxFn(), test.slug)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestService_SpotWithNearby(t *testing.T) {
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	spot := surf.Spot{
+		ID:   "1",
+		Name: "Spot 1",
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        SpotWithNearbyParams
+		expected      SpotWithNearby
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unauthenticated request",
+			ctxFn:         context.Background,
+			spotStore:     newMockSpotStore(),
+			params:        SpotWithNearbyParams{SpotID: "1"},
+			expected:      SpotWithNearby{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:      "return error for invalid params",
+			ctxFn:     adminCtx,
+			spotStore: newMockSpotStore(),
+			params: SpotWithNearbyParams{
+				SpotID:   " ",
+				RadiusKm: -1,
+			},
+			expected:      SpotWithNearby{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidRadius),
+		},
+		{
+			name:  "return error for unexisting spot",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			params:        SpotWithNearbyParams{SpotID: "1"},
+			expected:      SpotWithNearby{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name:  "return spot with nearby spots using default radius without error",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("NearbySpots", mock.Anything, surf.NearbySpotsParams{
+						Coordinates: spot.Location.Coordinates,
+						RadiusKm:    50,
+						Limit:       11,
+					}).
+					Return([]surf.NearbySpot{
+						{
+							Spot: spot,
+						},
+						{
+							Spot: surf.Spot{
+								ID:   "2",
+								Name: "Spot 2",
+							},
+							DistanceKm: 1.23,
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotWithNearbyParams{SpotID: "1"},
+			expected: SpotWithNearby{
+				Spot: spot,
+				Nearby: []surf.NearbySpot{
+					{
+						Spot: surf.Spot{
+							ID:   "2",
+							Name: "Spot 2",
+						},
+						DistanceKm: 1.23,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			swn, err := s.SpotWithNearby(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expected, swn)
+		})
+	}
+}
+
 func TestService_Spots(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -400,31 +766,57 @@ func TestService_Spots(t *testing.T) {
 			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSouthWestLongitude),
 		},
 		{
-			name: "return error during spot store failure",
+			name: "return error for inverted latitudes",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			spotStore: func() SpotStore {
-				m := newMockSpotStore()
-				m.
-					On("Spots", surf.SpotsParams{
-						Limit:  20,
-						Offset: 0,
-					}).
-					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
-				return m
-			}(),
+			spotStore: newMockSpotStore(),
 			params: SpotsParams{
 				Limit:  20,
 				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: 20,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  10,
+						Longitude: -20,
+					},
+				},
 			},
 			expectedSpots: nil,
-			expectedErrFn: assert.Error,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidBounds),
 		},
 		{
-			name: "return spots using sanitized params without error",
+			name: "return error for inverted latitudes and longitudes",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: -20,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  10,
+						Longitude: 20,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidBounds),
+		},
+		{
+			name: "return spots by bounds crossing antimeridian without error",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
@@ -433,19 +825,304 @@ func TestService_Spots(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spots", surf.SpotsParams{
-						Limit:       10,
-						Offset:      0,
-						CountryCode: "kz",
-						SearchQuery: surf.SpotSearchQuery{
-							Query:      "query",
-							WithSpotID: true,
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  20,
+						Offset: 0,
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  10,
+								Longitude: -170,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -10,
+								Longitude: 170,
+							},
 						},
 					}).
-					Return(
-						[]surf.Spot{
-							{
-								Location: geo.Location{
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  10,
+						Longitude: -170,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: 170,
+					},
+				},
+			},
+			expectedSpots: []surf.Spot{},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  20,
+						Offset: 0,
+					}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for invalid sort",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Sort: "name",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSort),
+		},
+		{
+			name: "return error for reference sort without reference set",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Sort: SortReferenceDistance,
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSort),
+		},
+		{
+			name: "return error for invalid reference set",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				ReferenceSet: "air ports",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidReferenceSet),
+		},
+		{
+			name: "return spots nearest to reference set without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:        10,
+						Offset:       0,
+						ReferenceSet: "airports",
+						Order:        surf.SpotOrderReferenceDistance,
+					}).
+					Return([]surf.Spot{
+						{
+							ID: "1",
+							NearestReference: &surf.ReferenceDistance{
+								PointName:  "Aktau",
+								DistanceKm: 24.2,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				ReferenceSet: " Airports ",
+				Sort:         " reference_distance ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID: "1",
+					NearestReference: &surf.ReferenceDistance{
+						PointName:  "Aktau",
+						DistanceKm: 24.2,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return error for invalid search mode",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				SearchQuery: "query",
+				SearchMode:  "regex",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSearchMode),
+		},
+		{
+			name: "return spots using full-text search mode without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  10,
+						Offset: 0,
+						SearchQuery: surf.SpotSearchQuery{
+							Query:      "kazakh beach",
+							WithSpotID: true,
+							FullText:   true,
+						},
+					}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				SearchQuery: "kazakh beach",
+				SearchMode:  " fulltext ",
+			},
+			expectedSpots: []surf.Spot{},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return error for too many tags",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTags),
+		},
+		{
+			name: "return error for invalid tags match",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Tags:      []string{"reef"},
+				TagsMatch: "some",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTagsMatch),
+		},
+		{
+			name: "return spots using sanitized tags without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:     10,
+						Offset:    0,
+						Tags:      []string{"reef", "point"},
+						TagsMatch: surf.SpotTagsMatchAny,
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Tags: []string{"reef"},
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Tags:      []string{" Reef ", "", "point", "reef"},
+				TagsMatch: " ANY ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:   "1",
+					Tags: []string{"reef"},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots sorted by update time without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  10,
+						Offset: 0,
+						Order:  surf.SpotOrderUpdatedAtDesc,
+					}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Sort: " -updated_at ",
+			},
+			expectedSpots: []surf.Spot{},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots using sanitized params without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "kz",
+						SearchQuery: surf.SpotSearchQuery{
+							Query:      "query",
+							WithSpotID: true,
+						},
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
 									Coordinates: geo.Coordinates{
 										Latitude:  1.23,
 										Longitude: 3.21,
@@ -521,7 +1198,7 @@ func TestService_Spots(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spots", surf.SpotsParams{
+					On("Spots", mock.Anything, surf.SpotsParams{
 						Limit:       20,
 						Offset:      3,
 						CountryCode: "kz",
@@ -607,13 +1284,14 @@ func TestService_Spots(t *testing.T) {
 	}
 }
 
-func TestService_CreateSpot(t *testing.T) {
+func TestService_SpotsQueryPlan(t *testing.T) {
 	tests := []struct {
 		name          string
 		ctxFn         func() context.Context
 		spotStore     SpotStore
-		params        CreateSpotParams
-		expectedSpot  surf.Spot
+		opts          []Option
+		params        SpotsParams
+		expectedPlan  []byte
 		expectedErrFn assert.ErrorAssertionFunc
 	}{
 		{
@@ -621,19 +1299,10 @@ func TestService_CreateSpot(t *testing.T) {
 			ctxFn: func() context.Context {
 				return context.Background()
 			},
-			spotStore: newMockSpotStore(),
-			params: CreateSpotParams{
-				Name: "",
-				Location: geo.Location{
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
-					},
-					Locality:    "Locality 1",
-					CountryCode: "kz",
-				},
-			},
-			expectedSpot:  surf.Spot{},
+			spotStore:     newMockSpotStore(),
+			opts:          []Option{WithDiagnostics()},
+			params:        SpotsParams{},
+			expectedPlan:  nil,
 			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
 		},
 		{
@@ -643,52 +1312,187 @@ func TestService_CreateSpot(t *testing.T) {
 					Role: "",
 				})
 			},
-			spotStore: newMockSpotStore(),
-			params: CreateSpotParams{
-				Name: "",
-				Location: geo.Location{
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
-					},
-					Locality:    "Locality 1",
-					CountryCode: "kz",
-				},
-			},
-			expectedSpot:  surf.Spot{},
+			spotStore:     newMockSpotStore(),
+			opts:          []Option{WithDiagnostics()},
+			params:        SpotsParams{},
+			expectedPlan:  nil,
 			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
 		},
 		{
-			name: "return error for invalid name",
+			name: "return error when diagnostics are disabled",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			spotStore: newMockSpotStore(),
-			params: CreateSpotParams{
-				Name: "",
-				Location: geo.Location{
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
-					},
-					Locality:    "Locality 1",
-					CountryCode: "kz",
-				},
-			},
-			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotName),
+			spotStore:     newMockSpotStore(),
+			opts:          nil,
+			params:        SpotsParams{},
+			expectedPlan:  nil,
+			expectedErrFn: testutil.IsError(ErrDiagnosticsDisabled),
 		},
 		{
-			name: "return error for invalid latitude",
+			name: "return error for invalid params",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
 			spotStore: newMockSpotStore(),
-			params: CreateSpotParams{
+			opts:      []Option{WithDiagnostics()},
+			params: SpotsParams{
+				CountryCode: "invalid",
+			},
+			expectedPlan:  nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ExplainSpots", mock.Anything, surf.SpotsParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(([]byte)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			opts:          []Option{WithDiagnostics()},
+			params:        SpotsParams{},
+			expectedPlan:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return plan without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ExplainSpots", mock.Anything, surf.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "kz",
+						SearchQuery: surf.SpotSearchQuery{
+							Query:      "query",
+							WithSpotID: true,
+						},
+					}).
+					Return([]byte(`[{"Plan":{}}]`), nil)
+				return m
+			}(),
+			opts: []Option{WithDiagnostics()},
+			params: SpotsParams{
+				CountryCode: " KZ ",
+				SearchQuery: " query ",
+			},
+			expectedPlan:  []byte(`[{"Plan":{}}]`),
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource(), test.opts...)
+
+			plan, err := s.SpotsQueryPlan(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPlan, plan)
+		})
+	}
+}
+
+func TestService_CreateSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        CreateSpotParams
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore: newMockSpotStore(),
+			params: CreateSpotParams{
+				Name: "",
+				Location: geo.Location{
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+				},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: CreateSpotParams{
+				Name: "",
+				Location: geo.Location{
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+				},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid name",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: CreateSpotParams{
+				Name: "",
+				Location: geo.Location{
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+				},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotName),
+		},
+		{
+			name: "return error for invalid latitude",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: CreateSpotParams{
 				Name: "Spot 1",
 				Location: geo.Location{
 					Coordinates: geo.Coordinates{
@@ -778,7 +1582,7 @@ func TestService_CreateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("CreateSpot", surf.SpotCreationEntry{
+					On("CreateSpot", mock.Anything, surf.SpotCreationEntry{
 						Location: geo.Location{
 							Coordinates: geo.Coordinates{
 								Latitude:  1.23,
@@ -788,6 +1592,7 @@ func TestService_CreateSpot(t *testing.T) {
 							CountryCode: "kz",
 						},
 						Name: "Spot 1",
+						Slug: "spot-1-locality-1",
 					}).
 					Return(surf.Spot{}, errors.New("something went wrong"))
 				return m
@@ -816,7 +1621,7 @@ func TestService_CreateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("CreateSpot", surf.SpotCreationEntry{
+					On("CreateSpot", mock.Anything, surf.SpotCreationEntry{
 						Location: geo.Location{
 							Coordinates: geo.Coordinates{
 								Latitude:  1.23,
@@ -826,6 +1631,7 @@ func TestService_CreateSpot(t *testing.T) {
 							CountryCode: "kz",
 						},
 						Name: "Spot 1",
+						Slug: "spot-1-locality-1",
 					}).
 					Return(
 						surf.Spot{
@@ -881,7 +1687,7 @@ func TestService_CreateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("CreateSpot", surf.SpotCreationEntry{
+					On("CreateSpot", mock.Anything, surf.SpotCreationEntry{
 						Location: geo.Location{
 							Coordinates: geo.Coordinates{
 								Latitude:  1.23,
@@ -891,6 +1697,7 @@ func TestService_CreateSpot(t *testing.T) {
 							CountryCode: "kz",
 						},
 						Name: "Spot 1",
+						Slug: "spot-1-locality-1",
 					}).
 					Return(
 						surf.Spot{
@@ -936,6 +1743,35 @@ func TestService_CreateSpot(t *testing.T) {
 			},
 			expectedErrFn: assert.NoError,
 		},
+		{
+			name: "create spot on behalf of admin",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpot", auditedBy("admin-1"), mock.Anything).
+					Return(surf.Spot{ID: "1"}, nil)
+				return m
+			}(),
+			params: CreateSpotParams{
+				Location: geo.Location{
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+				},
+				Name: "Spot 1",
+			},
+			expectedSpot:  surf.Spot{ID: "1"},
+			expectedErrFn: assert.NoError,
+		},
 	}
 
 	for _, test := range tests {
@@ -949,106 +1785,433 @@ func TestService_CreateSpot(t *testing.T) {
 	}
 }
 
-func TestService_UpdateSpot(t *testing.T) {
+func TestService_CreateSpotIdempotently(t *testing.T) {
+	var (
+		now   = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		since = now.Add(-time.Hour)
+		ctx   = jwt.ContextWith(context.Background(), jwt.Claims{
+			StandardClaims: jwtgo.StandardClaims{
+				Subject: "admin-1",
+			},
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+		params = CreateSpotParams{
+			Name: "Spot 1",
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+			},
+		}
+		entry = func() surf.SpotCreationEntry {
+			e := surf.SpotCreationEntry(params)
+			e.Slug = "spot-1-locality-1"
+			return e
+		}()
+		spot = surf.Spot{
+			ID:       "1",
+			Name:     "Spot 1",
+			Location: params.Location,
+		}
+	)
+
 	tests := []struct {
 		name          string
-		ctxFn         func() context.Context
+		ctx           context.Context
 		spotStore     SpotStore
-		params        UpdateSpotParams
+		key           string
+		params        CreateSpotParams
 		expectedSpot  surf.Spot
 		expectedErrFn assert.ErrorAssertionFunc
 	}{
 		{
-			name: "return error for unauthenticated request",
-			ctxFn: func() context.Context {
-				return context.Background()
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:   "",
-				Name: pconv.String("Spot 1"),
-			},
+			name:          "return error for unauthenticated request",
+			ctx:           context.Background(),
+			spotStore:     newMockSpotStore(),
+			key:           "key",
+			params:        params,
 			expectedSpot:  surf.Spot{},
 			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
 		},
 		{
-			name: "return error for unauthorized request",
-			ctxFn: func() context.Context {
-				return jwt.ContextWith(context.Background(), jwt.Claims{
-					Role: "",
-				})
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:   "",
-				Name: pconv.String("Spot 1"),
-			},
+			name:          "return error for empty key",
+			ctx:           ctx,
+			spotStore:     newMockSpotStore(),
+			key:           "  ",
+			params:        params,
 			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidIdempotencyKey),
 		},
 		{
-			name: "return error for invalid id",
-			ctxFn: func() context.Context {
-				return jwt.ContextWith(context.Background(), jwt.Claims{
-					Role: jwt.RoleName(auth.RoleAdmin),
-				})
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:   "",
-				Name: pconv.String("Spot 1"),
-			},
+			name:          "return error for too long key",
+			ctx:           ctx,
+			spotStore:     newMockSpotStore(),
+			key:           strings.Repeat("a", 256),
+			params:        params,
 			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidIdempotencyKey),
 		},
 		{
-			name: "return error for invalid name",
-			ctxFn: func() context.Context {
-				return jwt.ContextWith(context.Background(), jwt.Claims{
-					Role: jwt.RoleName(auth.RoleAdmin),
-				})
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:   "1",
-				Name: pconv.String(""),
-			},
+			name:          "return error for invalid params",
+			ctx:           ctx,
+			spotStore:     newMockSpotStore(),
+			key:           "key",
+			params:        CreateSpotParams{Location: params.Location},
 			expectedSpot:  surf.Spot{},
 			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotName),
 		},
 		{
-			name: "return error for invalid latitude",
-			ctxFn: func() context.Context {
-				return jwt.ContextWith(context.Background(), jwt.Claims{
-					Role: jwt.RoleName(auth.RoleAdmin),
-				})
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:       "1",
-				Name:     pconv.String("Spot 1"),
-				Latitude: pconv.Float64(-91),
-			},
+			name: "return error during spot store failure",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", auditedBy("admin-1"), "key", entry, since, now).
+					Return(surf.Spot{}, false, errors.New("something went wrong"))
+				return m
+			}(),
+			key:           "key",
+			params:        params,
 			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidLatitude),
+			expectedErrFn: assert.Error,
 		},
 		{
-			name: "return error for invalid longitude",
-			ctxFn: func() context.Context {
-				return jwt.ContextWith(context.Background(), jwt.Claims{
-					Role: jwt.RoleName(auth.RoleAdmin),
-				})
-			},
-			spotStore: newMockSpotStore(),
-			params: UpdateSpotParams{
-				ID:        "1",
-				Name:      pconv.String("Spot 1"),
-				Latitude:  pconv.Float64(1.23),
-				Longitude: pconv.Float64(-181),
-			},
-			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidLongitude),
-		},
+			name: "return error for replayed key of deleted spot",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", auditedBy("admin-1"), "key", entry, since, now).
+					Return(surf.Spot{}, false, surf.ErrSpotNotFound)
+				return m
+			}(),
+			key:           "key",
+			params:        params,
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return previously created spot for replayed key",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", auditedBy("admin-1"), "key", entry, since, now).
+					Return(spot, false, nil)
+				return m
+			}(),
+			key:           " key ",
+			params:        params,
+			expectedSpot:  spot,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "create spot for new or expired key",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", auditedBy("admin-1"), "key", entry, since, now).
+					Return(spot, true, nil)
+				return m
+			}(),
+			key:           "key",
+			params:        params,
+			expectedSpot:  spot,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource(), WithIdempotencyKeyTTL(time.Hour))
+			s.now = func() time.Time { return now }
+
+			spot, err := s.CreateSpotIdempotently(test.ctx, test.key, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreateSpots(t *testing.T) {
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	validParams := CreateSpotParams{
+		Name: " Spot 1 ",
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+			Locality:    " Locality 1 ",
+			CountryCode: "kz",
+		},
+	}
+
+	validEntry := surf.SpotCreationEntry{
+		Name: "Spot 1",
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+			Locality:    "Locality 1",
+			CountryCode: "kz",
+		},
+	}
+
+	invalidParams := CreateSpotParams{
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+			Locality:    "Locality 2",
+			CountryCode: "kz",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		params         []CreateSpotParams
+		expectedResult CreateSpotsResult
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:      newMockSpotStore(),
+			params:         []CreateSpotParams{validParams},
+			expectedResult: CreateSpotsResult{},
+			expectedErrFn:  testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			params:         []CreateSpotParams{validParams},
+			expectedResult: CreateSpotsResult{},
+			expectedErrFn:  testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name:           "return error for empty entries",
+			ctxFn:          adminCtx,
+			spotStore:      newMockSpotStore(),
+			params:         nil,
+			expectedResult: CreateSpotsResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotEntries),
+		},
+		{
+			name:           "return error for too many entries",
+			ctxFn:          adminCtx,
+			spotStore:      newMockSpotStore(),
+			params:         make([]CreateSpotParams, 101),
+			expectedResult: CreateSpotsResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotEntries),
+		},
+		{
+			name:  "return error during spot store failure",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpots", mock.Anything, []surf.SpotCreationEntry{validEntry}, surf.SpotCreationModeInsert).
+					Return(surf.SpotCreationStats{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params:         []CreateSpotParams{validParams},
+			expectedResult: CreateSpotsResult{},
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name:      "return result without creating anything when all entries are invalid",
+			ctxFn:     adminCtx,
+			spotStore: newMockSpotStore(),
+			params:    []CreateSpotParams{invalidParams},
+			expectedResult: CreateSpotsResult{
+				Created: 0,
+				Entries: []CreateSpotsEntryResult{
+					{Err: valerra.NewErrors(ErrInvalidSpotName)},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "create spots on behalf of admin",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpots", auditedBy("admin-1"), []surf.SpotCreationEntry{validEntry}, surf.SpotCreationModeInsert).
+					Return(surf.SpotCreationStats{Inserted: 1}, nil)
+				return m
+			}(),
+			params: []CreateSpotParams{validParams},
+			expectedResult: CreateSpotsResult{
+				Created: 1,
+				Entries: []CreateSpotsEntryResult{
+					{Err: nil},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:  "return result with partially created entries without error",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpots", mock.Anything, []surf.SpotCreationEntry{validEntry, validEntry}, surf.SpotCreationModeInsert).
+					Return(surf.SpotCreationStats{Inserted: 2}, nil)
+				return m
+			}(),
+			params: []CreateSpotParams{validParams, invalidParams, validParams},
+			expectedResult: CreateSpotsResult{
+				Created: 2,
+				Entries: []CreateSpotsEntryResult{
+					{Err: nil},
+					{Err: valerra.NewErrors(ErrInvalidSpotName)},
+					{Err: nil},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			res, err := s.CreateSpots(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResult, res)
+		})
+	}
+}
+
+func TestService_UpdateSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        UpdateSpotParams
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:   "",
+				Name: pconv.String("Spot 1"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:   "",
+				Name: pconv.String("Spot 1"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid id",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:   "",
+				Name: pconv.String("Spot 1"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error for invalid name",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:   "1",
+				Name: pconv.String(""),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotName),
+		},
+		{
+			name: "return error for invalid latitude",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:       "1",
+				Name:     pconv.String("Spot 1"),
+				Latitude: pconv.Float64(-91),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidLatitude),
+		},
+		{
+			name: "return error for invalid longitude",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: UpdateSpotParams{
+				ID:        "1",
+				Name:      pconv.String("Spot 1"),
+				Latitude:  pconv.Float64(1.23),
+				Longitude: pconv.Float64(-181),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidLongitude),
+		},
 		{
 			name: "return error for invalid locality",
 			ctxFn: func() context.Context {
@@ -1096,7 +2259,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("UpdateSpot", surf.SpotUpdateEntry{
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
 						Latitude:    pconv.Float64(1.23),
 						Longitude:   pconv.Float64(2.34),
 						Locality:    pconv.String("Locality 1"),
@@ -1128,7 +2291,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("UpdateSpot", surf.SpotUpdateEntry{
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
 						Name: pconv.String("Spot 1"),
 						ID:   "1",
 					}).
@@ -1179,7 +2342,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("UpdateSpot", surf.SpotUpdateEntry{
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
 						ID:       "1",
 						Latitude: pconv.Float64(1.23),
 						Locality: pconv.String("Locality 1"),
@@ -1223,7 +2386,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spot using sanitized params without error",
+			name: "return spot with cleared fields without error",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
@@ -1232,13 +2395,11 @@ func TestService_UpdateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("UpdateSpot", surf.SpotUpdateEntry{
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
 						ID:          "1",
-						Latitude:    pconv.Float64(1.23),
-						Longitude:   pconv.Float64(2.34),
-						Locality:    pconv.String("Locality 1"),
-						CountryCode: pconv.String("kz"),
-						Name:        pconv.String("Spot 1"),
+						Locality:    pconv.String(""),
+						CountryCode: pconv.String(""),
+						Region:      pconv.String(""),
 					}).
 					Return(
 						surf.Spot{
@@ -1247,8 +2408,6 @@ func TestService_UpdateSpot(t *testing.T) {
 									Latitude:  1.23,
 									Longitude: 3.21,
 								},
-								Locality:    "Locality 1",
-								CountryCode: "kz",
 							},
 							Name:      "Spot 1",
 							ID:        "1",
@@ -1259,12 +2418,11 @@ func TestService_UpdateSpot(t *testing.T) {
 				return m
 			}(),
 			params: UpdateSpotParams{
-				ID:          " 1 ",
-				Latitude:    pconv.Float64(1.23),
-				Longitude:   pconv.Float64(2.34),
-				Locality:    pconv.String(" Locality 1 "),
-				CountryCode: pconv.String(" kz "),
-				Name:        pconv.String(" Spot 1 "),
+				ID:               "1",
+				CountryCode:      pconv.String("zz"),
+				ClearLocality:    true,
+				ClearCountryCode: true,
+				ClearRegion:      true,
 			},
 			expectedSpot: surf.Spot{
 				Location: geo.Location{
@@ -1272,8 +2430,6 @@ func TestService_UpdateSpot(t *testing.T) {
 						Latitude:  1.23,
 						Longitude: 3.21,
 					},
-					Locality:    "Locality 1",
-					CountryCode: "kz",
 				},
 				Name:      "Spot 1",
 				ID:        "1",
@@ -1282,7 +2438,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spot without error",
+			name: "return spot using sanitized params without error",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
@@ -1291,7 +2447,7 @@ func TestService_UpdateSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("UpdateSpot", surf.SpotUpdateEntry{
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
 						ID:          "1",
 						Latitude:    pconv.Float64(1.23),
 						Longitude:   pconv.Float64(2.34),
@@ -1318,12 +2474,12 @@ func TestService_UpdateSpot(t *testing.T) {
 				return m
 			}(),
 			params: UpdateSpotParams{
-				ID:          "1",
+				ID:          " 1 ",
 				Latitude:    pconv.Float64(1.23),
 				Longitude:   pconv.Float64(2.34),
-				Locality:    pconv.String("Locality 1"),
-				CountryCode: pconv.String("kz"),
-				Name:        pconv.String("Spot 1"),
+				Locality:    pconv.String(" Locality 1 "),
+				CountryCode: pconv.String(" kz "),
+				Name:        pconv.String(" Spot 1 "),
 			},
 			expectedSpot: surf.Spot{
 				Location: geo.Location{
@@ -1340,36 +2496,3464 @@ func TestService_UpdateSpot(t *testing.T) {
 			},
 			expectedErrFn: assert.NoError,
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			s := NewService(test.spotStore, newMockLocationSource())
-
-			spot, err := s.UpdateSpot(test.ctxFn(), test.params)
-			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedSpot, spot)
-		})
-	}
-}
+		{
+			name: "return spot without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:          "1",
+						Latitude:    pconv.Float64(1.23),
+						Longitude:   pconv.Float64(2.34),
+						Locality:    pconv.String("Locality 1"),
+						CountryCode: pconv.String("kz"),
+						Name:        pconv.String("Spot 1"),
+					}).
+					Return(
+						surf.Spot{
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+							},
+							Name:      "Spot 1",
+							ID:        "1",
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				Latitude:    pconv.Float64(1.23),
+				Longitude:   pconv.Float64(2.34),
+				Locality:    pconv.String("Locality 1"),
+				CountryCode: pconv.String("kz"),
+				Name:        pconv.String("Spot 1"),
+			},
+			expectedSpot: surf.Spot{
+				Location: geo.Location{
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+				},
+				Name:      "Spot 1",
+				ID:        "1",
+				CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			spot, err := s.UpdateSpot(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestService_UpdateSpot_StrictCountryValidation(t *testing.T) {
+	spot := surf.Spot{
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  43.65,
+				Longitude: 51.17,
+			},
+			Locality:    "Aktau",
+			CountryCode: "ru",
+		},
+		ID:   "1",
+		Name: "Spot 1",
+	}
+
+	tests := []struct {
+		name           string
+		spotStore      SpotStore
+		locationSource geo.LocationSource
+		params         UpdateSpotParams
+		expectedSpot   surf.Spot
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for country code that mismatches coordinates",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", spot.Location.Coordinates).
+					Return(geo.Location{CountryCode: "kz"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("tr"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrLocationMismatch),
+		},
+		{
+			name: "return error during location source failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", spot.Location.Coordinates).
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			locationSource: newMockLocationSource(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "update spot with country code that matches coordinates",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:          "1",
+						CountryCode: pconv.String("kz"),
+					}).
+					Return(surf.Spot{ID: "1", Location: geo.Location{CountryCode: "kz"}}, nil)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", spot.Location.Coordinates).
+					Return(geo.Location{CountryCode: "kz"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+			},
+			expectedSpot:  surf.Spot{ID: "1", Location: geo.Location{CountryCode: "kz"}},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "update spot when geocoder knows no location at coordinates",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:          "1",
+						CountryCode: pconv.String("kz"),
+					}).
+					Return(surf.Spot{ID: "1"}, nil)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", spot.Location.Coordinates).
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+			},
+			expectedSpot:  surf.Spot{ID: "1"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "update spot without validating unchanged country code",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:          "1",
+						CountryCode: pconv.String("ru"),
+					}).
+					Return(surf.Spot{ID: "1"}, nil)
+				return m
+			}(),
+			locationSource: newMockLocationSource(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				CountryCode: pconv.String("ru"),
+			},
+			expectedSpot:  surf.Spot{ID: "1"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "update spot without validating country code of moved spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:          "1",
+						Latitude:    pconv.Float64(41.01),
+						CountryCode: pconv.String("tr"),
+					}).
+					Return(surf.Spot{ID: "1"}, nil)
+				return m
+			}(),
+			locationSource: newMockLocationSource(),
+			params: UpdateSpotParams{
+				ID:          "1",
+				Latitude:    pconv.Float64(41.01),
+				CountryCode: pconv.String("tr"),
+			},
+			expectedSpot:  surf.Spot{ID: "1"},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, test.locationSource, WithStrictCountryValidation())
+
+			ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+				Role: jwt.RoleName(auth.RoleAdmin),
+			})
+
+			spot, err := s.UpdateSpot(ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestService_UpdateSpot_SlugRegeneration(t *testing.T) {
+	tests := []struct {
+		name          string
+		spotStore     SpotStore
+		params        UpdateSpotParams
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:   "1",
+				Name: pconv.String("Uluwatu"),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "regenerate slug using current locality",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Locality: "Bali"}}, nil)
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:   "1",
+						Name: pconv.String("Uluwatu"),
+						Slug: pconv.String("uluwatu-bali"),
+					}).
+					Return(surf.Spot{ID: "1", Slug: "uluwatu-bali"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:   "1",
+				Name: pconv.String(" Uluwatu "),
+			},
+			expectedSpot:  surf.Spot{ID: "1", Slug: "uluwatu-bali"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "regenerate slug using updated locality",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:       "1",
+						Name:     pconv.String("Uluwatu"),
+						Locality: pconv.String("Pecatu"),
+						Slug:     pconv.String("uluwatu-pecatu"),
+					}).
+					Return(surf.Spot{ID: "1", Slug: "uluwatu-pecatu"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:       "1",
+				Name:     pconv.String("Uluwatu"),
+				Locality: pconv.String("Pecatu"),
+			},
+			expectedSpot:  surf.Spot{ID: "1", Slug: "uluwatu-pecatu"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "regenerate slug without cleared locality",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:       "1",
+						Name:     pconv.String("Uluwatu"),
+						Locality: pconv.String(""),
+						Slug:     pconv.String("uluwatu"),
+					}).
+					Return(surf.Spot{ID: "1", Slug: "uluwatu"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:            "1",
+				Name:          pconv.String("Uluwatu"),
+				ClearLocality: true,
+			},
+			expectedSpot:  surf.Spot{ID: "1", Slug: "uluwatu"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "keep slug for name without letters and digits",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:       "1",
+						Name:     pconv.String("???"),
+						Locality: pconv.String(""),
+					}).
+					Return(surf.Spot{ID: "1", Slug: "uluwatu"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:            "1",
+				Name:          pconv.String("???"),
+				ClearLocality: true,
+			},
+			expectedSpot:  surf.Spot{ID: "1", Slug: "uluwatu"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "keep slug when name is not updated",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:       "1",
+						Locality: pconv.String("Pecatu"),
+					}).
+					Return(surf.Spot{ID: "1", Slug: "uluwatu-bali"}, nil)
+				return m
+			}(),
+			params: UpdateSpotParams{
+				ID:       "1",
+				Locality: pconv.String("Pecatu"),
+			},
+			expectedSpot:  surf.Spot{ID: "1", Slug: "uluwatu-bali"},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource(), WithSlugRegeneration())
+
+			ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+				Role: jwt.RoleName(auth.RoleAdmin),
+			})
+
+			spot, err := s.UpdateSpot(ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DeleteSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		id            string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "",
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "",
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid spot id",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "",
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			id:            "1",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spot using sanitized id without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			id:            " 1 ",
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			id:            "1",
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "delete spot on behalf of admin",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", auditedBy("admin-1"), "1").
+					Return(nil)
+				return m
+			}(),
+			id:            "1",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			err := s.DeleteSpot(test.ctxFn(), test.id)
+			test.expectedErrFn(t, err)
+		})
+	}
+}
+
+func TestService_DeleteSpots(t *testing.T) {
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	tests := []struct {
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		ids            []string
+		expectedResult DeleteSpotsResult
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:      newMockSpotStore(),
+			ids:            []string{"1"},
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			ids:            []string{"1"},
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name:           "return error for empty ids",
+			ctxFn:          adminCtx,
+			spotStore:      newMockSpotStore(),
+			ids:            nil,
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name:      "return error for too many ids",
+			ctxFn:     adminCtx,
+			spotStore: newMockSpotStore(),
+			ids: func() []string {
+				ids := make([]string, 101)
+				for i := range ids {
+					ids[i] = strconv.Itoa(i)
+				}
+				return ids
+			}(),
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name:           "return error for invalid spot id",
+			ctxFn:          adminCtx,
+			spotStore:      newMockSpotStore(),
+			ids:            []string{"1", " "},
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name:  "return error during spot store failure",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpots", mock.Anything, []string{"1"}).
+					Return(([]string)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			ids:            []string{"1"},
+			expectedResult: DeleteSpotsResult{},
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "delete spots on behalf of admin",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpots", auditedBy("admin-1"), []string{"1"}).
+					Return([]string{"1"}, nil)
+				return m
+			}(),
+			ids: []string{"1"},
+			expectedResult: DeleteSpotsResult{
+				DeletedCount: 1,
+				NotFoundIDs:  []string{},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:  "return result with not found ids using sanitized ids without error",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpots", mock.Anything, []string{"1", "2", "3"}).
+					Return([]string{"1", "3"}, nil)
+				return m
+			}(),
+			ids: []string{" 1 ", "2", "1", "3"},
+			expectedResult: DeleteSpotsResult{
+				DeletedCount: 2,
+				NotFoundIDs:  []string{"2"},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			res, err := s.DeleteSpots(test.ctxFn(), test.ids)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResult, res)
+		})
+	}
+}
+
+func TestService_EventPublishing(t *testing.T) {
+	now := time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		StandardClaims: jwtgo.StandardClaims{
+			Subject: "42",
+		},
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	tests := []struct {
+		name           string
+		spotStore      SpotStore
+		locationSource geo.LocationSource
+		fn             func(*Service) error
+		expectedEvents []eventbus.Event
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "publish event about created spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpot", mock.Anything, mock.Anything).
+					Return(surf.Spot{ID: "1", Name: "Spot 1"}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.CreateSpot(ctx, CreateSpotParams{
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+					},
+					Name: "Spot 1",
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotCreated,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1", Name: "Spot 1"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish event about spot created with idempotency key",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", mock.Anything, "key", mock.Anything, mock.Anything, now).
+					Return(surf.Spot{ID: "1", Name: "Spot 1"}, true, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.CreateSpotIdempotently(ctx, "key", CreateSpotParams{
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+					},
+					Name: "Spot 1",
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotCreated,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1", Name: "Spot 1"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish no event about spot returned for replayed idempotency key",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotIdempotently", mock.Anything, "key", mock.Anything, mock.Anything, now).
+					Return(surf.Spot{ID: "1", Name: "Spot 1"}, false, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.CreateSpotIdempotently(ctx, "key", CreateSpotParams{
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+					},
+					Name: "Spot 1",
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{},
+			expectedErrFn:  assert.NoError,
+		},
+		{
+			name: "publish event about updated spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UpdateSpot", mock.Anything, mock.Anything).
+					Return(surf.Spot{ID: "1", Name: "Spot 2"}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.UpdateSpot(ctx, UpdateSpotParams{
+					ID:   "1",
+					Name: pconv.String("Spot 2"),
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotUpdated,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1", Name: "Spot 2"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish event about spot with updated tags",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SetSpotTags", mock.Anything, "1", []string{"reef"}).
+					Return(nil)
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Tags: []string{"reef"}}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.SetSpotTags(ctx, SetSpotTagsParams{
+					SpotID: "1",
+					Tags:   []string{"reef"},
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotUpdated,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1", Tags: []string{"reef"}},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish event about spot with applied location suggestion",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1"}, nil)
+				m.
+					On("UpdateSpot", mock.Anything, surf.SpotUpdateEntry{
+						ID:       "1",
+						Locality: pconv.String("Aktau"),
+					}).
+					Return(surf.Spot{
+						ID: "1",
+						Location: geo.Location{
+							Locality: "Aktau",
+						},
+					}, nil)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{}).
+					Return(geo.Location{Locality: "Aktau"}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.ApplySpotLocationSuggestion(ctx, ApplySpotLocationSuggestionParams{
+					SpotID:   "1",
+					Locality: true,
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:    eventbus.EventSpotUpdated,
+					ActorID: "42",
+					Spot: surf.Spot{
+						ID: "1",
+						Location: geo.Location{
+							Locality: "Aktau",
+						},
+					},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish event about restored spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RestoreSpot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1"}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.RestoreSpot(ctx, "1")
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotUpdated,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish event about deleted spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				return s.DeleteSpot(ctx, "1")
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotDeleted,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "publish events about deleted spots only",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpots", mock.Anything, []string{"1", "2", "3"}).
+					Return([]string{"1", "3"}, nil)
+				return m
+			}(),
+			fn: func(s *Service) error {
+				_, err := s.DeleteSpots(ctx, []string{"1", "2", "3"})
+				return err
+			},
+			expectedEvents: []eventbus.Event{
+				{
+					Type:       eventbus.EventSpotDeleted,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "1"},
+					OccurredAt: now,
+				},
+				{
+					Type:       eventbus.EventSpotDeleted,
+					ActorID:    "42",
+					Spot:       surf.Spot{ID: "3"},
+					OccurredAt: now,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "publish no event for invalid params",
+			spotStore: newMockSpotStore(),
+			fn: func(s *Service) error {
+				_, err := s.UpdateSpot(ctx, UpdateSpotParams{
+					ID:   "1",
+					Name: pconv.String(" "),
+				})
+				return err
+			},
+			expectedEvents: []eventbus.Event{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotName),
+		},
+		{
+			name: "publish no event during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpot", mock.Anything, "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			fn: func(s *Service) error {
+				return s.DeleteSpot(ctx, "1")
+			},
+			expectedEvents: []eventbus.Event{},
+			expectedErrFn:  assert.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher := eventbus.NewMemory()
+
+			s := NewService(test.spotStore, test.locationSource, WithEventPublisher(publisher))
+			s.now = func() time.Time { return now }
+
+			err := test.fn(s)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedEvents, publisher.Events())
+		})
+	}
+}
+
+func TestService_RestoreSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		id            string
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid spot id",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			id:            "",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error when spot is not found",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RestoreSpot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id:            "1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return spot using sanitized id without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RestoreSpot", auditedBy("admin-1"), "1").
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Locality:    "Locality 1",
+								CountryCode: "kz",
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+							CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: " 1 ",
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+				CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			spot, err := s.RestoreSpot(test.ctxFn(), test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestService_Location(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctxFn            func() context.Context
+		locationSource   geo.LocationSource
+		coord            geo.Coordinates
+		expectedLocation geo.Location
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			locationSource: newMockLocationSource(),
+			coord: geo.Coordinates{
+				Latitude:  -91,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			locationSource: newMockLocationSource(),
+			coord: geo.Coordinates{
+				Latitude:  -91,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid latitude",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			locationSource: newMockLocationSource(),
+			coord: geo.Coordinates{
+				Latitude:  -91,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidLatitude),
+		},
+		{
+			name: "return error for invalid longitude",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			locationSource: newMockLocationSource(),
+			coord: geo.Coordinates{
+				Latitude:  -90,
+				Longitude: 181,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidLongitude),
+		},
+		{
+			name: "return error during unexpected location source failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  -90,
+						Longitude: 180,
+					}).
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			coord: geo.Coordinates{
+				Latitude:  -90,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name: "return error when location is not found",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  -90,
+						Longitude: 180,
+					}).
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			coord: geo.Coordinates{
+				Latitude:  -90,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{},
+			expectedErrFn:    testutil.IsError(geo.ErrLocationNotFound),
+		},
+		{
+			name: "return location without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  -90,
+						Longitude: 180,
+					}).
+					Return(
+						geo.Location{
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+							Coordinates: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: 180,
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			coord: geo.Coordinates{
+				Latitude:  -90,
+				Longitude: 180,
+			},
+			expectedLocation: geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  -90,
+					Longitude: 180,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(newMockSpotStore(), test.locationSource)
+
+			l, err := s.Location(test.ctxFn(), test.coord)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedLocation, l)
+		})
+	}
+}
+
+func TestService_Location_SlowSource(t *testing.T) {
+	const sourceDelay = 2 * time.Second
+
+	tests := []struct {
+		name          string
+		ctxFn         func() (context.Context, context.CancelFunc)
+		timeout       time.Duration
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error once location source times out",
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			timeout:       50 * time.Millisecond,
+			expectedErrFn: testutil.IsError(ErrLocationTimeout),
+		},
+		{
+			name: "return error once context is done before location source times out",
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			timeout:       time.Minute,
+			expectedErrFn: testutil.IsError(context.DeadlineExceeded),
+		},
+		{
+			name: "return error once context is done while location timeout is disabled",
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			timeout:       0,
+			expectedErrFn: testutil.IsError(context.DeadlineExceeded),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := test.ctxFn()
+			defer cancel()
+
+			ctx = jwt.ContextWith(ctx, jwt.Claims{
+				Role: jwt.RoleName(auth.RoleAdmin),
+			})
+
+			coord := geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			}
+
+			source := newMockLocationSource()
+			source.
+				On("Location", coord).
+				Return(geo.Location{}, nil).
+				After(sourceDelay)
+
+			s := NewService(nil, source, WithLocationTimeout(test.timeout))
+
+			start := time.Now()
+			_, err := s.Location(ctx, coord)
+			test.expectedErrFn(t, err)
+			assert.Less(t, int64(time.Since(start)), int64(sourceDelay))
+		})
+	}
+}
+
+func TestService_Locations(t *testing.T) {
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	tests := []struct {
+		name            string
+		ctxFn           func() context.Context
+		locationSource  geo.LocationSource
+		coords          []geo.Coordinates
+		expectedResults []LocationsEntryResult
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			locationSource:  newMockLocationSource(),
+			coords:          []geo.Coordinates{{Latitude: 1, Longitude: 2}},
+			expectedResults: nil,
+			expectedErrFn:   testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:            "return error for no coordinates",
+			ctxFn:           adminCtx,
+			locationSource:  newMockLocationSource(),
+			coords:          nil,
+			expectedResults: nil,
+			expectedErrFn:   testutil.AreValidationErrors(ErrInvalidCoordinates),
+		},
+		{
+			name:            "return error for too many coordinates",
+			ctxFn:           adminCtx,
+			locationSource:  newMockLocationSource(),
+			coords:          make([]geo.Coordinates, maxLocationCoordinates+1),
+			expectedResults: nil,
+			expectedErrFn:   testutil.AreValidationErrors(ErrInvalidCoordinates),
+		},
+		{
+			name:  "return error during unexpected location source failure",
+			ctxFn: adminCtx,
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{Latitude: 1, Longitude: 2}).
+					Return(geo.Location{Locality: "Locality 1"}, nil).
+					Maybe()
+				m.
+					On("Location", geo.Coordinates{Latitude: 3, Longitude: 4}).
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			coords: []geo.Coordinates{
+				{Latitude: 1, Longitude: 2},
+				{Latitude: 3, Longitude: 4},
+			},
+			expectedResults: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name:  "return results in order of coordinates without error",
+			ctxFn: adminCtx,
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{Latitude: 1, Longitude: 2}).
+					Return(geo.Location{Locality: "Locality 1", CountryCode: "kz"}, nil)
+				m.
+					On("Location", geo.Coordinates{Latitude: 3, Longitude: 4}).
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				m.
+					On("Location", geo.Coordinates{Latitude: 5, Longitude: 6}).
+					Return(geo.Location{Locality: "Locality 3", CountryCode: "us"}, nil)
+				return m
+			}(),
+			coords: []geo.Coordinates{
+				{Latitude: 1, Longitude: 2},
+				{Latitude: 3, Longitude: 4},
+				{Latitude: 91, Longitude: 181},
+				{Latitude: 5, Longitude: 6},
+			},
+			expectedResults: []LocationsEntryResult{
+				{
+					Location: geo.Location{Locality: "Locality 1", CountryCode: "kz"},
+				},
+				{
+					Err: geo.ErrLocationNotFound,
+				},
+				{
+					Err: valerra.NewErrors(ErrInvalidLatitude, ErrInvalidLongitude),
+				},
+				{
+					Location: geo.Location{Locality: "Locality 3", CountryCode: "us"},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(newMockSpotStore(), test.locationSource)
+
+			results, err := s.Locations(test.ctxFn(), test.coords)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResults, results)
+		})
+	}
+}
+
+// countingLocationSource records the highest number of locations looked up at the
+// same time.
+type countingLocationSource struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	active  int
+	maxSeen int
+}
+
+func (s *countingLocationSource) Location(c geo.Coordinates) (geo.Location, error) {
+	s.mu.Lock()
+	s.active++
+	if s.active > s.maxSeen {
+		s.maxSeen = s.active
+	}
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+
+	return geo.Location{Coordinates: c}, nil
+}
+
+func TestService_Locations_Concurrency(t *testing.T) {
+	source := &countingLocationSource{
+		delay: 10 * time.Millisecond,
+	}
+
+	const concurrency = 3
+
+	coords := make([]geo.Coordinates, 3*concurrency)
+	for i := range coords {
+		coords[i] = geo.Coordinates{Latitude: float64(i)}
+	}
+
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	results, err := NewService(nil, source, WithLocationsConcurrency(concurrency)).Locations(ctx, coords)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i, r := range results {
+		assert.Equal(t, coords[i], r.Location.Coordinates)
+	}
+	assert.LessOrEqual(t, source.maxSeen, concurrency)
+	assert.Greater(t, source.maxSeen, 1)
+}
+
+func TestService_Locations_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	}))
+	cancel()
+
+	_, err := NewService(nil, &countingLocationSource{}).Locations(ctx, []geo.Coordinates{
+		{Latitude: 1, Longitude: 2},
+	})
+	testutil.IsError(context.Canceled)(t, err)
+}
+
+func TestService_ExportSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        ExportSpotsParams
+		expectedIDs   []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			params:        ExportSpotsParams{},
+			expectedIDs:   nil,
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			params:        ExportSpotsParams{},
+			expectedIDs:   nil,
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid params",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: ExportSpotsParams{
+				CountryCode: "invalid",
+				SearchQuery: strutil.RepeatRune('a', 101),
+			},
+			expectedIDs:   nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSearchQuery, ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("IterateSpots", mock.Anything, surf.SpotIterationParams{}, mock.Anything).
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			params:        ExportSpotsParams{},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "iterate over spots without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("IterateSpots", mock.Anything, surf.SpotIterationParams{
+						CountryCode: "kz",
+						SearchQuery: surf.SpotSearchQuery{
+							Query:      "query",
+							WithSpotID: true,
+						},
+					}, mock.Anything).
+					Return(nil).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(surf.Spot) error)
+						_ = fn(surf.Spot{ID: "1"})
+						_ = fn(surf.Spot{ID: "2"})
+					})
+				return m
+			}(),
+			params: ExportSpotsParams{
+				CountryCode: " KZ ",
+				SearchQuery: " query ",
+			},
+			expectedIDs:   []string{"1", "2"},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			var ids []string
+			err := s.ExportSpots(test.ctxFn(), test.params, func(s surf.Spot) error {
+				ids = append(ids, s.ID)
+				return nil
+			})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestService_SuggestSpotLocation(t *testing.T) {
+	tests := []struct {
+		name               string
+		ctxFn              func() context.Context
+		spotStore          SpotStore
+		locationSource     geo.LocationSource
+		id                 string
+		expectedSuggestion SpotLocationSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:          newMockSpotStore(),
+			locationSource:     newMockLocationSource(),
+			id:                 "1",
+			expectedSuggestion: SpotLocationSuggestion{},
+			expectedErrFn:      testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:          newMockSpotStore(),
+			locationSource:     newMockLocationSource(),
+			id:                 "1",
+			expectedSuggestion: SpotLocationSuggestion{},
+			expectedErrFn:      testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid spot id",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:          newMockSpotStore(),
+			locationSource:     newMockLocationSource(),
+			id:                 " ",
+			expectedSuggestion: SpotLocationSuggestion{},
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error when spot is not found",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			locationSource:     newMockLocationSource(),
+			id:                 "1",
+			expectedSuggestion: SpotLocationSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error when location is not found",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			id:                 "1",
+			expectedSuggestion: SpotLocationSuggestion{},
+			expectedErrFn:      testutil.IsError(geo.ErrLocationNotFound),
+		},
+		{
+			name: "return suggestion without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(
+						surf.Spot{
+							ID:   "1",
+							Name: "Spot 1",
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{
+									Latitude:  1.23,
+									Longitude: 3.21,
+								},
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(
+						geo.Location{
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+							Region:      "Region 1",
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			id: " 1 ",
+			expectedSuggestion: SpotLocationSuggestion{
+				Spot: surf.Spot{
+					ID:   "1",
+					Name: "Spot 1",
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Region:      "Region 1",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, test.locationSource)
+
+			suggestion, err := s.SuggestSpotLocation(test.ctxFn(), test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+		})
+	}
+}
+
+func TestService_ApplySpotLocationSuggestion(t *testing.T) {
+	spotStoreWithSpot := func(updateEntry *surf.SpotUpdateEntry) SpotStore {
+		m := newMockSpotStore()
+		m.
+			On("Spot", mock.Anything, "1").
+			Return(
+				surf.Spot{
+					ID:   "1",
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Old locality",
+						CountryCode: "ru",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				nil,
+			)
+		if updateEntry != nil {
+			m.
+				On("UpdateSpot", mock.Anything, *updateEntry).
+				Return(
+					surf.Spot{
+						ID:   "1",
+						Name: "Spot 1",
+						Location: geo.Location{
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+						},
+					},
+					nil,
+				)
+		}
+		return m
+	}
+
+	locationSource := func(l geo.Location) geo.LocationSource {
+		m := newMockLocationSource()
+		m.
+			On("Location", geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			}).
+			Return(l, nil)
+		return m
+	}
+
+	tests := []struct {
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		locationSource geo.LocationSource
+		params         ApplySpotLocationSuggestionParams
+		expectedSpot   surf.Spot
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:      newMockSpotStore(),
+			locationSource: newMockLocationSource(),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:   "1",
+				Locality: true,
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			locationSource: newMockLocationSource(),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:   "1",
+				Locality: true,
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid params",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			locationSource: newMockLocationSource(),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID: "",
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrEmptySuggestionSelection),
+		},
+		{
+			name: "return error when spot is not found",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.On("Spot", mock.Anything, "1").Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			locationSource: newMockLocationSource(),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:   "1",
+				Locality: true,
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during unexpected location source failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: spotStoreWithSpot(nil),
+			locationSource: func() geo.LocationSource {
+				m := newMockLocationSource()
+				m.
+					On("Location", geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}).
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:   "1",
+				Locality: true,
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "apply only chosen suggestion",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: spotStoreWithSpot(&surf.SpotUpdateEntry{
+				ID:       "1",
+				Locality: pconv.String("Locality 1"),
+			}),
+			locationSource: locationSource(geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+			}),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:   " 1 ",
+				Locality: true,
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "skip chosen values that could not be suggested",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: spotStoreWithSpot(&surf.SpotUpdateEntry{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+			}),
+			locationSource: locationSource(geo.Location{
+				Locality:    "",
+				CountryCode: "kz",
+			}),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:      "1",
+				Locality:    true,
+				CountryCode: true,
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "apply chosen region suggestion",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: spotStoreWithSpot(&surf.SpotUpdateEntry{
+				ID:          "1",
+				CountryCode: pconv.String("kz"),
+				Region:      pconv.String("Region 1"),
+			}),
+			locationSource: locationSource(geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Region:      "Region 1",
+			}),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:      "1",
+				CountryCode: true,
+				Region:      true,
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot as is when none of chosen values could be suggested",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore:      spotStoreWithSpot(nil),
+			locationSource: locationSource(geo.Location{}),
+			params: ApplySpotLocationSuggestionParams{
+				SpotID:      "1",
+				Locality:    true,
+				CountryCode: true,
+				Region:      true,
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Old locality",
+					CountryCode: "ru",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, test.locationSource)
+
+			spot, err := s.ApplySpotLocationSuggestion(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestSpotChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        UpdateSpotParams
+		expectedEntry surf.SpotUpdateEntry
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for invalid params",
+			params: UpdateSpotParams{
+				ID:          " ",
+				Name:        pconv.String(" "),
+				Latitude:    pconv.Float64(-91),
+				Longitude:   pconv.Float64(181),
+				Locality:    pconv.String(" "),
+				CountryCode: pconv.String("zz"),
+			},
+			expectedEntry: surf.SpotUpdateEntry{},
+			expectedErrFn: testutil.AreValidationErrors(
+				ErrInvalidSpotID,
+				ErrInvalidSpotName,
+				ErrInvalidLatitude,
+				ErrInvalidLongitude,
+				ErrInvalidLocality,
+				ErrInvalidCountryCode,
+			),
+		},
+		{
+			name: "return sanitized entry for valid params",
+			params: UpdateSpotParams{
+				ID:            " 1 ",
+				Name:          pconv.String(" Spot 1 "),
+				Latitude:      pconv.Float64(1.23),
+				Region:        pconv.String(" Region 1 "),
+				ClearLocality: true,
+			},
+			expectedEntry: surf.SpotUpdateEntry{
+				ID:       "1",
+				Name:     pconv.String("Spot 1"),
+				Latitude: pconv.Float64(1.23),
+				Locality: pconv.String(""),
+				Region:   pconv.String("Region 1"),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry, err := SpotChange(test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedEntry, entry)
+		})
+	}
+}
+
+func TestService_SpotSuggestions(t *testing.T) {
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	suggestions := []surf.SpotSuggestion{
+		{
+			ID:     "11",
+			Status: surf.SpotSuggestionStatusPending,
+			Change: surf.SpotUpdateEntry{
+				ID:       "1",
+				Latitude: pconv.Float64(1.23),
+			},
+			CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		spotStore           SpotStore
+		params              SpotSuggestionsParams
+		expectedSuggestions []surf.SpotSuggestion
+		expectedErrFn       assert.ErrorAssertionFunc
+	}{
+		{
+			name:                "return error for unauthenticated request",
+			ctx:                 context.Background(),
+			spotStore:           newMockSpotStore(),
+			expectedSuggestions: nil,
+			expectedErrFn:       testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:      "return error for invalid status",
+			ctx:       ctx,
+			spotStore: newMockSpotStore(),
+			params: SpotSuggestionsParams{
+				Status: "approved",
+			},
+			expectedSuggestions: nil,
+			expectedErrFn:       testutil.AreValidationErrors(ErrInvalidSuggestionStatus),
+		},
+		{
+			name: "return error during unexpected failure",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestions", mock.Anything, surf.SpotSuggestionsParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(([]surf.SpotSuggestion)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedSuggestions: nil,
+			expectedErrFn:       assert.Error,
+		},
+		{
+			name: "return suggestions using default pagination",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestions", mock.Anything, surf.SpotSuggestionsParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(suggestions, nil)
+				return m
+			}(),
+			expectedSuggestions: suggestions,
+			expectedErrFn:       assert.NoError,
+		},
+		{
+			name: "return suggestions of sanitized status",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestions", mock.Anything, surf.SpotSuggestionsParams{
+						Limit:  100,
+						Offset: 5,
+						Status: surf.SpotSuggestionStatusPending,
+					}).
+					Return(suggestions, nil)
+				return m
+			}(),
+			params: SpotSuggestionsParams{
+				Limit:  1000,
+				Offset: 5,
+				Status: " Pending ",
+			},
+			expectedSuggestions: suggestions,
+			expectedErrFn:       assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			suggestions, err := s.SpotSuggestions(test.ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestions, suggestions)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_AuditEntries(t *testing.T) {
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	entries := []surf.AuditEntry{
+		{
+			ID:      "11",
+			ActorID: "admin-1",
+			Action:  surf.AuditActionUpdate,
+			SpotID:  "1",
+			Changes: []surf.AuditChange{
+				{Field: "name", Before: "Spot 1", After: "Updated spot 1"},
+			},
+			CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		spotStore       SpotStore
+		params          AuditEntriesParams
+		expectedEntries []surf.AuditEntry
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name:            "return error for unauthenticated request",
+			ctx:             context.Background(),
+			spotStore:       newMockSpotStore(),
+			expectedEntries: nil,
+			expectedErrFn:   testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctx: jwt.ContextWith(context.Background(), jwt.Claims{
+				Role: "",
+			}),
+			spotStore:       newMockSpotStore(),
+			expectedEntries: nil,
+			expectedErrFn:   testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error during unexpected failure",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("AuditEntries", mock.Anything, surf.AuditEntriesParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(([]surf.AuditEntry)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedEntries: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return entries using default pagination",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("AuditEntries", mock.Anything, surf.AuditEntriesParams{
+						Limit:  10,
+						Offset: 0,
+					}).
+					Return(entries, nil)
+				return m
+			}(),
+			expectedEntries: entries,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return entries of sanitized spot id",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("AuditEntries", mock.Anything, surf.AuditEntriesParams{
+						Limit:  100,
+						Offset: 5,
+						SpotID: "1",
+					}).
+					Return(entries, nil)
+				return m
+			}(),
+			params: AuditEntriesParams{
+				Limit:  1000,
+				Offset: 5,
+				SpotID: " 1 ",
+			},
+			expectedEntries: entries,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name:      "return error for period ending before it starts",
+			ctx:       ctx,
+			spotStore: newMockSpotStore(),
+			params: AuditEntriesParams{
+				From: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedEntries: nil,
+			expectedErrFn:   testutil.AreValidationErrors(ErrInvalidTo),
+		},
+		{
+			name: "return entries of sanitized actor id within period",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("AuditEntries", mock.Anything, surf.AuditEntriesParams{
+						Limit:   10,
+						Offset:  0,
+						ActorID: "admin-1",
+						From:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						To:      time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+					}).
+					Return(entries, nil)
+				return m
+			}(),
+			params: AuditEntriesParams{
+				ActorID: " admin-1 ",
+				From:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expectedEntries: entries,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return entries of open-ended period",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("AuditEntries", mock.Anything, surf.AuditEntriesParams{
+						Limit:  10,
+						Offset: 0,
+						From:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					}).
+					Return(entries, nil)
+				return m
+			}(),
+			params: AuditEntriesParams{
+				From: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedEntries: entries,
+			expectedErrFn:   assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			entries, err := s.AuditEntries(test.ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedEntries, entries)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ApproveSpotSuggestion(t *testing.T) {
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	suggestion := surf.SpotSuggestion{
+		ID:     "11",
+		Status: surf.SpotSuggestionStatusPending,
+		Change: surf.SpotUpdateEntry{
+			ID:       "1",
+			Latitude: pconv.Float64(1.23),
+			Locality: pconv.String(""),
+		},
+		Note:      "Note 1",
+		CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	applied := suggestion
+	applied.Status = surf.SpotSuggestionStatusApplied
+	applied.ResolvedAt = func() *time.Time {
+		t := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		return &t
+	}()
+
+	tests := []struct {
+		name               string
+		ctx                context.Context
+		spotStore          SpotStore
+		id                 string
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "return error for unauthenticated request",
+			ctx:                context.Background(),
+			spotStore:          newMockSpotStore(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctx: jwt.ContextWith(context.Background(), jwt.Claims{
+				Role: "",
+			}),
+			spotStore:          newMockSpotStore(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name:               "return error for empty id",
+			ctx:                ctx,
+			spotStore:          newMockSpotStore(),
+			id:                 " ",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidSuggestionID),
+		},
+		{
+			name: "return error when suggestion is not found",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestion", mock.Anything, "11").
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionNotFound),
+		},
+		{
+			name: "return error when suggestion is already resolved",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestion", mock.Anything, "11").
+					Return(applied, nil)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionResolved),
+		},
+		{
+			name: "return error when spot is not found",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestion", mock.Anything, "11").
+					Return(suggestion, nil)
+				m.
+					On("UpdateSpot", mock.Anything, suggestion.Change).
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error when suggestion gets resolved concurrently",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestion", mock.Anything, "11").
+					Return(suggestion, nil)
+				m.
+					On("UpdateSpot", mock.Anything, suggestion.Change).
+					Return(surf.Spot{ID: "1"}, nil)
+				m.
+					On("ResolveSpotSuggestion", mock.Anything, "11", surf.SpotSuggestionStatusApplied).
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionResolved),
+		},
+		{
+			name: "return applied suggestion",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotSuggestion", mock.Anything, "11").
+					Return(suggestion, nil)
+				m.
+					On("UpdateSpot", mock.Anything, suggestion.Change).
+					Return(surf.Spot{ID: "1"}, nil)
+				m.
+					On("ResolveSpotSuggestion", mock.Anything, "11", surf.SpotSuggestionStatusApplied).
+					Return(applied, nil)
+				return m
+			}(),
+			id:                 " 11 ",
+			expectedSuggestion: applied,
+			expectedErrFn:      assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			suggestion, err := s.ApproveSpotSuggestion(test.ctx, test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_RejectSpotSuggestion(t *testing.T) {
+	ctx := jwt.ContextWith(context.Background(), jwt.Claims{
+		Role: jwt.RoleName(auth.RoleAdmin),
+	})
+
+	rejected := surf.SpotSuggestion{
+		ID:     "11",
+		Status: surf.SpotSuggestionStatusRejected,
+		Change: surf.SpotUpdateEntry{
+			ID:   "1",
+			Name: pconv.String("Spot 1"),
+		},
+		CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		ResolvedAt: func() *time.Time {
+			t := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+			return &t
+		}(),
+	}
+
+	tests := []struct {
+		name               string
+		ctx                context.Context
+		spotStore          SpotStore
+		id                 string
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "return error for unauthenticated request",
+			ctx:                context.Background(),
+			spotStore:          newMockSpotStore(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:               "return error for empty id",
+			ctx:                ctx,
+			spotStore:          newMockSpotStore(),
+			id:                 "",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidSuggestionID),
+		},
+		{
+			name: "return error when suggestion is already resolved",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ResolveSpotSuggestion", mock.Anything, "11", surf.SpotSuggestionStatusRejected).
+					Return(surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionResolved),
+		},
+		{
+			name: "return rejected suggestion",
+			ctx:  ctx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ResolveSpotSuggestion", mock.Anything, "11", surf.SpotSuggestionStatusRejected).
+					Return(rejected, nil)
+				return m
+			}(),
+			id:                 "11",
+			expectedSuggestion: rejected,
+			expectedErrFn:      assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			suggestion, err := s.RejectSpotSuggestion(test.ctx, test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Countries(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		params         CountriesParams
+		expectedCounts []surf.CountrySpotCount
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:      newMockSpotStore(),
+			params:         CountriesParams{},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			params:         CountriesParams{},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid search query",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: CountriesParams{
+				SearchQuery: strutil.RepeatRune('a', 101),
+			},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSearchQuery),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:         CountriesParams{},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return counts using sanitized search query without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{
+						SearchQuery: surf.SpotSearchQuery{
+							Query: "query",
+						},
+					}).
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
+						},
+					}, nil)
+				return m
+			}(),
+			params: CountriesParams{
+				SearchQuery: " query ",
+			},
+			expectedCounts: []surf.CountrySpotCount{
+				{
+					CountryCode: "kz",
+					Count:       12,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			counts, err := s.Countries(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+		})
+	}
+}
+
+func TestService_SpotCountsByCountry(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		expectedCounts []surf.CountrySpotCount
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:      newMockSpotStore(),
+			expectedCounts: nil,
+			expectedErrFn:  testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:      newMockSpotStore(),
+			expectedCounts: nil,
+			expectedErrFn:  testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return counts with spots without country under unknown code without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{}).
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
+						},
+						{
+							CountryCode: "",
+							Count:       3,
+						},
+					}, nil)
+				return m
+			}(),
+			expectedCounts: []surf.CountrySpotCount{
+				{
+					CountryCode: "kz",
+					Count:       12,
+				},
+				{
+					CountryCode: CountryCodeUnknown,
+					Count:       3,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			counts, err := s.SpotCountsByCountry(test.ctxFn())
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+		})
+	}
+}
+
+func TestService_SetSpotTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        SetSpotTagsParams
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			params:        SetSpotTagsParams{},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			params:        SetSpotTagsParams{},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid params",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SetSpotTagsParams{
+				SpotID: " ",
+				Tags:   []string{strutil.RepeatRune('a', 31)},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidTags),
+		},
+		{
+			name: "return error for too many tags",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: SetSpotTagsParams{
+				SpotID: "1",
+				Tags:   []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTags),
+		},
+		{
+			name: "return error for unexisting spot",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SetSpotTags", mock.Anything, "1", []string{"reef"}).
+					Return(surf.ErrSpotNotFound)
+				return m
+			}(),
+			params: SetSpotTagsParams{
+				SpotID: "1",
+				Tags:   []string{"reef"},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SetSpotTags", mock.Anything, "1", []string{"reef"}).
+					Return(nil)
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params: SetSpotTagsParams{
+				SpotID: "1",
+				Tags:   []string{"reef"},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spot using sanitized params without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					StandardClaims: jwtgo.StandardClaims{Subject: "admin-1"},
+					Role:           jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SetSpotTags", auditedBy("admin-1"), "1", []string{"reef", "point"}).
+					Return(nil)
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{
+						ID:   "1",
+						Tags: []string{"point", "reef"},
+					}, nil)
+				return m
+			}(),
+			params: SetSpotTagsParams{
+				SpotID: " 1 ",
+				Tags:   []string{" Reef ", "point", "", "reef"},
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Tags: []string{"point", "reef"},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			spot, err := s.SetSpotTags(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestService_UploadSpotPhoto(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		mediaStorage  media.Storage
+		params        UploadSpotPhotoParams
+		expectedPhoto SpotPhoto
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:     newMockSpotStore(),
+			mediaStorage:  newMockMediaStorage(),
+			params:        UploadSpotPhotoParams{},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name:         "return error for invalid params",
+			ctxFn:        adminCtx,
+			spotStore:    newMockSpotStore(),
+			mediaStorage: newMockMediaStorage(),
+			params: UploadSpotPhotoParams{
+				SpotID: " ",
+			},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidPhoto),
+		},
+		{
+			name:         "return error for unsupported photo type",
+			ctxFn:        adminCtx,
+			spotStore:    newMockSpotStore(),
+			mediaStorage: newMockMediaStorage(),
+			params: UploadSpotPhotoParams{
+				SpotID:  "1",
+				Content: []byte("GIF89a"),
+			},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: testutil.AreValidationErrors(ErrUnsupportedPhotoType),
+		},
+		{
+			name:      "return error for missing media storage",
+			ctxFn:     adminCtx,
+			spotStore: newMockSpotStore(),
+			params: UploadSpotPhotoParams{
+				SpotID:  "1",
+				Content: png,
+			},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: testutil.IsError(ErrPhotosUnavailable),
+		},
+		{
+			name:  "return error for unexisting spot",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotPhoto", mock.Anything, surf.SpotPhotoEntry{
+						SpotID:      "1",
+						ContentType: "image/png",
+						Size:        int64(len(png)),
+					}).
+					Return(surf.SpotPhoto{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			mediaStorage: newMockMediaStorage(),
+			params: UploadSpotPhotoParams{
+				SpotID:  "1",
+				Content: png,
+			},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name:  "return error and delete photo during media storage failure",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotPhoto", mock.Anything, mock.Anything).
+					Return(surf.SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/png"}, nil)
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(surf.SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/png"}, nil).
+					Once()
+				return m
+			}(),
+			mediaStorage: func() media.Storage {
+				m := newMockMediaStorage()
+				m.
+					On("Put", mock.Anything, "spots/1/2.png", mock.Anything).
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			params: UploadSpotPhotoParams{
+				SpotID:  "1",
+				Content: png,
+			},
+			expectedPhoto: SpotPhoto{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:  "return photo using sanitized params without error",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotPhoto", mock.Anything, surf.SpotPhotoEntry{
+						SpotID:      "1",
+						ContentType: "image/png",
+						Size:        int64(len(png)),
+					}).
+					Return(surf.SpotPhoto{
+						ID:          "2",
+						SpotID:      "1",
+						ContentType: "image/png",
+						Size:        int64(len(png)),
+						CreatedAt:   createdAt,
+					}, nil)
+				return m
+			}(),
+			mediaStorage: func() media.Storage {
+				m := newMockMediaStorage()
+				m.
+					On("Put", mock.Anything, "spots/1/2.png", mock.Anything).
+					Return(nil)
+				return m
+			}(),
+			params: UploadSpotPhotoParams{
+				SpotID:  " 1 ",
+				Content: png,
+			},
+			expectedPhoto: SpotPhoto{
+				SpotPhoto: surf.SpotPhoto{
+					ID:          "2",
+					SpotID:      "1",
+					ContentType: "image/png",
+					Size:        int64(len(png)),
+					CreatedAt:   createdAt,
+				},
+				URL: "/media/spots/1/2.png",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.mediaStorage != nil {
+				opts = append(opts, WithMediaStorage(test.mediaStorage))
+			}
+			s := NewService(test.spotStore, newMockLocationSource(), opts...)
+
+			photo, err := s.UploadSpotPhoto(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPhoto, photo)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DeleteSpotPhoto(t *testing.T) {
+	adminCtx := func() context.Context {
+		return jwt.ContextWith(context.Background(), jwt.Claims{
+			Role: jwt.RoleName(auth.RoleAdmin),
+		})
+	}
+
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		mediaStorage  media.Storage
+		spotID        string
+		photoID       string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			mediaStorage:  newMockMediaStorage(),
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:          "return error for invalid params",
+			ctxFn:         adminCtx,
+			spotStore:     newMockSpotStore(),
+			mediaStorage:  newMockMediaStorage(),
+			spotID:        " ",
+			photoID:       "",
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidPhotoID),
+		},
+		{
+			name:          "return error for missing media storage",
+			ctxFn:         adminCtx,
+			spotStore:     newMockSpotStore(),
+			spotID:        "1",
+			photoID:       "2",
+			expectedErrFn: testutil.IsError(ErrPhotosUnavailable),
+		},
+		{
+			name:  "return error for unexisting photo",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(surf.SpotPhoto{}, surf.ErrSpotPhotoNotFound)
+				return m
+			}(),
+			mediaStorage:  newMockMediaStorage(),
+			spotID:        "1",
+			photoID:       "2",
+			expectedErrFn: testutil.IsError(surf.ErrSpotPhotoNotFound),
+		},
+		{
+			name:  "return error during media storage failure",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(surf.SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/jpeg"}, nil)
+				return m
+			}(),
+			mediaStorage: func() media.Storage {
+				m := newMockMediaStorage()
+				m.
+					On("Delete", mock.Anything, "spots/1/2.jpg").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			spotID:        "1",
+			photoID:       "2",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:  "return no error using sanitized params",
+			ctxFn: adminCtx,
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("DeleteSpotPhoto", mock.Anything, "1", "2").
+					Return(surf.SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/jpeg"}, nil)
+				return m
+			}(),
+			mediaStorage: func() media.Storage {
+				m := newMockMediaStorage()
+				m.
+					On("Delete", mock.Anything, "spots/1/2.jpg").
+					Return(nil)
+				return m
+			}(),
+			spotID:        " 1 ",
+			photoID:       " 2 ",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.mediaStorage != nil {
+				opts = append(opts, WithMediaStorage(test.mediaStorage))
+			}
+			s := NewService(test.spotStore, newMockLocationSource(), opts...)
+
+			err := s.DeleteSpotPhoto(test.ctxFn(), test.spotID, test.photoID)
+			test.expectedErrFn(t, err)
+		})
+	}
+}
+
+func TestService_Localities(t *testing.T) {
+	tests := []struct {
+		name               string
+		ctxFn              func() context.Context
+		spotStore          SpotStore
+		params             LocalitiesParams
+		expectedLocalities []string
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthorized request",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: "",
+				})
+			},
+			spotStore:          newMockSpotStore(),
+			params:             LocalitiesParams{},
+			expectedLocalities: nil,
+			expectedErrFn:      testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid params",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: newMockSpotStore(),
+			params: LocalitiesParams{
+				Prefix:      " k ",
+				CountryCode: "zz",
+			},
+			expectedLocalities: nil,
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidLocalityPrefix, ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Localities", mock.Anything, surf.LocalitiesParams{
+						Prefix: "ka",
+						Limit:  10,
+					}).
+					Return(([]string)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: LocalitiesParams{
+				Prefix: "ka",
+			},
+			expectedLocalities: nil,
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return localities using sanitized params without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Localities", mock.Anything, surf.LocalitiesParams{
+						Prefix:      "ka",
+						CountryCode: "kz",
+						Limit:       50,
+					}).
+					Return([]string{"Kapchagay", "Karaganda"}, nil)
+				return m
+			}(),
+			params: LocalitiesParams{
+				Prefix:      " ka ",
+				CountryCode: " KZ ",
+				Limit:       100,
+			},
+			expectedLocalities: []string{"Kapchagay", "Karaganda"},
+			expectedErrFn:      assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			localities, err := s.Localities(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedLocalities, localities)
+		})
+	}
+}
+
+func TestService_SpotsOverTime(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
 
-func TestService_DeleteSpot(t *testing.T) {
 	tests := []struct {
-		name          string
-		ctxFn         func() context.Context
-		spotStore     SpotStore
-		id            string
-		expectedErrFn assert.ErrorAssertionFunc
+		name           string
+		ctxFn          func() context.Context
+		spotStore      SpotStore
+		params         SpotsOverTimeParams
+		expectedCounts []surf.SpotCreationCount
+		expectedErrFn  assert.ErrorAssertionFunc
 	}{
-		{
-			name: "return error for unauthenticated request",
-			ctxFn: func() context.Context {
-				return context.Background()
-			},
-			spotStore:     newMockSpotStore(),
-			id:            "",
-			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
-		},
 		{
 			name: "return error for unauthorized request",
 			ctxFn: func() context.Context {
@@ -1377,40 +5961,41 @@ func TestService_DeleteSpot(t *testing.T) {
 					Role: "",
 				})
 			},
-			spotStore:     newMockSpotStore(),
-			id:            "",
-			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+			spotStore:      newMockSpotStore(),
+			params:         SpotsOverTimeParams{},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.IsError(jwt.ErrMismatchedRole),
 		},
 		{
-			name: "return error for invalid spot id",
+			name: "return error for missing params",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			spotStore:     newMockSpotStore(),
-			id:            "",
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+			spotStore:      newMockSpotStore(),
+			params:         SpotsOverTimeParams{},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidFrom, ErrInvalidTo),
 		},
 		{
-			name: "return error during spot store failure",
+			name: "return error for invalid params",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			spotStore: func() SpotStore {
-				m := newMockSpotStore()
-				m.
-					On("DeleteSpot", "1").
-					Return(errors.New("something went wrong"))
-				return m
-			}(),
-			id:            "1",
-			expectedErrFn: assert.Error,
+			spotStore: newMockSpotStore(),
+			params: SpotsOverTimeParams{
+				From:     to,
+				To:       from,
+				Interval: "year",
+			},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidTo, ErrInvalidInterval),
 		},
 		{
-			name: "return spot using sanitized id without error",
+			name: "return error during spot store failure",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
@@ -1419,15 +6004,23 @@ func TestService_DeleteSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("DeleteSpot", "1").
-					Return(nil)
+					On("SpotCreationCounts", mock.Anything, surf.SpotCreationCountsParams{
+						From:     from,
+						To:       to,
+						Interval: surf.TimeIntervalMonth,
+					}).
+					Return(([]surf.SpotCreationCount)(nil), errors.New("something went wrong"))
 				return m
 			}(),
-			id:            " 1 ",
-			expectedErrFn: assert.NoError,
+			params: SpotsOverTimeParams{
+				From: from,
+				To:   to,
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
 		},
 		{
-			name: "return spot without error",
+			name: "return counts using sanitized params without error",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
@@ -1436,11 +6029,30 @@ func TestService_DeleteSpot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("DeleteSpot", "1").
-					Return(nil)
+					On("SpotCreationCounts", mock.Anything, surf.SpotCreationCountsParams{
+						From:     from,
+						To:       to,
+						Interval: surf.TimeIntervalWeek,
+					}).
+					Return([]surf.SpotCreationCount{
+						{
+							BucketStart: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+							Count:       2,
+						},
+					}, nil)
 				return m
 			}(),
-			id:            "1",
+			params: SpotsOverTimeParams{
+				From:     from.In(time.FixedZone("UTC+6", 6*60*60)),
+				To:       to,
+				Interval: " WEEK ",
+			},
+			expectedCounts: []surf.SpotCreationCount{
+				{
+					BucketStart: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+					Count:       2,
+				},
+			},
 			expectedErrFn: assert.NoError,
 		},
 	}
@@ -1449,33 +6061,36 @@ func TestService_DeleteSpot(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			s := NewService(test.spotStore, newMockLocationSource())
 
-			err := s.DeleteSpot(test.ctxFn(), test.id)
+			counts, err := s.SpotsOverTime(test.ctxFn(), test.params)
 			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
 		})
 	}
 }
 
-func TestService_Location(t *testing.T) {
+func TestService_ReplaceReferenceSet(t *testing.T) {
+	points := []surf.ReferencePoint{
+		{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+		{Name: "Perth", Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86}},
+	}
+
 	tests := []struct {
-		name             string
-		ctxFn            func() context.Context
-		locationSource   geo.LocationSource
-		coord            geo.Coordinates
-		expectedLocation geo.Location
-		expectedErrFn    assert.ErrorAssertionFunc
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		params        ReplaceReferenceSetParams
+		expectedCount int
+		expectedErrFn assert.ErrorAssertionFunc
 	}{
 		{
 			name: "return error for unauthenticated request",
 			ctxFn: func() context.Context {
 				return context.Background()
 			},
-			locationSource: newMockLocationSource(),
-			coord: geo.Coordinates{
-				Latitude:  -91,
-				Longitude: 180,
-			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    testutil.IsError(jwt.ErrClaimsNotFound),
+			spotStore:     newMockSpotStore(),
+			params:        ReplaceReferenceSetParams{},
+			expectedCount: 0,
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
 		},
 		{
 			name: "return error for unauthorized request",
@@ -1484,142 +6099,271 @@ func TestService_Location(t *testing.T) {
 					Role: "",
 				})
 			},
-			locationSource: newMockLocationSource(),
-			coord: geo.Coordinates{
-				Latitude:  -91,
-				Longitude: 180,
+			spotStore:     newMockSpotStore(),
+			params:        ReplaceReferenceSetParams{},
+			expectedCount: 0,
+			expectedErrFn: testutil.IsError(jwt.ErrMismatchedRole),
+		},
+		{
+			name: "return error for invalid params",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
 			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    testutil.IsError(jwt.ErrMismatchedRole),
+			spotStore: newMockSpotStore(),
+			params: ReplaceReferenceSetParams{
+				Set:    "air/ports",
+				Points: nil,
+			},
+			expectedCount: 0,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidReferenceSet, ErrInvalidReferencePoints),
 		},
 		{
-			name: "return error for invalid latitude",
+			name: "return error for invalid points",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			locationSource: newMockLocationSource(),
-			coord: geo.Coordinates{
-				Latitude:  -91,
-				Longitude: 180,
+			spotStore: newMockSpotStore(),
+			params: ReplaceReferenceSetParams{
+				Set: "airports",
+				Points: []surf.ReferencePoint{
+					{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+					{Name: " Aktau ", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+				},
 			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidLatitude),
+			expectedCount: 0,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidReferencePoints),
 		},
 		{
-			name: "return error for invalid longitude",
+			name: "return error for invalid coordinates of points",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			locationSource: newMockLocationSource(),
-			coord: geo.Coordinates{
-				Latitude:  -90,
-				Longitude: 181,
+			spotStore: newMockSpotStore(),
+			params: ReplaceReferenceSetParams{
+				Set: "airports",
+				Points: []surf.ReferencePoint{
+					{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 91, Longitude: 51.09}},
+				},
 			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidLongitude),
+			expectedCount: 0,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidReferencePoints),
 		},
 		{
-			name: "return error during unexpected location source failure",
+			name: "return error during point replacement",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			locationSource: func() geo.LocationSource {
-				m := newMockLocationSource()
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
 				m.
-					On("Location", geo.Coordinates{
-						Latitude:  -90,
-						Longitude: 180,
-					}).
-					Return(geo.Location{}, errors.New("something went wrong"))
+					On("ReplaceReferencePoints", mock.Anything, "airports", points).
+					Return(errors.New("something went wrong"))
 				return m
 			}(),
-			coord: geo.Coordinates{
-				Latitude:  -90,
-				Longitude: 180,
+			params: ReplaceReferenceSetParams{
+				Set:    "airports",
+				Points: points,
 			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    assert.Error,
+			expectedCount: 0,
+			expectedErrFn: assert.Error,
 		},
 		{
-			name: "return error when location is not found",
+			name: "replace points and backfill distances without error",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			locationSource: func() geo.LocationSource {
-				m := newMockLocationSource()
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
 				m.
-					On("Location", geo.Coordinates{
-						Latitude:  -90,
-						Longitude: 180,
-					}).
-					Return(geo.Location{}, geo.ErrLocationNotFound)
+					On("ReplaceReferencePoints", mock.Anything, "airports", points).
+					Return(nil)
+				m.
+					On("IterateSpots", mock.Anything, surf.SpotIterationParams{}, mock.Anything).
+					Return(nil).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(surf.Spot) error)
+						_ = fn(surf.Spot{
+							ID: "1",
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{Latitude: 43.65, Longitude: 51.17},
+							},
+						})
+					})
+				m.
+					On("ReplaceSpotReferenceDistances", mock.Anything, "airports", mock.MatchedBy(
+						func(ds []surf.SpotReferenceDistance) bool {
+							return len(ds) == 1 && ds[0].SpotID == "1" && ds[0].PointName == "Aktau" &&
+								ds[0].DistanceKm > 24 && ds[0].DistanceKm < 25
+						},
+					)).
+					Return(nil)
 				return m
 			}(),
-			coord: geo.Coordinates{
-				Latitude:  -90,
-				Longitude: 180,
+			params: ReplaceReferenceSetParams{
+				Set: " Airports ",
+				Points: []surf.ReferencePoint{
+					{Name: " Aktau ", Coordinates: points[0].Coordinates},
+					points[1],
+				},
 			},
-			expectedLocation: geo.Location{},
-			expectedErrFn:    testutil.IsError(geo.ErrLocationNotFound),
+			expectedCount: 1,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore, newMockLocationSource())
+
+			count, err := s.ReplaceReferenceSet(test.ctxFn(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCount, count)
+		})
+	}
+}
+
+func TestService_BackfillReferenceDistances(t *testing.T) {
+	points := []surf.ReferencePoint{
+		{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+		{Name: "Perth", Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86}},
+	}
+
+	tests := []struct {
+		name          string
+		ctxFn         func() context.Context
+		spotStore     SpotStore
+		set           string
+		expectedCount int
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unauthenticated request",
+			ctxFn: func() context.Context {
+				return context.Background()
+			},
+			spotStore:     newMockSpotStore(),
+			set:           "airports",
+			expectedCount: 0,
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
 		},
 		{
-			name: "return location without error",
+			name: "return error for invalid set",
 			ctxFn: func() context.Context {
 				return jwt.ContextWith(context.Background(), jwt.Claims{
 					Role: jwt.RoleName(auth.RoleAdmin),
 				})
 			},
-			locationSource: func() geo.LocationSource {
-				m := newMockLocationSource()
+			spotStore:     newMockSpotStore(),
+			set:           " ",
+			expectedCount: 0,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidReferenceSet),
+		},
+		{
+			name: "return error for unexisting set",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
+			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
 				m.
-					On("Location", geo.Coordinates{
-						Latitude:  -90,
-						Longitude: 180,
-					}).
-					Return(
-						geo.Location{
-							Locality:    "Locality 1",
-							CountryCode: "kz",
-							Coordinates: geo.Coordinates{
-								Latitude:  -90,
-								Longitude: 180,
-							},
-						},
-						nil,
-					)
+					On("ReferencePoints", mock.Anything, "airports").
+					Return([]surf.ReferencePoint(nil), nil)
 				return m
 			}(),
-			coord: geo.Coordinates{
-				Latitude:  -90,
-				Longitude: 180,
+			set:           "airports",
+			expectedCount: 0,
+			expectedErrFn: testutil.IsError(ErrReferenceSetNotFound),
+		},
+		{
+			name: "return error during spot iteration",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
 			},
-			expectedLocation: geo.Location{
-				Locality:    "Locality 1",
-				CountryCode: "kz",
-				Coordinates: geo.Coordinates{
-					Latitude:  -90,
-					Longitude: 180,
-				},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ReferencePoints", mock.Anything, "airports").
+					Return(points, nil)
+				m.
+					On("IterateSpots", mock.Anything, surf.SpotIterationParams{}, mock.Anything).
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			set:           "airports",
+			expectedCount: 0,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "backfill distances to nearest points without error",
+			ctxFn: func() context.Context {
+				return jwt.ContextWith(context.Background(), jwt.Claims{
+					Role: jwt.RoleName(auth.RoleAdmin),
+				})
 			},
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ReferencePoints", mock.Anything, "airports").
+					Return(points, nil)
+				m.
+					On("IterateSpots", mock.Anything, surf.SpotIterationParams{}, mock.Anything).
+					Return(nil).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(surf.Spot) error)
+						_ = fn(surf.Spot{
+							ID: "1",
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86},
+							},
+						})
+						_ = fn(surf.Spot{
+							ID: "2",
+							Location: geo.Location{
+								Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09},
+							},
+						})
+					})
+				m.
+					On("ReplaceSpotReferenceDistances", mock.Anything, "airports", []surf.SpotReferenceDistance{
+						{
+							SpotID:            "1",
+							ReferenceDistance: surf.ReferenceDistance{PointName: "Perth", DistanceKm: 0},
+						},
+						{
+							SpotID:            "2",
+							ReferenceDistance: surf.ReferenceDistance{PointName: "Aktau", DistanceKm: 0},
+						},
+					}).
+					Return(nil)
+				return m
+			}(),
+			set:           " airports ",
+			expectedCount: 2,
 			expectedErrFn: assert.NoError,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s := NewService(newMockSpotStore(), test.locationSource)
+			s := NewService(test.spotStore, newMockLocationSource())
 
-			l, err := s.Location(test.ctxFn(), test.coord)
+			count, err := s.BackfillReferenceDistances(test.ctxFn(), test.set)
 			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedLocation, l)
+			assert.Equal(t, test.expectedCount, count)
 		})
 	}
 }
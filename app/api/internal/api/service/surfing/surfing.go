@@ -1,11 +1,23 @@
 package surfing
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/media"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
 	"github.com/ztimes2/tolqin/app/api/pkg/paging"
 	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
@@ -19,6 +31,76 @@ const (
 	minOffset = 0
 
 	maxSearchQueryChars = 100
+
+	// markersLimit caps a number of spot markers that can be returned at once.
+	// It is deliberately high since markers are lightweight.
+	markersLimit = 5000
+
+	minRandomCount     = 1
+	maxRandomCount     = 20
+	defaultRandomCount = 1
+
+	maxNearbyRadiusKm     = 500
+	defaultNearbyRadiusKm = 50
+
+	// maxSpotNearbyCount caps a number of nearby spots embedded into a spot.
+	maxSpotNearbyCount = 10
+
+	// maxRouteSpots caps a number of spots that a route can be planned across.
+	maxRouteSpots = 25
+
+	// maxFavoriteBatchSpotIDs caps a number of spots that can be favorited and
+	// unfavorited at once.
+	maxFavoriteBatchSpotIDs = 100
+
+	maxTags     = 10
+	maxTagChars = 30
+
+	minScore = 1
+	maxScore = 5
+
+	maxSuggestionNoteChars = 500
+
+	// minLocalityPrefixChars prevents autocompleting localities by a prefix that
+	// is too short to narrow them down.
+	minLocalityPrefixChars = 2
+	maxLocalitiesLimit     = 50
+
+	// Localities and spots are limited within each group of the directory.
+	maxDirectoryLocalities     = 50
+	defaultDirectoryLocalities = 10
+	maxDirectorySpots          = 20
+	defaultDirectorySpots      = 5
+
+	minForecastDays     = 1
+	maxForecastDays     = 7
+	defaultForecastDays = 3
+
+	// defaultForecastCacheTTL keeps forecasts fresh enough while sparing the
+	// forecast source from fetching the same spot on every request.
+	defaultForecastCacheTTL = 15 * time.Minute
+
+	// maxTideDateDays limits how many days before or after today tides can be
+	// requested for.
+	maxTideDateDays = 7
+
+	// TideDateLayout is a format of dates that tides are requested for.
+	TideDateLayout = "2006-01-02"
+
+	// TagsMatchAll is used for filtering spots that have all of the given tags.
+	TagsMatchAll = "all"
+
+	// TagsMatchAny is used for filtering spots that have any of the given tags.
+	TagsMatchAny = "any"
+
+	// SearchModeSubstring is used for searching for spots whose names or
+	// localities contain the search query.
+	SearchModeSubstring = "substring"
+
+	// SearchModeFullText is used for searching for spots whose names or
+	// localities contain words of the search query in any order, ranked by
+	// relevance.
+	SearchModeFullText = "fulltext"
 )
 
 var (
@@ -28,49 +110,755 @@ var (
 	ErrInvalidNorthEastLongitude = errors.New("invalid north-east longitude")
 	ErrInvalidSouthWestLatitude  = errors.New("invalid south-west latitude")
 	ErrInvalidSouthWestLongitude = errors.New("invalid south-west longitude")
+	ErrInvalidBounds             = errors.New("invalid bounds")
 	ErrInvalidSpotID             = errors.New("invalid spot id")
+	ErrInvalidLatitude           = errors.New("invalid latitude")
+	ErrInvalidLongitude          = errors.New("invalid longitude")
+	ErrInvalidRadius             = errors.New("invalid radius")
+	ErrInvalidTags               = errors.New("invalid tags")
+	ErrInvalidTagsMatch          = errors.New("invalid tags match")
+	ErrInvalidLocalityPrefix     = errors.New("invalid locality prefix")
+	ErrInvalidSearchMode         = errors.New("invalid search mode")
+	ErrInvalidLanguage           = errors.New("invalid language")
+	ErrInvalidSpotIDs            = errors.New("invalid spot ids")
+	ErrInvalidScore              = errors.New("invalid score")
+	ErrInvalidSuggestionNote     = errors.New("invalid suggestion note")
+	ErrInvalidForecastDays       = errors.New("invalid forecast days")
+	ErrInvalidTideDate           = errors.New("invalid tide date")
+
+	// ErrEmptySpotChange is used when a change of a spot is suggested without any
+	// of its fields being changed.
+	ErrEmptySpotChange = errors.New("empty spot change")
+
+	// ErrConflictingSpotIDs is used when the same spot is requested to be both
+	// favorited and unfavorited at once.
+	ErrConflictingSpotIDs = errors.New("conflicting spot ids")
+
+	// ErrUnknownSpotID is used when a route is requested across a spot that
+	// doesn't exist.
+	ErrUnknownSpotID = errors.New("unknown spot id")
+
+	// ErrLocalizationUnavailable is used when spots are requested to be localized
+	// but Service has no localizer.
+	ErrLocalizationUnavailable = errors.New("localization unavailable")
+
+	// ErrPhotosUnavailable is used when photos of spots are requested but Service
+	// has no media storage.
+	ErrPhotosUnavailable = errors.New("photos unavailable")
+
+	// ErrForecastUnavailable is used when a forecast of a spot is requested but
+	// Service has no forecast source, or the source fails to fetch it.
+	ErrForecastUnavailable = errors.New("forecast unavailable")
+
+	// ErrTidesUnavailable is used when tides of a spot are requested but Service
+	// has no tide source, or the source fails to fetch them.
+	ErrTidesUnavailable = errors.New("tides unavailable")
 )
 
+// languageRegexp matches simple IETF language tags such as "ru", "kk" or "pt-BR".
+var languageRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
 type SpotStore interface {
 	surf.SpotReader
+	surf.RandomSpotReader
+	surf.SpotCentroidReader
+	surf.NearbySpotReader
+	surf.SpotCountryReader
+	surf.SpotDirectoryReader
+	surf.LocalityReader
+	surf.SpotMarkerReader
+	surf.SpotPhotoReader
+	surf.SpotRatingWriter
+	surf.SpotFavoriteReader
+	surf.SpotFavoriteWriter
+	surf.SpotSuggestionWriter
 }
 
 type Service struct {
-	spotStore SpotStore
+	spotStore        SpotStore
+	localizer        geo.LocalizedLocationSource
+	mediaStorage     media.Storage
+	forecastSource   forecast.Source
+	forecastCacheTTL time.Duration
+	tideSource       tide.Source
+	now              func() time.Time
+
+	forecastsMu sync.Mutex
+	forecasts   map[string]cachedForecast
+}
+
+// cachedForecast holds days forecasted for a spot along with when they expire.
+type cachedForecast struct {
+	days      []forecast.Day
+	expiresAt time.Time
+}
+
+func NewService(s SpotStore, opts ...Option) *Service {
+	svc := &Service{
+		spotStore:        s,
+		forecastCacheTTL: defaultForecastCacheTTL,
+		now:              time.Now,
+		forecasts:        make(map[string]cachedForecast),
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
 }
 
-func NewService(s SpotStore) *Service {
-	return &Service{
-		spotStore: s,
+// Option is an optional function for Service.
+type Option func(*Service)
+
+// WithLocalizer sets a source of locations which is used for localizing spots
+// into other languages. Localization is unavailable by default.
+func WithLocalizer(l geo.LocalizedLocationSource) Option {
+	return func(s *Service) {
+		s.localizer = l
 	}
 }
 
-func (s *Service) Spot(id string) (surf.Spot, error) {
+// WithMediaStorage sets a storage in which content of photos of spots is kept.
+// Photos are unavailable by default.
+func WithMediaStorage(m media.Storage) Option {
+	return func(s *Service) {
+		s.mediaStorage = m
+	}
+}
+
+// WithForecastSource sets a source of forecasts of conditions at spots. Forecasts
+// are unavailable by default.
+func WithForecastSource(src forecast.Source) Option {
+	return func(s *Service) {
+		s.forecastSource = src
+	}
+}
+
+// WithForecastCacheTTL sets how long forecasts are cached in memory per spot. Zero
+// disables caching.
+func WithForecastCacheTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.forecastCacheTTL = ttl
+	}
+}
+
+// WithTideSource sets a source of tides at spots. Tides are unavailable by
+// default.
+func WithTideSource(src tide.Source) Option {
+	return func(s *Service) {
+		s.tideSource = src
+	}
+}
+
+func (s *Service) Spot(ctx context.Context, id string) (surf.Spot, error) {
 	id = strings.TrimSpace(id)
 
 	if err := valerra.IfFalse(valerra.StringNotEmpty(id), ErrInvalidSpotID); err != nil {
 		return surf.Spot{}, err
 	}
 
-	return s.spotStore.Spot(id)
+	return s.spotStore.Spot(ctx, id)
+}
+
+// LocalizedSpot returns a spot by the given ID along with its locality in the
+// given language, which is looked up by the spot's coordinates. The localized
+// locality is left empty when the location is not found.
+func (s *Service) LocalizedSpot(ctx context.Context, p LocalizedSpotParams) (LocalizedSpot, error) {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	p.Language = strings.TrimSpace(p.Language)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return languageRegexp.MatchString(p.Language) }, ErrInvalidLanguage)
+	if err := v.Validate(); err != nil {
+		return LocalizedSpot{}, err
+	}
+
+	if s.localizer == nil {
+		return LocalizedSpot{}, ErrLocalizationUnavailable
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return LocalizedSpot{}, err
+	}
+
+	l, err := s.localizer.LocalizedLocation(spot.Location.Coordinates, p.Language)
+	if err != nil && !errors.Is(err, geo.ErrLocationNotFound) {
+		return LocalizedSpot{}, err
+	}
+
+	return LocalizedSpot{
+		Spot:              spot,
+		LocalizedLocality: l.Locality,
+	}, nil
+}
+
+type LocalizedSpotParams struct {
+	SpotID   string
+	Language string
+}
+
+// LocalizedSpot holds a spot along with its locality in another language.
+type LocalizedSpot struct {
+	Spot              surf.Spot
+	LocalizedLocality string
+}
+
+// SpotPhoto is a photo of a spot along with a URL at which its content is served.
+type SpotPhoto struct {
+	surf.SpotPhoto
+	URL string
+}
+
+// SpotPhotos returns photos of a spot by the given ID from the oldest one.
+func (s *Service) SpotPhotos(ctx context.Context, spotID string) ([]SpotPhoto, error) {
+	spotID = strings.TrimSpace(spotID)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(spotID), ErrInvalidSpotID); err != nil {
+		return nil, err
+	}
+
+	if s.mediaStorage == nil {
+		return nil, ErrPhotosUnavailable
+	}
+
+	photos, err := s.spotStore.SpotPhotos(ctx, spotID)
+	if err != nil {
+		return nil, err
+	}
+
+	withURLs := make([]SpotPhoto, len(photos))
+	for i, p := range photos {
+		withURLs[i] = SpotPhoto{
+			SpotPhoto: p,
+			URL:       s.mediaStorage.URL(p.Key()),
+		}
+	}
+
+	return withURLs, nil
+}
+
+// SpotForecast returns conditions forecasted at a spot by the given ID for the
+// given number of days starting from today. Forecasts are fetched for the maximum
+// number of days at once and cached per spot, so that requests for fewer days are
+// served from the cache as well.
+func (s *Service) SpotForecast(ctx context.Context, p SpotForecastParams) ([]forecast.Day, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	if s.forecastSource == nil {
+		return nil, ErrForecastUnavailable
+	}
+
+	days, ok := s.cachedForecast(p.SpotID)
+	if !ok {
+		spot, err := s.spotStore.Spot(ctx, p.SpotID)
+		if err != nil {
+			return nil, err
+		}
+
+		days, err = s.forecastSource.Forecast(ctx, spot.Location.Coordinates, maxForecastDays)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrForecastUnavailable, err)
+		}
+
+		s.cacheForecast(p.SpotID, days)
+	}
+
+	if len(days) > p.Days {
+		days = days[:p.Days]
+	}
+
+	return append([]forecast.Day(nil), days...), nil
+}
+
+type SpotForecastParams struct {
+	SpotID string
+	Days   int
+}
+
+func (p SpotForecastParams) sanitize() SpotForecastParams {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	if p.Days == 0 {
+		p.Days = defaultForecastDays
+	}
+	return p
+}
+
+func (p SpotForecastParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return p.Days >= minForecastDays && p.Days <= maxForecastDays }, ErrInvalidForecastDays)
+
+	return v.Validate()
+}
+
+func (s *Service) cachedForecast(spotID string) ([]forecast.Day, bool) {
+	s.forecastsMu.Lock()
+	defer s.forecastsMu.Unlock()
+
+	f, ok := s.forecasts[spotID]
+	if !ok || !s.now().Before(f.expiresAt) {
+		return nil, false
+	}
+	return f.days, true
+}
+
+// cacheForecast caches the given days of a spot and evicts expired forecasts of
+// other spots along the way, so that the cache doesn't grow with spots that are
+// no longer requested.
+func (s *Service) cacheForecast(spotID string, days []forecast.Day) {
+	if s.forecastCacheTTL <= 0 {
+		return
+	}
+
+	s.forecastsMu.Lock()
+	defer s.forecastsMu.Unlock()
+
+	now := s.now()
+	for id, f := range s.forecasts {
+		if !now.Before(f.expiresAt) {
+			delete(s.forecasts, id)
+		}
+	}
+
+	s.forecasts[spotID] = cachedForecast{
+		days:      days,
+		expiresAt: now.Add(s.forecastCacheTTL),
+	}
+}
+
+// SpotTides returns high and low tides at a spot by the given ID on the given
+// date in UTC, which defaults to today and may be at most a week away from it.
+func (s *Service) SpotTides(ctx context.Context, p SpotTidesParams) ([]tide.Extreme, error) {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+
+	p = p.sanitize(today)
+
+	if err := p.validate(today); err != nil {
+		return nil, err
+	}
+
+	if s.tideSource == nil {
+		return nil, ErrTidesUnavailable
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, _ := time.Parse(TideDateLayout, p.Date)
+
+	extremes, err := s.tideSource.Extremes(ctx, spot.Location.Coordinates, date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTidesUnavailable, err)
+	}
+
+	return extremes, nil
+}
+
+type SpotTidesParams struct {
+	SpotID string
+
+	// Date is formatted using TideDateLayout.
+	Date string
+}
+
+func (p SpotTidesParams) sanitize(today time.Time) SpotTidesParams {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	p.Date = strings.TrimSpace(p.Date)
+	if p.Date == "" {
+		p.Date = today.Format(TideDateLayout)
+	}
+	return p
+}
+
+func (p SpotTidesParams) validate(today time.Time) error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool {
+		date, err := time.Parse(TideDateLayout, p.Date)
+		if err != nil {
+			return false
+		}
+		days := date.Sub(today).Hours() / 24
+		return days >= -maxTideDateDays && days <= maxTideDateDays
+	}, ErrInvalidTideDate)
+
+	return v.Validate()
+}
+
+// RateSpot records a rating of a spot on behalf of a client with the given IP
+// address and returns updated ratings of the spot. Every client counts once a day
+// per spot, so rating the same spot again on the same day replaces the previous
+// score.
+func (s *Service) RateSpot(ctx context.Context, p RateSpotParams) (surf.SpotRatings, error) {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+
+	if err := p.validate(); err != nil {
+		return surf.SpotRatings{}, err
+	}
+
+	if err := s.spotStore.RateSpot(ctx, surf.SpotRatingEntry{
+		SpotID:   p.SpotID,
+		Score:    p.Score,
+		RaterKey: raterKey(p.ClientIP),
+		RatedOn:  s.now(),
+	}); err != nil {
+		return surf.SpotRatings{}, err
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return surf.SpotRatings{}, err
+	}
+
+	return spot.Ratings, nil
+}
+
+type RateSpotParams struct {
+	SpotID   string
+	Score    int
+	ClientIP string
+}
+
+func (p RateSpotParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return p.Score >= minScore && p.Score <= maxScore }, ErrInvalidScore)
+
+	return v.Validate()
+}
+
+// raterKey identifies a rater by a hash of the given IP address, so that IP
+// addresses of clients are not kept along with their ratings.
+func raterKey(ip string) string {
+	sum := sha256.Sum256([]byte(ip))
+	return hex.EncodeToString(sum[:])
+}
+
+// FavoriteSpot adds a spot by the given ID to favorites of the authenticated user.
+// Favoriting a spot that is already favorited has no effect.
+func (s *Service) FavoriteSpot(ctx context.Context, spotID string) error {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	spotID = strings.TrimSpace(spotID)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(spotID), ErrInvalidSpotID); err != nil {
+		return err
+	}
+
+	return s.spotStore.FavoriteSpot(ctx, userID, spotID)
+}
+
+// UnfavoriteSpot removes a spot by the given ID from favorites of the authenticated
+// user. Unfavoriting a spot that is not favorited has no effect.
+func (s *Service) UnfavoriteSpot(ctx context.Context, spotID string) error {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	spotID = strings.TrimSpace(spotID)
+
+	if err := valerra.IfFalse(valerra.StringNotEmpty(spotID), ErrInvalidSpotID); err != nil {
+		return err
+	}
+
+	return s.spotStore.UnfavoriteSpot(ctx, userID, spotID)
+}
+
+// FavoriteSpots returns spots that the authenticated user has favorited, from the
+// most recently favorited one.
+func (s *Service) FavoriteSpots(ctx context.Context, p FavoriteSpotsParams) ([]surf.Spot, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := p.Page()
+
+	return s.spotStore.FavoriteSpots(ctx, surf.FavoriteSpotsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+type FavoriteSpotsParams struct {
+	Limit  int
+	Offset int
+}
+
+// Page returns the limit and the offset that favorite spots are listed with once
+// defaults and boundaries are applied to the given ones.
+func (p FavoriteSpotsParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
+}
+
+// FavoritedSpotIDs reports which of spots by the given IDs the authenticated user
+// has favorited. Only IDs of favorited spots are present in the returned map.
+func (s *Service) FavoritedSpotIDs(ctx context.Context, spotIDs []string) (map[string]bool, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.FavoritedSpotIDs(ctx, userID, spotIDs)
+}
+
+// ApplyFavoriteBatch adds and removes spots by the given IDs to and from favorites
+// of the authenticated user at once. Favoriting a spot that is already favorited,
+// or unfavoriting one that is not, has no effect. IDs of spots to favorite that
+// are not found don't fail the batch and are reported in the result instead.
+func (s *Service) ApplyFavoriteBatch(ctx context.Context, p FavoriteBatchParams) (FavoriteBatchResult, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return FavoriteBatchResult{}, err
+	}
+
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return FavoriteBatchResult{}, err
+	}
+
+	favorited, err := s.spotStore.ApplyFavoriteBatch(ctx, surf.FavoriteBatch{
+		UserID:     userID,
+		Favorite:   p.Favorite,
+		Unfavorite: p.Unfavorite,
+	})
+	if err != nil {
+		return FavoriteBatchResult{}, err
+	}
+
+	found := make(map[string]struct{}, len(favorited))
+	for _, id := range favorited {
+		found[id] = struct{}{}
+	}
+
+	r := FavoriteBatchResult{
+		FavoritedIDs:   make([]string, 0, len(favorited)),
+		UnfavoritedIDs: p.Unfavorite,
+		NotFoundIDs:    make([]string, 0),
+	}
+	for _, id := range p.Favorite {
+		if _, ok := found[id]; ok {
+			r.FavoritedIDs = append(r.FavoritedIDs, id)
+		} else {
+			r.NotFoundIDs = append(r.NotFoundIDs, id)
+		}
+	}
+
+	return r, nil
+}
+
+type FavoriteBatchParams struct {
+	Favorite   []string
+	Unfavorite []string
+}
+
+func (p FavoriteBatchParams) sanitize() FavoriteBatchParams {
+	p.Favorite = sanitizeSpotIDs(p.Favorite)
+	p.Unfavorite = sanitizeSpotIDs(p.Unfavorite)
+	return p
+}
+
+func (p FavoriteBatchParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(func() bool {
+		n := len(p.Favorite) + len(p.Unfavorite)
+		return n > 0 && n <= maxFavoriteBatchSpotIDs
+	}, ErrInvalidSpotIDs)
+	v.IfFalse(func() bool {
+		favorite := make(map[string]struct{}, len(p.Favorite))
+		for _, id := range p.Favorite {
+			favorite[id] = struct{}{}
+		}
+		for _, id := range p.Unfavorite {
+			if _, ok := favorite[id]; ok {
+				return false
+			}
+		}
+		return true
+	}, ErrConflictingSpotIDs)
+	v.IfFalse(func() bool {
+		for _, ids := range [][]string{p.Favorite, p.Unfavorite} {
+			for _, id := range ids {
+				if id == "" {
+					return false
+				}
+			}
+		}
+		return true
+	}, ErrInvalidSpotID)
+
+	return v.Validate()
+}
+
+// FavoriteBatchResult reports the outcome of applying a batch of favorites. IDs
+// keep the order they were requested in.
+type FavoriteBatchResult struct {
+	FavoritedIDs   []string
+	UnfavoritedIDs []string
+	NotFoundIDs    []string
+}
+
+// sanitizeSpotIDs trims the given IDs and drops duplicates while preserving their
+// order.
+func sanitizeSpotIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	sanitized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		sanitized = append(sanitized, id)
+	}
+	return sanitized
+}
+
+// userIDFromContext returns an ID of the user whose JWT claims the given context
+// holds. jwt.ErrClaimsNotFound is returned when the context holds no claims.
+func userIDFromContext(ctx context.Context) (string, error) {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return "", jwt.ErrClaimsNotFound
+	}
+	return claims.Subject, nil
+}
+
+// SuggestSpotChange records a change of a spot proposed by a surfer, which is
+// kept pending until an admin reviews it. The proposed values are validated by
+// the same rules that admins update spots with, so errors of the management
+// service are returned for invalid ones.
+func (s *Service) SuggestSpotChange(ctx context.Context, p SuggestSpotChangeParams) (surf.SpotSuggestion, error) {
+	p.Note = strings.TrimSpace(p.Note)
+
+	change, err := management.SpotChange(p.Change)
+	if err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	if err := valerra.IfFalse(
+		valerra.StringLessOrEqual(p.Note, maxSuggestionNoteChars),
+		ErrInvalidSuggestionNote,
+	); err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	if change.Name == nil && change.Latitude == nil && change.Longitude == nil &&
+		change.Locality == nil && change.CountryCode == nil && change.Region == nil {
+		return surf.SpotSuggestion{}, ErrEmptySpotChange
+	}
+
+	return s.spotStore.CreateSpotSuggestion(ctx, surf.SpotSuggestionEntry{
+		Change: change,
+		Note:   p.Note,
+	})
+}
+
+// SuggestSpotChangeParams holds a proposed change of a spot along with an optional
+// note explaining it.
+type SuggestSpotChangeParams struct {
+	Change management.UpdateSpotParams
+	Note   string
+}
+
+// SpotWithNearby returns a spot by the given ID along with up to 10 spots that
+// are the closest to it within the given radius, excluding the spot itself.
+func (s *Service) SpotWithNearby(ctx context.Context, p SpotWithNearbyParams) (SpotWithNearby, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	spot, err := s.spotStore.Spot(ctx, p.SpotID)
+	if err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	nearby, err := surf.NearbySpotsOf(ctx, s.spotStore, spot, p.RadiusKm, maxSpotNearbyCount)
+	if err != nil {
+		return SpotWithNearby{}, err
+	}
+
+	return SpotWithNearby{
+		Spot:   spot,
+		Nearby: nearby,
+	}, nil
+}
+
+type SpotWithNearbyParams struct {
+	SpotID   string
+	RadiusKm float64
+}
+
+func (p SpotWithNearbyParams) sanitize() SpotWithNearbyParams {
+	p.SpotID = strings.TrimSpace(p.SpotID)
+	if p.RadiusKm == 0 {
+		p.RadiusKm = defaultNearbyRadiusKm
+	}
+	if p.RadiusKm > maxNearbyRadiusKm {
+		p.RadiusKm = maxNearbyRadiusKm
+	}
+	return p
+}
+
+func (p SpotWithNearbyParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(p.SpotID), ErrInvalidSpotID)
+	v.IfFalse(func() bool { return p.RadiusKm > 0 }, ErrInvalidRadius)
+
+	return v.Validate()
 }
 
-func (s *Service) Spots(p SpotsParams) ([]surf.Spot, error) {
+// SpotWithNearby holds a spot along with spots located near it.
+type SpotWithNearby struct {
+	Spot   surf.Spot
+	Nearby []surf.NearbySpot
+}
+
+func (s *Service) Spots(ctx context.Context, p SpotsParams) ([]surf.Spot, error) {
 	p = p.sanitize()
 
 	if err := p.validate(); err != nil {
 		return nil, err
 	}
 
-	return s.spotStore.Spots(surf.SpotsParams{
+	sp := surf.SpotsParams{
 		Limit:       p.Limit,
 		Offset:      p.Offset,
 		CountryCode: p.CountryCode,
 		Bounds:      p.Bounds,
 		SearchQuery: surf.SpotSearchQuery{
-			Query: p.SearchQuery,
+			Query:    p.SearchQuery,
+			FullText: p.SearchMode == SearchModeFullText,
 		},
-	})
+		Tags: p.Tags,
+	}
+	if p.TagsMatch == TagsMatchAny {
+		sp.TagsMatch = surf.SpotTagsMatchAny
+	}
+
+	return s.spotStore.Spots(ctx, sp)
 }
 
 type SpotsParams struct {
@@ -79,29 +867,422 @@ type SpotsParams struct {
 	CountryCode string
 	SearchQuery string
 	Bounds      *geo.Bounds
+
+	// SearchMode can be optionally set to either SearchModeSubstring, which is
+	// the default, or SearchModeFullText.
+	SearchMode string
+
+	// Tags can be optionally set to filter spots by tags. TagsMatch can be
+	// optionally set to either TagsMatchAll, which is the default, or TagsMatchAny.
+	Tags      []string
+	TagsMatch string
+}
+
+// Page returns the limit and the offset that spots are listed with once defaults
+// and boundaries are applied to the given ones.
+func (p SpotsParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
 }
 
 func (p SpotsParams) sanitize() SpotsParams {
-	p.Limit = paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit)
-	p.Offset = paging.Offset(p.Offset, minOffset)
+	p.Limit, p.Offset = p.Page()
 	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
 	p.SearchQuery = strings.TrimSpace(p.SearchQuery)
+	p.SearchMode = strings.ToLower(strings.TrimSpace(p.SearchMode))
+	p.Tags = sanitizeTags(p.Tags)
+	p.TagsMatch = strings.ToLower(strings.TrimSpace(p.TagsMatch))
 	return p
 }
 
+// sanitizeTags trims and lowercases the given tags, and drops empty and duplicate
+// ones while preserving their order.
+func sanitizeTags(tags []string) []string {
+	var (
+		sanitized []string
+		seen      = make(map[string]struct{}, len(tags))
+	)
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		sanitized = append(sanitized, t)
+	}
+	return sanitized
+}
+
+func areValidTags(tags []string) valerra.Condition {
+	return func() bool {
+		if len(tags) > maxTags {
+			return false
+		}
+		for _, t := range tags {
+			if !valerra.StringLessOrEqual(t, maxTagChars)() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 func (p SpotsParams) validate() error {
 	v := valerra.New()
 
 	v.IfFalse(valerra.StringLessOrEqual(p.SearchQuery, maxSearchQueryChars), ErrInvalidSearchQuery)
+	v.IfFalse(func() bool {
+		return p.SearchMode == "" || p.SearchMode == SearchModeSubstring || p.SearchMode == SearchModeFullText
+	}, ErrInvalidSearchMode)
 	if p.CountryCode != "" {
 		v.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
 	}
 	if p.Bounds != nil {
-		v.IfFalse(valerrautil.IsLatitude(p.Bounds.NorthEast.Latitude), ErrInvalidNorthEastLatitude)
-		v.IfFalse(valerrautil.IsLongitude(p.Bounds.NorthEast.Longitude), ErrInvalidNorthEastLongitude)
-		v.IfFalse(valerrautil.IsLatitude(p.Bounds.SouthWest.Latitude), ErrInvalidSouthWestLatitude)
-		v.IfFalse(valerrautil.IsLongitude(p.Bounds.SouthWest.Longitude), ErrInvalidSouthWestLongitude)
+		validateBounds(v, *p.Bounds)
 	}
+	v.IfFalse(areValidTags(p.Tags), ErrInvalidTags)
+	v.IfFalse(func() bool {
+		return p.TagsMatch == "" || p.TagsMatch == TagsMatchAll || p.TagsMatch == TagsMatchAny
+	}, ErrInvalidTagsMatch)
 
 	return v.Validate()
 }
+
+func validateBounds(v *valerra.Validator, b geo.Bounds) {
+	v.IfFalse(valerrautil.IsLatitude(b.NorthEast.Latitude), ErrInvalidNorthEastLatitude)
+	v.IfFalse(valerrautil.IsLongitude(b.NorthEast.Longitude), ErrInvalidNorthEastLongitude)
+	v.IfFalse(valerrautil.IsLatitude(b.SouthWest.Latitude), ErrInvalidSouthWestLatitude)
+	v.IfFalse(valerrautil.IsLongitude(b.SouthWest.Longitude), ErrInvalidSouthWestLongitude)
+	v.IfFalse(valerrautil.AreConsistentBounds(b), ErrInvalidBounds)
+}
+
+func (s *Service) SpotMarkers(ctx context.Context, p SpotMarkersParams) ([]surf.SpotMarker, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.SpotMarkers(ctx, surf.SpotMarkersParams{
+		Limit:  markersLimit,
+		Bounds: p.Bounds,
+	})
+}
+
+type SpotMarkersParams struct {
+	Bounds *geo.Bounds
+}
+
+func (p SpotMarkersParams) validate() error {
+	if p.Bounds == nil {
+		return nil
+	}
+
+	v := valerra.New()
+	validateBounds(v, *p.Bounds)
+	return v.Validate()
+}
+
+func (s *Service) RandomSpots(ctx context.Context, p RandomSpotsParams) ([]surf.Spot, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.RandomSpots(ctx, surf.RandomSpotsParams{
+		Limit:       p.Count,
+		CountryCode: p.CountryCode,
+	})
+}
+
+type RandomSpotsParams struct {
+	Count       int
+	CountryCode string
+}
+
+func (p RandomSpotsParams) sanitize() RandomSpotsParams {
+	p.Count = paging.Limit(p.Count, minRandomCount, maxRandomCount, defaultRandomCount)
+	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
+	return p
+}
+
+func (p RandomSpotsParams) validate() error {
+	if p.CountryCode == "" {
+		return nil
+	}
+	return valerra.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
+}
+
+func (s *Service) SpotsCentroid(ctx context.Context, p SpotsCentroidParams) (*geo.Coordinates, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.SpotsCentroid(ctx, surf.SpotsCentroidParams{
+		CountryCode: p.CountryCode,
+		Bounds:      p.Bounds,
+		SearchQuery: surf.SpotSearchQuery{
+			Query: p.SearchQuery,
+		},
+	})
+}
+
+type SpotsCentroidParams struct {
+	CountryCode string
+	SearchQuery string
+	Bounds      *geo.Bounds
+}
+
+func (p SpotsCentroidParams) sanitize() SpotsCentroidParams {
+	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
+	p.SearchQuery = strings.TrimSpace(p.SearchQuery)
+	return p
+}
+
+func (p SpotsCentroidParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringLessOrEqual(p.SearchQuery, maxSearchQueryChars), ErrInvalidSearchQuery)
+	if p.CountryCode != "" {
+		v.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
+	}
+	if p.Bounds != nil {
+		validateBounds(v, *p.Bounds)
+	}
+
+	return v.Validate()
+}
+
+func (s *Service) NearbySpots(ctx context.Context, p NearbySpotsParams) ([]surf.NearbySpot, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.NearbySpots(ctx, surf.NearbySpotsParams{
+		Coordinates: p.Coordinates,
+		RadiusKm:    p.RadiusKm,
+		Limit:       p.Limit,
+	})
+}
+
+type NearbySpotsParams struct {
+	Coordinates geo.Coordinates
+	RadiusKm    float64
+	Limit       int
+}
+
+func (p NearbySpotsParams) sanitize() NearbySpotsParams {
+	p.Limit = paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit)
+	if p.RadiusKm == 0 {
+		p.RadiusKm = defaultNearbyRadiusKm
+	}
+	if p.RadiusKm > maxNearbyRadiusKm {
+		p.RadiusKm = maxNearbyRadiusKm
+	}
+	return p
+}
+
+func (p NearbySpotsParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(valerrautil.IsLatitude(p.Coordinates.Latitude), ErrInvalidLatitude)
+	v.IfFalse(valerrautil.IsLongitude(p.Coordinates.Longitude), ErrInvalidLongitude)
+	v.IfFalse(func() bool { return p.RadiusKm > 0 }, ErrInvalidRadius)
+
+	return v.Validate()
+}
+
+// SpotRoute returns spots by the given IDs ordered as a route that starts at the
+// given point and always continues to the nearest spot that hasn't been visited
+// yet. The route starts at the first of the given spots if no point is given.
+//
+// Nearest-neighbor ordering only approximates the shortest route, which is good
+// enough for suggesting a travel order across a handful of spots.
+func (s *Service) SpotRoute(ctx context.Context, p SpotRouteParams) ([]RouteStop, error) {
+	p = p.sanitize()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	spots := make([]surf.Spot, len(p.SpotIDs))
+	for i, id := range p.SpotIDs {
+		spot, err := s.spotStore.Spot(ctx, id)
+		if err != nil {
+			if errors.Is(err, surf.ErrSpotNotFound) {
+				return nil, valerra.NewErrors(ErrUnknownSpotID)
+			}
+			return nil, err
+		}
+		spots[i] = spot
+	}
+
+	start := spots[0].Location.Coordinates
+	if p.Start != nil {
+		start = *p.Start
+	}
+
+	return planRoute(start, spots), nil
+}
+
+type SpotRouteParams struct {
+	SpotIDs []string
+
+	// Start is an optional point which the route starts at.
+	Start *geo.Coordinates
+}
+
+func (p SpotRouteParams) sanitize() SpotRouteParams {
+	seen := make(map[string]struct{}, len(p.SpotIDs))
+	ids := make([]string, 0, len(p.SpotIDs))
+	for _, id := range p.SpotIDs {
+		id = strings.TrimSpace(id)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	p.SpotIDs = ids
+	return p
+}
+
+func (p SpotRouteParams) validate() error {
+	v := valerra.New()
+
+	v.IfFalse(func() bool { return len(p.SpotIDs) > 0 && len(p.SpotIDs) <= maxRouteSpots }, ErrInvalidSpotIDs)
+	v.IfFalse(func() bool {
+		for _, id := range p.SpotIDs {
+			if id == "" {
+				return false
+			}
+		}
+		return true
+	}, ErrInvalidSpotID)
+	if p.Start != nil {
+		v.IfFalse(valerrautil.IsLatitude(p.Start.Latitude), ErrInvalidLatitude)
+		v.IfFalse(valerrautil.IsLongitude(p.Start.Longitude), ErrInvalidLongitude)
+	}
+
+	return v.Validate()
+}
+
+// RouteStop is a spot on a route along with its distance from the previous stop,
+// or from the start of the route in case of the first stop.
+type RouteStop struct {
+	Spot                 surf.Spot
+	DistanceKm           float64
+	CumulativeDistanceKm float64
+}
+
+// planRoute orders the given spots by repeatedly picking the nearest one to the
+// current point, starting at the given one. Ties are broken in favor of the spot
+// that comes first in the given order.
+func planRoute(start geo.Coordinates, spots []surf.Spot) []RouteStop {
+	remaining := make([]surf.Spot, len(spots))
+	copy(remaining, spots)
+
+	var (
+		stops   = make([]RouteStop, 0, len(spots))
+		current = start
+		total   float64
+	)
+	for len(remaining) > 0 {
+		nearest := 0
+		nearestKm := geo.DistanceKm(current, remaining[0].Location.Coordinates)
+		for i := 1; i < len(remaining); i++ {
+			if d := geo.DistanceKm(current, remaining[i].Location.Coordinates); d < nearestKm {
+				nearest, nearestKm = i, d
+			}
+		}
+
+		spot := remaining[nearest]
+		total += nearestKm
+		stops = append(stops, RouteStop{
+			Spot:                 spot,
+			DistanceKm:           nearestKm,
+			CumulativeDistanceKm: total,
+		})
+
+		current = spot.Location.Coordinates
+		remaining = append(remaining[:nearest], remaining[nearest+1:]...)
+	}
+
+	return stops
+}
+
+func (s *Service) Countries(ctx context.Context, p CountriesParams) ([]surf.CountrySpotCount, error) {
+	p.SearchQuery = strings.TrimSpace(p.SearchQuery)
+
+	if err := valerra.IfFalse(
+		valerra.StringLessOrEqual(p.SearchQuery, maxSearchQueryChars),
+		ErrInvalidSearchQuery,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.CountriesWithSpotCounts(ctx, surf.CountriesParams{
+		SearchQuery: surf.SpotSearchQuery{
+			Query: p.SearchQuery,
+		},
+	})
+}
+
+type CountriesParams struct {
+	SearchQuery string
+}
+
+func (s *Service) SpotDirectory(ctx context.Context, p SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	limit, offset := p.Page()
+
+	return s.spotStore.SpotDirectory(ctx, surf.SpotDirectoryParams{
+		Limit:                limit,
+		Offset:               offset,
+		LocalitiesPerCountry: paging.Limit(p.LocalitiesLimit, minLimit, maxDirectoryLocalities, defaultDirectoryLocalities),
+		SpotsPerLocality:     paging.Limit(p.SpotsLimit, minLimit, maxDirectorySpots, defaultDirectorySpots),
+	})
+}
+
+// SpotDirectoryParams holds parameters for listing spots grouped by countries and
+// localities. Limit and Offset paginate countries, while LocalitiesLimit and
+// SpotsLimit cap localities of each country and spots of each locality.
+type SpotDirectoryParams struct {
+	Limit           int
+	Offset          int
+	LocalitiesLimit int
+	SpotsLimit      int
+}
+
+// Page returns the limit and the offset that countries are listed with once
+// defaults and boundaries are applied to the given ones.
+func (p SpotDirectoryParams) Page() (limit, offset int) {
+	return paging.Limit(p.Limit, minLimit, maxLimit, defaultLimit), paging.Offset(p.Offset, minOffset)
+}
+
+func (s *Service) Localities(ctx context.Context, p LocalitiesParams) ([]string, error) {
+	p.Prefix = strings.TrimSpace(p.Prefix)
+	p.CountryCode = strings.ToLower(strings.TrimSpace(p.CountryCode))
+	p.Limit = paging.Limit(p.Limit, minLimit, maxLocalitiesLimit, defaultLimit)
+
+	v := valerra.New()
+	v.IfFalse(valerra.StringGreaterOrEqual(p.Prefix, minLocalityPrefixChars), ErrInvalidLocalityPrefix)
+	if p.CountryCode != "" {
+		v.IfFalse(valerrautil.IsCountry(p.CountryCode), ErrInvalidCountryCode)
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.spotStore.Localities(ctx, surf.LocalitiesParams(p))
+}
+
+type LocalitiesParams struct {
+	Prefix      string
+	CountryCode string
+	Limit       int
+}
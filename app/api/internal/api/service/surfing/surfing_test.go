@@ -1,14 +1,23 @@
 package surfing
 
 import (
+	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
+	jwtgo "github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/api/service/management"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/jwt"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/media"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
+	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
 	"github.com/ztimes2/tolqin/app/api/pkg/strutil"
 	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
 )
@@ -21,16 +30,91 @@ func newMockSpotStore() *mockSpotStore {
 	return &mockSpotStore{}
 }
 
-func (m *mockSpotStore) Spot(id string) (surf.Spot, error) {
-	args := m.Called(id)
+func (m *mockSpotStore) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(surf.Spot), args.Error(1)
 }
 
-func (m *mockSpotStore) Spots(p surf.SpotsParams) ([]surf.Spot, error) {
-	args := m.Called(p)
+func (m *mockSpotStore) Spots(ctx context.Context, p surf.SpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
 	return args.Get(0).([]surf.Spot), args.Error(1)
 }
 
+func (m *mockSpotStore) RandomSpots(ctx context.Context, p surf.RandomSpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.Spot), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotsCentroid(ctx context.Context, p surf.SpotsCentroidParams) (*geo.Coordinates, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(*geo.Coordinates), args.Error(1)
+}
+
+func (m *mockSpotStore) NearbySpots(ctx context.Context, p surf.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.NearbySpot), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotPhotos(ctx context.Context, spotID string) ([]surf.SpotPhoto, error) {
+	args := m.Called(ctx, spotID)
+	return args.Get(0).([]surf.SpotPhoto), args.Error(1)
+}
+
+func (m *mockSpotStore) RateSpot(ctx context.Context, e surf.SpotRatingEntry) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) FavoriteSpots(ctx context.Context, p surf.FavoriteSpotsParams) ([]surf.Spot, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.Spot), args.Error(1)
+}
+
+func (m *mockSpotStore) FavoritedSpotIDs(ctx context.Context, userID string, spotIDs []string) (map[string]bool, error) {
+	args := m.Called(ctx, userID, spotIDs)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *mockSpotStore) FavoriteSpot(ctx context.Context, userID, spotID string) error {
+	args := m.Called(ctx, userID, spotID)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) UnfavoriteSpot(ctx context.Context, userID, spotID string) error {
+	args := m.Called(ctx, userID, spotID)
+	return args.Error(0)
+}
+
+func (m *mockSpotStore) ApplyFavoriteBatch(ctx context.Context, b surf.FavoriteBatch) ([]string, error) {
+	args := m.Called(ctx, b)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockSpotStore) CreateSpotSuggestion(ctx context.Context, e surf.SpotSuggestionEntry) (surf.SpotSuggestion, error) {
+	args := m.Called(ctx, e)
+	return args.Get(0).(surf.SpotSuggestion), args.Error(1)
+}
+
+func (m *mockSpotStore) Localities(ctx context.Context, p surf.LocalitiesParams) ([]string, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockSpotStore) CountriesWithSpotCounts(ctx context.Context, p surf.CountriesParams) ([]surf.CountrySpotCount, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.CountrySpotCount), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotDirectory(ctx context.Context, p surf.SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.DirectoryCountry), args.Error(1)
+}
+
+func (m *mockSpotStore) SpotMarkers(ctx context.Context, p surf.SpotMarkersParams) ([]surf.SpotMarker, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).([]surf.SpotMarker), args.Error(1)
+}
+
 func TestService_Spot(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -51,7 +135,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(surf.Spot{}, errors.New("something went wrong"))
 				return m
 			}(),
@@ -64,7 +148,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(
 						surf.Spot{
 							Location: geo.Location{
@@ -104,7 +188,7 @@ func TestService_Spot(t *testing.T) {
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spot", "1").
+					On("Spot", mock.Anything, "1").
 					Return(
 						surf.Spot{
 							Location: geo.Location{
@@ -145,300 +229,2445 @@ func TestService_Spot(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			s := NewService(test.spotStore)
 
-			spot, err := s.Spot(test.id)
+			spot, err := s.Spot(context.Background(), test.id)
 			test.expectedErrFn(t, err)
 			assert.Equal(t, test.expectedSpot, spot)
 		})
 	}
 }
 
-func TestService_Spots(t *testing.T) {
+type mockLocalizer struct {
+	mock.Mock
+}
+
+func newMockLocalizer() *mockLocalizer {
+	return &mockLocalizer{}
+}
+
+func (m *mockLocalizer) LocalizedLocation(c geo.Coordinates, language string) (geo.Location, error) {
+	args := m.Called(c, language)
+	return args.Get(0).(geo.Location), args.Error(1)
+}
+
+func TestService_SpotPhotos(t *testing.T) {
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		spotStore      SpotStore
-		locationSource geo.LocationSource
-		params         SpotsParams
-		expectedSpots  []surf.Spot
+		withMedia      bool
+		spotID         string
+		expectedPhotos []SpotPhoto
 		expectedErrFn  assert.ErrorAssertionFunc
 	}{
 		{
-			name:      "return error for invalid country code",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:       20,
-				Offset:      0,
-				CountryCode: "invalid",
+			name:           "return error for invalid spot id",
+			spotStore:      newMockSpotStore(),
+			withMedia:      true,
+			spotID:         " ",
+			expectedPhotos: nil,
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name:           "return error for missing media storage",
+			spotStore:      newMockSpotStore(),
+			withMedia:      false,
+			spotID:         "1",
+			expectedPhotos: nil,
+			expectedErrFn:  testutil.IsError(ErrPhotosUnavailable),
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surf.SpotPhoto(nil), surf.ErrSpotNotFound)
+				return m
+			}(),
+			withMedia:      true,
+			spotID:         "1",
+			expectedPhotos: nil,
+			expectedErrFn:  testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return photos with urls using sanitized spot id without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotPhotos", mock.Anything, "1").
+					Return([]surf.SpotPhoto{
+						{ID: "2", SpotID: "1", ContentType: "image/jpeg", Size: 10, CreatedAt: createdAt},
+						{ID: "3", SpotID: "1", ContentType: "image/webp", Size: 20, CreatedAt: createdAt},
+					}, nil)
+				return m
+			}(),
+			withMedia: true,
+			spotID:    " 1 ",
+			expectedPhotos: []SpotPhoto{
+				{
+					SpotPhoto: surf.SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/jpeg", Size: 10, CreatedAt: createdAt},
+					URL:       "/media/spots/1/2.jpg",
+				},
+				{
+					SpotPhoto: surf.SpotPhoto{ID: "3", SpotID: "1", ContentType: "image/webp", Size: 20, CreatedAt: createdAt},
+					URL:       "/media/spots/1/3.webp",
+				},
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidCountryCode),
+			expectedErrFn: assert.NoError,
 		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.withMedia {
+				opts = append(opts, WithMediaStorage(media.NewLocalStorage(t.TempDir(), "/media")))
+			}
+			s := NewService(test.spotStore, opts...)
+
+			photos, err := s.SpotPhotos(context.Background(), test.spotID)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPhotos, photos)
+		})
+	}
+}
+
+type mockForecastSource struct {
+	mock.Mock
+}
+
+func newMockForecastSource() *mockForecastSource {
+	return &mockForecastSource{}
+}
+
+func (m *mockForecastSource) Forecast(ctx context.Context, c geo.Coordinates, days int) ([]forecast.Day, error) {
+	args := m.Called(c, days)
+	return args.Get(0).([]forecast.Day), args.Error(1)
+}
+
+func forecastDays(n int) []forecast.Day {
+	days := make([]forecast.Day, n)
+	for i := range days {
+		days[i] = forecast.Day{
+			Date:          time.Date(2021, 1, 1+i, 0, 0, 0, 0, time.UTC),
+			WaveHeightMax: pconv.Float64(float64(i + 1)),
+		}
+	}
+	return days
+}
+
+func TestService_SpotForecast(t *testing.T) {
+	coord := geo.Coordinates{Latitude: 1.23, Longitude: 3.21}
+
+	tests := []struct {
+		name           string
+		spotStore      SpotStore
+		forecastSource forecast.Source
+		params         SpotForecastParams
+		expectedDays   []forecast.Day
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
 		{
-			name:      "return error for invalid query",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:       20,
-				Offset:      0,
-				CountryCode: "kz",
-				SearchQuery: strutil.RepeatRune('a', 101),
+			name:           "return error for invalid params",
+			spotStore:      newMockSpotStore(),
+			forecastSource: newMockForecastSource(),
+			params: SpotForecastParams{
+				SpotID: " ",
+				Days:   8,
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSearchQuery),
+			expectedDays:  nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidForecastDays),
 		},
 		{
-			name:      "return error for invalid north-east latitude",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:  20,
-				Offset: 0,
-				Bounds: &geo.Bounds{
-					NorthEast: geo.Coordinates{
-						Latitude:  91,
-						Longitude: 180,
-					},
-					SouthWest: geo.Coordinates{
-						Latitude:  -90,
-						Longitude: -180,
-					},
-				},
+			name:           "return error for negative days",
+			spotStore:      newMockSpotStore(),
+			forecastSource: newMockForecastSource(),
+			params: SpotForecastParams{
+				SpotID: "1",
+				Days:   -1,
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidNorthEastLatitude),
+			expectedDays:  nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidForecastDays),
 		},
 		{
-			name:      "return error for invalid north-east longitude",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:  20,
-				Offset: 0,
-				Bounds: &geo.Bounds{
-					NorthEast: geo.Coordinates{
-						Latitude:  90,
-						Longitude: 181,
-					},
-					SouthWest: geo.Coordinates{
-						Latitude:  -90,
-						Longitude: -180,
-					},
-				},
+			name:           "return error for missing forecast source",
+			spotStore:      newMockSpotStore(),
+			forecastSource: nil,
+			params: SpotForecastParams{
+				SpotID: "1",
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidNorthEastLongitude),
+			expectedDays:  nil,
+			expectedErrFn: testutil.IsError(ErrForecastUnavailable),
 		},
 		{
-			name:      "return error for invalid south-west latitude",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:  20,
-				Offset: 0,
-				Bounds: &geo.Bounds{
-					NorthEast: geo.Coordinates{
-						Latitude:  90,
-						Longitude: 180,
-					},
-					SouthWest: geo.Coordinates{
-						Latitude:  -91,
-						Longitude: -180,
-					},
-				},
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			forecastSource: newMockForecastSource(),
+			params: SpotForecastParams{
+				SpotID: "1",
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSouthWestLatitude),
+			expectedDays:  nil,
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
 		},
 		{
-			name:      "return error for invalid south-west longitude",
-			spotStore: newMockSpotStore(),
-			params: SpotsParams{
-				Limit:  20,
-				Offset: 0,
-				Bounds: &geo.Bounds{
-					NorthEast: geo.Coordinates{
-						Latitude:  90,
-						Longitude: 180,
-					},
-					SouthWest: geo.Coordinates{
-						Latitude:  -90,
-						Longitude: -181,
-					},
-				},
+			name: "return error during forecast source failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
+				return m
+			}(),
+			forecastSource: func() forecast.Source {
+				m := newMockForecastSource()
+				m.
+					On("Forecast", coord, 7).
+					Return([]forecast.Day(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: SpotForecastParams{
+				SpotID: "1",
 			},
-			expectedSpots: nil,
-			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSouthWestLongitude),
+			expectedDays:  nil,
+			expectedErrFn: testutil.IsError(ErrForecastUnavailable),
 		},
 		{
-			name: "return error during spot spore failure",
+			name: "return default number of days using sanitized spot id without error",
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spots", surf.SpotsParams{
-						Limit:  20,
-						Offset: 0,
-					}).
-					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
 				return m
 			}(),
-			params: SpotsParams{
-				Limit:  20,
-				Offset: 0,
+			forecastSource: func() forecast.Source {
+				m := newMockForecastSource()
+				m.
+					On("Forecast", coord, 7).
+					Return(forecastDays(7), nil)
+				return m
+			}(),
+			params: SpotForecastParams{
+				SpotID: " 1 ",
 			},
-			expectedSpots: nil,
-			expectedErrFn: assert.Error,
+			expectedDays:  forecastDays(3),
+			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots using sanitized params without error",
+			name: "return fewer days than requested when source lacks them without error",
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spots", surf.SpotsParams{
-						Limit:       10,
-						Offset:      0,
-						CountryCode: "kz",
-						SearchQuery: surf.SpotSearchQuery{
-							Query: "query",
-						},
-					}).
-					Return(
-						[]surf.Spot{
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 1",
-									CountryCode: "kz",
-								},
-								ID:        "1",
-								Name:      "Spot 1",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 2",
-									CountryCode: "kz",
-								},
-								ID:        "2",
-								Name:      "Spot 2",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-						},
-						nil,
-					)
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
 				return m
 			}(),
-			params: SpotsParams{
-				Limit:       0,
-				Offset:      -1,
-				CountryCode: " kz ",
-				SearchQuery: " query ",
+			forecastSource: func() forecast.Source {
+				m := newMockForecastSource()
+				m.
+					On("Forecast", coord, 7).
+					Return(forecastDays(5), nil)
+				return m
+			}(),
+			params: SpotForecastParams{
+				SpotID: "1",
+				Days:   7,
 			},
-			expectedSpots: []surf.Spot{
-				{
-					Location: geo.Location{
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+			expectedDays:  forecastDays(5),
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.forecastSource != nil {
+				opts = append(opts, WithForecastSource(test.forecastSource))
+			}
+			s := NewService(test.spotStore, opts...)
+
+			days, err := s.SpotForecast(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedDays, days)
+		})
+	}
+}
+
+func TestService_SpotForecast_Cache(t *testing.T) {
+	coord := geo.Coordinates{Latitude: 1.23, Longitude: 3.21}
+
+	store := newMockSpotStore()
+	store.
+		On("Spot", mock.Anything, "1").
+		Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil).
+		Twice()
+
+	src := newMockForecastSource()
+	src.
+		On("Forecast", coord, 7).
+		Return(forecastDays(7), nil).
+		Twice()
+
+	now := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := NewService(store, WithForecastSource(src), WithForecastCacheTTL(time.Minute))
+	s.now = func() time.Time { return now }
+
+	days, err := s.SpotForecast(context.Background(), SpotForecastParams{SpotID: "1", Days: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, forecastDays(2), days)
+
+	days, err = s.SpotForecast(context.Background(), SpotForecastParams{SpotID: "1", Days: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, forecastDays(5), days)
+
+	now = now.Add(time.Minute)
+
+	days, err = s.SpotForecast(context.Background(), SpotForecastParams{SpotID: "1", Days: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, forecastDays(1), days)
+
+	store.AssertExpectations(t)
+	src.AssertExpectations(t)
+}
+
+type mockTideSource struct {
+	mock.Mock
+}
+
+func newMockTideSource() *mockTideSource {
+	return &mockTideSource{}
+}
+
+func (m *mockTideSource) Extremes(ctx context.Context, c geo.Coordinates, start time.Time) ([]tide.Extreme, error) {
+	args := m.Called(c, start)
+	return args.Get(0).([]tide.Extreme), args.Error(1)
+}
+
+func TestService_SpotTides(t *testing.T) {
+	now := time.Date(2021, 1, 10, 15, 30, 0, 0, time.UTC)
+	coord := geo.Coordinates{Latitude: 1.23, Longitude: 3.21}
+
+	tests := []struct {
+		name             string
+		spotStore        SpotStore
+		tideSource       tide.Source
+		params           SpotTidesParams
+		expectedExtremes []tide.Extreme
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "return error for invalid params",
+			spotStore:  newMockSpotStore(),
+			tideSource: newMockTideSource(),
+			params: SpotTidesParams{
+				SpotID: " ",
+				Date:   "10.01.2021",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidTideDate),
+		},
+		{
+			name:       "return error for date more than a week before today",
+			spotStore:  newMockSpotStore(),
+			tideSource: newMockTideSource(),
+			params: SpotTidesParams{
+				SpotID: "1",
+				Date:   "2021-01-02",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidTideDate),
+		},
+		{
+			name:       "return error for date more than a week after today",
+			spotStore:  newMockSpotStore(),
+			tideSource: newMockTideSource(),
+			params: SpotTidesParams{
+				SpotID: "1",
+				Date:   "2021-01-18",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidTideDate),
+		},
+		{
+			name:       "return error for missing tide source",
+			spotStore:  newMockSpotStore(),
+			tideSource: nil,
+			params: SpotTidesParams{
+				SpotID: "1",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.IsError(ErrTidesUnavailable),
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			tideSource: newMockTideSource(),
+			params: SpotTidesParams{
+				SpotID: "1",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during tide source failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
+				return m
+			}(),
+			tideSource: func() tide.Source {
+				m := newMockTideSource()
+				m.
+					On("Extremes", coord, time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)).
+					Return([]tide.Extreme(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: SpotTidesParams{
+				SpotID: "1",
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    testutil.IsError(ErrTidesUnavailable),
+		},
+		{
+			name: "return extremes of today using sanitized params without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
+				return m
+			}(),
+			tideSource: func() tide.Source {
+				m := newMockTideSource()
+				m.
+					On("Extremes", coord, time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)).
+					Return([]tide.Extreme{
+						{Time: time.Date(2021, 1, 10, 4, 0, 0, 0, time.UTC), Type: tide.TypeHigh},
+					}, nil)
+				return m
+			}(),
+			params: SpotTidesParams{
+				SpotID: " 1 ",
+				Date:   " ",
+			},
+			expectedExtremes: []tide.Extreme{
+				{Time: time.Date(2021, 1, 10, 4, 0, 0, 0, time.UTC), Type: tide.TypeHigh},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return extremes of date a week after today without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Location: geo.Location{Coordinates: coord}}, nil)
+				return m
+			}(),
+			tideSource: func() tide.Source {
+				m := newMockTideSource()
+				m.
+					On("Extremes", coord, time.Date(2021, 1, 17, 0, 0, 0, 0, time.UTC)).
+					Return([]tide.Extreme{
+						{Time: time.Date(2021, 1, 17, 9, 0, 0, 0, time.UTC), Type: tide.TypeLow, Height: pconv.Float64(-0.5)},
+					}, nil)
+				return m
+			}(),
+			params: SpotTidesParams{
+				SpotID: "1",
+				Date:   "2021-01-17",
+			},
+			expectedExtremes: []tide.Extreme{
+				{Time: time.Date(2021, 1, 17, 9, 0, 0, 0, time.UTC), Type: tide.TypeLow, Height: pconv.Float64(-0.5)},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.tideSource != nil {
+				opts = append(opts, WithTideSource(test.tideSource))
+			}
+			s := NewService(test.spotStore, opts...)
+			s.now = func() time.Time { return now }
+
+			extremes, err := s.SpotTides(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedExtremes, extremes)
+		})
+	}
+}
+
+func TestService_RateSpot(t *testing.T) {
+	now := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	entry := surf.SpotRatingEntry{
+		SpotID:   "1",
+		Score:    4,
+		RaterKey: "6694f83c9f476da31f5df6bcc520034e7e57d421d247b9d34f49edbfc84a764c",
+		RatedOn:  now,
+	}
+
+	tests := []struct {
+		name            string
+		spotStore       SpotStore
+		params          RateSpotParams
+		expectedRatings surf.SpotRatings
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name:            "return error for invalid params",
+			spotStore:       newMockSpotStore(),
+			params:          RateSpotParams{SpotID: " ", Score: 6, ClientIP: "1.2.3.4"},
+			expectedRatings: surf.SpotRatings{},
+			expectedErrFn:   testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidScore),
+		},
+		{
+			name:            "return error for zero score",
+			spotStore:       newMockSpotStore(),
+			params:          RateSpotParams{SpotID: "1", Score: 0, ClientIP: "1.2.3.4"},
+			expectedRatings: surf.SpotRatings{},
+			expectedErrFn:   testutil.AreValidationErrors(ErrInvalidScore),
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RateSpot", mock.Anything, entry).
+					Return(surf.ErrSpotNotFound)
+				return m
+			}(),
+			params:          RateSpotParams{SpotID: "1", Score: 4, ClientIP: "1.2.3.4"},
+			expectedRatings: surf.SpotRatings{},
+			expectedErrFn:   testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RateSpot", mock.Anything, entry).
+					Return(nil)
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params:          RateSpotParams{SpotID: "1", Score: 4, ClientIP: "1.2.3.4"},
+			expectedRatings: surf.SpotRatings{},
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return updated ratings using sanitized spot id and hashed ip without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RateSpot", mock.Anything, entry).
+					Return(nil)
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{ID: "1", Ratings: surf.SpotRatings{Average: 4.5, Count: 2}}, nil)
+				return m
+			}(),
+			params:          RateSpotParams{SpotID: " 1 ", Score: 4, ClientIP: "1.2.3.4"},
+			expectedRatings: surf.SpotRatings{Average: 4.5, Count: 2},
+			expectedErrFn:   assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+			s.now = func() time.Time { return now }
+
+			ratings, err := s.RateSpot(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedRatings, ratings)
+		})
+	}
+}
+
+func userContext() context.Context {
+	return jwt.ContextWith(context.Background(), jwt.Claims{
+		StandardClaims: jwtgo.StandardClaims{Subject: "user"},
+	})
+}
+
+func TestService_FavoriteSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		spotStore     SpotStore
+		spotID        string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unauthenticated request",
+			ctx:           context.Background(),
+			spotStore:     newMockSpotStore(),
+			spotID:        "1",
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:          "return error for invalid spot id",
+			ctx:           userContext(),
+			spotStore:     newMockSpotStore(),
+			spotID:        " ",
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error for unexisting spot",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("FavoriteSpot", mock.Anything, "user", "1").
+					Return(surf.ErrSpotNotFound)
+				return m
+			}(),
+			spotID:        "1",
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "favorite spot using sanitized id without error",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("FavoriteSpot", mock.Anything, "user", "1").
+					Return(nil)
+				return m
+			}(),
+			spotID:        " 1 ",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			err := s.FavoriteSpot(test.ctx, test.spotID)
+			test.expectedErrFn(t, err)
+		})
+	}
+}
+
+func TestService_UnfavoriteSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		spotStore     SpotStore
+		spotID        string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unauthenticated request",
+			ctx:           context.Background(),
+			spotStore:     newMockSpotStore(),
+			spotID:        "1",
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:          "return error for invalid spot id",
+			ctx:           userContext(),
+			spotStore:     newMockSpotStore(),
+			spotID:        "",
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID),
+		},
+		{
+			name: "return error during spot store failure",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UnfavoriteSpot", mock.Anything, "user", "1").
+					Return(errors.New("something went wrong"))
+				return m
+			}(),
+			spotID:        "1",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "unfavorite spot using sanitized id without error",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("UnfavoriteSpot", mock.Anything, "user", "1").
+					Return(nil)
+				return m
+			}(),
+			spotID:        " 1 ",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			err := s.UnfavoriteSpot(test.ctx, test.spotID)
+			test.expectedErrFn(t, err)
+		})
+	}
+}
+
+func TestService_ApplyFavoriteBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		spotStore      SpotStore
+		params         FavoriteBatchParams
+		expectedResult FavoriteBatchResult
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for unauthenticated request",
+			ctx:       context.Background(),
+			spotStore: newMockSpotStore(),
+			params: FavoriteBatchParams{
+				Favorite: []string{"1"},
+			},
+			expectedResult: FavoriteBatchResult{},
+			expectedErrFn:  testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name:           "return error for empty batch",
+			ctx:            userContext(),
+			spotStore:      newMockSpotStore(),
+			params:         FavoriteBatchParams{},
+			expectedResult: FavoriteBatchResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name:      "return error for too many spot ids",
+			ctx:       userContext(),
+			spotStore: newMockSpotStore(),
+			params: func() FavoriteBatchParams {
+				var p FavoriteBatchParams
+				for i := 0; i < maxFavoriteBatchSpotIDs; i++ {
+					p.Favorite = append(p.Favorite, "f"+strconv.Itoa(i))
+				}
+				p.Unfavorite = []string{"u"}
+				return p
+			}(),
+			expectedResult: FavoriteBatchResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name:      "return error for invalid and conflicting spot ids",
+			ctx:       userContext(),
+			spotStore: newMockSpotStore(),
+			params: FavoriteBatchParams{
+				Favorite:   []string{"1", " "},
+				Unfavorite: []string{" 1"},
+			},
+			expectedResult: FavoriteBatchResult{},
+			expectedErrFn:  testutil.AreValidationErrors(ErrConflictingSpotIDs, ErrInvalidSpotID),
+		},
+		{
+			name: "return error during spot store failure",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surf.FavoriteBatch{
+						UserID:     "user",
+						Favorite:   []string{"1"},
+						Unfavorite: []string{},
+					}).
+					Return(([]string)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: FavoriteBatchParams{
+				Favorite: []string{"1"},
+			},
+			expectedResult: FavoriteBatchResult{},
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "apply batch using sanitized ids and report missing spots without error",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("ApplyFavoriteBatch", mock.Anything, surf.FavoriteBatch{
+						UserID:     "user",
+						Favorite:   []string{"1", "2", "3"},
+						Unfavorite: []string{"4"},
+					}).
+					Return([]string{"3", "1"}, nil)
+				return m
+			}(),
+			params: FavoriteBatchParams{
+				Favorite:   []string{" 1 ", "2", "1", "3"},
+				Unfavorite: []string{"4", " 4"},
+			},
+			expectedResult: FavoriteBatchResult{
+				FavoritedIDs:   []string{"1", "3"},
+				UnfavoritedIDs: []string{"4"},
+				NotFoundIDs:    []string{"2"},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			result, err := s.ApplyFavoriteBatch(test.ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
+
+func TestService_FavoriteSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		spotStore     SpotStore
+		params        FavoriteSpotsParams
+		expectedSpots []surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unauthenticated request",
+			ctx:           context.Background(),
+			spotStore:     newMockSpotStore(),
+			expectedSpots: nil,
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return error during spot store failure",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("FavoriteSpots", mock.Anything, surf.FavoriteSpotsParams{UserID: "user", Limit: 10}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots using bounded page without error",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("FavoriteSpots", mock.Anything, surf.FavoriteSpotsParams{UserID: "user", Limit: 100, Offset: 0}).
+					Return([]surf.Spot{{ID: "1"}}, nil)
+				return m
+			}(),
+			params:        FavoriteSpotsParams{Limit: 1000, Offset: -1},
+			expectedSpots: []surf.Spot{{ID: "1"}},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			spots, err := s.FavoriteSpots(test.ctx, test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+		})
+	}
+}
+
+func TestService_FavoritedSpotIDs(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		spotStore     SpotStore
+		expectedIDs   map[string]bool
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unauthenticated request",
+			ctx:           context.Background(),
+			spotStore:     newMockSpotStore(),
+			expectedIDs:   nil,
+			expectedErrFn: testutil.IsError(jwt.ErrClaimsNotFound),
+		},
+		{
+			name: "return ids without error",
+			ctx:  userContext(),
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("FavoritedSpotIDs", mock.Anything, "user", []string{"1", "2"}).
+					Return(map[string]bool{"2": true}, nil)
+				return m
+			}(),
+			expectedIDs:   map[string]bool{"2": true},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			ids, err := s.FavoritedSpotIDs(test.ctx, []string{"1", "2"})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestService_LocalizedSpot(t *testing.T) {
+	spot := surf.Spot{
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+			Locality:    "Aktau",
+			CountryCode: "kz",
+		},
+		ID:        "1",
+		Name:      "Spot 1",
+		CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+	}
+
+	tests := []struct {
+		name                  string
+		spotStore             SpotStore
+		localizer             geo.LocalizedLocationSource
+		params                LocalizedSpotParams
+		expectedLocalizedSpot LocalizedSpot
+		expectedErrFn         assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid params",
+			spotStore: newMockSpotStore(),
+			localizer: newMockLocalizer(),
+			params: LocalizedSpotParams{
+				SpotID:   "",
+				Language: "russian",
+			},
+			expectedLocalizedSpot: LocalizedSpot{},
+			expectedErrFn:         testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidLanguage),
+		},
+		{
+			name:      "return error when localizer is not set",
+			spotStore: newMockSpotStore(),
+			localizer: nil,
+			params: LocalizedSpotParams{
+				SpotID:   "1",
+				Language: "ru",
+			},
+			expectedLocalizedSpot: LocalizedSpot{},
+			expectedErrFn:         testutil.IsError(ErrLocalizationUnavailable),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, errors.New("something went wrong"))
+				return m
+			}(),
+			localizer: newMockLocalizer(),
+			params: LocalizedSpotParams{
+				SpotID:   "1",
+				Language: "ru",
+			},
+			expectedLocalizedSpot: LocalizedSpot{},
+			expectedErrFn:         assert.Error,
+		},
+		{
+			name: "return error during localizer failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			localizer: func() geo.LocalizedLocationSource {
+				m := newMockLocalizer()
+				m.
+					On("LocalizedLocation", spot.Location.Coordinates, "ru").
+					Return(geo.Location{}, errors.New("something went wrong"))
+				return m
+			}(),
+			params: LocalizedSpotParams{
+				SpotID:   "1",
+				Language: "ru",
+			},
+			expectedLocalizedSpot: LocalizedSpot{},
+			expectedErrFn:         assert.Error,
+		},
+		{
+			name: "return spot without localized locality when location is not found",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			localizer: func() geo.LocalizedLocationSource {
+				m := newMockLocalizer()
+				m.
+					On("LocalizedLocation", spot.Location.Coordinates, "ru").
+					Return(geo.Location{}, geo.ErrLocationNotFound)
+				return m
+			}(),
+			params: LocalizedSpotParams{
+				SpotID:   "1",
+				Language: "ru",
+			},
+			expectedLocalizedSpot: LocalizedSpot{
+				Spot: spot,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return localized spot using sanitized params without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				return m
+			}(),
+			localizer: func() geo.LocalizedLocationSource {
+				m := newMockLocalizer()
+				m.
+					On("LocalizedLocation", spot.Location.Coordinates, "pt-BR").
+					Return(geo.Location{Locality: "Aktau (pt)"}, nil)
+				return m
+			}(),
+			params: LocalizedSpotParams{
+				SpotID:   " 1 ",
+				Language: " pt-BR ",
+			},
+			expectedLocalizedSpot: LocalizedSpot{
+				Spot:              spot,
+				LocalizedLocality: "Aktau (pt)",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []Option
+			if test.localizer != nil {
+				opts = append(opts, WithLocalizer(test.localizer))
+			}
+			s := NewService(test.spotStore, opts...)
+
+			ls, err := s.LocalizedSpot(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedLocalizedSpot, ls)
+		})
+	}
+}
+
+func TestService_Spots(t *testing.T) {
+	tests := []struct {
+		name           string
+		spotStore      SpotStore
+		locationSource geo.LocationSource
+		params         SpotsParams
+		expectedSpots  []surf.Spot
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid country code",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:       20,
+				Offset:      0,
+				CountryCode: "invalid",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidCountryCode),
+		},
+		{
+			name:      "return error for invalid query",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:       20,
+				Offset:      0,
+				CountryCode: "kz",
+				SearchQuery: strutil.RepeatRune('a', 101),
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSearchQuery),
+		},
+		{
+			name:      "return error for invalid north-east latitude",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  91,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidNorthEastLatitude),
+		},
+		{
+			name:      "return error for invalid north-east longitude",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 181,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidNorthEastLongitude),
+		},
+		{
+			name:      "return error for invalid south-west latitude",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -91,
+						Longitude: -180,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSouthWestLatitude),
+		},
+		{
+			name:      "return error for invalid south-west longitude",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -181,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSouthWestLongitude),
+		},
+		{
+			name:      "return error for inverted latitudes",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: 20,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  10,
+						Longitude: -20,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidBounds),
+		},
+		{
+			name:      "return error for inverted latitudes and longitudes",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: -20,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  10,
+						Longitude: 20,
+					},
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidBounds),
+		},
+		{
+			name: "return spots by bounds crossing antimeridian without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  20,
+						Offset: 0,
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  10,
+								Longitude: -170,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -10,
+								Longitude: 170,
+							},
+						},
+					}).
+					Return([]surf.Spot{}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  10,
+						Longitude: -170,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: 170,
+					},
+				},
+			},
+			expectedSpots: []surf.Spot{},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "return error for too many tags",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Tags:   []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTags),
+		},
+		{
+			name:      "return error for too long tag",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+				Tags:   []string{strutil.RepeatRune('a', 31)},
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTags),
+		},
+		{
+			name:      "return error for invalid tags match",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:     20,
+				Offset:    0,
+				Tags:      []string{"reef"},
+				TagsMatch: "some",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidTagsMatch),
+		},
+		{
+			name:      "return error for invalid search mode",
+			spotStore: newMockSpotStore(),
+			params: SpotsParams{
+				Limit:       20,
+				Offset:      0,
+				SearchQuery: "query",
+				SearchMode:  "regex",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSearchMode),
+		},
+		{
+			name: "return spots using full-text search mode without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  20,
+						Offset: 0,
+						SearchQuery: surf.SpotSearchQuery{
+							Query:    "kazakh beach",
+							FullText: true,
+						},
+					}).
+					Return([]surf.Spot{
+						{
+							ID: "1",
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:       20,
+				Offset:      0,
+				SearchQuery: " kazakh beach ",
+				SearchMode:  " FullText ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID: "1",
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots using sanitized tags without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:     20,
+						Offset:    0,
+						Tags:      []string{"reef", "point"},
+						TagsMatch: surf.SpotTagsMatchAny,
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Tags: []string{"reef"},
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:     20,
+				Offset:    0,
+				Tags:      []string{" Reef ", "", "point", "reef"},
+				TagsMatch: " ANY ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:   "1",
+					Tags: []string{"reef"},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return error during spot spore failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:  20,
+						Offset: 0,
+					}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:  20,
+				Offset: 0,
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots using sanitized params without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:       10,
+						Offset:      0,
+						CountryCode: "kz",
+						SearchQuery: surf.SpotSearchQuery{
+							Query: "query",
+						},
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:        "2",
+								Name:      "Spot 2",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:       0,
+				Offset:      -1,
+				CountryCode: " kz ",
+				SearchQuery: " query ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+					},
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+				{
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						Locality:    "Locality 2",
+						CountryCode: "kz",
+					},
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spots", mock.Anything, surf.SpotsParams{
+						Limit:       20,
+						Offset:      3,
+						CountryCode: "kz",
+					}).
+					Return(
+						[]surf.Spot{
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 1",
+									CountryCode: "kz",
+								},
+								ID:        "1",
+								Name:      "Spot 1",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+							{
+								Location: geo.Location{
+									Coordinates: geo.Coordinates{
+										Latitude:  1.23,
+										Longitude: 3.21,
+									},
+									Locality:    "Locality 2",
+									CountryCode: "kz",
+								},
+								ID:        "2",
+								Name:      "Spot 2",
+								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							},
+						},
+						nil,
+					)
+				return m
+			}(),
+			params: SpotsParams{
+				Limit:       20,
+				Offset:      3,
+				CountryCode: "kz",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+					},
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+				{
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						Locality:    "Locality 2",
+						CountryCode: "kz",
+					},
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			spots, err := s.Spots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+		})
+	}
+}
+
+func TestService_SpotMarkers(t *testing.T) {
+	tests := []struct {
+		name            string
+		spotStore       SpotStore
+		params          SpotMarkersParams
+		expectedMarkers []surf.SpotMarker
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid bounds",
+			spotStore: newMockSpotStore(),
+			params: SpotMarkersParams{
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  91,
+						Longitude: 181,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -91,
+						Longitude: -181,
+					},
+				},
+			},
+			expectedMarkers: nil,
+			expectedErrFn: testutil.AreValidationErrors(
+				ErrInvalidNorthEastLatitude,
+				ErrInvalidNorthEastLongitude,
+				ErrInvalidSouthWestLatitude,
+				ErrInvalidSouthWestLongitude,
+			),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotMarkers", mock.Anything, surf.SpotMarkersParams{
+						Limit: 5000,
+					}).
+					Return(([]surf.SpotMarker)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:          SpotMarkersParams{},
+			expectedMarkers: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return markers without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotMarkers", mock.Anything, surf.SpotMarkersParams{
+						Limit: 5000,
+						Bounds: &geo.Bounds{
+							NorthEast: geo.Coordinates{
+								Latitude:  90,
+								Longitude: 180,
+							},
+							SouthWest: geo.Coordinates{
+								Latitude:  -90,
+								Longitude: -180,
+							},
+						},
+					}).
+					Return([]surf.SpotMarker{
+						{
+							ID: "1",
+							Coordinates: geo.Coordinates{
+								Latitude:  1.23,
+								Longitude: 3.21,
+							},
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotMarkersParams{
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			expectedMarkers: []surf.SpotMarker{
+				{
+					ID: "1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			markers, err := s.SpotMarkers(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedMarkers, markers)
+		})
+	}
+}
+
+func TestService_RandomSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		spotStore     SpotStore
+		params        RandomSpotsParams
+		expectedSpots []surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid country code",
+			spotStore: newMockSpotStore(),
+			params: RandomSpotsParams{
+				CountryCode: "zz",
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RandomSpots", mock.Anything, surf.RandomSpotsParams{
+						Limit: 1,
+					}).
+					Return(([]surf.Spot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:        RandomSpotsParams{},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return capped number of spots without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("RandomSpots", mock.Anything, surf.RandomSpotsParams{
+						Limit:       20,
+						CountryCode: "kz",
+					}).
+					Return([]surf.Spot{
+						{
+							ID:   "1",
+							Name: "Spot 1",
+						},
+					}, nil)
+				return m
+			}(),
+			params: RandomSpotsParams{
+				Count:       1000,
+				CountryCode: " KZ ",
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:   "1",
+					Name: "Spot 1",
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			spots, err := s.RandomSpots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+		})
+	}
+}
+
+func TestService_SpotsCentroid(t *testing.T) {
+	tests := []struct {
+		name             string
+		spotStore        SpotStore
+		params           SpotsCentroidParams
+		expectedCentroid *geo.Coordinates
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid params",
+			spotStore: newMockSpotStore(),
+			params: SpotsCentroidParams{
+				CountryCode: "zz",
+				SearchQuery: strutil.RepeatRune('a', 101),
+			},
+			expectedCentroid: nil,
+			expectedErrFn:    testutil.AreValidationErrors(ErrInvalidSearchQuery, ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotsCentroid", mock.Anything, surf.SpotsCentroidParams{}).
+					Return((*geo.Coordinates)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:           SpotsCentroidParams{},
+			expectedCentroid: nil,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name: "return centroid without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotsCentroid", mock.Anything, surf.SpotsCentroidParams{
+						CountryCode: "kz",
+						SearchQuery: surf.SpotSearchQuery{
+							Query: "query",
+						},
+					}).
+					Return(&geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					}, nil)
+				return m
+			}(),
+			params: SpotsCentroidParams{
+				CountryCode: " KZ ",
+				SearchQuery: " query ",
+			},
+			expectedCentroid: &geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			centroid, err := s.SpotsCentroid(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCentroid, centroid)
+		})
+	}
+}
+
+func TestService_NearbySpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		spotStore     SpotStore
+		params        NearbySpotsParams
+		expectedSpots []surf.NearbySpot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid params",
+			spotStore: newMockSpotStore(),
+			params: NearbySpotsParams{
+				Coordinates: geo.Coordinates{
+					Latitude:  91,
+					Longitude: 181,
+				},
+				RadiusKm: -1,
+			},
+			expectedSpots: nil,
+			expectedErrFn: testutil.AreValidationErrors(
+				ErrInvalidLatitude,
+				ErrInvalidLongitude,
+				ErrInvalidRadius,
+			),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("NearbySpots", mock.Anything, surf.NearbySpotsParams{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						RadiusKm: 50,
+						Limit:    10,
+					}).
+					Return(([]surf.NearbySpot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: NearbySpotsParams{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots using capped radius and limit without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("NearbySpots", mock.Anything, surf.NearbySpotsParams{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						RadiusKm: 500,
+						Limit:    100,
+					}).
+					Return([]surf.NearbySpot{
+						{
+							Spot: surf.Spot{
+								ID:   "1",
+								Name: "Spot 1",
+							},
+							DistanceKm: 1.23,
+						},
+					}, nil)
+				return m
+			}(),
+			params: NearbySpotsParams{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				RadiusKm: 1000,
+				Limit:    1000,
+			},
+			expectedSpots: []surf.NearbySpot{
+				{
+					Spot: surf.Spot{
+						ID:   "1",
+						Name: "Spot 1",
+					},
+					DistanceKm: 1.23,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			spots, err := s.NearbySpots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+		})
+	}
+}
+
+func TestService_SuggestSpotChange(t *testing.T) {
+	suggestion := surf.SpotSuggestion{
+		ID:     "11",
+		Status: surf.SpotSuggestionStatusPending,
+		Change: surf.SpotUpdateEntry{
+			ID:        "1",
+			Latitude:  pconv.Float64(1.23),
+			Longitude: pconv.Float64(3.21),
+			Region:    pconv.String(""),
+		},
+		Note:      "Coordinates point at the parking lot",
+		CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name               string
+		spotStore          SpotStore
+		params             SuggestSpotChangeParams
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for values invalid by management rules",
+			spotStore: newMockSpotStore(),
+			params: SuggestSpotChangeParams{
+				Change: management.UpdateSpotParams{
+					ID:          "",
+					Name:        pconv.String(" "),
+					Latitude:    pconv.Float64(91),
+					CountryCode: pconv.String("zz"),
+				},
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn: testutil.AreValidationErrors(
+				management.ErrInvalidSpotID,
+				management.ErrInvalidSpotName,
+				management.ErrInvalidLatitude,
+				management.ErrInvalidCountryCode,
+			),
+		},
+		{
+			name:      "return error for too long note",
+			spotStore: newMockSpotStore(),
+			params: SuggestSpotChangeParams{
+				Change: management.UpdateSpotParams{
+					ID:   "1",
+					Name: pconv.String("Spot 1"),
+				},
+				Note: strutil.RepeatRune('a', 501),
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidSuggestionNote),
+		},
+		{
+			name:      "return error for empty change",
+			spotStore: newMockSpotStore(),
+			params: SuggestSpotChangeParams{
+				Change: management.UpdateSpotParams{
+					ID: "1",
+				},
+				Note: "Note 1",
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(ErrEmptySpotChange),
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotSuggestion", mock.Anything, surf.SpotSuggestionEntry{
+						Change: surf.SpotUpdateEntry{
+							ID:   "1",
+							Name: pconv.String("Spot 1"),
+						},
+					}).
+					Return(surf.SpotSuggestion{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			params: SuggestSpotChangeParams{
+				Change: management.UpdateSpotParams{
+					ID:   "1",
+					Name: pconv.String("Spot 1"),
+				},
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return suggestion created using sanitized params",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CreateSpotSuggestion", mock.Anything, surf.SpotSuggestionEntry{
+						Change: suggestion.Change,
+						Note:   suggestion.Note,
+					}).
+					Return(suggestion, nil)
+				return m
+			}(),
+			params: SuggestSpotChangeParams{
+				Change: management.UpdateSpotParams{
+					ID:          " 1 ",
+					Latitude:    pconv.Float64(1.23),
+					Longitude:   pconv.Float64(3.21),
+					ClearRegion: true,
+				},
+				Note: " Coordinates point at the parking lot ",
+			},
+			expectedSuggestion: suggestion,
+			expectedErrFn:      assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			suggestion, err := s.SuggestSpotChange(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			test.spotStore.(*mockSpotStore).AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_SpotWithNearby(t *testing.T) {
+	spot := surf.Spot{
+		ID:   "1",
+		Name: "Spot 1",
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		spotStore     SpotStore
+		params        SpotWithNearbyParams
+		expected      SpotWithNearby
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid params",
+			spotStore: newMockSpotStore(),
+			params: SpotWithNearbyParams{
+				SpotID:   " ",
+				RadiusKm: -1,
+			},
+			expected:      SpotWithNearby{},
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotID, ErrInvalidRadius),
+		},
+		{
+			name: "return error for unexisting spot",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(surf.Spot{}, surf.ErrSpotNotFound)
+				return m
+			}(),
+			params: SpotWithNearbyParams{
+				SpotID: "1",
+			},
+			expected:      SpotWithNearby{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("NearbySpots", mock.Anything, surf.NearbySpotsParams{
+						Coordinates: spot.Location.Coordinates,
+						RadiusKm:    50,
+						Limit:       11,
+					}).
+					Return(([]surf.NearbySpot)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: SpotWithNearbyParams{
+				SpotID: "1",
+			},
+			expected:      SpotWithNearby{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spot with nearby spots using capped radius without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Spot", mock.Anything, "1").
+					Return(spot, nil)
+				m.
+					On("NearbySpots", mock.Anything, surf.NearbySpotsParams{
+						Coordinates: spot.Location.Coordinates,
+						RadiusKm:    500,
+						Limit:       11,
+					}).
+					Return([]surf.NearbySpot{
+						{
+							Spot: spot,
 						},
-						Locality:    "Locality 1",
-						CountryCode: "kz",
+						{
+							Spot: surf.Spot{
+								ID:   "2",
+								Name: "Spot 2",
+							},
+							DistanceKm: 1.23,
+						},
+					}, nil)
+				return m
+			}(),
+			params: SpotWithNearbyParams{
+				SpotID:   " 1 ",
+				RadiusKm: 1000,
+			},
+			expected: SpotWithNearby{
+				Spot: spot,
+				Nearby: []surf.NearbySpot{
+					{
+						Spot: surf.Spot{
+							ID:   "2",
+							Name: "Spot 2",
+						},
+						DistanceKm: 1.23,
 					},
-					ID:        "1",
-					Name:      "Spot 1",
-					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
 				},
-				{
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			swn, err := s.SpotWithNearby(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expected, swn)
+		})
+	}
+}
+
+func TestService_SpotRoute(t *testing.T) {
+	// Spots are placed along the equator, where a degree of longitude is roughly
+	// 111.19 kilometers.
+	spotStore := func() *mockSpotStore {
+		m := newMockSpotStore()
+		for id, lon := range map[string]float64{"a": 0, "b": 1, "c": 3} {
+			m.
+				On("Spot", mock.Anything, id).
+				Return(surf.Spot{
+					ID: id,
 					Location: geo.Location{
 						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+							Longitude: lon,
 						},
-						Locality:    "Locality 2",
-						CountryCode: "kz",
 					},
-					ID:        "2",
-					Name:      "Spot 2",
-					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+				}, nil)
+		}
+		m.
+			On("Spot", mock.Anything, "d").
+			Return(surf.Spot{}, surf.ErrSpotNotFound)
+		m.
+			On("Spot", mock.Anything, "e").
+			Return(surf.Spot{}, errors.New("something went wrong"))
+		return m
+	}
+
+	type stop struct {
+		id                   string
+		distanceKm           float64
+		cumulativeDistanceKm float64
+	}
+
+	tests := []struct {
+		name          string
+		params        SpotRouteParams
+		expectedStops []stop
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for invalid params",
+			params: SpotRouteParams{
+				SpotIDs: []string{"a", " "},
+				Start: &geo.Coordinates{
+					Latitude:  91,
+					Longitude: 181,
+				},
+			},
+			expectedStops: nil,
+			expectedErrFn: testutil.AreValidationErrors(
+				ErrInvalidSpotID,
+				ErrInvalidLatitude,
+				ErrInvalidLongitude,
+			),
+		},
+		{
+			name: "return error for empty spot ids",
+			params: SpotRouteParams{
+				SpotIDs: nil,
+			},
+			expectedStops: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name: "return error for too many spot ids",
+			params: SpotRouteParams{
+				SpotIDs: func() []string {
+					ids := make([]string, 26)
+					for i := range ids {
+						ids[i] = strconv.Itoa(i)
+					}
+					return ids
+				}(),
+			},
+			expectedStops: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrInvalidSpotIDs),
+		},
+		{
+			name: "return error for unknown spot id",
+			params: SpotRouteParams{
+				SpotIDs: []string{"a", "d"},
+			},
+			expectedStops: nil,
+			expectedErrFn: testutil.AreValidationErrors(ErrUnknownSpotID),
+		},
+		{
+			name: "return error during spot store failure",
+			params: SpotRouteParams{
+				SpotIDs: []string{"a", "e"},
+			},
+			expectedStops: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots ordered from start point without error",
+			params: SpotRouteParams{
+				SpotIDs: []string{"a", "b", "c"},
+				Start: &geo.Coordinates{
+					Longitude: 2.1,
 				},
 			},
+			expectedStops: []stop{
+				{id: "c", distanceKm: 100.08, cumulativeDistanceKm: 100.08},
+				{id: "b", distanceKm: 222.39, cumulativeDistanceKm: 322.47},
+				{id: "a", distanceKm: 111.19, cumulativeDistanceKm: 433.66},
+			},
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots without error",
+			name: "return spots ordered from first spot using sanitized ids without error",
+			params: SpotRouteParams{
+				SpotIDs: []string{" b ", "c", "a", "b"},
+			},
+			expectedStops: []stop{
+				{id: "b", distanceKm: 0, cumulativeDistanceKm: 0},
+				{id: "a", distanceKm: 111.19, cumulativeDistanceKm: 111.19},
+				{id: "c", distanceKm: 333.58, cumulativeDistanceKm: 444.78},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(spotStore())
+
+			stops, err := s.SpotRoute(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+
+			if !assert.Len(t, stops, len(test.expectedStops)) {
+				return
+			}
+			for i, expected := range test.expectedStops {
+				assert.Equal(t, expected.id, stops[i].Spot.ID)
+				assert.InDelta(t, expected.distanceKm, stops[i].DistanceKm, 0.01)
+				assert.InDelta(t, expected.cumulativeDistanceKm, stops[i].CumulativeDistanceKm, 0.01)
+			}
+		})
+	}
+}
+
+func TestService_Countries(t *testing.T) {
+	tests := []struct {
+		name           string
+		spotStore      SpotStore
+		params         CountriesParams
+		expectedCounts []surf.CountrySpotCount
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid search query",
+			spotStore: newMockSpotStore(),
+			params: CountriesParams{
+				SearchQuery: strutil.RepeatRune('a', 101),
+			},
+			expectedCounts: nil,
+			expectedErrFn:  testutil.AreValidationErrors(ErrInvalidSearchQuery),
+		},
+		{
+			name: "return error during spot store failure",
 			spotStore: func() SpotStore {
 				m := newMockSpotStore()
 				m.
-					On("Spots", surf.SpotsParams{
-						Limit:       20,
-						Offset:      3,
-						CountryCode: "kz",
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{}).
+					Return(([]surf.CountrySpotCount)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:         CountriesParams{},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return counts using sanitized search query without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("CountriesWithSpotCounts", mock.Anything, surf.CountriesParams{
+						SearchQuery: surf.SpotSearchQuery{
+							Query: "query",
+						},
 					}).
-					Return(
-						[]surf.Spot{
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 1",
-									CountryCode: "kz",
-								},
-								ID:        "1",
-								Name:      "Spot 1",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
-							{
-								Location: geo.Location{
-									Coordinates: geo.Coordinates{
-										Latitude:  1.23,
-										Longitude: 3.21,
-									},
-									Locality:    "Locality 2",
-									CountryCode: "kz",
-								},
-								ID:        "2",
-								Name:      "Spot 2",
-								CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
-							},
+					Return([]surf.CountrySpotCount{
+						{
+							CountryCode: "kz",
+							Count:       12,
 						},
-						nil,
-					)
+					}, nil)
 				return m
 			}(),
-			params: SpotsParams{
-				Limit:       20,
-				Offset:      3,
-				CountryCode: "kz",
+			params: CountriesParams{
+				SearchQuery: " query ",
 			},
-			expectedSpots: []surf.Spot{
+			expectedCounts: []surf.CountrySpotCount{
 				{
-					Location: geo.Location{
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-						Locality:    "Locality 1",
-						CountryCode: "kz",
-					},
-					ID:        "1",
-					Name:      "Spot 1",
-					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+					CountryCode: "kz",
+					Count:       12,
 				},
-				{
-					Location: geo.Location{
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			counts, err := s.Countries(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+		})
+	}
+}
+
+func TestService_SpotDirectory(t *testing.T) {
+	tests := []struct {
+		name              string
+		spotStore         SpotStore
+		params            SpotDirectoryParams
+		expectedCountries []surf.DirectoryCountry
+		expectedErrFn     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotDirectory", mock.Anything, surf.SpotDirectoryParams{
+						Limit:                10,
+						LocalitiesPerCountry: 10,
+						SpotsPerLocality:     5,
+					}).
+					Return(([]surf.DirectoryCountry)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params:            SpotDirectoryParams{},
+			expectedCountries: nil,
+			expectedErrFn:     assert.Error,
+		},
+		{
+			name: "return countries using limits within boundaries and defaults without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("SpotDirectory", mock.Anything, surf.SpotDirectoryParams{
+						Limit:                100,
+						Offset:               0,
+						LocalitiesPerCountry: 50,
+						SpotsPerLocality:     5,
+					}).
+					Return([]surf.DirectoryCountry{
+						{
+							CountryCode:   "kz",
+							SpotCount:     1,
+							LocalityCount: 1,
 						},
-						Locality:    "Locality 2",
-						CountryCode: "kz",
-					},
-					ID:        "2",
-					Name:      "Spot 2",
-					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+					}, nil)
+				return m
+			}(),
+			params: SpotDirectoryParams{
+				Limit:           1000,
+				Offset:          -1,
+				LocalitiesLimit: 1000,
+				SpotsLimit:      -1,
+			},
+			expectedCountries: []surf.DirectoryCountry{
+				{
+					CountryCode:   "kz",
+					SpotCount:     1,
+					LocalityCount: 1,
 				},
 			},
 			expectedErrFn: assert.NoError,
@@ -449,9 +2678,79 @@ func TestService_Spots(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			s := NewService(test.spotStore)
 
-			spots, err := s.Spots(test.params)
+			countries, err := s.SpotDirectory(context.Background(), test.params)
 			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedSpots, spots)
+			assert.Equal(t, test.expectedCountries, countries)
+		})
+	}
+}
+
+func TestService_Localities(t *testing.T) {
+	tests := []struct {
+		name               string
+		spotStore          SpotStore
+		params             LocalitiesParams
+		expectedLocalities []string
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "return error for invalid params",
+			spotStore: newMockSpotStore(),
+			params: LocalitiesParams{
+				Prefix:      " k ",
+				CountryCode: "zz",
+			},
+			expectedLocalities: nil,
+			expectedErrFn:      testutil.AreValidationErrors(ErrInvalidLocalityPrefix, ErrInvalidCountryCode),
+		},
+		{
+			name: "return error during spot store failure",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Localities", mock.Anything, surf.LocalitiesParams{
+						Prefix: "ka",
+						Limit:  10,
+					}).
+					Return(([]string)(nil), errors.New("something went wrong"))
+				return m
+			}(),
+			params: LocalitiesParams{
+				Prefix: "ka",
+			},
+			expectedLocalities: nil,
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return localities using sanitized params without error",
+			spotStore: func() SpotStore {
+				m := newMockSpotStore()
+				m.
+					On("Localities", mock.Anything, surf.LocalitiesParams{
+						Prefix:      "ka",
+						CountryCode: "kz",
+						Limit:       50,
+					}).
+					Return([]string{"Kapchagay", "Karaganda"}, nil)
+				return m
+			}(),
+			params: LocalitiesParams{
+				Prefix:      " ka ",
+				CountryCode: " KZ ",
+				Limit:       100,
+			},
+			expectedLocalities: []string{"Kapchagay", "Karaganda"},
+			expectedErrFn:      assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(test.spotStore)
+
+			localities, err := s.Localities(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedLocalities, localities)
 		})
 	}
 }
@@ -1,52 +1,131 @@
 package cmd
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"os"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/spf13/cobra"
 	"github.com/ztimes2/tolqin/app/api/internal/cli/config"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/service/importing"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/csv"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/psql"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/sqlite"
 	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
 )
 
-func newCSVSpotCreationEntrySource(filename string) (*csv.SpotCreationEntrySource, error) {
-	b, err := ioutil.ReadFile(filename)
+func newCSVSpotCreationEntrySource(
+	filename string,
+	opts ...csv.SpotCreationEntrySourceOption) (*csv.SpotCreationEntrySource, error) {
+
+	f, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("could not read csv file: %w", err)
+		return nil, fmt.Errorf("could not open csv file: %w", err)
 	}
 
-	return csv.NewSpotCreationEntrySource(bytes.NewReader(b)), nil
+	// The file is passed as is since encoding/csv buffers reads on its own, and the
+	// source needs to close the file.
+	return csv.NewSpotCreationEntrySource(f, opts...), nil
+}
+
+// spotStore is a spot store that spots can be imported to and seeded into.
+type spotStore interface {
+	surf.MultiSpotWriter
+	surf.SpotReader
+	BatchSize() int
 }
 
-func newPostgresSpotStore() (*psql.SpotStore, error) {
-	cfg, err := config.LoadDatabase()
+func newSpotStore(configPath string) (spotStore, error) {
+	cfg, err := config.LoadDatabase(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("could not load database config: %w", err)
 	}
 
-	db, err := psqlutil.NewDB(psqlutil.DriverNamePQ, psqlutil.Config{
+	if cfg.Driver == config.DatabaseDriverSQLite {
+		db, err := sqlite.Open(cfg.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not open sqlite db: %w", err)
+		}
+		return sqlite.NewSpotStore(db), nil
+	}
+
+	db, err := newPostgresDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return psql.NewSpotStore(db), nil
+}
+
+const postgresConnectTimeout = 5 * time.Second
+
+func newPostgresDB(cfg config.Database) (*sqlx.DB, error) {
+	dbConfig := psqlutil.Config{
 		Host:         cfg.Host,
 		Port:         cfg.Port,
 		Username:     cfg.Username,
 		Password:     cfg.Password,
 		DatabaseName: cfg.Name,
 		SSLMode:      psqlutil.NewSSLMode(cfg.SSLMode),
-	})
+	}
+
+	// The database is pinged right away, so that wrong credentials are reported
+	// before any work is done rather than on the first query.
+	db, err := psqlutil.NewDB(
+		psqlutil.DriverNamePQ,
+		dbConfig,
+		psqlutil.WithConnectRetry(1, 0),
+		psqlutil.WithConnectTimeout(postgresConnectTimeout),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("could not connect to postgres db: %w", err)
 	}
 
-	return psql.NewSpotStore(db), nil
+	return db, nil
+}
+
+const (
+	modeNameInsert = "insert"
+	modeNameUpsert = "upsert"
+)
+
+func parseSpotCreationMode(s string) (surf.SpotCreationMode, error) {
+	switch s {
+	case modeNameInsert:
+		return surf.SpotCreationModeInsert, nil
+	case modeNameUpsert:
+		return surf.SpotCreationModeUpsert, nil
+	default:
+		return 0, fmt.Errorf("invalid import mode: %q", s)
+	}
+}
+
+const (
+	reportFormatNameCSV  = "csv"
+	reportFormatNameJSON = "json"
+)
+
+func parseValidationReportWriter(s string) (func(io.Writer, importing.ValidationReport) error, error) {
+	switch s {
+	case reportFormatNameCSV:
+		return importing.WriteValidationReportCSV, nil
+	case reportFormatNameJSON:
+		return importing.WriteValidationReportJSON, nil
+	default:
+		return nil, fmt.Errorf("invalid report format: %q", s)
+	}
 }
 
 func newImportCmd(
-	csvSourceFn func(filename string) (*csv.SpotCreationEntrySource, error),
-	postgresStoreFn func() (*psql.SpotStore, error),
-	importFn func(surf.SpotCreationEntrySource, surf.MultiSpotWriter) (int, error),
+	csvSourceFn func(filename string, opts ...csv.SpotCreationEntrySourceOption) (*csv.SpotCreationEntrySource, error),
+	storeFn func(configPath string) (spotStore, error),
+	importFn func(context.Context, surf.SpotCreationEntrySource, surf.MultiSpotWriter, importing.ImportParams) (importing.ImportResult, error),
+	validateFn func(surf.SpotCreationEntrySource) (importing.ValidationReport, error),
 ) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import",
@@ -54,12 +133,15 @@ func newImportCmd(
 		Long: `Import spots from a CSV file to the database.
 
 Environment variables:
+  - DATABASE_DRIVER (postgres or sqlite, defaults to postgres)
   - DB_HOST
   - DB_PORT
   - DB_USERNAME
   - DB_PASSWORD
-  - DB_NAME
+  - DB_NAME (a path of the database file for sqlite)
   - DB_SSLMODE
+
+The variables can also be set in a YAML file passed via --config.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			filename, err := cmd.Flags().GetString("csv")
@@ -67,28 +149,268 @@ Environment variables:
 				return err
 			}
 
-			src, err := csvSourceFn(filename)
+			modeName, err := cmd.Flags().GetString("mode")
+			if err != nil {
+				return err
+			}
+
+			mode, err := parseSpotCreationMode(modeName)
+			if err != nil {
+				return err
+			}
+
+			lenient, err := cmd.Flags().GetBool("lenient-coordinates")
+			if err != nil {
+				return err
+			}
+
+			var csvOpts []csv.SpotCreationEntrySourceOption
+			if lenient {
+				csvOpts = append(csvOpts, csv.WithLenientCoordinates())
+			}
+
+			allowEmpty, err := cmd.Flags().GetBool("allow-empty")
+			if err != nil {
+				return err
+			}
+			if allowEmpty {
+				csvOpts = append(csvOpts, csv.WithEmptyInputAllowed())
+			}
+
+			tagsDelimiter, err := cmd.Flags().GetString("tags-delimiter")
+			if err != nil {
+				return err
+			}
+			if tagsDelimiter == "" {
+				return errors.New("tags delimiter must not be empty")
+			}
+			csvOpts = append(csvOpts, csv.WithTagsDelimiter(tagsDelimiter))
+
+			reportFilename, err := cmd.Flags().GetString("report")
+			if err != nil {
+				return err
+			}
+
+			reportFormatName, err := cmd.Flags().GetString("report-format")
+			if err != nil {
+				return err
+			}
+
+			writeReportFn, err := parseValidationReportWriter(reportFormatName)
+			if err != nil {
+				return err
+			}
+
+			maxPrinted, err := cmd.Flags().GetInt("max-printed-issues")
+			if err != nil {
+				return err
+			}
+
+			printer := reportPrinter{
+				w:              cmd.OutOrStdout(),
+				limit:          maxPrinted,
+				reportFilename: reportFilename,
+			}
+
+			saveReport := func(r importing.ValidationReport) error {
+				if reportFilename == "" {
+					return nil
+				}
+				if err := writeValidationReportFile(reportFilename, r, writeReportFn); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Validation report was written to %s\n", reportFilename)
+				return nil
+			}
+
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			skipInvalid, err := cmd.Flags().GetBool("skip-invalid")
+			if err != nil {
+				return err
+			}
+
+			// The source is validated in a separate pass beforehand unless invalid
+			// rows are skipped, so that a file with invalid rows does not get
+			// imported partially.
+			if dryRun || !skipInvalid {
+				src, err := csvSourceFn(filename, csvOpts...)
+				if err != nil {
+					return err
+				}
+				defer src.Close()
+
+				report, err := validateFn(src)
+				if err != nil {
+					return err
+				}
+
+				printer.printReport(report)
+
+				if err := saveReport(report); err != nil {
+					return err
+				}
+
+				if !report.IsValid() {
+					return fmt.Errorf("%d invalid row(s) found", len(report.InvalidLines()))
+				}
+
+				if dryRun {
+					fmt.Fprintln(cmd.OutOrStdout(), "All rows are valid!")
+					return nil
+				}
+			}
+
+			src, err := csvSourceFn(filename, csvOpts...)
 			if err != nil {
 				return err
 			}
+			defer src.Close()
 
-			dest, err := postgresStoreFn()
+			configPath, err := cmd.Flags().GetString(flagConfig)
 			if err != nil {
 				return err
 			}
 
-			n, err := importFn(src, dest)
+			dest, err := storeFn(configPath)
 			if err != nil {
 				return err
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "%d spot(s) were imported!\n", n)
+			res, err := importFn(cmd.Context(), src, dest, importing.ImportParams{
+				Mode:        mode,
+				SkipInvalid: skipInvalid,
+				BatchSize:   dest.BatchSize(),
+			})
+			if err != nil {
+				var interruptedErr *importing.InterruptedError
+				if errors.As(err, &interruptedErr) {
+					r := interruptedErr.Result
+					fmt.Fprintf(
+						cmd.OutOrStdout(),
+						"Import interrupted! %d spot(s) had been imported before interruption. (inserted: %d, updated: %d)\n",
+						r.Inserted+r.Updated, r.Inserted, r.Updated,
+					)
+				}
+
+				var invalidErr *importing.InvalidEntriesError
+				if errors.As(err, &invalidErr) {
+					printer.printReport(invalidErr.Report)
+					if err := saveReport(invalidErr.Report); err != nil {
+						return err
+					}
+				}
+				return err
+			}
+
+			// Warnings have already been printed during the validation pass
+			// unless it was skipped.
+			if skipInvalid {
+				printer.printWarnings(res.Warnings)
+
+				if err := saveReport(importing.ValidationReport{
+					Issues:   res.Skipped.Issues,
+					Warnings: res.Warnings,
+				}); err != nil {
+					return err
+				}
+			}
+
+			if !res.Skipped.IsValid() {
+				printer.printReport(res.Skipped)
+				fmt.Fprintf(cmd.OutOrStdout(), "%d invalid row(s) were skipped.\n", len(res.Skipped.InvalidLines()))
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"%d spot(s) were imported! (inserted: %d, updated: %d)\n",
+				res.Inserted+res.Updated, res.Inserted, res.Updated,
+			)
 
 			return nil
 		},
 	}
 
 	cmd.Flags().String("csv", "", "Name of a CSV file to import spots from.")
+	cmd.Flags().Bool("lenient-coordinates", false, "Accept coordinates in DMS/DDM notations besides decimal degrees.")
+	cmd.Flags().Bool("allow-empty", false, "Treat an empty CSV file as a successful import of 0 spots instead of failing.")
+	cmd.Flags().String("tags-delimiter", csv.DefaultTagsDelimiter, "Delimiter that separates tags within the optional tags column.")
+	cmd.Flags().String("mode", modeNameInsert, "Import mode: either insert or upsert (by external reference).")
+	cmd.Flags().Bool("dry-run", false, "Validate spots and print a report without importing them.")
+	cmd.Flags().Bool("skip-invalid", false, "Skip invalid rows instead of failing the whole import.")
+	cmd.Flags().String("report", "", "Name of a file to write a validation report of all skipped and invalid rows to.")
+	cmd.Flags().String("report-format", reportFormatNameCSV, "Validation report format: either csv or json.")
+	cmd.Flags().Int("max-printed-issues", defaultMaxPrintedIssues, "Maximum number of issues and warnings each to print. Zero prints all of them.")
 
 	return cmd
 }
+
+const defaultMaxPrintedIssues = 100
+
+// reportPrinter prints issues of a validation report, so that a file with
+// thousands of invalid rows does not flood the output. Once the limit is reached,
+// the rest of the issues are only counted, and the full report file is pointed
+// to.
+type reportPrinter struct {
+	w io.Writer
+
+	// limit caps a number of issues and warnings each that are printed. Zero
+	// means no limit.
+	limit int
+
+	// reportFilename is a name of the file the full report is written to, if any.
+	reportFilename string
+}
+
+func (p reportPrinter) printReport(r importing.ValidationReport) {
+	p.printIssues("", r.Issues)
+	p.printWarnings(r.Warnings)
+}
+
+func (p reportPrinter) printWarnings(warnings []importing.ValidationIssue) {
+	p.printIssues("warning: ", warnings)
+}
+
+func (p reportPrinter) printIssues(prefix string, issues []importing.ValidationIssue) {
+	for i, issue := range issues {
+		if p.limit > 0 && i == p.limit {
+			break
+		}
+		fmt.Fprintf(p.w, "%s%s\n", prefix, issue.Error())
+	}
+
+	if p.limit <= 0 || len(issues) <= p.limit {
+		return
+	}
+
+	fmt.Fprintf(p.w, "%s%d more of %d in total are not shown. ", prefix, len(issues)-p.limit, len(issues))
+	if p.reportFilename != "" {
+		fmt.Fprintf(p.w, "See %s for all of them.\n", p.reportFilename)
+	} else {
+		fmt.Fprintln(p.w, "Use --report to write all of them to a file.")
+	}
+}
+
+func writeValidationReportFile(
+	filename string,
+	r importing.ValidationReport,
+	writeFn func(io.Writer, importing.ValidationReport) error) error {
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("could not create report file: %w", err)
+	}
+
+	if err := writeFn(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write report file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close report file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/service/importing"
+)
+
+func TestNewCSVSpotCreationEntrySource_Close(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "spots.csv")
+	err := os.WriteFile(filename, []byte("name,latitude,longitude,locality,country_code\n"), 0o600)
+	assert.NoError(t, err)
+
+	src, err := newCSVSpotCreationEntrySource(filename)
+	assert.NoError(t, err)
+
+	assert.NoError(t, src.Close())
+
+	_, err = src.Next()
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestReportPrinter_PrintReport(t *testing.T) {
+	var issues []importing.ValidationIssue
+	for line := 2; line < 1002; line++ {
+		issues = append(issues, importing.ValidationIssue{
+			Line:   line,
+			Field:  "latitude",
+			Reason: "must be a valid latitude",
+		})
+	}
+
+	report := importing.ValidationReport{
+		Issues: issues,
+		Warnings: []importing.ValidationIssue{
+			{
+				Line:   2,
+				Field:  "name",
+				Reason: "looks like a placeholder",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		limit          int
+		reportFilename string
+		expectedOutput string
+	}{
+		{
+			name:           "print truncated issues with total count and pointer to report",
+			limit:          2,
+			reportFilename: "report.csv",
+			expectedOutput: "line 2: latitude: must be a valid latitude\n" +
+				"line 3: latitude: must be a valid latitude\n" +
+				"998 more of 1000 in total are not shown. See report.csv for all of them.\n" +
+				"warning: line 2: name: looks like a placeholder\n",
+		},
+		{
+			name:  "print truncated issues with total count and hint without report",
+			limit: 1,
+			expectedOutput: "line 2: latitude: must be a valid latitude\n" +
+				"999 more of 1000 in total are not shown. Use --report to write all of them to a file.\n" +
+				"warning: line 2: name: looks like a placeholder\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := reportPrinter{
+				w:              &buf,
+				limit:          test.limit,
+				reportFilename: test.reportFilename,
+			}
+
+			p.printReport(report)
+			assert.Equal(t, test.expectedOutput, buf.String())
+		})
+	}
+}
+
+func TestReportPrinter_PrintReport_Unlimited(t *testing.T) {
+	var issues []importing.ValidationIssue
+	for line := 2; line < 1002; line++ {
+		issues = append(issues, importing.ValidationIssue{Line: line})
+	}
+
+	var buf bytes.Buffer
+	p := reportPrinter{w: &buf}
+	p.printReport(importing.ValidationReport{Issues: issues})
+
+	assert.Equal(t, 1000, bytes.Count(buf.Bytes(), []byte("\n")))
+	assert.NotContains(t, buf.String(), "not shown")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/config"
+	"github.com/ztimes2/tolqin/app/api/migrations"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil/migrate"
+)
+
+// NewMigrate returns a root command of a tool for migrating the database schema.
+func NewMigrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tolqin-migrate",
+		Short: "A tool for migrating Tolqin database schema",
+	}
+
+	cmd.SetOut(os.Stdout)
+	cmd.SilenceErrors = true
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	addConfigFlag(cmd)
+
+	cmd.AddCommand(
+		newMigrateUpCmd(newPostgresMigrator),
+		newMigrateDownCmd(newPostgresMigrator),
+		newMigrateVersionCmd(newPostgresMigrator),
+	)
+
+	return cmd
+}
+
+func newPostgresMigrator(configPath string) (*migrate.Migrator, error) {
+	cfg, err := config.LoadDatabase(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load database config: %w", err)
+	}
+
+	// SQLite databases get their schema bootstrapped whenever they are opened.
+	if cfg.Driver != config.DatabaseDriverPostgres {
+		return nil, fmt.Errorf("migrations are not supported for %s", cfg.Driver)
+	}
+
+	db, err := newPostgresDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.New(db, migrations.FS, migrate.WithLogger(logrus.StandardLogger()))
+	if err != nil {
+		return nil, fmt.Errorf("could not load migrations: %w", err)
+	}
+
+	return m, nil
+}
+
+func newMigrateUpCmd(newMigrator func(configPath string) (*migrate.Migrator, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			m, err := newMigrator(configPath)
+			if err != nil {
+				return err
+			}
+
+			if err := m.Up(cmd.Context()); err != nil {
+				return fmt.Errorf("could not apply migrations: %w", err)
+			}
+
+			return printVersion(cmd, m)
+		},
+	}
+}
+
+func newMigrateDownCmd(newMigrator func(configPath string) (*migrate.Migrator, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "down",
+		Short: "Revert the most recently applied migration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			m, err := newMigrator(configPath)
+			if err != nil {
+				return err
+			}
+
+			if err := m.Down(cmd.Context()); err != nil {
+				if errors.Is(err, migrate.ErrNoChange) {
+					fmt.Fprintln(cmd.OutOrStdout(), "No migration to revert.")
+					return nil
+				}
+				return fmt.Errorf("could not revert migration: %w", err)
+			}
+
+			return printVersion(cmd, m)
+		},
+	}
+}
+
+func newMigrateVersionCmd(newMigrator func(configPath string) (*migrate.Migrator, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the current schema version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			m, err := newMigrator(configPath)
+			if err != nil {
+				return err
+			}
+
+			return printVersion(cmd, m)
+		},
+	}
+}
+
+func printVersion(cmd *cobra.Command, m *migrate.Migrator) error {
+	v, err := m.Version(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("could not read schema version: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Schema version: %d\n", v)
+	return nil
+}
@@ -16,8 +16,17 @@ func New() *cobra.Command {
 	cmd.SetOut(os.Stdout)
 	cmd.SilenceErrors = true
 	cmd.CompletionOptions.DisableDefaultCmd = true
+	addConfigFlag(cmd)
 
-	cmd.AddCommand(newImportCmd(newCSVSpotCreationEntrySource, newPostgresSpotStore, importing.ImportSpots))
+	cmd.AddCommand(newImportCmd(newCSVSpotCreationEntrySource, newSpotStore, importing.ImportSpots, importing.ValidateSpots))
 
 	return cmd
 }
+
+const flagConfig = "config"
+
+// addConfigFlag adds a flag holding a path of a YAML config file to the given
+// command and its subcommands.
+func addConfigFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(flagConfig, "", "Path to a YAML config file; environment variables override its values")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/seed"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/service/importing"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/csv"
+)
+
+// errNotEmpty is used when spots are about to be seeded into a store that already
+// has some.
+var errNotEmpty = errors.New("database already has spots: use --force to seed anyway")
+
+// NewSeed returns a root command of a tool for seeding the database with demo
+// spots.
+func NewSeed() *cobra.Command {
+	cmd := newSeedCmd(seed.FS, newSpotStore, importing.ImportSpots)
+
+	cmd.SetOut(os.Stdout)
+	cmd.SilenceErrors = true
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	addConfigFlag(cmd)
+
+	return cmd
+}
+
+func newSeedCmd(
+	fsys fs.FS,
+	storeFn func(configPath string) (spotStore, error),
+	importFn func(context.Context, surf.SpotCreationEntrySource, surf.MultiSpotWriter, importing.ImportParams) (importing.ImportResult, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tolqin-seed",
+		Short: "A tool for seeding Tolqin database with well-known surf spots",
+		Long: `A tool for seeding Tolqin database with well-known surf spots.
+
+Seeding is refused when the database already has spots, including soft-deleted
+ones, unless --force is passed. Seeded spots are matched by their external
+references, so seeding again updates them rather than creating duplicates.
+
+Environment variables:
+  - DATABASE_DRIVER (postgres or sqlite, defaults to postgres)
+  - DB_HOST
+  - DB_PORT
+  - DB_USERNAME
+  - DB_PASSWORD
+  - DB_NAME (a path of the database file for sqlite)
+  - DB_SSLMODE
+
+The variables can also be set in a YAML file passed via --config.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, err := cmd.Flags().GetBool("force")
+			if err != nil {
+				return err
+			}
+
+			configPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			dest, err := storeFn(configPath)
+			if err != nil {
+				return err
+			}
+
+			if !force {
+				spots, err := dest.Spots(cmd.Context(), surf.SpotsParams{
+					Limit:          1,
+					IncludeDeleted: true,
+				})
+				if err != nil {
+					return fmt.Errorf("could not check for existing spots: %w", err)
+				}
+				if len(spots) > 0 {
+					return errNotEmpty
+				}
+			}
+
+			f, err := fsys.Open(seed.SpotsFilename)
+			if err != nil {
+				return fmt.Errorf("could not open seed data: %w", err)
+			}
+
+			src := csv.NewSpotCreationEntrySource(f)
+			defer src.Close()
+
+			res, err := importFn(cmd.Context(), src, dest, importing.ImportParams{
+				Mode:      surf.SpotCreationModeUpsert,
+				BatchSize: dest.BatchSize(),
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"%d spot(s) were seeded! (inserted: %d, updated: %d)\n",
+				res.Inserted+res.Updated, res.Inserted, res.Updated,
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Seed spots even if the database already has some.")
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/seed"
+	"github.com/ztimes2/tolqin/app/api/internal/cli/service/importing"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/csv"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/sqlite"
+)
+
+func TestSeedData_IsValid(t *testing.T) {
+	f, err := seed.FS.Open(seed.SpotsFilename)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	report, err := importing.ValidateSpots(csv.NewSpotCreationEntrySource(f))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, report.Issues)
+	assert.Empty(t, report.Warnings)
+}
+
+func TestSeedCmd(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "tolqin.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	store := sqlite.NewSpotStore(db)
+
+	run := func(args ...string) (string, error) {
+		cmd := newSeedCmd(seed.FS, func(string) (spotStore, error) {
+			return store, nil
+		}, importing.ImportSpots)
+		addConfigFlag(cmd)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		cmd.SilenceUsage = true
+
+		err := cmd.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	out, err := run()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "25 spot(s) were seeded! (inserted: 25, updated: 0)\n", out)
+
+	_, err = run()
+	assert.ErrorIs(t, err, errNotEmpty)
+
+	out, err = run("--force")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "25 spot(s) were seeded! (inserted: 0, updated: 25)\n", out)
+
+	spots, err := store.Spots(context.Background(), surf.SpotsParams{Limit: 100})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 25)
+}
@@ -2,33 +2,84 @@ package config
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/heetch/confita"
 	"github.com/heetch/confita/backend"
 	"github.com/heetch/confita/backend/env"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
 	"github.com/ztimes2/tolqin/app/api/pkg/dotenv"
+	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
+	"github.com/ztimes2/tolqin/app/api/pkg/yamlenv"
+)
+
+const (
+	// DatabaseDriverPostgres connects to a PostgreSQL server.
+	DatabaseDriverPostgres = "postgres"
+
+	// DatabaseDriverSQLite opens an SQLite database file.
+	DatabaseDriverSQLite = "sqlite"
 )
 
 type Database struct {
-	Host     string `config:"DB_HOST,required"`
-	Port     string `config:"DB_PORT,required"`
+	// Driver selects the database: either postgres or sqlite. It defaults to
+	// postgres. Name is a path of the database file for sqlite.
+	Driver string `config:"DATABASE_DRIVER"`
+
+	// Host and Port are required for postgres.
+	Host     string `config:"DB_HOST"`
+	Port     string `config:"DB_PORT"`
 	Username string `config:"DB_USERNAME"`
 	Password string `config:"DB_PASSWORD"`
 	Name     string `config:"DB_NAME,required"`
 	SSLMode  string `config:"DB_SSLMODE"`
 }
 
-func LoadDatabase() (Database, error) {
-	var cfg Database
+// LoadDatabase loads database configuration from environment variables and a .env
+// file, and falls back to the YAML file at the given path unless it is empty.
+func LoadDatabase(path string) (Database, error) {
+	cfg := Database{
+		Driver: DatabaseDriverPostgres,
+	}
 
 	backends := []backend.Backend{
 		env.NewBackend(),
 		dotenv.NewBackend(),
 	}
 
+	if path != "" {
+		file, err := yamlenv.NewBackend(path)
+		if err != nil {
+			return Database{}, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		backends = append(backends, file)
+	}
+
 	if err := confita.NewLoader(backends...).Load(context.Background(), &cfg); err != nil {
 		return Database{}, err
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return Database{}, fmt.Errorf("invalid database config: %w", err)
+	}
+
 	return cfg, nil
 }
+
+// Validate checks values of the config and returns errors of all the keys that
+// failed validation at once as valerrautil.FieldErrors.
+func (d Database) Validate() error {
+	v := valerra.New()
+	v.IfFalse(
+		valerrautil.IsOneOf(d.Driver, DatabaseDriverPostgres, DatabaseDriverSQLite),
+		valerrautil.NewFieldError("DATABASE_DRIVER", "must be postgres or sqlite"),
+	)
+	v.IfFalse(valerra.StringNotEmpty(d.Name), valerrautil.NewFieldError("DB_NAME", "must not be empty"))
+
+	if d.Driver == DatabaseDriverPostgres {
+		v.IfFalse(valerra.StringNotEmpty(d.Host), valerrautil.NewFieldError("DB_HOST", "must not be empty for postgres"))
+		v.IfFalse(valerrautil.IsPort(d.Port), valerrautil.NewFieldError("DB_PORT", "must be a port number"))
+	}
+
+	return valerrautil.ValidateFields(v)
+}
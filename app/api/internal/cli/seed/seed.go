@@ -0,0 +1,15 @@
+// Package seed embeds a curated set of well-known surf spots that development
+// instances can be seeded with.
+package seed
+
+import "embed"
+
+// SpotsFilename is a name of the CSV file within FS that holds the spots. Every
+// spot has an external reference, so that seeding the same spots again updates
+// them rather than creating duplicates.
+const SpotsFilename = "spots.csv"
+
+// FS holds the spots in the same CSV format that the importer accepts.
+//
+//go:embed spots.csv
+var FS embed.FS
@@ -1,23 +1,396 @@
 package importing
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/valerrautil"
+	"github.com/ztimes2/tolqin/app/api/pkg/valerra"
 )
 
-func ImportSpots(src surf.SpotCreationEntrySource, dest surf.MultiSpotWriter) (int, error) {
-	entries, err := src.SpotCreationEntries()
+// DefaultBatchSize is a number of entries that are written to a destination at
+// once unless configured otherwise.
+const DefaultBatchSize = 100
+
+// Tag limits mirror the ones enforced by the API when tags of a spot are set.
+const (
+	maxTags     = 10
+	maxTagChars = 30
+)
+
+// ImportParams holds parameters for importing spots.
+type ImportParams struct {
+	Mode surf.SpotCreationMode
+
+	// SkipInvalid can be optionally used to skip invalid entries instead of
+	// failing the whole import.
+	SkipInvalid bool
+
+	// BatchSize is a number of entries that are written to the destination at
+	// once. DefaultBatchSize is used when it is not set.
+	BatchSize int
+}
+
+// ImportResult holds results of importing spots.
+type ImportResult struct {
+	surf.SpotCreationStats
+
+	// Skipped holds a validation report of the entries that were skipped.
+	Skipped ValidationReport
+
+	// Warnings holds issues of the imported entries that look suspicious but
+	// did not prevent them from being imported.
+	Warnings []ValidationIssue
+}
+
+// ImportSpots streams spot entries from the given source, validates them, and
+// creates them in the given destination in batches, so that the whole source is
+// never held in memory.
+//
+// *InvalidEntriesError is returned when at least one of the entries is invalid,
+// unless invalid entries are asked to be skipped. Since entries are streamed, the
+// batches that precede the first invalid entry might have already been written
+// by then, so ValidateSpots should be used beforehand in order to avoid partial
+// imports.
+//
+// *InterruptedError is returned when the given context gets canceled. Each batch
+// is written atomically, so the batch that was being written at the time either
+// finishes or is rolled back, and the error holds the result of the batches that
+// had been written before.
+func ImportSpots(ctx context.Context, src surf.SpotCreationEntrySource, dest surf.MultiSpotWriter, p ImportParams) (ImportResult, error) {
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var (
+		res     ImportResult
+		report  ValidationReport
+		entries = make([]surf.SpotCreationEntry, 0, batchSize)
+	)
+
+	flush := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+
+		stats, err := dest.CreateSpots(ctx, entries, p.Mode)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("could not create spots in the destination: %w", err)
+		}
+
+		res.Inserted += stats.Inserted
+		res.Updated += stats.Updated
+		entries = make([]surf.SpotCreationEntry, 0, batchSize)
+		return nil
+	}
+
+	err := iterateEntries(src, &report, func(line int, e surf.SpotCreationEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if issues := validateEntry(line, e); len(issues) > 0 {
+			report.Issues = append(report.Issues, issues...)
+			return nil
+		}
+
+		// Keep validating the rest of the entries for the sake of a complete
+		// report, but stop writing them once an invalid one is found.
+		if !report.IsValid() && !p.SkipInvalid {
+			return nil
+		}
+
+		res.Warnings = append(res.Warnings, checkEntry(line, e)...)
+
+		entries = append(entries, e)
+		if len(entries) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return ImportResult{}, interruptedOr(err, res)
+	}
+
+	if !report.IsValid() && !p.SkipInvalid {
+		return ImportResult{}, &InvalidEntriesError{
+			Report: report,
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ImportResult{}, interruptedOr(err, res)
+	}
+
+	if err := flush(); err != nil {
+		return ImportResult{}, interruptedOr(err, res)
+	}
+
+	res.Skipped = report
+	return res, nil
+}
+
+// InterruptedError is used when an import gets interrupted by cancellation of its
+// context.
+type InterruptedError struct {
+	// Result holds the result of the batches that had been written before the
+	// import was interrupted.
+	Result ImportResult
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("import interrupted: %s", e.Err)
+}
+
+// Unwrap returns the underlying context error.
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// interruptedOr wraps the given error into *InterruptedError along with the given
+// result if it is caused by context cancellation, and returns it as is otherwise.
+func interruptedOr(err error, res ImportResult) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &InterruptedError{
+			Result: res,
+			Err:    err,
+		}
+	}
+	return err
+}
+
+// ValidateSpots streams spot entries from the given source and validates them
+// using the same rules that are applied during import without creating them.
+func ValidateSpots(src surf.SpotCreationEntrySource) (ValidationReport, error) {
+	var report ValidationReport
+
+	err := iterateEntries(src, &report, func(line int, e surf.SpotCreationEntry) error {
+		if issues := validateEntry(line, e); len(issues) > 0 {
+			report.Issues = append(report.Issues, issues...)
+			return nil
+		}
+
+		report.Warnings = append(report.Warnings, checkEntry(line, e)...)
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("could not read spot entries from source: %w", err)
+		return ValidationReport{}, err
+	}
+
+	return report, nil
+}
+
+// lineSource is implemented by sources that know which line of their input the
+// last fetched entry is on.
+type lineSource interface {
+	Line() int
+}
+
+// iterateEntries reads sanitized entries from the given source one by one until
+// the source is exhausted, and calls the given function for each of them along
+// with its line number. Entries that the source fails to parse are not passed to
+// the function, and their issues are added to the given report instead.
+//
+// Line numbers are taken from the source if it implements lineSource, so that they
+// account for a header. Otherwise, entries are numbered starting from 1.
+func iterateEntries(
+	src surf.SpotCreationEntrySource,
+	report *ValidationReport,
+	fn func(line int, e surf.SpotCreationEntry) error) error {
+
+	for n := 1; ; n++ {
+		e, err := src.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		line := n
+		if ls, ok := src.(lineSource); ok {
+			line = ls.Line()
+		}
+
+		if err != nil {
+			var malformedErr *surf.MalformedSpotCreationEntryError
+			if errors.As(err, &malformedErr) {
+				for _, f := range malformedErr.Fields {
+					report.Issues = append(report.Issues, ValidationIssue{
+						Line: line, Field: f.Name, Reason: fmt.Sprintf("could not be parsed: %s", f.Err),
+					})
+				}
+				continue
+			}
+
+			return fmt.Errorf("could not read spot entries from source: %w", err)
+		}
+
+		if err := fn(line, sanitizeEntry(e)); err != nil {
+			return err
+		}
 	}
+}
+
+// InvalidEntriesError is used when spot entries fail validation.
+type InvalidEntriesError struct {
+	Report ValidationReport
+}
+
+// Error implements the error interface.
+func (e *InvalidEntriesError) Error() string {
+	return fmt.Sprintf("%d invalid row(s) found", len(e.Report.InvalidLines()))
+}
 
-	// TODO sanitize each entry
-	// TODO validate each entry
+// ValidationReport holds issues found during validation of spot entries.
+type ValidationReport struct {
+	Issues []ValidationIssue
 
-	if err := dest.CreateSpots(entries); err != nil {
-		return 0, fmt.Errorf("could not create spots in the destination: %w", err)
+	// Warnings holds issues of valid entries that look suspicious. They do not
+	// make the entries invalid.
+	Warnings []ValidationIssue
+}
+
+// IsValid checks whether the report contains no issues. Warnings are not taken
+// into account.
+func (r ValidationReport) IsValid() bool {
+	return len(r.Issues) == 0
+}
+
+// InvalidLines returns numbers of the lines whose entries have at least one issue
+// in ascending order.
+func (r ValidationReport) InvalidLines() []int {
+	var (
+		lines []int
+		seen  = make(map[int]struct{})
+	)
+	for _, i := range r.Issues {
+		if _, ok := seen[i.Line]; ok {
+			continue
+		}
+		seen[i.Line] = struct{}{}
+		lines = append(lines, i.Line)
 	}
+	return lines
+}
 
-	return len(entries), nil
+// ValidationIssue holds details of a field of a spot entry that failed validation.
+type ValidationIssue struct {
+	// Line is a number of the line within the source that the entry is on,
+	// including a header if the source has one. For example, the 1st entry of a
+	// CSV file is on line 2.
+	Line   int
+	Field  string
+	Reason string
+}
+
+// Error implements the error interface.
+func (i ValidationIssue) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Field, i.Reason)
+}
+
+func sanitizeEntry(e surf.SpotCreationEntry) surf.SpotCreationEntry {
+	e.Name = strings.TrimSpace(e.Name)
+	e.ExternalRef = strings.TrimSpace(e.ExternalRef)
+	e.Location.Locality = strings.TrimSpace(e.Location.Locality)
+	e.Location.CountryCode = strings.ToLower(strings.TrimSpace(e.Location.CountryCode))
+	e.Tags = sanitizeTags(e.Tags)
+	return e
+}
+
+// sanitizeTags trims and lowercases the given tags, and drops empty and duplicate
+// ones while preserving their order.
+func sanitizeTags(tags []string) []string {
+	var (
+		sanitized []string
+		seen      = make(map[string]struct{}, len(tags))
+	)
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		sanitized = append(sanitized, t)
+	}
+	return sanitized
+}
+
+func validateEntry(line int, e surf.SpotCreationEntry) []ValidationIssue {
+	v := valerra.New()
+
+	v.IfFalse(valerra.StringNotEmpty(e.Name), ValidationIssue{
+		Line: line, Field: "name", Reason: "must be a non empty string",
+	})
+	v.IfFalse(valerrautil.IsLatitude(e.Location.Coordinates.Latitude), ValidationIssue{
+		Line: line, Field: "latitude", Reason: "must be a valid latitude",
+	})
+	v.IfFalse(valerrautil.IsLongitude(e.Location.Coordinates.Longitude), ValidationIssue{
+		Line: line, Field: "longitude", Reason: "must be a valid longitude",
+	})
+	v.IfFalse(valerra.StringNotEmpty(e.Location.Locality), ValidationIssue{
+		Line: line, Field: "locality", Reason: "must be a non empty string",
+	})
+	v.IfFalse(valerrautil.IsCountry(e.Location.CountryCode), ValidationIssue{
+		Line: line, Field: "country_code", Reason: "must be a valid ISO-2 country code",
+	})
+	v.IfFalse(func() bool { return len(e.Tags) <= maxTags }, ValidationIssue{
+		Line: line, Field: "tags", Reason: fmt.Sprintf("must contain at most %d tags", maxTags),
+	})
+	v.IfFalse(areValidTagLengths(e.Tags), ValidationIssue{
+		Line: line, Field: "tags", Reason: fmt.Sprintf("must contain tags of at most %d characters", maxTagChars),
+	})
+
+	err := v.Validate()
+	if err == nil {
+		return nil
+	}
+
+	errs := err.(*valerra.Errors).Errors()
+	issues := make([]ValidationIssue, len(errs))
+	for i, e := range errs {
+		issues[i] = e.(ValidationIssue)
+	}
+	return issues
+}
+
+func areValidTagLengths(tags []string) valerra.Condition {
+	return func() bool {
+		for _, t := range tags {
+			if !valerra.StringLessOrEqual(t, maxTagChars)() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// checkEntry looks for signs of data-entry mistakes in a valid entry, such as the
+// same value being pasted into both coordinates, and returns them as warnings.
+func checkEntry(line int, e surf.SpotCreationEntry) []ValidationIssue {
+	lat, lon := e.Location.Coordinates.Latitude, e.Location.Coordinates.Longitude
+
+	switch {
+	case lat == lon:
+		return []ValidationIssue{{
+			Line: line, Field: "coordinates", Reason: "latitude is equal to longitude",
+		}}
+	case math.Abs(lat) == math.Abs(lon):
+		return []ValidationIssue{{
+			Line: line, Field: "coordinates", Reason: "latitude and longitude differ only in sign",
+		}}
+	default:
+		return nil
+	}
 }
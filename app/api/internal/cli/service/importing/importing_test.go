@@ -0,0 +1,496 @@
+package importing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/csv"
+)
+
+type mockMultiSpotWriter struct {
+	mock.Mock
+}
+
+func newMockMultiSpotWriter() *mockMultiSpotWriter {
+	return &mockMultiSpotWriter{}
+}
+
+func (m *mockMultiSpotWriter) CreateSpots(
+	ctx context.Context, entries []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+
+	args := m.Called(ctx, entries, mode)
+	return args.Get(0).(surf.SpotCreationStats), args.Error(1)
+}
+
+func newMixedSource(t *testing.T) surf.SpotCreationEntrySource {
+	f, err := os.Open("testdata/mixed.csv")
+	if err != nil {
+		t.Fatalf("could not open csv file: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	return csv.NewSpotCreationEntrySource(f)
+}
+
+var mixedIssues = []ValidationIssue{
+	{Line: 3, Field: "name", Reason: "must be a non empty string"},
+	{Line: 5, Field: "latitude", Reason: "must be a valid latitude"},
+	{Line: 5, Field: "locality", Reason: "must be a non empty string"},
+	{Line: 5, Field: "country_code", Reason: "must be a valid ISO-2 country code"},
+}
+
+var mixedValidEntries = []surf.SpotCreationEntry{
+	{
+		Name: "Abrolhos Islands",
+		Location: geo.Location{
+			Locality:    "City Of Greater Geraldton",
+			CountryCode: "au",
+			Coordinates: geo.Coordinates{
+				Latitude:  -28.92683,
+				Longitude: 113.97929,
+			},
+		},
+	},
+	{
+		Name: "Cables",
+		Location: geo.Location{
+			Locality:    "Town of Mosman Park",
+			CountryCode: "au",
+			Coordinates: geo.Coordinates{
+				Latitude:  -32.01783,
+				Longitude: 115.7512,
+			},
+		},
+	},
+}
+
+func TestValidateSpots(t *testing.T) {
+	report, err := ValidateSpots(newMixedSource(t))
+	assert.NoError(t, err)
+	assert.False(t, report.IsValid())
+	assert.Equal(t, mixedIssues, report.Issues)
+	assert.Equal(t, []int{3, 5}, report.InvalidLines())
+}
+
+func newSuspiciousSource(t *testing.T) surf.SpotCreationEntrySource {
+	f, err := os.Open("testdata/suspicious.csv")
+	if err != nil {
+		t.Fatalf("could not open csv file: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	return csv.NewSpotCreationEntrySource(f)
+}
+
+var suspiciousWarnings = []ValidationIssue{
+	{Line: 3, Field: "coordinates", Reason: "latitude is equal to longitude"},
+	{Line: 4, Field: "coordinates", Reason: "latitude and longitude differ only in sign"},
+}
+
+func TestValidateSpots_Warnings(t *testing.T) {
+	t.Run("report warnings for suspicious entries", func(t *testing.T) {
+		report, err := ValidateSpots(newSuspiciousSource(t))
+		assert.NoError(t, err)
+		assert.True(t, report.IsValid())
+		assert.Equal(t, suspiciousWarnings, report.Warnings)
+	})
+
+	t.Run("report no warnings for normal entries", func(t *testing.T) {
+		report, err := ValidateSpots(newMixedSource(t))
+		assert.NoError(t, err)
+		assert.Empty(t, report.Warnings)
+	})
+}
+
+func TestImportSpots_Warnings(t *testing.T) {
+	w := newMockMultiSpotWriter()
+	w.
+		On("CreateSpots", mock.Anything, mock.Anything, surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{Inserted: 4}, nil)
+
+	res, err := ImportSpots(context.Background(), newSuspiciousSource(t), w, ImportParams{
+		Mode: surf.SpotCreationModeInsert,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ImportResult{
+		SpotCreationStats: surf.SpotCreationStats{
+			Inserted: 4,
+		},
+		Warnings: suspiciousWarnings,
+	}, res)
+}
+
+func TestImportSpots_HeaderOnly(t *testing.T) {
+	w := newMockMultiSpotWriter()
+
+	res, err := ImportSpots(
+		context.Background(),
+		csv.NewSpotCreationEntrySource(newGeneratedCSVReader(0)),
+		w,
+		ImportParams{
+			Mode: surf.SpotCreationModeInsert,
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, ImportResult{}, res)
+	w.AssertNotCalled(t, "CreateSpots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImportSpots(t *testing.T) {
+	tests := []struct {
+		name           string
+		writer         func() surf.MultiSpotWriter
+		params         ImportParams
+		expectedResult ImportResult
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for invalid entries",
+			writer: func() surf.MultiSpotWriter {
+				return newMockMultiSpotWriter()
+			},
+			params: ImportParams{
+				Mode: surf.SpotCreationModeInsert,
+			},
+			expectedResult: ImportResult{},
+			expectedErrFn: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var invalidErr *InvalidEntriesError
+				return assert.True(t, errors.As(err, &invalidErr)) &&
+					assert.Equal(t, mixedIssues, invalidErr.Report.Issues)
+			},
+		},
+		{
+			name: "return error during writer failure",
+			writer: func() surf.MultiSpotWriter {
+				m := newMockMultiSpotWriter()
+				m.
+					On("CreateSpots", mock.Anything, mixedValidEntries, surf.SpotCreationModeInsert).
+					Return(surf.SpotCreationStats{}, errors.New("something went wrong"))
+				return m
+			},
+			params: ImportParams{
+				Mode:        surf.SpotCreationModeInsert,
+				SkipInvalid: true,
+			},
+			expectedResult: ImportResult{},
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "skip invalid entries and import valid ones",
+			writer: func() surf.MultiSpotWriter {
+				m := newMockMultiSpotWriter()
+				m.
+					On("CreateSpots", mock.Anything, mixedValidEntries, surf.SpotCreationModeUpsert).
+					Return(surf.SpotCreationStats{Inserted: 1, Updated: 1}, nil)
+				return m
+			},
+			params: ImportParams{
+				Mode:        surf.SpotCreationModeUpsert,
+				SkipInvalid: true,
+			},
+			expectedResult: ImportResult{
+				SpotCreationStats: surf.SpotCreationStats{
+					Inserted: 1,
+					Updated:  1,
+				},
+				Skipped: ValidationReport{
+					Issues: mixedIssues,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := ImportSpots(context.Background(), newMixedSource(t), test.writer(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResult, res)
+		})
+	}
+}
+
+// generatedCSVReader produces a CSV with the given number of valid rows on the fly
+// without holding all of them in memory.
+type generatedCSVReader struct {
+	rows   int
+	row    int
+	buffer bytes.Buffer
+}
+
+func newGeneratedCSVReader(rows int) *generatedCSVReader {
+	r := &generatedCSVReader{
+		rows: rows,
+	}
+	r.buffer.WriteString("name,latitude,longitude,locality,country_code\n")
+	return r
+}
+
+func (r *generatedCSVReader) Read(b []byte) (int, error) {
+	for r.buffer.Len() < len(b) && r.row < r.rows {
+		r.row++
+		fmt.Fprintf(&r.buffer, "Spot %d,1.23,3.21,Locality %d,kz\n", r.row, r.row)
+	}
+	if r.buffer.Len() == 0 {
+		return 0, io.EOF
+	}
+	return r.buffer.Read(b)
+}
+
+// countingMultiSpotWriter records sizes of the batches it receives without
+// retaining the entries.
+type countingMultiSpotWriter struct {
+	batchSizes []int
+}
+
+func (w *countingMultiSpotWriter) CreateSpots(
+	ctx context.Context, entries []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+
+	w.batchSizes = append(w.batchSizes, len(entries))
+	return surf.SpotCreationStats{Inserted: len(entries)}, nil
+}
+
+func TestImportSpots_Streaming(t *testing.T) {
+	const (
+		rows      = 100000
+		batchSize = 300
+	)
+
+	w := &countingMultiSpotWriter{}
+	res, err := ImportSpots(
+		context.Background(),
+		csv.NewSpotCreationEntrySource(newGeneratedCSVReader(rows)),
+		w,
+		ImportParams{
+			Mode:      surf.SpotCreationModeInsert,
+			BatchSize: batchSize,
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, res.Inserted)
+
+	assert.Len(t, w.batchSizes, rows/batchSize+1)
+	for _, size := range w.batchSizes[:len(w.batchSizes)-1] {
+		assert.Equal(t, batchSize, size)
+	}
+	assert.Equal(t, rows%batchSize, w.batchSizes[len(w.batchSizes)-1])
+}
+
+func TestImportSpots_SliceSource(t *testing.T) {
+	w := newMockMultiSpotWriter()
+	w.
+		On("CreateSpots", mock.Anything, mixedValidEntries[:1], surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{Inserted: 1}, nil).
+		Once()
+	w.
+		On("CreateSpots", mock.Anything, mixedValidEntries[1:], surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{Inserted: 1}, nil).
+		Once()
+
+	res, err := ImportSpots(
+		context.Background(),
+		surf.NewSliceSpotCreationEntrySource(mixedValidEntries),
+		w,
+		ImportParams{
+			Mode:      surf.SpotCreationModeInsert,
+			BatchSize: 1,
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Inserted)
+	w.AssertExpectations(t)
+}
+
+func newMalformedSource(t *testing.T) surf.SpotCreationEntrySource {
+	f, err := os.Open("testdata/malformed.csv")
+	if err != nil {
+		t.Fatalf("could not open csv file: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	return csv.NewSpotCreationEntrySource(f)
+}
+
+var malformedIssues = []ValidationIssue{
+	{Line: 3, Field: "latitude", Reason: `could not be parsed: strconv.ParseFloat: parsing "north": invalid syntax`},
+	{Line: 3, Field: "longitude", Reason: `could not be parsed: strconv.ParseFloat: parsing "west": invalid syntax`},
+	{Line: 4, Field: "record", Reason: "could not be parsed: must contain from 5 to 7 fields"},
+}
+
+func TestValidateSpots_Malformed(t *testing.T) {
+	report, err := ValidateSpots(newMalformedSource(t))
+	assert.NoError(t, err)
+	assert.Equal(t, malformedIssues, report.Issues)
+	assert.Equal(t, []int{3, 4}, report.InvalidLines())
+}
+
+func TestImportSpots_Malformed(t *testing.T) {
+	t.Run("return error for malformed entries", func(t *testing.T) {
+		w := newMockMultiSpotWriter()
+
+		res, err := ImportSpots(context.Background(), newMalformedSource(t), w, ImportParams{
+			Mode: surf.SpotCreationModeInsert,
+		})
+
+		var invalidErr *InvalidEntriesError
+		assert.True(t, errors.As(err, &invalidErr))
+		assert.Equal(t, malformedIssues, invalidErr.Report.Issues)
+		assert.Equal(t, ImportResult{}, res)
+	})
+
+	t.Run("skip malformed entries and import valid ones", func(t *testing.T) {
+		w := newMockMultiSpotWriter()
+		w.
+			On("CreateSpots", mock.Anything, mixedValidEntries, surf.SpotCreationModeInsert).
+			Return(surf.SpotCreationStats{Inserted: 2}, nil)
+
+		res, err := ImportSpots(context.Background(), newMalformedSource(t), w, ImportParams{
+			Mode:        surf.SpotCreationModeInsert,
+			SkipInvalid: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, ImportResult{
+			SpotCreationStats: surf.SpotCreationStats{
+				Inserted: 2,
+			},
+			Skipped: ValidationReport{
+				Issues: malformedIssues,
+			},
+		}, res)
+	})
+}
+
+func newTagsSource(t *testing.T) surf.SpotCreationEntrySource {
+	f, err := os.Open("testdata/tags.csv")
+	if err != nil {
+		t.Fatalf("could not open csv file: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	return csv.NewSpotCreationEntrySource(f)
+}
+
+var tagsIssues = []ValidationIssue{
+	{Line: 3, Field: "tags", Reason: "must contain at most 10 tags"},
+	{Line: 4, Field: "tags", Reason: "must contain tags of at most 30 characters"},
+}
+
+func TestValidateSpots_Tags(t *testing.T) {
+	report, err := ValidateSpots(newTagsSource(t))
+	assert.NoError(t, err)
+	assert.Equal(t, tagsIssues, report.Issues)
+	assert.Equal(t, []int{3, 4}, report.InvalidLines())
+}
+
+func TestImportSpots_Tags(t *testing.T) {
+	w := newMockMultiSpotWriter()
+	w.
+		On("CreateSpots", mock.Anything, []surf.SpotCreationEntry{
+			{
+				Name:        "Abrolhos Islands",
+				ExternalRef: "au-1",
+				Tags:        []string{"reef", "left hand"},
+				Location: geo.Location{
+					Locality:    "City Of Greater Geraldton",
+					CountryCode: "au",
+					Coordinates: geo.Coordinates{
+						Latitude:  -28.92683,
+						Longitude: 113.97929,
+					},
+				},
+			},
+		}, surf.SpotCreationModeUpsert).
+		Return(surf.SpotCreationStats{Inserted: 1}, nil)
+
+	res, err := ImportSpots(context.Background(), newTagsSource(t), w, ImportParams{
+		Mode:        surf.SpotCreationModeUpsert,
+		SkipInvalid: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Inserted)
+	assert.Equal(t, tagsIssues, res.Skipped.Issues)
+	w.AssertExpectations(t)
+}
+
+func TestImportSpots_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := newMockMultiSpotWriter()
+	w.
+		On("CreateSpots", mock.Anything, mixedValidEntries[:1], surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{Inserted: 1}, nil).
+		Run(func(args mock.Arguments) {
+			cancel()
+		}).
+		Once()
+
+	res, err := ImportSpots(
+		ctx,
+		surf.NewSliceSpotCreationEntrySource(mixedValidEntries),
+		w,
+		ImportParams{
+			Mode:      surf.SpotCreationModeInsert,
+			BatchSize: 1,
+		},
+	)
+	assert.Equal(t, ImportResult{}, res)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var interruptedErr *InterruptedError
+	if !assert.ErrorAs(t, err, &interruptedErr) {
+		return
+	}
+	assert.Equal(t, 1, interruptedErr.Result.Inserted)
+	w.AssertExpectations(t)
+}
+
+func TestImportSpots_InterruptedDuringBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := newMockMultiSpotWriter()
+	w.
+		On("CreateSpots", mock.Anything, mixedValidEntries[:1], surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{Inserted: 1}, nil).
+		Once()
+	w.
+		On("CreateSpots", mock.Anything, mixedValidEntries[1:], surf.SpotCreationModeInsert).
+		Return(surf.SpotCreationStats{}, errors.New("transaction rolled back")).
+		Run(func(args mock.Arguments) {
+			cancel()
+		}).
+		Once()
+
+	_, err := ImportSpots(
+		ctx,
+		surf.NewSliceSpotCreationEntrySource(mixedValidEntries),
+		w,
+		ImportParams{
+			Mode:      surf.SpotCreationModeInsert,
+			BatchSize: 1,
+		},
+	)
+
+	var interruptedErr *InterruptedError
+	if !assert.ErrorAs(t, err, &interruptedErr) {
+		return
+	}
+	assert.Equal(t, 1, interruptedErr.Result.Inserted)
+	w.AssertExpectations(t)
+}
@@ -0,0 +1,93 @@
+package importing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// WriteValidationReportCSV writes the given report to the given writer as CSV
+// records, one per issue, preceded by a header. Issues that made rows invalid
+// come first, followed by warnings.
+func WriteValidationReportCSV(w io.Writer, r ValidationReport) error {
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write([]string{"line", "severity", "field", "reason"}); err != nil {
+		return fmt.Errorf("could not write csv header: %w", err)
+	}
+
+	write := func(severity string, issues []ValidationIssue) error {
+		for _, i := range issues {
+			record := []string{strconv.Itoa(i.Line), severity, i.Field, i.Reason}
+			if err := csvWriter.Write(record); err != nil {
+				return fmt.Errorf("could not write csv record: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := write(severityError, r.Issues); err != nil {
+		return err
+	}
+	if err := write(severityWarning, r.Warnings); err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return fmt.Errorf("could not flush csv: %w", err)
+	}
+	return nil
+}
+
+type validationReportJSON struct {
+	InvalidLines []int                 `json:"invalid_lines"`
+	Issues       []validationIssueJSON `json:"issues"`
+	Warnings     []validationIssueJSON `json:"warnings"`
+}
+
+type validationIssueJSON struct {
+	Line   int    `json:"line"`
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func toValidationIssuesJSON(issues []ValidationIssue) []validationIssueJSON {
+	items := make([]validationIssueJSON, len(issues))
+	for i, issue := range issues {
+		items[i] = validationIssueJSON{
+			Line:   issue.Line,
+			Field:  issue.Field,
+			Reason: issue.Reason,
+		}
+	}
+	return items
+}
+
+// WriteValidationReportJSON writes the given report to the given writer as a JSON
+// object holding numbers of the invalid lines, issues, and warnings.
+func WriteValidationReportJSON(w io.Writer, r ValidationReport) error {
+	invalidLines := r.InvalidLines()
+	if invalidLines == nil {
+		invalidLines = []int{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(validationReportJSON{
+		InvalidLines: invalidLines,
+		Issues:       toValidationIssuesJSON(r.Issues),
+		Warnings:     toValidationIssuesJSON(r.Warnings),
+	}); err != nil {
+		return fmt.Errorf("could not encode json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package importing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteValidationReportCSV(t *testing.T) {
+	report, err := ValidateSpots(newMixedSource(t))
+	assert.NoError(t, err)
+	report.Warnings = suspiciousWarnings
+
+	var buf bytes.Buffer
+	err = WriteValidationReportCSV(&buf, report)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"line,severity,field,reason\n"+
+			"3,error,name,must be a non empty string\n"+
+			"5,error,latitude,must be a valid latitude\n"+
+			"5,error,locality,must be a non empty string\n"+
+			"5,error,country_code,must be a valid ISO-2 country code\n"+
+			"3,warning,coordinates,latitude is equal to longitude\n"+
+			"4,warning,coordinates,latitude and longitude differ only in sign\n",
+		buf.String(),
+	)
+}
+
+func TestWriteValidationReportJSON(t *testing.T) {
+	t.Run("write report with invalid lines", func(t *testing.T) {
+		report, err := ValidateSpots(newMixedSource(t))
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		err = WriteValidationReportJSON(&buf, report)
+		assert.NoError(t, err)
+		assert.JSONEq(
+			t,
+			`{
+				"invalid_lines": [3, 5],
+				"issues": [
+					{
+						"line": 3,
+						"field": "name",
+						"reason": "must be a non empty string"
+					},
+					{
+						"line": 5,
+						"field": "latitude",
+						"reason": "must be a valid latitude"
+					},
+					{
+						"line": 5,
+						"field": "locality",
+						"reason": "must be a non empty string"
+					},
+					{
+						"line": 5,
+						"field": "country_code",
+						"reason": "must be a valid ISO-2 country code"
+					}
+				],
+				"warnings": []
+			}`,
+			buf.String(),
+		)
+	})
+
+	t.Run("write empty report", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := WriteValidationReportJSON(&buf, ValidationReport{})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"invalid_lines": [], "issues": [], "warnings": []}`, buf.String())
+	})
+}
@@ -0,0 +1,134 @@
+// Package eventbus provides publishing events about changes of spots to
+// downstream consumers.
+package eventbus
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+// EventType is a type of an event.
+type EventType string
+
+const (
+	EventSpotCreated EventType = "spot.created"
+	EventSpotUpdated EventType = "spot.updated"
+	EventSpotDeleted EventType = "spot.deleted"
+)
+
+// Event describes a change of a spot.
+type Event struct {
+	Type EventType
+
+	// ActorID is an ID of the user who made the change.
+	ActorID string
+
+	// Spot holds a snapshot of the spot after the change. Only its ID is known for
+	// deleted spots.
+	Spot surf.Spot
+
+	OccurredAt time.Time
+}
+
+type eventPayload struct {
+	Type       EventType   `json:"type"`
+	ActorID    string      `json:"actor_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Spot       spotPayload `json:"spot"`
+}
+
+type spotPayload struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name,omitempty"`
+	Latitude    float64    `json:"latitude"`
+	Longitude   float64    `json:"longitude"`
+	Locality    string     `json:"locality,omitempty"`
+	CountryCode string     `json:"country_code,omitempty"`
+	Region      string     `json:"region,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface. Events are encoded the same
+// way regardless of where they are published to.
+func (e Event) MarshalJSON() ([]byte, error) {
+	p := eventPayload{
+		Type:       e.Type,
+		ActorID:    e.ActorID,
+		OccurredAt: e.OccurredAt.UTC(),
+		Spot: spotPayload{
+			ID:          e.Spot.ID,
+			Name:        e.Spot.Name,
+			Latitude:    e.Spot.Location.Coordinates.Latitude,
+			Longitude:   e.Spot.Location.Coordinates.Longitude,
+			Locality:    e.Spot.Location.Locality,
+			CountryCode: e.Spot.Location.CountryCode,
+			Region:      e.Spot.Location.Region,
+			Tags:        e.Spot.Tags,
+			DeletedAt:   e.Spot.DeletedAt,
+		},
+	}
+	if !e.Spot.CreatedAt.IsZero() {
+		p.Spot.CreatedAt = &e.Spot.CreatedAt
+	}
+	if !e.Spot.UpdatedAt.IsZero() {
+		p.Spot.UpdatedAt = &e.Spot.UpdatedAt
+	}
+	return json.Marshal(p)
+}
+
+// Publisher is anything that can publish events. Publishing must not block, and
+// failures must be handled by the publisher itself.
+type Publisher interface {
+	Publish(Event)
+}
+
+// Fanout returns a Publisher that publishes every event to all of the given
+// publishers.
+func Fanout(ps ...Publisher) Publisher {
+	return fanout(ps)
+}
+
+type fanout []Publisher
+
+// Publish implements Publisher interface.
+func (f fanout) Publish(e Event) {
+	for _, p := range f {
+		p.Publish(e)
+	}
+}
+
+// Memory is a Publisher that keeps published events in memory, which is useful
+// for tests.
+type Memory struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemory returns a new *Memory.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish implements Publisher interface.
+func (m *Memory) Publish(e Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, e)
+}
+
+// Events returns the published events in the order they were published.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := make([]Event, len(m.events))
+	copy(events, m.events)
+	return events
+}
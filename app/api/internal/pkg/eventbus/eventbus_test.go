@@ -0,0 +1,103 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestEvent_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        Event
+		expectedJSON string
+	}{
+		{
+			name: "return json of event with deleted spot",
+			event: Event{
+				Type:       EventSpotDeleted,
+				ActorID:    "42",
+				Spot:       surf.Spot{ID: "1"},
+				OccurredAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.FixedZone("UTC+5", 5*60*60)),
+			},
+			expectedJSON: `{
+				"type": "spot.deleted",
+				"actor_id": "42",
+				"occurred_at": "2020-12-31T20:01:01Z",
+				"spot": {
+					"id": "1",
+					"latitude": 0,
+					"longitude": 0
+				}
+			}`,
+		},
+		{
+			name: "return json of event with spot snapshot",
+			event: Event{
+				Type:    EventSpotUpdated,
+				ActorID: "42",
+				Spot: surf.Spot{
+					ID:   "1",
+					Name: "Spot 1",
+					Location: geo.Location{
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+						Locality:    "Aktau",
+						CountryCode: "kz",
+						Region:      "Mangystau",
+					},
+					Tags:      []string{"reef"},
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+				},
+				OccurredAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+			},
+			expectedJSON: `{
+				"type": "spot.updated",
+				"actor_id": "42",
+				"occurred_at": "2021-01-02T01:01:01Z",
+				"spot": {
+					"id": "1",
+					"name": "Spot 1",
+					"latitude": 1.23,
+					"longitude": 3.21,
+					"locality": "Aktau",
+					"country_code": "kz",
+					"region": "Mangystau",
+					"tags": ["reef"],
+					"created_at": "2021-01-01T01:01:01Z",
+					"updated_at": "2021-01-02T01:01:01Z"
+				}
+			}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.event)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedJSON, string(b))
+		})
+	}
+}
+
+func TestFanout(t *testing.T) {
+	m1, m2 := NewMemory(), NewMemory()
+
+	p := Fanout(m1, m2)
+	p.Publish(Event{Type: EventSpotCreated})
+	p.Publish(Event{Type: EventSpotDeleted})
+
+	expected := []Event{
+		{Type: EventSpotCreated},
+		{Type: EventSpotDeleted},
+	}
+	assert.Equal(t, expected, m1.Events())
+	assert.Equal(t, expected, m2.Events())
+}
@@ -0,0 +1,236 @@
+package nats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+type message struct {
+	subject string
+	body    string
+}
+
+// fakeServer is a NATS server that accepts published messages and records them
+// along with options that clients connect with.
+type fakeServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	connects []string
+	messages []message
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeServer{
+		listener: l,
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeServer) url() string {
+	return "nats://user:secret@" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte("INFO {\"server_id\":\"fake\"}\r\n"))
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			s.mu.Lock()
+			s.connects = append(s.connects, strings.TrimPrefix(line, "CONNECT "))
+			s.mu.Unlock()
+		case strings.HasPrefix(line, "PUB "):
+			parts := strings.Fields(line)
+			size, _ := strconv.Atoi(parts[2])
+
+			body := make([]byte, size+2)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.messages = append(s.messages, message{
+				subject: parts[1],
+				body:    string(body[:size]),
+			})
+			s.mu.Unlock()
+		case line == "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		}
+	}
+}
+
+func (s *fakeServer) close() {
+	s.listener.Close()
+}
+
+func TestPublisher(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.close()
+
+	p, err := NewPublisher(Config{
+		URL:           server.url(),
+		SubjectPrefix: "test",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	events := []eventbus.Event{
+		{
+			Type:    eventbus.EventSpotCreated,
+			ActorID: "42",
+			Spot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+			},
+		},
+		{
+			Type:    eventbus.EventSpotDeleted,
+			ActorID: "42",
+			Spot: surf.Spot{
+				ID: "1",
+			},
+		},
+	}
+	for _, e := range events {
+		p.Publish(e)
+	}
+	p.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if !assert.Len(t, server.connects, 1) {
+		return
+	}
+	var opts connectOptions
+	assert.NoError(t, json.Unmarshal([]byte(server.connects[0]), &opts))
+	assert.Equal(t, "user", opts.User)
+	assert.Equal(t, "secret", opts.Pass)
+	assert.False(t, opts.Verbose)
+
+	if !assert.Len(t, server.messages, 2) {
+		return
+	}
+	for i, e := range events {
+		body, err := json.Marshal(e)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "test."+string(e.Type), server.messages[i].subject)
+		assert.JSONEq(t, string(body), server.messages[i].body)
+	}
+}
+
+func TestPublisher_LogsFailures(t *testing.T) {
+	server := newFakeServer(t)
+	server.close()
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	p, err := NewPublisher(Config{
+		URL: server.url(),
+	}, WithLogger(logger))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p.Publish(eventbus.Event{
+		Type: eventbus.EventSpotCreated,
+		Spot: surf.Spot{ID: "1"},
+	})
+	p.Close()
+
+	assert.Contains(t, buf.String(), "failed to publish event: failed to connect")
+	assert.Contains(t, buf.String(), "spot_id=1")
+
+	buf.Reset()
+	p.Publish(eventbus.Event{Type: eventbus.EventSpotUpdated})
+	assert.Contains(t, buf.String(), "event dropped: publisher is closed")
+}
+
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expectedAddr  string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for url with unexpected scheme",
+			url:           "http://localhost:4222",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:          "return error for url without host",
+			url:           "nats://",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:          "return publisher using default port without error",
+			url:           "nats://localhost",
+			expectedAddr:  "localhost:4222",
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return publisher without error",
+			url:           "nats://localhost:1234",
+			expectedAddr:  "localhost:1234",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewPublisher(Config{URL: test.url})
+			test.expectedErrFn(t, err)
+			if p == nil {
+				return
+			}
+			defer p.Close()
+
+			assert.Equal(t, test.expectedAddr, p.addr)
+			assert.Equal(t, defaultSubjectPrefix, p.subjectPrefix)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Package forecast provides fetching forecasts of surfing conditions such as
+// swell and wind from external sources.
+package forecast
+
+import (
+	"context"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+)
+
+// Source is anything that can forecast conditions at given coordinates.
+type Source interface {
+	// Forecast fetches conditions for the given number of days starting from
+	// today, in chronological order.
+	Forecast(ctx context.Context, c geo.Coordinates, days int) ([]Day, error)
+}
+
+// Day holds conditions forecasted for a single day. Values are nil when the
+// source has no data for them, e.g. waves at coordinates far from the sea.
+type Day struct {
+	// Date is midnight of the day in UTC.
+	Date time.Time
+
+	// WaveHeightMax is in meters and WavePeriodMax is in seconds. WaveDirection
+	// is a dominant direction that waves come from in degrees.
+	WaveHeightMax *float64
+	WavePeriodMax *float64
+	WaveDirection *float64
+
+	// WindSpeedMax and WindGustsMax are in kilometers per hour. WindDirection is
+	// a dominant direction that wind blows from in degrees.
+	WindSpeedMax  *float64
+	WindGustsMax  *float64
+	WindDirection *float64
+}
@@ -0,0 +1,239 @@
+// Package openmeteo provides forecasts of surfing conditions using the Open-Meteo
+// APIs, whose marine API forecasts waves and whose weather API forecasts wind.
+package openmeteo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+const (
+	endpointMarine  = "/v1/marine"
+	endpointWeather = "/v1/forecast"
+
+	queryParamLatitude     = "latitude"
+	queryParamLongitude    = "longitude"
+	queryParamDaily        = "daily"
+	queryParamForecastDays = "forecast_days"
+	queryParamTimezone     = "timezone"
+
+	// timezoneGMT makes days start at midnight in UTC, so that days fetched from
+	// both APIs line up regardless of where the coordinates are.
+	timezoneGMT = "GMT"
+
+	dateLayout = "2006-01-02"
+
+	metricRequestDurationSeconds = "openmeteo_request_duration_seconds"
+	metricRequestErrorsTotal     = "openmeteo_request_errors_total"
+)
+
+var (
+	marineVariables = []string{
+		"wave_height_max",
+		"wave_period_max",
+		"wave_direction_dominant",
+	}
+	weatherVariables = []string{
+		"wind_speed_10m_max",
+		"wind_gusts_10m_max",
+		"wind_direction_10m_dominant",
+	}
+)
+
+// OpenMeteo is an adapter for communicating with the Open-Meteo APIs.
+type OpenMeteo struct {
+	client         *http.Client
+	marineBaseURL  string
+	weatherBaseURL string
+
+	requestDurations *metrics.Histogram
+	requestErrors    *metrics.Counter
+}
+
+// Config holds configuration for connecting to the Open-Meteo APIs, which are
+// served at separate base URLs.
+type Config struct {
+	MarineBaseURL  string
+	WeatherBaseURL string
+	Timeout        time.Duration
+}
+
+// New returns a new *OpenMeteo using the given configuration and various options.
+func New(cfg Config, opts ...Option) *OpenMeteo {
+	om := &OpenMeteo{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		marineBaseURL:  cfg.MarineBaseURL,
+		weatherBaseURL: cfg.WeatherBaseURL,
+	}
+
+	for _, opt := range opts {
+		opt(om)
+	}
+
+	return om
+}
+
+// Option is an optional function for OpenMeteo.
+type Option func(*OpenMeteo)
+
+// WithMetrics enables instrumentation of requests sent to the Open-Meteo APIs
+// using the given registry.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(om *OpenMeteo) {
+		om.requestDurations = reg.Histogram(
+			metricRequestDurationSeconds,
+			"Duration of requests to the Open-Meteo APIs in seconds.",
+			nil,
+		)
+		om.requestErrors = reg.Counter(
+			metricRequestErrorsTotal,
+			"Total number of failed requests to the Open-Meteo APIs.",
+		)
+	}
+}
+
+// Forecast implements forecast.Source interface and fetches waves and wind at the
+// given coordinates for the given number of days starting from today in UTC.
+func (om *OpenMeteo) Forecast(ctx context.Context, c geo.Coordinates, days int) ([]forecast.Day, error) {
+	var marine marineResponse
+	if err := om.get(ctx, om.marineBaseURL+endpointMarine, query(c, days, marineVariables), &marine); err != nil {
+		return nil, fmt.Errorf("failed to fetch marine forecast: %w", err)
+	}
+
+	var weather weatherResponse
+	if err := om.get(ctx, om.weatherBaseURL+endpointWeather, query(c, days, weatherVariables), &weather); err != nil {
+		return nil, fmt.Errorf("failed to fetch weather forecast: %w", err)
+	}
+
+	return toDays(marine, weather)
+}
+
+func query(c geo.Coordinates, days int, variables []string) url.Values {
+	return url.Values{
+		queryParamLatitude:     []string{floatToString(c.Latitude)},
+		queryParamLongitude:    []string{floatToString(c.Longitude)},
+		queryParamDaily:        []string{strings.Join(variables, ",")},
+		queryParamForecastDays: []string{strconv.Itoa(days)},
+		queryParamTimezone:     []string{timezoneGMT},
+	}
+}
+
+func (om *OpenMeteo) get(ctx context.Context, endpoint string, q url.Values, v interface{}) error {
+	start := time.Now()
+
+	err := om.do(ctx, endpoint, q, v)
+
+	om.requestDurations.Observe(time.Since(start).Seconds())
+	if err != nil {
+		om.requestErrors.Inc()
+	}
+
+	return err
+}
+
+func (om *OpenMeteo) do(ctx context.Context, endpoint string, q url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := om.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unsuccessful response: %s %s", resp.Status, string(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+func floatToString(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type marineResponse struct {
+	Daily struct {
+		Time          []string   `json:"time"`
+		WaveHeightMax []*float64 `json:"wave_height_max"`
+		WavePeriodMax []*float64 `json:"wave_period_max"`
+		WaveDirection []*float64 `json:"wave_direction_dominant"`
+	} `json:"daily"`
+}
+
+type weatherResponse struct {
+	Daily struct {
+		Time          []string   `json:"time"`
+		WindSpeedMax  []*float64 `json:"wind_speed_10m_max"`
+		WindGustsMax  []*float64 `json:"wind_gusts_10m_max"`
+		WindDirection []*float64 `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+}
+
+// toDays merges days of both responses by their dates. Days of the marine
+// response are kept in their order, and wind is left empty for the ones that the
+// weather response lacks.
+func toDays(marine marineResponse, weather weatherResponse) ([]forecast.Day, error) {
+	weatherIndexes := make(map[string]int, len(weather.Daily.Time))
+	for i, date := range weather.Daily.Time {
+		weatherIndexes[date] = i
+	}
+
+	days := make([]forecast.Day, len(marine.Daily.Time))
+	for i, date := range marine.Daily.Time {
+		t, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date: %w", err)
+		}
+
+		d := forecast.Day{
+			Date:          t,
+			WaveHeightMax: valueAt(marine.Daily.WaveHeightMax, i),
+			WavePeriodMax: valueAt(marine.Daily.WavePeriodMax, i),
+			WaveDirection: valueAt(marine.Daily.WaveDirection, i),
+		}
+
+		if j, ok := weatherIndexes[date]; ok {
+			d.WindSpeedMax = valueAt(weather.Daily.WindSpeedMax, j)
+			d.WindGustsMax = valueAt(weather.Daily.WindGustsMax, j)
+			d.WindDirection = valueAt(weather.Daily.WindDirection, j)
+		}
+
+		days[i] = d
+	}
+
+	return days, nil
+}
+
+func valueAt(values []*float64, i int) *float64 {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
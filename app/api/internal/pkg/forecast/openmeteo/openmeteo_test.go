@@ -0,0 +1,180 @@
+package openmeteo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/forecast"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
+)
+
+func TestOpenMeteo_Forecast(t *testing.T) {
+	tests := []struct {
+		name           string
+		marineHandler  http.HandlerFunc
+		weatherHandler http.HandlerFunc
+		expectedDays   []forecast.Day
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for marine response with non-200 http status code",
+			marineHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":true,"reason":"Invalid coordinates."}`))
+			},
+			weatherHandler: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("unexpected request to weather api")
+			},
+			expectedDays:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for weather response with non-200 http status code",
+			marineHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"daily":{"time":["2021-01-01"]}}`))
+			},
+			weatherHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedDays:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for response with unexpected body",
+			marineHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(nil)
+			},
+			weatherHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"daily":{"time":["2021-01-01"]}}`))
+			},
+			expectedDays:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for response with invalid date",
+			marineHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"daily":{"time":["01.01.2021"]}}`))
+			},
+			weatherHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"daily":{"time":["01.01.2021"]}}`))
+			},
+			expectedDays:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return days merged by dates without error",
+			marineHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(
+					`{
+						"daily": {
+							"time": ["2021-01-01", "2021-01-02"],
+							"wave_height_max": [1.5, null],
+							"wave_period_max": [9.8, 11.2],
+							"wave_direction_dominant": [270, 265]
+						}
+					}`,
+				))
+			},
+			weatherHandler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(
+					`{
+						"daily": {
+							"time": ["2021-01-02"],
+							"wind_speed_10m_max": [20.5],
+							"wind_gusts_10m_max": [35.1],
+							"wind_direction_10m_dominant": [90]
+						}
+					}`,
+				))
+			},
+			expectedDays: []forecast.Day{
+				{
+					Date:          time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					WaveHeightMax: pconv.Float64(1.5),
+					WavePeriodMax: pconv.Float64(9.8),
+					WaveDirection: pconv.Float64(270),
+				},
+				{
+					Date:          time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+					WavePeriodMax: pconv.Float64(11.2),
+					WaveDirection: pconv.Float64(265),
+					WindSpeedMax:  pconv.Float64(20.5),
+					WindGustsMax:  pconv.Float64(35.1),
+					WindDirection: pconv.Float64(90),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertQuery := func(r *http.Request, variables []string) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "1.23", r.URL.Query().Get(queryParamLatitude))
+				assert.Equal(t, "3.21", r.URL.Query().Get(queryParamLongitude))
+				assert.Equal(t, "2", r.URL.Query().Get(queryParamForecastDays))
+				assert.Equal(t, timezoneGMT, r.URL.Query().Get(queryParamTimezone))
+				assert.Equal(t, strings.Join(variables, ","), r.URL.Query().Get(queryParamDaily))
+			}
+
+			marineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, endpointMarine, r.URL.Path)
+				assertQuery(r, marineVariables)
+				test.marineHandler(w, r)
+			}))
+			defer marineServer.Close()
+
+			weatherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, endpointWeather, r.URL.Path)
+				assertQuery(r, weatherVariables)
+				test.weatherHandler(w, r)
+			}))
+			defer weatherServer.Close()
+
+			om := New(Config{
+				MarineBaseURL:  marineServer.URL,
+				WeatherBaseURL: weatherServer.URL,
+			})
+
+			days, err := om.Forecast(context.Background(), geo.Coordinates{
+				Latitude:  1.23,
+				Longitude: 3.21,
+			}, 2)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedDays, days)
+		})
+	}
+}
+
+func TestOpenMeteo_Forecast_Metrics(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"daily":{"time":[]}}`))
+	}))
+	defer server.Close()
+
+	reg := metrics.NewRegistry()
+	om := New(Config{
+		MarineBaseURL:  server.URL,
+		WeatherBaseURL: server.URL,
+	}, WithMetrics(reg))
+
+	_, err := om.Forecast(context.Background(), geo.Coordinates{}, 1)
+	assert.Error(t, err)
+
+	statusCode = http.StatusOK
+	_, err = om.Forecast(context.Background(), geo.Coordinates{}, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(3), reg.Histogram(metricRequestDurationSeconds, "", nil).Count())
+	assert.Equal(t, float64(1), reg.Counter(metricRequestErrorsTotal, "").Value())
+}
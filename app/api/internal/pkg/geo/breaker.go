@@ -0,0 +1,160 @@
+package geo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// Geocoder is anything that can fetch locations by coordinates in the default and
+// in other languages.
+type Geocoder interface {
+	LocationSource
+	LocalizedLocationSource
+}
+
+// UnavailableError is used when a location source is temporarily unavailable and
+// is not called until it is expected to recover.
+type UnavailableError struct {
+	// RetryAfter is a duration after which the source is going to be called again.
+	RetryAfter time.Duration
+}
+
+// Error implements error interface.
+func (e *UnavailableError) Error() string {
+	return "location source unavailable"
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker is a Geocoder that stops calling another Geocoder after a number
+// of consecutive failures, and fails fast with *UnavailableError instead. Once a
+// cooldown passes, a single trial call is let through. The breaker closes again if
+// it succeeds, or stays open for another cooldown otherwise.
+//
+// ErrLocationNotFound is not treated as a failure.
+type CircuitBreaker struct {
+	source    Geocoder
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker returns a new *CircuitBreaker that wraps the given source and
+// is configured with various options.
+func NewCircuitBreaker(src Geocoder, opts ...BreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		source:    src,
+		threshold: defaultBreakerThreshold,
+		cooldown:  defaultBreakerCooldown,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(cb)
+	}
+
+	return cb
+}
+
+// BreakerOption is an optional function for CircuitBreaker.
+type BreakerOption func(*CircuitBreaker)
+
+// WithBreakerThreshold sets a number of consecutive failures that opens the
+// breaker. It defaults to 5.
+func WithBreakerThreshold(n int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		if n > 0 {
+			cb.threshold = n
+		}
+	}
+}
+
+// WithBreakerCooldown sets a duration for which the breaker stays open before
+// letting a trial call through. It defaults to 30 seconds.
+func WithBreakerCooldown(d time.Duration) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		if d > 0 {
+			cb.cooldown = d
+		}
+	}
+}
+
+// Location implements LocationSource interface.
+func (cb *CircuitBreaker) Location(c Coordinates) (Location, error) {
+	return cb.call(func() (Location, error) {
+		return cb.source.Location(c)
+	})
+}
+
+// LocalizedLocation implements LocalizedLocationSource interface.
+func (cb *CircuitBreaker) LocalizedLocation(c Coordinates, language string) (Location, error) {
+	return cb.call(func() (Location, error) {
+		return cb.source.LocalizedLocation(c, language)
+	})
+}
+
+func (cb *CircuitBreaker) call(fn func() (Location, error)) (Location, error) {
+	if err := cb.allow(); err != nil {
+		return Location{}, err
+	}
+
+	l, err := fn()
+	cb.record(err == nil || errors.Is(err, ErrLocationNotFound))
+	return l, err
+}
+
+// allow reports whether a call can be made, and moves the open breaker to the
+// half-open state once the cooldown passes.
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		elapsed := cb.now().Sub(cb.openedAt)
+		if elapsed < cb.cooldown {
+			return &UnavailableError{RetryAfter: cb.cooldown - elapsed}
+		}
+		cb.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// A trial call is already in flight.
+		return &UnavailableError{RetryAfter: cb.cooldown}
+	default:
+		return nil
+	}
+}
+
+func (cb *CircuitBreaker) record(ok bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if ok {
+		cb.state = breakerClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == breakerHalfOpen || cb.failures >= cb.threshold {
+		cb.state = breakerOpen
+		cb.openedAt = cb.now()
+	}
+}
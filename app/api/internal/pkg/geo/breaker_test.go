@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockGeocoder struct {
+	mock.Mock
+}
+
+func (m *mockGeocoder) Location(c Coordinates) (Location, error) {
+	args := m.Called(c)
+	return args.Get(0).(Location), args.Error(1)
+}
+
+func (m *mockGeocoder) LocalizedLocation(c Coordinates, language string) (Location, error) {
+	args := m.Called(c, language)
+	return args.Get(0).(Location), args.Error(1)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		c      = Coordinates{Latitude: 1, Longitude: 1}
+		errFoo = errors.New("something went wrong")
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	src := &mockGeocoder{}
+	cb := NewCircuitBreaker(src, WithBreakerThreshold(2), WithBreakerCooldown(time.Minute))
+	cb.now = func() time.Time { return now }
+
+	assertUnavailable := func(retryAfter time.Duration) {
+		var uErr *UnavailableError
+		_, err := cb.Location(c)
+		if assert.True(t, errors.As(err, &uErr)) {
+			assert.Equal(t, retryAfter, uErr.RetryAfter)
+		}
+	}
+
+	// Closed: not found locations are not failures.
+	src.On("Location", c).Return(Location{}, ErrLocationNotFound).Twice()
+	for i := 0; i < 2; i++ {
+		_, err := cb.Location(c)
+		assert.True(t, errors.Is(err, ErrLocationNotFound))
+	}
+	assert.Equal(t, breakerClosed, cb.state)
+
+	// Closed: consecutive failures reaching the threshold open the breaker.
+	src.On("Location", c).Return(Location{}, errFoo).Twice()
+	_, err := cb.Location(c)
+	assert.Equal(t, errFoo, err)
+	assert.Equal(t, breakerClosed, cb.state)
+	_, err = cb.Location(c)
+	assert.Equal(t, errFoo, err)
+	assert.Equal(t, breakerOpen, cb.state)
+
+	// Open: calls are short-circuited until the cooldown passes.
+	assertUnavailable(time.Minute)
+	now = now.Add(40 * time.Second)
+	assertUnavailable(20 * time.Second)
+
+	// Half-open: a failed trial call opens the breaker again.
+	now = now.Add(20 * time.Second)
+	src.On("LocalizedLocation", c, "ru").Return(Location{}, errFoo).Once()
+	_, err = cb.LocalizedLocation(c, "ru")
+	assert.Equal(t, errFoo, err)
+	assert.Equal(t, breakerOpen, cb.state)
+	assertUnavailable(time.Minute)
+
+	// Half-open: a successful trial call closes the breaker.
+	now = now.Add(time.Minute)
+	src.On("Location", c).Return(Location{Locality: "Aktau"}, nil).Once()
+	l, err := cb.Location(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "Aktau", l.Locality)
+	assert.Equal(t, breakerClosed, cb.state)
+	assert.Equal(t, 0, cb.failures)
+
+	src.AssertExpectations(t)
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleTrial(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cb := NewCircuitBreaker(&mockGeocoder{}, WithBreakerThreshold(1), WithBreakerCooldown(time.Minute))
+	cb.now = func() time.Time { return now }
+	cb.state = breakerOpen
+	cb.openedAt = now.Add(-time.Minute)
+
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, breakerHalfOpen, cb.state)
+
+	var uErr *UnavailableError
+	assert.True(t, errors.As(cb.allow(), &uErr))
+}
@@ -0,0 +1,85 @@
+package geo
+
+import "sync"
+
+// LocationCache is a Geocoder that caches locations fetched from another Geocoder
+// in memory. Only successfully fetched locations are cached, and the oldest ones
+// are evicted once the cache is full.
+type LocationCache struct {
+	source Geocoder
+	size   int
+
+	mu        sync.Mutex
+	locations map[locationCacheKey]Location
+	keys      []locationCacheKey
+}
+
+// locationCacheKey identifies a cached location. An empty language stands for
+// the default one.
+type locationCacheKey struct {
+	coordinates Coordinates
+	language    string
+}
+
+// NewLocationCache returns a new *LocationCache that caches up to the given
+// number of locations fetched from the given source.
+func NewLocationCache(src Geocoder, size int) *LocationCache {
+	return &LocationCache{
+		source:    src,
+		size:      size,
+		locations: make(map[locationCacheKey]Location, size),
+	}
+}
+
+// Location implements LocationSource interface and fetches a location from the
+// cache, or from the underlying source when it is not cached yet.
+func (lc *LocationCache) Location(c Coordinates) (Location, error) {
+	key := locationCacheKey{
+		coordinates: c,
+	}
+	return lc.get(key, func() (Location, error) {
+		return lc.source.Location(c)
+	})
+}
+
+// LocalizedLocation implements LocalizedLocationSource interface and fetches a
+// location from the cache, or from the underlying source when it is not cached
+// yet.
+func (lc *LocationCache) LocalizedLocation(c Coordinates, language string) (Location, error) {
+	key := locationCacheKey{
+		coordinates: c,
+		language:    language,
+	}
+	return lc.get(key, func() (Location, error) {
+		return lc.source.LocalizedLocation(c, language)
+	})
+}
+
+func (lc *LocationCache) get(key locationCacheKey, fetch func() (Location, error)) (Location, error) {
+	lc.mu.Lock()
+	l, ok := lc.locations[key]
+	lc.mu.Unlock()
+	if ok {
+		return l, nil
+	}
+
+	l, err := fetch()
+	if err != nil {
+		return Location{}, err
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if _, ok := lc.locations[key]; ok || lc.size <= 0 {
+		return l, nil
+	}
+	if len(lc.keys) >= lc.size {
+		delete(lc.locations, lc.keys[0])
+		lc.keys = lc.keys[1:]
+	}
+	lc.locations[key] = l
+	lc.keys = append(lc.keys, key)
+
+	return l, nil
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationCache(t *testing.T) {
+	var (
+		c1 = Coordinates{Latitude: 1, Longitude: 1}
+		c2 = Coordinates{Latitude: 2, Longitude: 2}
+		c3 = Coordinates{Latitude: 3, Longitude: 3}
+	)
+
+	src := &mockGeocoder{}
+	src.On("LocalizedLocation", c1, "ru").Return(Location{Locality: "Актау"}, nil).Once()
+	src.On("LocalizedLocation", c1, "en").Return(Location{Locality: "Aktau"}, nil).Once()
+	src.On("LocalizedLocation", c2, "ru").Return(Location{}, errors.New("something went wrong")).Twice()
+	src.On("LocalizedLocation", c3, "ru").Return(Location{Locality: "Атырау"}, nil).Once()
+	src.On("LocalizedLocation", c1, "ru").Return(Location{Locality: "Актау"}, nil).Once()
+
+	lc := NewLocationCache(src, 2)
+
+	l, err := lc.LocalizedLocation(c1, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Актау", l.Locality)
+
+	// Served from the cache.
+	l, err = lc.LocalizedLocation(c1, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Актау", l.Locality)
+
+	l, err = lc.LocalizedLocation(c1, "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "Aktau", l.Locality)
+
+	// Failures are not cached.
+	_, err = lc.LocalizedLocation(c2, "ru")
+	assert.Error(t, err)
+	_, err = lc.LocalizedLocation(c2, "ru")
+	assert.Error(t, err)
+
+	// Evicts the oldest location.
+	l, err = lc.LocalizedLocation(c3, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Атырау", l.Locality)
+
+	l, err = lc.LocalizedLocation(c1, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Актау", l.Locality)
+
+	src.AssertExpectations(t)
+}
+
+func TestLocationCache_Location(t *testing.T) {
+	c := Coordinates{Latitude: 1, Longitude: 1}
+
+	src := &mockGeocoder{}
+	src.On("Location", c).Return(Location{Locality: "Aktau"}, nil).Once()
+	src.On("LocalizedLocation", c, "ru").Return(Location{Locality: "Актау"}, nil).Once()
+
+	lc := NewLocationCache(src, 2)
+
+	for i := 0; i < 2; i++ {
+		l, err := lc.Location(c)
+		assert.NoError(t, err)
+		assert.Equal(t, "Aktau", l.Locality)
+	}
+
+	// Locations in the default language are cached separately.
+	l, err := lc.LocalizedLocation(c, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Актау", l.Locality)
+
+	src.AssertExpectations(t)
+}
@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// LocationFunc fetches a location by coordinates in the same way as
+// LocationSource does, and is expected to give up once the given context is done.
+type LocationFunc func(context.Context, Coordinates) (Location, error)
+
+// LocationResult holds a location fetched by coordinates. Err is set to
+// ErrLocationNotFound when there is no location at the coordinates, in which case
+// Location is empty.
+type LocationResult struct {
+	Location Location
+	Err      error
+}
+
+// ConcurrentLocations fetches locations by the given coordinates using the given
+// function, which is called by up to the given number of workers at the same
+// time, and returns results in the same order as the coordinates. A non-positive
+// number of workers is treated as 1.
+//
+// Coordinates without a location get ErrLocationNotFound in their results. Any
+// other error is treated as fatal: the first one is returned without results, the
+// workers stop picking up the remaining coordinates, and the context passed to
+// the calls that are still in progress gets cancelled. Once the given context is
+// done, its error is returned without results as well.
+func ConcurrentLocations(ctx context.Context, fn LocationFunc, coords []Coordinates, maxWorkers int) ([]LocationResult, error) {
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+	if maxWorkers > len(coords) {
+		maxWorkers = len(coords)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results = make([]LocationResult, len(coords))
+		indexes = make(chan int)
+		wg      sync.WaitGroup
+		once    sync.Once
+		failure error
+	)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				l, err := fn(ctx, coords[i])
+				switch {
+				case err == nil:
+					results[i].Location = l
+				case errors.Is(err, ErrLocationNotFound):
+					results[i].Err = err
+				default:
+					once.Do(func() {
+						failure = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range coords {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
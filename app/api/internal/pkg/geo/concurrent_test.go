@@ -0,0 +1,204 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trackingLocationSource responds with locations at the given coordinates after a
+// delay that is longer for lower latitudes, so that responses arrive out of order,
+// unless the delay is overridden or the context is done earlier. It records the
+// number of calls and the highest number of concurrent ones.
+type trackingLocationSource struct {
+	errs   map[Coordinates]error
+	delays map[Coordinates]time.Duration
+
+	mu      sync.Mutex
+	calls   int
+	active  int
+	maxSeen int
+}
+
+func (s *trackingLocationSource) location(ctx context.Context, c Coordinates) (Location, error) {
+	s.mu.Lock()
+	s.calls++
+	s.active++
+	if s.active > s.maxSeen {
+		s.maxSeen = s.active
+	}
+	s.mu.Unlock()
+
+	delay, ok := s.delays[c]
+	if !ok {
+		delay = time.Duration(20-c.Latitude) * time.Millisecond
+	}
+
+	var err error
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+
+	if err != nil {
+		return Location{}, err
+	}
+
+	if err, ok := s.errs[c]; ok {
+		return Location{}, err
+	}
+	return Location{Coordinates: c}, nil
+}
+
+func coordinatesOf(n int) []Coordinates {
+	coords := make([]Coordinates, n)
+	for i := range coords {
+		coords[i] = Coordinates{Latitude: float64(i)}
+	}
+	return coords
+}
+
+func TestConcurrentLocations(t *testing.T) {
+	tests := []struct {
+		name            string
+		errs            map[Coordinates]error
+		coords          []Coordinates
+		maxWorkers      int
+		expectedResults []LocationResult
+		expectedMaxSeen int
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name:            "return no results for no coordinates",
+			coords:          nil,
+			maxWorkers:      4,
+			expectedResults: []LocationResult{},
+			expectedMaxSeen: 0,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name:       "return results in order of coordinates",
+			coords:     coordinatesOf(6),
+			maxWorkers: 3,
+			expectedResults: []LocationResult{
+				{Location: Location{Coordinates: Coordinates{Latitude: 0}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 1}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 2}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 3}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 4}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 5}}},
+			},
+			expectedMaxSeen: 3,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return not found errors in results",
+			errs: map[Coordinates]error{
+				{Latitude: 1}: ErrLocationNotFound,
+			},
+			coords:     coordinatesOf(3),
+			maxWorkers: 5,
+			expectedResults: []LocationResult{
+				{Location: Location{Coordinates: Coordinates{Latitude: 0}}},
+				{Err: ErrLocationNotFound},
+				{Location: Location{Coordinates: Coordinates{Latitude: 2}}},
+			},
+			expectedMaxSeen: 3,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name:       "use single worker for non-positive number of workers",
+			coords:     coordinatesOf(2),
+			maxWorkers: 0,
+			expectedResults: []LocationResult{
+				{Location: Location{Coordinates: Coordinates{Latitude: 0}}},
+				{Location: Location{Coordinates: Coordinates{Latitude: 1}}},
+			},
+			expectedMaxSeen: 1,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return error for fatal failure",
+			errs: map[Coordinates]error{
+				{Latitude: 1}: errors.New("something went wrong"),
+			},
+			coords:          coordinatesOf(3),
+			maxWorkers:      2,
+			expectedResults: nil,
+			expectedMaxSeen: 2,
+			expectedErrFn:   assert.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &trackingLocationSource{
+				errs: test.errs,
+			}
+
+			results, err := ConcurrentLocations(context.Background(), src.location, test.coords, test.maxWorkers)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedResults, results)
+			assert.Equal(t, test.expectedMaxSeen, src.maxSeen)
+		})
+	}
+}
+
+func TestConcurrentLocations_StopOnFatalError(t *testing.T) {
+	coords := coordinatesOf(20)
+
+	src := &trackingLocationSource{
+		errs: map[Coordinates]error{
+			coords[0]: errors.New("something went wrong"),
+		},
+	}
+
+	_, err := ConcurrentLocations(context.Background(), src.location, coords, 1)
+	assert.Error(t, err)
+
+	// The coordinates that are fed while the first call fails can still be picked
+	// up, but the rest of them are not.
+	assert.LessOrEqual(t, src.calls, 2)
+}
+
+func TestConcurrentLocations_CancelCallsInProgress(t *testing.T) {
+	var (
+		coords = coordinatesOf(2)
+		errFoo = errors.New("something went wrong")
+	)
+
+	src := &trackingLocationSource{
+		errs: map[Coordinates]error{
+			coords[1]: errFoo,
+		},
+		delays: map[Coordinates]time.Duration{
+			coords[0]: time.Hour,
+			coords[1]: time.Millisecond,
+		},
+	}
+
+	start := time.Now()
+	_, err := ConcurrentLocations(context.Background(), src.location, coords, 2)
+
+	assert.Equal(t, errFoo, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestConcurrentLocations_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := &trackingLocationSource{}
+
+	_, err := ConcurrentLocations(ctx, src.location, coordinatesOf(3), 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
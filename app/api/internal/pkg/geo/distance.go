@@ -0,0 +1,25 @@
+package geo
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth, which is also used by the spot
+// store for computing distances.
+const earthRadiusKm = 6371
+
+// DistanceKm returns a great-circle distance in kilometers between the given
+// coordinates, which is computed using the haversine formula.
+func DistanceKm(a, b Coordinates) float64 {
+	lat1 := radians(a.Latitude)
+	lat2 := radians(b.Latitude)
+	dLat := radians(b.Latitude - a.Latitude)
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
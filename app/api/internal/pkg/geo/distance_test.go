@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Coordinates
+		b        Coordinates
+		expected float64
+	}{
+		{
+			name:     "return zero for same coordinates",
+			a:        Coordinates{Latitude: 43.65, Longitude: 51.17},
+			b:        Coordinates{Latitude: 43.65, Longitude: 51.17},
+			expected: 0,
+		},
+		{
+			name:     "return distance of one degree along the equator",
+			a:        Coordinates{Latitude: 0, Longitude: 0},
+			b:        Coordinates{Latitude: 0, Longitude: 1},
+			expected: 111.19,
+		},
+		{
+			name:     "return distance across the antimeridian",
+			a:        Coordinates{Latitude: 0, Longitude: 179.5},
+			b:        Coordinates{Latitude: 0, Longitude: -179.5},
+			expected: 111.19,
+		},
+		{
+			name:     "return distance between cities",
+			a:        Coordinates{Latitude: 51.5074, Longitude: -0.1278},
+			b:        Coordinates{Latitude: 48.8566, Longitude: 2.3522},
+			expected: 343.56,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := DistanceKm(test.a, test.b)
+			assert.InDelta(t, test.expected, actual, 0.01)
+		})
+	}
+}
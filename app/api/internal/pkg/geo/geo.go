@@ -16,6 +16,9 @@ const (
 var (
 	// ErrLocationNotFound is used when a location is not found.
 	ErrLocationNotFound = errors.New("location not found")
+
+	// ErrInvalidBounds is used when bounds are not internally consistent.
+	ErrInvalidBounds = errors.New("invalid bounds")
 )
 
 // LocationSource is anything that can fetch a location by coordinates.
@@ -25,10 +28,20 @@ type LocationSource interface {
 	Location(Coordinates) (Location, error)
 }
 
+// LocalizedLocationSource is anything that can fetch a location by coordinates
+// with its names in a certain language.
+type LocalizedLocationSource interface {
+	// LocalizedLocation fetches a location by the given coordinates with its names
+	// in the given language, which is an IETF language tag such as "ru" or "kk".
+	// ErrLocationNotFound is returned when location is not found.
+	LocalizedLocation(c Coordinates, language string) (Location, error)
+}
+
 // Location represent a geographical location.
 type Location struct {
 	Locality    string
 	CountryCode string
+	Region      string
 	Coordinates Coordinates
 }
 
@@ -54,13 +67,64 @@ type Bounds struct {
 	SouthWest Coordinates
 }
 
+// CrossesAntimeridian checks if the bounds cross the 180th meridian, which is
+// the case when the south-west longitude is greater than the north-east one.
+func (b Bounds) CrossesAntimeridian() bool {
+	return b.SouthWest.Longitude > b.NorthEast.Longitude
+}
+
+// Validate checks if the bounds are internally consistent, which is the case when
+// the north-east latitude is not less than the south-west one. Longitudes are not
+// compared since a south-west longitude greater than the north-east one denotes
+// bounds crossing the 180th meridian. ErrInvalidBounds is returned otherwise.
+func (b Bounds) Validate() error {
+	if b.NorthEast.Latitude < b.SouthWest.Latitude {
+		return ErrInvalidBounds
+	}
+	return nil
+}
+
+// NormalizeBounds splits the given bounds into boxes that do not cross the 180th
+// meridian. Bounds crossing it are split into two boxes: one stretching from the
+// south-west longitude to 180 and another one from -180 to the north-east
+// longitude. Any other bounds are returned as a single box unchanged.
+func NormalizeBounds(b Bounds) []Bounds {
+	if !b.CrossesAntimeridian() {
+		return []Bounds{b}
+	}
+
+	return []Bounds{
+		{
+			NorthEast: Coordinates{
+				Latitude:  b.NorthEast.Latitude,
+				Longitude: maxLongitude,
+			},
+			SouthWest: b.SouthWest,
+		},
+		{
+			NorthEast: b.NorthEast,
+			SouthWest: Coordinates{
+				Latitude:  b.SouthWest.Latitude,
+				Longitude: minLongitude,
+			},
+		},
+	}
+}
+
 // IsCountry checks if the given string is a valid ISO-2 country code.
 func IsCountry(code string) bool {
+	_, ok := CountryName(code)
+	return ok
+}
+
+// CountryName returns an English short name of the country with the given ISO-2
+// code, which is matched case-insensitively. False is returned for unknown codes.
+func CountryName(code string) (string, bool) {
 	if len(code) != 2 {
-		return false
+		return "", false
 	}
-	_, ok := countries[strings.ToUpper(code)]
-	return ok
+	name, ok := countries[strings.ToUpper(code)]
+	return name, ok
 }
 
 var countries = map[string]string{
@@ -83,7 +147,7 @@ var countries = map[string]string{
 	"BV": "Bouvet Island",
 	"BW": "Botswana",
 	"WS": "Samoa",
-	"BQ": "Bonaire, Saint Eustatius and Saba ",
+	"BQ": "Bonaire, Saint Eustatius and Saba",
 	"BR": "Brazil",
 	"BS": "Bahamas",
 	"JE": "Jersey",
@@ -70,6 +70,105 @@ func TestIsLongitude(t *testing.T) {
 	}
 }
 
+func TestBounds_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		bounds        Bounds
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for inverted latitudes",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: -10, Longitude: 20},
+				SouthWest: Coordinates{Latitude: 50, Longitude: -20},
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return no error for bounds crossing antimeridian",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: 10, Longitude: -170},
+				SouthWest: Coordinates{Latitude: -10, Longitude: 170},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return no error for consistent bounds",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: 10, Longitude: 20},
+				SouthWest: Coordinates{Latitude: -10, Longitude: -20},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.bounds.Validate()
+			test.expectedErrFn(t, err)
+		})
+	}
+}
+
+func TestNormalizeBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		bounds   Bounds
+		expected []Bounds
+	}{
+		{
+			name: "return normal bounds unchanged",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: 10, Longitude: 20},
+				SouthWest: Coordinates{Latitude: -10, Longitude: -20},
+			},
+			expected: []Bounds{
+				{
+					NorthEast: Coordinates{Latitude: 10, Longitude: 20},
+					SouthWest: Coordinates{Latitude: -10, Longitude: -20},
+				},
+			},
+		},
+		{
+			name: "return bounds with inverted latitudes unchanged",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: -10, Longitude: 20},
+				SouthWest: Coordinates{Latitude: 10, Longitude: -20},
+			},
+			expected: []Bounds{
+				{
+					NorthEast: Coordinates{Latitude: -10, Longitude: 20},
+					SouthWest: Coordinates{Latitude: 10, Longitude: -20},
+				},
+			},
+		},
+		{
+			name: "return bounds crossing antimeridian split in two",
+			bounds: Bounds{
+				NorthEast: Coordinates{Latitude: 10, Longitude: -170},
+				SouthWest: Coordinates{Latitude: -10, Longitude: 170},
+			},
+			expected: []Bounds{
+				{
+					NorthEast: Coordinates{Latitude: 10, Longitude: 180},
+					SouthWest: Coordinates{Latitude: -10, Longitude: 170},
+				},
+				{
+					NorthEast: Coordinates{Latitude: 10, Longitude: -170},
+					SouthWest: Coordinates{Latitude: -10, Longitude: -180},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := NormalizeBounds(test.bounds)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestIsCountry(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -118,3 +217,57 @@ func TestIsCountry(t *testing.T) {
 		})
 	}
 }
+
+func TestCountryName(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		expectedName string
+		expectedOK   bool
+	}{
+		{
+			name:         "return name for upper-case code",
+			code:         "KZ",
+			expectedName: "Kazakhstan",
+			expectedOK:   true,
+		},
+		{
+			name:         "return name for lower-case code",
+			code:         "us",
+			expectedName: "United States",
+			expectedOK:   true,
+		},
+		{
+			name:         "return name for mixed-case code",
+			code:         "pT",
+			expectedName: "Portugal",
+			expectedOK:   true,
+		},
+		{
+			name:         "return name without trailing whitespace",
+			code:         "bq",
+			expectedName: "Bonaire, Saint Eustatius and Saba",
+			expectedOK:   true,
+		},
+		{
+			name:         "return false for unknown code",
+			code:         "zz",
+			expectedName: "",
+			expectedOK:   false,
+		},
+		{
+			name:         "return false for code of invalid length",
+			code:         "kaz",
+			expectedName: "",
+			expectedOK:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, ok := CountryName(test.code)
+			assert.Equal(t, test.expectedName, name)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
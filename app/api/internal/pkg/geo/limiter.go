@@ -0,0 +1,64 @@
+package geo
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a Geocoder that spaces calls of another Geocoder by at least the
+// given interval, so that services with usage policies, such as the public
+// Nominatim allowing a single request per second, are not overwhelmed. Calls that
+// are made too early wait for their turn in the order they are made.
+type RateLimiter struct {
+	source   Geocoder
+	interval time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimiter returns a new *RateLimiter that wraps the given source and lets
+// a call through once per the given interval. A non-positive interval disables
+// rate limiting.
+func NewRateLimiter(src Geocoder, interval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		source:   src,
+		interval: interval,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// Location implements LocationSource interface.
+func (rl *RateLimiter) Location(c Coordinates) (Location, error) {
+	rl.wait()
+	return rl.source.Location(c)
+}
+
+// LocalizedLocation implements LocalizedLocationSource interface.
+func (rl *RateLimiter) LocalizedLocation(c Coordinates, language string) (Location, error) {
+	rl.wait()
+	return rl.source.LocalizedLocation(c, language)
+}
+
+// wait reserves the next free slot and blocks until it comes.
+func (rl *RateLimiter) wait() {
+	if rl.interval <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	now := rl.now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	delay := rl.next.Sub(now)
+	rl.next = rl.next.Add(rl.interval)
+	rl.mu.Unlock()
+
+	if delay > 0 {
+		rl.sleep(delay)
+	}
+}
@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var (
+		c      = Coordinates{Latitude: 1, Longitude: 1}
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		sleeps []time.Duration
+	)
+
+	src := &mockGeocoder{}
+	src.On("Location", c).Return(Location{Locality: "Aktau"}, nil)
+	src.On("LocalizedLocation", c, "ru").Return(Location{Locality: "Актау"}, nil)
+
+	rl := NewRateLimiter(src, time.Second)
+	rl.now = func() time.Time { return now }
+	rl.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	// Calls made at once wait for their slots one after another.
+	for i := 0; i < 3; i++ {
+		l, err := rl.Location(c)
+		assert.NoError(t, err)
+		assert.Equal(t, "Aktau", l.Locality)
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+
+	// Slots are shared by localized calls, and are not accumulated while idle.
+	now = now.Add(time.Minute)
+	sleeps = nil
+
+	l, err := rl.LocalizedLocation(c, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Актау", l.Locality)
+
+	now = now.Add(300 * time.Millisecond)
+	_, err = rl.Location(c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []time.Duration{700 * time.Millisecond}, sleeps)
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	c := Coordinates{Latitude: 1, Longitude: 1}
+
+	src := &mockGeocoder{}
+	src.On("Location", c).Return(Location{}, ErrLocationNotFound)
+
+	rl := NewRateLimiter(src, 0)
+	rl.sleep = func(d time.Duration) {
+		assert.Fail(t, "unexpected sleep")
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := rl.Location(c)
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	}
+}
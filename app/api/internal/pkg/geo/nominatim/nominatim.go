@@ -1,7 +1,9 @@
 package nominatim
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,10 +12,12 @@ import (
 	"time"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
 )
 
 const (
 	endpointReverseGeocoding = "/reverse"
+	endpointStatus           = "/status"
 
 	headerAcceptLanguage = "Accept-Language"
 
@@ -23,12 +27,18 @@ const (
 
 	formatJSON          = "json"
 	languageCodeEnglish = "en"
+
+	metricRequestDurationSeconds = "nominatim_request_duration_seconds"
+	metricRequestErrorsTotal     = "nominatim_request_errors_total"
 )
 
 // Nominatim is an adapter for communicating with the Notimatim API.
 type Nominatim struct {
 	client  *http.Client
 	baseURL string
+
+	requestDurations *metrics.Histogram
+	requestErrors    *metrics.Counter
 }
 
 // Config holds configuration for connecting to the Nominatim API.
@@ -37,19 +47,64 @@ type Config struct {
 	Timeout time.Duration
 }
 
-// New returns a new *Nominatim.
-func New(cfg Config) *Nominatim {
-	return &Nominatim{
+// New returns a new *Nominatim using the given configuration and various options.
+func New(cfg Config, opts ...Option) *Nominatim {
+	n := &Nominatim{
 		client: &http.Client{
 			Timeout: cfg.Timeout,
 		},
 		baseURL: cfg.BaseURL,
 	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// Option is an optional function for Nominatim.
+type Option func(*Nominatim)
+
+// WithMetrics enables instrumentation of requests sent to the Nominatim API using
+// the given registry.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(n *Nominatim) {
+		n.requestDurations = reg.Histogram(
+			metricRequestDurationSeconds,
+			"Duration of requests to the Nominatim API in seconds.",
+			nil,
+		)
+		n.requestErrors = reg.Counter(
+			metricRequestErrorsTotal,
+			"Total number of failed requests to the Nominatim API.",
+		)
+	}
 }
 
 // Location implements geo.LocationSource interface and fetches a location by the
 // given coordinates. ErrLocationNotFound is returned when location is not found.
 func (n *Nominatim) Location(c geo.Coordinates) (geo.Location, error) {
+	return n.LocalizedLocation(c, languageCodeEnglish)
+}
+
+// LocalizedLocation implements geo.LocalizedLocationSource interface and fetches
+// a location by the given coordinates with its names in the given language.
+// ErrLocationNotFound is returned when location is not found.
+func (n *Nominatim) LocalizedLocation(c geo.Coordinates, language string) (geo.Location, error) {
+	start := time.Now()
+
+	l, err := n.location(c, language)
+
+	n.requestDurations.Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, geo.ErrLocationNotFound) {
+		n.requestErrors.Inc()
+	}
+
+	return l, err
+}
+
+func (n *Nominatim) location(c geo.Coordinates, language string) (geo.Location, error) {
 	req, err := http.NewRequest(http.MethodGet, n.baseURL+endpointReverseGeocoding, nil)
 	if err != nil {
 		return geo.Location{}, fmt.Errorf("failed to prepare request: %w", err)
@@ -62,7 +117,7 @@ func (n *Nominatim) Location(c geo.Coordinates) (geo.Location, error) {
 	}
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set(headerAcceptLanguage, languageCodeEnglish)
+	req.Header.Set(headerAcceptLanguage, language)
 
 	resp, err := n.client.Do(req)
 	if err != nil {
@@ -91,6 +146,27 @@ func (n *Nominatim) Location(c geo.Coordinates) (geo.Location, error) {
 	return r.toLocation(c), nil
 }
 
+// Ping checks whether the Nominatim API is reachable and operational using its
+// status endpoint.
+func (n *Nominatim) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+endpointStatus, nil)
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unsuccessful response: %s", resp.Status)
+	}
+
+	return nil
+}
+
 func floatToString(f float64) string {
 	return strconv.FormatFloat(f, 'f', -1, 64)
 }
@@ -108,6 +184,7 @@ func (r reverseGeocodingResponse) toLocation(c geo.Coordinates) geo.Location {
 	return geo.Location{
 		CountryCode: r.Address.CountryCode,
 		Locality:    r.Address.locality(),
+		Region:      r.Address.region(),
 		Coordinates: c,
 	}
 }
@@ -159,3 +236,16 @@ func (r reverseGeocodingAddressResponse) locality() string {
 	}
 	return ""
 }
+
+func (r reverseGeocodingAddressResponse) region() string {
+	if r.State != "" {
+		return r.State
+	}
+	if r.Territory != "" {
+		return r.Territory
+	}
+	if r.Region != "" {
+		return r.Region
+	}
+	return ""
+}
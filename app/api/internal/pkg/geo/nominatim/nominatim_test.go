@@ -1,12 +1,14 @@
 package nominatim
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
 	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
 )
 
@@ -106,6 +108,7 @@ func TestNominatim_Location(t *testing.T) {
 				},
 				CountryCode: "Country code",
 				Locality:    "Hamlet",
+				Region:      "State",
 			},
 			expectedErrFn: assert.NoError,
 		},
@@ -133,6 +136,55 @@ func TestNominatim_Location(t *testing.T) {
 	}
 }
 
+func TestNominatim_LocalizedLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, endpointReverseGeocoding, r.URL.Path)
+		assert.Equal(t, "ru", r.Header.Get(headerAcceptLanguage))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"address":{"country_code":"kz","city":"Актау"}}`))
+	}))
+	defer server.Close()
+
+	n := New(Config{
+		BaseURL: server.URL,
+	})
+
+	location, err := n.LocalizedLocation(geo.Coordinates{Latitude: 43.65, Longitude: 51.17}, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Location{
+		Coordinates: geo.Coordinates{
+			Latitude:  43.65,
+			Longitude: 51.17,
+		},
+		CountryCode: "kz",
+		Locality:    "Актау",
+	}, location)
+}
+
+func TestNominatim_Location_Metrics(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"error":"Unable to geocode"}`))
+	}))
+	defer server.Close()
+
+	reg := metrics.NewRegistry()
+	n := New(Config{
+		BaseURL: server.URL,
+	}, WithMetrics(reg))
+
+	_, err := n.Location(geo.Coordinates{})
+	assert.Error(t, err)
+
+	statusCode = http.StatusOK
+	_, err = n.Location(geo.Coordinates{})
+	assert.ErrorIs(t, err, geo.ErrLocationNotFound)
+
+	assert.Equal(t, uint64(2), reg.Histogram(metricRequestDurationSeconds, "", nil).Count())
+	assert.Equal(t, float64(1), reg.Counter(metricRequestErrorsTotal, "").Value())
+}
+
 func TestReverseGeocodingAddressResponse_Locality(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -268,3 +320,90 @@ func TestReverseGeocodingAddressResponse_Locality(t *testing.T) {
 		})
 	}
 }
+
+func TestReverseGeocodingAddressResponse_Region(t *testing.T) {
+	tests := []struct {
+		name           string
+		resp           reverseGeocodingAddressResponse
+		expectedRegion string
+	}{
+		{
+			name: "return state",
+			resp: reverseGeocodingAddressResponse{
+				Region:    "Region",
+				Territory: "Territory",
+				State:     "State",
+				County:    "County",
+			},
+			expectedRegion: "State",
+		},
+		{
+			name: "return territory",
+			resp: reverseGeocodingAddressResponse{
+				Region:    "Region",
+				Territory: "Territory",
+				County:    "County",
+			},
+			expectedRegion: "Territory",
+		},
+		{
+			name: "return region",
+			resp: reverseGeocodingAddressResponse{
+				Region: "Region",
+				County: "County",
+			},
+			expectedRegion: "Region",
+		},
+		{
+			name: "return empty string",
+			resp: reverseGeocodingAddressResponse{
+				County: "County",
+			},
+			expectedRegion: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			region := test.resp.region()
+			assert.Equal(t, test.expectedRegion, region)
+		})
+	}
+}
+
+func TestNominatim_Ping(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for unsuccessful response",
+			statusCode:    http.StatusInternalServerError,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:          "return no error for successful response",
+			statusCode:    http.StatusOK,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, endpointStatus, r.URL.Path)
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			n := New(Config{
+				BaseURL: server.URL,
+			})
+
+			err := n.Ping(context.Background())
+			test.expectedErrFn(t, err)
+		})
+	}
+}
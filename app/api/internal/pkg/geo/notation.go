@@ -0,0 +1,119 @@
+package geo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidCoordinate is used when a coordinate could not be parsed.
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+)
+
+// ParseLatitude parses a latitude from the given string. Apart from decimal degrees
+// (e.g. "34.0522"), the string can be written in degrees and decimal minutes
+// (e.g. "34°03.13'N") or in degrees, minutes, and seconds (e.g. "34°03'08\"N")
+// notations. ErrInvalidCoordinate is returned when the string could not be parsed
+// or when the result is not a valid latitude.
+func ParseLatitude(s string) (float64, error) {
+	return parseCoordinate(s, 'N', 'S', IsLatitude)
+}
+
+// ParseLongitude parses a longitude from the given string. Apart from decimal
+// degrees (e.g. "-118.2437"), the string can be written in degrees and decimal
+// minutes (e.g. "118°14.62'W") or in degrees, minutes, and seconds (e.g.
+// "118°14'37\"W") notations. ErrInvalidCoordinate is returned when the string
+// could not be parsed or when the result is not a valid longitude.
+func ParseLongitude(s string) (float64, error) {
+	return parseCoordinate(s, 'E', 'W', IsLongitude)
+}
+
+func parseCoordinate(s string, positive, negative rune, isValid func(float64) bool) (float64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, ErrInvalidCoordinate
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if !isValid(f) {
+			return 0, ErrInvalidCoordinate
+		}
+		return f, nil
+	}
+
+	sign := 1.0
+
+	runes := []rune(s)
+	var hemisphere rune
+	switch {
+	case unicode.IsLetter(runes[0]):
+		hemisphere, runes = runes[0], runes[1:]
+	case unicode.IsLetter(runes[len(runes)-1]):
+		hemisphere, runes = runes[len(runes)-1], runes[:len(runes)-1]
+	}
+
+	switch hemisphere {
+	case 0, positive:
+	case negative:
+		sign = -1
+	default:
+		return 0, ErrInvalidCoordinate
+	}
+
+	s = strings.TrimSpace(string(runes))
+	if strings.HasPrefix(s, "-") {
+		// A negative value combined with a hemisphere letter is ambiguous.
+		if hemisphere != 0 {
+			return 0, ErrInvalidCoordinate
+		}
+		sign, s = -1, s[1:]
+	}
+
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '.' && !isNotationSeparator(r) {
+			return 0, ErrInvalidCoordinate
+		}
+	}
+
+	parts := strings.FieldsFunc(s, isNotationSeparator)
+	if len(parts) == 0 || len(parts) > 3 {
+		return 0, ErrInvalidCoordinate
+	}
+
+	values := make([]float64, 3)
+	for i, p := range parts {
+		// Only the last part is allowed to have a fractional component.
+		if i < len(parts)-1 && strings.Contains(p, ".") {
+			return 0, ErrInvalidCoordinate
+		}
+
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, ErrInvalidCoordinate
+		}
+		values[i] = v
+	}
+
+	degrees, minutes, seconds := values[0], values[1], values[2]
+	if minutes >= 60 || seconds >= 60 {
+		return 0, ErrInvalidCoordinate
+	}
+
+	f := sign * (degrees + minutes/60 + seconds/3600)
+	if !isValid(f) {
+		return 0, ErrInvalidCoordinate
+	}
+
+	return f, nil
+}
+
+func isNotationSeparator(r rune) bool {
+	switch r {
+	case ' ', ':', '°', 'º', '\'', '"', '′', '″', '’', '”':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,164 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
+)
+
+func TestParseLatitude(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		expected      float64
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for empty string",
+			s:             "",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for unparseable string",
+			s:             "latitude",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for out of range decimal degrees",
+			s:             "91.5",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for out of range degrees, minutes, and seconds",
+			s:             "91°00'00\"N",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for longitude hemisphere",
+			s:             "34°03'08\"E",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for negative value with hemisphere",
+			s:             "-34°03'08\"S",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for minutes greater than 59",
+			s:             "34°60'N",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for fractional degrees with minutes",
+			s:             "34.5°30'N",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for unexpected characters",
+			s:             "34°03'08\"N!",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return latitude for decimal degrees",
+			s:             "-34.0522",
+			expected:      -34.0522,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return latitude for degrees, minutes, and seconds",
+			s:             "34°03'08\"N",
+			expected:      34.05222,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return latitude for degrees, minutes, and seconds in southern hemisphere",
+			s:             "S 34 03 08",
+			expected:      -34.05222,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return latitude for degrees and decimal minutes",
+			s:             "34°03.5'N",
+			expected:      34.05833,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return latitude for degrees and minutes",
+			s:             "34°03'S",
+			expected:      -34.05,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseLatitude(test.s)
+			test.expectedErrFn(t, err)
+			assert.InDelta(t, test.expected, actual, 0.00001)
+		})
+	}
+}
+
+func TestParseLongitude(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		expected      float64
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error for latitude hemisphere",
+			s:             "118°14'37\"N",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return error for out of range decimal degrees",
+			s:             "-180.5",
+			expected:      0,
+			expectedErrFn: testutil.IsError(ErrInvalidCoordinate),
+		},
+		{
+			name:          "return longitude for decimal degrees",
+			s:             "-118.2437",
+			expected:      -118.2437,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return longitude for degrees, minutes, and seconds",
+			s:             "118°14'37.2\"W",
+			expected:      -118.24367,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return longitude for negative degrees, minutes, and seconds",
+			s:             "-118°14'37.2\"",
+			expected:      -118.24367,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return longitude for degrees and decimal minutes",
+			s:             "E 76°57.3'",
+			expected:      76.955,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseLongitude(test.s)
+			test.expectedErrFn(t, err)
+			assert.InDelta(t, test.expected, actual, 0.00001)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package media provides keeping files such as photos of spots apart from the
+// database, in storages that serve them to clients by URLs.
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey is used when a key of a file is not a clean relative path.
+var ErrInvalidKey = errors.New("invalid key")
+
+// Storage is anything that can keep files by keys, which are slash-separated
+// relative paths such as "spots/photo.jpg".
+type Storage interface {
+	// Put writes content of a file by the given key, replacing an existing file
+	// if any.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Delete removes a file by the given key. Missing files are not treated as
+	// errors.
+	Delete(ctx context.Context, key string) error
+
+	// URL returns an address at which a file by the given key is served.
+	URL(key string) string
+}
+
+// LocalStorage is a Storage that keeps files in a directory of the local file
+// system.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage returns a new *LocalStorage that keeps files in the given
+// directory, and reports their URLs relative to the given base URL, at which
+// Handler is expected to be served.
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put writes content of a file by the given key, replacing an existing file if
+// any. Content is written to a temporary file first, so that the file is never
+// served partially written.
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to change file mode: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a file by the given key. Missing files are not treated as
+// errors.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+
+	return nil
+}
+
+// URL returns an address at which a file by the given key is served.
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler returns an HTTP handler that serves files of the storage by their keys
+// relative to the root path. Directories are not listed.
+func (s *LocalStorage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := s.path(strings.TrimPrefix(r.URL.Path, "/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		info, err := os.Stat(name)
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.ServeFile(w, r, name)
+	})
+}
+
+// path returns a path of a file by the given key within the directory of the
+// storage.
+func (s *LocalStorage) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." || path.Clean(key) != key {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
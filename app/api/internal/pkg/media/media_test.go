@@ -0,0 +1,109 @@
+package media
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "/media/")
+	ctx := context.Background()
+
+	if !assert.NoError(t, s.Put(ctx, "spots/1.jpg", strings.NewReader("first"))) {
+		return
+	}
+	if !assert.NoError(t, s.Put(ctx, "spots/1.jpg", strings.NewReader("second"))) {
+		return
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "spots", "1.jpg"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "second", string(content))
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "spots"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, entries, 1, "temporary files must not be left behind")
+
+	assert.Equal(t, "/media/spots/1.jpg", s.URL("spots/1.jpg"))
+
+	assert.NoError(t, s.Delete(ctx, "spots/1.jpg"))
+	assert.NoError(t, s.Delete(ctx, "spots/1.jpg"))
+
+	_, err = os.Stat(filepath.Join(dir, "spots", "1.jpg"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "/media")
+
+	for _, key := range []string{"", ".", "../1.jpg", "/1.jpg", "spots/../1.jpg", "spots//1.jpg"} {
+		t.Run(key, func(t *testing.T) {
+			assert.ErrorIs(t, s.Put(context.Background(), key, strings.NewReader("content")), ErrInvalidKey)
+			assert.ErrorIs(t, s.Delete(context.Background(), key), ErrInvalidKey)
+		})
+	}
+}
+
+func TestLocalStorage_Handler(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "/media")
+	if !assert.NoError(t, s.Put(context.Background(), "spots/1.txt", strings.NewReader("content"))) {
+		return
+	}
+
+	server := httptest.NewServer(http.StripPrefix("/media", s.Handler()))
+	defer server.Close()
+
+	tests := []struct {
+		name               string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "serve existing file",
+			path:               "/media/spots/1.txt",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "content",
+		},
+		{
+			name:               "respond with 404 for missing file",
+			path:               "/media/spots/2.txt",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "respond with 404 for directory",
+			path:               "/media/spots",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + test.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			if test.expectedBody != "" {
+				body, err := ioutil.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -6,59 +6,254 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 )
 
+var (
+	// ErrEmptyInput is used when the input does not contain even a header.
+	ErrEmptyInput = errors.New("csv is empty: expected a header followed by spot rows")
+
+	// ErrInvalidHeader is used when the header does not list the expected columns.
+	ErrInvalidHeader = errors.New("invalid csv header")
+)
+
+var (
+	headerColumns = []string{"name", "latitude", "longitude", "locality", "country_code"}
+
+	// optionalHeaderColumns can follow headerColumns in the listed order. A column
+	// can be present only if all of the preceding ones are.
+	optionalHeaderColumns = []string{"external_ref", "tags"}
+)
+
+// DefaultTagsDelimiter separates tags within a single field unless configured
+// otherwise.
+const DefaultTagsDelimiter = "|"
+
+// SpotCreationEntrySource is a surf.SpotCreationEntrySource that streams entries
+// from CSV records one by one without reading the whole input into memory. The
+// first record is expected to be a header and gets validated and skipped.
 type SpotCreationEntrySource struct {
-	reader io.Reader
+	reader           io.Reader
+	csvReader        *csv.Reader
+	row              int
+	allowEmpty       bool
+	tagsDelimiter    string
+	parseLatitudeFn  func(string) (float64, error)
+	parseLongitudeFn func(string) (float64, error)
 }
 
-func NewSpotCreationEntrySource(r io.Reader) *SpotCreationEntrySource {
-	return &SpotCreationEntrySource{
-		reader: r,
+// NewSpotCreationEntrySource returns a new *SpotCreationEntrySource that reads CSV
+// records from the given reader using various options.
+func NewSpotCreationEntrySource(r io.Reader, opts ...SpotCreationEntrySourceOption) *SpotCreationEntrySource {
+	s := &SpotCreationEntrySource{
+		reader:           r,
+		tagsDelimiter:    DefaultTagsDelimiter,
+		parseLatitudeFn:  parseDecimalDegrees,
+		parseLongitudeFn: parseDecimalDegrees,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-func (s *SpotCreationEntrySource) SpotCreationEntries() ([]surf.SpotCreationEntry, error) {
-	records, err := csv.NewReader(s.reader).ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("could not read csv: %w", err)
+// SpotCreationEntrySourceOption is an optional function for SpotCreationEntrySource.
+type SpotCreationEntrySourceOption func(*SpotCreationEntrySource)
+
+// WithLenientCoordinates makes SpotCreationEntrySource additionally accept coordinates
+// written in degrees and decimal minutes or in degrees, minutes, and seconds notations.
+// By default, only decimal degrees are accepted.
+func WithLenientCoordinates() SpotCreationEntrySourceOption {
+	return func(s *SpotCreationEntrySource) {
+		s.parseLatitudeFn = geo.ParseLatitude
+		s.parseLongitudeFn = geo.ParseLongitude
 	}
+}
 
-	if len(records) <= 1 {
-		return nil, nil
+// WithEmptyInputAllowed makes SpotCreationEntrySource treat an input without a
+// header the same way as an input with a header only, which yields no entries.
+// By default, ErrEmptyInput is returned for such input.
+func WithEmptyInputAllowed() SpotCreationEntrySourceOption {
+	return func(s *SpotCreationEntrySource) {
+		s.allowEmpty = true
 	}
+}
 
-	var entries []surf.SpotCreationEntry
-	for _, r := range records[1:] {
-		if len(r) != 5 {
-			return nil, errors.New("invalid csv record: must contain exactly 3 fields")
-		}
+// WithTagsDelimiter makes SpotCreationEntrySource split tags using the given
+// delimiter instead of DefaultTagsDelimiter.
+func WithTagsDelimiter(d string) SpotCreationEntrySourceOption {
+	return func(s *SpotCreationEntrySource) {
+		s.tagsDelimiter = d
+	}
+}
+
+func parseDecimalDegrees(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// Next implements surf.SpotCreationEntrySource interface and returns an entry
+// parsed from the next CSV record. io.EOF is returned when there are no records
+// left, including when the input contains a header only. ErrEmptyInput and
+// ErrInvalidHeader are returned when the header is missing or malformed.
+// *surf.MalformedSpotCreationEntryError is returned when a record has an unexpected
+// number of fields or unparseable coordinates, in which case the rest of the
+// records can still be read. Tags are split by the delimiter but are otherwise
+// left as is.
+func (s *SpotCreationEntrySource) Next() (surf.SpotCreationEntry, error) {
+	if s.csvReader == nil {
+		s.csvReader = csv.NewReader(s.reader)
+		s.csvReader.ReuseRecord = true
+		// The number of fields is checked per record, so that a malformed record
+		// does not prevent the rest of them from being read.
+		s.csvReader.FieldsPerRecord = -1
 
-		lat, err := strconv.ParseFloat(r[1], 64)
+		header, err := s.read()
 		if err != nil {
-			return nil, fmt.Errorf("invalid latitide: %w", err)
+			if errors.Is(err, io.EOF) && !s.allowEmpty {
+				return surf.SpotCreationEntry{}, ErrEmptyInput
+			}
+			return surf.SpotCreationEntry{}, err
 		}
 
-		long, err := strconv.ParseFloat(r[2], 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid longitude: %w", err)
+		if err := validateHeader(header); err != nil {
+			return surf.SpotCreationEntry{}, err
 		}
+	}
 
-		entries = append(entries, surf.SpotCreationEntry{
-			Name: r[0],
-			Location: geo.Location{
-				Locality:    r[3],
-				CountryCode: r[4],
-				Coordinates: geo.Coordinates{
-					Latitude:  lat,
-					Longitude: long,
-				},
-			},
+	r, err := s.read()
+	if err != nil {
+		return surf.SpotCreationEntry{}, err
+	}
+
+	// Rows are numbered starting from the 1st row that follows the header.
+	s.row++
+	row := s.row
+
+	if len(r) < len(headerColumns) || len(r) > len(headerColumns)+len(optionalHeaderColumns) {
+		return surf.SpotCreationEntry{}, &surf.MalformedSpotCreationEntryError{
+			Row: row,
+			Fields: []surf.MalformedSpotCreationEntryField{{
+				Name: "record",
+				Err: fmt.Errorf(
+					"must contain from %d to %d fields",
+					len(headerColumns), len(headerColumns)+len(optionalHeaderColumns),
+				),
+			}},
+		}
+	}
+
+	var malformed []surf.MalformedSpotCreationEntryField
+
+	lat, err := s.parseLatitudeFn(r[1])
+	if err != nil {
+		malformed = append(malformed, surf.MalformedSpotCreationEntryField{
+			Name: "latitude",
+			Err:  err,
 		})
 	}
 
-	return entries, nil
+	long, err := s.parseLongitudeFn(r[2])
+	if err != nil {
+		malformed = append(malformed, surf.MalformedSpotCreationEntryField{
+			Name: "longitude",
+			Err:  err,
+		})
+	}
+
+	if len(malformed) > 0 {
+		return surf.SpotCreationEntry{}, &surf.MalformedSpotCreationEntryError{
+			Row:    row,
+			Fields: malformed,
+		}
+	}
+
+	var externalRef string
+	if len(r) > 5 {
+		externalRef = r[5]
+	}
+
+	var tags []string
+	if len(r) > 6 && r[6] != "" {
+		tags = strings.Split(r[6], s.tagsDelimiter)
+	}
+
+	return surf.SpotCreationEntry{
+		Name:        r[0],
+		ExternalRef: externalRef,
+		Tags:        tags,
+		Location: geo.Location{
+			Locality:    r[3],
+			CountryCode: r[4],
+			Coordinates: geo.Coordinates{
+				Latitude:  lat,
+				Longitude: long,
+			},
+		},
+	}, nil
+}
+
+func validateHeader(header []string) error {
+	valid := len(header) >= len(headerColumns) && len(header) <= len(headerColumns)+len(optionalHeaderColumns)
+	if valid {
+		for i, col := range header {
+			// Spreadsheet editors tend to prepend a byte order mark to the file.
+			if i == 0 {
+				col = strings.TrimPrefix(col, "\ufeff")
+			}
+
+			var expected string
+			if i < len(headerColumns) {
+				expected = headerColumns[i]
+			} else {
+				expected = optionalHeaderColumns[i-len(headerColumns)]
+			}
+
+			if !strings.EqualFold(strings.TrimSpace(col), expected) {
+				valid = false
+				break
+			}
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf(
+			"%w: expected %q optionally followed by %q, got %q",
+			ErrInvalidHeader,
+			strings.Join(headerColumns, ","),
+			strings.Join(optionalHeaderColumns, ","),
+			strings.Join(header, ","),
+		)
+	}
+
+	return nil
+}
+
+func (s *SpotCreationEntrySource) read() ([]string, error) {
+	r, err := s.csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("could not read csv: %w", err)
+	}
+	return r, nil
+}
+
+// Line returns a number of the line that the last read record is on, counting the
+// header as line 1. Records are expected not to span multiple lines.
+func (s *SpotCreationEntrySource) Line() int {
+	return s.row + 1
+}
+
+// Close closes the underlying reader if it implements io.Closer.
+func (s *SpotCreationEntrySource) Close() error {
+	if c, ok := s.reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
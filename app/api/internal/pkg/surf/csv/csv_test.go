@@ -27,9 +27,10 @@ func (m *mockReader) Read(b []byte) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func TestSpotCreationEntrySource_SpotCreationEntries(t *testing.T) {
+func TestSpotCreationEntrySource_Next(t *testing.T) {
 	tests := []struct {
 		name            string
+		opts            []SpotCreationEntrySourceOption
 		readerFn        func(t *testing.T) io.Reader
 		expectedEntries []surf.SpotCreationEntry
 		expectedErrFn   assert.ErrorAssertionFunc
@@ -47,13 +48,71 @@ func TestSpotCreationEntrySource_SpotCreationEntries(t *testing.T) {
 			expectedErrFn:   assert.Error,
 		},
 		{
-			name: "return 0 entries for empty csv",
+			name: "return error for empty csv",
+			readerFn: func(t *testing.T) io.Reader {
+				return strings.NewReader("")
+			},
+			expectedEntries: nil,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrEmptyInput)
+			},
+		},
+		{
+			name: "return 0 entries for empty csv when empty input is allowed",
+			opts: []SpotCreationEntrySourceOption{WithEmptyInputAllowed()},
 			readerFn: func(t *testing.T) io.Reader {
 				return strings.NewReader("")
 			},
 			expectedEntries: nil,
 			expectedErrFn:   assert.NoError,
 		},
+		{
+			name: "return error for csv with invalid header",
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/invalid_header.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: nil,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidHeader)
+			},
+		},
+		{
+			name: "return error for csv with tags header but without external reference header",
+			readerFn: func(t *testing.T) io.Reader {
+				return strings.NewReader(
+					"name,latitude,longitude,locality,country_code,tags\n" +
+						"Abrolhos Islands,-28.92683,113.97929,City Of Greater Geraldton,au,reef\n",
+				)
+			},
+			expectedEntries: nil,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidHeader)
+			},
+		},
+		{
+			name: "return entries for csv with byte order mark and capitalized header",
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/bom_header.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: []surf.SpotCreationEntry{
+				{
+					Name: "Abrolhos Islands",
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "City Of Greater Geraldton",
+						Coordinates: geo.Coordinates{
+							Latitude:  -28.92683,
+							Longitude: 113.97929,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
 		{
 			name: "return 0 entries for csv with 0 rows",
 			readerFn: func(t *testing.T) io.Reader {
@@ -65,14 +124,18 @@ func TestSpotCreationEntrySource_SpotCreationEntries(t *testing.T) {
 			expectedErrFn:   assert.NoError,
 		},
 		{
-			name: "return error for csv with invalid columns",
+			name: "return malformed entry error for csv with invalid columns",
 			readerFn: func(t *testing.T) io.Reader {
 				b, err := ioutil.ReadFile("testdata/invalid_columns.csv")
 				assert.NoError(t, err)
 				return bytes.NewReader(b)
 			},
 			expectedEntries: nil,
-			expectedErrFn:   assert.Error,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var malformedErr *surf.MalformedSpotCreationEntryError
+				return assert.True(t, errors.As(err, &malformedErr)) &&
+					assert.EqualError(t, err, "invalid record on row 1: must contain from 5 to 7 fields")
+			},
 		},
 		{
 			name: "return error for csv with invalid latitude",
@@ -127,12 +190,173 @@ func TestSpotCreationEntrySource_SpotCreationEntries(t *testing.T) {
 			},
 			expectedErrFn: assert.NoError,
 		},
+		{
+			name: "return entries with external references without error",
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/valid_external_refs.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: []surf.SpotCreationEntry{
+				{
+					Name:        "Abrolhos Islands",
+					ExternalRef: "au-1",
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "City Of Greater Geraldton",
+						Coordinates: geo.Coordinates{
+							Latitude:  -28.92683,
+							Longitude: 113.97929,
+						},
+					},
+				},
+				{
+					Name:        "Cables",
+					ExternalRef: "",
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "Town of Mosman Park",
+						Coordinates: geo.Coordinates{
+							Latitude:  -32.01783,
+							Longitude: 115.7512,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return entries with tags without error",
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/valid_tags.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: []surf.SpotCreationEntry{
+				{
+					Name:        "Abrolhos Islands",
+					ExternalRef: "au-1",
+					Tags:        []string{"reef", "Left Hand", " reef"},
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "City Of Greater Geraldton",
+						Coordinates: geo.Coordinates{
+							Latitude:  -28.92683,
+							Longitude: 113.97929,
+						},
+					},
+				},
+				{
+					Name:        "Cables",
+					ExternalRef: "",
+					Tags:        nil,
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "Town of Mosman Park",
+						Coordinates: geo.Coordinates{
+							Latitude:  -32.01783,
+							Longitude: 115.7512,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return entries with tags split by custom delimiter without error",
+			opts: []SpotCreationEntrySourceOption{WithTagsDelimiter(";")},
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/valid_tags_semicolon.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: []surf.SpotCreationEntry{
+				{
+					Name:        "Abrolhos Islands",
+					ExternalRef: "au-1",
+					Tags:        []string{"reef", "left hand"},
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "City Of Greater Geraldton",
+						Coordinates: geo.Coordinates{
+							Latitude:  -28.92683,
+							Longitude: 113.97929,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return row-numbered error for csv with non-decimal coordinates by default",
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/dms.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: nil,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.EqualError(t, err, `invalid latitude on row 1: strconv.ParseFloat: parsing "28°55'36.6\"S": invalid syntax; `+
+					`invalid longitude on row 1: strconv.ParseFloat: parsing "113°58.7574'E": invalid syntax`)
+			},
+		},
+		{
+			name: "return row-numbered error for csv with unparseable coordinates in lenient mode",
+			opts: []SpotCreationEntrySourceOption{WithLenientCoordinates()},
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/invalid_dms.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: nil,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var malformedErr *surf.MalformedSpotCreationEntryError
+				return assert.True(t, errors.As(err, &malformedErr)) &&
+					assert.Len(t, malformedErr.Fields, 1) &&
+					assert.ErrorIs(t, malformedErr.Fields[0].Err, geo.ErrInvalidCoordinate) &&
+					assert.EqualError(t, err, "invalid latitude on row 2: invalid coordinate")
+			},
+		},
+		{
+			name: "return entries for csv with non-decimal coordinates in lenient mode",
+			opts: []SpotCreationEntrySourceOption{WithLenientCoordinates()},
+			readerFn: func(t *testing.T) io.Reader {
+				b, err := ioutil.ReadFile("testdata/dms.csv")
+				assert.NoError(t, err)
+				return bytes.NewReader(b)
+			},
+			expectedEntries: []surf.SpotCreationEntry{
+				{
+					Name: "Abrolhos Islands",
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "City Of Greater Geraldton",
+						Coordinates: geo.Coordinates{
+							Latitude:  -28.926833333333335,
+							Longitude: 113.97929,
+						},
+					},
+				},
+				{
+					Name: "Cables",
+					Location: geo.Location{
+						CountryCode: "au",
+						Locality:    "Town of Mosman Park",
+						Coordinates: geo.Coordinates{
+							Latitude:  -32.01783,
+							Longitude: 115.7512,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s := NewSpotCreationEntrySource(test.readerFn(t))
-			entries, err := s.SpotCreationEntries()
+			s := NewSpotCreationEntrySource(test.readerFn(t), test.opts...)
+			entries, err := surf.ReadAllSpotCreationEntries(s)
 			test.expectedErrFn(t, err)
 			assert.Equal(t, test.expectedEntries, entries)
 		})
@@ -0,0 +1,1650 @@
+// Package inmemory provides a spot store that keeps spots in memory, which is
+// useful for running the API locally without a database and for tests.
+package inmemory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/slug"
+)
+
+// maxSearchQueryChars mirrors the search query limit of the PostgreSQL store.
+const maxSearchQueryChars = 100
+
+// SpotStore is a spot store that keeps spots in memory. It mirrors the behavior of
+// the PostgreSQL store closely enough to be used in its place, with the following
+// differences:
+//
+//   - Spots are read in the order of their creation unless a certain order is
+//     requested, whereas PostgreSQL does not guarantee any order.
+//   - The full-text search matches whole words of the query case-insensitively,
+//     and ranks spots by a number of matched words, but does not support the
+//     operators of websearch_to_tsquery such as quotes and OR.
+//
+// Spots are lost once the process exits.
+type SpotStore struct {
+	now func() time.Time
+
+	mu    sync.RWMutex
+	spots map[string]*surf.Spot
+	ids   []string
+
+	// referencePoints and referenceDistances are keyed by reference sets, and
+	// the latter are additionally keyed by spot IDs.
+	referencePoints    map[string][]surf.ReferencePoint
+	referenceDistances map[string]map[string]surf.ReferenceDistance
+
+	// photos are keyed by spot IDs and ordered from the oldest one.
+	photos map[string][]surf.SpotPhoto
+
+	// ratings hold scores keyed by spot IDs, and additionally by raters and days.
+	ratings map[string]map[ratingKey]int
+
+	// favorites hold IDs of spots keyed by user IDs and ordered from the least
+	// recently favorited one.
+	favorites map[string][]string
+
+	// suggestions are ordered from the oldest one.
+	suggestions []surf.SpotSuggestion
+
+	// auditLog is ordered from the oldest entry.
+	auditLog []surf.AuditEntry
+
+	// idempotencyKeys hold spots that were created with idempotency keys, keyed by
+	// the keys.
+	idempotencyKeys map[string]idempotencyKey
+
+	reserveDeletedSlugs bool
+}
+
+type idempotencyKey struct {
+	spotID    string
+	createdAt time.Time
+}
+
+type ratingKey struct {
+	raterKey string
+	ratedOn  string
+}
+
+// NewSpotStore returns a new empty *SpotStore using various options.
+func NewSpotStore(opts ...SpotStoreOption) *SpotStore {
+	ss := &SpotStore{
+		now: func() time.Time {
+			// PostgreSQL keeps timestamps with a precision of microseconds.
+			return time.Now().UTC().Truncate(time.Microsecond)
+		},
+		spots:              make(map[string]*surf.Spot),
+		referencePoints:    make(map[string][]surf.ReferencePoint),
+		referenceDistances: make(map[string]map[string]surf.ReferenceDistance),
+		photos:             make(map[string][]surf.SpotPhoto),
+		ratings:            make(map[string]map[ratingKey]int),
+		favorites:          make(map[string][]string),
+		idempotencyKeys:    make(map[string]idempotencyKey),
+	}
+
+	for _, opt := range opts {
+		opt(ss)
+	}
+
+	return ss
+}
+
+// SpotStoreOption is an optional function for SpotStore.
+type SpotStoreOption func(*SpotStore)
+
+// WithDeletedSlugsReserved keeps slugs of soft-deleted spots reserved in the same
+// way as in the PostgreSQL store.
+func WithDeletedSlugsReserved() SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.reserveDeletedSlugs = true
+	}
+}
+
+// Spot returns a spot by the given ID. surf.ErrSpotNotFound is returned when spot
+// is not found or soft-deleted.
+func (ss *SpotStore) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	s, ok := ss.spots[id]
+	if !ok || s.DeletedAt != nil {
+		return surf.Spot{}, surf.ErrSpotNotFound
+	}
+
+	spot := toSpot(s)
+	spot.Ratings = ss.spotRatings(id)
+	return spot, nil
+}
+
+// SpotBySlug returns a spot by the given slug. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	for _, id := range ss.ids {
+		s := ss.spots[id]
+		if s.DeletedAt == nil && s.Slug != "" && strings.EqualFold(s.Slug, slug) {
+			spot := toSpot(s)
+			spot.Ratings = ss.spotRatings(id)
+			return spot, nil
+		}
+	}
+
+	return surf.Spot{}, surf.ErrSpotNotFound
+}
+
+// uniqueSlug returns the given slug suffixed with -2, -3, and so on when it is
+// taken by a spot other than the one by the given ID, ignoring case. Like in the
+// PostgreSQL store, slugs of soft-deleted spots are taken into account only when
+// they are reserved.
+func (ss *SpotStore) uniqueSlug(base, spotID string) string {
+	var taken []string
+	for id, s := range ss.spots {
+		if id == spotID || s.Slug == "" || (s.DeletedAt != nil && !ss.reserveDeletedSlugs) {
+			continue
+		}
+		taken = append(taken, strings.ToLower(s.Slug))
+	}
+	return slug.Unique(strings.ToLower(base), taken)
+}
+
+// slugTaken reports whether a spot other than the one by the given ID that is not
+// soft-deleted has the given slug, ignoring case.
+func (ss *SpotStore) slugTaken(candidate, spotID string) bool {
+	for id, s := range ss.spots {
+		if id != spotID && s.DeletedAt == nil && strings.EqualFold(s.Slug, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Spots returns multiple spots that match the given parameters. Soft-deleted spots
+// are excluded unless they are explicitly requested.
+func (ss *SpotStore) Spots(ctx context.Context, p surf.SpotsParams) ([]surf.Spot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	matched := ss.matchSpots(p)
+	matched = paginate(matched, p.Limit, p.Offset)
+
+	var spots []surf.Spot
+	for _, s := range matched {
+		spot := toSpot(s)
+		spot.Ratings = ss.spotRatings(s.ID)
+		if p.IncludeDeleted {
+			spot.DeletedAt = copyTime(s.DeletedAt)
+		}
+		if d, ok := ss.referenceDistances[p.ReferenceSet][s.ID]; ok && p.ReferenceSet != "" {
+			spot.NearestReference = &d
+		}
+		spots = append(spots, spot)
+	}
+
+	return spots, nil
+}
+
+// matchSpots returns spots that match the given parameters in the requested
+// order, without applying the limit and the offset.
+func (ss *SpotStore) matchSpots(p surf.SpotsParams) []*surf.Spot {
+	var (
+		matched []*surf.Spot
+		ranks   = make(map[string]int)
+	)
+	for _, id := range ss.ids {
+		s := ss.spots[id]
+		if s.DeletedAt != nil && !p.IncludeDeleted {
+			continue
+		}
+		if !matchesFilters(s, p.CountryCode, p.Bounds) || !matchesTags(s, p.Tags, p.TagsMatch) {
+			continue
+		}
+
+		rank, ok := matchQuery(s, p.SearchQuery)
+		if !ok {
+			continue
+		}
+		ranks[s.ID] = rank
+		matched = append(matched, s)
+	}
+
+	switch {
+	case p.Order == surf.SpotOrderReferenceDistance && p.ReferenceSet != "":
+		distances := ss.referenceDistances[p.ReferenceSet]
+		sort.SliceStable(matched, func(i, j int) bool {
+			di, iok := distances[matched[i].ID]
+			dj, jok := distances[matched[j].ID]
+			if iok != jok {
+				return iok
+			}
+			if iok && di.DistanceKm != dj.DistanceKm {
+				return di.DistanceKm < dj.DistanceKm
+			}
+			return matched[i].ID < matched[j].ID
+		})
+	case p.Order == surf.SpotOrderUpdatedAtDesc:
+		sort.SliceStable(matched, func(i, j int) bool {
+			if !matched[i].UpdatedAt.Equal(matched[j].UpdatedAt) {
+				return matched[i].UpdatedAt.After(matched[j].UpdatedAt)
+			}
+			return matched[i].ID < matched[j].ID
+		})
+	case p.SearchQuery.FullText && p.SearchQuery.Query != "":
+		sort.SliceStable(matched, func(i, j int) bool {
+			if ranks[matched[i].ID] != ranks[matched[j].ID] {
+				return ranks[matched[i].ID] > ranks[matched[j].ID]
+			}
+			return matched[i].ID < matched[j].ID
+		})
+	}
+
+	return matched
+}
+
+// IterateSpots calls the given function for every spot that matches the given
+// parameters in the order of their IDs. The function is called on a snapshot of
+// the spots, so it is safe for it to write to the store.
+func (ss *SpotStore) IterateSpots(ctx context.Context, p surf.SpotIterationParams, fn func(surf.Spot) error) error {
+	ss.mu.RLock()
+	matched := ss.matchSpots(surf.SpotsParams{
+		CountryCode: p.CountryCode,
+		SearchQuery: p.SearchQuery,
+		Bounds:      p.Bounds,
+	})
+
+	spots := make([]surf.Spot, len(matched))
+	for i, s := range matched {
+		spots[i] = toSpot(s)
+		spots[i].ExternalRef = s.ExternalRef
+	}
+	ss.mu.RUnlock()
+
+	sort.Slice(spots, func(i, j int) bool {
+		return spots[i].ID < spots[j].ID
+	})
+
+	for _, s := range spots {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExplainSpots returns a plan, in the JSON format of PostgreSQL, that describes a
+// scan of the spots held in memory along with a number of spots that match the
+// given parameters.
+func (ss *SpotStore) ExplainSpots(ctx context.Context, p surf.SpotsParams) ([]byte, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	plan := []map[string]interface{}{
+		{
+			"Plan": map[string]interface{}{
+				"Node Type": "In-Memory Scan",
+				"Plan Rows": len(paginate(ss.matchSpots(p), p.Limit, p.Offset)),
+			},
+		},
+	}
+
+	b, err := json.Marshal(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	return b, nil
+}
+
+// CountriesWithSpotCounts returns countries of spots that match the given parameters
+// along with numbers of such spots in them, ordered from the country with the most
+// spots.
+func (ss *SpotStore) CountriesWithSpotCounts(ctx context.Context, p surf.CountriesParams) ([]surf.CountrySpotCount, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, s := range ss.matchSpots(surf.SpotsParams{SearchQuery: p.SearchQuery}) {
+		counts[s.Location.CountryCode]++
+	}
+
+	var countries []surf.CountrySpotCount
+	for code, count := range counts {
+		countries = append(countries, surf.CountrySpotCount{
+			CountryCode: code,
+			Count:       count,
+		})
+	}
+
+	sort.Slice(countries, func(i, j int) bool {
+		if countries[i].Count != countries[j].Count {
+			return countries[i].Count > countries[j].Count
+		}
+		return countries[i].CountryCode < countries[j].CountryCode
+	})
+
+	return countries, nil
+}
+
+// SpotDirectory returns countries of spots ordered by their codes, along with their
+// localities and spots limited according to the given parameters.
+func (ss *SpotStore) SpotDirectory(ctx context.Context, p surf.SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	grouped := make(map[string]map[string][]*surf.Spot)
+	for _, s := range ss.matchSpots(surf.SpotsParams{}) {
+		code := s.Location.CountryCode
+		if grouped[code] == nil {
+			grouped[code] = make(map[string][]*surf.Spot)
+		}
+		grouped[code][s.Location.Locality] = append(grouped[code][s.Location.Locality], s)
+	}
+
+	codes := make([]string, 0, len(grouped))
+	for code := range grouped {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	if p.Offset >= len(codes) {
+		return nil, nil
+	}
+	codes = codes[p.Offset:]
+	if p.Limit < len(codes) {
+		codes = codes[:p.Limit]
+	}
+
+	var countries []surf.DirectoryCountry
+	for _, code := range codes {
+		localities := make([]string, 0, len(grouped[code]))
+		for l := range grouped[code] {
+			localities = append(localities, l)
+		}
+		sort.Strings(localities)
+
+		c := surf.DirectoryCountry{
+			CountryCode:   code,
+			LocalityCount: len(localities),
+		}
+		for i, l := range localities {
+			spots := grouped[code][l]
+			c.SpotCount += len(spots)
+
+			if i >= p.LocalitiesPerCountry {
+				continue
+			}
+
+			sort.SliceStable(spots, func(i, j int) bool {
+				if spots[i].Name != spots[j].Name {
+					return spots[i].Name < spots[j].Name
+				}
+				return spots[i].ID < spots[j].ID
+			})
+
+			dl := surf.DirectoryLocality{
+				Locality:  l,
+				SpotCount: len(spots),
+			}
+			for _, s := range paginate(spots, p.SpotsPerLocality, 0) {
+				dl.Spots = append(dl.Spots, surf.Spot{
+					ID:   s.ID,
+					Name: s.Name,
+					Location: geo.Location{
+						Locality:    s.Location.Locality,
+						CountryCode: s.Location.CountryCode,
+						Coordinates: s.Location.Coordinates,
+					},
+				})
+			}
+			c.Localities = append(c.Localities, dl)
+		}
+
+		// Like in the PostgreSQL store, countries without any localities or spots
+		// to show are left out.
+		if len(c.Localities) == 0 || len(c.Localities[0].Spots) == 0 {
+			continue
+		}
+		countries = append(countries, c)
+	}
+
+	return countries, nil
+}
+
+// SpotCreationCounts returns numbers of spots created within time buckets of the
+// given interval, ordered from the earliest bucket. Buckets without any spots
+// created are omitted.
+func (ss *SpotStore) SpotCreationCounts(ctx context.Context, p surf.SpotCreationCountsParams) ([]surf.SpotCreationCount, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	counts := make(map[time.Time]int)
+	for _, s := range ss.matchSpots(surf.SpotsParams{}) {
+		if s.CreatedAt.Before(p.From) || !s.CreatedAt.Before(p.To) {
+			continue
+		}
+		counts[bucketStart(s.CreatedAt, p.Interval)]++
+	}
+
+	var buckets []surf.SpotCreationCount
+	for start, count := range counts {
+		buckets = append(buckets, surf.SpotCreationCount{
+			BucketStart: start,
+			Count:       count,
+		})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].BucketStart.Before(buckets[j].BucketStart)
+	})
+
+	return buckets, nil
+}
+
+// bucketStart truncates the given time to the beginning of its bucket in the same
+// way as PostgreSQL's date_trunc does, with weeks starting on Mondays.
+func bucketStart(t time.Time, i surf.TimeInterval) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch i {
+	case surf.TimeIntervalDay:
+		return day
+	case surf.TimeIntervalWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Localities returns distinct localities of spots that match the given parameters
+// in alphabetical order. The prefix is matched literally and case-insensitively.
+func (ss *SpotStore) Localities(ctx context.Context, p surf.LocalitiesParams) ([]string, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var (
+		localities []string
+		seen       = make(map[string]bool)
+		prefix     = strings.ToLower(p.Prefix)
+	)
+	for _, s := range ss.matchSpots(surf.SpotsParams{CountryCode: p.CountryCode}) {
+		l := s.Location.Locality
+		if seen[l] || !strings.HasPrefix(strings.ToLower(l), prefix) {
+			continue
+		}
+		seen[l] = true
+		localities = append(localities, l)
+	}
+
+	sort.Strings(localities)
+
+	if len(localities) > p.Limit {
+		localities = localities[:p.Limit]
+	}
+	if len(localities) == 0 {
+		return nil, nil
+	}
+
+	return localities, nil
+}
+
+// SpotsCentroid returns the average coordinates of spots that match the given
+// parameters. Nil is returned when no spots match. Longitudes are averaged as
+// angles in the same way as the PostgreSQL store does.
+func (ss *SpotStore) SpotsCentroid(ctx context.Context, p surf.SpotsCentroidParams) (*geo.Coordinates, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	matched := ss.matchSpots(surf.SpotsParams{
+		CountryCode: p.CountryCode,
+		SearchQuery: p.SearchQuery,
+		Bounds:      p.Bounds,
+	})
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	var lat, lonSin, lonCos float64
+	for _, s := range matched {
+		lon := s.Location.Coordinates.Longitude * math.Pi / 180
+		lat += s.Location.Coordinates.Latitude
+		lonSin += math.Sin(lon)
+		lonCos += math.Cos(lon)
+	}
+
+	n := float64(len(matched))
+	return &geo.Coordinates{
+		Latitude:  lat / n,
+		Longitude: math.Atan2(lonSin/n, lonCos/n) * 180 / math.Pi,
+	}, nil
+}
+
+// RandomSpots returns multiple randomly picked spots that match the given parameters.
+func (ss *SpotStore) RandomSpots(ctx context.Context, p surf.RandomSpotsParams) ([]surf.Spot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	matched := ss.matchSpots(surf.SpotsParams{CountryCode: p.CountryCode})
+	mathrand.Shuffle(len(matched), func(i, j int) {
+		matched[i], matched[j] = matched[j], matched[i]
+	})
+
+	var spots []surf.Spot
+	for _, s := range paginate(matched, p.Limit, 0) {
+		spots = append(spots, toSpot(s))
+	}
+
+	return spots, nil
+}
+
+// NearbySpots returns multiple spots located within the given radius from the given
+// point, ordered by their distance from the point.
+func (ss *SpotStore) NearbySpots(ctx context.Context, p surf.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var spots []surf.NearbySpot
+	for _, s := range ss.matchSpots(surf.SpotsParams{}) {
+		d := geo.DistanceKm(p.Coordinates, s.Location.Coordinates)
+		if d > p.RadiusKm {
+			continue
+		}
+		spots = append(spots, surf.NearbySpot{
+			Spot:       toSpot(s),
+			DistanceKm: d,
+		})
+	}
+
+	sort.SliceStable(spots, func(i, j int) bool {
+		if spots[i].DistanceKm != spots[j].DistanceKm {
+			return spots[i].DistanceKm < spots[j].DistanceKm
+		}
+		return spots[i].ID < spots[j].ID
+	})
+
+	if len(spots) > p.Limit {
+		spots = spots[:p.Limit]
+	}
+	if len(spots) == 0 {
+		return nil, nil
+	}
+
+	return spots, nil
+}
+
+// SpotMarkers returns markers of multiple spots that match the given parameters.
+func (ss *SpotStore) SpotMarkers(ctx context.Context, p surf.SpotMarkersParams) ([]surf.SpotMarker, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var markers []surf.SpotMarker
+	for _, s := range paginate(ss.matchSpots(surf.SpotsParams{Bounds: p.Bounds}), p.Limit, 0) {
+		markers = append(markers, surf.SpotMarker{
+			ID:          s.ID,
+			Coordinates: s.Location.Coordinates,
+		})
+	}
+
+	return markers, nil
+}
+
+// CreateSpot creates a new spot using the given entry and returns it if the
+// creation succeeds. Like in the PostgreSQL store, neither tags nor the external
+// reference of the entry are stored, and a slug of the entry is suffixed when it is
+// taken by another spot.
+func (ss *SpotStore) CreateSpot(ctx context.Context, e surf.SpotCreationEntry) (surf.Spot, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.createSpot(ctx, e)
+}
+
+// createSpot creates a new spot in the same way as CreateSpot does, expecting the
+// store to be locked by the caller.
+func (ss *SpotStore) createSpot(ctx context.Context, e surf.SpotCreationEntry) (surf.Spot, error) {
+	e.Tags = nil
+	e.ExternalRef = ""
+
+	s, err := ss.insert(e, ss.now())
+	if err != nil {
+		return surf.Spot{}, err
+	}
+	if e.Slug != "" {
+		s.Slug = ss.uniqueSlug(e.Slug, s.ID)
+	}
+
+	if err := ss.audit(ctx, surf.AuditActionCreate, s.ID, surf.SpotChanges(nil, s)); err != nil {
+		delete(ss.spots, s.ID)
+		ss.ids = ss.ids[:len(ss.ids)-1]
+		return surf.Spot{}, err
+	}
+
+	return toSpot(s), nil
+}
+
+// CreateSpots creates multiple new spots using the given entries and mode, and
+// returns statistics about the affected spots. Either all or none of the entries
+// are applied.
+//
+// In surf.SpotCreationModeUpsert mode, entries are matched against existing spots
+// in the same way as in the PostgreSQL store: by external references, or by IDs of
+// spots without an external reference, ignoring soft-deleted spots. When several
+// entries share the same external reference, only the last one of them is used.
+// In surf.SpotCreationModeInsert mode, an entry whose external reference is taken
+// by another spot fails the whole creation.
+func (ss *SpotStore) CreateSpots(ctx context.Context, entries []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+	if len(entries) == 0 {
+		return surf.SpotCreationStats{}, errors.New("no entries")
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if mode == surf.SpotCreationModeUpsert {
+		return ss.upsertSpots(ctx, dedupeByExternalRef(entries))
+	}
+
+	refs := make(map[string]bool)
+	for _, e := range entries {
+		if e.ExternalRef == "" {
+			continue
+		}
+		if refs[e.ExternalRef] || ss.spotByExternalRef(e.ExternalRef) != nil {
+			return surf.SpotCreationStats{}, fmt.Errorf("duplicate external reference: %q", e.ExternalRef)
+		}
+		refs[e.ExternalRef] = true
+	}
+
+	now := ss.now()
+	for _, e := range entries {
+		s, err := ss.insert(e, now)
+		if err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+		if err := ss.audit(ctx, surf.AuditActionCreate, s.ID, surf.SpotChanges(nil, s)); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+	}
+
+	return surf.SpotCreationStats{
+		Inserted: len(entries),
+	}, nil
+}
+
+func (ss *SpotStore) upsertSpots(ctx context.Context, entries []surf.SpotCreationEntry) (surf.SpotCreationStats, error) {
+	now := ss.now()
+
+	for _, e := range entries {
+		if e.ExternalRef == "" {
+			continue
+		}
+		if s, ok := ss.spots[e.ExternalRef]; ok && s.DeletedAt == nil && s.ExternalRef == "" {
+			s.ExternalRef = s.ID
+		}
+	}
+
+	var stats surf.SpotCreationStats
+	for _, e := range entries {
+		var s *surf.Spot
+		if e.ExternalRef != "" {
+			s = ss.spotByExternalRef(e.ExternalRef)
+		}
+
+		if s == nil {
+			s, err := ss.insert(e, now)
+			if err != nil {
+				return surf.SpotCreationStats{}, err
+			}
+			if err := ss.audit(ctx, surf.AuditActionCreate, s.ID, surf.SpotChanges(nil, s)); err != nil {
+				return surf.SpotCreationStats{}, err
+			}
+			stats.Inserted++
+			continue
+		}
+
+		before := *s
+		s.Name = e.Name
+		s.Location.Coordinates = e.Location.Coordinates
+		s.Location.Locality = e.Location.Locality
+		s.Location.CountryCode = e.Location.CountryCode
+		s.UpdatedAt = now
+		if len(e.Tags) > 0 {
+			s.Tags = normalizeTags(e.Tags)
+		}
+		if err := ss.audit(ctx, surf.AuditActionUpdate, s.ID, surf.SpotChanges(&before, s)); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+		stats.Updated++
+	}
+
+	return stats, nil
+}
+
+// dedupeByExternalRef removes entries that share the same external reference
+// keeping only the last one of them at the position of the first one.
+func dedupeByExternalRef(entries []surf.SpotCreationEntry) []surf.SpotCreationEntry {
+	var (
+		deduped = make([]surf.SpotCreationEntry, 0, len(entries))
+		indices = make(map[string]int)
+	)
+
+	for _, e := range entries {
+		if e.ExternalRef == "" {
+			deduped = append(deduped, e)
+			continue
+		}
+
+		if i, ok := indices[e.ExternalRef]; ok {
+			deduped[i] = e
+			continue
+		}
+
+		indices[e.ExternalRef] = len(deduped)
+		deduped = append(deduped, e)
+	}
+
+	return deduped
+}
+
+// spotByExternalRef returns a spot that is not soft-deleted and has the given
+// external reference, or nil if there is no such spot.
+func (ss *SpotStore) spotByExternalRef(ref string) *surf.Spot {
+	for _, id := range ss.ids {
+		s := ss.spots[id]
+		if s.DeletedAt == nil && s.ExternalRef == ref {
+			return s
+		}
+	}
+	return nil
+}
+
+func (ss *SpotStore) insert(e surf.SpotCreationEntry, now time.Time) (*surf.Spot, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &surf.Spot{
+		ID:          id,
+		Name:        e.Name,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ExternalRef: e.ExternalRef,
+		Tags:        normalizeTags(e.Tags),
+		Location: geo.Location{
+			Coordinates: e.Location.Coordinates,
+			Locality:    e.Location.Locality,
+			CountryCode: e.Location.CountryCode,
+		},
+	}
+
+	ss.spots[id] = s
+	ss.ids = append(ss.ids, id)
+
+	return s, nil
+}
+
+// UpdateSpot updates an existing spot using the given entry and returns it if the
+// update succeeds. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted. A slug of the entry is suffixed when it is taken by another spot,
+// and an empty one clears the slug.
+func (ss *SpotStore) UpdateSpot(ctx context.Context, p surf.SpotUpdateEntry) (surf.Spot, error) {
+	if p.Name == nil && p.Latitude == nil && p.Longitude == nil &&
+		p.Locality == nil && p.CountryCode == nil && p.Region == nil && p.Slug == nil {
+		return surf.Spot{}, surf.ErrEmptySpotUpdateEntry
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[p.ID]
+	if !ok || s.DeletedAt != nil {
+		return surf.Spot{}, surf.ErrSpotNotFound
+	}
+
+	before := *s
+
+	if p.Name != nil {
+		s.Name = *p.Name
+	}
+	if p.Latitude != nil {
+		s.Location.Coordinates.Latitude = *p.Latitude
+	}
+	if p.Longitude != nil {
+		s.Location.Coordinates.Longitude = *p.Longitude
+	}
+	if p.Locality != nil {
+		s.Location.Locality = *p.Locality
+	}
+	if p.CountryCode != nil {
+		s.Location.CountryCode = *p.CountryCode
+	}
+	if p.Region != nil {
+		s.Location.Region = *p.Region
+	}
+	if p.Slug != nil {
+		s.Slug = ""
+		if *p.Slug != "" {
+			s.Slug = ss.uniqueSlug(*p.Slug, s.ID)
+		}
+	}
+	s.UpdatedAt = ss.now()
+
+	if err := ss.audit(ctx, surf.AuditActionUpdate, s.ID, surf.SpotChanges(&before, s)); err != nil {
+		*s = before
+		return surf.Spot{}, err
+	}
+
+	return toSpot(s), nil
+}
+
+// DeleteSpot soft-deletes a spot by the given ID, so that it can be restored later.
+// surf.ErrSpotNotFound is returned when spot is not found or already soft-deleted.
+func (ss *SpotStore) DeleteSpot(ctx context.Context, id string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[id]
+	if !ok || s.DeletedAt != nil {
+		return surf.ErrSpotNotFound
+	}
+
+	if err := ss.audit(ctx, surf.AuditActionDelete, s.ID, surf.SpotChanges(s, nil)); err != nil {
+		return err
+	}
+
+	now := ss.now()
+	s.DeletedAt = &now
+
+	return nil
+}
+
+// audit appends an entry with the given changes of a spot by the given ID to the
+// audit log when the context holds an audit actor. It must be called while holding
+// the lock.
+func (ss *SpotStore) audit(ctx context.Context, action surf.AuditAction, spotID string, changes []surf.AuditChange) error {
+	actorID, ok := surf.AuditActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	ss.auditLog = append(ss.auditLog, surf.AuditEntry{
+		ID:        id,
+		ActorID:   actorID,
+		Action:    action,
+		SpotID:    spotID,
+		Changes:   changes,
+		CreatedAt: ss.now(),
+	})
+
+	return nil
+}
+
+// AuditEntries returns audit entries that match the given parameters, from the
+// most recently created one.
+func (ss *SpotStore) AuditEntries(ctx context.Context, p surf.AuditEntriesParams) ([]surf.AuditEntry, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var matched []surf.AuditEntry
+	for i := len(ss.auditLog) - 1; i >= 0; i-- {
+		if matchesAuditEntry(ss.auditLog[i], p) {
+			matched = append(matched, ss.auditLog[i])
+		}
+	}
+
+	if p.Offset >= len(matched) {
+		return nil, nil
+	}
+	matched = matched[p.Offset:]
+	if p.Limit < len(matched) {
+		matched = matched[:p.Limit]
+	}
+
+	entries := make([]surf.AuditEntry, len(matched))
+	for i, e := range matched {
+		e.Changes = append([]surf.AuditChange(nil), e.Changes...)
+		entries[i] = e
+	}
+
+	return entries, nil
+}
+
+func matchesAuditEntry(e surf.AuditEntry, p surf.AuditEntriesParams) bool {
+	if p.SpotID != "" && e.SpotID != p.SpotID {
+		return false
+	}
+	if p.ActorID != "" && e.ActorID != p.ActorID {
+		return false
+	}
+	if !p.From.IsZero() && e.CreatedAt.Before(p.From) {
+		return false
+	}
+	if !p.To.IsZero() && !e.CreatedAt.Before(p.To) {
+		return false
+	}
+	return true
+}
+
+// DeleteSpots soft-deletes multiple spots by the given IDs and returns IDs of the
+// spots that got deleted. IDs of spots that are not found or are already
+// soft-deleted are skipped.
+func (ss *SpotStore) DeleteSpots(ctx context.Context, ids []string) ([]string, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var (
+		deleted []string
+		now     = ss.now()
+	)
+	for _, id := range ids {
+		s, ok := ss.spots[id]
+		if !ok || s.DeletedAt != nil {
+			continue
+		}
+
+		if err := ss.audit(ctx, surf.AuditActionDelete, s.ID, surf.SpotChanges(s, nil)); err != nil {
+			return nil, err
+		}
+
+		deletedAt := now
+		s.DeletedAt = &deletedAt
+		deleted = append(deleted, id)
+	}
+
+	return deleted, nil
+}
+
+// RestoreSpot restores a soft-deleted spot by the given ID and returns it if the
+// restoration succeeds. surf.ErrSpotNotFound is returned when spot is not found
+// or not soft-deleted.
+func (ss *SpotStore) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[id]
+	if !ok || s.DeletedAt == nil {
+		return surf.Spot{}, surf.ErrSpotNotFound
+	}
+
+	before := *s
+
+	// The external reference might have been taken by another spot while the spot
+	// was soft-deleted, in which case the restored spot loses it.
+	if s.ExternalRef != "" && ss.spotByExternalRef(s.ExternalRef) != nil {
+		s.ExternalRef = ""
+	}
+	// The same goes for the slug, unless slugs of soft-deleted spots are reserved.
+	if s.Slug != "" && ss.slugTaken(s.Slug, s.ID) {
+		s.Slug = ""
+	}
+	s.DeletedAt = nil
+
+	if err := ss.audit(ctx, surf.AuditActionRestore, s.ID, surf.SpotChanges(nil, s)); err != nil {
+		*s = before
+		return surf.Spot{}, err
+	}
+
+	return toSpot(s), nil
+}
+
+// SetSpotTags replaces tags of a spot by the given ID with the given tags, and
+// bumps its update time. surf.ErrSpotNotFound is returned when spot is not found
+// or soft-deleted.
+func (ss *SpotStore) SetSpotTags(ctx context.Context, id string, tags []string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[id]
+	if !ok || s.DeletedAt != nil {
+		return surf.ErrSpotNotFound
+	}
+
+	if err := ss.audit(ctx, surf.AuditActionUpdate, s.ID, surf.TagChanges(s.Tags, normalizeTags(tags))); err != nil {
+		return err
+	}
+
+	s.Tags = normalizeTags(tags)
+	s.UpdatedAt = ss.now()
+
+	return nil
+}
+
+// SpotPhotos returns photos of a spot by the given ID from the oldest one.
+// surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotPhotos(ctx context.Context, spotID string) ([]surf.SpotPhoto, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	s, ok := ss.spots[spotID]
+	if !ok || s.DeletedAt != nil {
+		return nil, surf.ErrSpotNotFound
+	}
+
+	var photos []surf.SpotPhoto
+	photos = append(photos, ss.photos[spotID]...)
+	return photos, nil
+}
+
+// CreateSpotPhoto creates a new photo of a spot using the given entry and returns
+// it. surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) CreateSpotPhoto(ctx context.Context, e surf.SpotPhotoEntry) (surf.SpotPhoto, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[e.SpotID]
+	if !ok || s.DeletedAt != nil {
+		return surf.SpotPhoto{}, surf.ErrSpotNotFound
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return surf.SpotPhoto{}, err
+	}
+
+	photo := surf.SpotPhoto{
+		ID:          id,
+		SpotID:      e.SpotID,
+		ContentType: e.ContentType,
+		Size:        e.Size,
+		CreatedAt:   ss.now(),
+	}
+	ss.photos[e.SpotID] = append(ss.photos[e.SpotID], photo)
+
+	return photo, nil
+}
+
+// DeleteSpotPhoto deletes a photo by the given ID of a spot by the given ID and
+// returns the deleted photo. surf.ErrSpotPhotoNotFound is returned when photo is
+// not found, or when spot is not found or soft-deleted.
+func (ss *SpotStore) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) (surf.SpotPhoto, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[spotID]
+	if !ok || s.DeletedAt != nil {
+		return surf.SpotPhoto{}, surf.ErrSpotPhotoNotFound
+	}
+
+	photos := ss.photos[spotID]
+	for i, p := range photos {
+		if p.ID != photoID {
+			continue
+		}
+
+		ss.photos[spotID] = append(photos[:i:i], photos[i+1:]...)
+		return p, nil
+	}
+
+	return surf.SpotPhoto{}, surf.ErrSpotPhotoNotFound
+}
+
+// RateSpot records a rating of a spot using the given entry, replacing a rating of
+// the same spot by the same rater on the same day. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) RateSpot(ctx context.Context, e surf.SpotRatingEntry) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[e.SpotID]
+	if !ok || s.DeletedAt != nil {
+		return surf.ErrSpotNotFound
+	}
+
+	if ss.ratings[e.SpotID] == nil {
+		ss.ratings[e.SpotID] = make(map[ratingKey]int)
+	}
+	ss.ratings[e.SpotID][ratingKey{
+		raterKey: e.RaterKey,
+		ratedOn:  e.RatedOn.UTC().Format("2006-01-02"),
+	}] = e.Score
+
+	return nil
+}
+
+// spotRatings aggregates ratings of a spot by the given ID. It must be called
+// while holding the lock.
+func (ss *SpotStore) spotRatings(id string) surf.SpotRatings {
+	ratings := ss.ratings[id]
+	if len(ratings) == 0 {
+		return surf.SpotRatings{}
+	}
+
+	var sum int
+	for _, score := range ratings {
+		sum += score
+	}
+
+	return surf.SpotRatings{
+		Average: float64(sum) / float64(len(ratings)),
+		Count:   len(ratings),
+	}
+}
+
+// FavoriteSpots returns spots that a user has favorited, from the most recently
+// favorited one. Soft-deleted spots are excluded.
+func (ss *SpotStore) FavoriteSpots(ctx context.Context, p surf.FavoriteSpotsParams) ([]surf.Spot, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	ids := ss.favorites[p.UserID]
+
+	var matched []*surf.Spot
+	for i := len(ids) - 1; i >= 0; i-- {
+		if s := ss.spots[ids[i]]; s != nil && s.DeletedAt == nil {
+			matched = append(matched, s)
+		}
+	}
+
+	var spots []surf.Spot
+	for _, s := range paginate(matched, p.Limit, p.Offset) {
+		spot := toSpot(s)
+		spot.Ratings = ss.spotRatings(s.ID)
+		spots = append(spots, spot)
+	}
+
+	return spots, nil
+}
+
+// FavoritedSpotIDs reports which of spots by the given IDs a user by the given ID
+// has favorited.
+func (ss *SpotStore) FavoritedSpotIDs(ctx context.Context, userID string, spotIDs []string) (map[string]bool, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	favorited := make(map[string]bool)
+	for _, id := range spotIDs {
+		if ss.favoriteIndex(userID, id) >= 0 {
+			favorited[id] = true
+		}
+	}
+
+	return favorited, nil
+}
+
+// FavoriteSpot adds a spot by the given ID to favorites of a user by the given ID,
+// unless it is already there. surf.ErrSpotNotFound is returned when spot is not
+// found or soft-deleted.
+func (ss *SpotStore) FavoriteSpot(ctx context.Context, userID, spotID string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[spotID]
+	if !ok || s.DeletedAt != nil {
+		return surf.ErrSpotNotFound
+	}
+
+	if ss.favoriteIndex(userID, spotID) < 0 {
+		ss.favorites[userID] = append(ss.favorites[userID], spotID)
+	}
+
+	return nil
+}
+
+// UnfavoriteSpot removes a spot by the given ID from favorites of a user by the
+// given ID, if it is there.
+func (ss *SpotStore) UnfavoriteSpot(ctx context.Context, userID, spotID string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if i := ss.favoriteIndex(userID, spotID); i >= 0 {
+		ids := ss.favorites[userID]
+		ss.favorites[userID] = append(ids[:i:i], ids[i+1:]...)
+	}
+
+	return nil
+}
+
+// ApplyFavoriteBatch adds and removes spots of the given batch to and from
+// favorites of its user at once, and returns IDs of the spots that got favorited.
+// IDs of spots to favorite that are not found or soft-deleted are skipped.
+func (ss *SpotStore) ApplyFavoriteBatch(ctx context.Context, b surf.FavoriteBatch) ([]string, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	favorited := make([]string, 0, len(b.Favorite))
+	for _, spotID := range b.Favorite {
+		s, ok := ss.spots[spotID]
+		if !ok || s.DeletedAt != nil {
+			continue
+		}
+
+		if ss.favoriteIndex(b.UserID, spotID) < 0 {
+			ss.favorites[b.UserID] = append(ss.favorites[b.UserID], spotID)
+		}
+		favorited = append(favorited, spotID)
+	}
+
+	for _, spotID := range b.Unfavorite {
+		if i := ss.favoriteIndex(b.UserID, spotID); i >= 0 {
+			ids := ss.favorites[b.UserID]
+			ss.favorites[b.UserID] = append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+
+	return favorited, nil
+}
+
+// favoriteIndex returns an index of a spot by the given ID among favorites of a
+// user by the given ID, or -1 when the spot is not favorited. It must be called
+// while holding the lock.
+func (ss *SpotStore) favoriteIndex(userID, spotID string) int {
+	for i, id := range ss.favorites[userID] {
+		if id == spotID {
+			return i
+		}
+	}
+	return -1
+}
+
+// SpotSuggestion returns a spot suggestion by the given ID.
+// surf.ErrSpotSuggestionNotFound is returned when suggestion is not found.
+func (ss *SpotStore) SpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	i := ss.suggestionIndex(id)
+	if i < 0 {
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound
+	}
+
+	return copySpotSuggestion(ss.suggestions[i]), nil
+}
+
+// SpotSuggestions returns spot suggestions that match the given parameters, from
+// the most recently created one.
+func (ss *SpotStore) SpotSuggestions(ctx context.Context, p surf.SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var matched []surf.SpotSuggestion
+	for i := len(ss.suggestions) - 1; i >= 0; i-- {
+		if p.Status == "" || ss.suggestions[i].Status == p.Status {
+			matched = append(matched, ss.suggestions[i])
+		}
+	}
+
+	if p.Offset >= len(matched) {
+		return nil, nil
+	}
+	matched = matched[p.Offset:]
+	if p.Limit < len(matched) {
+		matched = matched[:p.Limit]
+	}
+
+	suggestions := make([]surf.SpotSuggestion, len(matched))
+	for i, s := range matched {
+		suggestions[i] = copySpotSuggestion(s)
+	}
+
+	return suggestions, nil
+}
+
+// CreateSpotSuggestion creates a new pending spot suggestion using the given entry
+// and returns it. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted.
+func (ss *SpotStore) CreateSpotSuggestion(ctx context.Context, e surf.SpotSuggestionEntry) (surf.SpotSuggestion, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.spots[e.Change.ID]
+	if !ok || s.DeletedAt != nil {
+		return surf.SpotSuggestion{}, surf.ErrSpotNotFound
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	suggestion := copySpotSuggestion(surf.SpotSuggestion{
+		ID:        id,
+		Status:    surf.SpotSuggestionStatusPending,
+		Change:    e.Change,
+		Note:      e.Note,
+		CreatedAt: ss.now(),
+	})
+	ss.suggestions = append(ss.suggestions, suggestion)
+
+	return copySpotSuggestion(suggestion), nil
+}
+
+// ResolveSpotSuggestion moves a pending spot suggestion by the given ID to the
+// given status and returns it. surf.ErrSpotSuggestionNotFound is returned when
+// suggestion is not found, and surf.ErrSpotSuggestionResolved when it is no
+// longer pending.
+func (ss *SpotStore) ResolveSpotSuggestion(
+	ctx context.Context,
+	id string,
+	status surf.SpotSuggestionStatus) (surf.SpotSuggestion, error) {
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	i := ss.suggestionIndex(id)
+	if i < 0 {
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound
+	}
+
+	s := &ss.suggestions[i]
+	if s.Status != surf.SpotSuggestionStatusPending {
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved
+	}
+
+	now := ss.now()
+	s.Status = status
+	s.ResolvedAt = &now
+
+	return copySpotSuggestion(*s), nil
+}
+
+// suggestionIndex returns an index of a spot suggestion by the given ID, or -1
+// when it is not found. It must be called while holding the lock.
+func (ss *SpotStore) suggestionIndex(id string) int {
+	for i, s := range ss.suggestions {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// copySpotSuggestion returns a copy of the given spot suggestion that shares no
+// pointers with it.
+func copySpotSuggestion(s surf.SpotSuggestion) surf.SpotSuggestion {
+	s.Change.Name = copyString(s.Change.Name)
+	s.Change.Latitude = copyFloat(s.Change.Latitude)
+	s.Change.Longitude = copyFloat(s.Change.Longitude)
+	s.Change.Locality = copyString(s.Change.Locality)
+	s.Change.CountryCode = copyString(s.Change.CountryCode)
+	s.Change.Region = copyString(s.Change.Region)
+	s.ResolvedAt = copyTime(s.ResolvedAt)
+	return s
+}
+
+// CreateSpotIdempotently creates a new spot using the given entry and saves the
+// given idempotency key along with it at the given time, unless a spot has already
+// been created with the key not earlier than the given since time. In that case,
+// nothing is created, and that spot is returned along with false.
+// surf.ErrSpotNotFound is returned when the spot has been soft-deleted since then.
+func (ss *SpotStore) CreateSpotIdempotently(
+	ctx context.Context,
+	key string,
+	e surf.SpotCreationEntry,
+	since, now time.Time) (surf.Spot, bool, error) {
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if k, ok := ss.idempotencyKeys[key]; ok && !k.createdAt.Before(since) {
+		s, ok := ss.spots[k.spotID]
+		if !ok || s.DeletedAt != nil {
+			return surf.Spot{}, false, surf.ErrSpotNotFound
+		}
+
+		spot := toSpot(s)
+		spot.Ratings = ss.spotRatings(s.ID)
+		return spot, false, nil
+	}
+
+	spot, err := ss.createSpot(ctx, e)
+	if err != nil {
+		return surf.Spot{}, false, err
+	}
+
+	ss.idempotencyKeys[key] = idempotencyKey{
+		spotID:    spot.ID,
+		createdAt: now,
+	}
+
+	return spot, true, nil
+}
+
+// ReferencePoints returns points of the given reference set ordered by their names.
+func (ss *SpotStore) ReferencePoints(ctx context.Context, set string) ([]surf.ReferencePoint, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var points []surf.ReferencePoint
+	points = append(points, ss.referencePoints[set]...)
+	return points, nil
+}
+
+// ReplaceReferencePoints replaces points of the given reference set with the given
+// ones.
+func (ss *SpotStore) ReplaceReferencePoints(ctx context.Context, set string, points []surf.ReferencePoint) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	replaced := append([]surf.ReferencePoint(nil), points...)
+	sort.SliceStable(replaced, func(i, j int) bool {
+		return replaced[i].Name < replaced[j].Name
+	})
+
+	if len(replaced) == 0 {
+		delete(ss.referencePoints, set)
+		return nil
+	}
+	ss.referencePoints[set] = replaced
+
+	return nil
+}
+
+// ReplaceSpotReferenceDistances replaces distances of spots to the given reference
+// set with the given ones. Distances of spots that are not found are skipped.
+func (ss *SpotStore) ReplaceSpotReferenceDistances(
+	ctx context.Context,
+	set string,
+	distances []surf.SpotReferenceDistance) error {
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	replaced := make(map[string]surf.ReferenceDistance, len(distances))
+	for _, d := range distances {
+		if _, ok := ss.spots[d.SpotID]; !ok {
+			continue
+		}
+		replaced[d.SpotID] = d.ReferenceDistance
+	}
+
+	if len(replaced) == 0 {
+		delete(ss.referenceDistances, set)
+		return nil
+	}
+	ss.referenceDistances[set] = replaced
+
+	return nil
+}
+
+func matchesFilters(s *surf.Spot, countryCode string, bounds *geo.Bounds) bool {
+	if countryCode != "" && s.Location.CountryCode != countryCode {
+		return false
+	}
+
+	if bounds == nil {
+		return true
+	}
+
+	c := s.Location.Coordinates
+	for _, box := range geo.NormalizeBounds(*bounds) {
+		if c.Latitude >= box.SouthWest.Latitude && c.Latitude <= box.NorthEast.Latitude &&
+			c.Longitude >= box.SouthWest.Longitude && c.Longitude <= box.NorthEast.Longitude {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesTags(s *surf.Spot, tags []string, match surf.SpotTagsMatch) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	has := make(map[string]bool, len(s.Tags))
+	for _, t := range s.Tags {
+		has[t] = true
+	}
+
+	for _, t := range tags {
+		if has[t] && match == surf.SpotTagsMatchAny {
+			return true
+		}
+		if !has[t] && match == surf.SpotTagsMatchAll {
+			return false
+		}
+	}
+	return match == surf.SpotTagsMatchAll
+}
+
+// matchQuery reports whether the given spot matches the given search query along
+// with its rank for the full-text search.
+func matchQuery(s *surf.Spot, q surf.SpotSearchQuery) (int, bool) {
+	if q.Query == "" {
+		return 0, true
+	}
+
+	query := q.Query
+	if utf8.RuneCountInString(query) > maxSearchQueryChars {
+		query = string([]rune(query)[:maxSearchQueryChars])
+	}
+
+	if q.WithSpotID && containsFold(s.ID, query) {
+		return 0, true
+	}
+
+	if !q.FullText {
+		return 0, containsFold(s.Name, query) || containsFold(s.Location.Locality, query)
+	}
+
+	return matchWords(words(s.Name+" "+s.Location.Locality), words(q.Query))
+}
+
+// matchWords reports whether every word of the query is among words of a document,
+// and ranks the document by a number of occurrences of the query's words.
+func matchWords(doc, query []string) (int, bool) {
+	if len(query) == 0 {
+		return 0, false
+	}
+
+	counts := make(map[string]int, len(doc))
+	for _, w := range doc {
+		counts[w]++
+	}
+
+	var rank int
+	for _, w := range query {
+		if counts[w] == 0 {
+			return 0, false
+		}
+		rank += counts[w]
+	}
+	return rank, true
+}
+
+// words splits the given string into lower-cased words in the same way as the
+// simple text search configuration of PostgreSQL does.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// normalizeTags returns a sorted copy of the given tags without duplicates, which
+// is how the PostgreSQL store reads them back.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if !seen[t] {
+			seen[t] = true
+			normalized = append(normalized, t)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func paginate(spots []*surf.Spot, limit, offset int) []*surf.Spot {
+	if offset >= len(spots) {
+		return nil
+	}
+	spots = spots[offset:]
+
+	if limit < len(spots) {
+		spots = spots[:limit]
+	}
+	return spots
+}
+
+// toSpot returns a copy of the given spot that is safe to be handed out, leaving
+// out the fields that the PostgreSQL store does not read by default.
+func toSpot(s *surf.Spot) surf.Spot {
+	spot := *s
+	spot.Tags = append([]string{}, s.Tags...)
+	spot.DeletedAt = nil
+	spot.ExternalRef = ""
+	return spot
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func copyFloat(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	return &c
+}
+
+// newUUID returns a random version 4 UUID, which is what PostgreSQL's
+// gen_random_uuid generates.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
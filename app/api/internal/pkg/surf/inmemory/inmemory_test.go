@@ -0,0 +1,84 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/surftest"
+)
+
+func TestSpotStore_Conformance(t *testing.T) {
+	surftest.TestSpotStore(t, func(t *testing.T) surftest.SpotStore {
+		return NewSpotStore()
+	})
+}
+
+func TestSpotStore_ReservedSlugs(t *testing.T) {
+	surftest.TestReservedSlugs(t, NewSpotStore(WithDeletedSlugsReserved()))
+}
+
+func TestSpotStore_SpotCreationCounts(t *testing.T) {
+	times := []time.Time{
+		time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),  // Monday
+		time.Date(2021, 3, 7, 23, 0, 0, 0, time.UTC),  // Sunday
+		time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC),   // Monday
+		time.Date(2021, 4, 30, 12, 0, 0, 0, time.UTC), // Friday
+	}
+
+	ss := NewSpotStore()
+	for _, tm := range times {
+		now := tm
+		ss.now = func() time.Time { return now }
+		_, err := ss.CreateSpot(context.Background(), surf.SpotCreationEntry{Name: "Spot"})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	tests := []struct {
+		name           string
+		interval       surf.TimeInterval
+		expectedCounts []surf.SpotCreationCount
+	}{
+		{
+			name:     "aggregate by months",
+			interval: surf.TimeIntervalMonth,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 3},
+			},
+		},
+		{
+			name:     "aggregate by weeks starting on mondays",
+			interval: surf.TimeIntervalWeek,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+				{BucketStart: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), Count: 1},
+			},
+		},
+		{
+			name:     "aggregate by days",
+			interval: surf.TimeIntervalDay,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 1},
+				{BucketStart: time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC), Count: 1},
+				{BucketStart: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), Count: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The upper bound is exclusive, so the last spot is left out.
+			counts, err := ss.SpotCreationCounts(context.Background(), surf.SpotCreationCountsParams{
+				From:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2021, 4, 30, 12, 0, 0, 0, time.UTC),
+				Interval: test.interval,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+		})
+	}
+}
@@ -1,29 +1,59 @@
 package psql
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
 	"github.com/ztimes2/tolqin/app/api/pkg/batch"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
 	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/slug"
 )
 
 const (
 	defaultBatchSize = 100
+
+	// maxSearchQueryChars mirrors the search query limit enforced by the services
+	// so that oversized patterns never reach the database.
+	maxSearchQueryChars = 100
+
+	// maxSlugAttempts limits how many times a spot is tried to be inserted with a
+	// slug that keeps getting taken by spots created at the same time.
+	maxSlugAttempts = 3
+
+	metricQueryDurationSeconds = "psql_query_duration_seconds"
+
+	queryTagRequestID = "request_id"
 )
 
 // SpotStore is a PostgreSQL database adapter that stores spots and implements
 // surf.SpotReader, surf.SpotWriter, and surf.MultiSpotWriter interfaces.
+//
+// Read-only queries run through a reader, which defaults to the database that the
+// writes go to and can be pointed to read replicas using WithReader. Writes and
+// queries that return written rows always run on the database.
 type SpotStore struct {
 	db        *sqlx.DB
+	reader    psqlutil.Queryer
 	builder   sq.StatementBuilderType
 	batchSize int
+
+	queryDurations *metrics.Histogram
+	requestIDFn    func(context.Context) string
+
+	reserveDeletedSlugs bool
 }
 
 // NewSpotStore returns a new *SpotStore using the given database connector and
@@ -31,6 +61,7 @@ type SpotStore struct {
 func NewSpotStore(db *sqlx.DB, opts ...SpotStoreOption) *SpotStore {
 	ss := &SpotStore{
 		db:        db,
+		reader:    db,
 		builder:   psqlutil.NewQueryBuilder(),
 		batchSize: defaultBatchSize,
 	}
@@ -53,41 +84,178 @@ func WithBatchSize(size int) SpotStoreOption {
 	}
 }
 
+// WithReader sets a custom reader for the read-only queries of SpotStore, such as
+// *psqlutil.Cluster that routes them to read replicas. Since replicas may lag behind,
+// spots that have just been written might not be returned by the reads right away.
+func WithReader(r psqlutil.Queryer) SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.reader = r
+	}
+}
+
+// WithMetrics enables instrumentation of queries executed by SpotStore using the
+// given registry.
+func WithMetrics(reg *metrics.Registry) SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.queryDurations = reg.Histogram(
+			metricQueryDurationSeconds,
+			"Duration of PostgreSQL queries in seconds.",
+			nil,
+			"query",
+		)
+	}
+}
+
+// WithRequestIDTagging tags every query executed by SpotStore with a request ID
+// that the given function retrieves from the query's context, so that slow queries
+// can be correlated with requests in PostgreSQL logs and pg_stat_activity. Queries
+// are left untagged when the context does not hold a request ID.
+func WithRequestIDTagging(fn func(context.Context) string) SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.requestIDFn = fn
+	}
+}
+
+// WithDeletedSlugsReserved keeps slugs of soft-deleted spots reserved, so that new
+// spots get suffixed slugs instead of the ones that restored spots would have. By
+// default, slugs of soft-deleted spots can be reused by other spots, in which case
+// restored spots lose them.
+func WithDeletedSlugsReserved() SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.reserveDeletedSlugs = true
+	}
+}
+
+// BatchSize returns the batch size that is used for batch processing in the Multi
+// methods of SpotStore.
+func (ss *SpotStore) BatchSize() int {
+	return ss.batchSize
+}
+
+func (ss *SpotStore) observeQuery(name string, start time.Time) {
+	ss.queryDurations.Observe(time.Since(start).Seconds(), name)
+}
+
+func (ss *SpotStore) tagQuery(ctx context.Context, query string) string {
+	if ss.requestIDFn == nil {
+		return query
+	}
+	return psqlutil.TagQuery(query, queryTagRequestID, ss.requestIDFn(ctx))
+}
+
 // Spot returns a spot by the given ID. surf.ErrSpotNotFound is returned when spot
-// is not found.
-func (ss *SpotStore) Spot(id string) (surf.Spot, error) {
-	query, args, err := ss.builder.
-		Select("id", "name", "latitude", "longitude", "locality", "country_code", "created_at").
+// is not found or soft-deleted.
+func (ss *SpotStore) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	defer ss.observeQuery("spot", time.Now())
+
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
 		From("spots").
 		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
-		ToSql()
+		Where(notDeleted),
+	).ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpot(s), nil
+}
+
+// SpotBySlug returns a spot by the given slug. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	defer ss.observeQuery("spot_by_slug", time.Now())
+
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"lower(slug)": strings.ToLower(slug)}).
+		Where(notDeleted),
+	).ToSql()
 	if err != nil {
 		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
 	}
 
 	var s spot
-	if err := ss.db.QueryRowx(query, args...).StructScan(&s); err != nil {
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return surf.Spot{}, surf.ErrSpotNotFound
 		}
-		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
 	}
 
 	return toSpot(s), nil
 }
 
-// Spots returns multiple spots that match the given parameters.
-func (ss *SpotStore) Spots(p surf.SpotsParams) ([]surf.Spot, error) {
-	builder := buildSpotsSQL(ss.builder, p)
+// uniqueSlug returns the given slug suffixed with -2, -3, and so on when it is
+// taken by a spot other than the one by the given ID, ignoring case. Slugs of
+// soft-deleted spots are taken into account only when they are reserved.
+func (ss *SpotStore) uniqueSlug(ctx context.Context, q sqlx.QueryerContext, base, spotID string) (string, error) {
+	base = strings.ToLower(base)
+
+	builder := ss.builder.
+		Select("lower(slug)").
+		From("spots").
+		Where(sq.Or{
+			sq.Eq{"lower(slug)": base},
+			sq.Like{"lower(slug)": base + "-%"},
+		})
+	if !ss.reserveDeletedSlugs {
+		builder = builder.Where(notDeleted)
+	}
+	if spotID != "" {
+		builder = builder.Where(sq.NotEq{psqlutil.CastAsVarchar("id"): spotID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := q.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	defer rows.Close()
+	var taken []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return "", fmt.Errorf("failed to scan row: %w", err)
+		}
+		taken = append(taken, t)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return slug.Unique(base, taken), nil
+}
+
+// Spots returns multiple spots that match the given parameters. Soft-deleted spots
+// are excluded unless they are explicitly requested.
+func (ss *SpotStore) Spots(ctx context.Context, p surf.SpotsParams) ([]surf.Spot, error) {
+	defer ss.observeQuery("spots", time.Now())
+
+	builder := rankSpotsSQL(withRatingsSQL(buildSpotsSQL(ss.builder, p)), p)
 
 	query, args, err := builder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := ss.db.Queryx(query, args...)
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
 	}
 
 	var spots []surf.Spot
@@ -103,221 +271,2733 @@ func (ss *SpotStore) Spots(p surf.SpotsParams) ([]surf.Spot, error) {
 	return spots, nil
 }
 
-func buildSpotsSQL(b sq.StatementBuilderType, p surf.SpotsParams) sq.SelectBuilder {
-	builder := b.
-		Select("id", "name", "latitude", "longitude", "locality", "country_code", "created_at").
-		From("spots").
-		Limit(uint64(p.Limit)).
-		Offset(uint64(p.Offset))
+// IterateSpots calls the given function for every spot that matches the given
+// parameters. Spots are fetched page by page in the order of their IDs, using the
+// configured batch size as a page size, so that only a single page is held in
+// memory at once.
+func (ss *SpotStore) IterateSpots(ctx context.Context, p surf.SpotIterationParams, fn func(surf.Spot) error) error {
+	var lastID string
+	for {
+		page, err := ss.spotsPage(ctx, p, lastID)
+		if err != nil {
+			return err
+		}
 
-	if p.CountryCode != "" {
-		builder = builder.Where(sq.Eq{"country_code": p.CountryCode})
-	}
+		for _, s := range page {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
 
-	if p.SearchQuery.Query != "" {
-		or := sq.Or{
-			sq.ILike{"name": psqlutil.Wildcard(p.SearchQuery.Query)},
-			sq.ILike{"locality": psqlutil.Wildcard(p.SearchQuery.Query)},
+		if len(page) < ss.batchSize {
+			return nil
 		}
-		if p.SearchQuery.WithSpotID {
-			or = append(or, sq.ILike{psqlutil.CastAsVarchar("id"): psqlutil.Wildcard(p.SearchQuery.Query)})
+		lastID = page[len(page)-1].ID
+	}
+}
+
+func (ss *SpotStore) spotsPage(ctx context.Context, p surf.SpotIterationParams, afterID string) ([]surf.Spot, error) {
+	defer ss.observeQuery("spots_page", time.Now())
+
+	builder := buildSpotsSQL(ss.builder, surf.SpotsParams{
+		Limit:       ss.batchSize,
+		CountryCode: p.CountryCode,
+		SearchQuery: p.SearchQuery,
+		Bounds:      p.Bounds,
+	}).Column("external_ref").OrderBy("id")
+
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var spots []surf.Spot
+	defer rows.Close()
+	for rows.Next() {
+		var s spot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		builder = builder.Where(or)
+		spots = append(spots, toSpot(s))
 	}
 
-	if p.Bounds != nil {
-		builder = builder.Where(sq.And{
-			psqlutil.Between("latitude", p.Bounds.SouthWest.Latitude, p.Bounds.NorthEast.Latitude),
-			psqlutil.Between("longitude", p.Bounds.SouthWest.Longitude, p.Bounds.NorthEast.Longitude),
-		})
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
 	}
 
-	return builder
+	return spots, nil
 }
 
-// CreateSpot creates a new spot using the given entry and returns it if the creation
-// succeeds.
-func (ss *SpotStore) CreateSpot(e surf.SpotCreationEntry) (surf.Spot, error) {
-	query, args, err := ss.builder.
-		Insert("spots").
-		Columns("name", "latitude", "longitude", "locality", "country_code").
-		Values(
-			e.Name,
-			e.Location.Coordinates.Latitude,
-			e.Location.Coordinates.Longitude,
-			e.Location.Locality,
-			e.Location.CountryCode,
-		).
-		Suffix("RETURNING id, name, latitude, longitude, locality, country_code, created_at").
+// ExplainSpots returns an execution plan, in a JSON format, of the query that reads
+// multiple spots matching the given parameters. The query itself is planned but
+// not executed.
+func (ss *SpotStore) ExplainSpots(ctx context.Context, p surf.SpotsParams) ([]byte, error) {
+	defer ss.observeQuery("explain_spots", time.Now())
+
+	query, args, err := rankSpotsSQL(withRatingsSQL(buildSpotsSQL(ss.builder, p)), p).
+		Prefix("EXPLAIN (FORMAT JSON)").
 		ToSql()
 	if err != nil {
-		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	var s spot
-	if err := ss.db.QueryRowx(query, args...).StructScan(&s); err != nil {
-		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	var plan []byte
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
 	}
 
-	return toSpot(s), nil
+	return plan, nil
 }
 
-// CreateSpots creates multiple new spots using the given entries.
-func (ss *SpotStore) CreateSpots(entries []surf.SpotCreationEntry) error {
-	if len(entries) == 0 {
-		return errors.New("no entries")
+func buildSpotsSQL(b sq.StatementBuilderType, p surf.SpotsParams) sq.SelectBuilder {
+	columns := spotColumns
+	if p.IncludeDeleted {
+		columns = append(append([]string(nil), spotColumns...), "deleted_at")
 	}
 
-	tx, err := ss.db.Beginx()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	builder := b.
+		Select(columns...).
+		From("spots").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if !p.IncludeDeleted {
+		builder = builder.Where(notDeleted)
 	}
 
-	coord := batch.New(len(entries), ss.batchSize)
-	for coord.HasNext() {
-		b := coord.Batch()
+	if p.Order == surf.SpotOrderUpdatedAtDesc {
+		builder = builder.OrderBy("updated_at DESC", "id")
+	}
 
-		if err := ss.createSpots(tx, entries[b.I:b.J+1]); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("failed to import spots: %w", err)
+	if p.ReferenceSet != "" {
+		builder = builder.
+			Columns("srd.reference_name", "srd.distance_km").
+			LeftJoin(
+				"spot_reference_distances AS srd ON srd.spot_id = spots.id AND srd.reference_set = ?",
+				p.ReferenceSet,
+			)
+		if p.Order == surf.SpotOrderReferenceDistance {
+			builder = builder.OrderBy("srd.distance_km NULLS LAST", "id")
 		}
 	}
 
-	_ = tx.Commit()
-	return nil
+	if len(p.Tags) > 0 {
+		// @> requires all of the tags to be present while && requires any of them.
+		op := "@>"
+		if p.TagsMatch == surf.SpotTagsMatchAny {
+			op = "&&"
+		}
+		builder = builder.Where(sq.Expr(spotTagsSQL+" "+op+" ?", pq.Array(p.Tags)))
+	}
+
+	return filterSpotsSQL(builder, p.CountryCode, p.SearchQuery, p.Bounds)
 }
 
-func (ss *SpotStore) createSpots(tx *sqlx.Tx, entries []surf.SpotCreationEntry) error {
-	builder := ss.builder.
-		Insert("spots").
-		Columns("name", "latitude", "longitude", "locality", "country_code")
+func filterSpotsSQL(
+	builder sq.SelectBuilder,
+	countryCode string,
+	q surf.SpotSearchQuery,
+	bounds *geo.Bounds) sq.SelectBuilder {
 
-	for _, e := range entries {
-		builder = builder.Values(
-			e.Name,
-			e.Location.Coordinates.Latitude,
-			e.Location.Coordinates.Longitude,
-			e.Location.Locality,
-			e.Location.CountryCode,
-		)
+	if countryCode != "" {
+		builder = builder.Where(sq.Eq{"country_code": countryCode})
 	}
 
-	query, args, err := builder.ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+	if q.Query != "" {
+		var or sq.Or
+		if q.FullText {
+			or = sq.Or{
+				sq.Expr("search_vector @@ websearch_to_tsquery('simple', ?)", q.Query),
+			}
+		} else {
+			or = sq.Or{
+				psqlutil.ILikeEscaped("name", searchPattern(q.Query)),
+				psqlutil.ILikeEscaped("locality", searchPattern(q.Query)),
+			}
+		}
+		if q.WithSpotID {
+			or = append(or, psqlutil.ILikeEscaped(psqlutil.CastAsVarchar("id"), searchPattern(q.Query)))
+		}
+		builder = builder.Where(or)
 	}
 
-	res, err := tx.Exec(query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+	if bounds != nil {
+		builder = builder.Where(boundsSQL(*bounds))
 	}
 
-	count, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to read affected rows: %w", err)
-	}
+	return builder
+}
 
-	if count == 0 {
-		return fmt.Errorf("no rows affected")
+// boundsSQL returns a condition matching coordinates within the given bounds.
+// Bounds crossing the 180th meridian are matched as two separate boxes.
+func boundsSQL(b geo.Bounds) sq.Sqlizer {
+	boxes := geo.NormalizeBounds(b)
+	if len(boxes) == 1 {
+		return boxSQL(boxes[0])
 	}
 
-	return nil
+	or := make(sq.Or, 0, len(boxes))
+	for _, box := range boxes {
+		or = append(or, boxSQL(box))
+	}
+	return or
 }
 
-// UpdateSpot updates an existing spot using the given entry and returns it if the
-// update succeeds. surf.ErrSpotNotFound is returned when spot is not found.
-func (ss *SpotStore) UpdateSpot(p surf.SpotUpdateEntry) (surf.Spot, error) {
-	values := make(map[string]interface{})
-	if p.Name != nil {
-		values["name"] = *p.Name
-	}
-	if p.Latitude != nil {
-		values["latitude"] = *p.Latitude
+func boxSQL(b geo.Bounds) sq.Sqlizer {
+	return sq.And{
+		psqlutil.Between("latitude", b.SouthWest.Latitude, b.NorthEast.Latitude),
+		psqlutil.Between("longitude", b.SouthWest.Longitude, b.NorthEast.Longitude),
 	}
-	if p.Longitude != nil {
-		values["longitude"] = *p.Longitude
-	}
-	if p.Locality != nil {
-		values["locality"] = *p.Locality
+}
+
+// searchPattern converts the given search query into an ILIKE pattern that matches
+// the query literally as a substring.
+func searchPattern(query string) string {
+	if utf8.RuneCountInString(query) > maxSearchQueryChars {
+		query = string([]rune(query)[:maxSearchQueryChars])
 	}
-	if p.CountryCode != nil {
-		values["country_code"] = *p.CountryCode
+	return psqlutil.Wildcard(psqlutil.EscapeLike(query))
+}
+
+// withRatingsSQL adds aggregated ratings to spots being read. Ratings are grouped
+// by spots in a subquery that is joined once, rather than aggregated per every
+// spot being read.
+func withRatingsSQL(builder sq.SelectBuilder) sq.SelectBuilder {
+	return builder.
+		Columns(
+			"COALESCE(sr.average_rating, 0) AS average_rating",
+			"COALESCE(sr.ratings_count, 0) AS ratings_count",
+		).
+		LeftJoin(spotRatingsSQL)
+}
+
+// spotRatingsSQL aggregates ratings of every spot.
+const spotRatingsSQL = "(" +
+	"SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id" +
+	") AS sr ON sr.spot_id = spots.id"
+
+// rankSpotsSQL orders spots that are read using the full-text search from the most
+// relevant to the search query, unless a different order is requested.
+func rankSpotsSQL(builder sq.SelectBuilder, p surf.SpotsParams) sq.SelectBuilder {
+	if p.Order != surf.SpotOrderNone || !p.SearchQuery.FullText || p.SearchQuery.Query == "" {
+		return builder
 	}
-	if len(values) == 0 {
-		return surf.Spot{}, surf.ErrEmptySpotUpdateEntry
+
+	return builder.
+		OrderByClause("ts_rank(search_vector, websearch_to_tsquery('simple', ?)) DESC", p.SearchQuery.Query).
+		OrderBy("id")
+}
+
+// CountriesWithSpotCounts returns countries of spots that match the given parameters
+// along with numbers of such spots in them, ordered from the country with the most
+// spots.
+func (ss *SpotStore) CountriesWithSpotCounts(ctx context.Context, p surf.CountriesParams) ([]surf.CountrySpotCount, error) {
+	defer ss.observeQuery("countries_with_spot_counts", time.Now())
+
+	builder := ss.builder.
+		Select("country_code", "COUNT(*) AS count").
+		From("spots").
+		Where(notDeleted).
+		GroupBy("country_code").
+		OrderBy("count DESC", "country_code")
+
+	query, args, err := filterSpotsSQL(builder, "", p.SearchQuery, nil).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	query, args, err := ss.builder.
-		Update("spots").
-		SetMap(values).
-		Where(sq.Eq{psqlutil.CastAsVarchar("id"): p.ID}).
-		Suffix("RETURNING id, name, latitude, longitude, locality, country_code, created_at").
-		ToSql()
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
 	if err != nil {
-		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
 	}
 
-	var s spot
-	if err := ss.db.QueryRowx(query, args...).StructScan(&s); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return surf.Spot{}, surf.ErrSpotNotFound
+	var counts []surf.CountrySpotCount
+	defer rows.Close()
+	for rows.Next() {
+		var c countrySpotCount
+		if err := rows.StructScan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+		counts = append(counts, surf.CountrySpotCount(c))
 	}
 
-	return toSpot(s), nil
+	return counts, nil
 }
 
-// DeleteSpot deletes a spot by the given ID. surf.ErrSpotNotFound is returned when
-// spot is not found.
-func (ss *SpotStore) DeleteSpot(id string) error {
+type countrySpotCount struct {
+	CountryCode string `db:"country_code"`
+	Count       int    `db:"count"`
+}
+
+// spotDirectorySQL defines common table expressions for SpotDirectory: a page of
+// countries, their localities ranked alphabetically, and their spots ranked by
+// names within localities. The limit and the offset of the page are expected to
+// be passed.
+const spotDirectorySQL = "WITH countries AS (" +
+	"SELECT country_code, COUNT(*) AS spot_count, COUNT(DISTINCT locality) AS locality_count " +
+	"FROM spots WHERE deleted_at IS NULL " +
+	"GROUP BY country_code ORDER BY country_code LIMIT ? OFFSET ?" +
+	"), localities AS (" +
+	"SELECT country_code, locality, COUNT(*) AS spot_count, " +
+	"ROW_NUMBER() OVER (PARTITION BY country_code ORDER BY locality) AS locality_rank " +
+	"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries) " +
+	"GROUP BY country_code, locality" +
+	"), ranked_spots AS (" +
+	"SELECT id, name, latitude, longitude, country_code, locality, " +
+	"ROW_NUMBER() OVER (PARTITION BY country_code, locality ORDER BY name, id) AS spot_rank " +
+	"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries)" +
+	")"
+
+// SpotDirectory returns countries of spots ordered by their codes, along with their
+// localities and spots limited according to the given parameters. Groups are
+// limited by window functions within a single query, which returns a row per spot
+// that is then nested into countries and localities.
+func (ss *SpotStore) SpotDirectory(ctx context.Context, p surf.SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	defer ss.observeQuery("spot_directory", time.Now())
+
 	query, args, err := ss.builder.
-		Delete("spots").
-		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Select(
+			"c.country_code",
+			"c.spot_count AS country_spot_count",
+			"c.locality_count",
+			"l.locality",
+			"l.spot_count AS locality_spot_count",
+			"s.id",
+			"s.name",
+			"s.latitude",
+			"s.longitude",
+		).
+		Prefix(spotDirectorySQL, p.Limit, p.Offset).
+		From("countries AS c").
+		Join("localities AS l ON l.country_code = c.country_code AND l.locality_rank <= ?", p.LocalitiesPerCountry).
+		Join(
+			"ranked_spots AS s ON s.country_code = l.country_code AND s.locality = l.locality AND s.spot_rank <= ?",
+			p.SpotsPerLocality,
+		).
+		OrderBy("c.country_code", "l.locality", "s.spot_rank").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	res, err := ss.db.Exec(query, args...)
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
 	}
 
-	count, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to read affected rows: %w", err)
+	var countries []surf.DirectoryCountry
+	defer rows.Close()
+	for rows.Next() {
+		var d directorySpot
+		if err := rows.StructScan(&d); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		countries = appendDirectorySpot(countries, d)
 	}
 
-	if count == 0 {
-		return surf.ErrSpotNotFound
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
 	}
 
-	return nil
+	return countries, nil
 }
 
-type spot struct {
-	ID          string    `db:"id"`
-	Name        string    `db:"name"`
-	Latitude    float64   `db:"latitude"`
-	Longitude   float64   `db:"longitude"`
-	Locality    string    `db:"locality"`
-	CountryCode string    `db:"country_code"`
-	CreatedAt   time.Time `db:"created_at"`
+type directorySpot struct {
+	CountryCode       string  `db:"country_code"`
+	CountrySpotCount  int     `db:"country_spot_count"`
+	LocalityCount     int     `db:"locality_count"`
+	Locality          string  `db:"locality"`
+	LocalitySpotCount int     `db:"locality_spot_count"`
+	ID                string  `db:"id"`
+	Name              string  `db:"name"`
+	Latitude          float64 `db:"latitude"`
+	Longitude         float64 `db:"longitude"`
 }
 
-func toSpot(s spot) surf.Spot {
-	return surf.Spot{
-		ID:        s.ID,
-		Name:      s.Name,
-		CreatedAt: s.CreatedAt,
+// appendDirectorySpot nests the given spot into the last country and locality of
+// the given countries, or into new ones if the spot belongs to different ones.
+// Spots are expected to be ordered by their countries and localities.
+func appendDirectorySpot(countries []surf.DirectoryCountry, d directorySpot) []surf.DirectoryCountry {
+	if len(countries) == 0 || countries[len(countries)-1].CountryCode != d.CountryCode {
+		countries = append(countries, surf.DirectoryCountry{
+			CountryCode:   d.CountryCode,
+			SpotCount:     d.CountrySpotCount,
+			LocalityCount: d.LocalityCount,
+		})
+	}
+
+	c := &countries[len(countries)-1]
+	if len(c.Localities) == 0 || c.Localities[len(c.Localities)-1].Locality != d.Locality {
+		c.Localities = append(c.Localities, surf.DirectoryLocality{
+			Locality:  d.Locality,
+			SpotCount: d.LocalitySpotCount,
+		})
+	}
+
+	l := &c.Localities[len(c.Localities)-1]
+	l.Spots = append(l.Spots, surf.Spot{
+		ID:   d.ID,
+		Name: d.Name,
 		Location: geo.Location{
-			Locality:    s.Locality,
-			CountryCode: s.CountryCode,
+			Locality:    d.Locality,
+			CountryCode: d.CountryCode,
 			Coordinates: geo.Coordinates{
-				Latitude:  s.Latitude,
-				Longitude: s.Longitude,
+				Latitude:  d.Latitude,
+				Longitude: d.Longitude,
 			},
 		},
+	})
+
+	return countries
+}
+
+// SpotCreationCounts returns numbers of spots created within time buckets of the
+// given interval, ordered from the earliest bucket. Buckets without any spots
+// created are omitted.
+func (ss *SpotStore) SpotCreationCounts(ctx context.Context, p surf.SpotCreationCountsParams) ([]surf.SpotCreationCount, error) {
+	defer ss.observeQuery("spot_creation_counts", time.Now())
+
+	bucket := fmt.Sprintf("date_trunc('%s', created_at)", dateTruncField(p.Interval))
+
+	query, args, err := ss.builder.
+		Select(bucket+" AS bucket_start", "COUNT(*) AS count").
+		From("spots").
+		Where(notDeleted).
+		Where(sq.GtOrEq{"created_at": p.From}).
+		Where(sq.Lt{"created_at": p.To}).
+		GroupBy("bucket_start").
+		OrderBy("bucket_start").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var counts []surf.SpotCreationCount
+	defer rows.Close()
+	for rows.Next() {
+		var c spotCreationCount
+		if err := rows.StructScan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts = append(counts, surf.SpotCreationCount(c))
+	}
+
+	return counts, nil
+}
+
+type spotCreationCount struct {
+	BucketStart time.Time `db:"bucket_start"`
+	Count       int       `db:"count"`
+}
+
+// dateTruncField returns a field of PostgreSQL's date_trunc function matching the
+// given interval.
+func dateTruncField(i surf.TimeInterval) string {
+	switch i {
+	case surf.TimeIntervalDay:
+		return "day"
+	case surf.TimeIntervalWeek:
+		return "week"
+	default:
+		return "month"
+	}
+}
+
+// Localities returns distinct localities of spots that match the given parameters
+// in alphabetical order. The prefix is escaped, so that wildcard characters within
+// it are matched literally.
+func (ss *SpotStore) Localities(ctx context.Context, p surf.LocalitiesParams) ([]string, error) {
+	defer ss.observeQuery("localities", time.Now())
+
+	builder := ss.builder.
+		Select("locality").
+		Distinct().
+		From("spots").
+		Where(notDeleted).
+		Where(sq.ILike{"locality": psqlutil.EscapeLike(p.Prefix) + "%"}).
+		OrderBy("locality").
+		Limit(uint64(p.Limit))
+
+	if p.CountryCode != "" {
+		builder = builder.Where(sq.Eq{"country_code": p.CountryCode})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var localities []string
+	defer rows.Close()
+	for rows.Next() {
+		var l string
+		if err := rows.Scan(&l); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		localities = append(localities, l)
+	}
+
+	return localities, nil
+}
+
+// SpotsCentroid returns the average coordinates of spots that match the given
+// parameters. Nil is returned when no spots match.
+//
+// Longitudes are averaged as angles rather than as plain numbers, so spots on both
+// sides of the antimeridian get a centroid near it instead of near the prime
+// meridian.
+func (ss *SpotStore) SpotsCentroid(ctx context.Context, p surf.SpotsCentroidParams) (*geo.Coordinates, error) {
+	defer ss.observeQuery("spots_centroid", time.Now())
+
+	builder := ss.builder.
+		Select(
+			"COUNT(*) AS count",
+			"AVG(latitude) AS latitude",
+			"AVG(SIN(RADIANS(longitude))) AS longitude_sin",
+			"AVG(COS(RADIANS(longitude))) AS longitude_cos",
+		).
+		From("spots").
+		Where(notDeleted)
+
+	query, args, err := filterSpotsSQL(builder, p.CountryCode, p.SearchQuery, p.Bounds).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var c centroid
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&c); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	if c.Count == 0 {
+		return nil, nil
+	}
+
+	return &geo.Coordinates{
+		Latitude:  c.Latitude.Float64,
+		Longitude: meanLongitude(c.LongitudeSin.Float64, c.LongitudeCos.Float64),
+	}, nil
+}
+
+type centroid struct {
+	Count        int             `db:"count"`
+	Latitude     sql.NullFloat64 `db:"latitude"`
+	LongitudeSin sql.NullFloat64 `db:"longitude_sin"`
+	LongitudeCos sql.NullFloat64 `db:"longitude_cos"`
+}
+
+// meanLongitude converts the given averaged sine and cosine of longitudes back to
+// a longitude in degrees.
+func meanLongitude(sin, cos float64) float64 {
+	return math.Atan2(sin, cos) * 180 / math.Pi
+}
+
+// RandomSpots returns multiple randomly picked spots that match the given parameters.
+//
+// Spots are picked using ORDER BY random(), which scans and sorts every matching
+// row. This is simple and yields a uniform sample, and is cheap enough for the
+// current number of spots. TABLESAMPLE would avoid the full scan on large tables,
+// but it samples pages rather than rows, so it skews towards clustered rows and may
+// return fewer spots than requested once filters are applied.
+func (ss *SpotStore) RandomSpots(ctx context.Context, p surf.RandomSpotsParams) ([]surf.Spot, error) {
+	defer ss.observeQuery("random_spots", time.Now())
+
+	query, args, err := buildSpotsSQL(ss.builder, surf.SpotsParams{
+		Limit:       p.Limit,
+		CountryCode: p.CountryCode,
+	}).
+		OrderBy("random()").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var spots []surf.Spot
+	defer rows.Close()
+	for rows.Next() {
+		var s spot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, toSpot(s))
+	}
+
+	return spots, nil
+}
+
+// haversineSQL computes a great-circle distance in kilometers between a spot and a
+// point, using 6371 km as the mean radius of the Earth. Latitude of the point is
+// expected to be passed twice, followed by its longitude. The square root is
+// clamped to 1, since rounding can push it above 1 for antipodal points, which
+// ASIN is undefined for.
+const haversineSQL = "2 * 6371 * ASIN(LEAST(1, SQRT(" +
+	"POWER(SIN(RADIANS(latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2)" +
+	")))"
+
+// NearbySpots returns multiple spots located within the given radius from the given
+// point, ordered by their distance from the point. Distances are computed using the
+// haversine formula, which scans every spot; this is acceptable for the current
+// number of spots but would call for a spatial index as the table grows.
+func (ss *SpotStore) NearbySpots(ctx context.Context, p surf.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	defer ss.observeQuery("nearby_spots", time.Now())
+
+	distances := sq.
+		Select(spotColumns...).
+		Column(
+			sq.Alias(
+				sq.Expr(
+					haversineSQL,
+					p.Coordinates.Latitude,
+					p.Coordinates.Latitude,
+					p.Coordinates.Longitude,
+				),
+				"distance_km",
+			),
+		).
+		From("spots").
+		Where(notDeleted)
+
+	query, args, err := ss.builder.
+		Select("*").
+		FromSelect(distances, "s").
+		Where(sq.LtOrEq{"distance_km": p.RadiusKm}).
+		OrderBy("distance_km", "id").
+		Limit(uint64(p.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var spots []surf.NearbySpot
+	defer rows.Close()
+	for rows.Next() {
+		var s nearbySpot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, surf.NearbySpot{
+			Spot:       toSpot(s.spot),
+			DistanceKm: s.DistanceKm,
+		})
+	}
+
+	return spots, nil
+}
+
+type nearbySpot struct {
+	spot
+	DistanceKm float64 `db:"distance_km"`
+}
+
+// SpotMarkers returns markers of multiple spots that match the given parameters.
+func (ss *SpotStore) SpotMarkers(ctx context.Context, p surf.SpotMarkersParams) ([]surf.SpotMarker, error) {
+	defer ss.observeQuery("spot_markers", time.Now())
+
+	builder := ss.builder.
+		Select("id", "latitude", "longitude").
+		From("spots").
+		Where(notDeleted).
+		Limit(uint64(p.Limit))
+
+	if p.Bounds != nil {
+		builder = builder.Where(boundsSQL(*p.Bounds))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var markers []surf.SpotMarker
+	defer rows.Close()
+	for rows.Next() {
+		var m spotMarker
+		if err := rows.StructScan(&m); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		markers = append(markers, toSpotMarker(m))
+	}
+
+	return markers, nil
+}
+
+// CreateSpot creates a new spot using the given entry and returns it if the creation
+// succeeds. Both created_at and updated_at default to NOW(), which is fixed for the
+// duration of a transaction, so a new spot has them equal. A slug of the entry is
+// suffixed when it is taken by another spot.
+func (ss *SpotStore) CreateSpot(ctx context.Context, e surf.SpotCreationEntry) (surf.Spot, error) {
+	defer ss.observeQuery("create_spot", time.Now())
+
+	return ss.audited(ctx, surf.AuditActionCreate, "", func(q sqlx.ExtContext) (surf.Spot, error) {
+		return ss.insertSpot(ctx, q, e)
+	})
+}
+
+// insertSpot inserts a new spot using the given entry. The slug of the entry is
+// tried as is first, unless slugs of soft-deleted spots are reserved, and gets
+// suffixed when another spot turns out to have it, so that spots created at the
+// same time with the same slug end up with different ones.
+func (ss *SpotStore) insertSpot(ctx context.Context, q sqlx.ExtContext, e surf.SpotCreationEntry) (surf.Spot, error) {
+	candidate := e.Slug
+	if candidate != "" && ss.reserveDeletedSlugs {
+		// The unique index covers slugs of spots that are not soft-deleted only, so
+		// reserved slugs have to be skipped beforehand.
+		var err error
+		if candidate, err = ss.uniqueSlug(ctx, q, e.Slug, ""); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		spot, err := ss.insertSpotWithSlug(ctx, q, e, candidate)
+		if !errors.Is(err, errSlugTaken) {
+			return spot, err
+		}
+		if attempt == maxSlugAttempts {
+			return surf.Spot{}, fmt.Errorf("failed to find unique slug: %w", surf.ErrDuplicateSpot)
+		}
+
+		if candidate, err = ss.uniqueSlug(ctx, q, e.Slug, ""); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+}
+
+// errSlugTaken is used when a spot cannot be inserted because another spot that
+// is not soft-deleted has the same slug.
+var errSlugTaken = errors.New("slug taken")
+
+func (ss *SpotStore) insertSpotWithSlug(ctx context.Context, q sqlx.ExtContext, e surf.SpotCreationEntry, candidate string) (surf.Spot, error) {
+	columns := []string{"name", "latitude", "longitude", "locality", "country_code"}
+	values := []interface{}{
+		e.Name,
+		e.Location.Coordinates.Latitude,
+		e.Location.Coordinates.Longitude,
+		e.Location.Locality,
+		e.Location.CountryCode,
+	}
+	suffix := "RETURNING " + strings.Join(spotColumns, ", ")
+	if candidate != "" {
+		columns = append(columns, "slug")
+		values = append(values, candidate)
+		// Unlike a unique violation, skipping the insertion does not abort the
+		// transaction that it might run in, so it can be retried with another slug.
+		suffix = "ON CONFLICT ((lower(slug))) WHERE deleted_at IS NULL DO NOTHING " + suffix
+	}
+
+	query, args, err := ss.builder.
+		Insert("spots").
+		Columns(columns...).
+		Values(values...).
+		Suffix(suffix).
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := q.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, errSlugTaken
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpot(s), nil
+}
+
+// CreateSpots creates multiple new spots using the given entries and mode, and
+// returns statistics about the affected spots.
+//
+// In surf.SpotCreationModeUpsert mode, spots that have the same external references
+// as the given entries get updated instead of being inserted. Soft-deleted spots
+// are not matched, and new spots get inserted in place of them. Spots without an
+// external reference are matched by their IDs instead, so that exported spots can
+// be imported back. When several entries share the same external reference, only
+// the last one of them is used. Tags of the matched spots are replaced with the
+// ones of the entries unless the entries have none.
+func (ss *SpotStore) CreateSpots(ctx context.Context, entries []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+	defer ss.observeQuery("create_spots", time.Now())
+
+	if len(entries) == 0 {
+		return surf.SpotCreationStats{}, errors.New("no entries")
+	}
+
+	createFn := ss.insertSpots
+	if mode == surf.SpotCreationModeUpsert {
+		createFn = ss.upsertSpots
+		entries = dedupeByExternalRef(entries)
+	}
+
+	tx, err := ss.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var stats surf.SpotCreationStats
+
+	coord := batch.New(len(entries), ss.batchSize)
+	for coord.HasNext() {
+		b := coord.Batch()
+
+		s, err := createFn(ctx, tx, entries[b.I:b.J+1])
+		if err != nil {
+			_ = tx.Rollback()
+			return surf.SpotCreationStats{}, fmt.Errorf("failed to import spots: %w", err)
+		}
+
+		stats.Inserted += s.Inserted
+		stats.Updated += s.Updated
+	}
+
+	if err := tx.Commit(); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to commit transaction: %w", storeError(err))
+	}
+
+	return stats, nil
+}
+
+func (ss *SpotStore) insertSpots(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) (surf.SpotCreationStats, error) {
+	query, args, err := buildCreateSpotsSQL(ss.builder, entries).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := tx.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var ids []string
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return surf.SpotCreationStats{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return surf.SpotCreationStats{}, fmt.Errorf("no rows affected")
+	}
+
+	if err := ss.replaceSpotTags(ctx, tx, ids, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	for i, id := range ids {
+		after := createdSpot(id, entries[i])
+		if err := ss.audit(ctx, tx, surf.AuditActionCreate, id, surf.SpotChanges(nil, &after)); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+	}
+
+	return surf.SpotCreationStats{
+		Inserted: len(ids),
+	}, nil
+}
+
+func (ss *SpotStore) upsertSpots(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) (surf.SpotCreationStats, error) {
+	if err := ss.adoptSpotIDsAsExternalRefs(ctx, tx, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	matched, err := ss.lockMatchedSpots(ctx, tx, entries)
+	if err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	query, args, err := buildCreateSpotsSQL(ss.builder, entries).
+		Suffix(
+			// External references are unique only among spots that are not
+			// soft-deleted, so soft-deleted spots never get matched.
+			"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET " +
+				"name = EXCLUDED.name, " +
+				"latitude = EXCLUDED.latitude, " +
+				"longitude = EXCLUDED.longitude, " +
+				"locality = EXCLUDED.locality, " +
+				"country_code = EXCLUDED.country_code, " +
+				"updated_at = NOW() " +
+				// xmax of a freshly inserted row version is always 0, which makes
+				// it possible to tell inserted rows apart from updated ones.
+				"RETURNING (xmax = 0) AS inserted, id",
+		).
+		ToSql()
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := tx.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var (
+		stats surf.SpotCreationStats
+		ids   []string
+	)
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			inserted bool
+			id       string
+		)
+		if err := rows.Scan(&inserted, &id); err != nil {
+			return surf.SpotCreationStats{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+
+		if inserted {
+			stats.Inserted++
+		} else {
+			stats.Updated++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	if stats.Inserted+stats.Updated == 0 {
+		return surf.SpotCreationStats{}, fmt.Errorf("no rows affected")
+	}
+
+	if err := ss.replaceSpotTags(ctx, tx, ids, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	for i, id := range ids {
+		after := createdSpot(id, entries[i])
+
+		action, changes := surf.AuditActionCreate, surf.SpotChanges(nil, &after)
+		if before, ok := matched[id]; ok {
+			// The upsert leaves the rest of the fields of matched spots as is.
+			after.Location.Region = before.Location.Region
+			after.Slug = before.Slug
+			action, changes = surf.AuditActionUpdate, surf.SpotChanges(&before, &after)
+		}
+
+		if err := ss.audit(ctx, tx, action, id, changes); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+	}
+
+	return stats, nil
+}
+
+// lockMatchedSpots reads spots that the upsert of the given entries is going to
+// match by their external references, and locks them until the end of the given
+// transaction, so that their states before the upsert can be audited. Spots are
+// keyed by their IDs. Nothing is read when the context holds no audit actor.
+func (ss *SpotStore) lockMatchedSpots(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) (map[string]surf.Spot, error) {
+	if _, ok := surf.AuditActorFromContext(ctx); !ok {
+		return nil, nil
+	}
+
+	var refs []string
+	for _, e := range entries {
+		if e.ExternalRef != "" {
+			refs = append(refs, e.ExternalRef)
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"external_ref": refs}).
+		Where(notDeleted).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	spots, err := ss.querySpots(ctx, tx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]surf.Spot, len(spots))
+	for _, s := range spots {
+		matched[s.ID] = s
+	}
+	return matched, nil
+}
+
+// createdSpot returns a state of a spot by the given ID that is created out of the
+// given entry.
+func createdSpot(id string, e surf.SpotCreationEntry) surf.Spot {
+	return surf.Spot{
+		ID:   id,
+		Name: e.Name,
+		Location: geo.Location{
+			Locality:    e.Location.Locality,
+			CountryCode: e.Location.CountryCode,
+			Coordinates: e.Location.Coordinates,
+		},
+	}
+}
+
+// replaceSpotTags replaces tags of the spots by the given IDs with tags of the
+// entries at the same positions. Spots whose entries have no tags are left as is.
+// PostgreSQL returns rows of a multi-row INSERT in the order of its VALUES, which
+// is what makes the IDs line up with the entries.
+func (ss *SpotStore) replaceSpotTags(ctx context.Context, tx *sqlx.Tx, ids []string, entries []surf.SpotCreationEntry) error {
+	if len(ids) != len(entries) {
+		return fmt.Errorf("expected %d affected rows, got %d", len(entries), len(ids))
+	}
+
+	var (
+		tagged  []string
+		builder = ss.builder.Insert("spot_tags").Columns("spot_id", "tag")
+	)
+	for i, e := range entries {
+		if len(e.Tags) == 0 {
+			continue
+		}
+
+		tagged = append(tagged, ids[i])
+		for _, tag := range e.Tags {
+			builder = builder.Values(ids[i], tag)
+		}
+	}
+
+	if len(tagged) == 0 {
+		return nil
+	}
+
+	query, args, err := ss.builder.
+		Delete("spot_tags").
+		Where(sq.Expr(psqlutil.CastAsVarchar("spot_id")+" = ANY(?)", pq.Array(tagged))).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+// adoptSpotIDsAsExternalRefs sets IDs of spots without an external reference as
+// their external references if the given entries refer to them, so that such spots
+// get matched by the upsert that follows.
+func (ss *SpotStore) adoptSpotIDsAsExternalRefs(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) error {
+	var refs []string
+	for _, e := range entries {
+		if e.ExternalRef != "" {
+			refs = append(refs, e.ExternalRef)
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("external_ref", sq.Expr(psqlutil.CastAsVarchar("id"))).
+		Where(sq.Eq{"external_ref": nil}).
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): refs}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+func buildCreateSpotsSQL(b sq.StatementBuilderType, entries []surf.SpotCreationEntry) sq.InsertBuilder {
+	builder := b.
+		Insert("spots").
+		Columns("name", "latitude", "longitude", "locality", "country_code", "external_ref")
+
+	for _, e := range entries {
+		builder = builder.Values(
+			e.Name,
+			e.Location.Coordinates.Latitude,
+			e.Location.Coordinates.Longitude,
+			e.Location.Locality,
+			e.Location.CountryCode,
+			nullString(e.ExternalRef),
+		)
+	}
+
+	return builder
+}
+
+// dedupeByExternalRef removes entries that share the same external reference
+// keeping only the last one of them. Postgres refuses to update the same row
+// twice within a single INSERT ... ON CONFLICT statement, so such entries must
+// not end up in the same batch.
+func dedupeByExternalRef(entries []surf.SpotCreationEntry) []surf.SpotCreationEntry {
+	var (
+		deduped = make([]surf.SpotCreationEntry, 0, len(entries))
+		indices = make(map[string]int)
+	)
+
+	for _, e := range entries {
+		if e.ExternalRef == "" {
+			deduped = append(deduped, e)
+			continue
+		}
+
+		if i, ok := indices[e.ExternalRef]; ok {
+			deduped[i] = e
+			continue
+		}
+
+		indices[e.ExternalRef] = len(deduped)
+		deduped = append(deduped, e)
+	}
+
+	return deduped
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+// UpdateSpot updates an existing spot using the given entry and returns it if the
+// update succeeds. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted. A slug of the entry is suffixed when it is taken by another spot,
+// and an empty one clears the slug. Since an update cannot skip a conflicting row,
+// surf.ErrDuplicateSpot is returned when another spot gets the slug in the meantime.
+func (ss *SpotStore) UpdateSpot(ctx context.Context, p surf.SpotUpdateEntry) (surf.Spot, error) {
+	defer ss.observeQuery("update_spot", time.Now())
+
+	if p.Name == nil && p.Latitude == nil && p.Longitude == nil &&
+		p.Locality == nil && p.CountryCode == nil && p.Region == nil && p.Slug == nil {
+		return surf.Spot{}, surf.ErrEmptySpotUpdateEntry
+	}
+
+	return ss.audited(ctx, surf.AuditActionUpdate, p.ID, func(q sqlx.ExtContext) (surf.Spot, error) {
+		values := map[string]interface{}{
+			"updated_at": sq.Expr("NOW()"),
+		}
+		if p.Name != nil {
+			values["name"] = *p.Name
+		}
+		if p.Latitude != nil {
+			values["latitude"] = *p.Latitude
+		}
+		if p.Longitude != nil {
+			values["longitude"] = *p.Longitude
+		}
+		if p.Locality != nil {
+			values["locality"] = *p.Locality
+		}
+		if p.CountryCode != nil {
+			values["country_code"] = *p.CountryCode
+		}
+		if p.Region != nil {
+			values["region"] = *p.Region
+		}
+		if p.Slug != nil {
+			values["slug"] = nil
+			if *p.Slug != "" {
+				unique, err := ss.uniqueSlug(ctx, q, *p.Slug, p.ID)
+				if err != nil {
+					return surf.Spot{}, err
+				}
+				values["slug"] = unique
+			}
+		}
+
+		query, args, err := ss.builder.
+			Update("spots").
+			SetMap(values).
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): p.ID}).
+			Where(notDeleted).
+			Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+			ToSql()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		var s spot
+		if err := q.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return surf.Spot{}, surf.ErrSpotNotFound
+			}
+			return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		return toSpot(s), nil
+	})
+}
+
+// DeleteSpot soft-deletes a spot by the given ID, so that it can be restored later.
+// surf.ErrSpotNotFound is returned when spot is not found or already soft-deleted.
+func (ss *SpotStore) DeleteSpot(ctx context.Context, id string) error {
+	defer ss.observeQuery("delete_spot", time.Now())
+
+	_, err := ss.audited(ctx, surf.AuditActionDelete, id, func(q sqlx.ExtContext) (surf.Spot, error) {
+		query, args, err := ss.builder.
+			Update("spots").
+			Set("deleted_at", sq.Expr("NOW()")).
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+			Where(notDeleted).
+			ToSql()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		res, err := q.ExecContext(ctx, ss.tagQuery(ctx, query), args...)
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to read affected rows: %w", err)
+		}
+
+		if count == 0 {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+
+		return surf.Spot{}, nil
+	})
+	return err
+}
+
+// DeleteSpots soft-deletes multiple spots by the given IDs in a single query and
+// returns IDs of the spots that got deleted. IDs of spots that are not found or
+// are already soft-deleted are skipped.
+func (ss *SpotStore) DeleteSpots(ctx context.Context, ids []string) ([]string, error) {
+	defer ss.observeQuery("delete_spots", time.Now())
+
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(sq.Expr(psqlutil.CastAsVarchar("id")+" = ANY(?)", pq.Array(ids))).
+		Where(notDeleted).
+		Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var deleted []string
+	if err := ss.inAuditTx(ctx, func(q sqlx.ExtContext) error {
+		spots, err := ss.querySpots(ctx, q, query, args)
+		if err != nil {
+			return err
+		}
+
+		for i := range spots {
+			deleted = append(deleted, spots[i].ID)
+			if err := ss.audit(ctx, q, surf.AuditActionDelete, spots[i].ID, surf.SpotChanges(&spots[i], nil)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// RestoreSpot restores a soft-deleted spot by the given ID and returns it if the
+// restoration succeeds. surf.ErrSpotNotFound is returned when spot is not found
+// or not soft-deleted.
+func (ss *SpotStore) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	defer ss.observeQuery("restore_spot", time.Now())
+
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("deleted_at", nil).
+		// The external reference might have been taken by another spot while the
+		// spot was soft-deleted, in which case the restored spot loses it.
+		Set("external_ref", sq.Expr(restoredExternalRefSQL)).
+		// The same goes for the slug, unless slugs of soft-deleted spots are reserved.
+		Set("slug", sq.Expr(restoredSlugSQL)).
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var restored surf.Spot
+	if err := ss.inAuditTx(ctx, func(q sqlx.ExtContext) error {
+		var s spot
+		if err := q.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return surf.ErrSpotNotFound
+			}
+			return fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		restored = toSpot(s)
+		return ss.audit(ctx, q, surf.AuditActionRestore, restored.ID, surf.SpotChanges(nil, &restored))
+	}); err != nil {
+		return surf.Spot{}, err
+	}
+
+	return restored, nil
+}
+
+// SetSpotTags replaces tags of a spot by the given ID with the given tags within
+// a single transaction, and bumps updated_at of the spot. surf.ErrSpotNotFound is
+// returned when spot is not found or soft-deleted.
+func (ss *SpotStore) SetSpotTags(ctx context.Context, id string, tags []string) error {
+	defer ss.observeQuery("set_spot_tags", time.Now())
+
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		var before []string
+		if _, ok := surf.AuditActorFromContext(ctx); ok {
+			s, err := ss.lockSpot(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			before = s.Tags
+		}
+
+		if err := ss.setSpotTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+
+		return ss.audit(ctx, tx, surf.AuditActionUpdate, id, surf.TagChanges(before, tags))
+	})
+}
+
+func (ss *SpotStore) setSpotTags(ctx context.Context, tx *sqlx.Tx, id string, tags []string) error {
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Where(notDeleted).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var spotID string
+	if err := tx.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&spotID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.ErrSpotNotFound
+		}
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	query, args, err = ss.builder.
+		Delete("spot_tags").
+		Where(sq.Eq{"spot_id": spotID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	builder := ss.builder.
+		Insert("spot_tags").
+		Columns("spot_id", "tag")
+	for _, tag := range tags {
+		builder = builder.Values(spotID, tag)
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+var spotPhotoColumns = []string{"id", "spot_id", "content_type", "size", "created_at"}
+
+type spotPhoto struct {
+	ID          string    `db:"id"`
+	SpotID      string    `db:"spot_id"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// SpotPhotos returns photos of a spot by the given ID from the oldest one.
+// surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotPhotos(ctx context.Context, spotID string) ([]surf.SpotPhoto, error) {
+	defer ss.observeQuery("spot_photos", time.Now())
+
+	query, args, err := ss.builder.
+		Select("id").
+		From("spots").
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): spotID}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var id string
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, surf.ErrSpotNotFound
+		}
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	query, args, err = ss.builder.
+		Select(spotPhotoColumns...).
+		From("spot_photos").
+		Where(sq.Eq{"spot_id": id}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var photos []surf.SpotPhoto
+	defer rows.Close()
+	for rows.Next() {
+		var p spotPhoto
+		if err := rows.StructScan(&p); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		photos = append(photos, surf.SpotPhoto(p))
+	}
+
+	return photos, nil
+}
+
+// CreateSpotPhoto creates a new photo of a spot using the given entry and returns
+// it. surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) CreateSpotPhoto(ctx context.Context, e surf.SpotPhotoEntry) (surf.SpotPhoto, error) {
+	defer ss.observeQuery("create_spot_photo", time.Now())
+
+	// The photo is inserted only if the spot exists, so that a spot that gets
+	// soft-deleted concurrently does not end up with a new photo.
+	query, args, err := ss.builder.
+		Insert("spot_photos").
+		Columns("spot_id", "content_type", "size").
+		Select(sq.
+			Select("id").
+			Column(sq.Expr("CAST(? AS TEXT)", e.ContentType)).
+			Column(sq.Expr("CAST(? AS BIGINT)", e.Size)).
+			From("spots").
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): e.SpotID}).
+			Where(notDeleted),
+		).
+		Suffix("RETURNING " + strings.Join(spotPhotoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotPhoto{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var p spotPhoto
+	if err := ss.db.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotPhoto{}, surf.ErrSpotNotFound
+		}
+		return surf.SpotPhoto{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return surf.SpotPhoto(p), nil
+}
+
+// DeleteSpotPhoto deletes a photo by the given ID of a spot by the given ID and
+// returns the deleted photo. surf.ErrSpotPhotoNotFound is returned when photo is
+// not found, or when spot is not found or soft-deleted.
+func (ss *SpotStore) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) (surf.SpotPhoto, error) {
+	defer ss.observeQuery("delete_spot_photo", time.Now())
+
+	query, args, err := ss.builder.
+		Delete("spot_photos").
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): photoID}).
+		Where(sq.Expr(
+			"spot_id IN (SELECT id FROM spots WHERE "+psqlutil.CastAsVarchar("id")+" = ? AND deleted_at IS NULL)",
+			spotID,
+		)).
+		Suffix("RETURNING " + strings.Join(spotPhotoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotPhoto{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var p spotPhoto
+	if err := ss.db.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotPhoto{}, surf.ErrSpotPhotoNotFound
+		}
+		return surf.SpotPhoto{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return surf.SpotPhoto(p), nil
+}
+
+// RateSpot records a rating of a spot using the given entry, replacing a rating of
+// the same spot by the same rater on the same day. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) RateSpot(ctx context.Context, e surf.SpotRatingEntry) error {
+	defer ss.observeQuery("rate_spot", time.Now())
+
+	query, args, err := ss.builder.
+		Insert("spot_ratings").
+		Columns("spot_id", "score", "rater_key", "rated_on").
+		Select(sq.
+			Select("id").
+			Column(sq.Expr("CAST(? AS SMALLINT)", e.Score)).
+			Column(sq.Expr("CAST(? AS TEXT)", e.RaterKey)).
+			Column(sq.Expr("CAST(? AS DATE)", e.RatedOn.UTC().Format("2006-01-02"))).
+			From("spots").
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): e.SpotID}).
+			Where(notDeleted),
+		).
+		Suffix("ON CONFLICT (spot_id, rater_key, rated_on) DO UPDATE SET score = EXCLUDED.score").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := ss.db.ExecContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return surf.ErrSpotNotFound
+	}
+
+	return nil
+}
+
+// FavoriteSpots returns spots that a user has favorited, from the most recently
+// favorited one. Soft-deleted spots are excluded.
+func (ss *SpotStore) FavoriteSpots(ctx context.Context, p surf.FavoriteSpotsParams) ([]surf.Spot, error) {
+	defer ss.observeQuery("favorite_spots", time.Now())
+
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Join("favorites ON favorites.spot_id = spots.id").
+		Where(sq.Eq{psqlutil.CastAsVarchar("favorites.user_id"): p.UserID}).
+		Where(notDeleted).
+		OrderBy("favorites.favorited_at DESC", "spots.id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset)),
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var spots []surf.Spot
+	defer rows.Close()
+	for rows.Next() {
+		var s spot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, toSpot(s))
+	}
+
+	return spots, nil
+}
+
+// FavoritedSpotIDs reports which of spots by the given IDs a user by the given ID
+// has favorited.
+func (ss *SpotStore) FavoritedSpotIDs(ctx context.Context, userID string, spotIDs []string) (map[string]bool, error) {
+	favorited := make(map[string]bool)
+	if len(spotIDs) == 0 {
+		return favorited, nil
+	}
+
+	defer ss.observeQuery("favorited_spot_ids", time.Now())
+
+	query, args, err := ss.builder.
+		Select(psqlutil.CastAsVarchar("spot_id")).
+		From("favorites").
+		Where(sq.Eq{psqlutil.CastAsVarchar("user_id"): userID}).
+		Where(sq.Eq{psqlutil.CastAsVarchar("spot_id"): spotIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		favorited[id] = true
+	}
+
+	return favorited, nil
+}
+
+// FavoriteSpot adds a spot by the given ID to favorites of a user by the given ID,
+// unless it is already there. surf.ErrSpotNotFound is returned when spot is not
+// found or soft-deleted.
+func (ss *SpotStore) FavoriteSpot(ctx context.Context, userID, spotID string) error {
+	defer ss.observeQuery("favorite_spot", time.Now())
+
+	// A conflicting favorite is updated in place rather than ignored, so that it
+	// still counts as an affected row and is told apart from a missing spot.
+	query, args, err := ss.builder.
+		Insert("favorites").
+		Columns("user_id", "spot_id").
+		Select(sq.
+			Select().
+			Column(sq.Expr("CAST(? AS UUID)", userID)).
+			Column("id").
+			From("spots").
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): spotID}).
+			Where(notDeleted),
+		).
+		Suffix("ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := ss.db.ExecContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return surf.ErrSpotNotFound
+	}
+
+	return nil
+}
+
+// UnfavoriteSpot removes a spot by the given ID from favorites of a user by the
+// given ID, if it is there.
+func (ss *SpotStore) UnfavoriteSpot(ctx context.Context, userID, spotID string) error {
+	defer ss.observeQuery("unfavorite_spot", time.Now())
+
+	query, args, err := ss.builder.
+		Delete("favorites").
+		Where(sq.Eq{psqlutil.CastAsVarchar("user_id"): userID}).
+		Where(sq.Eq{psqlutil.CastAsVarchar("spot_id"): spotID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := ss.db.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+// ApplyFavoriteBatch adds and removes spots of the given batch to and from
+// favorites of its user within a single transaction, and returns IDs of the spots
+// that got favorited. IDs of spots to favorite that are not found or soft-deleted
+// are skipped.
+func (ss *SpotStore) ApplyFavoriteBatch(ctx context.Context, b surf.FavoriteBatch) ([]string, error) {
+	defer ss.observeQuery("apply_favorite_batch", time.Now())
+
+	favorited := make([]string, 0, len(b.Favorite))
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		if len(b.Favorite) > 0 {
+			query, args, err := ss.builder.
+				Insert("favorites").
+				Columns("user_id", "spot_id").
+				Select(sq.
+					Select().
+					Column(sq.Expr("CAST(? AS UUID)", b.UserID)).
+					Column("id").
+					From("spots").
+					Where(sq.Eq{psqlutil.CastAsVarchar("id"): b.Favorite}).
+					Where(notDeleted),
+				).
+				Suffix(
+					"ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at " +
+						"RETURNING " + psqlutil.CastAsVarchar("spot_id"),
+				).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if err := tx.SelectContext(ctx, &favorited, ss.tagQuery(ctx, query), args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", storeError(err))
+			}
+		}
+
+		if len(b.Unfavorite) > 0 {
+			query, args, err := ss.builder.
+				Delete("favorites").
+				Where(sq.Eq{psqlutil.CastAsVarchar("user_id"): b.UserID}).
+				Where(sq.Eq{psqlutil.CastAsVarchar("spot_id"): b.Unfavorite}).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", storeError(err))
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return favorited, nil
+}
+
+var spotSuggestionColumns = []string{
+	psqlutil.CastAsVarchar("id") + " AS id",
+	psqlutil.CastAsVarchar("spot_id") + " AS spot_id",
+	"status",
+	"name",
+	"latitude",
+	"longitude",
+	"locality",
+	"country_code",
+	"region",
+	"note",
+	"created_at",
+	"resolved_at",
+}
+
+type spotSuggestion struct {
+	ID          string       `db:"id"`
+	SpotID      string       `db:"spot_id"`
+	Status      string       `db:"status"`
+	Name        *string      `db:"name"`
+	Latitude    *float64     `db:"latitude"`
+	Longitude   *float64     `db:"longitude"`
+	Locality    *string      `db:"locality"`
+	CountryCode *string      `db:"country_code"`
+	Region      *string      `db:"region"`
+	Note        string       `db:"note"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ResolvedAt  sql.NullTime `db:"resolved_at"`
+}
+
+func toSpotSuggestion(s spotSuggestion) surf.SpotSuggestion {
+	var resolvedAt *time.Time
+	if s.ResolvedAt.Valid {
+		resolvedAt = &s.ResolvedAt.Time
+	}
+
+	return surf.SpotSuggestion{
+		ID:     s.ID,
+		Status: surf.SpotSuggestionStatus(s.Status),
+		Change: surf.SpotUpdateEntry{
+			ID:          s.SpotID,
+			Name:        s.Name,
+			Latitude:    s.Latitude,
+			Longitude:   s.Longitude,
+			Locality:    s.Locality,
+			CountryCode: s.CountryCode,
+			Region:      s.Region,
+		},
+		Note:       s.Note,
+		CreatedAt:  s.CreatedAt,
+		ResolvedAt: resolvedAt,
+	}
+}
+
+// SpotSuggestion returns a spot suggestion by the given ID.
+// surf.ErrSpotSuggestionNotFound is returned when suggestion is not found.
+func (ss *SpotStore) SpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	defer ss.observeQuery("spot_suggestion", time.Now())
+
+	query, args, err := ss.builder.
+		Select(spotSuggestionColumns...).
+		From("spot_suggestions").
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.reader.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound
+		}
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// SpotSuggestions returns spot suggestions that match the given parameters, from
+// the most recently created one.
+func (ss *SpotStore) SpotSuggestions(ctx context.Context, p surf.SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	defer ss.observeQuery("spot_suggestions", time.Now())
+
+	builder := ss.builder.
+		Select(spotSuggestionColumns...).
+		From("spot_suggestions").
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if p.Status != "" {
+		builder = builder.Where(sq.Eq{"status": string(p.Status)})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var suggestions []surf.SpotSuggestion
+	defer rows.Close()
+	for rows.Next() {
+		var s spotSuggestion
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		suggestions = append(suggestions, toSpotSuggestion(s))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// CreateSpotSuggestion creates a new pending spot suggestion using the given entry
+// and returns it. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted.
+func (ss *SpotStore) CreateSpotSuggestion(ctx context.Context, e surf.SpotSuggestionEntry) (surf.SpotSuggestion, error) {
+	defer ss.observeQuery("create_spot_suggestion", time.Now())
+
+	// Types of the proposed values are spelled out, since PostgreSQL cannot infer
+	// them for parameters that are selected rather than compared.
+	query, args, err := ss.builder.
+		Insert("spot_suggestions").
+		Columns("spot_id", "name", "latitude", "longitude", "locality", "country_code", "region", "note").
+		Select(sq.
+			Select("id").
+			Column(sq.Expr("CAST(? AS TEXT)", e.Change.Name)).
+			Column(sq.Expr("CAST(? AS NUMERIC)", e.Change.Latitude)).
+			Column(sq.Expr("CAST(? AS NUMERIC)", e.Change.Longitude)).
+			Column(sq.Expr("CAST(? AS TEXT)", e.Change.Locality)).
+			Column(sq.Expr("CAST(? AS TEXT)", e.Change.CountryCode)).
+			Column(sq.Expr("CAST(? AS TEXT)", e.Change.Region)).
+			Column(sq.Expr("CAST(? AS TEXT)", e.Note)).
+			From("spots").
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): e.Change.ID}).
+			Where(notDeleted),
+		).
+		Suffix("RETURNING " + strings.Join(spotSuggestionColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.db.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, surf.ErrSpotNotFound
+		}
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// ResolveSpotSuggestion moves a pending spot suggestion by the given ID to the
+// given status and returns it. surf.ErrSpotSuggestionNotFound is returned when
+// suggestion is not found, and surf.ErrSpotSuggestionResolved when it is no
+// longer pending.
+func (ss *SpotStore) ResolveSpotSuggestion(
+	ctx context.Context,
+	id string,
+	status surf.SpotSuggestionStatus) (surf.SpotSuggestion, error) {
+
+	defer ss.observeQuery("resolve_spot_suggestion", time.Now())
+
+	query, args, err := ss.builder.
+		Update("spot_suggestions").
+		Set("status", string(status)).
+		Set("resolved_at", sq.Expr("NOW()")).
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Where(sq.Eq{"status": string(surf.SpotSuggestionStatusPending)}).
+		Suffix("RETURNING " + strings.Join(spotSuggestionColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.db.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		// The suggestion is looked up once again in order to tell a missing
+		// suggestion apart from a resolved one.
+		query, args, err := ss.builder.
+			Select("1").
+			From("spot_suggestions").
+			Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+			ToSql()
+		if err != nil {
+			return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		var exists int
+		if err := ss.db.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&exists); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound
+			}
+			return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// audited runs the given mutation of a spot by the given ID, which is empty for
+// creations, and returns the spot that the mutation returns. When the context holds
+// an audit actor, the mutation runs within a transaction along with an insertion of
+// an audit entry, so that a failure to record the entry rolls the mutation back.
+// Otherwise, the mutation runs on the database as is.
+func (ss *SpotStore) audited(
+	ctx context.Context,
+	action surf.AuditAction,
+	spotID string,
+	mutate func(sqlx.ExtContext) (surf.Spot, error)) (surf.Spot, error) {
+
+	actorID, ok := surf.AuditActorFromContext(ctx)
+	if !ok {
+		return mutate(ss.db)
+	}
+
+	var spot surf.Spot
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		var before *surf.Spot
+		if action != surf.AuditActionCreate {
+			s, err := ss.lockSpot(ctx, tx, spotID)
+			if err != nil {
+				return err
+			}
+			before = &s
+		}
+
+		var err error
+		if spot, err = mutate(tx); err != nil {
+			return err
+		}
+
+		after := &spot
+		switch action {
+		case surf.AuditActionCreate:
+			spotID = spot.ID
+		case surf.AuditActionDelete:
+			after = nil
+		}
+
+		return ss.insertAuditEntry(ctx, tx, surf.AuditEntry{
+			ActorID: actorID,
+			Action:  action,
+			SpotID:  spotID,
+			Changes: surf.SpotChanges(before, after),
+		})
+	}); err != nil {
+		return surf.Spot{}, err
+	}
+
+	return spot, nil
+}
+
+// lockSpot reads a spot by the given ID and locks it until the end of the given
+// transaction, so that its state cannot change before being audited.
+// surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) lockSpot(ctx context.Context, tx *sqlx.Tx, id string) (surf.Spot, error) {
+	query, args, err := ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Where(notDeleted).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := tx.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpot(s), nil
+}
+
+// querySpots executes the given query that reads spots using the given executor,
+// and returns all of them once the rows are closed, so that the executor can be
+// used for other queries afterwards.
+func (ss *SpotStore) querySpots(ctx context.Context, q sqlx.QueryerContext, query string, args []interface{}) ([]surf.Spot, error) {
+	rows, err := q.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+	defer rows.Close()
+
+	var spots []surf.Spot
+	for rows.Next() {
+		var s spot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, toSpot(s))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return spots, nil
+}
+
+// inAuditTx calls the given function within a transaction when the context holds
+// an audit actor, so that audit entries get recorded along with the mutations
+// made by the function. Otherwise, the function is called outside of a
+// transaction, since there is nothing to record.
+func (ss *SpotStore) inAuditTx(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if _, ok := surf.AuditActorFromContext(ctx); !ok {
+		return fn(ss.db)
+	}
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		return fn(tx)
+	})
+}
+
+// audit records an entry with the given changes of a spot by the given ID when the
+// context holds an audit actor.
+func (ss *SpotStore) audit(
+	ctx context.Context,
+	q sqlx.ExecerContext,
+	action surf.AuditAction,
+	spotID string,
+	changes []surf.AuditChange) error {
+
+	actorID, ok := surf.AuditActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return ss.insertAuditEntry(ctx, q, surf.AuditEntry{
+		ActorID: actorID,
+		Action:  action,
+		SpotID:  spotID,
+		Changes: changes,
+	})
+}
+
+func (ss *SpotStore) insertAuditEntry(ctx context.Context, q sqlx.ExecerContext, e surf.AuditEntry) error {
+	changes, err := json.Marshal(toAuditChanges(e.Changes))
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	query, args, err := ss.builder.
+		Insert("audit_log").
+		Columns("actor_id", "action", "spot_id", "changes").
+		Values(e.ActorID, string(e.Action), e.SpotID, string(changes)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+// AuditEntries returns audit entries that match the given parameters, from the
+// most recently created one.
+func (ss *SpotStore) AuditEntries(ctx context.Context, p surf.AuditEntriesParams) ([]surf.AuditEntry, error) {
+	defer ss.observeQuery("audit_entries", time.Now())
+
+	builder := ss.builder.
+		Select(auditEntryColumns...).
+		From("audit_log").
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if p.SpotID != "" {
+		builder = builder.Where(sq.Eq{psqlutil.CastAsVarchar("spot_id"): p.SpotID})
+	}
+	if p.ActorID != "" {
+		builder = builder.Where(sq.Eq{"actor_id": p.ActorID})
+	}
+	if !p.From.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": p.From})
+	}
+	if !p.To.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": p.To})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var entries []surf.AuditEntry
+	defer rows.Close()
+	for rows.Next() {
+		var e auditEntry
+		if err := rows.StructScan(&e); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		entry, err := toAuditEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+var auditEntryColumns = []string{
+	psqlutil.CastAsVarchar("id") + " AS id",
+	"actor_id",
+	"action",
+	psqlutil.CastAsVarchar("spot_id") + " AS spot_id",
+	"changes",
+	"created_at",
+}
+
+type auditEntry struct {
+	ID        string    `db:"id"`
+	ActorID   string    `db:"actor_id"`
+	Action    string    `db:"action"`
+	SpotID    string    `db:"spot_id"`
+	Changes   []byte    `db:"changes"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// auditChange is an element of the JSON array that changes of an audit entry are
+// kept in.
+type auditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func toAuditChanges(changes []surf.AuditChange) []auditChange {
+	cc := make([]auditChange, len(changes))
+	for i, c := range changes {
+		cc[i] = auditChange(c)
+	}
+	return cc
+}
+
+func toAuditEntry(e auditEntry) (surf.AuditEntry, error) {
+	var cc []auditChange
+	if err := json.Unmarshal(e.Changes, &cc); err != nil {
+		return surf.AuditEntry{}, fmt.Errorf("failed to decode changes: %w", err)
+	}
+
+	var changes []surf.AuditChange
+	for _, c := range cc {
+		changes = append(changes, surf.AuditChange(c))
+	}
+
+	return surf.AuditEntry{
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		Action:    surf.AuditAction(e.Action),
+		SpotID:    e.SpotID,
+		Changes:   changes,
+		CreatedAt: e.CreatedAt,
+	}, nil
+}
+
+// CreateSpotIdempotently creates a new spot using the given entry and saves the
+// given idempotency key along with it at the given time within a single
+// transaction, unless a spot has already been created with the key not earlier
+// than the given since time. In that case, nothing is created, and that spot is
+// returned along with false. surf.ErrSpotNotFound is returned when the spot has
+// been soft-deleted since then.
+//
+// A key is claimed with an INSERT that updates only expired keys on conflict, so
+// a concurrent creation with the same key waits for the other one to commit and
+// then finds the key taken. Its spot is rolled back, and the spot of the other
+// creation is returned instead.
+func (ss *SpotStore) CreateSpotIdempotently(
+	ctx context.Context,
+	key string,
+	e surf.SpotCreationEntry,
+	since, now time.Time) (surf.Spot, bool, error) {
+
+	defer ss.observeQuery("create_spot_idempotently", time.Now())
+
+	spot, created, err := ss.createSpotIdempotently(ctx, key, e, since, now)
+	if errors.Is(err, errIdempotencyKeyTaken) {
+		spot, created, err = ss.createSpotIdempotently(ctx, key, e, since, now)
+	}
+	return spot, created, err
+}
+
+// errIdempotencyKeyTaken is used when an idempotency key gets claimed by another
+// creation while a spot is being created with it.
+var errIdempotencyKeyTaken = errors.New("idempotency key taken")
+
+func (ss *SpotStore) createSpotIdempotently(
+	ctx context.Context,
+	key string,
+	e surf.SpotCreationEntry,
+	since, now time.Time) (surf.Spot, bool, error) {
+
+	var (
+		spot    surf.Spot
+		created bool
+	)
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		spotID, ok, err := ss.idempotentSpotID(ctx, tx, key, since)
+		if err != nil {
+			return err
+		}
+		if ok {
+			spot, err = ss.spotInTx(ctx, tx, spotID)
+			return err
+		}
+
+		if spot, err = ss.insertSpot(ctx, tx, e); err != nil {
+			return err
+		}
+
+		if err := ss.audit(ctx, tx, surf.AuditActionCreate, spot.ID, surf.SpotChanges(nil, &spot)); err != nil {
+			return err
+		}
+
+		if err := ss.claimIdempotencyKey(ctx, tx, key, spot.ID, since, now); err != nil {
+			return err
+		}
+
+		created = true
+		return nil
+	}); err != nil {
+		return surf.Spot{}, false, err
+	}
+
+	return spot, created, nil
+}
+
+// spotInTx returns a spot by the given ID along with its ratings using the given
+// transaction, so that a spot committed just before it can be read regardless of
+// replication lag. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted.
+func (ss *SpotStore) spotInTx(ctx context.Context, tx *sqlx.Tx, id string) (surf.Spot, error) {
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{psqlutil.CastAsVarchar("id"): id}).
+		Where(notDeleted),
+	).ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := tx.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return toSpot(s), nil
+}
+
+// idempotentSpotID returns an ID of a spot that was created with the given
+// idempotency key not earlier than the given time, and whether there is such a key.
+func (ss *SpotStore) idempotentSpotID(ctx context.Context, q sqlx.QueryerContext, key string, since time.Time) (string, bool, error) {
+	query, args, err := ss.builder.
+		Select(psqlutil.CastAsVarchar("spot_id")).
+		From("idempotency_keys").
+		Where(sq.Eq{"key": key}).
+		Where(sq.GtOrEq{"created_at": since.UTC()}).
+		ToSql()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var spotID string
+	if err := q.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&spotID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return spotID, true, nil
+}
+
+// claimIdempotencyKey saves the given idempotency key along with an ID of a spot
+// that is being created with it. A key saved earlier than the given since time has
+// expired and gets replaced, while any other key is left as is, in which case
+// errIdempotencyKeyTaken is returned.
+func (ss *SpotStore) claimIdempotencyKey(ctx context.Context, tx *sqlx.Tx, key, spotID string, since, now time.Time) error {
+	query, args, err := ss.builder.
+		Insert("idempotency_keys").
+		Columns("key", "spot_id", "created_at").
+		Values(key, spotID, now.UTC()).
+		Suffix(
+			"ON CONFLICT (key) DO UPDATE SET spot_id = EXCLUDED.spot_id, created_at = EXCLUDED.created_at "+
+				"WHERE idempotency_keys.created_at < ? RETURNING key",
+			since.UTC(),
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var claimed string
+	if err := tx.QueryRowxContext(ctx, ss.tagQuery(ctx, query), args...).Scan(&claimed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errIdempotencyKeyTaken
+		}
+		return fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	return nil
+}
+
+// ReferencePoints returns points of the given reference set ordered by their names.
+func (ss *SpotStore) ReferencePoints(ctx context.Context, set string) ([]surf.ReferencePoint, error) {
+	defer ss.observeQuery("reference_points", time.Now())
+
+	query, args, err := ss.builder.
+		Select("name", "latitude", "longitude").
+		From("reference_points").
+		Where(sq.Eq{"reference_set": set}).
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.reader.QueryxContext(ctx, ss.tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", storeError(err))
+	}
+
+	var points []surf.ReferencePoint
+	defer rows.Close()
+	for rows.Next() {
+		var p referencePoint
+		if err := rows.StructScan(&p); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		points = append(points, toReferencePoint(p))
+	}
+
+	return points, nil
+}
+
+// ReplaceReferencePoints replaces points of the given reference set with the given
+// ones within a single transaction.
+func (ss *SpotStore) ReplaceReferencePoints(ctx context.Context, set string, points []surf.ReferencePoint) error {
+	defer ss.observeQuery("replace_reference_points", time.Now())
+
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		query, args, err := ss.builder.
+			Delete("reference_points").
+			Where(sq.Eq{"reference_set": set}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+			return fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		for start := 0; start < len(points); start += ss.batchSize {
+			end := start + ss.batchSize
+			if end > len(points) {
+				end = len(points)
+			}
+
+			builder := ss.builder.
+				Insert("reference_points").
+				Columns("reference_set", "name", "latitude", "longitude")
+			for _, p := range points[start:end] {
+				builder = builder.Values(set, p.Name, p.Coordinates.Latitude, p.Coordinates.Longitude)
+			}
+
+			query, args, err := builder.ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", storeError(err))
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceSpotReferenceDistances replaces distances of spots to the given reference
+// set with the given ones within a single transaction. Distances are inserted in
+// batches, and the ones of spots that are not found are skipped.
+func (ss *SpotStore) ReplaceSpotReferenceDistances(
+	ctx context.Context,
+	set string,
+	distances []surf.SpotReferenceDistance) error {
+
+	defer ss.observeQuery("replace_spot_reference_distances", time.Now())
+
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		query, args, err := ss.builder.
+			Delete("spot_reference_distances").
+			Where(sq.Eq{"reference_set": set}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+			return fmt.Errorf("failed to execute query: %w", storeError(err))
+		}
+
+		for start := 0; start < len(distances); start += ss.batchSize {
+			end := start + ss.batchSize
+			if end > len(distances) {
+				end = len(distances)
+			}
+
+			var (
+				batch  = distances[start:end]
+				ids    = make([]string, len(batch))
+				names  = make([]string, len(batch))
+				values = make([]float64, len(batch))
+			)
+			for i, d := range batch {
+				ids[i], names[i], values[i] = d.SpotID, d.PointName, d.DistanceKm
+			}
+
+			// Joining the spots skips distances of spots that are not found
+			// instead of violating the foreign key.
+			query, args, err := ss.builder.
+				Insert("spot_reference_distances").
+				Columns("spot_id", "reference_set", "reference_name", "distance_km").
+				Select(ss.builder.
+					Select("spots.id").
+					Column("?::text", set).
+					Columns("d.reference_name", "d.distance_km").
+					From("spots").
+					Join(
+						"UNNEST(?::text[], ?::text[], ?::float8[]) AS d (spot_id, reference_name, distance_km) "+
+							"ON "+psqlutil.CastAsVarchar("spots.id")+" = d.spot_id",
+						pq.Array(ids), pq.Array(names), pq.Array(values),
+					),
+				).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, ss.tagQuery(ctx, query), args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", storeError(err))
+			}
+		}
+
+		return nil
+	})
+}
+
+// inTx calls the given function within a transaction that is committed once the
+// function succeeds and rolled back otherwise.
+func (ss *SpotStore) inTx(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	tx, err := ss.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", storeError(err))
+	}
+
+	return nil
+}
+
+// SQLSTATE codes of constraint violations that are classified by storeError.
+const (
+	codeForeignKeyViolation pq.ErrorCode = "23503"
+	codeUniqueViolation     pq.ErrorCode = "23505"
+)
+
+// storeError wraps the given error into *surf.StoreError when it is reported by
+// PostgreSQL, so that constraint violations can be told apart from other failures,
+// such as lost connections. Other errors are returned as is.
+func storeError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	sErr := &surf.StoreError{
+		Code: string(pqErr.Code),
+		Err:  err,
+	}
+
+	switch pqErr.Code {
+	case codeUniqueViolation:
+		sErr.Kind = surf.ErrDuplicateSpot
+	case codeForeignKeyViolation:
+		sErr.Kind = surf.ErrInvalidReference
+	}
+
+	return sErr
+}
+
+type referencePoint struct {
+	Name      string  `db:"name"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
+
+func toReferencePoint(p referencePoint) surf.ReferencePoint {
+	return surf.ReferencePoint{
+		Name: p.Name,
+		Coordinates: geo.Coordinates{
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		},
+	}
+}
+
+// spotColumns holds columns that are read into spot.
+var spotColumns = []string{
+	"id", "name", "latitude", "longitude", "locality", "country_code", "region", "slug", "created_at", "updated_at",
+	spotTagsSQL + " AS tags",
+}
+
+// spotTagsSQL aggregates tags of a spot into an array within the same query that
+// reads the spot, so that reading multiple spots does not take a query per spot.
+const spotTagsSQL = "ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag)"
+
+// restoredExternalRefSQL keeps an external reference of a spot being restored
+// unless another spot that is not soft-deleted has the same one.
+const restoredExternalRefSQL = "CASE WHEN EXISTS (" +
+	"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL" +
+	") THEN NULL ELSE external_ref END"
+
+// restoredSlugSQL keeps a slug of a spot being restored unless another spot that
+// is not soft-deleted has the same one, ignoring case.
+const restoredSlugSQL = "CASE WHEN EXISTS (" +
+	"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL" +
+	") THEN NULL ELSE slug END"
+
+// notDeleted is a condition that excludes soft-deleted spots.
+var notDeleted = sq.Eq{"deleted_at": nil}
+
+type spot struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Latitude    float64        `db:"latitude"`
+	Longitude   float64        `db:"longitude"`
+	Locality    string         `db:"locality"`
+	CountryCode string         `db:"country_code"`
+	Region      string         `db:"region"`
+	Slug        sql.NullString `db:"slug"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	Tags        pq.StringArray `db:"tags"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+	ExternalRef sql.NullString `db:"external_ref"`
+
+	ReferenceName sql.NullString  `db:"reference_name"`
+	DistanceKm    sql.NullFloat64 `db:"distance_km"`
+
+	AverageRating float64 `db:"average_rating"`
+	RatingsCount  int     `db:"ratings_count"`
+}
+
+func toSpot(s spot) surf.Spot {
+	var deletedAt *time.Time
+	if s.DeletedAt.Valid {
+		deletedAt = &s.DeletedAt.Time
+	}
+
+	var nearestReference *surf.ReferenceDistance
+	if s.ReferenceName.Valid {
+		nearestReference = &surf.ReferenceDistance{
+			PointName:  s.ReferenceName.String,
+			DistanceKm: s.DistanceKm.Float64,
+		}
+	}
+
+	return surf.Spot{
+		ID:               s.ID,
+		Name:             s.Name,
+		Slug:             s.Slug.String,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+		DeletedAt:        deletedAt,
+		Tags:             s.Tags,
+		ExternalRef:      s.ExternalRef.String,
+		NearestReference: nearestReference,
+		Ratings: surf.SpotRatings{
+			Average: s.AverageRating,
+			Count:   s.RatingsCount,
+		},
+		Location: geo.Location{
+			Locality:    s.Locality,
+			CountryCode: s.CountryCode,
+			Region:      s.Region,
+			Coordinates: geo.Coordinates{
+				Latitude:  s.Latitude,
+				Longitude: s.Longitude,
+			},
+		},
+	}
+}
+
+type spotMarker struct {
+	ID        string  `db:"id"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
+
+func toSpotMarker(m spotMarker) surf.SpotMarker {
+	return surf.SpotMarker{
+		ID: m.ID,
+		Coordinates: geo.Coordinates{
+			Latitude:  m.Latitude,
+			Longitude: m.Longitude,
+		},
 	}
 }
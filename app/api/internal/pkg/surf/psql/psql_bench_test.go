@@ -0,0 +1,129 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/migrations"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil/migrate"
+)
+
+// envBenchDatabaseURL holds a connection string of a disposable PostgreSQL database
+// that the search benchmark migrates and seeds with spots. The benchmark is skipped
+// when it is not set.
+const envBenchDatabaseURL = "TOLQIN_BENCH_DATABASE_URL"
+
+const benchSpots = 100000
+
+var (
+	benchNameWords     = []string{"Trigg", "Point", "Cables", "Reef", "Bay", "Beach", "Left", "Right", "Island", "Lagoon"}
+	benchLocalityWords = []string{"Mosman", "Geraldton", "Aktau", "Kuryk", "Scarborough", "Margaret", "River", "Park"}
+)
+
+// BenchmarkSpotStore_Spots_Search compares the substring search, which is matched
+// with ILIKE, against the full-text search, which is matched using the GIN index
+// of the search_vector column. Execution plans of both are logged, so that the
+// use of the index can be checked:
+//
+//	TOLQIN_BENCH_DATABASE_URL=postgres://... go test -run '^$' -bench Spots_Search -v ./internal/pkg/surf/psql
+func BenchmarkSpotStore_Spots_Search(b *testing.B) {
+	url := os.Getenv(envBenchDatabaseURL)
+	if url == "" {
+		b.Skipf("%s is not set", envBenchDatabaseURL)
+	}
+
+	db, err := sqlx.Open(psqlutil.DriverNamePQ, url)
+	if err != nil {
+		b.Fatalf("could not open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewSpotStore(db, WithBatchSize(1000))
+
+	seedBenchSpots(ctx, b, db, store)
+
+	modes := []struct {
+		name     string
+		fullText bool
+	}{
+		{name: "substring", fullText: false},
+		{name: "fulltext", fullText: true},
+	}
+
+	for _, mode := range modes {
+		p := surf.SpotsParams{
+			Limit: 10,
+			SearchQuery: surf.SpotSearchQuery{
+				Query:    "reef mosman",
+				FullText: mode.fullText,
+			},
+		}
+
+		plan, err := store.ExplainSpots(ctx, p)
+		if err != nil {
+			b.Fatalf("could not explain spots: %v", err)
+		}
+		b.Logf("%s plan: %s", mode.name, plan)
+
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := store.Spots(ctx, p); err != nil {
+					b.Fatalf("could not read spots: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func seedBenchSpots(ctx context.Context, b *testing.B, db *sqlx.DB, store *SpotStore) {
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		b.Fatalf("could not load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		b.Fatalf("could not apply migrations: %v", err)
+	}
+
+	var count int
+	if err := db.GetContext(ctx, &count, "SELECT COUNT(*) FROM spots WHERE deleted_at IS NULL"); err != nil {
+		b.Fatalf("could not count spots: %v", err)
+	}
+	if count >= benchSpots {
+		return
+	}
+
+	entries := make([]surf.SpotCreationEntry, 0, benchSpots-count)
+	for i := count; i < benchSpots; i++ {
+		entries = append(entries, surf.SpotCreationEntry{
+			Name: fmt.Sprintf(
+				"%s %s %d",
+				benchNameWords[i%len(benchNameWords)],
+				benchNameWords[(i/len(benchNameWords))%len(benchNameWords)],
+				i,
+			),
+			Location: geo.Location{
+				Locality:    benchLocalityWords[i%len(benchLocalityWords)],
+				CountryCode: "au",
+				Coordinates: geo.Coordinates{
+					Latitude:  float64(i%180) - 90,
+					Longitude: float64(i%360) - 180,
+				},
+			},
+		})
+	}
+
+	if _, err := store.CreateSpots(ctx, entries, surf.SpotCreationModeInsert); err != nil {
+		b.Fatalf("could not seed spots: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "ANALYZE spots"); err != nil {
+		b.Fatalf("could not analyze spots: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package psql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/surftest"
+	"github.com/ztimes2/tolqin/app/api/migrations"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil/migrate"
+)
+
+// envTestDatabaseURL holds a connection string of a disposable PostgreSQL database
+// that the conformance test suite migrates and truncates. The suite is skipped when
+// it is not set.
+const envTestDatabaseURL = "TOLQIN_TEST_DATABASE_URL"
+
+// TestSpotStore_Conformance runs the same suite as the in-memory store does:
+//
+//	TOLQIN_TEST_DATABASE_URL=postgres://... go test -run Conformance -v ./internal/pkg/surf/...
+func TestSpotStore_Conformance(t *testing.T) {
+	url := os.Getenv(envTestDatabaseURL)
+	if url == "" {
+		t.Skipf("%s is not set", envTestDatabaseURL)
+	}
+
+	db, err := sqlx.Open(psqlutil.DriverNamePQ, url)
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatalf("could not load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("could not apply migrations: %v", err)
+	}
+
+	truncate := func(t *testing.T) {
+		if _, err := db.ExecContext(ctx, "TRUNCATE spots CASCADE"); err != nil {
+			t.Fatalf("could not truncate spots: %v", err)
+		}
+	}
+
+	surftest.TestSpotStore(t, func(t *testing.T) surftest.SpotStore {
+		truncate(t)
+		return NewSpotStore(db)
+	})
+
+	t.Run("ReservedSlugs", func(t *testing.T) {
+		truncate(t)
+		surftest.TestReservedSlugs(t, NewSpotStore(db, WithDeletedSlugsReserved()))
+	})
+}
@@ -1,19 +1,25 @@
 package psql
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"math"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
 	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
 	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/strutil"
 	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
 )
 
@@ -30,8 +36,12 @@ func TestSpotStore_Spot(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots WHERE CAST(id AS VARCHAR) = $1",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
 					)).
 					WithArgs("1").
 					WillReturnError(errors.New("something went wrong"))
@@ -45,8 +55,12 @@ func TestSpotStore_Spot(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots WHERE CAST(id AS VARCHAR) = $1",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
 					)).
 					WithArgs("1").
 					WillReturnError(sql.ErrNoRows)
@@ -60,15 +74,24 @@ func TestSpotStore_Spot(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots WHERE CAST(id AS VARCHAR) = $1",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
 					)).
 					WithArgs("1").
 					WillReturnRows(sqlmock.
 						NewRows([]string{
-							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+							"average_rating", "ratings_count",
 						}).
-						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+							4.5, 2,
+						),
 					).
 					RowsWillBeClosed()
 			},
@@ -77,6 +100,122 @@ func TestSpotStore_Spot(t *testing.T) {
 				ID:        "1",
 				Name:      "Spot 1",
 				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				Ratings: surf.SpotRatings{
+					Average: 4.5,
+					Count:   2,
+				},
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			spot, err := store.Spot(context.Background(), test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotBySlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		slug          string
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE lower(slug) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("spot-1").
+					WillReturnError(errors.New("something went wrong"))
+			},
+			slug:          "spot-1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE lower(slug) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("spot-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			slug:          "spot-1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return spot without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE lower(slug) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("spot-1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "slug", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			slug: "spot-1",
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				Slug:      "spot-1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
 				Location: geo.Location{
 					Locality:    "Locality 1",
 					CountryCode: "Country code 1",
@@ -102,7 +241,7 @@ func TestSpotStore_Spot(t *testing.T) {
 
 			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
 
-			spot, err := store.Spot(test.id)
+			spot, err := store.SpotBySlug(context.Background(), test.slug)
 			test.expectedErrFn(t, err)
 			assert.Equal(t, test.expectedSpot, spot)
 
@@ -128,8 +267,12 @@ func TestSpotStore_Spots(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
 					)).
 					WillReturnError(errors.New("unexpected error"))
 			},
@@ -145,8 +288,12 @@ func TestSpotStore_Spots(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
 					)).
 					WillReturnRows(sqlmock.
 						NewRows([]string{
@@ -168,8 +315,12 @@ func TestSpotStore_Spots(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
 					)).
 					WillReturnRows(sqlmock.
 						NewRows([]string{
@@ -190,8 +341,12 @@ func TestSpotStore_Spots(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
 					)).
 					WillReturnRows(sqlmock.
 						NewRows([]string{
@@ -233,25 +388,27 @@ func TestSpotStore_Spots(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots by country without error",
+			name: "return spots including soft-deleted ones without error",
 			params: surf.SpotsParams{
-				Limit:       10,
-				Offset:      0,
-				CountryCode: "kz",
+				Limit:          10,
+				Offset:         0,
+				IncludeDeleted: true,
 			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at " +
-							"FROM spots WHERE country_code = $1 LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, deleted_at, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id LIMIT 10 OFFSET 0",
 					)).
-					WithArgs("kz").
 					WillReturnRows(sqlmock.
 						NewRows([]string{
-							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "deleted_at",
 						}).
-						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
-						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), nil).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "Country code 2", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC)),
 					).
 					RowsWillBeClosed()
 			},
@@ -262,7 +419,7 @@ func TestSpotStore_Spots(t *testing.T) {
 					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
 						Locality:    "Locality 1",
-						CountryCode: "kz",
+						CountryCode: "Country code 1",
 						Coordinates: geo.Coordinates{
 							Latitude:  1.23,
 							Longitude: 3.21,
@@ -273,9 +430,13 @@ func TestSpotStore_Spots(t *testing.T) {
 					ID:        "2",
 					Name:      "Spot 2",
 					CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+					DeletedAt: func() *time.Time {
+						t := time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC)
+						return &t
+					}(),
 					Location: geo.Location{
 						Locality:    "Locality 2",
-						CountryCode: "kz",
+						CountryCode: "Country code 2",
 						Coordinates: geo.Coordinates{
 							Latitude:  2.34,
 							Longitude: 4.32,
@@ -286,27 +447,105 @@ func TestSpotStore_Spots(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots by query without error",
+			name: "return most recently updated spots without error",
 			params: surf.SpotsParams{
 				Limit:  10,
 				Offset: 0,
-				SearchQuery: surf.SpotSearchQuery{
-					Query: "query",
+				Order:  surf.SpotOrderUpdatedAtDesc,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL " +
+							"ORDER BY updated_at DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+						}).
+						AddRow(
+							"2", "Spot 2", 2.34, 4.32, "Locality 2", "Country code 2",
+							time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+						).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "Country code 2",
+						Coordinates: geo.Coordinates{
+							Latitude:  2.34,
+							Longitude: 4.32,
+						},
+					},
+				},
+				{
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
 				},
 			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots nearest to reference set without error",
+			params: surf.SpotsParams{
+				Limit:        10,
+				Offset:       0,
+				ReferenceSet: "airports",
+				Order:        surf.SpotOrderReferenceDistance,
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at "+
-							"FROM spots WHERE (name ILIKE $1 OR locality ILIKE $2) LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"srd.reference_name, srd.distance_km, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots LEFT JOIN spot_reference_distances AS srd " +
+							"ON srd.spot_id = spots.id AND srd.reference_set = $1 " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL " +
+							"ORDER BY srd.distance_km NULLS LAST, id LIMIT 10 OFFSET 0",
 					)).
-					WithArgs("%query%", "%query%").
+					WithArgs("airports").
 					WillReturnRows(sqlmock.
 						NewRows([]string{
 							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+							"reference_name", "distance_km",
 						}).
-						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
-						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), "Airport 1", 12.5,
+						).
+						AddRow(
+							"2", "Spot 2", 2.34, 4.32, "Locality 2", "Country code 2",
+							time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), nil, nil,
+						),
 					).
 					RowsWillBeClosed()
 			},
@@ -317,12 +556,16 @@ func TestSpotStore_Spots(t *testing.T) {
 					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
 						Locality:    "Locality 1",
-						CountryCode: "kz",
+						CountryCode: "Country code 1",
 						Coordinates: geo.Coordinates{
 							Latitude:  1.23,
 							Longitude: 3.21,
 						},
 					},
+					NearestReference: &surf.ReferenceDistance{
+						PointName:  "Airport 1",
+						DistanceKm: 12.5,
+					},
 				},
 				{
 					ID:        "2",
@@ -330,7 +573,7 @@ func TestSpotStore_Spots(t *testing.T) {
 					CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
 						Locality:    "Locality 2",
-						CountryCode: "kz",
+						CountryCode: "Country code 2",
 						Coordinates: geo.Coordinates{
 							Latitude:  2.34,
 							Longitude: 4.32,
@@ -341,29 +584,23 @@ func TestSpotStore_Spots(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots by bounds without error",
+			name: "return spots by country without error",
 			params: surf.SpotsParams{
-				Limit:  10,
-				Offset: 0,
-				Bounds: &geo.Bounds{
-					NorthEast: geo.Coordinates{
-						Latitude:  90,
-						Longitude: 180,
-					},
-					SouthWest: geo.Coordinates{
-						Latitude:  -90,
-						Longitude: -180,
-					},
-				},
+				Limit:       10,
+				Offset:      0,
+				CountryCode: "kz",
 			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at "+
-							"FROM spots WHERE (latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4) "+
-							"LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL AND country_code = $1 LIMIT 10 OFFSET 0",
 					)).
-					WithArgs(-90.0, 90.0, -180.0, 180.0).
+					WithArgs("kz").
 					WillReturnRows(sqlmock.
 						NewRows([]string{
 							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
@@ -404,11 +641,10 @@ func TestSpotStore_Spots(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots by country code and query without error",
+			name: "return spots by query without error",
 			params: surf.SpotsParams{
-				Limit:       10,
-				Offset:      0,
-				CountryCode: "kz",
+				Limit:  10,
+				Offset: 0,
 				SearchQuery: surf.SpotSearchQuery{
 					Query: "query",
 				},
@@ -416,10 +652,14 @@ func TestSpotStore_Spots(t *testing.T) {
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at "+
-							"FROM spots WHERE country_code = $1 AND (name ILIKE $2 OR locality ILIKE $3) LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND (name ILIKE $1 ESCAPE '\\' OR locality ILIKE $2 ESCAPE '\\') LIMIT 10 OFFSET 0",
 					)).
-					WithArgs("kz", "%query%", "%query%").
+					WithArgs("%query%", "%query%").
 					WillReturnRows(sqlmock.
 						NewRows([]string{
 							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
@@ -460,23 +700,78 @@ func TestSpotStore_Spots(t *testing.T) {
 			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spots by country code and query including spot id without error",
+			name: "return spots by query matching wildcards literally without error",
 			params: surf.SpotsParams{
-				Limit:       10,
-				Offset:      0,
-				CountryCode: "kz",
+				Limit:  10,
+				Offset: 0,
 				SearchQuery: surf.SpotSearchQuery{
-					Query:      "query",
-					WithSpotID: true,
+					Query: "5%",
 				},
 			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"SELECT id, name, latitude, longitude, locality, country_code, created_at "+
-							"FROM spots WHERE country_code = $1 AND (name ILIKE $2 OR locality ILIKE $3 OR CAST(id AS VARCHAR) ILIKE $4) LIMIT 10 OFFSET 0",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND (name ILIKE $1 ESCAPE '\\' OR locality ILIKE $2 ESCAPE '\\') LIMIT 10 OFFSET 0",
 					)).
-					WithArgs("kz", "%query%", "%query%", "%query%").
+					WithArgs(`%5\%%`, `%5\%%`).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "5% Point", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:        "1",
+					Name:      "5% Point",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "kz",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots by bounds without error",
+			params: surf.SpotsParams{
+				Limit:  10,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND (latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4) "+
+							"LIMIT 10 OFFSET 0",
+					)).
+					WithArgs(-90.0, 90.0, -180.0, 180.0).
 					WillReturnRows(sqlmock.
 						NewRows([]string{
 							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
@@ -516,157 +811,101 @@ func TestSpotStore_Spots(t *testing.T) {
 			},
 			expectedErrFn: assert.NoError,
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				assert.Fail(t, err.Error())
-			}
-			defer db.Close()
-
-			test.mockFn(mock)
-
-			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
-
-			spots, err := store.Spots(test.params)
-			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedSpots, spots)
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func TestSpotStore_CreateSpot(t *testing.T) {
-	tests := []struct {
-		name          string
-		mockFn        func(sqlmock.Sqlmock)
-		params        surf.SpotCreationEntry
-		expectedSpot  surf.Spot
-		expectedErrFn assert.ErrorAssertionFunc
-	}{
 		{
-			name: "return error during query execution",
-			mockFn: func(m sqlmock.Sqlmock) {
-				m.
-					ExpectQuery(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5) "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
-					)).
-					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1").
-					WillReturnError(errors.New("unexpected error"))
-			},
-			params: surf.SpotCreationEntry{
-				Name: "Spot 1",
-				Location: geo.Location{
-					Locality:    "Locality 1",
-					CountryCode: "Country code 1",
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
+			name: "return spots by bounds crossing antimeridian without error",
+			params: surf.SpotsParams{
+				Limit:  10,
+				Offset: 0,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  10,
+						Longitude: -170,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -10,
+						Longitude: 170,
 					},
 				},
 			},
-			expectedSpot:  surf.Spot{},
-			expectedErrFn: assert.Error,
-		},
-		{
-			name: "return spot without error",
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
 					ExpectQuery(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5) "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND "+
+							"((latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4) OR "+
+							"(latitude BETWEEN $5 AND $6 AND longitude BETWEEN $7 AND $8)) "+
+							"LIMIT 10 OFFSET 0",
 					)).
-					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1").
+					WithArgs(-10.0, 10.0, 170.0, 180.0, -10.0, 10.0, -180.0, -170.0).
 					WillReturnRows(sqlmock.
 						NewRows([]string{
 							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
 						}).
-						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+						AddRow("1", "Spot 1", 1.23, 179.5, "Locality 1", "fj", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
 					).
 					RowsWillBeClosed()
 			},
-			params: surf.SpotCreationEntry{
-				Name: "Spot 1",
-				Location: geo.Location{
-					Locality:    "Locality 1",
-					CountryCode: "Country code 1",
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
-					},
-				},
-			},
-			expectedSpot: surf.Spot{
-				ID:        "1",
-				Name:      "Spot 1",
-				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
-				Location: geo.Location{
-					Locality:    "Locality 1",
-					CountryCode: "Country code 1",
-					Coordinates: geo.Coordinates{
-						Latitude:  1.23,
-						Longitude: 3.21,
+			expectedSpots: []surf.Spot{
+				{
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "fj",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 179.5,
+						},
 					},
 				},
 			},
 			expectedErrFn: assert.NoError,
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				assert.Fail(t, err.Error())
-			}
-			defer db.Close()
-
-			test.mockFn(mock)
-
-			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
-			spot, err := store.CreateSpot(test.params)
-			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedSpot, spot)
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func TestSpotStore_CreateSpots(t *testing.T) {
-	tests := []struct {
-		name          string
-		batchSize     int
-		mockFn        func(sqlmock.Sqlmock)
-		entries       []surf.SpotCreationEntry
-		expectedErrFn assert.ErrorAssertionFunc
-	}{
-		{
-			name:          "return error when nothing to import",
-			batchSize:     2,
-			mockFn:        func(m sqlmock.Sqlmock) {},
-			entries:       []surf.SpotCreationEntry{},
-			expectedErrFn: assert.Error,
-		},
 		{
-			name:      "return error during tx init",
-			batchSize: 2,
+			name: "return spots by country code and full-text query ordered by rank without error",
+			params: surf.SpotsParams{
+				Limit:       10,
+				Offset:      0,
+				CountryCode: "kz",
+				SearchQuery: surf.SpotSearchQuery{
+					Query:    "kazakh beach",
+					FullText: true,
+				},
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
-				m.ExpectBegin().
-					WillReturnError(errors.New("something went wrong"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND country_code = $1 AND (search_vector @@ websearch_to_tsquery('simple', $2)) "+
+							"ORDER BY ts_rank(search_vector, websearch_to_tsquery('simple', $3)) DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WithArgs("kz", "kazakh beach", "kazakh beach").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
 			},
-			entries: []surf.SpotCreationEntry{
+			expectedSpots: []surf.Spot{
 				{
-					Name: "Spot 1",
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
 						Locality:    "Locality 1",
-						CountryCode: "Country code 1",
+						CountryCode: "kz",
 						Coordinates: geo.Coordinates{
 							Latitude:  1.23,
 							Longitude: 3.21,
@@ -674,32 +913,105 @@ func TestSpotStore_CreateSpots(t *testing.T) {
 					},
 				},
 				{
-					Name: "Spot 2",
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
 						Locality:    "Locality 2",
-						CountryCode: "",
+						CountryCode: "kz",
 						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+							Latitude:  2.34,
+							Longitude: 4.32,
 						},
 					},
 				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots without search predicate and ranking for empty full-text query",
+			params: surf.SpotsParams{
+				Limit:  10,
+				Offset: 0,
+				SearchQuery: surf.SpotSearchQuery{
+					FullText: true,
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
 				{
-					Name: "Spot 3",
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "Country code 3",
+						Locality:    "Locality 1",
+						CountryCode: "kz",
 						Coordinates: geo.Coordinates{
 							Latitude:  1.23,
 							Longitude: 3.21,
 						},
 					},
 				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spots by country code and query including spot id without error",
+			params: surf.SpotsParams{
+				Limit:       10,
+				Offset:      0,
+				CountryCode: "kz",
+				SearchQuery: surf.SpotSearchQuery{
+					Query:      "query",
+					WithSpotID: true,
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count "+
+							"FROM spots "+
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id "+
+							"WHERE deleted_at IS NULL AND country_code = $1 "+
+							"AND (name ILIKE $2 ESCAPE '\\' OR locality ILIKE $3 ESCAPE '\\' OR CAST(id AS VARCHAR) ILIKE $4 ESCAPE '\\') LIMIT 10 OFFSET 0",
+					)).
+					WithArgs("kz", "%query%", "%query%", "%query%").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
 				{
-					Name: "Spot 4",
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "",
+						Locality:    "Locality 1",
+						CountryCode: "kz",
 						Coordinates: geo.Coordinates{
 							Latitude:  1.23,
 							Longitude: 3.21,
@@ -707,41 +1019,57 @@ func TestSpotStore_CreateSpots(t *testing.T) {
 					},
 				},
 				{
-					Name: "Spot 5",
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
 					Location: geo.Location{
-						Locality:    "Locality 5",
-						CountryCode: "Country code 5",
+						Locality:    "Locality 2",
+						CountryCode: "kz",
 						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+							Latitude:  2.34,
+							Longitude: 4.32,
 						},
 					},
 				},
 			},
-			expectedErrFn: assert.Error,
+			expectedErrFn: assert.NoError,
 		},
 		{
-			name:      "return error during query execution",
-			batchSize: 2,
+			name: "return spots having all of tags without error",
+			params: surf.SpotsParams{
+				Limit:  10,
+				Offset: 0,
+				Tags:   []string{"reef", "point"},
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
-				m.ExpectBegin()
-
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL " +
+							"AND ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) @> $1 LIMIT 10 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
-						"Spot 2", 1.23, 3.21, "Locality 2", "",
+					WithArgs(pq.Array([]string{"reef", "point"})).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "tags",
+						}).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), "{point,reef}",
+						),
 					).
-					WillReturnError(errors.New("something went wrong"))
-
-				m.ExpectRollback()
+					RowsWillBeClosed()
 			},
-			entries: []surf.SpotCreationEntry{
+			expectedSpots: []surf.Spot{
 				{
-					Name: "Spot 1",
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					Tags:      []string{"point", "reef"},
 					Location: geo.Location{
 						Locality:    "Locality 1",
 						CountryCode: "Country code 1",
@@ -751,77 +1079,46 @@ func TestSpotStore_CreateSpots(t *testing.T) {
 						},
 					},
 				},
-				{
-					Name: "Spot 2",
-					Location: geo.Location{
-						Locality:    "Locality 2",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 3",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "Country code 3",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 4",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 5",
-					Location: geo.Location{
-						Locality:    "Locality 5",
-						CountryCode: "Country code 5",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
 			},
-			expectedErrFn: assert.Error,
+			expectedErrFn: assert.NoError,
 		},
 		{
-			name:      "return error when reading affected rows",
-			batchSize: 2,
+			name: "return spots having any of tags without error",
+			params: surf.SpotsParams{
+				Limit:     10,
+				Offset:    0,
+				Tags:      []string{"reef", "point"},
+				TagsMatch: surf.SpotTagsMatchAny,
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
-				m.ExpectBegin()
-
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL " +
+							"AND ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) && $1 LIMIT 10 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
-						"Spot 2", 1.23, 3.21, "Locality 2", "",
+					WithArgs(pq.Array([]string{"reef", "point"})).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "tags",
+						}).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), "{reef}",
+						),
 					).
-					WillReturnResult(sqlmock.NewErrorResult(
-						errors.New("something went wrong"),
-					))
-
-				m.ExpectRollback()
+					RowsWillBeClosed()
 			},
-			entries: []surf.SpotCreationEntry{
+			expectedSpots: []surf.Spot{
 				{
-					Name: "Spot 1",
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					Tags:      []string{"reef"},
 					Location: geo.Location{
 						Locality:    "Locality 1",
 						CountryCode: "Country code 1",
@@ -831,226 +1128,463 @@ func TestSpotStore_CreateSpots(t *testing.T) {
 						},
 					},
 				},
-				{
-					Name: "Spot 2",
-					Location: geo.Location{
-						Locality:    "Locality 2",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 3",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "Country code 3",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 4",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 5",
-					Location: geo.Location{
-						Locality:    "Locality 5",
-						CountryCode: "Country code 5",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
 			},
-			expectedErrFn: assert.Error,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			spots, err := store.Spots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_Spots_ContextCanceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer db.Close()
+
+	mock.
+		ExpectQuery(regexp.QuoteMeta(
+			"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+				"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+				"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+				"FROM spots " +
+				"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+				"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
+		)).
+		WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	spots, err := store.Spots(ctx, surf.SpotsParams{
+		Limit:  10,
+		Offset: 0,
+	})
+	assert.Error(t, err)
+	assert.Nil(t, spots)
+}
+
+func TestSpotStore_RequestIDTagging(t *testing.T) {
+	type requestIDContextKey struct{}
+
+	requestIDFromContext := func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDContextKey{}).(string)
+		return id
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		opts          []SpotStoreOption
+		expectedQuery string
+	}{
+		{
+			name: "tag query with request id from context",
+			ctx:  context.WithValue(context.Background(), requestIDContextKey{}, "abc-123"),
+			opts: []SpotStoreOption{WithRequestIDTagging(requestIDFromContext)},
+			expectedQuery: "/* request_id=abc-123 */ " +
+				"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
 		},
 		{
-			name:      "return error when no rows affected",
-			batchSize: 2,
-			mockFn: func(m sqlmock.Sqlmock) {
-				m.ExpectBegin()
+			name:          "leave query untagged when context holds no request id",
+			ctx:           context.Background(),
+			opts:          []SpotStoreOption{WithRequestIDTagging(requestIDFromContext)},
+			expectedQuery: "UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+		},
+		{
+			name:          "leave query untagged when tagging is disabled",
+			ctx:           context.WithValue(context.Background(), requestIDContextKey{}, "abc-123"),
+			opts:          nil,
+			expectedQuery: "UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			mock.
+				ExpectExec(test.expectedQuery).
+				WithArgs("1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
 
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), test.opts...)
+			err = store.DeleteSpot(test.ctx, "1")
+			assert.NoError(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_WithReader(t *testing.T) {
+	primaryDB, primaryMock, err := sqlmock.New()
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer primaryDB.Close()
+
+	readerDB, readerMock, err := sqlmock.New()
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer readerDB.Close()
+
+	readerMock.
+		ExpectQuery("SELECT (.+) FROM spots").
+		WithArgs("1").
+		WillReturnError(sql.ErrNoRows)
+
+	primaryMock.
+		ExpectExec("UPDATE spots SET deleted_at = NOW()").
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	store := NewSpotStore(
+		sqlx.NewDb(primaryDB, psqlutil.DriverNameSQLMock),
+		WithReader(sqlx.NewDb(readerDB, psqlutil.DriverNameSQLMock)),
+	)
+
+	_, err = store.Spot(context.Background(), "1")
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	err = store.DeleteSpot(context.Background(), "1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, readerMock.ExpectationsWereMet())
+}
+
+func TestSpotStore_IterateSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        surf.SpotIterationParams
+		mockFn        func(sqlmock.Sqlmock)
+		fnErr         error
+		expectedIDs   []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "return error during query execution",
+			params: surf.SpotIterationParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, external_ref " +
+							"FROM spots WHERE deleted_at IS NULL ORDER BY id LIMIT 2 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
-						"Spot 2", 1.23, 3.21, "Locality 2", "",
-					).
-					WillReturnResult(sqlmock.NewResult(0, 0))
-
-				m.ExpectRollback()
-			},
-			entries: []surf.SpotCreationEntry{
-				{
-					Name: "Spot 1",
-					Location: geo.Location{
-						Locality:    "Locality 1",
-						CountryCode: "Country code 1",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 2",
-					Location: geo.Location{
-						Locality:    "Locality 2",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 3",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "Country code 3",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 4",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 5",
-					Location: geo.Location{
-						Locality:    "Locality 5",
-						CountryCode: "Country code 5",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
+					WillReturnError(errors.New("something went wrong"))
 			},
+			expectedIDs:   nil,
 			expectedErrFn: assert.Error,
 		},
 		{
-			name:      "return spots without error",
-			batchSize: 2,
+			name:   "return error returned by function",
+			params: surf.SpotIterationParams{},
 			mockFn: func(m sqlmock.Sqlmock) {
-				m.ExpectBegin()
-
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, external_ref " +
+							"FROM spots WHERE deleted_at IS NULL ORDER BY id LIMIT 2 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
-						"Spot 2", 1.23, 3.21, "Locality 2", "",
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
 					).
-					WillReturnResult(sqlmock.NewResult(0, 2))
-
+					RowsWillBeClosed()
+			},
+			fnErr:         errors.New("something went wrong"),
+			expectedIDs:   []string{"1"},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:   "return error during row iteration",
+			params: surf.SpotIterationParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, external_ref " +
+							"FROM spots WHERE deleted_at IS NULL ORDER BY id LIMIT 2 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 3", 1.23, 3.21, "", "Country code 3",
-						"Spot 4", 1.23, 3.21, "", "",
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)).
+						RowError(1, errors.New("something went wrong")),
 					).
-					WillReturnResult(sqlmock.NewResult(0, 2))
-
+					RowsWillBeClosed()
+			},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "iterate over spots page by page without error",
+			params: surf.SpotIterationParams{
+				CountryCode: "kz",
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
-							"VALUES ($1,$2,$3,$4,$5)",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, external_ref " +
+							"FROM spots WHERE deleted_at IS NULL AND country_code = $1 ORDER BY id LIMIT 2 OFFSET 0",
 					)).
-					WithArgs(
-						"Spot 5", 1.23, 3.21, "Locality 5", "Country code 5",
+					WithArgs("kz").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "kz", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("2", "Spot 2", 2.34, 4.32, "Locality 2", "kz", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)),
 					).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+					RowsWillBeClosed()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, external_ref "+
+							"FROM spots WHERE deleted_at IS NULL AND country_code = $1 AND id > $2 ORDER BY id LIMIT 2 OFFSET 0",
+					)).
+					WithArgs("kz", "2").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("3", "Spot 3", 3.45, 5.43, "Locality 3", "kz", time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			expectedIDs:   []string{"1", "2", "3"},
+			expectedErrFn: assert.NoError,
+		},
+	}
 
-				m.ExpectCommit()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), WithBatchSize(2))
+
+			var ids []string
+			err = store.IterateSpots(context.Background(), test.params, func(s surf.Spot) error {
+				ids = append(ids, s.ID)
+				return test.fnErr
+			})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_ExplainSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        surf.SpotsParams
+		mockFn        func(sqlmock.Sqlmock)
+		expectedPlan  []byte
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			params: surf.SpotsParams{
+				Limit:  10,
+				Offset: 0,
 			},
-			entries: []surf.SpotCreationEntry{
-				{
-					Name: "Spot 1",
-					Location: geo.Location{
-						Locality:    "Locality 1",
-						CountryCode: "Country code 1",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"EXPLAIN (FORMAT JSON) " +
+							"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL LIMIT 10 OFFSET 0",
+					)).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedPlan:  nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return plan without error",
+			params: surf.SpotsParams{
+				Limit:       10,
+				Offset:      0,
+				CountryCode: "kz",
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"EXPLAIN (FORMAT JSON) " +
+							"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+							"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+							"FROM spots " +
+							"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+							"WHERE deleted_at IS NULL AND country_code = $1 LIMIT 10 OFFSET 0",
+					)).
+					WithArgs("kz").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"QUERY PLAN"}).
+						AddRow([]byte(`[{"Plan":{"Node Type":"Limit"}}]`)),
+					)
+			},
+			expectedPlan:  []byte(`[{"Plan":{"Node Type":"Limit"}}]`),
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			plan, err := store.ExplainSpots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPlan, plan)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_RandomSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        surf.RandomSpotsParams
+		mockFn        func(sqlmock.Sqlmock)
+		expectedSpots []surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			params: surf.RandomSpotsParams{
+				Limit: 3,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags " +
+							"FROM spots WHERE deleted_at IS NULL ORDER BY random() LIMIT 3 OFFSET 0",
+					)).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error during scanning rows",
+			params: surf.RandomSpotsParams{
+				Limit: 3,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags " +
+							"FROM spots WHERE deleted_at IS NULL ORDER BY random() LIMIT 3 OFFSET 0",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow(1, true, "1.23", "3.21", "Locality 1", "Country code 1", "Not-a-time"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return random spots by country without error",
+			params: surf.RandomSpotsParams{
+				Limit:       3,
+				CountryCode: "kz",
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags " +
+							"FROM spots WHERE deleted_at IS NULL AND country_code = $1 ORDER BY random() LIMIT 3 OFFSET 0",
+					)).
+					WithArgs("kz").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+						}).
+						AddRow(
+							"2", "Spot 2", 2.34, 4.32, "Locality 2", "kz",
+							time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+							time.Date(2021, 1, 2, 1, 1, 1, 1, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
 				{
+					ID:   "2",
 					Name: "Spot 2",
 					Location: geo.Location{
-						Locality:    "Locality 2",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 3",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "Country code 3",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 4",
-					Location: geo.Location{
-						Locality:    "",
-						CountryCode: "",
-						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
-						},
-					},
-				},
-				{
-					Name: "Spot 5",
-					Location: geo.Location{
-						Locality:    "Locality 5",
-						CountryCode: "Country code 5",
 						Coordinates: geo.Coordinates{
-							Latitude:  1.23,
-							Longitude: 3.21,
+							Latitude:  2.34,
+							Longitude: 4.32,
 						},
+						Locality:    "Locality 2",
+						CountryCode: "kz",
 					},
+					CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
+					UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 1, time.UTC),
 				},
 			},
 			expectedErrFn: assert.NoError,
@@ -1067,157 +1601,5274 @@ func TestSpotStore_CreateSpots(t *testing.T) {
 
 			test.mockFn(mock)
 
-			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), WithBatchSize(test.batchSize))
-			err = store.CreateSpots(test.entries)
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			spots, err := store.RandomSpots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CountriesWithSpotCounts(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         surf.CountriesParams
+		mockFn         func(sqlmock.Sqlmock)
+		expectedCounts []surf.CountrySpotCount
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "return error during query execution",
+			params: surf.CountriesParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT country_code, COUNT(*) AS count FROM spots WHERE deleted_at IS NULL " +
+							"GROUP BY country_code ORDER BY count DESC, country_code",
+					)).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name:   "return error during scanning rows",
+			params: surf.CountriesParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT country_code, COUNT(*) AS count FROM spots WHERE deleted_at IS NULL " +
+							"GROUP BY country_code ORDER BY count DESC, country_code",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"country_code", "count"}).
+						AddRow("kz", "not an int"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name:   "return no counts without error",
+			params: surf.CountriesParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT country_code, COUNT(*) AS count FROM spots WHERE deleted_at IS NULL " +
+							"GROUP BY country_code ORDER BY count DESC, country_code",
+					)).
+					WillReturnRows(sqlmock.NewRows([]string{"country_code", "count"})).
+					RowsWillBeClosed()
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.NoError,
+		},
+		{
+			name: "return counts by search query without error",
+			params: surf.CountriesParams{
+				SearchQuery: surf.SpotSearchQuery{
+					Query: "query",
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT country_code, COUNT(*) AS count FROM spots WHERE deleted_at IS NULL "+
+							"AND (name ILIKE $1 ESCAPE '\\' OR locality ILIKE $2 ESCAPE '\\') "+
+							"GROUP BY country_code ORDER BY count DESC, country_code",
+					)).
+					WithArgs("%query%", "%query%").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"country_code", "count"}).
+						AddRow("kz", 12).
+						AddRow("id", 3),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: []surf.CountrySpotCount{
+				{
+					CountryCode: "kz",
+					Count:       12,
+				},
+				{
+					CountryCode: "id",
+					Count:       3,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			counts, err := store.CountriesWithSpotCounts(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotDirectory(t *testing.T) {
+	const query = "WITH countries AS (" +
+		"SELECT country_code, COUNT(*) AS spot_count, COUNT(DISTINCT locality) AS locality_count " +
+		"FROM spots WHERE deleted_at IS NULL " +
+		"GROUP BY country_code ORDER BY country_code LIMIT $1 OFFSET $2" +
+		"), localities AS (" +
+		"SELECT country_code, locality, COUNT(*) AS spot_count, " +
+		"ROW_NUMBER() OVER (PARTITION BY country_code ORDER BY locality) AS locality_rank " +
+		"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries) " +
+		"GROUP BY country_code, locality" +
+		"), ranked_spots AS (" +
+		"SELECT id, name, latitude, longitude, country_code, locality, " +
+		"ROW_NUMBER() OVER (PARTITION BY country_code, locality ORDER BY name, id) AS spot_rank " +
+		"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries)" +
+		") " +
+		"SELECT c.country_code, c.spot_count AS country_spot_count, c.locality_count, " +
+		"l.locality, l.spot_count AS locality_spot_count, s.id, s.name, s.latitude, s.longitude " +
+		"FROM countries AS c " +
+		"JOIN localities AS l ON l.country_code = c.country_code AND l.locality_rank <= $3 " +
+		"JOIN ranked_spots AS s ON s.country_code = l.country_code AND s.locality = l.locality AND s.spot_rank <= $4 " +
+		"ORDER BY c.country_code, l.locality, s.spot_rank"
+
+	columns := []string{
+		"country_code", "country_spot_count", "locality_count", "locality", "locality_spot_count",
+		"id", "name", "latitude", "longitude",
+	}
+
+	params := surf.SpotDirectoryParams{
+		Limit:                10,
+		Offset:               20,
+		LocalitiesPerCountry: 2,
+		SpotsPerLocality:     3,
+	}
+
+	tests := []struct {
+		name              string
+		mockFn            func(sqlmock.Sqlmock)
+		expectedCountries []surf.DirectoryCountry
+		expectedErrFn     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(10, 20, 2, 3).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedCountries: nil,
+			expectedErrFn:     assert.Error,
+		},
+		{
+			name: "return error during scanning rows",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(10, 20, 2, 3).
+					WillReturnRows(sqlmock.
+						NewRows(columns).
+						AddRow("kz", "not an int", 1, "Aktau", 1, "1", "Spot 1", 1.23, 3.21),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCountries: nil,
+			expectedErrFn:     assert.Error,
+		},
+		{
+			name: "return no countries without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(10, 20, 2, 3).
+					WillReturnRows(sqlmock.NewRows(columns)).
+					RowsWillBeClosed()
+			},
+			expectedCountries: nil,
+			expectedErrFn:     assert.NoError,
+		},
+		{
+			name: "return countries with nested localities and spots without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(10, 20, 2, 3).
+					WillReturnRows(sqlmock.
+						NewRows(columns).
+						AddRow("au", 7, 3, "Margaret River", 2, "1", "Main Break", 1.1, 2.1).
+						AddRow("au", 7, 3, "Margaret River", 2, "2", "The Box", 1.2, 2.2).
+						AddRow("au", 7, 3, "Perth", 4, "3", "Cables", 1.3, 2.3).
+						AddRow("kz", 1, 1, "Aktau", 1, "4", "Aktau Beach", 1.4, 2.4),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCountries: []surf.DirectoryCountry{
+				{
+					CountryCode:   "au",
+					SpotCount:     7,
+					LocalityCount: 3,
+					Localities: []surf.DirectoryLocality{
+						{
+							Locality:  "Margaret River",
+							SpotCount: 2,
+							Spots: []surf.Spot{
+								{
+									ID:   "1",
+									Name: "Main Break",
+									Location: geo.Location{
+										Locality:    "Margaret River",
+										CountryCode: "au",
+										Coordinates: geo.Coordinates{Latitude: 1.1, Longitude: 2.1},
+									},
+								},
+								{
+									ID:   "2",
+									Name: "The Box",
+									Location: geo.Location{
+										Locality:    "Margaret River",
+										CountryCode: "au",
+										Coordinates: geo.Coordinates{Latitude: 1.2, Longitude: 2.2},
+									},
+								},
+							},
+						},
+						{
+							Locality:  "Perth",
+							SpotCount: 4,
+							Spots: []surf.Spot{
+								{
+									ID:   "3",
+									Name: "Cables",
+									Location: geo.Location{
+										Locality:    "Perth",
+										CountryCode: "au",
+										Coordinates: geo.Coordinates{Latitude: 1.3, Longitude: 2.3},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					CountryCode:   "kz",
+					SpotCount:     1,
+					LocalityCount: 1,
+					Localities: []surf.DirectoryLocality{
+						{
+							Locality:  "Aktau",
+							SpotCount: 1,
+							Spots: []surf.Spot{
+								{
+									ID:   "4",
+									Name: "Aktau Beach",
+									Location: geo.Location{
+										Locality:    "Aktau",
+										CountryCode: "kz",
+										Coordinates: geo.Coordinates{Latitude: 1.4, Longitude: 2.4},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			countries, err := store.SpotDirectory(context.Background(), params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCountries, countries)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotCreationCounts(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		params         surf.SpotCreationCountsParams
+		mockFn         func(sqlmock.Sqlmock)
+		expectedCounts []surf.SpotCreationCount
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			params: surf.SpotCreationCountsParams{
+				From: from,
+				To:   to,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('month', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return error during scanning rows",
+			params: surf.SpotCreationCountsParams{
+				From: from,
+				To:   to,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('month', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"bucket_start", "count"}).
+						AddRow(from, "not an int"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return no counts without error",
+			params: surf.SpotCreationCountsParams{
+				From: from,
+				To:   to,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('month', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"bucket_start", "count"})).
+					RowsWillBeClosed()
+			},
+			expectedCounts: nil,
+			expectedErrFn:  assert.NoError,
+		},
+		{
+			name: "return monthly counts without error",
+			params: surf.SpotCreationCountsParams{
+				From:     from,
+				To:       to,
+				Interval: surf.TimeIntervalMonth,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('month', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"bucket_start", "count"}).
+						AddRow(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 4).
+						AddRow(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), 9),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: []surf.SpotCreationCount{
+				{
+					BucketStart: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					Count:       4,
+				},
+				{
+					BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+					Count:       9,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return weekly counts without error",
+			params: surf.SpotCreationCountsParams{
+				From:     from,
+				To:       to,
+				Interval: surf.TimeIntervalWeek,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('week', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"bucket_start", "count"}).
+						AddRow(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 2),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: []surf.SpotCreationCount{
+				{
+					BucketStart: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+					Count:       2,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return daily counts without error",
+			params: surf.SpotCreationCountsParams{
+				From:     from,
+				To:       to,
+				Interval: surf.TimeIntervalDay,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT date_trunc('day', created_at) AS bucket_start, COUNT(*) AS count FROM spots "+
+							"WHERE deleted_at IS NULL AND created_at >= $1 AND created_at < $2 "+
+							"GROUP BY bucket_start ORDER BY bucket_start",
+					)).
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"bucket_start", "count"}).
+						AddRow(time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC), 1),
+					).
+					RowsWillBeClosed()
+			},
+			expectedCounts: []surf.SpotCreationCount{
+				{
+					BucketStart: time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC),
+					Count:       1,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			counts, err := store.SpotCreationCounts(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotsCentroid(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           surf.SpotsCentroidParams
+		mockFn           func(sqlmock.Sqlmock)
+		expectedCentroid *geo.Coordinates
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "return error during query execution",
+			params: surf.SpotsCentroidParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT COUNT(*) AS count, AVG(latitude) AS latitude, " +
+							"AVG(SIN(RADIANS(longitude))) AS longitude_sin, AVG(COS(RADIANS(longitude))) AS longitude_cos " +
+							"FROM spots WHERE deleted_at IS NULL",
+					)).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedCentroid: nil,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name:   "return nil centroid when no spots match",
+			params: surf.SpotsCentroidParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT COUNT(*) AS count, AVG(latitude) AS latitude, " +
+							"AVG(SIN(RADIANS(longitude))) AS longitude_sin, AVG(COS(RADIANS(longitude))) AS longitude_cos " +
+							"FROM spots WHERE deleted_at IS NULL",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"count", "latitude", "longitude_sin", "longitude_cos"}).
+						AddRow(0, nil, nil, nil),
+					)
+			},
+			expectedCentroid: nil,
+			expectedErrFn:    assert.NoError,
+		},
+		{
+			name: "return centroid by filters without error",
+			params: surf.SpotsCentroidParams{
+				CountryCode: "kz",
+				SearchQuery: surf.SpotSearchQuery{
+					Query: "query",
+				},
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT COUNT(*) AS count, AVG(latitude) AS latitude, "+
+							"AVG(SIN(RADIANS(longitude))) AS longitude_sin, AVG(COS(RADIANS(longitude))) AS longitude_cos "+
+							"FROM spots WHERE deleted_at IS NULL AND country_code = $1 "+
+							"AND (name ILIKE $2 ESCAPE '\\' OR locality ILIKE $3 ESCAPE '\\') "+
+							"AND (latitude BETWEEN $4 AND $5 AND longitude BETWEEN $6 AND $7)",
+					)).
+					WithArgs("kz", "%query%", "%query%", -90.0, 90.0, -180.0, 180.0).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"count", "latitude", "longitude_sin", "longitude_cos"}).
+						AddRow(2, 10.5, math.Sin(20*math.Pi/180), math.Cos(20*math.Pi/180)),
+					)
+			},
+			expectedCentroid: &geo.Coordinates{
+				Latitude:  10.5,
+				Longitude: 20,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:   "return centroid near antimeridian for spots spanning it",
+			params: surf.SpotsCentroidParams{},
+			mockFn: func(m sqlmock.Sqlmock) {
+				// Spots at longitudes 170 and -170, whose naive average is 0.
+				sin := (math.Sin(170*math.Pi/180) + math.Sin(-170*math.Pi/180)) / 2
+				cos := (math.Cos(170*math.Pi/180) + math.Cos(-170*math.Pi/180)) / 2
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT COUNT(*) AS count, AVG(latitude) AS latitude, " +
+							"AVG(SIN(RADIANS(longitude))) AS longitude_sin, AVG(COS(RADIANS(longitude))) AS longitude_cos " +
+							"FROM spots WHERE deleted_at IS NULL",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"count", "latitude", "longitude_sin", "longitude_cos"}).
+						AddRow(2, -15.0, sin, cos),
+					)
+			},
+			expectedCentroid: &geo.Coordinates{
+				Latitude:  -15,
+				Longitude: 180,
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			centroid, err := store.SpotsCentroid(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			if test.expectedCentroid == nil {
+				assert.Nil(t, centroid)
+			} else if assert.NotNil(t, centroid) {
+				assert.InDelta(t, test.expectedCentroid.Latitude, centroid.Latitude, 1e-9)
+				// Longitudes of 180 and -180 denote the same meridian.
+				diff := math.Mod(math.Abs(test.expectedCentroid.Longitude-centroid.Longitude), 360)
+				assert.InDelta(t, 0, math.Min(diff, 360-diff), 1e-9)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_NearbySpots(t *testing.T) {
+	params := surf.NearbySpotsParams{
+		Coordinates: geo.Coordinates{
+			Latitude:  1.23,
+			Longitude: 3.21,
+		},
+		RadiusKm: 50,
+		Limit:    10,
+	}
+
+	tests := []struct {
+		name          string
+		params        surf.NearbySpotsParams
+		mockFn        func(sqlmock.Sqlmock)
+		expectedSpots []surf.NearbySpot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "return error during query execution",
+			params: params,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT * FROM ("+
+							"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"(2 * 6371 * ASIN(LEAST(1, SQRT("+
+							"POWER(SIN(RADIANS(latitude - $1) / 2), 2) + "+
+							"COS(RADIANS($2)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $3) / 2), 2)"+
+							")))) AS distance_km "+
+							"FROM spots WHERE deleted_at IS NULL"+
+							") AS s WHERE distance_km <= $4 ORDER BY distance_km, id LIMIT 10",
+					)).
+					WithArgs(1.23, 1.23, 3.21, 50.0).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:   "return error during scanning rows",
+			params: params,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT * FROM ("+
+							"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"(2 * 6371 * ASIN(LEAST(1, SQRT("+
+							"POWER(SIN(RADIANS(latitude - $1) / 2), 2) + "+
+							"COS(RADIANS($2)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $3) / 2), 2)"+
+							")))) AS distance_km "+
+							"FROM spots WHERE deleted_at IS NULL"+
+							") AS s WHERE distance_km <= $4 ORDER BY distance_km, id LIMIT 10",
+					)).
+					WithArgs(1.23, 1.23, 3.21, 50.0).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "distance_km"}).
+						AddRow("1", "not a float"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:   "return nearby spots without error",
+			params: params,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT * FROM ("+
+							"SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, "+
+							"(2 * 6371 * ASIN(LEAST(1, SQRT("+
+							"POWER(SIN(RADIANS(latitude - $1) / 2), 2) + "+
+							"COS(RADIANS($2)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $3) / 2), 2)"+
+							")))) AS distance_km "+
+							"FROM spots WHERE deleted_at IS NULL"+
+							") AS s WHERE distance_km <= $4 ORDER BY distance_km, id LIMIT 10",
+					)).
+					WithArgs(1.23, 1.23, 3.21, 50.0).
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at", "distance_km",
+						}).
+						AddRow(
+							"1", "Spot 1", 1.24, 3.22, "Locality 1", "kz",
+							time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+							time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+							1.5725,
+						).
+						AddRow(
+							"2", "Spot 2", 1.33, 3.31, "Locality 2", "kz",
+							time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+							time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+							15.7248,
+						),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.NearbySpot{
+				{
+					Spot: surf.Spot{
+						ID:   "1",
+						Name: "Spot 1",
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.24,
+								Longitude: 3.22,
+							},
+							Locality:    "Locality 1",
+							CountryCode: "kz",
+						},
+						CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+						UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+					},
+					DistanceKm: 1.5725,
+				},
+				{
+					Spot: surf.Spot{
+						ID:   "2",
+						Name: "Spot 2",
+						Location: geo.Location{
+							Coordinates: geo.Coordinates{
+								Latitude:  1.33,
+								Longitude: 3.31,
+							},
+							Locality:    "Locality 2",
+							CountryCode: "kz",
+						},
+						CreatedAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+						UpdatedAt: time.Date(2021, 1, 2, 1, 1, 1, 0, time.UTC),
+					},
+					DistanceKm: 15.7248,
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			spots, err := store.NearbySpots(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotMarkers(t *testing.T) {
+	tests := []struct {
+		name            string
+		params          surf.SpotMarkersParams
+		mockFn          func(sqlmock.Sqlmock)
+		expectedMarkers []surf.SpotMarker
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			params: surf.SpotMarkersParams{
+				Limit: 10,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, latitude, longitude FROM spots WHERE deleted_at IS NULL LIMIT 10",
+					)).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedMarkers: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return error during scanning rows",
+			params: surf.SpotMarkersParams{
+				Limit: 10,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, latitude, longitude FROM spots WHERE deleted_at IS NULL LIMIT 10",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "latitude", "longitude"}).
+						AddRow("1", "not a float", 3.21),
+					).
+					RowsWillBeClosed()
+			},
+			expectedMarkers: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return markers without error",
+			params: surf.SpotMarkersParams{
+				Limit: 10,
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, latitude, longitude FROM spots WHERE deleted_at IS NULL LIMIT 10",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "latitude", "longitude"}).
+						AddRow("1", 1.23, 3.21).
+						AddRow("2", 2.34, 4.32),
+					).
+					RowsWillBeClosed()
+			},
+			expectedMarkers: []surf.SpotMarker{
+				{
+					ID: "1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+				{
+					ID: "2",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return markers by bounds without error",
+			params: surf.SpotMarkersParams{
+				Limit: 10,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{
+						Latitude:  90,
+						Longitude: 180,
+					},
+					SouthWest: geo.Coordinates{
+						Latitude:  -90,
+						Longitude: -180,
+					},
+				},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, latitude, longitude "+
+							"FROM spots WHERE deleted_at IS NULL AND (latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4) "+
+							"LIMIT 10",
+					)).
+					WithArgs(-90.0, 90.0, -180.0, 180.0).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "latitude", "longitude"}).
+						AddRow("1", 1.23, 3.21),
+					).
+					RowsWillBeClosed()
+			},
+			expectedMarkers: []surf.SpotMarker{
+				{
+					ID: "1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			markers, err := store.SpotMarkers(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedMarkers, markers)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpot(t *testing.T) {
+	const insertWithSlugQuery = "INSERT INTO spots (name,latitude,longitude,locality,country_code,slug) " +
+		"VALUES ($1,$2,$3,$4,$5,$6) " +
+		"ON CONFLICT ((lower(slug))) WHERE deleted_at IS NULL DO NOTHING " +
+		"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+		"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags"
+
+	var (
+		createdAt = time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+		slugEntry = surf.SpotCreationEntry{
+			Name: "Spot 1",
+			Slug: "spot-1-locality-1",
+			Location: geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "Country code 1",
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+		}
+		slugSpotColumns = []string{
+			"id", "name", "latitude", "longitude", "locality", "country_code", "slug", "created_at", "updated_at",
+		}
+		slugSpotRow = func(slug string) []driver.Value {
+			return []driver.Value{"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", slug, createdAt, createdAt}
+		}
+		slugSpot = func(slug string) surf.Spot {
+			return surf.Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				Slug:      slug,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Location:  slugEntry.Location,
+			}
+		}
+	)
+
+	tests := []struct {
+		name          string
+		opts          []SpotStoreOption
+		mockFn        func(sqlmock.Sqlmock)
+		params        surf.SpotCreationEntry
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
+							"VALUES ($1,$2,$3,$4,$5) "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			params: surf.SpotCreationEntry{
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return duplicate spot error for unique violation",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
+							"VALUES ($1,$2,$3,$4,$5) "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			params: surf.SpotCreationEntry{
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrDuplicateSpot),
+		},
+		{
+			name: "return spot without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code) "+
+							"VALUES ($1,$2,$3,$4,$5) "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotCreationEntry{
+				Name: "Spot 1",
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return error during slug query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1-locality-1").
+					WillReturnRows(sqlmock.NewRows(slugSpotColumns))
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT lower(slug) FROM spots WHERE (lower(slug) = $1 OR lower(slug) LIKE $2) AND deleted_at IS NULL",
+					)).
+					WithArgs("spot-1-locality-1", "spot-1-locality-1-%").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			params:        slugEntry,
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spot with slug without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1-locality-1").
+					WillReturnRows(sqlmock.
+						NewRows(slugSpotColumns).
+						AddRow(slugSpotRow("spot-1-locality-1")...),
+					).
+					RowsWillBeClosed()
+			},
+			params:        slugEntry,
+			expectedSpot:  slugSpot("spot-1-locality-1"),
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot with suffixed slug without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1-locality-1").
+					WillReturnRows(sqlmock.NewRows(slugSpotColumns))
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT lower(slug) FROM spots WHERE (lower(slug) = $1 OR lower(slug) LIKE $2) AND deleted_at IS NULL",
+					)).
+					WithArgs("spot-1-locality-1", "spot-1-locality-1-%").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"slug"}).
+						AddRow("spot-1-locality-1").
+						AddRow("spot-1-locality-1-2"),
+					).
+					RowsWillBeClosed()
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1-locality-1-3").
+					WillReturnRows(sqlmock.
+						NewRows(slugSpotColumns).
+						AddRow(slugSpotRow("spot-1-locality-1-3")...),
+					).
+					RowsWillBeClosed()
+			},
+			params:        slugEntry,
+			expectedSpot:  slugSpot("spot-1-locality-1-3"),
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return duplicate spot error when slugs keep getting taken",
+			mockFn: func(m sqlmock.Sqlmock) {
+				for i := 0; i < 3; i++ {
+					m.
+						ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+						WillReturnRows(sqlmock.NewRows(slugSpotColumns))
+					if i < 2 {
+						m.
+							ExpectQuery(regexp.QuoteMeta(
+								"SELECT lower(slug) FROM spots WHERE (lower(slug) = $1 OR lower(slug) LIKE $2) AND deleted_at IS NULL",
+							)).
+							WithArgs("spot-1-locality-1", "spot-1-locality-1-%").
+							WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("spot-1-locality-1"))
+					}
+				}
+			},
+			params:        slugEntry,
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrDuplicateSpot),
+		},
+		{
+			name: "return spot with slug of soft-deleted spot suffixed when slugs are reserved",
+			opts: []SpotStoreOption{WithDeletedSlugsReserved()},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT lower(slug) FROM spots WHERE (lower(slug) = $1 OR lower(slug) LIKE $2)",
+					)).
+					WithArgs("spot-1-locality-1", "spot-1-locality-1-%").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"slug"}).
+						AddRow("spot-1-locality-1"),
+					).
+					RowsWillBeClosed()
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertWithSlugQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "spot-1-locality-1-2").
+					WillReturnRows(sqlmock.
+						NewRows(slugSpotColumns).
+						AddRow(slugSpotRow("spot-1-locality-1-2")...),
+					).
+					RowsWillBeClosed()
+			},
+			params:        slugEntry,
+			expectedSpot:  slugSpot("spot-1-locality-1-2"),
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), test.opts...)
+			spot, err := store.CreateSpot(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		batchSize     int
+		mode          surf.SpotCreationMode
+		mockFn        func(sqlmock.Sqlmock)
+		entries       []surf.SpotCreationEntry
+		expectedStats surf.SpotCreationStats
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error when nothing to import",
+			batchSize:     2,
+			mockFn:        func(m sqlmock.Sqlmock) {},
+			entries:       []surf.SpotCreationEntry{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "return error during tx init",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin().
+					WillReturnError(errors.New("something went wrong"))
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 4",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 5",
+					Location: geo.Location{
+						Locality:    "Locality 5",
+						CountryCode: "Country code 5",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "return error during query execution",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", nil,
+						"Spot 2", 1.23, 3.21, "Locality 2", "", nil,
+					).
+					WillReturnError(errors.New("something went wrong"))
+
+				m.ExpectRollback()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 4",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 5",
+					Location: geo.Location{
+						Locality:    "Locality 5",
+						CountryCode: "Country code 5",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "return error during row iteration",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", nil,
+						"Spot 2", 1.23, 3.21, "Locality 2", "", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("1").
+						AddRow("2").
+						RowError(1, errors.New("something went wrong")),
+					)
+
+				m.ExpectRollback()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 4",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 5",
+					Location: geo.Location{
+						Locality:    "Locality 5",
+						CountryCode: "Country code 5",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "return error when no rows affected",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", nil,
+						"Spot 2", 1.23, 3.21, "Locality 2", "", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}),
+					).
+					RowsWillBeClosed()
+
+				m.ExpectRollback()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 4",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 5",
+					Location: geo.Location{
+						Locality:    "Locality 5",
+						CountryCode: "Country code 5",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "return spots without error",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", nil,
+						"Spot 2", 1.23, 3.21, "Locality 2", "", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("1").
+						AddRow("2"),
+					).
+					RowsWillBeClosed()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 3", 1.23, 3.21, "", "Country code 3", nil,
+						"Spot 4", 1.23, 3.21, "", "", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("3").
+						AddRow("4"),
+					).
+					RowsWillBeClosed()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 5", 1.23, 3.21, "Locality 5", "Country code 5", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("5"),
+					).
+					RowsWillBeClosed()
+
+				m.ExpectCommit()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 4",
+					Location: geo.Location{
+						Locality:    "",
+						CountryCode: "",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 5",
+					Location: geo.Location{
+						Locality:    "Locality 5",
+						CountryCode: "Country code 5",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 5,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "return error during upsert query execution",
+			batchSize: 2,
+			mode:      surf.SpotCreationModeUpsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET external_ref = CAST(id AS VARCHAR) " +
+							"WHERE external_ref IS NULL AND CAST(id AS VARCHAR) IN ($1) AND deleted_at IS NULL",
+					)).
+					WithArgs("ref-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6) "+
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET "+
+							"name = EXCLUDED.name, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, "+
+							"locality = EXCLUDED.locality, country_code = EXCLUDED.country_code, updated_at = NOW() "+
+							"RETURNING (xmax = 0) AS inserted, id",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "ref-1").
+					WillReturnError(errors.New("something went wrong"))
+
+				m.ExpectRollback()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name:        "Spot 1",
+					ExternalRef: "ref-1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:      "insert spots in place of soft-deleted ones with same external references",
+			batchSize: 2,
+			mode:      surf.SpotCreationModeUpsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET external_ref = CAST(id AS VARCHAR) " +
+							"WHERE external_ref IS NULL AND CAST(id AS VARCHAR) IN ($1) AND deleted_at IS NULL",
+					)).
+					WithArgs("deleted-ref").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				// The soft-deleted spot does not satisfy the conflict target, so
+				// the entry gets inserted.
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6) "+
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET "+
+							"name = EXCLUDED.name, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, "+
+							"locality = EXCLUDED.locality, country_code = EXCLUDED.country_code, updated_at = NOW() "+
+							"RETURNING (xmax = 0) AS inserted, id",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "deleted-ref").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"inserted", "id"}).
+						AddRow(true, "6"),
+					).
+					RowsWillBeClosed()
+
+				m.ExpectCommit()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name:        "Spot 1",
+					ExternalRef: "deleted-ref",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 1,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "upsert spots without error",
+			batchSize: 2,
+			mode:      surf.SpotCreationModeUpsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET external_ref = CAST(id AS VARCHAR) "+
+							"WHERE external_ref IS NULL AND CAST(id AS VARCHAR) IN ($1,$2) AND deleted_at IS NULL",
+					)).
+					WithArgs("ref-1", "ref-2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) "+
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET "+
+							"name = EXCLUDED.name, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, "+
+							"locality = EXCLUDED.locality, country_code = EXCLUDED.country_code, updated_at = NOW() "+
+							"RETURNING (xmax = 0) AS inserted, id",
+					)).
+					WithArgs(
+						"Spot 1 (updated)", 1.23, 3.21, "Locality 1", "Country code 1", "ref-1",
+						"Spot 2", 1.23, 3.21, "Locality 2", "Country code 2", "ref-2",
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"inserted", "id"}).
+						AddRow(false, "7").
+						AddRow(true, "8"),
+					).
+					RowsWillBeClosed()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6) "+
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET "+
+							"name = EXCLUDED.name, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, "+
+							"locality = EXCLUDED.locality, country_code = EXCLUDED.country_code, updated_at = NOW() "+
+							"RETURNING (xmax = 0) AS inserted, id",
+					)).
+					WithArgs("Spot 3", 1.23, 3.21, "Locality 3", "Country code 3", nil).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"inserted", "id"}).
+						AddRow(true, "9"),
+					).
+					RowsWillBeClosed()
+
+				m.ExpectCommit()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name:        "Spot 1",
+					ExternalRef: "ref-1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name:        "Spot 2",
+					ExternalRef: "ref-2",
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "Country code 2",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name:        "Spot 1 (updated)",
+					ExternalRef: "ref-1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 3",
+					Location: geo.Location{
+						Locality:    "Locality 3",
+						CountryCode: "Country code 3",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 2,
+				Updated:  1,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "insert spots with tags without error",
+			batchSize: 2,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WithArgs(
+						"Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", nil,
+						"Spot 2", 1.23, 3.21, "Locality 2", "Country code 2", nil,
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("1").
+						AddRow("2"),
+					).
+					RowsWillBeClosed()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"DELETE FROM spot_tags WHERE CAST(spot_id AS VARCHAR) = ANY($1)",
+					)).
+					WithArgs(pq.Array([]string{"2"})).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2),($3,$4)",
+					)).
+					WithArgs("2", "reef", "2", "left hand").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				m.ExpectCommit()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name: "Spot 1",
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+				{
+					Name: "Spot 2",
+					Tags: []string{"reef", "left hand"},
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "Country code 2",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 2,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:      "replace tags of upserted spots without error",
+			batchSize: 2,
+			mode:      surf.SpotCreationModeUpsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET external_ref = CAST(id AS VARCHAR) " +
+							"WHERE external_ref IS NULL AND CAST(id AS VARCHAR) IN ($1) AND deleted_at IS NULL",
+					)).
+					WithArgs("ref-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) "+
+							"VALUES ($1,$2,$3,$4,$5,$6) "+
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET "+
+							"name = EXCLUDED.name, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, "+
+							"locality = EXCLUDED.locality, country_code = EXCLUDED.country_code, updated_at = NOW() "+
+							"RETURNING (xmax = 0) AS inserted, id",
+					)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", "ref-1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"inserted", "id"}).
+						AddRow(false, "1"),
+					).
+					RowsWillBeClosed()
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"DELETE FROM spot_tags WHERE CAST(spot_id AS VARCHAR) = ANY($1)",
+					)).
+					WithArgs(pq.Array([]string{"1"})).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2)",
+					)).
+					WithArgs("1", "reef").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				m.ExpectCommit()
+			},
+			entries: []surf.SpotCreationEntry{
+				{
+					Name:        "Spot 1",
+					ExternalRef: "ref-1",
+					Tags:        []string{"reef"},
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedStats: surf.SpotCreationStats{
+				Updated: 1,
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), WithBatchSize(test.batchSize))
+			stats, err := store.CreateSpots(context.Background(), test.entries, test.mode)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedStats, stats)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_UpdateSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		params        surf.SpotUpdateEntry
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $6 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			params: surf.SpotUpdateEntry{
+				ID:          "1",
+				Name:        pconv.String("Updated spot 1"),
+				Locality:    pconv.String("Locality 1"),
+				CountryCode: pconv.String("Country code 1"),
+				Latitude:    pconv.Float64(2.34),
+				Longitude:   pconv.Float64(4.32),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $6 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			params: surf.SpotUpdateEntry{
+				ID:          "1",
+				Name:        pconv.String("Updated spot 1"),
+				Locality:    pconv.String("Locality 1"),
+				CountryCode: pconv.String("Country code 1"),
+				Latitude:    pconv.Float64(2.34),
+				Longitude:   pconv.Float64(4.32),
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name:   "return error when nothing to update",
+			mockFn: func(m sqlmock.Sqlmock) {},
+			params: surf.SpotUpdateEntry{
+				ID: "1",
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrEmptySpotUpdateEntry),
+		},
+		{
+			name: "return spot with suffixed slug without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT lower(slug) FROM spots WHERE (lower(slug) = $1 OR lower(slug) LIKE $2) AND deleted_at IS NULL AND CAST(id AS VARCHAR) <> $3",
+					)).
+					WithArgs("updated-spot-1", "updated-spot-1-%", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"slug"}).
+						AddRow("updated-spot-1"),
+					).
+					RowsWillBeClosed()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET name = $1, slug = $2, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $3 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Updated spot 1", "updated-spot-1-2", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "slug", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Updated spot 1", 2.34, 4.32, "", "kz", "updated-spot-1-2",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:   "1",
+				Name: pconv.String("Updated spot 1"),
+				Slug: pconv.String("updated-spot-1"),
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Updated spot 1",
+				Slug: "updated-spot-1-2",
+				Location: geo.Location{
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error for update clearing slug",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET slug = $1, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs(nil, "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "slug", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:   "1",
+				Slug: pconv.String(""),
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error for update clearing fields",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET locality = $1, region = $2, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $3 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("", "", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:       "1",
+				Locality: pconv.String(""),
+				Region:   pconv.String(""),
+			},
+			expectedSpot: surf.Spot{
+				ID:   "1",
+				Name: "Spot 1",
+				Location: geo.Location{
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error for full update",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $6 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+						}).
+						AddRow(
+							"1", "Updated spot 1", 2.34, 4.32, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:          "1",
+				Name:        pconv.String("Updated spot 1"),
+				Locality:    pconv.String("Locality 1"),
+				CountryCode: pconv.String("Country code 1"),
+				Latitude:    pconv.Float64(2.34),
+				Longitude:   pconv.Float64(4.32),
+			},
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Updated spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error for partial update",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET latitude = $1, name = $2, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $3 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs(2.34, "Updated spot 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Updated spot 1", 2.34, 4.32, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:       "1",
+				Name:     pconv.String("Updated spot 1"),
+				Latitude: pconv.Float64(2.34),
+			},
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Updated spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return spot without error for region update",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots "+
+							"SET region = $1, updated_at = NOW() "+
+							"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs("Region 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "region", "created_at",
+						}).
+						AddRow("1", "Spot 1", 2.34, 4.32, "Locality 1", "Country code 1", "Region 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotUpdateEntry{
+				ID:     "1",
+				Region: pconv.String("Region 1"),
+			},
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Region:      "Region 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			spot, err := store.UpdateSpot(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_DeleteSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		id            string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			id:            "1",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error when reading affected rows",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewErrorResult(
+						errors.New("unexpected error"),
+					))
+			},
+			id:            "1",
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			id:            "1",
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			id:            "1",
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.DeleteSpot(context.Background(), test.id)
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+const (
+	lockSpotQuery = "SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+		"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags " +
+		"FROM spots WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL FOR UPDATE"
+
+	insertAuditEntryQuery = "INSERT INTO audit_log (actor_id,action,spot_id,changes) VALUES ($1,$2,$3,$4)"
+)
+
+var lockedSpotRowColumns = []string{
+	"id", "name", "latitude", "longitude", "locality", "country_code", "slug", "created_at", "updated_at",
+}
+
+func TestSpotStore_UpdateSpot_Audited(t *testing.T) {
+	const updateQuery = "UPDATE spots SET name = $1, updated_at = NOW() " +
+		"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NULL " +
+		"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+		"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "roll back update when audit entry fails to be written",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("Updated spot 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Updated spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs(
+						"admin-1", "update", "1",
+						`[{"field":"name","before":"Spot 1","after":"Updated spot 1"}]`,
+					).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spot without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("Updated spot 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Updated spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs(
+						"admin-1", "update", "1",
+						`[{"field":"name","before":"Spot 1","after":"Updated spot 1"}]`,
+					).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Updated spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					CountryCode: "kz",
+					Coordinates: geo.Coordinates{
+						Latitude:  2.34,
+						Longitude: 4.32,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			spot, err := store.UpdateSpot(surf.ContextWithAuditActor(context.Background(), "admin-1"), surf.SpotUpdateEntry{
+				ID:   "1",
+				Name: pconv.String("Updated spot 1"),
+			})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_DeleteSpot_Audited(t *testing.T) {
+	const (
+		deleteQuery = "UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL"
+		changes     = `[{"field":"name","before":"Spot 1","after":null},` +
+			`{"field":"latitude","before":2.34,"after":null},` +
+			`{"field":"longitude","before":4.32,"after":null},` +
+			`{"field":"locality","before":"","after":null},` +
+			`{"field":"country_code","before":"kz","after":null},` +
+			`{"field":"region","before":"","after":null},` +
+			`{"field":"slug","before":"","after":null}]`
+	)
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "roll back deletion when audit entry fails to be written",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectExec(regexp.QuoteMeta(deleteQuery)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "delete", "1", changes).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectExec(regexp.QuoteMeta(deleteQuery)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "delete", "1", changes).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.DeleteSpot(surf.ContextWithAuditActor(context.Background(), "admin-1"), "1")
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpots_Audited(t *testing.T) {
+	const (
+		lockQuery = "SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+			"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags " +
+			"FROM spots WHERE external_ref IN ($1,$2) AND deleted_at IS NULL FOR UPDATE"
+		createChanges = `[{"field":"name","before":null,"after":"Spot 2"},` +
+			`{"field":"latitude","before":null,"after":1.23},` +
+			`{"field":"longitude","before":null,"after":3.21},` +
+			`{"field":"locality","before":null,"after":"Locality 2"},` +
+			`{"field":"country_code","before":null,"after":"kz"},` +
+			`{"field":"region","before":null,"after":""},` +
+			`{"field":"slug","before":null,"after":""}]`
+		updateChanges = `[{"field":"name","before":"Old spot 1","after":"Spot 1"}]`
+	)
+
+	entries := []surf.SpotCreationEntry{
+		{
+			Name:        "Spot 1",
+			ExternalRef: "ref-1",
+			Location: geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+		},
+		{
+			Name:        "Spot 2",
+			ExternalRef: "ref-2",
+			Location: geo.Location{
+				Locality:    "Locality 2",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		mode          surf.SpotCreationMode
+		mockFn        func(sqlmock.Sqlmock)
+		expectedStats surf.SpotCreationStats
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "roll back insertion when audit entry fails to be written",
+			mode: surf.SpotCreationModeInsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) " +
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "create", "1", sqlmock.AnyArg()).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedStats: surf.SpotCreationStats{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "audit inserted spots without error",
+			mode: surf.SpotCreationModeInsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) " +
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id",
+					)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "create", "1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "create", "2", createChanges).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 2,
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "audit updated and inserted spots without error",
+			mode: surf.SpotCreationModeUpsert,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"UPDATE spots SET external_ref = CAST(id AS VARCHAR) "+
+							"WHERE external_ref IS NULL AND CAST(id AS VARCHAR) IN ($1,$2) AND deleted_at IS NULL",
+					)).
+					WithArgs("ref-1", "ref-2").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs("ref-1", "ref-2").
+					WillReturnRows(sqlmock.
+						NewRows(lockedSpotRowColumns).
+						AddRow(
+							"1", "Old spot 1", 1.23, 3.21, "Locality 1", "kz", nil,
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					)
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"INSERT INTO spots (name,latitude,longitude,locality,country_code,external_ref) " +
+							"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) " +
+							"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"inserted", "id"}).
+						AddRow(false, "1").
+						AddRow(true, "2"),
+					)
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "update", "1", updateChanges).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "create", "2", createChanges).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedStats: surf.SpotCreationStats{
+				Inserted: 1,
+				Updated:  1,
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			stats, err := store.CreateSpots(
+				surf.ContextWithAuditActor(context.Background(), "admin-1"), entries, test.mode)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedStats, stats)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_DeleteSpots_Audited(t *testing.T) {
+	const (
+		deleteQuery = "UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = ANY($1) AND deleted_at IS NULL " +
+			"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+			"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags"
+		changes = `[{"field":"name","before":"Spot 3","after":null},` +
+			`{"field":"latitude","before":2.34,"after":null},` +
+			`{"field":"longitude","before":4.32,"after":null},` +
+			`{"field":"locality","before":"","after":null},` +
+			`{"field":"country_code","before":"kz","after":null},` +
+			`{"field":"region","before":"","after":null},` +
+			`{"field":"slug","before":"","after":null}]`
+	)
+
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.
+			NewRows(lockedSpotRowColumns).
+			AddRow(
+				"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+				time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			).
+			AddRow(
+				"3", "Spot 3", 2.34, 4.32, "", "kz", nil,
+				time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			)
+	}
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedIDs   []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "roll back deletion when audit entry fails to be written",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(pq.Array([]string{"1", "2", "3"})).
+					WillReturnRows(newRows())
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "delete", "1", sqlmock.AnyArg()).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return deleted ids without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(pq.Array([]string{"1", "2", "3"})).
+					WillReturnRows(newRows())
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "delete", "1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "delete", "3", changes).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedIDs:   []string{"1", "3"},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			ids, err := store.DeleteSpots(
+				surf.ContextWithAuditActor(context.Background(), "admin-1"), []string{"1", "2", "3"})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_RestoreSpot_Audited(t *testing.T) {
+	const (
+		restoreQuery = "UPDATE spots SET deleted_at = $1, external_ref = CASE WHEN EXISTS (" +
+			"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL" +
+			") THEN NULL ELSE external_ref END, slug = CASE WHEN EXISTS (" +
+			"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL" +
+			") THEN NULL ELSE slug END " +
+			"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NOT NULL " +
+			"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+			"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags"
+		changes = `[{"field":"name","before":null,"after":"Spot 1"},` +
+			`{"field":"latitude","before":null,"after":2.34},` +
+			`{"field":"longitude","before":null,"after":4.32},` +
+			`{"field":"locality","before":null,"after":""},` +
+			`{"field":"country_code","before":null,"after":"kz"},` +
+			`{"field":"region","before":null,"after":""},` +
+			`{"field":"slug","before":null,"after":""}]`
+	)
+
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.
+			NewRows(lockedSpotRowColumns).
+			AddRow(
+				"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+				time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			)
+	}
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting or not deleted resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(restoreQuery)).
+					WithArgs(nil, "1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "roll back restoration when audit entry fails to be written",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(restoreQuery)).
+					WithArgs(nil, "1").
+					WillReturnRows(newRows())
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "restore", "1", changes).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(restoreQuery)).
+					WithArgs(nil, "1").
+					WillReturnRows(newRows())
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "restore", "1", changes).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			_, err = store.RestoreSpot(surf.ContextWithAuditActor(context.Background(), "admin-1"), "1")
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SetSpotTags_Audited(t *testing.T) {
+	const changes = `[{"field":"tags","before":["beginner","reef"],"after":["point","reef"]}]`
+
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.
+			NewRows(append(lockedSpotRowColumns, "tags")).
+			AddRow(
+				"1", "Spot 1", 2.34, 4.32, "", "kz", nil,
+				time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				"{beginner,reef}",
+			)
+	}
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "roll back tag replacement when audit entry fails to be written",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(newRows())
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.
+					ExpectExec(regexp.QuoteMeta("INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2),($3,$4)")).
+					WithArgs("1", "reef", "1", "point").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "update", "1", changes).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lockSpotQuery)).
+					WithArgs("1").
+					WillReturnRows(newRows())
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.
+					ExpectExec(regexp.QuoteMeta("INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2),($3,$4)")).
+					WithArgs("1", "reef", "1", "point").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.
+					ExpectExec(regexp.QuoteMeta(insertAuditEntryQuery)).
+					WithArgs("admin-1", "update", "1", changes).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			err = store.SetSpotTags(
+				surf.ContextWithAuditActor(context.Background(), "admin-1"), "1", []string{"reef", "point"})
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_AuditEntries(t *testing.T) {
+	const columnsSQL = "CAST(id AS VARCHAR) AS id, actor_id, action, CAST(spot_id AS VARCHAR) AS spot_id, changes, created_at"
+
+	tests := []struct {
+		name            string
+		mockFn          func(sqlmock.Sqlmock)
+		params          surf.AuditEntriesParams
+		expectedEntries []surf.AuditEntry
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT " + columnsSQL + " FROM audit_log ORDER BY created_at DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WillReturnError(errors.New("unexpected error"))
+			},
+			params:          surf.AuditEntriesParams{Limit: 10},
+			expectedEntries: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return error for malformed changes",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT " + columnsSQL + " FROM audit_log ORDER BY created_at DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "actor_id", "action", "spot_id", "changes", "created_at"}).
+						AddRow("11", "admin-1", "update", "1", []byte("{"), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			params:          surf.AuditEntriesParams{Limit: 10},
+			expectedEntries: nil,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return entries of spot without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT " + columnsSQL + " FROM audit_log WHERE CAST(spot_id AS VARCHAR) = $1 " +
+							"ORDER BY created_at DESC, id LIMIT 5 OFFSET 10",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "actor_id", "action", "spot_id", "changes", "created_at"}).
+						AddRow(
+							"12", "admin-1", "update", "1",
+							[]byte(`[{"field": "name", "before": "Spot 1", "after": "Updated spot 1"}]`),
+							time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+						).
+						AddRow(
+							"11", "admin-2", "update", "1",
+							[]byte(`[]`),
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.AuditEntriesParams{
+				Limit:  5,
+				Offset: 10,
+				SpotID: "1",
+			},
+			expectedEntries: []surf.AuditEntry{
+				{
+					ID:      "12",
+					ActorID: "admin-1",
+					Action:  surf.AuditActionUpdate,
+					SpotID:  "1",
+					Changes: []surf.AuditChange{
+						{Field: "name", Before: "Spot 1", After: "Updated spot 1"},
+					},
+					CreatedAt: time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:        "11",
+					ActorID:   "admin-2",
+					Action:    surf.AuditActionUpdate,
+					SpotID:    "1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return entries of actor within period without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT "+columnsSQL+" FROM audit_log "+
+							"WHERE actor_id = $1 AND created_at >= $2 AND created_at < $3 "+
+							"ORDER BY created_at DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WithArgs(
+						"admin-1",
+						time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+					).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "actor_id", "action", "spot_id", "changes", "created_at"}).
+						AddRow(
+							"11", "admin-1", "restore", "1",
+							[]byte(`[]`),
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.AuditEntriesParams{
+				Limit:   10,
+				ActorID: "admin-1",
+				From:    time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expectedEntries: []surf.AuditEntry{
+				{
+					ID:        "11",
+					ActorID:   "admin-1",
+					Action:    surf.AuditActionRestore,
+					SpotID:    "1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			entries, err := store.AuditEntries(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedEntries, entries)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_DeleteSpots(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		ids           []string
+		expectedIDs   []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = ANY($1) AND deleted_at IS NULL RETURNING id",
+					)).
+					WithArgs(pq.Array([]string{"1", "2"})).
+					WillReturnError(errors.New("unexpected error"))
+			},
+			ids:           []string{"1", "2"},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error during row iteration",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = ANY($1) AND deleted_at IS NULL RETURNING id",
+					)).
+					WithArgs(pq.Array([]string{"1", "2"})).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("1").
+						AddRow("2").
+						RowError(1, errors.New("unexpected error")),
+					).
+					RowsWillBeClosed()
+			},
+			ids:           []string{"1", "2"},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return deleted ids without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = ANY($1) AND deleted_at IS NULL RETURNING id",
+					)).
+					WithArgs(pq.Array([]string{"1", "2", "3"})).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id"}).
+						AddRow("1").
+						AddRow("3"),
+					).
+					RowsWillBeClosed()
+			},
+			ids:           []string{"1", "2", "3"},
+			expectedIDs:   []string{"1", "3"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return no ids when nothing is deleted",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = ANY($1) AND deleted_at IS NULL RETURNING id",
+					)).
+					WithArgs(pq.Array([]string{"1"})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"})).
+					RowsWillBeClosed()
+			},
+			ids:           []string{"1"},
+			expectedIDs:   nil,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			ids, err := store.DeleteSpots(context.Background(), test.ids)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_RestoreSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		id            string
+		expectedSpot  surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = $1, external_ref = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE external_ref END, slug = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE slug END "+
+							"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NOT NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs(nil, "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			id:            "1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting or not deleted resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = $1, external_ref = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE external_ref END, slug = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE slug END "+
+							"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NOT NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs(nil, "1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			id:            "1",
+			expectedSpot:  surf.Spot{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return spot without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET deleted_at = $1, external_ref = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE external_ref END, slug = CASE WHEN EXISTS ("+
+							"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL"+
+							") THEN NULL ELSE slug END "+
+							"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NOT NULL "+
+							"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, "+
+							"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags",
+					)).
+					WithArgs(nil, "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
+						}).
+						AddRow("1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					)
+			},
+			id: "1",
+			expectedSpot: surf.Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				Location: geo.Location{
+					Locality:    "Locality 1",
+					CountryCode: "Country code 1",
+					Coordinates: geo.Coordinates{
+						Latitude:  1.23,
+						Longitude: 3.21,
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			spot, err := store.RestoreSpot(context.Background(), test.id)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_Metrics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer db.Close()
+
+	mock.
+		ExpectExec(regexp.QuoteMeta(
+			"UPDATE spots SET deleted_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+		)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reg := metrics.NewRegistry()
+	store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), WithMetrics(reg))
+
+	err = store.DeleteSpot(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	durations := reg.Histogram(metricQueryDurationSeconds, "", nil, "query")
+	assert.Equal(t, uint64(1), durations.Count("delete_spot"))
+}
+
+func TestSpotStore_SetSpotTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		id            string
+		tags          []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting resource",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			id:            "1",
+			tags:          []string{"reef"},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during tag insertion",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta("INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2)")).
+					WithArgs("1", "reef").
+					WillReturnError(errors.New("something went wrong"))
+				m.ExpectRollback()
+			},
+			id:            "1",
+			tags:          []string{"reef"},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return invalid reference error for foreign key violation",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta("INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2)")).
+					WithArgs("1", "reef").
+					WillReturnError(&pq.Error{Code: "23503"})
+				m.ExpectRollback()
+			},
+			id:            "1",
+			tags:          []string{"reef"},
+			expectedErrFn: testutil.IsError(surf.ErrInvalidReference),
+		},
+		{
+			name: "remove all tags without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			id:            "1",
+			tags:          nil,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "replace tags without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"UPDATE spots SET updated_at = NOW() WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+							"RETURNING id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_tags WHERE spot_id = $1")).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta("INSERT INTO spot_tags (spot_id,tag) VALUES ($1,$2),($3,$4)")).
+					WithArgs("1", "reef", "1", "point").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			id:            "1",
+			tags:          []string{"reef", "point"},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.SetSpotTags(context.Background(), test.id, test.tags)
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotPhotos(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockFn         func(sqlmock.Sqlmock)
+		spotID         string
+		expectedPhotos []surf.SpotPhoto
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for unexisting spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id FROM spots WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			spotID:         "1",
+			expectedPhotos: nil,
+			expectedErrFn:  testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during photos query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id FROM spots WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, spot_id, content_type, size, created_at FROM spot_photos " +
+							"WHERE spot_id = $1 ORDER BY created_at, id",
+					)).
+					WithArgs("1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			spotID:         "1",
+			expectedPhotos: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return photos without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id FROM spots WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT id, spot_id, content_type, size, created_at FROM spot_photos " +
+							"WHERE spot_id = $1 ORDER BY created_at, id",
+					)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "spot_id", "content_type", "size", "created_at"}).
+						AddRow("11", "1", "image/jpeg", 100, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)).
+						AddRow("12", "1", "image/png", 200, time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			spotID: "1",
+			expectedPhotos: []surf.SpotPhoto{
+				{
+					ID:          "11",
+					SpotID:      "1",
+					ContentType: "image/jpeg",
+					Size:        100,
+					CreatedAt:   time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:          "12",
+					SpotID:      "1",
+					ContentType: "image/png",
+					Size:        200,
+					CreatedAt:   time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			photos, err := store.SpotPhotos(context.Background(), test.spotID)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPhotos, photos)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpotPhoto(t *testing.T) {
+	const query = "INSERT INTO spot_photos (spot_id,content_type,size) " +
+		"SELECT id, CAST($1 AS TEXT), CAST($2 AS BIGINT) FROM spots WHERE CAST(id AS VARCHAR) = $3 AND deleted_at IS NULL " +
+		"RETURNING id, spot_id, content_type, size, created_at"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		entry         surf.SpotPhotoEntry
+		expectedPhoto surf.SpotPhoto
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("image/jpeg", int64(100), "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			entry: surf.SpotPhotoEntry{
+				SpotID:      "1",
+				ContentType: "image/jpeg",
+				Size:        100,
+			},
+			expectedPhoto: surf.SpotPhoto{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("image/jpeg", int64(100), "1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			entry: surf.SpotPhotoEntry{
+				SpotID:      "1",
+				ContentType: "image/jpeg",
+				Size:        100,
+			},
+			expectedPhoto: surf.SpotPhoto{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return photo without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("image/jpeg", int64(100), "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "spot_id", "content_type", "size", "created_at"}).
+						AddRow("11", "1", "image/jpeg", 100, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			entry: surf.SpotPhotoEntry{
+				SpotID:      "1",
+				ContentType: "image/jpeg",
+				Size:        100,
+			},
+			expectedPhoto: surf.SpotPhoto{
+				ID:          "11",
+				SpotID:      "1",
+				ContentType: "image/jpeg",
+				Size:        100,
+				CreatedAt:   time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			photo, err := store.CreateSpotPhoto(context.Background(), test.entry)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPhoto, photo)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_DeleteSpotPhoto(t *testing.T) {
+	const query = "DELETE FROM spot_photos WHERE CAST(id AS VARCHAR) = $1 " +
+		"AND spot_id IN (SELECT id FROM spots WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NULL) " +
+		"RETURNING id, spot_id, content_type, size, created_at"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedPhoto surf.SpotPhoto
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedPhoto: surf.SpotPhoto{},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting photo",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11", "1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedPhoto: surf.SpotPhoto{},
+			expectedErrFn: testutil.IsError(surf.ErrSpotPhotoNotFound),
+		},
+		{
+			name: "return deleted photo without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11", "1").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "spot_id", "content_type", "size", "created_at"}).
+						AddRow("11", "1", "image/jpeg", 100, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					).
+					RowsWillBeClosed()
+			},
+			expectedPhoto: surf.SpotPhoto{
+				ID:          "11",
+				SpotID:      "1",
+				ContentType: "image/jpeg",
+				Size:        100,
+				CreatedAt:   time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			photo, err := store.DeleteSpotPhoto(context.Background(), "1", "11")
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPhoto, photo)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_RateSpot(t *testing.T) {
+	const query = "INSERT INTO spot_ratings (spot_id,score,rater_key,rated_on) " +
+		"SELECT id, CAST($1 AS SMALLINT), CAST($2 AS TEXT), CAST($3 AS DATE) FROM spots " +
+		"WHERE CAST(id AS VARCHAR) = $4 AND deleted_at IS NULL " +
+		"ON CONFLICT (spot_id, rater_key, rated_on) DO UPDATE SET score = EXCLUDED.score"
+
+	entry := surf.SpotRatingEntry{
+		SpotID:   "1",
+		Score:    4,
+		RaterKey: "rater",
+		RatedOn:  time.Date(2021, 2, 1, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60)),
+	}
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(4, "rater", "2021-02-02", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(4, "rater", "2021-02-02", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(4, "rater", "2021-02-02", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.RateSpot(context.Background(), entry)
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_FavoriteSpots(t *testing.T) {
+	const query = "SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+		"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+		"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+		"FROM spots " +
+		"JOIN favorites ON favorites.spot_id = spots.id " +
+		"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+		"WHERE CAST(favorites.user_id AS VARCHAR) = $1 AND deleted_at IS NULL " +
+		"ORDER BY favorites.favorited_at DESC, spots.id LIMIT 10 OFFSET 5"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedSpots []surf.Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("user").
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error during row scanning",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("user").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "unknown"}).
+						AddRow("1", "unknown"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("user").
+					WillReturnRows(sqlmock.
+						NewRows([]string{
+							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at",
+							"average_rating", "ratings_count",
+						}).
+						AddRow(
+							"2", "Spot 2", 2.34, 4.32, "Locality 2", "Country code 2",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+							0, 0,
+						).
+						AddRow(
+							"1", "Spot 1", 1.23, 3.21, "Locality 1", "Country code 1",
+							time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+							4.5, 2,
+						),
+					).
+					RowsWillBeClosed()
+			},
+			expectedSpots: []surf.Spot{
+				{
+					ID:        "2",
+					Name:      "Spot 2",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+					Location: geo.Location{
+						Locality:    "Locality 2",
+						CountryCode: "Country code 2",
+						Coordinates: geo.Coordinates{
+							Latitude:  2.34,
+							Longitude: 4.32,
+						},
+					},
+				},
+				{
+					ID:        "1",
+					Name:      "Spot 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+					Ratings: surf.SpotRatings{
+						Average: 4.5,
+						Count:   2,
+					},
+					Location: geo.Location{
+						Locality:    "Locality 1",
+						CountryCode: "Country code 1",
+						Coordinates: geo.Coordinates{
+							Latitude:  1.23,
+							Longitude: 3.21,
+						},
+					},
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			spots, err := store.FavoriteSpots(context.Background(), surf.FavoriteSpotsParams{
+				UserID: "user",
+				Limit:  10,
+				Offset: 5,
+			})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpots, spots)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_FavoritedSpotIDs(t *testing.T) {
+	const query = "SELECT CAST(spot_id AS VARCHAR) FROM favorites " +
+		"WHERE CAST(user_id AS VARCHAR) = $1 AND CAST(spot_id AS VARCHAR) IN ($2,$3)"
+
+	tests := []struct {
+		name          string
+		spotIDs       []string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedIDs   map[string]bool
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return no ids without querying for no spots",
+			spotIDs:       nil,
+			mockFn:        func(m sqlmock.Sqlmock) {},
+			expectedIDs:   map[string]bool{},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:    "return error during query execution",
+			spotIDs: []string{"1", "2"},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("user", "1", "2").
+					WillReturnError(errors.New("something went wrong"))
+			},
+			expectedIDs:   nil,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:    "return ids of favorited spots without error",
+			spotIDs: []string{"1", "2"},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("user", "1", "2").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"spot_id"}).
+						AddRow("2"),
+					).
+					RowsWillBeClosed()
+			},
+			expectedIDs:   map[string]bool{"2": true},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+
+			ids, err := store.FavoritedSpotIDs(context.Background(), "user", test.spotIDs)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_FavoriteSpot(t *testing.T) {
+	const query = "INSERT INTO favorites (user_id,spot_id) " +
+		"SELECT CAST($1 AS UUID), id FROM spots " +
+		"WHERE CAST(id AS VARCHAR) = $2 AND deleted_at IS NULL " +
+		"ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return error for unexisting spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.FavoriteSpot(context.Background(), "user", "1")
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_UnfavoriteSpot(t *testing.T) {
+	const query = "DELETE FROM favorites WHERE CAST(user_id AS VARCHAR) = $1 AND CAST(spot_id AS VARCHAR) = $2"
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return no error for spot that is not favorited",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return no error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs("user", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			err = store.UnfavoriteSpot(context.Background(), "user", "1")
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_ApplyFavoriteBatch(t *testing.T) {
+	const (
+		favoriteQuery = "INSERT INTO favorites (user_id,spot_id) " +
+			"SELECT CAST($1 AS UUID), id FROM spots " +
+			"WHERE CAST(id AS VARCHAR) IN ($2,$3) AND deleted_at IS NULL " +
+			"ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at " +
+			"RETURNING CAST(spot_id AS VARCHAR)"
+		unfavoriteQuery = "DELETE FROM favorites WHERE CAST(user_id AS VARCHAR) = $1 AND CAST(spot_id AS VARCHAR) IN ($2)"
+	)
+
+	tests := []struct {
+		name              string
+		batch             surf.FavoriteBatch
+		mockFn            func(sqlmock.Sqlmock)
+		expectedFavorited []string
+		expectedErrFn     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during favorite query execution",
+			batch: surf.FavoriteBatch{
+				UserID:     "user",
+				Favorite:   []string{"1", "2"},
+				Unfavorite: []string{"3"},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(favoriteQuery)).
+					WithArgs("user", "1", "2").
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedFavorited: nil,
+			expectedErrFn:     assert.Error,
+		},
+		{
+			name: "roll back favorites when unfavorite query fails",
+			batch: surf.FavoriteBatch{
+				UserID:     "user",
+				Favorite:   []string{"1", "2"},
+				Unfavorite: []string{"3"},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(favoriteQuery)).
+					WithArgs("user", "1", "2").
+					WillReturnRows(sqlmock.NewRows([]string{"spot_id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta(unfavoriteQuery)).
+					WithArgs("user", "3").
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedFavorited: nil,
+			expectedErrFn:     assert.Error,
+		},
+		{
+			name: "return favorited spot ids without error",
+			batch: surf.FavoriteBatch{
+				UserID:     "user",
+				Favorite:   []string{"1", "2"},
+				Unfavorite: []string{"3"},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(favoriteQuery)).
+					WithArgs("user", "1", "2").
+					WillReturnRows(sqlmock.NewRows([]string{"spot_id"}).AddRow("1"))
+				m.
+					ExpectExec(regexp.QuoteMeta(unfavoriteQuery)).
+					WithArgs("user", "3").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expectedFavorited: []string{"1"},
+			expectedErrFn:     assert.NoError,
+		},
+		{
+			name: "skip favorite query when there is nothing to favorite",
+			batch: surf.FavoriteBatch{
+				UserID:     "user",
+				Unfavorite: []string{"3"},
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectExec(regexp.QuoteMeta(unfavoriteQuery)).
+					WithArgs("user", "3").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectCommit()
+			},
+			expectedFavorited: []string{},
+			expectedErrFn:     assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			favorited, err := store.ApplyFavoriteBatch(context.Background(), test.batch)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedFavorited, favorited)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+const spotSuggestionColumnsSQL = "CAST(id AS VARCHAR) AS id, CAST(spot_id AS VARCHAR) AS spot_id, status, name, " +
+	"latitude, longitude, locality, country_code, region, note, created_at, resolved_at"
+
+var spotSuggestionRowColumns = []string{
+	"id", "spot_id", "status", "name", "latitude", "longitude", "locality", "country_code", "region",
+	"note", "created_at", "resolved_at",
+}
+
+func TestSpotStore_SpotSuggestion(t *testing.T) {
+	const query = "SELECT " + spotSuggestionColumnsSQL + " FROM spot_suggestions WHERE CAST(id AS VARCHAR) = $1"
+
+	tests := []struct {
+		name               string
+		mockFn             func(sqlmock.Sqlmock)
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return error for unexisting suggestion",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionNotFound),
+		},
+		{
+			name: "return suggestion without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("11").
+					WillReturnRows(sqlmock.
+						NewRows(spotSuggestionRowColumns).
+						AddRow(
+							"11", "1", "applied", "Spot 1", 1.23, nil, nil, nil, "",
+							"Note 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+						),
+					)
+			},
+			expectedSuggestion: surf.SpotSuggestion{
+				ID:     "11",
+				Status: surf.SpotSuggestionStatusApplied,
+				Change: surf.SpotUpdateEntry{
+					ID:       "1",
+					Name:     pconv.String("Spot 1"),
+					Latitude: pconv.Float64(1.23),
+					Region:   pconv.String(""),
+				},
+				Note:      "Note 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				ResolvedAt: func() *time.Time {
+					t := time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC)
+					return &t
+				}(),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			suggestion, err := store.SpotSuggestion(context.Background(), "11")
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_SpotSuggestions(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockFn              func(sqlmock.Sqlmock)
+		params              surf.SpotSuggestionsParams
+		expectedSuggestions []surf.SpotSuggestion
+		expectedErrFn       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT " + spotSuggestionColumnsSQL + " FROM spot_suggestions " +
+							"ORDER BY created_at DESC, id LIMIT 10 OFFSET 0",
+					)).
+					WillReturnError(errors.New("unexpected error"))
+			},
+			params:              surf.SpotSuggestionsParams{Limit: 10},
+			expectedSuggestions: nil,
+			expectedErrFn:       assert.Error,
+		},
+		{
+			name: "return suggestions of status without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT " + spotSuggestionColumnsSQL + " FROM spot_suggestions WHERE status = $1 " +
+							"ORDER BY created_at DESC, id LIMIT 5 OFFSET 10",
+					)).
+					WithArgs("pending").
+					WillReturnRows(sqlmock.
+						NewRows(spotSuggestionRowColumns).
+						AddRow(
+							"12", "2", "pending", nil, nil, nil, "Locality 2", nil, nil,
+							"", time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC), nil,
+						).
+						AddRow(
+							"11", "1", "pending", "Spot 1", nil, nil, nil, nil, nil,
+							"Note 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), nil,
+						),
+					).
+					RowsWillBeClosed()
+			},
+			params: surf.SpotSuggestionsParams{
+				Limit:  5,
+				Offset: 10,
+				Status: surf.SpotSuggestionStatusPending,
+			},
+			expectedSuggestions: []surf.SpotSuggestion{
+				{
+					ID:        "12",
+					Status:    surf.SpotSuggestionStatusPending,
+					Change:    surf.SpotUpdateEntry{ID: "2", Locality: pconv.String("Locality 2")},
+					CreatedAt: time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:        "11",
+					Status:    surf.SpotSuggestionStatusPending,
+					Change:    surf.SpotUpdateEntry{ID: "1", Name: pconv.String("Spot 1")},
+					Note:      "Note 1",
+					CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			suggestions, err := store.SpotSuggestions(context.Background(), test.params)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestions, suggestions)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpotSuggestion(t *testing.T) {
+	const query = "INSERT INTO spot_suggestions (spot_id,name,latitude,longitude,locality,country_code,region,note) " +
+		"SELECT id, CAST($1 AS TEXT), CAST($2 AS NUMERIC), CAST($3 AS NUMERIC), CAST($4 AS TEXT), CAST($5 AS TEXT), " +
+		"CAST($6 AS TEXT), CAST($7 AS TEXT) FROM spots WHERE CAST(id AS VARCHAR) = $8 AND deleted_at IS NULL " +
+		"RETURNING " + spotSuggestionColumnsSQL
+
+	entry := surf.SpotSuggestionEntry{
+		Change: surf.SpotUpdateEntry{
+			ID:        "1",
+			Latitude:  pconv.Float64(1.23),
+			Longitude: pconv.Float64(3.21),
+		},
+		Note: "Note 1",
+	}
+
+	tests := []struct {
+		name               string
+		mockFn             func(sqlmock.Sqlmock)
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(nil, 1.23, 3.21, nil, nil, nil, "Note 1", "1").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return error for unexisting spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(nil, 1.23, 3.21, nil, nil, nil, "Note 1", "1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return suggestion without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(nil, 1.23, 3.21, nil, nil, nil, "Note 1", "1").
+					WillReturnRows(sqlmock.
+						NewRows(spotSuggestionRowColumns).
+						AddRow(
+							"11", "1", "pending", nil, 1.23, 3.21, nil, nil, nil,
+							"Note 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), nil,
+						),
+					)
+			},
+			expectedSuggestion: surf.SpotSuggestion{
+				ID:        "11",
+				Status:    surf.SpotSuggestionStatusPending,
+				Change:    entry.Change,
+				Note:      "Note 1",
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			suggestion, err := store.CreateSpotSuggestion(context.Background(), entry)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_ResolveSpotSuggestion(t *testing.T) {
+	const (
+		updateQuery = "UPDATE spot_suggestions SET status = $1, resolved_at = NOW() " +
+			"WHERE CAST(id AS VARCHAR) = $2 AND status = $3 RETURNING " + spotSuggestionColumnsSQL
+		selectQuery = "SELECT 1 FROM spot_suggestions WHERE CAST(id AS VARCHAR) = $1"
+	)
+
+	tests := []struct {
+		name               string
+		mockFn             func(sqlmock.Sqlmock)
+		expectedSuggestion surf.SpotSuggestion
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("rejected", "11", "pending").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return error for unexisting suggestion",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("rejected", "11", "pending").
+					WillReturnError(sql.ErrNoRows)
+				m.
+					ExpectQuery(regexp.QuoteMeta(selectQuery)).
+					WithArgs("11").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionNotFound),
+		},
+		{
+			name: "return error for resolved suggestion",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("rejected", "11", "pending").
+					WillReturnError(sql.ErrNoRows)
+				m.
+					ExpectQuery(regexp.QuoteMeta(selectQuery)).
+					WithArgs("11").
+					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			},
+			expectedSuggestion: surf.SpotSuggestion{},
+			expectedErrFn:      testutil.IsError(surf.ErrSpotSuggestionResolved),
+		},
+		{
+			name: "return suggestion without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("rejected", "11", "pending").
+					WillReturnRows(sqlmock.
+						NewRows(spotSuggestionRowColumns).
+						AddRow(
+							"11", "1", "rejected", "Spot 1", nil, nil, nil, nil, nil,
+							"", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC),
+						),
+					)
+			},
+			expectedSuggestion: surf.SpotSuggestion{
+				ID:        "11",
+				Status:    surf.SpotSuggestionStatusRejected,
+				Change:    surf.SpotUpdateEntry{ID: "1", Name: pconv.String("Spot 1")},
+				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				ResolvedAt: func() *time.Time {
+					t := time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC)
+					return &t
+				}(),
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			suggestion, err := store.ResolveSpotSuggestion(context.Background(), "11", surf.SpotSuggestionStatusRejected)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSuggestion, suggestion)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_CreateSpotIdempotently(t *testing.T) {
+	const (
+		lookupQuery = "SELECT CAST(spot_id AS VARCHAR) FROM idempotency_keys WHERE key = $1 AND created_at >= $2"
+		spotQuery   = "SELECT id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+			"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags, " +
+			"COALESCE(sr.average_rating, 0) AS average_rating, COALESCE(sr.ratings_count, 0) AS ratings_count " +
+			"FROM spots " +
+			"LEFT JOIN (SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id) AS sr ON sr.spot_id = spots.id " +
+			"WHERE CAST(id AS VARCHAR) = $1 AND deleted_at IS NULL"
+		insertQuery = "INSERT INTO spots (name,latitude,longitude,locality,country_code) VALUES ($1,$2,$3,$4,$5) " +
+			"RETURNING id, name, latitude, longitude, locality, country_code, region, slug, created_at, updated_at, " +
+			"ARRAY(SELECT tag FROM spot_tags WHERE spot_tags.spot_id = spots.id ORDER BY tag) AS tags"
+		claimQuery = "INSERT INTO idempotency_keys (key,spot_id,created_at) VALUES ($1,$2,$3) " +
+			"ON CONFLICT (key) DO UPDATE SET spot_id = EXCLUDED.spot_id, created_at = EXCLUDED.created_at " +
+			"WHERE idempotency_keys.created_at < $4 RETURNING key"
+	)
+
+	var (
+		now   = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		since = now.Add(-time.Hour)
+		entry = surf.SpotCreationEntry{
+			Name: "Spot 1",
+			Location: geo.Location{
+				Locality:    "Locality 1",
+				CountryCode: "kz",
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+			},
+		}
+		spotRow = func(id string) *sqlmock.Rows {
+			return sqlmock.
+				NewRows([]string{"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at"}).
+				AddRow(id, "Spot 1", 1.23, 3.21, "Locality 1", "kz", now, now)
+		}
+		spot = func(id string) surf.Spot {
+			return surf.Spot{
+				ID:        id,
+				Name:      "Spot 1",
+				Location:  entry.Location,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+		}
+	)
+
+	tests := []struct {
+		name            string
+		mockFn          func(sqlmock.Sqlmock)
+		expectedSpot    surf.Spot
+		expectedCreated bool
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during key lookup",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedSpot:    surf.Spot{},
+			expectedCreated: false,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "return previously created spot for fresh key",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnRows(sqlmock.NewRows([]string{"spot_id"}).AddRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(spotQuery)).
+					WithArgs("1").
+					WillReturnRows(spotRow("1"))
+				m.ExpectCommit()
+			},
+			expectedSpot:    spot("1"),
+			expectedCreated: false,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return error for fresh key of deleted spot",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnRows(sqlmock.NewRows([]string{"spot_id"}).AddRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(spotQuery)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedSpot:    surf.Spot{},
+			expectedCreated: false,
+			expectedErrFn:   testutil.IsError(surf.ErrSpotNotFound),
+		},
+		{
+			name: "return error during key claim",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnError(sql.ErrNoRows)
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "kz").
+					WillReturnRows(spotRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(claimQuery)).
+					WithArgs("key", "1", now, since).
+					WillReturnError(errors.New("unexpected error"))
+				m.ExpectRollback()
+			},
+			expectedSpot:    surf.Spot{},
+			expectedCreated: false,
+			expectedErrFn:   assert.Error,
+		},
+		{
+			name: "create spot for new or expired key",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnError(sql.ErrNoRows)
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "kz").
+					WillReturnRows(spotRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(claimQuery)).
+					WithArgs("key", "1", now, since).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key"))
+				m.ExpectCommit()
+			},
+			expectedSpot:    spot("1"),
+			expectedCreated: true,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return concurrently created spot for key claimed in the meantime",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnError(sql.ErrNoRows)
+				m.
+					ExpectQuery(regexp.QuoteMeta(insertQuery)).
+					WithArgs("Spot 1", 1.23, 3.21, "Locality 1", "kz").
+					WillReturnRows(spotRow("1"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(claimQuery)).
+					WithArgs("key", "1", now, since).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+
+				m.ExpectBegin()
+				m.
+					ExpectQuery(regexp.QuoteMeta(lookupQuery)).
+					WithArgs("key", since).
+					WillReturnRows(sqlmock.NewRows([]string{"spot_id"}).AddRow("2"))
+				m.
+					ExpectQuery(regexp.QuoteMeta(spotQuery)).
+					WithArgs("2").
+					WillReturnRows(spotRow("2"))
+				m.ExpectCommit()
+			},
+			expectedSpot:    spot("2"),
+			expectedCreated: false,
+			expectedErrFn:   assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			spot, created, err := store.CreateSpotIdempotently(context.Background(), "key", entry, since, now)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+			assert.Equal(t, test.expectedCreated, created)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_ReferencePoints(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockFn         func(sqlmock.Sqlmock)
+		expectedPoints []surf.ReferencePoint
+		expectedErrFn  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error during query execution",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT name, latitude, longitude FROM reference_points WHERE reference_set = $1 ORDER BY name",
+					)).
+					WithArgs("airports").
+					WillReturnError(errors.New("unexpected error"))
+			},
+			expectedPoints: nil,
+			expectedErrFn:  assert.Error,
+		},
+		{
+			name: "return points without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT name, latitude, longitude FROM reference_points WHERE reference_set = $1 ORDER BY name",
+					)).
+					WithArgs("airports").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"name", "latitude", "longitude"}).
+						AddRow("Aktau", 43.86, 51.09).
+						AddRow("Perth", -31.95, 115.86),
+					).
+					RowsWillBeClosed()
+			},
+			expectedPoints: []surf.ReferencePoint{
+				{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+				{Name: "Perth", Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86}},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
+			points, err := store.ReferencePoints(context.Background(), "airports")
 			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedPoints, points)
 
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestSpotStore_UpdateSpot(t *testing.T) {
+func TestSpotStore_ReplaceReferencePoints(t *testing.T) {
 	tests := []struct {
 		name          string
 		mockFn        func(sqlmock.Sqlmock)
-		params        surf.SpotUpdateEntry
-		expectedSpot  surf.Spot
+		points        []surf.ReferencePoint
 		expectedErrFn assert.ErrorAssertionFunc
 	}{
 		{
-			name: "return error during query execution",
+			name: "return error during point insertion",
 			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
 				m.
-					ExpectQuery(regexp.QuoteMeta(
-						"UPDATE spots "+
-							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5 "+
-							"WHERE CAST(id AS VARCHAR) = $6 "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
+					ExpectExec(regexp.QuoteMeta("DELETE FROM reference_points WHERE reference_set = $1")).
+					WithArgs("airports").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO reference_points (reference_set,name,latitude,longitude) VALUES ($1,$2,$3,$4)",
 					)).
-					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
-					WillReturnError(errors.New("unexpected error"))
+					WithArgs("airports", "Aktau", 43.86, 51.09).
+					WillReturnError(errors.New("something went wrong"))
+				m.ExpectRollback()
 			},
-			params: surf.SpotUpdateEntry{
-				ID:          "1",
-				Name:        pconv.String("Updated spot 1"),
-				Locality:    pconv.String("Locality 1"),
-				CountryCode: pconv.String("Country code 1"),
-				Latitude:    pconv.Float64(2.34),
-				Longitude:   pconv.Float64(4.32),
+			points: []surf.ReferencePoint{
+				{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
 			},
-			expectedSpot:  surf.Spot{},
 			expectedErrFn: assert.Error,
 		},
 		{
-			name: "return error for unexisting resource",
+			name: "remove all points without error",
 			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
 				m.
-					ExpectQuery(regexp.QuoteMeta(
-						"UPDATE spots "+
-							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5 "+
-							"WHERE CAST(id AS VARCHAR) = $6 "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
-					)).
-					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
-					WillReturnError(sql.ErrNoRows)
-			},
-			params: surf.SpotUpdateEntry{
-				ID:          "1",
-				Name:        pconv.String("Updated spot 1"),
-				Locality:    pconv.String("Locality 1"),
-				CountryCode: pconv.String("Country code 1"),
-				Latitude:    pconv.Float64(2.34),
-				Longitude:   pconv.Float64(4.32),
-			},
-			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
-		},
-		{
-			name:   "return error when nothing to update",
-			mockFn: func(m sqlmock.Sqlmock) {},
-			params: surf.SpotUpdateEntry{
-				ID: "1",
+					ExpectExec(regexp.QuoteMeta("DELETE FROM reference_points WHERE reference_set = $1")).
+					WithArgs("airports").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
 			},
-			expectedSpot:  surf.Spot{},
-			expectedErrFn: testutil.IsError(surf.ErrEmptySpotUpdateEntry),
+			points:        nil,
+			expectedErrFn: assert.NoError,
 		},
 		{
-			name: "return spot without error for full update",
+			name: "replace points in batches without error",
 			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
 				m.
-					ExpectQuery(regexp.QuoteMeta(
-						"UPDATE spots "+
-							"SET country_code = $1, latitude = $2, locality = $3, longitude = $4, name = $5 "+
-							"WHERE CAST(id AS VARCHAR) = $6 "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
+					ExpectExec(regexp.QuoteMeta("DELETE FROM reference_points WHERE reference_set = $1")).
+					WithArgs("airports").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO reference_points (reference_set,name,latitude,longitude) "+
+							"VALUES ($1,$2,$3,$4),($5,$6,$7,$8)",
 					)).
-					WithArgs("Country code 1", 2.34, "Locality 1", 4.32, "Updated spot 1", "1").
-					WillReturnRows(sqlmock.
-						NewRows([]string{
-							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
-						}).
-						AddRow("1", "Updated spot 1", 2.34, 4.32, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
-					).
-					RowsWillBeClosed()
-			},
-			params: surf.SpotUpdateEntry{
-				ID:          "1",
-				Name:        pconv.String("Updated spot 1"),
-				Locality:    pconv.String("Locality 1"),
-				CountryCode: pconv.String("Country code 1"),
-				Latitude:    pconv.Float64(2.34),
-				Longitude:   pconv.Float64(4.32),
+					WithArgs("airports", "Aktau", 43.86, 51.09, "airports", "Denpasar", -8.75, 115.17).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO reference_points (reference_set,name,latitude,longitude) VALUES ($1,$2,$3,$4)",
+					)).
+					WithArgs("airports", "Perth", -31.95, 115.86).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
 			},
-			expectedSpot: surf.Spot{
-				ID:        "1",
-				Name:      "Updated spot 1",
-				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
-				Location: geo.Location{
-					Locality:    "Locality 1",
-					CountryCode: "Country code 1",
-					Coordinates: geo.Coordinates{
-						Latitude:  2.34,
-						Longitude: 4.32,
-					},
-				},
+			points: []surf.ReferencePoint{
+				{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+				{Name: "Denpasar", Coordinates: geo.Coordinates{Latitude: -8.75, Longitude: 115.17}},
+				{Name: "Perth", Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86}},
 			},
 			expectedErrFn: assert.NoError,
 		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock), WithBatchSize(2))
+			err = store.ReplaceReferencePoints(context.Background(), "airports", test.points)
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSpotStore_ReplaceSpotReferenceDistances(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		distances     []surf.SpotReferenceDistance
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
 		{
-			name: "return spot without error for partial update",
+			name: "return error during removal",
 			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
 				m.
-					ExpectQuery(regexp.QuoteMeta(
-						"UPDATE spots "+
-							"SET latitude = $1, name = $2 "+
-							"WHERE CAST(id AS VARCHAR) = $3 "+
-							"RETURNING id, name, latitude, longitude, locality, country_code, created_at",
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_reference_distances WHERE reference_set = $1")).
+					WithArgs("airports").
+					WillReturnError(errors.New("something went wrong"))
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "replace distances without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.
+					ExpectExec(regexp.QuoteMeta("DELETE FROM spot_reference_distances WHERE reference_set = $1")).
+					WithArgs("airports").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				m.
+					ExpectExec(regexp.QuoteMeta(
+						"INSERT INTO spot_reference_distances (spot_id,reference_set,reference_name,distance_km) "+
+							"SELECT spots.id, $1::text, d.reference_name, d.distance_km FROM spots "+
+							"JOIN UNNEST($2::text[], $3::text[], $4::float8[]) "+
+							"AS d (spot_id, reference_name, distance_km) "+
+							"ON CAST(spots.id AS VARCHAR) = d.spot_id",
 					)).
-					WithArgs(2.34, "Updated spot 1", "1").
-					WillReturnRows(sqlmock.
-						NewRows([]string{
-							"id", "name", "latitude", "longitude", "locality", "country_code", "created_at",
-						}).
-						AddRow("1", "Updated spot 1", 2.34, 4.32, "Locality 1", "Country code 1", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					WithArgs(
+						"airports",
+						pq.Array([]string{"1", "2"}),
+						pq.Array([]string{"Aktau", "Perth"}),
+						pq.Array([]float64{24.2, 13.9}),
 					).
-					RowsWillBeClosed()
-			},
-			params: surf.SpotUpdateEntry{
-				ID:       "1",
-				Name:     pconv.String("Updated spot 1"),
-				Latitude: pconv.Float64(2.34),
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
 			},
-			expectedSpot: surf.Spot{
-				ID:        "1",
-				Name:      "Updated spot 1",
-				CreatedAt: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
-				Location: geo.Location{
-					Locality:    "Locality 1",
-					CountryCode: "Country code 1",
-					Coordinates: geo.Coordinates{
-						Latitude:  2.34,
-						Longitude: 4.32,
-					},
+			distances: []surf.SpotReferenceDistance{
+				{
+					SpotID:            "1",
+					ReferenceDistance: surf.ReferenceDistance{PointName: "Aktau", DistanceKm: 24.2},
+				},
+				{
+					SpotID:            "2",
+					ReferenceDistance: surf.ReferenceDistance{PointName: "Perth", DistanceKm: 13.9},
 				},
 			},
 			expectedErrFn: assert.NoError,
@@ -1235,75 +6886,82 @@ func TestSpotStore_UpdateSpot(t *testing.T) {
 			test.mockFn(mock)
 
 			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
-			spot, err := store.UpdateSpot(test.params)
+			err = store.ReplaceSpotReferenceDistances(context.Background(), "airports", test.distances)
 			test.expectedErrFn(t, err)
-			assert.Equal(t, test.expectedSpot, spot)
 
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestSpotStore_DeleteSpot(t *testing.T) {
+func TestSpotStore_Localities(t *testing.T) {
 	tests := []struct {
-		name          string
-		mockFn        func(sqlmock.Sqlmock)
-		id            string
-		expectedErrFn assert.ErrorAssertionFunc
+		name               string
+		params             surf.LocalitiesParams
+		mockFn             func(sqlmock.Sqlmock)
+		expectedLocalities []string
+		expectedErrFn      assert.ErrorAssertionFunc
 	}{
 		{
 			name: "return error during query execution",
-			mockFn: func(m sqlmock.Sqlmock) {
-				m.
-					ExpectExec(regexp.QuoteMeta(
-						"DELETE FROM spots WHERE CAST(id AS VARCHAR) = $1",
-					)).
-					WithArgs("1").
-					WillReturnError(errors.New("unexpected error"))
+			params: surf.LocalitiesParams{
+				Prefix: "ka",
+				Limit:  10,
 			},
-			id:            "1",
-			expectedErrFn: assert.Error,
-		},
-		{
-			name: "return error when reading affected rows",
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"DELETE FROM spots WHERE CAST(id AS VARCHAR) = $1",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT DISTINCT locality FROM spots WHERE deleted_at IS NULL AND locality ILIKE $1 " +
+							"ORDER BY locality LIMIT 10",
 					)).
-					WithArgs("1").
-					WillReturnResult(sqlmock.NewErrorResult(
-						errors.New("unexpected error"),
-					))
+					WithArgs("ka%").
+					WillReturnError(errors.New("something went wrong"))
 			},
-			id:            "1",
-			expectedErrFn: assert.Error,
+			expectedLocalities: nil,
+			expectedErrFn:      assert.Error,
 		},
 		{
-			name: "return error for unexisting resource",
+			name: "return localities without error",
+			params: surf.LocalitiesParams{
+				Prefix:      "ka",
+				CountryCode: "kz",
+				Limit:       10,
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"DELETE FROM spots WHERE CAST(id AS VARCHAR) = $1",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT DISTINCT locality FROM spots WHERE deleted_at IS NULL AND locality ILIKE $1 "+
+							"AND country_code = $2 ORDER BY locality LIMIT 10",
 					)).
-					WithArgs("1").
-					WillReturnResult(sqlmock.NewResult(0, 0))
+					WithArgs("ka%", "kz").
+					WillReturnRows(sqlmock.
+						NewRows([]string{"locality"}).
+						AddRow("Kapchagay").
+						AddRow("Karaganda"),
+					).
+					RowsWillBeClosed()
 			},
-			id:            "1",
-			expectedErrFn: testutil.IsError(surf.ErrSpotNotFound),
+			expectedLocalities: []string{"Kapchagay", "Karaganda"},
+			expectedErrFn:      assert.NoError,
 		},
 		{
-			name: "return no error",
+			name: "return localities using escaped prefix without error",
+			params: surf.LocalitiesParams{
+				Prefix: `1%_\`,
+				Limit:  10,
+			},
 			mockFn: func(m sqlmock.Sqlmock) {
 				m.
-					ExpectExec(regexp.QuoteMeta(
-						"DELETE FROM spots WHERE CAST(id AS VARCHAR) = $1",
+					ExpectQuery(regexp.QuoteMeta(
+						"SELECT DISTINCT locality FROM spots WHERE deleted_at IS NULL AND locality ILIKE $1 " +
+							"ORDER BY locality LIMIT 10",
 					)).
-					WithArgs("1").
-					WillReturnResult(sqlmock.NewResult(0, 1))
+					WithArgs(`1\%\_\\%`).
+					WillReturnRows(sqlmock.NewRows([]string{"locality"})).
+					RowsWillBeClosed()
 			},
-			id:            "1",
-			expectedErrFn: assert.NoError,
+			expectedLocalities: nil,
+			expectedErrFn:      assert.NoError,
 		},
 	}
 
@@ -1318,10 +6976,109 @@ func TestSpotStore_DeleteSpot(t *testing.T) {
 			test.mockFn(mock)
 
 			store := NewSpotStore(sqlx.NewDb(db, psqlutil.DriverNameSQLMock))
-			err = store.DeleteSpot(test.id)
+			localities, err := store.Localities(context.Background(), test.params)
 			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedLocalities, localities)
 
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestSearchPattern(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		expectedPattern string
+	}{
+		{
+			name:            "return pattern for plain query",
+			query:           "qu",
+			expectedPattern: "%qu%",
+		},
+		{
+			name:            "return pattern with escaped wildcards",
+			query:           `1_0%\`,
+			expectedPattern: `%1\_0\%\\%`,
+		},
+		{
+			name:            "return pattern for query trimmed to max length",
+			query:           strutil.RepeatRune('ы', 101),
+			expectedPattern: "%" + strutil.RepeatRune('ы', 100) + "%",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern := searchPattern(test.query)
+			assert.Equal(t, test.expectedPattern, pattern)
+		})
+	}
+}
+
+func TestStoreError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectedErrFn func(*testing.T, error)
+	}{
+		{
+			name: "return error that is not reported by postgres as is",
+			err:  sql.ErrConnDone,
+			expectedErrFn: func(t *testing.T, err error) {
+				var sErr *surf.StoreError
+				assert.False(t, errors.As(err, &sErr))
+				assert.Equal(t, sql.ErrConnDone, err)
+			},
+		},
+		{
+			name: "return duplicate spot error for unique violation",
+			err:  &pq.Error{Code: "23505"},
+			expectedErrFn: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, surf.ErrDuplicateSpot))
+				assert.False(t, errors.Is(err, surf.ErrInvalidReference))
+
+				var sErr *surf.StoreError
+				if !assert.True(t, errors.As(err, &sErr)) {
+					return
+				}
+				assert.Equal(t, "23505", sErr.Code)
+			},
+		},
+		{
+			name: "return invalid reference error for foreign key violation",
+			err:  &pq.Error{Code: "23503"},
+			expectedErrFn: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, surf.ErrInvalidReference))
+				assert.False(t, errors.Is(err, surf.ErrDuplicateSpot))
+			},
+		},
+		{
+			name: "return unclassified error for other codes",
+			err:  &pq.Error{Code: "57P01"},
+			expectedErrFn: func(t *testing.T, err error) {
+				assert.False(t, errors.Is(err, surf.ErrDuplicateSpot))
+				assert.False(t, errors.Is(err, surf.ErrInvalidReference))
+
+				var sErr *surf.StoreError
+				if !assert.True(t, errors.As(err, &sErr)) {
+					return
+				}
+				assert.Equal(t, "57P01", sErr.Code)
+				assert.Equal(t, map[string]interface{}{"sqlstate": "57P01"}, sErr.LogFields())
+
+				var pqErr *pq.Error
+				if !assert.True(t, errors.As(err, &pqErr)) {
+					return
+				}
+				assert.Equal(t, pq.ErrorCode("57P01"), pqErr.Code)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.expectedErrFn(t, storeError(test.err))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+CREATE TABLE IF NOT EXISTS spots (
+	id TEXT PRIMARY KEY NOT NULL,
+	name TEXT NOT NULL,
+	latitude REAL NOT NULL,
+	longitude REAL NOT NULL,
+	locality TEXT NOT NULL,
+	country_code TEXT NOT NULL,
+	region TEXT NOT NULL DEFAULT '',
+	external_ref TEXT,
+	slug TEXT,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS spots_external_ref_key ON spots (external_ref) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS spots_country_code_idx ON spots (country_code);
+CREATE UNIQUE INDEX IF NOT EXISTS spots_slug_key ON spots (lower(slug)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS spot_tags (
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (spot_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS spot_tags_tag_idx ON spot_tags (tag);
+
+CREATE TABLE IF NOT EXISTS spot_photos (
+	id TEXT PRIMARY KEY NOT NULL,
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	content_type TEXT NOT NULL,
+	size INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS spot_photos_spot_id_created_at_idx ON spot_photos (spot_id, created_at);
+
+CREATE TABLE IF NOT EXISTS spot_ratings (
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+	rater_key TEXT NOT NULL,
+	rated_on TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (spot_id, rater_key, rated_on)
+);
+
+-- Users are not referenced since the spot store does not manage them.
+CREATE TABLE IF NOT EXISTS favorites (
+	user_id TEXT NOT NULL,
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	favorited_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (user_id, spot_id)
+);
+
+CREATE INDEX IF NOT EXISTS favorites_user_id_favorited_at_idx ON favorites (user_id, favorited_at);
+
+CREATE TABLE IF NOT EXISTS spot_suggestions (
+	id TEXT PRIMARY KEY NOT NULL,
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	status TEXT NOT NULL CHECK (status IN ('pending', 'applied', 'rejected')),
+	name TEXT,
+	latitude REAL,
+	longitude REAL,
+	locality TEXT,
+	country_code TEXT,
+	region TEXT,
+	note TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS spot_suggestions_status_created_at_idx ON spot_suggestions (status, created_at);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+	id TEXT PRIMARY KEY NOT NULL,
+	actor_id TEXT NOT NULL,
+	action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+	spot_id TEXT NOT NULL,
+	changes TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_spot_id_created_at_idx ON audit_log (spot_id, created_at);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	key TEXT PRIMARY KEY NOT NULL,
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reference_points (
+	reference_set TEXT NOT NULL,
+	name TEXT NOT NULL,
+	latitude REAL NOT NULL,
+	longitude REAL NOT NULL,
+	PRIMARY KEY (reference_set, name)
+);
+
+CREATE TABLE IF NOT EXISTS spot_reference_distances (
+	spot_id TEXT NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	reference_set TEXT NOT NULL,
+	reference_name TEXT NOT NULL,
+	distance_km REAL NOT NULL,
+	PRIMARY KEY (spot_id, reference_set)
+);
+
+CREATE INDEX IF NOT EXISTS spot_reference_distances_reference_set_distance_km_idx
+	ON spot_reference_distances (reference_set, distance_km);
+
+-- Users live in the same file, so that the whole API can run on a single SQLite
+-- database. IDs are random version 4 UUIDs like the ones of gen_random_uuid.
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY NOT NULL DEFAULT (
+		lower(hex(randomblob(4))) || '-' ||
+		lower(hex(randomblob(2))) || '-4' ||
+		substr(lower(hex(randomblob(2))), 2) || '-' ||
+		substr('89ab', 1 + abs(random()) % 4, 1) ||
+		substr(lower(hex(randomblob(2))), 2) || '-' ||
+		lower(hex(randomblob(6)))
+	),
+	email TEXT NOT NULL UNIQUE,
+	role TEXT NOT NULL CHECK (role IN ('admin')),
+	password_hash TEXT NOT NULL,
+	password_salt TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ', 'now'))
+);
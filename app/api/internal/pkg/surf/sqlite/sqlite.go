@@ -0,0 +1,2953 @@
+// Package sqlite provides a spot store backed by a single SQLite database file,
+// which lets small self-hosted installations run without PostgreSQL.
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/batch"
+	"github.com/ztimes2/tolqin/app/api/pkg/psqlutil"
+	"github.com/ztimes2/tolqin/app/api/pkg/slug"
+
+	// The driver is written in pure Go, so the binary can still be built without
+	// cgo.
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// DriverName is a name of the SQLite driver.
+	DriverName = "sqlite"
+
+	defaultBatchSize = 100
+
+	// maxSlugAttempts limits how many times a spot is tried to be inserted with a
+	// slug that keeps getting taken by spots created at the same time.
+	maxSlugAttempts = 3
+
+	// maxSearchQueryChars mirrors the search query limit enforced by the services
+	// so that oversized patterns never reach the database.
+	maxSearchQueryChars = 100
+
+	// timeFormat keeps timestamps in UTC with a fixed number of fractional digits,
+	// so that they are ordered correctly when compared as strings. Both the date
+	// functions of SQLite and the driver understand it, and the driver parses it
+	// back into time.Time for columns declared as TIMESTAMP.
+	timeFormat = "2006-01-02 15:04:05.000000Z"
+
+	// tagsSeparator separates tags aggregated into a single string. It is the
+	// ASCII unit separator, which tags are not expected to contain.
+	tagsSeparator = "\x1f"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens an SQLite database file at the given path, creating it if it does
+// not exist, and bootstraps its schema.
+func Open(path string) (*sqlx.DB, error) {
+	db, err := sqlx.Open(DriverName, path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite lets a single connection write at a time. Sharing one connection
+	// queues concurrent writes instead of failing them with SQLITE_BUSY, and keeps
+	// in-memory databases from being split across connections.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to bootstrap schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// SpotStore is an SQLite database adapter that stores spots. It implements the
+// same interfaces as the PostgreSQL store with the following differences:
+//
+//   - Searches lower-case both sides of LIKE, which folds ASCII letters only.
+//   - The full-text search matches spots whose names and localities contain
+//     every word of the query as a substring. It neither ranks spots nor
+//     supports the operators of websearch_to_tsquery such as quotes and OR.
+//   - Query plans are returned as steps of EXPLAIN QUERY PLAN encoded in JSON.
+//
+// Opened databases are expected to be passed to it, since it relies on the
+// schema that Open bootstraps.
+type SpotStore struct {
+	db        *sqlx.DB
+	builder   sq.StatementBuilderType
+	batchSize int
+	now       func() time.Time
+
+	reserveDeletedSlugs bool
+}
+
+// NewSpotStore returns a new *SpotStore using the given database connector and
+// various options.
+func NewSpotStore(db *sqlx.DB, opts ...SpotStoreOption) *SpotStore {
+	ss := &SpotStore{
+		db:        db,
+		builder:   sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		batchSize: defaultBatchSize,
+		now: func() time.Time {
+			// Timestamps are stored with a precision of microseconds.
+			return time.Now().UTC().Truncate(time.Microsecond)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(ss)
+	}
+
+	return ss
+}
+
+// SpotStoreOption is an optional function for SpotStore.
+type SpotStoreOption func(*SpotStore)
+
+// WithBatchSize sets a custom batch size for batch processing in the Multi methods
+// of SpotStore.
+func WithBatchSize(size int) SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.batchSize = size
+	}
+}
+
+// WithDeletedSlugsReserved keeps slugs of soft-deleted spots reserved in the same
+// way as in the PostgreSQL store.
+func WithDeletedSlugsReserved() SpotStoreOption {
+	return func(ss *SpotStore) {
+		ss.reserveDeletedSlugs = true
+	}
+}
+
+// BatchSize returns the batch size that is used for batch processing in the Multi
+// methods of SpotStore.
+func (ss *SpotStore) BatchSize() int {
+	return ss.batchSize
+}
+
+// Spot returns a spot by the given ID. surf.ErrSpotNotFound is returned when spot
+// is not found or soft-deleted.
+func (ss *SpotStore) Spot(ctx context.Context, id string) (surf.Spot, error) {
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted),
+	).ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpot(s), nil
+}
+
+// SpotBySlug returns a spot by the given slug. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotBySlug(ctx context.Context, slug string) (surf.Spot, error) {
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"lower(slug)": strings.ToLower(slug)}).
+		Where(notDeleted),
+	).ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpot(s), nil
+}
+
+// uniqueSlug returns the given slug suffixed with -2, -3, and so on when it is
+// taken by a spot other than the one by the given ID, ignoring case. Slugs of
+// soft-deleted spots are taken into account only when they are reserved.
+func (ss *SpotStore) uniqueSlug(ctx context.Context, q sqlx.QueryerContext, base, spotID string) (string, error) {
+	base = strings.ToLower(base)
+
+	builder := ss.builder.
+		Select("lower(slug)").
+		From("spots").
+		Where(sq.Or{
+			sq.Eq{"lower(slug)": base},
+			sq.Like{"lower(slug)": base + "-%"},
+		})
+	if !ss.reserveDeletedSlugs {
+		builder = builder.Where(notDeleted)
+	}
+	if spotID != "" {
+		builder = builder.Where(sq.NotEq{"id": spotID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	defer rows.Close()
+	var taken []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return "", fmt.Errorf("failed to scan row: %w", err)
+		}
+		taken = append(taken, t)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return slug.Unique(base, taken), nil
+}
+
+// Spots returns multiple spots that match the given parameters. Soft-deleted spots
+// are excluded unless they are explicitly requested.
+func (ss *SpotStore) Spots(ctx context.Context, p surf.SpotsParams) ([]surf.Spot, error) {
+	query, args, err := withRatingsSQL(buildSpotsSQL(ss.builder, p)).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return ss.querySpots(ctx, ss.db, query, args...)
+}
+
+func (ss *SpotStore) querySpots(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) ([]surf.Spot, error) {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var spots []surf.Spot
+	defer rows.Close()
+	for rows.Next() {
+		var s spot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, toSpot(s))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return spots, nil
+}
+
+// IterateSpots calls the given function for every spot that matches the given
+// parameters. Spots are fetched page by page in the order of their IDs, using the
+// configured batch size as a page size, so that only a single page is held in
+// memory at once.
+func (ss *SpotStore) IterateSpots(ctx context.Context, p surf.SpotIterationParams, fn func(surf.Spot) error) error {
+	var lastID string
+	for {
+		page, err := ss.spotsPage(ctx, p, lastID)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range page {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < ss.batchSize {
+			return nil
+		}
+		lastID = page[len(page)-1].ID
+	}
+}
+
+func (ss *SpotStore) spotsPage(ctx context.Context, p surf.SpotIterationParams, afterID string) ([]surf.Spot, error) {
+	builder := buildSpotsSQL(ss.builder, surf.SpotsParams{
+		Limit:       ss.batchSize,
+		CountryCode: p.CountryCode,
+		SearchQuery: p.SearchQuery,
+		Bounds:      p.Bounds,
+	}).Column("external_ref").OrderBy("id")
+
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return ss.querySpots(ctx, ss.db, query, args...)
+}
+
+// ExplainSpots returns steps of the query plan, encoded in JSON, of the query that
+// reads multiple spots matching the given parameters. The query itself is planned
+// but not executed.
+func (ss *SpotStore) ExplainSpots(ctx context.Context, p surf.SpotsParams) ([]byte, error) {
+	query, args, err := withRatingsSQL(buildSpotsSQL(ss.builder, p)).
+		Prefix("EXPLAIN QUERY PLAN").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var steps []planStep
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			s       planStep
+			notUsed int
+		)
+		if err := rows.Scan(&s.ID, &s.Parent, &notUsed, &s.Detail); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		steps = append(steps, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	plan, err := json.Marshal(steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// withRatingsSQL adds aggregated ratings to spots being read. Ratings are grouped
+// by spots in a subquery that is joined once, rather than aggregated per every
+// spot being read.
+func withRatingsSQL(builder sq.SelectBuilder) sq.SelectBuilder {
+	return builder.
+		Columns(
+			"COALESCE(sr.average_rating, 0) AS average_rating",
+			"COALESCE(sr.ratings_count, 0) AS ratings_count",
+		).
+		LeftJoin(spotRatingsSQL)
+}
+
+// spotRatingsSQL aggregates ratings of every spot.
+const spotRatingsSQL = "(" +
+	"SELECT spot_id, AVG(score) AS average_rating, COUNT(*) AS ratings_count FROM spot_ratings GROUP BY spot_id" +
+	") AS sr ON sr.spot_id = spots.id"
+
+type planStep struct {
+	ID     int    `json:"id"`
+	Parent int    `json:"parent"`
+	Detail string `json:"detail"`
+}
+
+func buildSpotsSQL(b sq.StatementBuilderType, p surf.SpotsParams) sq.SelectBuilder {
+	columns := spotColumns
+	if p.IncludeDeleted {
+		columns = append(append([]string(nil), spotColumns...), "deleted_at")
+	}
+
+	builder := b.
+		Select(columns...).
+		From("spots").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if !p.IncludeDeleted {
+		builder = builder.Where(notDeleted)
+	}
+
+	if p.Order == surf.SpotOrderUpdatedAtDesc {
+		builder = builder.OrderBy("updated_at DESC", "id")
+	}
+
+	if p.ReferenceSet != "" {
+		builder = builder.
+			Columns("srd.reference_name", "srd.distance_km").
+			LeftJoin(
+				"spot_reference_distances AS srd ON srd.spot_id = spots.id AND srd.reference_set = ?",
+				p.ReferenceSet,
+			)
+		if p.Order == surf.SpotOrderReferenceDistance {
+			builder = builder.OrderBy("srd.distance_km NULLS LAST", "id")
+		}
+	}
+
+	if len(p.Tags) > 0 {
+		builder = builder.Where(tagsSQL(p.Tags, p.TagsMatch))
+	}
+
+	return filterSpotsSQL(builder, p.CountryCode, p.SearchQuery, p.Bounds)
+}
+
+// tagsSQL returns a condition matching spots that have all or any of the given
+// tags.
+func tagsSQL(tags []string, match surf.SpotTagsMatch) sq.Sqlizer {
+	var (
+		args     = make([]interface{}, len(tags))
+		distinct = make(map[string]bool, len(tags))
+	)
+	for i, t := range tags {
+		args[i] = t
+		distinct[t] = true
+	}
+
+	count := "(SELECT COUNT(DISTINCT tag) FROM spot_tags " +
+		"WHERE spot_tags.spot_id = spots.id AND tag IN (" + sq.Placeholders(len(tags)) + "))"
+
+	if match == surf.SpotTagsMatchAny {
+		return sq.Expr(count+" > 0", args...)
+	}
+	return sq.Expr(count+" = ?", append(args, len(distinct))...)
+}
+
+func filterSpotsSQL(
+	builder sq.SelectBuilder,
+	countryCode string,
+	q surf.SpotSearchQuery,
+	bounds *geo.Bounds) sq.SelectBuilder {
+
+	if countryCode != "" {
+		builder = builder.Where(sq.Eq{"country_code": countryCode})
+	}
+
+	if q.Query != "" {
+		var or sq.Or
+		if q.FullText {
+			or = sq.Or{
+				wordsSQL(q.Query),
+			}
+		} else {
+			or = sq.Or{
+				likeFold("name", searchPattern(q.Query)),
+				likeFold("locality", searchPattern(q.Query)),
+			}
+		}
+		if q.WithSpotID {
+			or = append(or, likeFold("id", searchPattern(q.Query)))
+		}
+		builder = builder.Where(or)
+	}
+
+	if bounds != nil {
+		builder = builder.Where(boundsSQL(*bounds))
+	}
+
+	return builder
+}
+
+// likeFold returns a condition matching the given expression against the given
+// LIKE pattern escaped by psqlutil.EscapeLike. Both sides are lower-cased, so that
+// the match stays case-insensitive even if case_sensitive_like is turned on.
+func likeFold(expr, pattern string) sq.Sqlizer {
+	return sq.Expr("LOWER("+expr+") LIKE LOWER(?) ESCAPE '\\'", pattern)
+}
+
+// wordsSQL returns a condition matching spots whose names and localities contain
+// every word of the given query. Words are split in the same way as the simple
+// text search configuration of PostgreSQL does, and nothing is matched when the
+// query has none.
+func wordsSQL(query string) sq.Sqlizer {
+	if utf8.RuneCountInString(query) > maxSearchQueryChars {
+		query = string([]rune(query)[:maxSearchQueryChars])
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return sq.Expr("1 = 0")
+	}
+
+	and := make(sq.And, len(words))
+	for i, w := range words {
+		and[i] = likeFold("name || ' ' || locality", psqlutil.Wildcard(psqlutil.EscapeLike(w)))
+	}
+	return and
+}
+
+// boundsSQL returns a condition matching coordinates within the given bounds.
+// Bounds crossing the 180th meridian are matched as two separate boxes.
+func boundsSQL(b geo.Bounds) sq.Sqlizer {
+	boxes := geo.NormalizeBounds(b)
+	if len(boxes) == 1 {
+		return boxSQL(boxes[0])
+	}
+
+	or := make(sq.Or, 0, len(boxes))
+	for _, box := range boxes {
+		or = append(or, boxSQL(box))
+	}
+	return or
+}
+
+func boxSQL(b geo.Bounds) sq.Sqlizer {
+	return sq.And{
+		psqlutil.Between("latitude", b.SouthWest.Latitude, b.NorthEast.Latitude),
+		psqlutil.Between("longitude", b.SouthWest.Longitude, b.NorthEast.Longitude),
+	}
+}
+
+// searchPattern converts the given search query into a LIKE pattern that matches
+// the query literally as a substring.
+func searchPattern(query string) string {
+	if utf8.RuneCountInString(query) > maxSearchQueryChars {
+		query = string([]rune(query)[:maxSearchQueryChars])
+	}
+	return psqlutil.Wildcard(psqlutil.EscapeLike(query))
+}
+
+// CountriesWithSpotCounts returns countries of spots that match the given parameters
+// along with numbers of such spots in them, ordered from the country with the most
+// spots.
+func (ss *SpotStore) CountriesWithSpotCounts(ctx context.Context, p surf.CountriesParams) ([]surf.CountrySpotCount, error) {
+	builder := ss.builder.
+		Select("country_code", "COUNT(*) AS count").
+		From("spots").
+		Where(notDeleted).
+		GroupBy("country_code").
+		OrderBy("count DESC", "country_code")
+
+	query, args, err := filterSpotsSQL(builder, "", p.SearchQuery, nil).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var counts []surf.CountrySpotCount
+	defer rows.Close()
+	for rows.Next() {
+		var c countrySpotCount
+		if err := rows.StructScan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts = append(counts, surf.CountrySpotCount(c))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+type countrySpotCount struct {
+	CountryCode string `db:"country_code"`
+	Count       int    `db:"count"`
+}
+
+// spotDirectorySQL defines common table expressions for SpotDirectory in the same
+// way as the PostgreSQL store does. The limit and the offset of the page of
+// countries are expected to be passed.
+const spotDirectorySQL = "WITH countries AS (" +
+	"SELECT country_code, COUNT(*) AS spot_count, COUNT(DISTINCT locality) AS locality_count " +
+	"FROM spots WHERE deleted_at IS NULL " +
+	"GROUP BY country_code ORDER BY country_code LIMIT ? OFFSET ?" +
+	"), localities AS (" +
+	"SELECT country_code, locality, COUNT(*) AS spot_count, " +
+	"ROW_NUMBER() OVER (PARTITION BY country_code ORDER BY locality) AS locality_rank " +
+	"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries) " +
+	"GROUP BY country_code, locality" +
+	"), ranked_spots AS (" +
+	"SELECT id, name, latitude, longitude, country_code, locality, " +
+	"ROW_NUMBER() OVER (PARTITION BY country_code, locality ORDER BY name, id) AS spot_rank " +
+	"FROM spots WHERE deleted_at IS NULL AND country_code IN (SELECT country_code FROM countries)" +
+	")"
+
+// SpotDirectory returns countries of spots ordered by their codes, along with their
+// localities and spots limited according to the given parameters.
+func (ss *SpotStore) SpotDirectory(ctx context.Context, p surf.SpotDirectoryParams) ([]surf.DirectoryCountry, error) {
+	query, args, err := ss.builder.
+		Select(
+			"c.country_code",
+			"c.spot_count AS country_spot_count",
+			"c.locality_count",
+			"l.locality",
+			"l.spot_count AS locality_spot_count",
+			"s.id",
+			"s.name",
+			"s.latitude",
+			"s.longitude",
+		).
+		Prefix(spotDirectorySQL, p.Limit, p.Offset).
+		From("countries AS c").
+		Join("localities AS l ON l.country_code = c.country_code AND l.locality_rank <= ?", p.LocalitiesPerCountry).
+		Join(
+			"ranked_spots AS s ON s.country_code = l.country_code AND s.locality = l.locality AND s.spot_rank <= ?",
+			p.SpotsPerLocality,
+		).
+		OrderBy("c.country_code", "l.locality", "s.spot_rank").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var countries []surf.DirectoryCountry
+	defer rows.Close()
+	for rows.Next() {
+		var d directorySpot
+		if err := rows.StructScan(&d); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		countries = appendDirectorySpot(countries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return countries, nil
+}
+
+type directorySpot struct {
+	CountryCode       string  `db:"country_code"`
+	CountrySpotCount  int     `db:"country_spot_count"`
+	LocalityCount     int     `db:"locality_count"`
+	Locality          string  `db:"locality"`
+	LocalitySpotCount int     `db:"locality_spot_count"`
+	ID                string  `db:"id"`
+	Name              string  `db:"name"`
+	Latitude          float64 `db:"latitude"`
+	Longitude         float64 `db:"longitude"`
+}
+
+// appendDirectorySpot nests the given spot into the last country and locality of
+// the given countries, or into new ones if the spot belongs to different ones.
+// Spots are expected to be ordered by their countries and localities.
+func appendDirectorySpot(countries []surf.DirectoryCountry, d directorySpot) []surf.DirectoryCountry {
+	if len(countries) == 0 || countries[len(countries)-1].CountryCode != d.CountryCode {
+		countries = append(countries, surf.DirectoryCountry{
+			CountryCode:   d.CountryCode,
+			SpotCount:     d.CountrySpotCount,
+			LocalityCount: d.LocalityCount,
+		})
+	}
+
+	c := &countries[len(countries)-1]
+	if len(c.Localities) == 0 || c.Localities[len(c.Localities)-1].Locality != d.Locality {
+		c.Localities = append(c.Localities, surf.DirectoryLocality{
+			Locality:  d.Locality,
+			SpotCount: d.LocalitySpotCount,
+		})
+	}
+
+	l := &c.Localities[len(c.Localities)-1]
+	l.Spots = append(l.Spots, surf.Spot{
+		ID:   d.ID,
+		Name: d.Name,
+		Location: geo.Location{
+			Locality:    d.Locality,
+			CountryCode: d.CountryCode,
+			Coordinates: geo.Coordinates{
+				Latitude:  d.Latitude,
+				Longitude: d.Longitude,
+			},
+		},
+	})
+
+	return countries
+}
+
+// SpotCreationCounts returns numbers of spots created within time buckets of the
+// given interval, ordered from the earliest bucket. Buckets without any spots
+// created are omitted.
+func (ss *SpotStore) SpotCreationCounts(ctx context.Context, p surf.SpotCreationCountsParams) ([]surf.SpotCreationCount, error) {
+	query, args, err := ss.builder.
+		Select(bucketSQL(p.Interval)+" AS bucket_start", "COUNT(*) AS count").
+		From("spots").
+		Where(notDeleted).
+		Where(sq.GtOrEq{"created_at": formatTime(p.From)}).
+		Where(sq.Lt{"created_at": formatTime(p.To)}).
+		GroupBy("bucket_start").
+		OrderBy("bucket_start").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var counts []surf.SpotCreationCount
+	defer rows.Close()
+	for rows.Next() {
+		var c spotCreationCount
+		if err := rows.StructScan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		// Dates computed by SQLite are plain strings, which the driver does not
+		// parse on its own.
+		start, err := time.Parse("2006-01-02", c.BucketStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bucket start: %w", err)
+		}
+
+		counts = append(counts, surf.SpotCreationCount{
+			BucketStart: start,
+			Count:       c.Count,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+type spotCreationCount struct {
+	BucketStart string `db:"bucket_start"`
+	Count       int    `db:"count"`
+}
+
+// bucketSQL returns an expression that truncates created_at of spots to the date
+// that its bucket of the given interval starts on, with weeks starting on Mondays
+// like in date_trunc of PostgreSQL.
+func bucketSQL(i surf.TimeInterval) string {
+	switch i {
+	case surf.TimeIntervalDay:
+		return "date(created_at)"
+	case surf.TimeIntervalWeek:
+		return "date(created_at, '-' || ((CAST(strftime('%w', created_at) AS INTEGER) + 6) % 7) || ' days')"
+	default:
+		return "date(created_at, 'start of month')"
+	}
+}
+
+// Localities returns distinct localities of spots that match the given parameters
+// in alphabetical order. The prefix is escaped, so that wildcard characters within
+// it are matched literally.
+func (ss *SpotStore) Localities(ctx context.Context, p surf.LocalitiesParams) ([]string, error) {
+	builder := ss.builder.
+		Select("locality").
+		Distinct().
+		From("spots").
+		Where(notDeleted).
+		Where(likeFold("locality", psqlutil.EscapeLike(p.Prefix)+"%")).
+		OrderBy("locality").
+		Limit(uint64(p.Limit))
+
+	if p.CountryCode != "" {
+		builder = builder.Where(sq.Eq{"country_code": p.CountryCode})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var localities []string
+	defer rows.Close()
+	for rows.Next() {
+		var l string
+		if err := rows.Scan(&l); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		localities = append(localities, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return localities, nil
+}
+
+// SpotsCentroid returns the average coordinates of spots that match the given
+// parameters. Nil is returned when no spots match. Longitudes are averaged as
+// angles in the same way as the PostgreSQL store does.
+func (ss *SpotStore) SpotsCentroid(ctx context.Context, p surf.SpotsCentroidParams) (*geo.Coordinates, error) {
+	builder := ss.builder.
+		Select(
+			"COUNT(*) AS count",
+			"AVG(latitude) AS latitude",
+			"AVG(SIN(RADIANS(longitude))) AS longitude_sin",
+			"AVG(COS(RADIANS(longitude))) AS longitude_cos",
+		).
+		From("spots").
+		Where(notDeleted)
+
+	query, args, err := filterSpotsSQL(builder, p.CountryCode, p.SearchQuery, p.Bounds).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var c centroid
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&c); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if c.Count == 0 {
+		return nil, nil
+	}
+
+	return &geo.Coordinates{
+		Latitude:  c.Latitude.Float64,
+		Longitude: math.Atan2(c.LongitudeSin.Float64, c.LongitudeCos.Float64) * 180 / math.Pi,
+	}, nil
+}
+
+type centroid struct {
+	Count        int             `db:"count"`
+	Latitude     sql.NullFloat64 `db:"latitude"`
+	LongitudeSin sql.NullFloat64 `db:"longitude_sin"`
+	LongitudeCos sql.NullFloat64 `db:"longitude_cos"`
+}
+
+// RandomSpots returns multiple randomly picked spots that match the given parameters.
+func (ss *SpotStore) RandomSpots(ctx context.Context, p surf.RandomSpotsParams) ([]surf.Spot, error) {
+	query, args, err := buildSpotsSQL(ss.builder, surf.SpotsParams{
+		Limit:       p.Limit,
+		CountryCode: p.CountryCode,
+	}).
+		OrderBy("random()").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return ss.querySpots(ctx, ss.db, query, args...)
+}
+
+// haversineSQL computes a great-circle distance in kilometers between a spot and a
+// point in the same way as the PostgreSQL store does. Latitude of the point is
+// expected to be passed twice, followed by its longitude. The square root is
+// clamped to 1, since rounding can push it above 1 for antipodal points, which
+// ASIN is undefined for.
+const haversineSQL = "2 * 6371 * ASIN(MIN(1, SQRT(" +
+	"POWER(SIN(RADIANS(latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2)" +
+	")))"
+
+// NearbySpots returns multiple spots located within the given radius from the given
+// point, ordered by their distance from the point.
+func (ss *SpotStore) NearbySpots(ctx context.Context, p surf.NearbySpotsParams) ([]surf.NearbySpot, error) {
+	distances := ss.builder.
+		Select(spotColumns...).
+		Column(
+			sq.Alias(
+				sq.Expr(
+					haversineSQL,
+					p.Coordinates.Latitude,
+					p.Coordinates.Latitude,
+					p.Coordinates.Longitude,
+				),
+				"distance_km",
+			),
+		).
+		From("spots").
+		Where(notDeleted)
+
+	query, args, err := ss.builder.
+		Select("*").
+		FromSelect(distances, "s").
+		Where(sq.LtOrEq{"distance_km": p.RadiusKm}).
+		OrderBy("distance_km", "id").
+		Limit(uint64(p.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var spots []surf.NearbySpot
+	defer rows.Close()
+	for rows.Next() {
+		var s nearbySpot
+		if err := rows.StructScan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		spots = append(spots, surf.NearbySpot{
+			Spot:       toSpot(s.spot),
+			DistanceKm: s.DistanceKm,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return spots, nil
+}
+
+type nearbySpot struct {
+	spot
+	DistanceKm float64 `db:"distance_km"`
+}
+
+// SpotMarkers returns markers of multiple spots that match the given parameters.
+func (ss *SpotStore) SpotMarkers(ctx context.Context, p surf.SpotMarkersParams) ([]surf.SpotMarker, error) {
+	builder := ss.builder.
+		Select("id", "latitude", "longitude").
+		From("spots").
+		Where(notDeleted).
+		Limit(uint64(p.Limit))
+
+	if p.Bounds != nil {
+		builder = builder.Where(boundsSQL(*p.Bounds))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var markers []surf.SpotMarker
+	defer rows.Close()
+	for rows.Next() {
+		var m spotMarker
+		if err := rows.StructScan(&m); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		markers = append(markers, surf.SpotMarker{
+			ID: m.ID,
+			Coordinates: geo.Coordinates{
+				Latitude:  m.Latitude,
+				Longitude: m.Longitude,
+			},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return markers, nil
+}
+
+type spotMarker struct {
+	ID        string  `db:"id"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
+
+// CreateSpot creates a new spot using the given entry and returns it if the creation
+// succeeds. Like in the PostgreSQL store, neither tags nor the external reference
+// of the entry are stored, and a slug of the entry is suffixed when it is taken by
+// another spot.
+func (ss *SpotStore) CreateSpot(ctx context.Context, e surf.SpotCreationEntry) (surf.Spot, error) {
+	return ss.audited(ctx, surf.AuditActionCreate, "", func(q sqlx.ExtContext) (surf.Spot, error) {
+		return ss.insertSpot(ctx, q, e)
+	})
+}
+
+// insertSpot inserts a new spot using the given entry, retrying with a suffixed
+// slug when another spot turns out to have the slug of the entry in the same way
+// as in the PostgreSQL store. Statements outside of transactions can interleave on
+// the shared connection, so the slug is not checked beforehand either.
+func (ss *SpotStore) insertSpot(ctx context.Context, q sqlx.ExtContext, e surf.SpotCreationEntry) (surf.Spot, error) {
+	candidate := e.Slug
+	if candidate != "" && ss.reserveDeletedSlugs {
+		var err error
+		if candidate, err = ss.uniqueSlug(ctx, q, e.Slug, ""); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		spot, err := ss.insertSpotWithSlug(ctx, q, e, candidate)
+		if !errors.Is(err, errSlugTaken) {
+			return spot, err
+		}
+		if attempt == maxSlugAttempts {
+			return surf.Spot{}, fmt.Errorf("failed to find unique slug: %w", surf.ErrDuplicateSpot)
+		}
+
+		if candidate, err = ss.uniqueSlug(ctx, q, e.Slug, ""); err != nil {
+			return surf.Spot{}, err
+		}
+	}
+}
+
+// errSlugTaken is used when a spot cannot be inserted because another spot that
+// is not soft-deleted has the same slug.
+var errSlugTaken = errors.New("slug taken")
+
+func (ss *SpotStore) insertSpotWithSlug(ctx context.Context, q sqlx.ExtContext, e surf.SpotCreationEntry, candidate string) (surf.Spot, error) {
+	id, err := newUUID()
+	if err != nil {
+		return surf.Spot{}, err
+	}
+
+	now := formatTime(ss.now())
+
+	columns := []string{"id", "name", "latitude", "longitude", "locality", "country_code", "created_at", "updated_at"}
+	values := []interface{}{
+		id,
+		e.Name,
+		e.Location.Coordinates.Latitude,
+		e.Location.Coordinates.Longitude,
+		e.Location.Locality,
+		e.Location.CountryCode,
+		now,
+		now,
+	}
+	suffix := "RETURNING " + strings.Join(spotColumns, ", ")
+	if candidate != "" {
+		columns = append(columns, "slug")
+		values = append(values, candidate)
+		suffix = "ON CONFLICT (lower(slug)) WHERE deleted_at IS NULL DO NOTHING " + suffix
+	}
+
+	query, args, err := ss.builder.
+		Insert("spots").
+		Columns(columns...).
+		Values(values...).
+		Suffix(suffix).
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := q.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, errSlugTaken
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpot(s), nil
+}
+
+// CreateSpots creates multiple new spots using the given entries and mode, and
+// returns statistics about the affected spots. Entries are inserted in batches of
+// the configured size within a single transaction, so either all or none of them
+// are applied.
+//
+// In surf.SpotCreationModeUpsert mode, entries are matched against existing spots
+// in the same way as in the PostgreSQL store: by external references, or by IDs of
+// spots without an external reference, ignoring soft-deleted spots. When several
+// entries share the same external reference, only the last one of them is used.
+// Tags of the matched spots are replaced with the ones of the entries unless the
+// entries have none.
+func (ss *SpotStore) CreateSpots(ctx context.Context, entries []surf.SpotCreationEntry, mode surf.SpotCreationMode) (surf.SpotCreationStats, error) {
+	if len(entries) == 0 {
+		return surf.SpotCreationStats{}, errors.New("no entries")
+	}
+
+	createFn := ss.insertSpots
+	if mode == surf.SpotCreationModeUpsert {
+		createFn = ss.upsertSpots
+		entries = dedupeByExternalRef(entries)
+	}
+
+	tx, err := ss.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var (
+		stats surf.SpotCreationStats
+		now   = formatTime(ss.now())
+	)
+
+	coord := batch.New(len(entries), ss.batchSize)
+	for coord.HasNext() {
+		b := coord.Batch()
+
+		s, err := createFn(ctx, tx, entries[b.I:b.J+1], now)
+		if err != nil {
+			_ = tx.Rollback()
+			return surf.SpotCreationStats{}, fmt.Errorf("failed to import spots: %w", err)
+		}
+
+		stats.Inserted += s.Inserted
+		stats.Updated += s.Updated
+	}
+
+	if err := tx.Commit(); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (ss *SpotStore) insertSpots(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry, now string) (surf.SpotCreationStats, error) {
+	ids, err := newUUIDs(len(entries))
+	if err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	query, args, err := buildCreateSpotsSQL(ss.builder, ids, entries, now).ToSql()
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if err := ss.replaceSpotTags(ctx, tx, ids, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	for i, id := range ids {
+		after := createdSpot(id, entries[i])
+		if err := ss.audit(ctx, tx, surf.AuditActionCreate, id, surf.SpotChanges(nil, &after)); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+	}
+
+	return surf.SpotCreationStats{
+		Inserted: len(ids),
+	}, nil
+}
+
+// upsertSpots inserts the given entries and updates the spots that have the same
+// external references instead. Unlike PostgreSQL, SQLite cannot tell inserted rows
+// apart from updated ones, so the spots that are going to be updated are looked up
+// beforehand within the same transaction.
+func (ss *SpotStore) upsertSpots(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry, now string) (surf.SpotCreationStats, error) {
+	if err := ss.adoptSpotIDsAsExternalRefs(ctx, tx, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	existing, err := ss.spotIDsByExternalRefs(ctx, tx, entries)
+	if err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	matched, err := ss.matchedSpots(ctx, tx, existing)
+	if err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	ids, err := newUUIDs(len(entries))
+	if err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	query, args, err := buildCreateSpotsSQL(ss.builder, ids, entries, now).
+		Suffix(
+			// External references are unique only among spots that are not
+			// soft-deleted, so soft-deleted spots never get matched.
+			"ON CONFLICT (external_ref) WHERE deleted_at IS NULL DO UPDATE SET " +
+				"name = excluded.name, " +
+				"latitude = excluded.latitude, " +
+				"longitude = excluded.longitude, " +
+				"locality = excluded.locality, " +
+				"country_code = excluded.country_code, " +
+				"updated_at = excluded.updated_at",
+		).
+		ToSql()
+	if err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return surf.SpotCreationStats{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var stats surf.SpotCreationStats
+	for i, e := range entries {
+		if id, ok := existing[e.ExternalRef]; ok && e.ExternalRef != "" {
+			ids[i] = id
+			stats.Updated++
+		} else {
+			stats.Inserted++
+		}
+	}
+
+	if err := ss.replaceSpotTags(ctx, tx, ids, entries); err != nil {
+		return surf.SpotCreationStats{}, err
+	}
+
+	for i, id := range ids {
+		after := createdSpot(id, entries[i])
+
+		action, changes := surf.AuditActionCreate, surf.SpotChanges(nil, &after)
+		if before, ok := matched[id]; ok {
+			// The upsert leaves the rest of the fields of matched spots as is.
+			after.Location.Region = before.Location.Region
+			after.Slug = before.Slug
+			action, changes = surf.AuditActionUpdate, surf.SpotChanges(&before, &after)
+		}
+
+		if err := ss.audit(ctx, tx, action, id, changes); err != nil {
+			return surf.SpotCreationStats{}, err
+		}
+	}
+
+	return stats, nil
+}
+
+// matchedSpots reads spots by IDs that the given external references are mapped
+// to, so that their states before the upsert can be audited. Spots are keyed by
+// their IDs. Nothing is read when the context holds no audit actor.
+func (ss *SpotStore) matchedSpots(ctx context.Context, tx *sqlx.Tx, idsByRefs map[string]string) (map[string]surf.Spot, error) {
+	if _, ok := surf.AuditActorFromContext(ctx); !ok || len(idsByRefs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(idsByRefs))
+	for _, id := range idsByRefs {
+		ids = append(ids, id)
+	}
+
+	query, args, err := ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	spots, err := ss.querySpots(ctx, tx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]surf.Spot, len(spots))
+	for _, s := range spots {
+		matched[s.ID] = s
+	}
+	return matched, nil
+}
+
+// createdSpot returns a state of a spot by the given ID that is created out of the
+// given entry.
+func createdSpot(id string, e surf.SpotCreationEntry) surf.Spot {
+	return surf.Spot{
+		ID:   id,
+		Name: e.Name,
+		Location: geo.Location{
+			Locality:    e.Location.Locality,
+			CountryCode: e.Location.CountryCode,
+			Coordinates: e.Location.Coordinates,
+		},
+	}
+}
+
+// spotIDsByExternalRefs returns IDs of spots that are not soft-deleted by external
+// references of the given entries.
+func (ss *SpotStore) spotIDsByExternalRefs(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) (map[string]string, error) {
+	refs := externalRefs(entries)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := ss.builder.
+		Select("id", "external_ref").
+		From("spots").
+		Where(sq.Eq{"external_ref": refs}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := tx.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	ids := make(map[string]string)
+	defer rows.Close()
+	for rows.Next() {
+		var id, ref string
+		if err := rows.Scan(&id, &ref); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids[ref] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// replaceSpotTags replaces tags of the spots by the given IDs with tags of the
+// entries at the same positions. Spots whose entries have no tags are left as is.
+func (ss *SpotStore) replaceSpotTags(ctx context.Context, tx *sqlx.Tx, ids []string, entries []surf.SpotCreationEntry) error {
+	var (
+		tagged  []string
+		builder = ss.builder.Insert("spot_tags").Columns("spot_id", "tag")
+	)
+	for i, e := range entries {
+		if len(e.Tags) == 0 {
+			continue
+		}
+
+		tagged = append(tagged, ids[i])
+		for _, tag := range uniqueTags(e.Tags) {
+			builder = builder.Values(ids[i], tag)
+		}
+	}
+
+	if len(tagged) == 0 {
+		return nil
+	}
+
+	query, args, err := ss.builder.
+		Delete("spot_tags").
+		Where(sq.Eq{"spot_id": tagged}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// adoptSpotIDsAsExternalRefs sets IDs of spots without an external reference as
+// their external references if the given entries refer to them, so that such spots
+// get matched by the upsert that follows.
+func (ss *SpotStore) adoptSpotIDsAsExternalRefs(ctx context.Context, tx *sqlx.Tx, entries []surf.SpotCreationEntry) error {
+	refs := externalRefs(entries)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("external_ref", sq.Expr("id")).
+		Where(sq.Eq{"external_ref": nil}).
+		Where(sq.Eq{"id": refs}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func externalRefs(entries []surf.SpotCreationEntry) []string {
+	var refs []string
+	for _, e := range entries {
+		if e.ExternalRef != "" {
+			refs = append(refs, e.ExternalRef)
+		}
+	}
+	return refs
+}
+
+func buildCreateSpotsSQL(b sq.StatementBuilderType, ids []string, entries []surf.SpotCreationEntry, now string) sq.InsertBuilder {
+	builder := b.
+		Insert("spots").
+		Columns(
+			"id", "name", "latitude", "longitude", "locality", "country_code", "external_ref",
+			"created_at", "updated_at",
+		)
+
+	for i, e := range entries {
+		builder = builder.Values(
+			ids[i],
+			e.Name,
+			e.Location.Coordinates.Latitude,
+			e.Location.Coordinates.Longitude,
+			e.Location.Locality,
+			e.Location.CountryCode,
+			nullString(e.ExternalRef),
+			now,
+			now,
+		)
+	}
+
+	return builder
+}
+
+// dedupeByExternalRef removes entries that share the same external reference
+// keeping only the last one of them at the position of the first one.
+func dedupeByExternalRef(entries []surf.SpotCreationEntry) []surf.SpotCreationEntry {
+	var (
+		deduped = make([]surf.SpotCreationEntry, 0, len(entries))
+		indices = make(map[string]int)
+	)
+
+	for _, e := range entries {
+		if e.ExternalRef == "" {
+			deduped = append(deduped, e)
+			continue
+		}
+
+		if i, ok := indices[e.ExternalRef]; ok {
+			deduped[i] = e
+			continue
+		}
+
+		indices[e.ExternalRef] = len(deduped)
+		deduped = append(deduped, e)
+	}
+
+	return deduped
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+// UpdateSpot updates an existing spot using the given entry and returns it if the
+// update succeeds. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted. A slug of the entry is suffixed when it is taken by another spot,
+// and an empty one clears the slug.
+func (ss *SpotStore) UpdateSpot(ctx context.Context, p surf.SpotUpdateEntry) (surf.Spot, error) {
+	if p.Name == nil && p.Latitude == nil && p.Longitude == nil &&
+		p.Locality == nil && p.CountryCode == nil && p.Region == nil && p.Slug == nil {
+		return surf.Spot{}, surf.ErrEmptySpotUpdateEntry
+	}
+
+	return ss.audited(ctx, surf.AuditActionUpdate, p.ID, func(q sqlx.ExtContext) (surf.Spot, error) {
+		values := map[string]interface{}{
+			"updated_at": formatTime(ss.now()),
+		}
+		if p.Name != nil {
+			values["name"] = *p.Name
+		}
+		if p.Latitude != nil {
+			values["latitude"] = *p.Latitude
+		}
+		if p.Longitude != nil {
+			values["longitude"] = *p.Longitude
+		}
+		if p.Locality != nil {
+			values["locality"] = *p.Locality
+		}
+		if p.CountryCode != nil {
+			values["country_code"] = *p.CountryCode
+		}
+		if p.Region != nil {
+			values["region"] = *p.Region
+		}
+		if p.Slug != nil {
+			values["slug"] = nil
+			if *p.Slug != "" {
+				unique, err := ss.uniqueSlug(ctx, q, *p.Slug, p.ID)
+				if err != nil {
+					return surf.Spot{}, err
+				}
+				values["slug"] = unique
+			}
+		}
+
+		query, args, err := ss.builder.
+			Update("spots").
+			SetMap(values).
+			Where(sq.Eq{"id": p.ID}).
+			Where(notDeleted).
+			Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+			ToSql()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		var s spot
+		if err := q.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return surf.Spot{}, surf.ErrSpotNotFound
+			}
+			return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		return toSpot(s), nil
+	})
+}
+
+// DeleteSpot soft-deletes a spot by the given ID, so that it can be restored later.
+// surf.ErrSpotNotFound is returned when spot is not found or already soft-deleted.
+func (ss *SpotStore) DeleteSpot(ctx context.Context, id string) error {
+	_, err := ss.audited(ctx, surf.AuditActionDelete, id, func(q sqlx.ExtContext) (surf.Spot, error) {
+		query, args, err := ss.builder.
+			Update("spots").
+			Set("deleted_at", formatTime(ss.now())).
+			Where(sq.Eq{"id": id}).
+			Where(notDeleted).
+			ToSql()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		res, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return surf.Spot{}, fmt.Errorf("failed to read affected rows: %w", err)
+		}
+
+		if count == 0 {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+
+		return surf.Spot{}, nil
+	})
+	return err
+}
+
+// DeleteSpots soft-deletes multiple spots by the given IDs in a single query and
+// returns IDs of the spots that got deleted. IDs of spots that are not found or
+// are already soft-deleted are skipped.
+func (ss *SpotStore) DeleteSpots(ctx context.Context, ids []string) ([]string, error) {
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("deleted_at", formatTime(ss.now())).
+		Where(sq.Eq{"id": ids}).
+		Where(notDeleted).
+		Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var deleted []string
+	if err := ss.inAuditTx(ctx, func(q sqlx.ExtContext) error {
+		spots, err := ss.querySpots(ctx, q, query, args...)
+		if err != nil {
+			return err
+		}
+
+		for i := range spots {
+			deleted = append(deleted, spots[i].ID)
+			if err := ss.audit(ctx, q, surf.AuditActionDelete, spots[i].ID, surf.SpotChanges(&spots[i], nil)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// RestoreSpot restores a soft-deleted spot by the given ID and returns it if the
+// restoration succeeds. surf.ErrSpotNotFound is returned when spot is not found
+// or not soft-deleted.
+func (ss *SpotStore) RestoreSpot(ctx context.Context, id string) (surf.Spot, error) {
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("deleted_at", nil).
+		// The external reference might have been taken by another spot while the
+		// spot was soft-deleted, in which case the restored spot loses it.
+		Set("external_ref", sq.Expr(restoredExternalRefSQL)).
+		// The same goes for the slug, unless slugs of soft-deleted spots are reserved.
+		Set("slug", sq.Expr(restoredSlugSQL)).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(spotColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var restored surf.Spot
+	if err := ss.inAuditTx(ctx, func(q sqlx.ExtContext) error {
+		var s spot
+		if err := q.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return surf.ErrSpotNotFound
+			}
+			return fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		restored = toSpot(s)
+		return ss.audit(ctx, q, surf.AuditActionRestore, restored.ID, surf.SpotChanges(nil, &restored))
+	}); err != nil {
+		return surf.Spot{}, err
+	}
+
+	return restored, nil
+}
+
+// SetSpotTags replaces tags of a spot by the given ID with the given tags within
+// a single transaction, and bumps updated_at of the spot. surf.ErrSpotNotFound is
+// returned when spot is not found or soft-deleted.
+func (ss *SpotStore) SetSpotTags(ctx context.Context, id string, tags []string) error {
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		var before []string
+		if _, ok := surf.AuditActorFromContext(ctx); ok {
+			s, err := ss.spot(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			before = s.Tags
+		}
+
+		if err := ss.setSpotTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+
+		return ss.audit(ctx, tx, surf.AuditActionUpdate, id, surf.TagChanges(before, tags))
+	})
+}
+
+func (ss *SpotStore) setSpotTags(ctx context.Context, tx *sqlx.Tx, id string, tags []string) error {
+	query, args, err := ss.builder.
+		Update("spots").
+		Set("updated_at", formatTime(ss.now())).
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	if count == 0 {
+		return surf.ErrSpotNotFound
+	}
+
+	query, args, err = ss.builder.
+		Delete("spot_tags").
+		Where(sq.Eq{"spot_id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	builder := ss.builder.
+		Insert("spot_tags").
+		Columns("spot_id", "tag")
+	for _, tag := range uniqueTags(tags) {
+		builder = builder.Values(id, tag)
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+var spotPhotoColumns = []string{"id", "spot_id", "content_type", "size", "created_at"}
+
+type spotPhoto struct {
+	ID          string    `db:"id"`
+	SpotID      string    `db:"spot_id"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// SpotPhotos returns photos of a spot by the given ID from the oldest one.
+// surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) SpotPhotos(ctx context.Context, spotID string) ([]surf.SpotPhoto, error) {
+	query, args, err := ss.builder.
+		Select("id").
+		From("spots").
+		Where(sq.Eq{"id": spotID}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var id string
+	if err := ss.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, surf.ErrSpotNotFound
+		}
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query, args, err = ss.builder.
+		Select(spotPhotoColumns...).
+		From("spot_photos").
+		Where(sq.Eq{"spot_id": id}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var photos []surf.SpotPhoto
+	defer rows.Close()
+	for rows.Next() {
+		var p spotPhoto
+		if err := rows.StructScan(&p); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		photos = append(photos, surf.SpotPhoto(p))
+	}
+
+	return photos, nil
+}
+
+// CreateSpotPhoto creates a new photo of a spot using the given entry and returns
+// it. surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) CreateSpotPhoto(ctx context.Context, e surf.SpotPhotoEntry) (surf.SpotPhoto, error) {
+	id, err := newUUID()
+	if err != nil {
+		return surf.SpotPhoto{}, err
+	}
+
+	query, args, err := ss.builder.
+		Insert("spot_photos").
+		Columns("id", "spot_id", "content_type", "size", "created_at").
+		Select(sq.
+			Select().
+			Column(sq.Expr("?", id)).
+			Column("id").
+			Column(sq.Expr("?", e.ContentType)).
+			Column(sq.Expr("?", e.Size)).
+			Column(sq.Expr("?", formatTime(ss.now()))).
+			From("spots").
+			Where(sq.Eq{"id": e.SpotID}).
+			Where(notDeleted),
+		).
+		Suffix("RETURNING " + strings.Join(spotPhotoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotPhoto{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var p spotPhoto
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotPhoto{}, surf.ErrSpotNotFound
+		}
+		return surf.SpotPhoto{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return surf.SpotPhoto(p), nil
+}
+
+// DeleteSpotPhoto deletes a photo by the given ID of a spot by the given ID and
+// returns the deleted photo. surf.ErrSpotPhotoNotFound is returned when photo is
+// not found, or when spot is not found or soft-deleted.
+func (ss *SpotStore) DeleteSpotPhoto(ctx context.Context, spotID, photoID string) (surf.SpotPhoto, error) {
+	query, args, err := ss.builder.
+		Delete("spot_photos").
+		Where(sq.Eq{"id": photoID}).
+		Where(sq.Expr("spot_id IN (SELECT id FROM spots WHERE id = ? AND deleted_at IS NULL)", spotID)).
+		Suffix("RETURNING " + strings.Join(spotPhotoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotPhoto{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var p spotPhoto
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotPhoto{}, surf.ErrSpotPhotoNotFound
+		}
+		return surf.SpotPhoto{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return surf.SpotPhoto(p), nil
+}
+
+// RateSpot records a rating of a spot using the given entry, replacing a rating of
+// the same spot by the same rater on the same day. surf.ErrSpotNotFound is returned
+// when spot is not found or soft-deleted.
+func (ss *SpotStore) RateSpot(ctx context.Context, e surf.SpotRatingEntry) error {
+	query, args, err := ss.builder.
+		Insert("spot_ratings").
+		Columns("spot_id", "score", "rater_key", "rated_on", "created_at").
+		Select(sq.
+			Select("id").
+			Column(sq.Expr("?", e.Score)).
+			Column(sq.Expr("?", e.RaterKey)).
+			Column(sq.Expr("?", e.RatedOn.UTC().Format("2006-01-02"))).
+			Column(sq.Expr("?", formatTime(ss.now()))).
+			From("spots").
+			Where(sq.Eq{"id": e.SpotID}).
+			Where(notDeleted),
+		).
+		Suffix("ON CONFLICT (spot_id, rater_key, rated_on) DO UPDATE SET score = excluded.score").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := ss.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return surf.ErrSpotNotFound
+	}
+
+	return nil
+}
+
+// FavoriteSpots returns spots that a user has favorited, from the most recently
+// favorited one. Soft-deleted spots are excluded.
+func (ss *SpotStore) FavoriteSpots(ctx context.Context, p surf.FavoriteSpotsParams) ([]surf.Spot, error) {
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Join("favorites ON favorites.spot_id = spots.id").
+		Where(sq.Eq{"favorites.user_id": p.UserID}).
+		Where(notDeleted).
+		OrderBy("favorites.favorited_at DESC", "spots.id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset)),
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return ss.querySpots(ctx, ss.db, query, args...)
+}
+
+// FavoritedSpotIDs reports which of spots by the given IDs a user by the given ID
+// has favorited.
+func (ss *SpotStore) FavoritedSpotIDs(ctx context.Context, userID string, spotIDs []string) (map[string]bool, error) {
+	favorited := make(map[string]bool)
+	if len(spotIDs) == 0 {
+		return favorited, nil
+	}
+
+	query, args, err := ss.builder.
+		Select("spot_id").
+		From("favorites").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"spot_id": spotIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var ids []string
+	if err := ss.db.SelectContext(ctx, &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	for _, id := range ids {
+		favorited[id] = true
+	}
+
+	return favorited, nil
+}
+
+// FavoriteSpot adds a spot by the given ID to favorites of a user by the given ID,
+// unless it is already there. surf.ErrSpotNotFound is returned when spot is not
+// found or soft-deleted.
+func (ss *SpotStore) FavoriteSpot(ctx context.Context, userID, spotID string) error {
+	// A conflicting favorite is updated in place rather than ignored, so that it
+	// still counts as a change and is told apart from a missing spot.
+	query, args, err := ss.builder.
+		Insert("favorites").
+		Columns("user_id", "spot_id", "favorited_at").
+		Select(sq.
+			Select().
+			Column(sq.Expr("?", userID)).
+			Column("id").
+			Column(sq.Expr("?", formatTime(ss.now()))).
+			From("spots").
+			Where(sq.Eq{"id": spotID}).
+			Where(notDeleted),
+		).
+		Suffix("ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := ss.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return surf.ErrSpotNotFound
+	}
+
+	return nil
+}
+
+// UnfavoriteSpot removes a spot by the given ID from favorites of a user by the
+// given ID, if it is there.
+func (ss *SpotStore) UnfavoriteSpot(ctx context.Context, userID, spotID string) error {
+	query, args, err := ss.builder.
+		Delete("favorites").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"spot_id": spotID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := ss.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyFavoriteBatch adds and removes spots of the given batch to and from
+// favorites of its user within a single transaction, and returns IDs of the spots
+// that got favorited. IDs of spots to favorite that are not found or soft-deleted
+// are skipped.
+func (ss *SpotStore) ApplyFavoriteBatch(ctx context.Context, b surf.FavoriteBatch) ([]string, error) {
+	favorited := make([]string, 0, len(b.Favorite))
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		if len(b.Favorite) > 0 {
+			query, args, err := ss.builder.
+				Insert("favorites").
+				Columns("user_id", "spot_id", "favorited_at").
+				Select(sq.
+					Select().
+					Column(sq.Expr("?", b.UserID)).
+					Column("id").
+					Column(sq.Expr("?", formatTime(ss.now()))).
+					From("spots").
+					Where(sq.Eq{"id": b.Favorite}).
+					Where(notDeleted),
+				).
+				Suffix("ON CONFLICT (user_id, spot_id) DO UPDATE SET favorited_at = favorites.favorited_at RETURNING spot_id").
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if err := tx.SelectContext(ctx, &favorited, query, args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", err)
+			}
+		}
+
+		if len(b.Unfavorite) > 0 {
+			query, args, err := ss.builder.
+				Delete("favorites").
+				Where(sq.Eq{"user_id": b.UserID}).
+				Where(sq.Eq{"spot_id": b.Unfavorite}).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return favorited, nil
+}
+
+var spotSuggestionColumns = []string{
+	"id",
+	"spot_id",
+	"status",
+	"name",
+	"latitude",
+	"longitude",
+	"locality",
+	"country_code",
+	"region",
+	"note",
+	"created_at",
+	"resolved_at",
+}
+
+type spotSuggestion struct {
+	ID          string       `db:"id"`
+	SpotID      string       `db:"spot_id"`
+	Status      string       `db:"status"`
+	Name        *string      `db:"name"`
+	Latitude    *float64     `db:"latitude"`
+	Longitude   *float64     `db:"longitude"`
+	Locality    *string      `db:"locality"`
+	CountryCode *string      `db:"country_code"`
+	Region      *string      `db:"region"`
+	Note        string       `db:"note"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ResolvedAt  sql.NullTime `db:"resolved_at"`
+}
+
+func toSpotSuggestion(s spotSuggestion) surf.SpotSuggestion {
+	var resolvedAt *time.Time
+	if s.ResolvedAt.Valid {
+		resolvedAt = &s.ResolvedAt.Time
+	}
+
+	return surf.SpotSuggestion{
+		ID:     s.ID,
+		Status: surf.SpotSuggestionStatus(s.Status),
+		Change: surf.SpotUpdateEntry{
+			ID:          s.SpotID,
+			Name:        s.Name,
+			Latitude:    s.Latitude,
+			Longitude:   s.Longitude,
+			Locality:    s.Locality,
+			CountryCode: s.CountryCode,
+			Region:      s.Region,
+		},
+		Note:       s.Note,
+		CreatedAt:  s.CreatedAt,
+		ResolvedAt: resolvedAt,
+	}
+}
+
+// SpotSuggestion returns a spot suggestion by the given ID.
+// surf.ErrSpotSuggestionNotFound is returned when suggestion is not found.
+func (ss *SpotStore) SpotSuggestion(ctx context.Context, id string) (surf.SpotSuggestion, error) {
+	query, args, err := ss.builder.
+		Select(spotSuggestionColumns...).
+		From("spot_suggestions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, surf.ErrSpotSuggestionNotFound
+		}
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// SpotSuggestions returns spot suggestions that match the given parameters, from
+// the most recently created one.
+func (ss *SpotStore) SpotSuggestions(ctx context.Context, p surf.SpotSuggestionsParams) ([]surf.SpotSuggestion, error) {
+	builder := ss.builder.
+		Select(spotSuggestionColumns...).
+		From("spot_suggestions").
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if p.Status != "" {
+		builder = builder.Where(sq.Eq{"status": string(p.Status)})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var rows []spotSuggestion
+	if err := ss.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var suggestions []surf.SpotSuggestion
+	for _, s := range rows {
+		suggestions = append(suggestions, toSpotSuggestion(s))
+	}
+
+	return suggestions, nil
+}
+
+// CreateSpotSuggestion creates a new pending spot suggestion using the given entry
+// and returns it. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted.
+func (ss *SpotStore) CreateSpotSuggestion(ctx context.Context, e surf.SpotSuggestionEntry) (surf.SpotSuggestion, error) {
+	id, err := newUUID()
+	if err != nil {
+		return surf.SpotSuggestion{}, err
+	}
+
+	query, args, err := ss.builder.
+		Insert("spot_suggestions").
+		Columns(
+			"id", "spot_id", "status", "name", "latitude", "longitude", "locality", "country_code", "region",
+			"note", "created_at",
+		).
+		Select(sq.
+			Select().
+			Column(sq.Expr("?", id)).
+			Column("id").
+			Column(sq.Expr("?", string(surf.SpotSuggestionStatusPending))).
+			Column(sq.Expr("?", e.Change.Name)).
+			Column(sq.Expr("?", e.Change.Latitude)).
+			Column(sq.Expr("?", e.Change.Longitude)).
+			Column(sq.Expr("?", e.Change.Locality)).
+			Column(sq.Expr("?", e.Change.CountryCode)).
+			Column(sq.Expr("?", e.Change.Region)).
+			Column(sq.Expr("?", e.Note)).
+			Column(sq.Expr("?", formatTime(ss.now()))).
+			From("spots").
+			Where(sq.Eq{"id": e.Change.ID}).
+			Where(notDeleted),
+		).
+		Suffix("RETURNING " + strings.Join(spotSuggestionColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, surf.ErrSpotNotFound
+		}
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// ResolveSpotSuggestion moves a pending spot suggestion by the given ID to the
+// given status and returns it. surf.ErrSpotSuggestionNotFound is returned when
+// suggestion is not found, and surf.ErrSpotSuggestionResolved when it is no
+// longer pending.
+func (ss *SpotStore) ResolveSpotSuggestion(
+	ctx context.Context,
+	id string,
+	status surf.SpotSuggestionStatus) (surf.SpotSuggestion, error) {
+
+	query, args, err := ss.builder.
+		Update("spot_suggestions").
+		Set("status", string(status)).
+		Set("resolved_at", formatTime(ss.now())).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"status": string(surf.SpotSuggestionStatusPending)}).
+		Suffix("RETURNING " + strings.Join(spotSuggestionColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return surf.SpotSuggestion{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spotSuggestion
+	if err := ss.db.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return surf.SpotSuggestion{}, fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		// The suggestion is looked up once again in order to tell a missing
+		// suggestion apart from a resolved one.
+		if _, err := ss.SpotSuggestion(ctx, id); err != nil {
+			return surf.SpotSuggestion{}, err
+		}
+		return surf.SpotSuggestion{}, surf.ErrSpotSuggestionResolved
+	}
+
+	return toSpotSuggestion(s), nil
+}
+
+// audited runs the given mutation of a spot by the given ID, which is empty for
+// creations, and returns the spot that the mutation returns. Like in the PostgreSQL
+// store, the mutation runs within a transaction along with an insertion of an audit
+// entry when the context holds an audit actor, and on the database as is otherwise.
+func (ss *SpotStore) audited(
+	ctx context.Context,
+	action surf.AuditAction,
+	spotID string,
+	mutate func(sqlx.ExtContext) (surf.Spot, error)) (surf.Spot, error) {
+
+	actorID, ok := surf.AuditActorFromContext(ctx)
+	if !ok {
+		return mutate(ss.db)
+	}
+
+	var spot surf.Spot
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		// SQLite locks the whole database for writes, so the spot does not need to
+		// be locked on its own.
+		var before *surf.Spot
+		if action != surf.AuditActionCreate {
+			s, err := ss.spot(ctx, tx, spotID)
+			if err != nil {
+				return err
+			}
+			before = &s
+		}
+
+		var err error
+		if spot, err = mutate(tx); err != nil {
+			return err
+		}
+
+		after := &spot
+		switch action {
+		case surf.AuditActionCreate:
+			spotID = spot.ID
+		case surf.AuditActionDelete:
+			after = nil
+		}
+
+		return ss.insertAuditEntry(ctx, tx, surf.AuditEntry{
+			ActorID: actorID,
+			Action:  action,
+			SpotID:  spotID,
+			Changes: surf.SpotChanges(before, after),
+		})
+	}); err != nil {
+		return surf.Spot{}, err
+	}
+
+	return spot, nil
+}
+
+// spot reads a spot by the given ID within the given transaction.
+// surf.ErrSpotNotFound is returned when spot is not found or soft-deleted.
+func (ss *SpotStore) spot(ctx context.Context, tx *sqlx.Tx, id string) (surf.Spot, error) {
+	query, args, err := ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := tx.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpot(s), nil
+}
+
+// inAuditTx calls the given function within a transaction when the context holds
+// an audit actor, so that audit entries get recorded along with the mutations
+// made by the function. Otherwise, the function is called on the database as is.
+func (ss *SpotStore) inAuditTx(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if _, ok := surf.AuditActorFromContext(ctx); !ok {
+		return fn(ss.db)
+	}
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		return fn(tx)
+	})
+}
+
+// audit records an entry with the given changes of a spot by the given ID when the
+// context holds an audit actor.
+func (ss *SpotStore) audit(
+	ctx context.Context,
+	q sqlx.ExecerContext,
+	action surf.AuditAction,
+	spotID string,
+	changes []surf.AuditChange) error {
+
+	actorID, ok := surf.AuditActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return ss.insertAuditEntry(ctx, q, surf.AuditEntry{
+		ActorID: actorID,
+		Action:  action,
+		SpotID:  spotID,
+		Changes: changes,
+	})
+}
+
+func (ss *SpotStore) insertAuditEntry(ctx context.Context, q sqlx.ExecerContext, e surf.AuditEntry) error {
+	id, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	changes, err := json.Marshal(toAuditChanges(e.Changes))
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	query, args, err := ss.builder.
+		Insert("audit_log").
+		Columns("id", "actor_id", "action", "spot_id", "changes", "created_at").
+		Values(id, e.ActorID, string(e.Action), e.SpotID, string(changes), formatTime(ss.now())).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := q.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// AuditEntries returns audit entries that match the given parameters, from the
+// most recently created one.
+func (ss *SpotStore) AuditEntries(ctx context.Context, p surf.AuditEntriesParams) ([]surf.AuditEntry, error) {
+	builder := ss.builder.
+		Select(auditEntryColumns...).
+		From("audit_log").
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(p.Limit)).
+		Offset(uint64(p.Offset))
+
+	if p.SpotID != "" {
+		builder = builder.Where(sq.Eq{"spot_id": p.SpotID})
+	}
+	if p.ActorID != "" {
+		builder = builder.Where(sq.Eq{"actor_id": p.ActorID})
+	}
+	if !p.From.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": formatTime(p.From)})
+	}
+	if !p.To.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": formatTime(p.To)})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var rows []auditEntry
+	if err := ss.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var entries []surf.AuditEntry
+	for _, e := range rows {
+		entry, err := toAuditEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+var auditEntryColumns = []string{"id", "actor_id", "action", "spot_id", "changes", "created_at"}
+
+type auditEntry struct {
+	ID        string    `db:"id"`
+	ActorID   string    `db:"actor_id"`
+	Action    string    `db:"action"`
+	SpotID    string    `db:"spot_id"`
+	Changes   string    `db:"changes"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// auditChange is an element of the JSON array that changes of an audit entry are
+// kept in.
+type auditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func toAuditChanges(changes []surf.AuditChange) []auditChange {
+	cc := make([]auditChange, len(changes))
+	for i, c := range changes {
+		cc[i] = auditChange(c)
+	}
+	return cc
+}
+
+func toAuditEntry(e auditEntry) (surf.AuditEntry, error) {
+	var cc []auditChange
+	if err := json.Unmarshal([]byte(e.Changes), &cc); err != nil {
+		return surf.AuditEntry{}, fmt.Errorf("failed to decode changes: %w", err)
+	}
+
+	var changes []surf.AuditChange
+	for _, c := range cc {
+		changes = append(changes, surf.AuditChange(c))
+	}
+
+	return surf.AuditEntry{
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		Action:    surf.AuditAction(e.Action),
+		SpotID:    e.SpotID,
+		Changes:   changes,
+		CreatedAt: e.CreatedAt,
+	}, nil
+}
+
+// CreateSpotIdempotently creates a new spot using the given entry and saves the
+// given idempotency key along with it at the given time within a single
+// transaction, unless a spot has already been created with the key not earlier
+// than the given since time. In that case, nothing is created, and that spot is
+// returned along with false. surf.ErrSpotNotFound is returned when the spot has
+// been soft-deleted since then. SQLite serializes the transactions, so the key
+// cannot be claimed by another creation in the meantime.
+func (ss *SpotStore) CreateSpotIdempotently(
+	ctx context.Context,
+	key string,
+	e surf.SpotCreationEntry,
+	since, now time.Time) (surf.Spot, bool, error) {
+
+	var (
+		spot    surf.Spot
+		created bool
+	)
+	if err := ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		spotID, ok, err := ss.idempotentSpotID(ctx, tx, key, since)
+		if err != nil {
+			return err
+		}
+		if ok {
+			spot, err = ss.spotWithRatings(ctx, tx, spotID)
+			return err
+		}
+
+		if spot, err = ss.insertSpot(ctx, tx, e); err != nil {
+			return err
+		}
+
+		if err := ss.audit(ctx, tx, surf.AuditActionCreate, spot.ID, surf.SpotChanges(nil, &spot)); err != nil {
+			return err
+		}
+
+		if err := ss.claimIdempotencyKey(ctx, tx, key, spot.ID, since, now); err != nil {
+			return err
+		}
+
+		created = true
+		return nil
+	}); err != nil {
+		return surf.Spot{}, false, err
+	}
+
+	return spot, created, nil
+}
+
+// spotWithRatings reads a spot by the given ID along with its ratings within the
+// given transaction. surf.ErrSpotNotFound is returned when spot is not found or
+// soft-deleted.
+func (ss *SpotStore) spotWithRatings(ctx context.Context, tx *sqlx.Tx, id string) (surf.Spot, error) {
+	query, args, err := withRatingsSQL(ss.builder.
+		Select(spotColumns...).
+		From("spots").
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted),
+	).ToSql()
+	if err != nil {
+		return surf.Spot{}, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s spot
+	if err := tx.QueryRowxContext(ctx, query, args...).StructScan(&s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return surf.Spot{}, surf.ErrSpotNotFound
+		}
+		return surf.Spot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return toSpot(s), nil
+}
+
+// idempotentSpotID returns an ID of a spot that was created with the given
+// idempotency key not earlier than the given time, and whether there is such a key.
+func (ss *SpotStore) idempotentSpotID(ctx context.Context, q sqlx.QueryerContext, key string, since time.Time) (string, bool, error) {
+	query, args, err := ss.builder.
+		Select("spot_id").
+		From("idempotency_keys").
+		Where(sq.Eq{"key": key}).
+		Where(sq.GtOrEq{"created_at": formatTime(since)}).
+		ToSql()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var spotID string
+	if err := q.QueryRowxContext(ctx, query, args...).Scan(&spotID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return spotID, true, nil
+}
+
+// claimIdempotencyKey saves the given idempotency key along with an ID of a spot
+// that is being created with it. Like in the PostgreSQL store, only a key saved
+// earlier than the given since time gets replaced.
+func (ss *SpotStore) claimIdempotencyKey(ctx context.Context, tx *sqlx.Tx, key, spotID string, since, now time.Time) error {
+	query, args, err := ss.builder.
+		Insert("idempotency_keys").
+		Columns("key", "spot_id", "created_at").
+		Values(key, spotID, formatTime(now)).
+		Suffix(
+			"ON CONFLICT (key) DO UPDATE SET spot_id = excluded.spot_id, created_at = excluded.created_at "+
+				"WHERE idempotency_keys.created_at < ?",
+			formatTime(since),
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+
+	if count == 0 {
+		return errors.New("idempotency key taken")
+	}
+
+	return nil
+}
+
+// ReferencePoints returns points of the given reference set ordered by their names.
+func (ss *SpotStore) ReferencePoints(ctx context.Context, set string) ([]surf.ReferencePoint, error) {
+	query, args, err := ss.builder.
+		Select("name", "latitude", "longitude").
+		From("reference_points").
+		Where(sq.Eq{"reference_set": set}).
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := ss.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var points []surf.ReferencePoint
+	defer rows.Close()
+	for rows.Next() {
+		var p referencePoint
+		if err := rows.StructScan(&p); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		points = append(points, toReferencePoint(p))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// ReplaceReferencePoints replaces points of the given reference set with the given
+// ones within a single transaction.
+func (ss *SpotStore) ReplaceReferencePoints(ctx context.Context, set string, points []surf.ReferencePoint) error {
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := ss.deleteReferenceSet(ctx, tx, "reference_points", set); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(points); start += ss.batchSize {
+			end := start + ss.batchSize
+			if end > len(points) {
+				end = len(points)
+			}
+
+			builder := ss.builder.
+				Insert("reference_points").
+				Columns("reference_set", "name", "latitude", "longitude")
+			for _, p := range points[start:end] {
+				builder = builder.Values(set, p.Name, p.Coordinates.Latitude, p.Coordinates.Longitude)
+			}
+
+			query, args, err := builder.ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceSpotReferenceDistances replaces distances of spots to the given reference
+// set with the given ones within a single transaction. Distances are inserted in
+// batches, and the ones of spots that are not found are skipped.
+func (ss *SpotStore) ReplaceSpotReferenceDistances(
+	ctx context.Context,
+	set string,
+	distances []surf.SpotReferenceDistance) error {
+
+	return ss.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := ss.deleteReferenceSet(ctx, tx, "spot_reference_distances", set); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(distances); start += ss.batchSize {
+			end := start + ss.batchSize
+			if end > len(distances) {
+				end = len(distances)
+			}
+			batch := distances[start:end]
+
+			ids := make([]string, len(batch))
+			for i, d := range batch {
+				ids[i] = d.SpotID
+			}
+
+			// Distances of spots that are not found would otherwise violate the
+			// foreign key.
+			existing, err := ss.existingSpotIDs(ctx, tx, ids)
+			if err != nil {
+				return err
+			}
+			if len(existing) == 0 {
+				continue
+			}
+
+			builder := ss.builder.
+				Insert("spot_reference_distances").
+				Columns("spot_id", "reference_set", "reference_name", "distance_km")
+			for _, d := range batch {
+				if existing[d.SpotID] {
+					builder = builder.Values(d.SpotID, set, d.PointName, d.DistanceKm)
+				}
+			}
+
+			query, args, err := builder.ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build query: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to execute query: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (ss *SpotStore) deleteReferenceSet(ctx context.Context, tx *sqlx.Tx, table, set string) error {
+	query, args, err := ss.builder.
+		Delete(table).
+		Where(sq.Eq{"reference_set": set}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func (ss *SpotStore) existingSpotIDs(ctx context.Context, tx *sqlx.Tx, ids []string) (map[string]bool, error) {
+	query, args, err := ss.builder.
+		Select("id").
+		From("spots").
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var found []string
+	if err := tx.SelectContext(ctx, &found, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	existing := make(map[string]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+
+	return existing, nil
+}
+
+// inTx calls the given function within a transaction that is committed once the
+// function succeeds and rolled back otherwise.
+func (ss *SpotStore) inTx(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	tx, err := ss.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+type referencePoint struct {
+	Name      string  `db:"name"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
+
+func toReferencePoint(p referencePoint) surf.ReferencePoint {
+	return surf.ReferencePoint{
+		Name: p.Name,
+		Coordinates: geo.Coordinates{
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+		},
+	}
+}
+
+// spotColumns holds columns that are read into spot.
+var spotColumns = []string{
+	"id", "name", "latitude", "longitude", "locality", "country_code", "region", "slug", "created_at", "updated_at",
+	spotTagsSQL + " AS tags",
+}
+
+// spotTagsSQL aggregates tags of a spot into a single string within the same query
+// that reads the spot, so that reading multiple spots does not take a query per
+// spot.
+const spotTagsSQL = "(SELECT group_concat(tag, char(31)) FROM spot_tags WHERE spot_tags.spot_id = spots.id)"
+
+// restoredExternalRefSQL keeps an external reference of a spot being restored
+// unless another spot that is not soft-deleted has the same one.
+const restoredExternalRefSQL = "CASE WHEN EXISTS (" +
+	"SELECT 1 FROM spots AS taken WHERE taken.external_ref = spots.external_ref AND taken.deleted_at IS NULL" +
+	") THEN NULL ELSE external_ref END"
+
+// restoredSlugSQL keeps a slug of a spot being restored unless another spot that
+// is not soft-deleted has the same one, ignoring case.
+const restoredSlugSQL = "CASE WHEN EXISTS (" +
+	"SELECT 1 FROM spots AS taken WHERE lower(taken.slug) = lower(spots.slug) AND taken.deleted_at IS NULL" +
+	") THEN NULL ELSE slug END"
+
+// notDeleted is a condition that excludes soft-deleted spots.
+var notDeleted = sq.Eq{"deleted_at": nil}
+
+type spot struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Latitude    float64        `db:"latitude"`
+	Longitude   float64        `db:"longitude"`
+	Locality    string         `db:"locality"`
+	CountryCode string         `db:"country_code"`
+	Region      string         `db:"region"`
+	Slug        sql.NullString `db:"slug"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	Tags        sql.NullString `db:"tags"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+	ExternalRef sql.NullString `db:"external_ref"`
+
+	ReferenceName sql.NullString  `db:"reference_name"`
+	DistanceKm    sql.NullFloat64 `db:"distance_km"`
+
+	AverageRating float64 `db:"average_rating"`
+	RatingsCount  int     `db:"ratings_count"`
+}
+
+func toSpot(s spot) surf.Spot {
+	var deletedAt *time.Time
+	if s.DeletedAt.Valid {
+		deletedAt = &s.DeletedAt.Time
+	}
+
+	// group_concat does not guarantee any order, so tags are sorted here to match
+	// the PostgreSQL store.
+	tags := []string{}
+	if s.Tags.String != "" {
+		tags = strings.Split(s.Tags.String, tagsSeparator)
+		sort.Strings(tags)
+	}
+
+	var nearestReference *surf.ReferenceDistance
+	if s.ReferenceName.Valid {
+		nearestReference = &surf.ReferenceDistance{
+			PointName:  s.ReferenceName.String,
+			DistanceKm: s.DistanceKm.Float64,
+		}
+	}
+
+	return surf.Spot{
+		ID:               s.ID,
+		Name:             s.Name,
+		Slug:             s.Slug.String,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+		DeletedAt:        deletedAt,
+		Tags:             tags,
+		ExternalRef:      s.ExternalRef.String,
+		NearestReference: nearestReference,
+		Ratings: surf.SpotRatings{
+			Average: s.AverageRating,
+			Count:   s.RatingsCount,
+		},
+		Location: geo.Location{
+			Locality:    s.Locality,
+			CountryCode: s.CountryCode,
+			Region:      s.Region,
+			Coordinates: geo.Coordinates{
+				Latitude:  s.Latitude,
+				Longitude: s.Longitude,
+			},
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// uniqueTags returns the given tags without duplicates, which would otherwise
+// violate the primary key of spot_tags.
+func uniqueTags(tags []string) []string {
+	var (
+		unique = make([]string, 0, len(tags))
+		seen   = make(map[string]bool, len(tags))
+	)
+	for _, t := range tags {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+func newUUIDs(n int) ([]string, error) {
+	ids := make([]string, n)
+	for i := range ids {
+		id, err := newUUID()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// newUUID returns a random version 4 UUID, which is what PostgreSQL's
+// gen_random_uuid generates.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf/surftest"
+)
+
+func newTestSpotStore(t *testing.T, opts ...SpotStoreOption) *SpotStore {
+	db, err := Open(filepath.Join(t.TempDir(), "tolqin.db"))
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewSpotStore(db, opts...)
+}
+
+func TestSpotStore_Conformance(t *testing.T) {
+	surftest.TestSpotStore(t, func(t *testing.T) surftest.SpotStore {
+		return newTestSpotStore(t)
+	})
+}
+
+func TestSpotStore_ReservedSlugs(t *testing.T) {
+	surftest.TestReservedSlugs(t, newTestSpotStore(t, WithDeletedSlugsReserved()))
+}
+
+func TestSpotStore_CreateSpot_ConcurrentSlugs(t *testing.T) {
+	ss := newTestSpotStore(t)
+
+	entry := surf.SpotCreationEntry{
+		Name: "Uluwatu",
+		Slug: "uluwatu-bali",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+		},
+	}
+
+	const n = 3
+
+	var wg sync.WaitGroup
+	slugs := make(chan string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			spot, err := ss.CreateSpot(context.Background(), entry)
+			if assert.NoError(t, err) {
+				slugs <- spot.Slug
+			}
+		}()
+	}
+	wg.Wait()
+	close(slugs)
+
+	var created []string
+	for s := range slugs {
+		created = append(created, s)
+	}
+	assert.ElementsMatch(t, []string{"uluwatu-bali", "uluwatu-bali-2", "uluwatu-bali-3"}, created)
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tolqin.db")
+
+	db, err := Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	created, err := NewSpotStore(db).CreateSpot(context.Background(), surf.SpotCreationEntry{Name: "Spot"})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	// Bootstrapping the schema of an existing file must keep its data.
+	db, err = Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	s, err := NewSpotStore(db).Spot(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created, s)
+}
+
+func TestSpotStore_CreateSpots_Batches(t *testing.T) {
+	ss := newTestSpotStore(t, WithBatchSize(2))
+
+	entries := []surf.SpotCreationEntry{
+		{Name: "Spot 1", ExternalRef: "ref-1", Tags: []string{"reef"}},
+		{Name: "Spot 2", ExternalRef: "ref-2"},
+		{Name: "Spot 3", ExternalRef: "ref-3", Tags: []string{"point", "point"}},
+	}
+
+	stats, err := ss.CreateSpots(context.Background(), entries, surf.SpotCreationModeInsert)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, surf.SpotCreationStats{Inserted: 3}, stats)
+
+	entries[1].Location = geo.Location{Locality: "Locality 2"}
+	stats, err = ss.CreateSpots(context.Background(), append(entries, surf.SpotCreationEntry{
+		Name:        "Spot 4",
+		ExternalRef: "ref-4",
+	}), surf.SpotCreationModeUpsert)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, surf.SpotCreationStats{Inserted: 1, Updated: 3}, stats)
+
+	spots, err := ss.Spots(context.Background(), surf.SpotsParams{Limit: 10})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, spots, 4) {
+		return
+	}
+
+	byName := make(map[string]surf.Spot)
+	for _, s := range spots {
+		byName[s.Name] = s
+	}
+	assert.Equal(t, "Locality 2", byName["Spot 2"].Location.Locality)
+	assert.Equal(t, []string{"point"}, byName["Spot 3"].Tags)
+}
+
+func TestSpotStore_SpotCreationCounts(t *testing.T) {
+	times := []time.Time{
+		time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),  // Monday
+		time.Date(2021, 3, 7, 23, 0, 0, 0, time.UTC),  // Sunday
+		time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC),   // Monday
+		time.Date(2021, 4, 30, 12, 0, 0, 0, time.UTC), // Friday
+	}
+
+	ss := newTestSpotStore(t)
+	for _, tm := range times {
+		now := tm
+		ss.now = func() time.Time { return now }
+		_, err := ss.CreateSpot(context.Background(), surf.SpotCreationEntry{Name: "Spot"})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	tests := []struct {
+		name           string
+		interval       surf.TimeInterval
+		expectedCounts []surf.SpotCreationCount
+	}{
+		{
+			name:     "aggregate by months",
+			interval: surf.TimeIntervalMonth,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 3},
+			},
+		},
+		{
+			name:     "aggregate by weeks starting on mondays",
+			interval: surf.TimeIntervalWeek,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+				{BucketStart: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), Count: 1},
+			},
+		},
+		{
+			name:     "aggregate by days",
+			interval: surf.TimeIntervalDay,
+			expectedCounts: []surf.SpotCreationCount{
+				{BucketStart: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), Count: 1},
+				{BucketStart: time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC), Count: 1},
+				{BucketStart: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), Count: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The upper bound is exclusive, so the last spot is left out.
+			counts, err := ss.SpotCreationCounts(context.Background(), surf.SpotCreationCountsParams{
+				From:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2021, 4, 30, 12, 0, 0, 0, time.UTC),
+				Interval: test.interval,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCounts, counts)
+		})
+	}
+}
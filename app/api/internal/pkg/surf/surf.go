@@ -1,7 +1,12 @@
 package surf
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
@@ -14,24 +19,113 @@ var (
 	// ErrEmptySpotUpdateEntry is used when a spot update entry does not contain
 	// any fields.
 	ErrEmptySpotUpdateEntry = errors.New("empty spot update entry")
+
+	// ErrSpotPhotoNotFound is used when a photo of a spot is not found.
+	ErrSpotPhotoNotFound = errors.New("spot photo not found")
+
+	// ErrSpotSuggestionNotFound is used when a spot suggestion is not found.
+	ErrSpotSuggestionNotFound = errors.New("spot suggestion not found")
+
+	// ErrSpotSuggestionResolved is used when a spot suggestion that is no longer
+	// pending is requested to be resolved.
+	ErrSpotSuggestionResolved = errors.New("spot suggestion resolved")
+
+	// ErrDuplicateSpot is used when a spot conflicts with an existing one, e.g. by
+	// its external reference or slug.
+	ErrDuplicateSpot = errors.New("duplicate spot")
+
+	// ErrInvalidReference is used when an entry references a spot or another
+	// entity that does not exist.
+	ErrInvalidReference = errors.New("invalid reference")
 )
 
+// StoreError is used when a store fails due to its underlying database, so that
+// the failure can be told apart from others without depending on the database
+// driver. errors.Is reports whether it is of the given kind, such as
+// ErrDuplicateSpot, and errors.As can still reach the error of the driver.
+type StoreError struct {
+	// Kind is either ErrDuplicateSpot, ErrInvalidReference, or nil for failures
+	// that are not classified.
+	Kind error
+
+	// Code is a code of the failure reported by the database, such as SQLSTATE.
+	Code string
+
+	// Err is the error of the database driver.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *StoreError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the database driver.
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the given kind.
+func (e *StoreError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// LogFields returns the code of the failure, so that it gets logged along with the
+// error.
+func (e *StoreError) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"sqlstate": e.Code,
+	}
+}
+
 // Spot represents a surfing spot.
 type Spot struct {
 	ID        string
 	Name      string
 	CreatedAt time.Time
+	UpdatedAt time.Time
 	Location  geo.Location
+
+	// Slug is a human-friendly identifier of the spot that can be used in URLs.
+	// It is empty for spots that have not got one generated.
+	Slug string
+
+	// Tags categorize the spot, e.g. by its break type or skill level.
+	Tags []string
+
+	// DeletedAt is set only for soft-deleted spots.
+	DeletedAt *time.Time
+
+	// ExternalRef is an optional key that references the spot in an external
+	// source. It is populated only when spots are iterated for an export.
+	ExternalRef string
+
+	// NearestReference is a distance from the spot to the nearest point of a
+	// reference set. It is populated only when spots are read along with a
+	// reference set, and only if the distance has been backfilled.
+	NearestReference *ReferenceDistance
+
+	// Ratings aggregates scores that surfers gave to the spot. It is populated
+	// only when spots are read using SpotReader.
+	Ratings SpotRatings
+}
+
+// SpotRatings holds aggregated ratings of a spot.
+type SpotRatings struct {
+	// Average is an average score of the ratings. It is zero when the spot has
+	// no ratings.
+	Average float64
+	Count   int
 }
 
 // SpotReader is a data storage from which spots can be read.
 type SpotReader interface {
 	// Spot returns a spot by the given ID. ErrSpotNotFound is returned when spot
 	// is not found.
-	Spot(id string) (Spot, error)
+	Spot(ctx context.Context, id string) (Spot, error)
 
 	// Spots returns multiple spots that match the given parameters.
-	Spots(SpotsParams) ([]Spot, error)
+	Spots(context.Context, SpotsParams) ([]Spot, error)
 }
 
 // SpotsParams holds parameters for reading multiple spots from a data storage.
@@ -41,8 +135,54 @@ type SpotsParams struct {
 	CountryCode string
 	SearchQuery SpotSearchQuery
 	Bounds      *geo.Bounds
+
+	// IncludeDeleted can be optionally used to additionally read soft-deleted
+	// spots.
+	IncludeDeleted bool
+
+	// Order can be optionally used to read spots in a certain order.
+	Order SpotOrder
+
+	// Tags can be optionally used to read only spots that have the given tags.
+	// TagsMatch defines whether spots must have all or any of them.
+	Tags      []string
+	TagsMatch SpotTagsMatch
+
+	// ReferenceSet can be optionally used to read spots along with their
+	// distances to the nearest point of the given reference set.
+	ReferenceSet string
 }
 
+// SpotTagsMatch defines how tags of spots are matched against the requested ones.
+type SpotTagsMatch int
+
+const (
+	// SpotTagsMatchAll is used for reading spots that have all of the requested
+	// tags.
+	SpotTagsMatchAll SpotTagsMatch = iota
+
+	// SpotTagsMatchAny is used for reading spots that have at least one of the
+	// requested tags.
+	SpotTagsMatchAny
+)
+
+// SpotOrder defines an order in which multiple spots are read from a data storage.
+type SpotOrder int
+
+const (
+	// SpotOrderNone is used when the order of spots does not matter.
+	SpotOrderNone SpotOrder = iota
+
+	// SpotOrderUpdatedAtDesc is used for reading the most recently updated spots
+	// first.
+	SpotOrderUpdatedAtDesc
+
+	// SpotOrderReferenceDistance is used for reading spots that are the closest
+	// to a reference set first. Spots without a distance to the reference set
+	// are read last. It requires SpotsParams.ReferenceSet to be set.
+	SpotOrderReferenceDistance
+)
+
 // SpotSearchQuery holds a string query for searching for spots. By default, the
 // query is compared against names and localities of spots.
 type SpotSearchQuery struct {
@@ -51,6 +191,263 @@ type SpotSearchQuery struct {
 	// WithSpotID can be optionally used to additionally compare the query against
 	// spot IDs.
 	WithSpotID bool
+
+	// FullText can be optionally used to match words of the query against words
+	// of names and localities, regardless of their order, instead of looking for
+	// the query as a substring. Spots are then ordered by relevance unless a
+	// different order is requested.
+	FullText bool
+}
+
+// SpotSlugReader is a data storage from which spots can be read by their slugs.
+type SpotSlugReader interface {
+	// SpotBySlug returns a spot by the given slug. ErrSpotNotFound is returned
+	// when spot is not found.
+	SpotBySlug(ctx context.Context, slug string) (Spot, error)
+}
+
+// RandomSpotReader is a data storage containing spots from which random spots
+// can be read.
+type RandomSpotReader interface {
+	// RandomSpots returns multiple randomly picked spots that match the given
+	// parameters.
+	RandomSpots(context.Context, RandomSpotsParams) ([]Spot, error)
+}
+
+// RandomSpotsParams holds parameters for reading multiple random spots from a
+// data storage.
+type RandomSpotsParams struct {
+	Limit       int
+	CountryCode string
+}
+
+// SpotCentroidReader is a data storage containing spots from which a centroid of
+// multiple spots can be read.
+type SpotCentroidReader interface {
+	// SpotsCentroid returns the average coordinates of spots that match the given
+	// parameters. Nil is returned when no spots match.
+	SpotsCentroid(context.Context, SpotsCentroidParams) (*geo.Coordinates, error)
+}
+
+// SpotsCentroidParams holds parameters for reading a centroid of multiple spots
+// from a data storage.
+type SpotsCentroidParams struct {
+	CountryCode string
+	SearchQuery SpotSearchQuery
+	Bounds      *geo.Bounds
+}
+
+// NearbySpot is a spot along with its distance from a certain point.
+type NearbySpot struct {
+	Spot
+	DistanceKm float64
+}
+
+// NearbySpotReader is a data storage containing spots from which spots located
+// near a certain point can be read.
+type NearbySpotReader interface {
+	// NearbySpots returns multiple spots located within the given radius from the
+	// given point, ordered by their distance from the point.
+	NearbySpots(context.Context, NearbySpotsParams) ([]NearbySpot, error)
+}
+
+// NearbySpotsParams holds parameters for reading multiple nearby spots from a data
+// storage.
+type NearbySpotsParams struct {
+	Coordinates geo.Coordinates
+	RadiusKm    float64
+	Limit       int
+}
+
+// NearbySpotsOf reads up to the given number of spots located within the given
+// radius from the given spot using the given reader, excluding the spot itself.
+func NearbySpotsOf(ctx context.Context, r NearbySpotReader, s Spot, radiusKm float64, limit int) ([]NearbySpot, error) {
+	// One more spot is read since the spot itself is expected to be the nearest one.
+	spots, err := r.NearbySpots(ctx, NearbySpotsParams{
+		Coordinates: s.Location.Coordinates,
+		RadiusKm:    radiusKm,
+		Limit:       limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nearby := make([]NearbySpot, 0, len(spots))
+	for _, n := range spots {
+		if n.ID == s.ID {
+			continue
+		}
+		nearby = append(nearby, n)
+	}
+
+	if len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// CountrySpotCount holds a number of spots located in a country.
+type CountrySpotCount struct {
+	CountryCode string
+	Count       int
+}
+
+// SpotCountryReader is a data storage containing spots from which countries of
+// spots can be read.
+type SpotCountryReader interface {
+	// CountriesWithSpotCounts returns countries of spots that match the given
+	// parameters along with numbers of such spots in them, ordered from the
+	// country with the most spots.
+	CountriesWithSpotCounts(context.Context, CountriesParams) ([]CountrySpotCount, error)
+}
+
+// CountriesParams holds parameters for reading countries of spots from a data
+// storage.
+type CountriesParams struct {
+	SearchQuery SpotSearchQuery
+}
+
+// DirectoryCountry holds a country of spots along with some of its localities, so
+// that spots can be browsed as a directory.
+type DirectoryCountry struct {
+	CountryCode   string
+	SpotCount     int
+	LocalityCount int
+
+	// Localities holds up to the requested number of localities of the country in
+	// alphabetical order.
+	Localities []DirectoryLocality
+}
+
+// DirectoryLocality holds a locality of spots along with some of its spots.
+type DirectoryLocality struct {
+	Locality  string
+	SpotCount int
+
+	// Spots holds up to the requested number of spots of the locality ordered by
+	// their names. Only their IDs, names, and locations are populated.
+	Spots []Spot
+}
+
+// SpotDirectoryReader is a data storage containing spots from which spots grouped
+// by countries and localities can be read.
+type SpotDirectoryReader interface {
+	// SpotDirectory returns countries of spots ordered by their codes, along with
+	// their localities and spots limited according to the given parameters.
+	SpotDirectory(context.Context, SpotDirectoryParams) ([]DirectoryCountry, error)
+}
+
+// SpotDirectoryParams holds parameters for reading spots grouped by countries and
+// localities from a data storage.
+type SpotDirectoryParams struct {
+	// Limit and Offset paginate countries.
+	Limit  int
+	Offset int
+
+	LocalitiesPerCountry int
+	SpotsPerLocality     int
+}
+
+// SpotCreationCount holds a number of spots created within a time bucket.
+type SpotCreationCount struct {
+	// BucketStart is the beginning of the time bucket.
+	BucketStart time.Time
+	Count       int
+}
+
+// SpotStatsReader is a data storage containing spots from which aggregated
+// statistics of spots can be read.
+type SpotStatsReader interface {
+	// SpotCreationCounts returns numbers of spots created within time buckets of
+	// the given interval, ordered from the earliest bucket. Buckets without any
+	// spots created are omitted.
+	SpotCreationCounts(context.Context, SpotCreationCountsParams) ([]SpotCreationCount, error)
+}
+
+// SpotCreationCountsParams holds parameters for reading numbers of created spots
+// from a data storage.
+type SpotCreationCountsParams struct {
+	// From is inclusive while To is exclusive.
+	From     time.Time
+	To       time.Time
+	Interval TimeInterval
+}
+
+// TimeInterval defines a length of time buckets in which spots are aggregated.
+type TimeInterval int
+
+const (
+	// TimeIntervalMonth is used for aggregating spots by calendar months.
+	TimeIntervalMonth TimeInterval = iota
+
+	// TimeIntervalWeek is used for aggregating spots by ISO weeks starting on
+	// Mondays.
+	TimeIntervalWeek
+
+	// TimeIntervalDay is used for aggregating spots by days.
+	TimeIntervalDay
+)
+
+// LocalityReader is a data storage containing spots from which localities of spots
+// can be read.
+type LocalityReader interface {
+	// Localities returns distinct localities of spots that match the given
+	// parameters in alphabetical order.
+	Localities(context.Context, LocalitiesParams) ([]string, error)
+}
+
+// LocalitiesParams holds parameters for reading localities of spots from a data
+// storage.
+type LocalitiesParams struct {
+	// Prefix is matched case-insensitively and literally against beginnings of
+	// localities.
+	Prefix      string
+	CountryCode string
+	Limit       int
+}
+
+// SpotIterator is a data storage that can iterate over a large number of spots
+// without loading all of them into memory at once.
+type SpotIterator interface {
+	// IterateSpots calls the given function for every spot that matches the given
+	// parameters. Iteration stops at the first error returned by the function,
+	// and the error gets returned.
+	IterateSpots(context.Context, SpotIterationParams, func(Spot) error) error
+}
+
+// SpotIterationParams holds parameters for iterating over spots in a data storage.
+type SpotIterationParams struct {
+	CountryCode string
+	SearchQuery SpotSearchQuery
+	Bounds      *geo.Bounds
+}
+
+// SpotQueryExplainer is a data storage that can explain how it executes queries
+// for reading spots, which is useful for diagnosing slow queries.
+type SpotQueryExplainer interface {
+	// ExplainSpots returns an execution plan, in a JSON format, of the query that
+	// reads multiple spots matching the given parameters.
+	ExplainSpots(context.Context, SpotsParams) ([]byte, error)
+}
+
+// SpotMarker is a lightweight representation of a spot that holds only its ID
+// and coordinates. It is suitable for rendering many spots on a map at once.
+type SpotMarker struct {
+	ID          string
+	Coordinates geo.Coordinates
+}
+
+// SpotMarkerReader is a data storage from which spot markers can be read.
+type SpotMarkerReader interface {
+	// SpotMarkers returns markers of multiple spots that match the given parameters.
+	SpotMarkers(context.Context, SpotMarkersParams) ([]SpotMarker, error)
+}
+
+// SpotMarkersParams holds parameters for reading multiple spot markers from a
+// data storage.
+type SpotMarkersParams struct {
+	Limit  int
+	Bounds *geo.Bounds
 }
 
 // SpotWriter is a data storage containing spots against which write operations
@@ -58,33 +455,514 @@ type SpotSearchQuery struct {
 type SpotWriter interface {
 	// CreateSpot creates a new spot using the given entry and returns it if the
 	// creation succeeds.
-	CreateSpot(SpotCreationEntry) (Spot, error)
+	CreateSpot(context.Context, SpotCreationEntry) (Spot, error)
 
 	// UpdateSpot updates an existing spot using the given entry and returns it
 	// if the update succeeds. ErrSpotNotFound is returned when spot is not found.
-	UpdateSpot(SpotUpdateEntry) (Spot, error)
+	UpdateSpot(context.Context, SpotUpdateEntry) (Spot, error)
+
+	// DeleteSpot soft-deletes a spot by the given ID. ErrSpotNotFound is returned
+	// when spot is not found.
+	DeleteSpot(ctx context.Context, id string) error
+
+	// DeleteSpots soft-deletes multiple spots by the given IDs and returns IDs of
+	// the spots that got deleted. IDs of spots that are not found are skipped.
+	DeleteSpots(ctx context.Context, ids []string) ([]string, error)
+
+	// RestoreSpot restores a soft-deleted spot by the given ID and returns it if
+	// the restoration succeeds. ErrSpotNotFound is returned when spot is not found
+	// or is not soft-deleted.
+	RestoreSpot(ctx context.Context, id string) (Spot, error)
+}
+
+// SpotTagWriter is a data storage containing spots whose tags can be written.
+type SpotTagWriter interface {
+	// SetSpotTags replaces tags of a spot by the given ID with the given tags.
+	// ErrSpotNotFound is returned when spot is not found.
+	SetSpotTags(ctx context.Context, id string, tags []string) error
+}
+
+// SpotPhoto holds metadata of a photo of a spot. Content of the photo is kept
+// apart from it in a media storage.
+type SpotPhoto struct {
+	ID          string
+	SpotID      string
+	ContentType string
+	Size        int64
+	CreatedAt   time.Time
+}
+
+// spotPhotoExtensions maps supported content types of photos to file extensions.
+var spotPhotoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// IsSpotPhotoContentType reports whether photos of the given content type, such as
+// image/jpeg, are supported.
+func IsSpotPhotoContentType(contentType string) bool {
+	_, ok := spotPhotoExtensions[contentType]
+	return ok
+}
+
+// Key returns a key under which content of the photo is kept in a media storage.
+func (p SpotPhoto) Key() string {
+	return "spots/" + p.SpotID + "/" + p.ID + spotPhotoExtensions[p.ContentType]
+}
+
+// SpotPhotoEntry holds parameters for creating a new photo of a spot in a data
+// storage.
+type SpotPhotoEntry struct {
+	SpotID      string
+	ContentType string
+	Size        int64
+}
+
+// SpotPhotoReader is a data storage from which photos of spots can be read.
+//
+// Photos of soft-deleted spots are kept, so that they come back once the spots are
+// restored, but they cannot be read or written in the meantime. Photos are
+// deleted along with spots that get deleted permanently.
+type SpotPhotoReader interface {
+	// SpotPhotos returns photos of a spot by the given ID from the oldest one.
+	// ErrSpotNotFound is returned when spot is not found.
+	SpotPhotos(ctx context.Context, spotID string) ([]SpotPhoto, error)
+}
+
+// SpotPhotoWriter is a data storage containing spots whose photos can be written.
+type SpotPhotoWriter interface {
+	// CreateSpotPhoto creates a new photo of a spot using the given entry and
+	// returns it. ErrSpotNotFound is returned when spot is not found.
+	CreateSpotPhoto(context.Context, SpotPhotoEntry) (SpotPhoto, error)
+
+	// DeleteSpotPhoto deletes a photo by the given ID of a spot by the given ID
+	// and returns the deleted photo. ErrSpotPhotoNotFound is returned when the
+	// photo is not found, including when the spot itself is not found.
+	DeleteSpotPhoto(ctx context.Context, spotID, photoID string) (SpotPhoto, error)
+}
+
+// SpotRatingEntry holds parameters for rating a spot in a data storage.
+type SpotRatingEntry struct {
+	SpotID string
+	Score  int
+
+	// RaterKey identifies whoever rates the spot without revealing them, such as
+	// a hash of their IP address.
+	RaterKey string
+
+	// RatedOn is a day on which the spot is rated. Only its date in UTC is kept.
+	RatedOn time.Time
+}
+
+// SpotRatingWriter is a data storage containing spots that can be rated.
+//
+// Ratings of soft-deleted spots are kept, so that they come back once the spots
+// are restored. Ratings are deleted along with spots that get deleted permanently.
+type SpotRatingWriter interface {
+	// RateSpot records a rating of a spot using the given entry. A rating of the
+	// same spot by the same rater on the same day replaces the previous one, so
+	// that every rater counts once a day. ErrSpotNotFound is returned when spot
+	// is not found.
+	RateSpot(context.Context, SpotRatingEntry) error
+}
+
+// FavoriteSpotsParams holds parameters for reading favorite spots of a user from a
+// data storage.
+type FavoriteSpotsParams struct {
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// SpotFavoriteReader is a data storage from which favorite spots of users can be
+// read.
+//
+// Favorites of soft-deleted spots are kept but hidden, so that they come back
+// once the spots are restored.
+type SpotFavoriteReader interface {
+	// FavoriteSpots returns spots that a user has favorited, from the most recently
+	// favorited one.
+	FavoriteSpots(context.Context, FavoriteSpotsParams) ([]Spot, error)
+
+	// FavoritedSpotIDs reports which of spots by the given IDs a user by the given
+	// ID has favorited. Only IDs of favorited spots are present in the returned map.
+	FavoritedSpotIDs(ctx context.Context, userID string, spotIDs []string) (map[string]bool, error)
+}
+
+// SpotFavoriteWriter is a data storage containing spots that users can favorite.
+type SpotFavoriteWriter interface {
+	// FavoriteSpot adds a spot by the given ID to favorites of a user by the given
+	// ID. Favoriting a spot that is already favorited has no effect.
+	// ErrSpotNotFound is returned when spot is not found.
+	FavoriteSpot(ctx context.Context, userID, spotID string) error
+
+	// UnfavoriteSpot removes a spot by the given ID from favorites of a user by the
+	// given ID. Unfavoriting a spot that is not favorited has no effect.
+	UnfavoriteSpot(ctx context.Context, userID, spotID string) error
+
+	// ApplyFavoriteBatch adds and removes spots of the given batch to and from
+	// favorites of its user at once, and returns IDs of the spots that got
+	// favorited. IDs of spots to favorite that are not found are skipped.
+	ApplyFavoriteBatch(context.Context, FavoriteBatch) ([]string, error)
+}
+
+// FavoriteBatch holds IDs of spots to add to and remove from favorites of a user
+// at once.
+type FavoriteBatch struct {
+	UserID     string
+	Favorite   []string
+	Unfavorite []string
+}
+
+// SpotSuggestionStatus defines a stage of reviewing a spot suggestion.
+type SpotSuggestionStatus string
+
+const (
+	// SpotSuggestionStatusPending is used for suggestions awaiting a review.
+	SpotSuggestionStatusPending SpotSuggestionStatus = "pending"
 
-	// DeleteSpot deletes a spot by the given ID. ErrSpotNotFound is returned when
-	// spot is not found.
-	DeleteSpot(id string) error
+	// SpotSuggestionStatusApplied is used for suggestions whose changes have been
+	// applied to their spots.
+	SpotSuggestionStatusApplied SpotSuggestionStatus = "applied"
+
+	// SpotSuggestionStatusRejected is used for suggestions that have been turned
+	// down.
+	SpotSuggestionStatusRejected SpotSuggestionStatus = "rejected"
+)
+
+// SpotSuggestion holds changes of a spot that are proposed by a surfer and have
+// to be reviewed before being applied.
+type SpotSuggestion struct {
+	ID     string
+	Status SpotSuggestionStatus
+
+	// Change holds the proposed values along with an ID of the spot. Nil values
+	// are left unchanged, and empty strings clear optional fields of a location.
+	Change SpotUpdateEntry
+
+	// Note is an optional comment of the surfer.
+	Note string
+
+	CreatedAt time.Time
+
+	// ResolvedAt is set only for suggestions that are no longer pending.
+	ResolvedAt *time.Time
+}
+
+// SpotSuggestionEntry holds parameters for creating a new spot suggestion in a data
+// storage.
+type SpotSuggestionEntry struct {
+	Change SpotUpdateEntry
+	Note   string
+}
+
+// SpotSuggestionsParams holds parameters for reading spot suggestions from a data
+// storage.
+type SpotSuggestionsParams struct {
+	Limit  int
+	Offset int
+
+	// Status can be optionally used to read only suggestions of the given status.
+	Status SpotSuggestionStatus
+}
+
+// SpotSuggestionReader is a data storage from which spot suggestions can be read.
+type SpotSuggestionReader interface {
+	// SpotSuggestion returns a spot suggestion by the given ID.
+	// ErrSpotSuggestionNotFound is returned when suggestion is not found.
+	SpotSuggestion(ctx context.Context, id string) (SpotSuggestion, error)
+
+	// SpotSuggestions returns spot suggestions that match the given parameters,
+	// from the most recently created one.
+	SpotSuggestions(context.Context, SpotSuggestionsParams) ([]SpotSuggestion, error)
+}
+
+// SpotSuggestionWriter is a data storage containing spots whose changes can be
+// suggested.
+type SpotSuggestionWriter interface {
+	// CreateSpotSuggestion creates a new pending spot suggestion using the given
+	// entry and returns it if the creation succeeds. ErrSpotNotFound is returned
+	// when spot is not found.
+	CreateSpotSuggestion(context.Context, SpotSuggestionEntry) (SpotSuggestion, error)
+}
+
+// SpotSuggestionResolver is a data storage in which spot suggestions can be
+// resolved once reviewed.
+type SpotSuggestionResolver interface {
+	// ResolveSpotSuggestion moves a pending spot suggestion by the given ID to the
+	// given status and returns it. ErrSpotSuggestionNotFound is returned when
+	// suggestion is not found, and ErrSpotSuggestionResolved when it is no longer
+	// pending.
+	ResolveSpotSuggestion(ctx context.Context, id string, status SpotSuggestionStatus) (SpotSuggestion, error)
+}
+
+// AuditAction defines a kind of a mutation of a spot recorded in an audit log.
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+// AuditEntry is a record of an audit log telling who mutated a spot, how, and when.
+type AuditEntry struct {
+	ID      string
+	ActorID string
+	Action  AuditAction
+	SpotID  string
+
+	// Changes hold values of the fields that the mutation changed.
+	Changes []AuditChange
+
+	CreatedAt time.Time
+}
+
+// AuditChange holds values of a field of a spot before and after a mutation. Values
+// of a spot that does not exist, such as before its creation, are nil.
+type AuditChange struct {
+	Field  string
+	Before interface{}
+	After  interface{}
+}
+
+// SpotChanges returns changes of the name, location, and slug between the given
+// states of a spot. Nil states stand for a spot that does not exist, so that all
+// of the fields are reported for creations and deletions.
+func SpotChanges(before, after *Spot) []AuditChange {
+	b, a := auditedValues(before), auditedValues(after)
+
+	var changes []AuditChange
+	for i, field := range auditedFields {
+		if b[i] != a[i] {
+			changes = append(changes, AuditChange{
+				Field:  field,
+				Before: b[i],
+				After:  a[i],
+			})
+		}
+	}
+	return changes
+}
+
+// TagChanges returns a change of tags between the given ones, which are compared
+// regardless of their order. No changes are returned when both hold the same tags.
+// Tags are held as []interface{}, like they are once decoded from a stored entry.
+func TagChanges(before, after []string) []AuditChange {
+	b, a := sortedTags(before), sortedTags(after)
+	if equalTags(b, a) {
+		return nil
+	}
+	return []AuditChange{
+		{
+			Field:  "tags",
+			Before: tagValues(b),
+			After:  tagValues(a),
+		},
+	}
+}
+
+func tagValues(tags []string) []interface{} {
+	values := make([]interface{}, len(tags))
+	for i, t := range tags {
+		values[i] = t
+	}
+	return values
+}
+
+func sortedTags(tags []string) []string {
+	sorted := append([]string{}, tags...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// auditedFields holds names of the fields that SpotChanges compares, in the order
+// of the values returned by auditedValues.
+var auditedFields = []string{"name", "latitude", "longitude", "locality", "country_code", "region", "slug"}
+
+func auditedValues(s *Spot) []interface{} {
+	if s == nil {
+		return make([]interface{}, len(auditedFields))
+	}
+	return []interface{}{
+		s.Name,
+		s.Location.Coordinates.Latitude,
+		s.Location.Coordinates.Longitude,
+		s.Location.Locality,
+		s.Location.CountryCode,
+		s.Location.Region,
+		s.Slug,
+	}
+}
+
+type auditActorKey struct{}
+
+// ContextWithAuditActor returns a copy of the given context that holds an ID of a
+// user on whose behalf spots are mutated. Data storages record mutations of spots
+// made with such a context in an audit log, including bulk ones such as batch
+// creations and deletions, within the same transaction as the mutations, so that
+// either both or none of them are applied.
+func ContextWithAuditActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actorID)
+}
+
+// AuditActorFromContext returns an ID of a user that is held by the given context,
+// if any.
+func AuditActorFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(auditActorKey{}).(string)
+	return id, ok
+}
+
+// AuditEntriesParams holds parameters for reading audit entries from a data
+// storage.
+type AuditEntriesParams struct {
+	Limit  int
+	Offset int
+
+	// SpotID can be optionally used to read only entries of the given spot.
+	SpotID string
+
+	// ActorID can be optionally used to read only entries of the given actor.
+	ActorID string
+
+	// From and To can be optionally used to read only entries created within the
+	// given period. From is inclusive while To is exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// AuditReader is a data storage from which audit entries can be read.
+type AuditReader interface {
+	// AuditEntries returns audit entries that match the given parameters, from the
+	// most recently created one.
+	AuditEntries(context.Context, AuditEntriesParams) ([]AuditEntry, error)
+}
+
+// IdempotencyKeyStore is a data storage in which keys that spots were created
+// with are kept, so that retried creations can be recognized.
+type IdempotencyKeyStore interface {
+	// CreateSpotIdempotently creates a new spot using the given entry and saves
+	// the given idempotency key along with it at the given time, both at once.
+	// When a spot has already been created with the key not earlier than the
+	// given since time, nothing is created, and that spot is returned along with
+	// false instead. ErrSpotNotFound is returned when that spot has been deleted
+	// since then. An existing key is replaced only once it has expired.
+	CreateSpotIdempotently(ctx context.Context, key string, e SpotCreationEntry, since, now time.Time) (Spot, bool, error)
 }
 
 // SpotCreationEntry holds parameters for creating a new spot in a data storage.
 type SpotCreationEntry struct {
 	Location geo.Location
 	Name     string
+
+	// ExternalRef is an optional key that references a spot in an external source
+	// (e.g. a row of an imported file). It is used for matching existing spots
+	// when entries are upserted.
+	ExternalRef string
+
+	// Tags optionally label the spot. When entries are upserted, tags of a matched
+	// spot are replaced only if the entry has any.
+	Tags []string
+
+	// Slug is an optional human-friendly identifier of the spot. A data storage
+	// suffixes it with -2, -3, and so on when it is taken by another spot. It is
+	// used only by SpotWriter.
+	Slug string
 }
 
-// SpotCreationEntrySource is anything that can fetch entries for creating spots.
+// SpotCreationEntrySource is anything that can iterate over entries for creating
+// spots.
 type SpotCreationEntrySource interface {
-	// SpotCreationEntries fetches and returns entries for creating spots.
-	SpotCreationEntries() ([]SpotCreationEntry, error)
+	// Next fetches and returns the next entry for creating a spot. io.EOF is
+	// returned when there are no entries left.
+	Next() (SpotCreationEntry, error)
+}
+
+// MalformedSpotCreationEntryError is used by a SpotCreationEntrySource when fields
+// of an entry cannot be parsed. Unlike other errors, it does not prevent the rest
+// of the entries from being fetched.
+type MalformedSpotCreationEntryError struct {
+	// Row is a number of the entry's row within the source.
+	Row    int
+	Fields []MalformedSpotCreationEntryField
+}
+
+// MalformedSpotCreationEntryField holds details of a field that cannot be parsed.
+type MalformedSpotCreationEntryField struct {
+	Name string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *MalformedSpotCreationEntryError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = fmt.Sprintf("invalid %s on row %d: %s", f.Name, e.Row, f.Err)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SliceSpotCreationEntrySource is a SpotCreationEntrySource that iterates over
+// entries held in memory.
+type SliceSpotCreationEntrySource struct {
+	entries []SpotCreationEntry
+	i       int
+}
+
+// NewSliceSpotCreationEntrySource returns a new *SliceSpotCreationEntrySource that
+// iterates over the given entries.
+func NewSliceSpotCreationEntrySource(entries []SpotCreationEntry) *SliceSpotCreationEntrySource {
+	return &SliceSpotCreationEntrySource{
+		entries: entries,
+	}
+}
+
+// Next implements SpotCreationEntrySource interface.
+func (s *SliceSpotCreationEntrySource) Next() (SpotCreationEntry, error) {
+	if s.i >= len(s.entries) {
+		return SpotCreationEntry{}, io.EOF
+	}
+
+	e := s.entries[s.i]
+	s.i++
+	return e, nil
+}
+
+// ReadAllSpotCreationEntries reads all entries from the given source until io.EOF
+// and returns them. It should be used only for sources that are known to be small
+// enough to be held in memory.
+func ReadAllSpotCreationEntries(src SpotCreationEntrySource) ([]SpotCreationEntry, error) {
+	var entries []SpotCreationEntry
+	for {
+		e, err := src.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
 }
 
 // SpotUpdateEntry holds parameters for updating a spot in a data storage. It can
 // be used for both partial and full updates. In order to achieve a full update,
 // all pointer-fields must not be nil. For a partial update, only the desired fields
-// must not be nil.
+// must not be nil. Pointers to empty strings clear the respective fields.
 type SpotUpdateEntry struct {
 	ID          string
 	Name        *string
@@ -92,11 +970,93 @@ type SpotUpdateEntry struct {
 	Longitude   *float64
 	Locality    *string
 	CountryCode *string
+	Region      *string
+
+	// Slug is suffixed by a data storage with -2, -3, and so on when it is taken
+	// by another spot.
+	Slug *string
 }
 
 // MultiSpotWriter is a data storage containing spots against which multiple write
 // operations can be performed at once.
 type MultiSpotWriter interface {
-	// CreateSpots creates multiple new spots using the given entries.
-	CreateSpots([]SpotCreationEntry) error
+	// CreateSpots creates multiple new spots using the given entries and mode,
+	// and returns statistics about the affected spots.
+	CreateSpots(context.Context, []SpotCreationEntry, SpotCreationMode) (SpotCreationStats, error)
+}
+
+// SpotCreationMode defines how multiple spots get created in a data storage.
+type SpotCreationMode int
+
+const (
+	// SpotCreationModeInsert is used for always inserting new spots.
+	SpotCreationModeInsert SpotCreationMode = iota
+
+	// SpotCreationModeUpsert is used for updating existing spots that have the
+	// same external references as the given entries and inserting the rest.
+	SpotCreationModeUpsert
+)
+
+// SpotCreationStats holds statistics about spots affected during creation of
+// multiple spots.
+type SpotCreationStats struct {
+	Inserted int
+	Updated  int
+}
+
+// ReferencePoint is a named point, such as a city or an airport, that belongs to
+// a reference set against which distances of spots are measured.
+type ReferencePoint struct {
+	Name        string
+	Coordinates geo.Coordinates
+}
+
+// ReferenceDistance holds a distance from a spot to the nearest point of a
+// reference set.
+type ReferenceDistance struct {
+	PointName  string
+	DistanceKm float64
+}
+
+// SpotReferenceDistance is a distance from a spot by the given ID to the nearest
+// point of a reference set.
+type SpotReferenceDistance struct {
+	SpotID string
+	ReferenceDistance
+}
+
+// NearestReferencePoint returns a point among the given ones that is the closest
+// to the given coordinates along with a distance to it. False is returned when no
+// points are given. Ties are resolved in favor of the point that comes first.
+func NearestReferencePoint(c geo.Coordinates, points []ReferencePoint) (ReferencePoint, float64, bool) {
+	var (
+		nearest  ReferencePoint
+		distance float64
+		found    bool
+	)
+	for _, p := range points {
+		d := geo.DistanceKm(c, p.Coordinates)
+		if !found || d < distance {
+			nearest, distance, found = p, d, true
+		}
+	}
+	return nearest, distance, found
+}
+
+// ReferenceStore is a data storage containing reference sets and distances of
+// spots to them.
+type ReferenceStore interface {
+	// ReferencePoints returns points of the given reference set ordered by their
+	// names. No points are returned when the set does not exist.
+	ReferencePoints(ctx context.Context, set string) ([]ReferencePoint, error)
+
+	// ReplaceReferencePoints replaces points of the given reference set with the
+	// given ones. Distances of spots to the set are left intact until they get
+	// replaced as well.
+	ReplaceReferencePoints(ctx context.Context, set string, points []ReferencePoint) error
+
+	// ReplaceSpotReferenceDistances replaces distances of spots to the given
+	// reference set with the given ones. Distances of spots that are not given
+	// are removed, and distances of spots that are not found are skipped.
+	ReplaceSpotReferenceDistances(ctx context.Context, set string, distances []SpotReferenceDistance) error
 }
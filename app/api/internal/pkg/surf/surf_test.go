@@ -0,0 +1,321 @@
+package surf
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+)
+
+func TestNearestReferencePoint(t *testing.T) {
+	airports := []ReferencePoint{
+		{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+		{Name: "Denpasar", Coordinates: geo.Coordinates{Latitude: -8.75, Longitude: 115.17}},
+		{Name: "Honolulu", Coordinates: geo.Coordinates{Latitude: 21.32, Longitude: -157.92}},
+		{Name: "Nadi", Coordinates: geo.Coordinates{Latitude: -17.76, Longitude: 177.44}},
+	}
+
+	tests := []struct {
+		name             string
+		coordinates      geo.Coordinates
+		points           []ReferencePoint
+		expectedName     string
+		expectedDistance float64
+		expectedFound    bool
+	}{
+		{
+			name:          "return nothing for no points",
+			coordinates:   geo.Coordinates{Latitude: 43.65, Longitude: 51.17},
+			expectedFound: false,
+		},
+		{
+			name:             "return only point",
+			coordinates:      geo.Coordinates{Latitude: 43.65, Longitude: 51.17},
+			points:           airports[:1],
+			expectedName:     "Aktau",
+			expectedDistance: 24.22,
+			expectedFound:    true,
+		},
+		{
+			name:             "return nearest point",
+			coordinates:      geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+			points:           airports,
+			expectedName:     "Denpasar",
+			expectedDistance: 11.04,
+			expectedFound:    true,
+		},
+		{
+			name:             "return nearest point across the antimeridian",
+			coordinates:      geo.Coordinates{Latitude: -17.5, Longitude: -179.9},
+			points:           airports,
+			expectedName:     "Nadi",
+			expectedDistance: 283.36,
+			expectedFound:    true,
+		},
+		{
+			name:        "return first of equally distant points",
+			coordinates: geo.Coordinates{Latitude: 0, Longitude: 0},
+			points: []ReferencePoint{
+				{Name: "East", Coordinates: geo.Coordinates{Latitude: 0, Longitude: 1}},
+				{Name: "West", Coordinates: geo.Coordinates{Latitude: 0, Longitude: -1}},
+			},
+			expectedName:     "East",
+			expectedDistance: 111.19,
+			expectedFound:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			point, distance, found := NearestReferencePoint(test.coordinates, test.points)
+			assert.Equal(t, test.expectedFound, found)
+			assert.Equal(t, test.expectedName, point.Name)
+			assert.InDelta(t, test.expectedDistance, distance, 0.01)
+		})
+	}
+}
+
+type stubNearbySpotReader struct {
+	spots  []NearbySpot
+	err    error
+	params NearbySpotsParams
+}
+
+func (r *stubNearbySpotReader) NearbySpots(ctx context.Context, p NearbySpotsParams) ([]NearbySpot, error) {
+	r.params = p
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.spots) > p.Limit {
+		return r.spots[:p.Limit], nil
+	}
+	return r.spots, nil
+}
+
+func TestNearbySpotsOf(t *testing.T) {
+	spot := Spot{
+		ID: "1",
+		Location: geo.Location{
+			Coordinates: geo.Coordinates{Latitude: 1.23, Longitude: 3.21},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		reader        *stubNearbySpotReader
+		limit         int
+		expectedIDs   []string
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "return error during reader failure",
+			reader:        &stubNearbySpotReader{err: errors.New("something went wrong")},
+			limit:         2,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "return spots excluding given spot",
+			reader: &stubNearbySpotReader{spots: []NearbySpot{
+				{Spot: Spot{ID: "1"}},
+				{Spot: Spot{ID: "2"}, DistanceKm: 1},
+				{Spot: Spot{ID: "3"}, DistanceKm: 2},
+				{Spot: Spot{ID: "4"}, DistanceKm: 3},
+			}},
+			limit:         2,
+			expectedIDs:   []string{"2", "3"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "return up to limit of spots when given spot is not read",
+			reader: &stubNearbySpotReader{spots: []NearbySpot{
+				{Spot: Spot{ID: "2"}, DistanceKm: 1},
+				{Spot: Spot{ID: "3"}, DistanceKm: 2},
+				{Spot: Spot{ID: "4"}, DistanceKm: 3},
+			}},
+			limit:         2,
+			expectedIDs:   []string{"2", "3"},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:          "return no spots without error",
+			reader:        &stubNearbySpotReader{spots: []NearbySpot{{Spot: Spot{ID: "1"}}}},
+			limit:         2,
+			expectedIDs:   []string{},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nearby, err := NearbySpotsOf(context.Background(), test.reader, spot, 10, test.limit)
+			if !test.expectedErrFn(t, err) || err != nil {
+				return
+			}
+
+			assert.Equal(t, NearbySpotsParams{
+				Coordinates: spot.Location.Coordinates,
+				RadiusKm:    10,
+				Limit:       test.limit + 1,
+			}, test.reader.params)
+
+			ids := make([]string, len(nearby))
+			for i, n := range nearby {
+				ids[i] = n.ID
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestSpotPhoto_Key(t *testing.T) {
+	tests := []struct {
+		name        string
+		photo       SpotPhoto
+		expectedKey string
+	}{
+		{
+			name:        "return key with extension of jpeg",
+			photo:       SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/jpeg"},
+			expectedKey: "spots/1/2.jpg",
+		},
+		{
+			name:        "return key with extension of webp",
+			photo:       SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/webp"},
+			expectedKey: "spots/1/2.webp",
+		},
+		{
+			name:        "return key without extension of unsupported content type",
+			photo:       SpotPhoto{ID: "2", SpotID: "1", ContentType: "image/gif"},
+			expectedKey: "spots/1/2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedKey, test.photo.Key())
+		})
+	}
+}
+
+func TestSpotChanges(t *testing.T) {
+	spot := Spot{
+		ID:   "1",
+		Name: "Uluwatu",
+		Slug: "uluwatu",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+			Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+		},
+	}
+
+	renamed := spot
+	renamed.Name = "Padang Padang"
+	renamed.Location.Region = "Badung"
+
+	tests := []struct {
+		name            string
+		before          *Spot
+		after           *Spot
+		expectedChanges []AuditChange
+	}{
+		{
+			name:   "return all fields for created spot",
+			before: nil,
+			after:  &spot,
+			expectedChanges: []AuditChange{
+				{Field: "name", Before: nil, After: "Uluwatu"},
+				{Field: "latitude", Before: nil, After: -8.81},
+				{Field: "longitude", Before: nil, After: 115.09},
+				{Field: "locality", Before: nil, After: "Bali"},
+				{Field: "country_code", Before: nil, After: "id"},
+				{Field: "region", Before: nil, After: ""},
+				{Field: "slug", Before: nil, After: "uluwatu"},
+			},
+		},
+		{
+			name:   "return changed fields for updated spot",
+			before: &spot,
+			after:  &renamed,
+			expectedChanges: []AuditChange{
+				{Field: "name", Before: "Uluwatu", After: "Padang Padang"},
+				{Field: "region", Before: "", After: "Badung"},
+			},
+		},
+		{
+			name:            "return nothing for unchanged spot",
+			before:          &spot,
+			after:           &spot,
+			expectedChanges: nil,
+		},
+		{
+			name:   "return all fields for deleted spot",
+			before: &spot,
+			after:  nil,
+			expectedChanges: []AuditChange{
+				{Field: "name", Before: "Uluwatu", After: nil},
+				{Field: "latitude", Before: -8.81, After: nil},
+				{Field: "longitude", Before: 115.09, After: nil},
+				{Field: "locality", Before: "Bali", After: nil},
+				{Field: "country_code", Before: "id", After: nil},
+				{Field: "region", Before: "", After: nil},
+				{Field: "slug", Before: "uluwatu", After: nil},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedChanges, SpotChanges(test.before, test.after))
+		})
+	}
+}
+
+func TestTagChanges(t *testing.T) {
+	tests := []struct {
+		name            string
+		before          []string
+		after           []string
+		expectedChanges []AuditChange
+	}{
+		{
+			name:   "return sorted tags when they change",
+			before: []string{"reef", "beginner"},
+			after:  []string{"reef"},
+			expectedChanges: []AuditChange{
+				{Field: "tags", Before: []interface{}{"beginner", "reef"}, After: []interface{}{"reef"}},
+			},
+		},
+		{
+			name:   "return empty tags when all are added",
+			before: nil,
+			after:  []string{"reef"},
+			expectedChanges: []AuditChange{
+				{Field: "tags", Before: []interface{}{}, After: []interface{}{"reef"}},
+			},
+		},
+		{
+			name:            "return nothing for reordered tags",
+			before:          []string{"reef", "beginner"},
+			after:           []string{"beginner", "reef"},
+			expectedChanges: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedChanges, TagChanges(test.before, test.after))
+		})
+	}
+}
+
+func TestAuditActorFromContext(t *testing.T) {
+	_, ok := AuditActorFromContext(context.Background())
+	assert.False(t, ok)
+
+	id, ok := AuditActorFromContext(ContextWithAuditActor(context.Background(), "admin-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "admin-1", id)
+}
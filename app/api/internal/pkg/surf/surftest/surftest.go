@@ -0,0 +1,1460 @@
+// Package surftest provides a conformance test suite for implementations of spot
+// stores, so that they can be used in place of each other.
+package surftest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
+)
+
+// unknownSpotID is a valid UUID that no spot is expected to have.
+const unknownSpotID = "00000000-0000-4000-8000-000000000000"
+
+// SpotStore is a spot store with all of the capabilities that the services rely
+// on.
+type SpotStore interface {
+	surf.SpotReader
+	surf.SpotSlugReader
+	surf.SpotWriter
+	surf.MultiSpotWriter
+	surf.SpotTagWriter
+	surf.SpotPhotoReader
+	surf.SpotPhotoWriter
+	surf.SpotRatingWriter
+	surf.SpotFavoriteReader
+	surf.SpotFavoriteWriter
+	surf.IdempotencyKeyStore
+	surf.SpotSuggestionReader
+	surf.SpotSuggestionWriter
+	surf.SpotSuggestionResolver
+	surf.AuditReader
+	surf.RandomSpotReader
+	surf.SpotCentroidReader
+	surf.NearbySpotReader
+	surf.SpotCountryReader
+	surf.SpotDirectoryReader
+	surf.LocalityReader
+	surf.SpotStatsReader
+	surf.SpotMarkerReader
+	surf.SpotIterator
+	surf.SpotQueryExplainer
+	surf.ReferenceStore
+}
+
+// TestSpotStore runs the conformance test suite against stores that the given
+// function returns. The function is called for every test and must return an
+// empty store.
+func TestSpotStore(t *testing.T, newStore func(t *testing.T) SpotStore) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, SpotStore)
+	}{
+		{name: "Spot", fn: testSpot},
+		{name: "Spots", fn: testSpots},
+		{name: "CreateSpots", fn: testCreateSpots},
+		{name: "UpdateSpot", fn: testUpdateSpot},
+		{name: "SpotSlugs", fn: testSpotSlugs},
+		{name: "DeleteAndRestoreSpots", fn: testDeleteAndRestoreSpots},
+		{name: "SetSpotTags", fn: testSetSpotTags},
+		{name: "SpotPhotos", fn: testSpotPhotos},
+		{name: "RateSpot", fn: testRateSpot},
+		{name: "Favorites", fn: testFavorites},
+		{name: "FavoriteBatch", fn: testFavoriteBatch},
+		{name: "IdempotencyKeys", fn: testIdempotencyKeys},
+		{name: "SpotSuggestions", fn: testSpotSuggestions},
+		{name: "AuditLog", fn: testAuditLog},
+		{name: "BulkAuditLog", fn: testBulkAuditLog},
+		{name: "Aggregates", fn: testAggregates},
+		{name: "SpotDirectory", fn: testSpotDirectory},
+		{name: "Geo", fn: testGeo},
+		{name: "IterateSpots", fn: testIterateSpots},
+		{name: "ReferenceDistances", fn: testReferenceDistances},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fn(t, newStore(t))
+		})
+	}
+}
+
+var seedEntries = []surf.SpotCreationEntry{
+	{
+		Name:        "Trigg Point",
+		ExternalRef: "trigg",
+		Tags:        []string{"reef", "point"},
+		Location: geo.Location{
+			Locality:    "Perth",
+			CountryCode: "au",
+			Coordinates: geo.Coordinates{Latitude: -31.87, Longitude: 115.75},
+		},
+	},
+	{
+		Name:        "Cables",
+		ExternalRef: "cables",
+		Tags:        []string{"reef"},
+		Location: geo.Location{
+			Locality:    "Perth",
+			CountryCode: "au",
+			Coordinates: geo.Coordinates{Latitude: -32.01, Longitude: 115.75},
+		},
+	},
+	{
+		Name: "Aktau Beach",
+		Tags: []string{"beach"},
+		Location: geo.Location{
+			Locality:    "Aktau",
+			CountryCode: "kz",
+			Coordinates: geo.Coordinates{Latitude: 43.65, Longitude: 51.17},
+		},
+	},
+	{
+		Name: "Fiji Reef",
+		Location: geo.Location{
+			Locality:    "Tavarua",
+			CountryCode: "fj",
+			Coordinates: geo.Coordinates{Latitude: -17.86, Longitude: 177.2},
+		},
+	},
+}
+
+// seed creates spots of seedEntries and returns them by their names.
+func seed(t *testing.T, s SpotStore) map[string]surf.Spot {
+	ctx := context.Background()
+
+	stats, err := s.CreateSpots(ctx, seedEntries, surf.SpotCreationModeInsert)
+	if !assert.NoError(t, err) || !assert.Equal(t, surf.SpotCreationStats{Inserted: len(seedEntries)}, stats) {
+		t.FailNow()
+	}
+
+	spots := make(map[string]surf.Spot)
+	err = s.IterateSpots(ctx, surf.SpotIterationParams{}, func(spot surf.Spot) error {
+		spot.ExternalRef = ""
+		spots[spot.Name] = spot
+		return nil
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, spots, len(seedEntries)) {
+		t.FailNow()
+	}
+
+	return spots
+}
+
+func names(spots []surf.Spot) []string {
+	var n []string
+	for _, s := range spots {
+		n = append(n, s.Name)
+	}
+	return n
+}
+
+func suggestionIDs(suggestions []surf.SpotSuggestion) []string {
+	var ids []string
+	for _, s := range suggestions {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func testSpot(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+
+	created, err := s.CreateSpot(ctx, surf.SpotCreationEntry{
+		Name: "Trigg Point",
+		Location: geo.Location{
+			Locality:    "Perth",
+			CountryCode: "au",
+			Coordinates: geo.Coordinates{Latitude: -31.87, Longitude: 115.75},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, created.ID)
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.Equal(t, created.CreatedAt, created.UpdatedAt)
+	assert.Empty(t, created.Tags)
+
+	spot, err := s.Spot(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created, spot)
+
+	_, err = s.Spot(ctx, unknownSpotID)
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+}
+
+func testSpots(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	tests := []struct {
+		name          string
+		params        surf.SpotsParams
+		expectedNames []string
+	}{
+		{
+			name:          "filter by country",
+			params:        surf.SpotsParams{Limit: 10, CountryCode: "au"},
+			expectedNames: []string{"Trigg Point", "Cables"},
+		},
+		{
+			name: "search by substring of name or locality case-insensitively",
+			params: surf.SpotsParams{
+				Limit:       10,
+				SearchQuery: surf.SpotSearchQuery{Query: "AKT"},
+			},
+			expectedNames: []string{"Aktau Beach"},
+		},
+		{
+			name: "search literally",
+			params: surf.SpotsParams{
+				Limit:       10,
+				SearchQuery: surf.SpotSearchQuery{Query: "%"},
+			},
+			expectedNames: nil,
+		},
+		{
+			name: "search by spot id",
+			params: surf.SpotsParams{
+				Limit: 10,
+				SearchQuery: surf.SpotSearchQuery{
+					Query:      seeded["Cables"].ID,
+					WithSpotID: true,
+				},
+			},
+			expectedNames: []string{"Cables"},
+		},
+		{
+			name: "search by words regardless of their order",
+			params: surf.SpotsParams{
+				Limit: 10,
+				SearchQuery: surf.SpotSearchQuery{
+					Query:    "perth point",
+					FullText: true,
+				},
+			},
+			expectedNames: []string{"Trigg Point"},
+		},
+		{
+			name: "filter by bounds crossing antimeridian",
+			params: surf.SpotsParams{
+				Limit: 10,
+				Bounds: &geo.Bounds{
+					NorthEast: geo.Coordinates{Latitude: 0, Longitude: -170},
+					SouthWest: geo.Coordinates{Latitude: -40, Longitude: 170},
+				},
+			},
+			expectedNames: []string{"Fiji Reef"},
+		},
+		{
+			name: "filter by all tags",
+			params: surf.SpotsParams{
+				Limit:     10,
+				Tags:      []string{"reef", "point"},
+				TagsMatch: surf.SpotTagsMatchAll,
+			},
+			expectedNames: []string{"Trigg Point"},
+		},
+		{
+			name: "filter by any tags",
+			params: surf.SpotsParams{
+				Limit:     10,
+				Tags:      []string{"point", "beach"},
+				TagsMatch: surf.SpotTagsMatchAny,
+			},
+			expectedNames: []string{"Trigg Point", "Aktau Beach"},
+		},
+		{
+			name:          "apply limit and offset",
+			params:        surf.SpotsParams{Limit: 2, Offset: 3},
+			expectedNames: []string{"Fiji Reef"},
+		},
+	}
+
+	// The most recently updated spot goes first.
+	if !assert.NoError(t, s.SetSpotTags(ctx, seeded["Fiji Reef"].ID, nil)) {
+		return
+	}
+
+	spots, err := s.Spots(ctx, surf.SpotsParams{
+		Limit: 2,
+		Order: surf.SpotOrderUpdatedAtDesc,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, spots, 2) {
+		assert.Equal(t, "Fiji Reef", spots[0].Name)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spots, err := s.Spots(ctx, test.params)
+			assert.NoError(t, err)
+
+			if test.params.Offset > 0 {
+				assert.Len(t, spots, len(test.expectedNames))
+				return
+			}
+			assert.ElementsMatch(t, test.expectedNames, names(spots))
+		})
+	}
+
+	if !assert.NoError(t, s.DeleteSpot(ctx, seeded["Cables"].ID)) {
+		return
+	}
+
+	spots, err = s.Spots(ctx, surf.SpotsParams{Limit: 10, CountryCode: "au"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Trigg Point"}, names(spots))
+
+	spots, err = s.Spots(ctx, surf.SpotsParams{Limit: 10, CountryCode: "au", IncludeDeleted: true})
+	assert.NoError(t, err)
+	for _, spot := range spots {
+		assert.Equal(t, spot.Name == "Cables", spot.DeletedAt != nil, spot.Name)
+	}
+}
+
+func testCreateSpots(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	_, err := s.CreateSpots(ctx, nil, surf.SpotCreationModeInsert)
+	assert.Error(t, err)
+
+	_, err = s.CreateSpots(ctx, []surf.SpotCreationEntry{
+		{Name: "Another Trigg", ExternalRef: "trigg"},
+	}, surf.SpotCreationModeInsert)
+	assert.Error(t, err)
+
+	stats, err := s.CreateSpots(ctx, []surf.SpotCreationEntry{
+		{
+			Name:        "Trigg Point 1",
+			ExternalRef: "trigg",
+			Location:    seedEntries[0].Location,
+		},
+		{
+			Name:        "Trigg Point 2",
+			ExternalRef: "trigg",
+			Location:    seedEntries[0].Location,
+		},
+		{
+			Name:        "Aktau Beach 2",
+			ExternalRef: seeded["Aktau Beach"].ID,
+			Tags:        []string{"sand"},
+			Location:    seedEntries[2].Location,
+		},
+		{
+			Name:        "New Spot",
+			ExternalRef: "new",
+			Location:    seedEntries[2].Location,
+		},
+	}, surf.SpotCreationModeUpsert)
+	assert.NoError(t, err)
+	assert.Equal(t, surf.SpotCreationStats{Inserted: 1, Updated: 2}, stats)
+
+	trigg, err := s.Spot(ctx, seeded["Trigg Point"].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Trigg Point 2", trigg.Name)
+	assert.Equal(t, []string{"point", "reef"}, trigg.Tags)
+	assert.False(t, trigg.UpdatedAt.Before(trigg.CreatedAt))
+
+	aktau, err := s.Spot(ctx, seeded["Aktau Beach"].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Aktau Beach 2", aktau.Name)
+	assert.Equal(t, []string{"sand"}, aktau.Tags)
+
+	spots, err := s.Spots(ctx, surf.SpotsParams{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, spots, len(seedEntries)+1)
+}
+
+func testUpdateSpot(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	cables := seeded["Cables"]
+
+	_, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{ID: cables.ID})
+	assert.ErrorIs(t, err, surf.ErrEmptySpotUpdateEntry)
+
+	_, err = s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   unknownSpotID,
+		Name: pconv.String("Cables"),
+	})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	updated, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:       cables.ID,
+		Name:     pconv.String("Cables Left"),
+		Latitude: pconv.Float64(-32),
+		Region:   pconv.String("Western Australia"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Cables Left", updated.Name)
+	assert.Equal(t, geo.Location{
+		Locality:    "Perth",
+		CountryCode: "au",
+		Region:      "Western Australia",
+		Coordinates: geo.Coordinates{Latitude: -32, Longitude: 115.75},
+	}, updated.Location)
+	assert.Equal(t, cables.CreatedAt, updated.CreatedAt)
+	assert.False(t, updated.UpdatedAt.Before(cables.UpdatedAt))
+
+	cleared, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:       cables.ID,
+		Locality: pconv.String(""),
+		Region:   pconv.String(""),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Location{
+		CountryCode: "au",
+		Coordinates: geo.Coordinates{Latitude: -32, Longitude: 115.75},
+	}, cleared.Location)
+
+	if !assert.NoError(t, s.DeleteSpot(ctx, cables.ID)) {
+		return
+	}
+
+	_, err = s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   cables.ID,
+		Name: pconv.String("Cables"),
+	})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+}
+
+func testSpotSlugs(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	for _, spot := range seeded {
+		assert.Empty(t, spot.Slug)
+	}
+
+	entry := surf.SpotCreationEntry{
+		Name: "Uluwatu",
+		Slug: "uluwatu-bali",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+			Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+		},
+	}
+
+	first, err := s.CreateSpot(ctx, entry)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "uluwatu-bali", first.Slug)
+
+	second, err := s.CreateSpot(ctx, entry)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "uluwatu-bali-2", second.Slug)
+
+	spot, err := s.SpotBySlug(ctx, "uluwatu-bali-2")
+	assert.NoError(t, err)
+	assert.Equal(t, second, spot)
+
+	_, err = s.SpotBySlug(ctx, "uluwatu")
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	// A spot keeps its own slug when it gets regenerated.
+	updated, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   first.ID,
+		Slug: pconv.String("uluwatu-bali"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "uluwatu-bali", updated.Slug)
+
+	// Slugs are matched regardless of case.
+	spot, err = s.SpotBySlug(ctx, "Uluwatu-Bali")
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, spot.ID)
+
+	// A slug of a soft-deleted spot can be reused by another spot.
+	if !assert.NoError(t, s.DeleteSpot(ctx, second.ID)) {
+		return
+	}
+
+	_, err = s.SpotBySlug(ctx, "uluwatu-bali-2")
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	cables := seeded["Cables"]
+	updated, err = s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   cables.ID,
+		Slug: pconv.String("uluwatu-bali"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "uluwatu-bali-2", updated.Slug)
+
+	// A restored spot loses its slug when another spot has reused it.
+	restored, err := s.RestoreSpot(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, restored.Slug)
+
+	cleared, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   cables.ID,
+		Slug: pconv.String(""),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, cleared.Slug)
+
+	_, err = s.SpotBySlug(ctx, "uluwatu-bali-2")
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+}
+
+// TestReservedSlugs tests the given store that keeps slugs of soft-deleted spots
+// reserved.
+func TestReservedSlugs(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	entry := surf.SpotCreationEntry{
+		Name: "Uluwatu",
+		Slug: "uluwatu-bali",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+			Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+		},
+	}
+
+	first, err := s.CreateSpot(ctx, entry)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NoError(t, s.DeleteSpot(ctx, first.ID)) {
+		return
+	}
+
+	second, err := s.CreateSpot(ctx, entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "uluwatu-bali-2", second.Slug)
+
+	updated, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   seeded["Cables"].ID,
+		Slug: pconv.String("Uluwatu-Bali"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "uluwatu-bali-3", updated.Slug)
+
+	// A restored spot keeps its slug.
+	restored, err := s.RestoreSpot(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "uluwatu-bali", restored.Slug)
+}
+
+func testDeleteAndRestoreSpots(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	assert.ErrorIs(t, s.DeleteSpot(ctx, unknownSpotID), surf.ErrSpotNotFound)
+
+	trigg := seeded["Trigg Point"]
+	assert.NoError(t, s.DeleteSpot(ctx, trigg.ID))
+	assert.ErrorIs(t, s.DeleteSpot(ctx, trigg.ID), surf.ErrSpotNotFound)
+
+	_, err := s.Spot(ctx, trigg.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	deleted, err := s.DeleteSpots(ctx, []string{trigg.ID, seeded["Cables"].ID, unknownSpotID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{seeded["Cables"].ID}, deleted)
+
+	// Another spot takes the external reference of the deleted one.
+	_, err = s.CreateSpots(ctx, []surf.SpotCreationEntry{
+		{Name: "New Trigg", ExternalRef: "trigg", Location: seedEntries[0].Location},
+	}, surf.SpotCreationModeUpsert)
+	assert.NoError(t, err)
+
+	restored, err := s.RestoreSpot(ctx, trigg.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, trigg.Name, restored.Name)
+	assert.Nil(t, restored.DeletedAt)
+
+	_, err = s.RestoreSpot(ctx, trigg.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	var refs []string
+	err = s.IterateSpots(ctx, surf.SpotIterationParams{}, func(spot surf.Spot) error {
+		if spot.ID == trigg.ID {
+			refs = append(refs, spot.ExternalRef)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, refs)
+}
+
+func testSetSpotTags(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau := seeded["Aktau Beach"]
+
+	assert.ErrorIs(t, s.SetSpotTags(ctx, unknownSpotID, []string{"reef"}), surf.ErrSpotNotFound)
+
+	assert.NoError(t, s.SetSpotTags(ctx, aktau.ID, []string{"sand", "beginner"}))
+
+	spot, err := s.Spot(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"beginner", "sand"}, spot.Tags)
+	assert.False(t, spot.UpdatedAt.Before(aktau.UpdatedAt))
+
+	assert.NoError(t, s.SetSpotTags(ctx, aktau.ID, nil))
+
+	spot, err = s.Spot(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, spot.Tags)
+}
+
+func testSpotPhotos(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau := seeded["Aktau Beach"]
+
+	_, err := s.CreateSpotPhoto(ctx, surf.SpotPhotoEntry{SpotID: unknownSpotID, ContentType: "image/jpeg", Size: 1})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	_, err = s.SpotPhotos(ctx, unknownSpotID)
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	photos, err := s.SpotPhotos(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, photos)
+
+	first, err := s.CreateSpotPhoto(ctx, surf.SpotPhotoEntry{SpotID: aktau.ID, ContentType: "image/jpeg", Size: 100})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, first.ID)
+	assert.Equal(t, aktau.ID, first.SpotID)
+	assert.Equal(t, "image/jpeg", first.ContentType)
+	assert.Equal(t, int64(100), first.Size)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	second, err := s.CreateSpotPhoto(ctx, surf.SpotPhotoEntry{SpotID: aktau.ID, ContentType: "image/png", Size: 200})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Both photos may be created within the same moment, so their order is not
+	// asserted.
+	photos, err = s.SpotPhotos(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, photoIDs(photos))
+
+	_, err = s.DeleteSpotPhoto(ctx, seeded["Trigg Point"].ID, first.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotPhotoNotFound)
+
+	deleted, err := s.DeleteSpotPhoto(ctx, aktau.ID, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, deleted.ID)
+	assert.Equal(t, "image/jpeg", deleted.ContentType)
+
+	_, err = s.DeleteSpotPhoto(ctx, aktau.ID, first.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotPhotoNotFound)
+
+	// Photos of soft-deleted spots are hidden until the spots get restored.
+	assert.NoError(t, s.DeleteSpot(ctx, aktau.ID))
+
+	_, err = s.SpotPhotos(ctx, aktau.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	_, err = s.DeleteSpotPhoto(ctx, aktau.ID, second.ID)
+	assert.ErrorIs(t, err, surf.ErrSpotPhotoNotFound)
+
+	_, err = s.RestoreSpot(ctx, aktau.ID)
+	assert.NoError(t, err)
+
+	photos, err = s.SpotPhotos(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{second.ID}, photoIDs(photos))
+}
+
+func photoIDs(photos []surf.SpotPhoto) []string {
+	ids := make([]string, len(photos))
+	for i, p := range photos {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func testRateSpot(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau := seeded["Aktau Beach"]
+
+	day := time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	err := s.RateSpot(ctx, surf.SpotRatingEntry{SpotID: unknownSpotID, Score: 5, RaterKey: "a", RatedOn: day})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	spot, err := s.Spot(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, surf.SpotRatings{}, spot.Ratings)
+
+	for _, e := range []surf.SpotRatingEntry{
+		{SpotID: aktau.ID, Score: 5, RaterKey: "a", RatedOn: day},
+		{SpotID: aktau.ID, Score: 2, RaterKey: "b", RatedOn: day},
+		// The same rater on the same day replaces the previous score.
+		{SpotID: aktau.ID, Score: 3, RaterKey: "b", RatedOn: day.Add(12 * time.Hour)},
+		// The same rater on the next day counts once more.
+		{SpotID: aktau.ID, Score: 5, RaterKey: "b", RatedOn: day.Add(24 * time.Hour)},
+		{SpotID: seeded["Cables"].ID, Score: 1, RaterKey: "a", RatedOn: day},
+	} {
+		if !assert.NoError(t, s.RateSpot(ctx, e)) {
+			return
+		}
+	}
+
+	spot, err = s.Spot(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, spot.Ratings.Count)
+	assert.InDelta(t, 13.0/3, spot.Ratings.Average, 0.0001)
+
+	spots, err := s.Spots(ctx, surf.SpotsParams{Limit: 10, CountryCode: "au"})
+	assert.NoError(t, err)
+	ratings := make(map[string]surf.SpotRatings)
+	for _, spot := range spots {
+		ratings[spot.Name] = spot.Ratings
+	}
+	assert.Equal(t, map[string]surf.SpotRatings{
+		"Trigg Point": {},
+		"Cables":      {Average: 1, Count: 1},
+	}, ratings)
+
+	// Soft-deleted spots cannot be rated, but keep their ratings.
+	assert.NoError(t, s.DeleteSpot(ctx, aktau.ID))
+
+	err = s.RateSpot(ctx, surf.SpotRatingEntry{SpotID: aktau.ID, Score: 1, RaterKey: "c", RatedOn: day})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	_, err = s.RestoreSpot(ctx, aktau.ID)
+	assert.NoError(t, err)
+
+	spot, err = s.Spot(ctx, aktau.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, spot.Ratings.Count)
+}
+
+func testFavorites(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau, cables := seeded["Aktau Beach"], seeded["Cables"]
+
+	const (
+		user  = "10000000-0000-4000-8000-000000000000"
+		other = "20000000-0000-4000-8000-000000000000"
+	)
+
+	assert.ErrorIs(t, s.FavoriteSpot(ctx, user, unknownSpotID), surf.ErrSpotNotFound)
+
+	spots, err := s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, spots)
+
+	assert.NoError(t, s.RateSpot(ctx, surf.SpotRatingEntry{SpotID: aktau.ID, Score: 4, RaterKey: "a", RatedOn: time.Now()}))
+
+	for _, id := range []string{aktau.ID, cables.ID, aktau.ID} {
+		// Favoriting the same spot again has no effect.
+		if !assert.NoError(t, s.FavoriteSpot(ctx, user, id)) {
+			return
+		}
+	}
+	assert.NoError(t, s.FavoriteSpot(ctx, other, cables.ID))
+
+	// Both spots may be favorited within the same moment, so their order is not
+	// asserted.
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Aktau Beach", "Cables"}, names(spots))
+	for _, spot := range spots {
+		if spot.ID == aktau.ID {
+			assert.Equal(t, surf.SpotRatings{Average: 4, Count: 1}, spot.Ratings)
+		}
+	}
+
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 1)
+
+	favorited, err := s.FavoritedSpotIDs(ctx, user, []string{aktau.ID, cables.ID, seeded["Fiji Reef"].ID, unknownSpotID})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{aktau.ID: true, cables.ID: true}, favorited)
+
+	favorited, err = s.FavoritedSpotIDs(ctx, other, []string{aktau.ID, cables.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{cables.ID: true}, favorited)
+
+	// Unfavoriting a spot that is not favorited has no effect.
+	assert.NoError(t, s.UnfavoriteSpot(ctx, user, cables.ID))
+	assert.NoError(t, s.UnfavoriteSpot(ctx, user, cables.ID))
+	assert.NoError(t, s.UnfavoriteSpot(ctx, user, unknownSpotID))
+
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Aktau Beach"}, names(spots))
+
+	// Favorites of soft-deleted spots are hidden until the spots get restored.
+	assert.NoError(t, s.DeleteSpot(ctx, aktau.ID))
+
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, spots)
+
+	assert.ErrorIs(t, s.FavoriteSpot(ctx, other, aktau.ID), surf.ErrSpotNotFound)
+
+	_, err = s.RestoreSpot(ctx, aktau.ID)
+	assert.NoError(t, err)
+
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Aktau Beach"}, names(spots))
+}
+
+func testFavoriteBatch(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau, cables, fiji := seeded["Aktau Beach"], seeded["Cables"], seeded["Fiji Reef"]
+
+	const user = "10000000-0000-4000-8000-000000000000"
+
+	if !assert.NoError(t, s.FavoriteSpot(ctx, user, fiji.ID)) {
+		return
+	}
+	if !assert.NoError(t, s.DeleteSpot(ctx, cables.ID)) {
+		return
+	}
+
+	// Spots that are not found or soft-deleted are skipped, while favoriting a
+	// spot that is already favorited has no effect.
+	favorited, err := s.ApplyFavoriteBatch(ctx, surf.FavoriteBatch{
+		UserID:     user,
+		Favorite:   []string{aktau.ID, cables.ID, unknownSpotID},
+		Unfavorite: []string{fiji.ID, unknownSpotID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{aktau.ID}, favorited)
+
+	spots, err := s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Aktau Beach"}, names(spots))
+
+	favorited, err = s.ApplyFavoriteBatch(ctx, surf.FavoriteBatch{
+		UserID:   user,
+		Favorite: []string{aktau.ID, fiji.ID},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{aktau.ID, fiji.ID}, favorited)
+
+	favorited, err = s.ApplyFavoriteBatch(ctx, surf.FavoriteBatch{
+		UserID:     user,
+		Unfavorite: []string{aktau.ID},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, favorited)
+
+	spots, err = s.FavoriteSpots(ctx, surf.FavoriteSpotsParams{UserID: user, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Fiji Reef"}, names(spots))
+}
+
+func testIdempotencyKeys(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+
+	entry := surf.SpotCreationEntry{
+		Name: "Uluwatu",
+		Slug: "uluwatu",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+			Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+		},
+	}
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first, created, err := s.CreateSpotIdempotently(ctx, "key", entry, createdAt.Add(-time.Hour), createdAt)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, created)
+	assert.Equal(t, "Uluwatu", first.Name)
+
+	// Retries with a fresh key return the spot created with it.
+	spot, created, err := s.CreateSpotIdempotently(ctx, "key", entry, createdAt, createdAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first.ID, spot.ID)
+
+	other, created, err := s.CreateSpotIdempotently(ctx, "other", entry, createdAt, createdAt)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, first.ID, other.ID)
+
+	// Expired keys are replaced by new creations.
+	second, created, err := s.CreateSpotIdempotently(ctx, "key", entry, createdAt.Add(time.Second), createdAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	spot, created, err = s.CreateSpotIdempotently(ctx, "key", entry, createdAt.Add(time.Second), createdAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, second.ID, spot.ID)
+
+	// Retries of creations whose spots have been deleted since then fail.
+	if !assert.NoError(t, s.DeleteSpot(ctx, second.ID)) {
+		return
+	}
+
+	_, _, err = s.CreateSpotIdempotently(ctx, "key", entry, createdAt.Add(time.Second), createdAt.Add(time.Hour))
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+}
+
+func testSpotSuggestions(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+	aktau, cables := seeded["Aktau Beach"], seeded["Cables"]
+
+	_, err := s.CreateSpotSuggestion(ctx, surf.SpotSuggestionEntry{
+		Change: surf.SpotUpdateEntry{ID: unknownSpotID, Name: pconv.String("Unknown")},
+	})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+
+	moved, err := s.CreateSpotSuggestion(ctx, surf.SpotSuggestionEntry{
+		Change: surf.SpotUpdateEntry{
+			ID:        aktau.ID,
+			Latitude:  pconv.Float64(43.6),
+			Longitude: pconv.Float64(51.2),
+		},
+		Note: "The beach is further south.",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, moved.ID)
+	assert.Equal(t, surf.SpotSuggestionStatusPending, moved.Status)
+	assert.Equal(t, surf.SpotUpdateEntry{
+		ID:        aktau.ID,
+		Latitude:  pconv.Float64(43.6),
+		Longitude: pconv.Float64(51.2),
+	}, moved.Change)
+	assert.Equal(t, "The beach is further south.", moved.Note)
+	assert.False(t, moved.CreatedAt.IsZero())
+	assert.Nil(t, moved.ResolvedAt)
+
+	// Empty strings of optional fields propose clearing them.
+	cleared, err := s.CreateSpotSuggestion(ctx, surf.SpotSuggestionEntry{
+		Change: surf.SpotUpdateEntry{ID: cables.ID, Region: pconv.String("")},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, surf.SpotUpdateEntry{ID: cables.ID, Region: pconv.String("")}, cleared.Change)
+
+	read, err := s.SpotSuggestion(ctx, moved.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, moved.ID, read.ID)
+	assert.Equal(t, moved.Change, read.Change)
+	assert.Equal(t, moved.Note, read.Note)
+
+	_, err = s.SpotSuggestion(ctx, unknownSpotID)
+	assert.ErrorIs(t, err, surf.ErrSpotSuggestionNotFound)
+
+	// Both suggestions may be created within the same moment, so their order is
+	// not asserted.
+	suggestions, err := s.SpotSuggestions(ctx, surf.SpotSuggestionsParams{Limit: 10})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{moved.ID, cleared.ID}, suggestionIDs(suggestions))
+
+	suggestions, err = s.SpotSuggestions(ctx, surf.SpotSuggestionsParams{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+
+	applied, err := s.ResolveSpotSuggestion(ctx, moved.ID, surf.SpotSuggestionStatusApplied)
+	assert.NoError(t, err)
+	assert.Equal(t, surf.SpotSuggestionStatusApplied, applied.Status)
+	assert.Equal(t, moved.Change, applied.Change)
+	assert.NotNil(t, applied.ResolvedAt)
+
+	_, err = s.ResolveSpotSuggestion(ctx, moved.ID, surf.SpotSuggestionStatusRejected)
+	assert.ErrorIs(t, err, surf.ErrSpotSuggestionResolved)
+
+	_, err = s.ResolveSpotSuggestion(ctx, unknownSpotID, surf.SpotSuggestionStatusRejected)
+	assert.ErrorIs(t, err, surf.ErrSpotSuggestionNotFound)
+
+	suggestions, err = s.SpotSuggestions(ctx, surf.SpotSuggestionsParams{
+		Limit:  10,
+		Status: surf.SpotSuggestionStatusPending,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{cleared.ID}, suggestionIDs(suggestions))
+
+	suggestions, err = s.SpotSuggestions(ctx, surf.SpotSuggestionsParams{
+		Limit:  10,
+		Status: surf.SpotSuggestionStatusApplied,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{moved.ID}, suggestionIDs(suggestions))
+
+	// Changes of soft-deleted spots cannot be suggested.
+	assert.NoError(t, s.DeleteSpot(ctx, aktau.ID))
+
+	_, err = s.CreateSpotSuggestion(ctx, surf.SpotSuggestionEntry{
+		Change: surf.SpotUpdateEntry{ID: aktau.ID, Name: pconv.String("Aktau")},
+	})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+}
+
+func testAuditLog(t *testing.T, s SpotStore) {
+	ctx := surf.ContextWithAuditActor(context.Background(), "admin-1")
+	seeded := seed(t, s)
+
+	// Spots written without an audit actor are not audited.
+	_, err := s.UpdateSpot(context.Background(), surf.SpotUpdateEntry{
+		ID:   seeded["Cables"].ID,
+		Name: pconv.String("Cables Left"),
+	})
+	assert.NoError(t, err)
+
+	created, err := s.CreateSpot(ctx, surf.SpotCreationEntry{
+		Name: "Uluwatu",
+		Slug: "uluwatu",
+		Location: geo.Location{
+			Locality:    "Bali",
+			CountryCode: "id",
+			Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updated, err := s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   created.ID,
+		Name: pconv.String("Uluwatu Bali"),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, s.DeleteSpot(ctx, created.ID))
+
+	// Failed mutations are not audited.
+	_, err = s.UpdateSpot(ctx, surf.SpotUpdateEntry{
+		ID:   unknownSpotID,
+		Name: pconv.String("Unknown"),
+	})
+	assert.ErrorIs(t, err, surf.ErrSpotNotFound)
+	assert.ErrorIs(t, s.DeleteSpot(ctx, created.ID), surf.ErrSpotNotFound)
+
+	entries, err := s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 10})
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 3) {
+		return
+	}
+
+	// Entries may be created within the same moment, so they are matched by their
+	// actions rather than by their order.
+	byAction := make(map[surf.AuditAction]surf.AuditEntry)
+	for _, e := range entries {
+		assert.NotEmpty(t, e.ID)
+		assert.Equal(t, "admin-1", e.ActorID)
+		assert.Equal(t, created.ID, e.SpotID)
+		assert.False(t, e.CreatedAt.IsZero())
+		byAction[e.Action] = e
+	}
+	assert.Equal(t, surf.SpotChanges(nil, &created), byAction[surf.AuditActionCreate].Changes)
+	assert.Equal(t, []surf.AuditChange{
+		{Field: "name", Before: "Uluwatu", After: "Uluwatu Bali"},
+	}, byAction[surf.AuditActionUpdate].Changes)
+	assert.Equal(t, surf.SpotChanges(&updated, nil), byAction[surf.AuditActionDelete].Changes)
+
+	newest, oldest := entries[0].CreatedAt, entries[len(entries)-1].CreatedAt
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 1, Offset: 2})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 10, SpotID: seeded["Cables"].ID})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{
+		Limit:   10,
+		ActorID: "admin-1",
+		From:    oldest,
+		To:      newest.Add(time.Second),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 10, ActorID: "admin-2"})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 10, From: newest.Add(time.Second)})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 10, To: oldest})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func testBulkAuditLog(t *testing.T, s SpotStore) {
+	ctx := surf.ContextWithAuditActor(context.Background(), "admin-1")
+	seeded := seed(t, s)
+
+	// Entries of bulk mutations may be created within the same moment, so they
+	// are looked up by spots and actions rather than by their order.
+	entry := func(spotID string, action surf.AuditAction) (surf.AuditEntry, bool) {
+		entries, err := s.AuditEntries(ctx, surf.AuditEntriesParams{Limit: 100, SpotID: spotID})
+		assert.NoError(t, err)
+		for _, e := range entries {
+			if e.Action == action {
+				return e, true
+			}
+		}
+		return surf.AuditEntry{}, false
+	}
+
+	stats, err := s.CreateSpots(ctx, []surf.SpotCreationEntry{
+		{
+			Name:        "Cables Left",
+			ExternalRef: "cables",
+			Location:    seeded["Cables"].Location,
+		},
+		{
+			Name:        "Uluwatu",
+			ExternalRef: "uluwatu",
+			Location: geo.Location{
+				Locality:    "Bali",
+				CountryCode: "id",
+				Coordinates: geo.Coordinates{Latitude: -8.81, Longitude: 115.09},
+			},
+		},
+	}, surf.SpotCreationModeUpsert)
+	if !assert.NoError(t, err) || !assert.Equal(t, surf.SpotCreationStats{Inserted: 1, Updated: 1}, stats) {
+		return
+	}
+
+	e, ok := entry(seeded["Cables"].ID, surf.AuditActionUpdate)
+	if assert.True(t, ok) {
+		assert.Equal(t, "admin-1", e.ActorID)
+		assert.Equal(t, []surf.AuditChange{
+			{Field: "name", Before: "Cables", After: "Cables Left"},
+		}, e.Changes)
+	}
+
+	spots, err := s.Spots(ctx, surf.SpotsParams{
+		Limit:       10,
+		SearchQuery: surf.SpotSearchQuery{Query: "Uluwatu"},
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, spots, 1) {
+		return
+	}
+	uluwatu := spots[0]
+
+	e, ok = entry(uluwatu.ID, surf.AuditActionCreate)
+	if assert.True(t, ok) {
+		assert.Equal(t, surf.SpotChanges(nil, &uluwatu), e.Changes)
+	}
+
+	assert.NoError(t, s.SetSpotTags(ctx, uluwatu.ID, []string{"reef", "left"}))
+
+	e, ok = entry(uluwatu.ID, surf.AuditActionUpdate)
+	if assert.True(t, ok) {
+		assert.Equal(t, []surf.AuditChange{
+			{Field: "tags", Before: []interface{}{}, After: []interface{}{"left", "reef"}},
+		}, e.Changes)
+	}
+
+	fiji := seeded["Fiji Reef"]
+
+	deleted, err := s.DeleteSpots(ctx, []string{fiji.ID, unknownSpotID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fiji.ID}, deleted)
+
+	e, ok = entry(fiji.ID, surf.AuditActionDelete)
+	if assert.True(t, ok) {
+		assert.Equal(t, surf.SpotChanges(&fiji, nil), e.Changes)
+	}
+
+	restored, err := s.RestoreSpot(ctx, fiji.ID)
+	assert.NoError(t, err)
+
+	e, ok = entry(fiji.ID, surf.AuditActionRestore)
+	if assert.True(t, ok) {
+		assert.Equal(t, surf.SpotChanges(nil, &restored), e.Changes)
+	}
+}
+
+func testAggregates(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	countries, err := s.CountriesWithSpotCounts(ctx, surf.CountriesParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.CountrySpotCount{
+		{CountryCode: "au", Count: 2},
+		{CountryCode: "fj", Count: 1},
+		{CountryCode: "kz", Count: 1},
+	}, countries)
+
+	countries, err = s.CountriesWithSpotCounts(ctx, surf.CountriesParams{
+		SearchQuery: surf.SpotSearchQuery{Query: "reef"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.CountrySpotCount{{CountryCode: "fj", Count: 1}}, countries)
+
+	localities, err := s.Localities(ctx, surf.LocalitiesParams{Prefix: "p", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Perth"}, localities)
+
+	localities, err = s.Localities(ctx, surf.LocalitiesParams{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Aktau", "Perth"}, localities)
+
+	localities, err = s.Localities(ctx, surf.LocalitiesParams{CountryCode: "fj", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tavarua"}, localities)
+
+	createdAt := seeded["Cables"].CreatedAt
+	counts, err := s.SpotCreationCounts(ctx, surf.SpotCreationCountsParams{
+		From:     createdAt.Add(-time.Hour),
+		To:       createdAt.Add(time.Hour),
+		Interval: surf.TimeIntervalDay,
+	})
+	assert.NoError(t, err)
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+		assert.True(t, !c.BucketStart.After(createdAt), c.BucketStart)
+	}
+	assert.Equal(t, len(seedEntries), total)
+
+	plan, err := s.ExplainSpots(ctx, surf.SpotsParams{Limit: 10})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, plan)
+}
+
+func testSpotDirectory(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	countries, err := s.SpotDirectory(ctx, surf.SpotDirectoryParams{
+		Limit:                1,
+		LocalitiesPerCountry: 1,
+		SpotsPerLocality:     1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.DirectoryCountry{
+		{
+			CountryCode:   "au",
+			SpotCount:     2,
+			LocalityCount: 1,
+			Localities: []surf.DirectoryLocality{
+				{
+					Locality:  "Perth",
+					SpotCount: 2,
+					Spots: []surf.Spot{
+						{
+							ID:   seeded["Cables"].ID,
+							Name: "Cables",
+							Location: geo.Location{
+								Locality:    "Perth",
+								CountryCode: "au",
+								Coordinates: geo.Coordinates{Latitude: -32.01, Longitude: 115.75},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, countries)
+
+	countries, err = s.SpotDirectory(ctx, surf.SpotDirectoryParams{
+		Limit:                10,
+		Offset:               1,
+		LocalitiesPerCountry: 10,
+		SpotsPerLocality:     10,
+	})
+	assert.NoError(t, err)
+	var codes []string
+	for _, c := range countries {
+		codes = append(codes, c.CountryCode)
+	}
+	assert.Equal(t, []string{"fj", "kz"}, codes)
+}
+
+func testGeo(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	centroid, err := s.SpotsCentroid(ctx, surf.SpotsCentroidParams{CountryCode: "au"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, centroid) {
+		assert.InDelta(t, -31.94, centroid.Latitude, 0.0001)
+		assert.InDelta(t, 115.75, centroid.Longitude, 0.0001)
+	}
+
+	centroid, err = s.SpotsCentroid(ctx, surf.SpotsCentroidParams{CountryCode: "us"})
+	assert.NoError(t, err)
+	assert.Nil(t, centroid)
+
+	nearby, err := s.NearbySpots(ctx, surf.NearbySpotsParams{
+		Coordinates: geo.Coordinates{Latitude: -31.9, Longitude: 115.75},
+		RadiusKm:    50,
+		Limit:       10,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, nearby, 2) {
+		assert.Equal(t, "Trigg Point", nearby[0].Name)
+		assert.Equal(t, "Cables", nearby[1].Name)
+		assert.InDelta(t, 3.34, nearby[0].DistanceKm, 0.01)
+	}
+
+	markers, err := s.SpotMarkers(ctx, surf.SpotMarkersParams{
+		Limit: 10,
+		Bounds: &geo.Bounds{
+			NorthEast: geo.Coordinates{Latitude: 50, Longitude: 60},
+			SouthWest: geo.Coordinates{Latitude: 40, Longitude: 50},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.SpotMarker{
+		{
+			ID:          seeded["Aktau Beach"].ID,
+			Coordinates: geo.Coordinates{Latitude: 43.65, Longitude: 51.17},
+		},
+	}, markers)
+
+	random, err := s.RandomSpots(ctx, surf.RandomSpotsParams{Limit: 1, CountryCode: "au"})
+	assert.NoError(t, err)
+	if assert.Len(t, random, 1) {
+		assert.Equal(t, "au", random[0].Location.CountryCode)
+	}
+}
+
+func testIterateSpots(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seed(t, s)
+
+	var (
+		ids  []string
+		refs []string
+	)
+	err := s.IterateSpots(ctx, surf.SpotIterationParams{CountryCode: "au"}, func(spot surf.Spot) error {
+		ids = append(ids, spot.ID)
+		refs = append(refs, spot.ExternalRef)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+	assert.True(t, ids[0] < ids[1])
+	assert.ElementsMatch(t, []string{"trigg", "cables"}, refs)
+
+	errStop := assert.AnError
+	calls := 0
+	err = s.IterateSpots(ctx, surf.SpotIterationParams{}, func(surf.Spot) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+func testReferenceDistances(t *testing.T, s SpotStore) {
+	ctx := context.Background()
+	seeded := seed(t, s)
+
+	points := []surf.ReferencePoint{
+		{Name: "Perth", Coordinates: geo.Coordinates{Latitude: -31.95, Longitude: 115.86}},
+		{Name: "Aktau", Coordinates: geo.Coordinates{Latitude: 43.86, Longitude: 51.09}},
+	}
+	if !assert.NoError(t, s.ReplaceReferencePoints(ctx, "airports", points)) {
+		return
+	}
+	if !assert.NoError(t, s.ReplaceReferencePoints(ctx, "cities", points[:1])) {
+		return
+	}
+
+	actualPoints, err := s.ReferencePoints(ctx, "airports")
+	assert.NoError(t, err)
+	assert.Equal(t, []surf.ReferencePoint{points[1], points[0]}, actualPoints)
+
+	actualPoints, err = s.ReferencePoints(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, actualPoints)
+
+	err = s.ReplaceSpotReferenceDistances(ctx, "airports", []surf.SpotReferenceDistance{
+		{
+			SpotID:            seeded["Cables"].ID,
+			ReferenceDistance: surf.ReferenceDistance{PointName: "Perth", DistanceKm: 13.9},
+		},
+		{
+			SpotID:            seeded["Aktau Beach"].ID,
+			ReferenceDistance: surf.ReferenceDistance{PointName: "Aktau", DistanceKm: 24.2},
+		},
+		{
+			SpotID:            unknownSpotID,
+			ReferenceDistance: surf.ReferenceDistance{PointName: "Aktau", DistanceKm: 1},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	spots, err := s.Spots(ctx, surf.SpotsParams{
+		Limit:        10,
+		ReferenceSet: "airports",
+		Order:        surf.SpotOrderReferenceDistance,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, spots, 4) {
+		assert.Equal(t, []string{"Cables", "Aktau Beach"}, names(spots[:2]))
+		assert.Equal(t, &surf.ReferenceDistance{PointName: "Perth", DistanceKm: 13.9}, spots[0].NearestReference)
+		assert.Nil(t, spots[2].NearestReference)
+		assert.Nil(t, spots[3].NearestReference)
+	}
+
+	// Distances are left out unless a reference set is requested.
+	spot, err := s.Spot(ctx, seeded["Cables"].ID)
+	assert.NoError(t, err)
+	assert.Nil(t, spot.NearestReference)
+
+	// Replacing distances removes the ones of spots that are not given.
+	err = s.ReplaceSpotReferenceDistances(ctx, "airports", []surf.SpotReferenceDistance{
+		{
+			SpotID:            seeded["Fiji Reef"].ID,
+			ReferenceDistance: surf.ReferenceDistance{PointName: "Perth", DistanceKm: 7000},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	spots, err = s.Spots(ctx, surf.SpotsParams{
+		Limit:        1,
+		ReferenceSet: "airports",
+		Order:        surf.SpotOrderReferenceDistance,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Fiji Reef"}, names(spots))
+
+	spots, err = s.Spots(ctx, surf.SpotsParams{
+		Limit:        10,
+		ReferenceSet: "cities",
+		Order:        surf.SpotOrderReferenceDistance,
+	})
+	assert.NoError(t, err)
+	for _, spot := range spots {
+		assert.Nil(t, spot.NearestReference)
+	}
+}
@@ -0,0 +1,36 @@
+// Package tide provides fetching high and low tides from external sources.
+package tide
+
+import (
+	"context"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+)
+
+const (
+	// TypeHigh is used for high tides.
+	TypeHigh = "high"
+
+	// TypeLow is used for low tides.
+	TypeLow = "low"
+)
+
+// Source is anything that can predict tides at given coordinates.
+type Source interface {
+	// Extremes fetches high and low tides within a day starting from the given
+	// time, in chronological order.
+	Extremes(ctx context.Context, c geo.Coordinates, start time.Time) ([]Extreme, error)
+}
+
+// Extreme is a high or low tide.
+type Extreme struct {
+	Time time.Time
+
+	// Type is either TypeHigh or TypeLow.
+	Type string
+
+	// Height is in meters relative to the source's datum. It is nil when the
+	// source has no data for it.
+	Height *float64
+}
@@ -0,0 +1,208 @@
+// Package worldtides provides predictions of tides using the WorldTides API.
+package worldtides
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+)
+
+const (
+	endpointTides = "/api/v3"
+
+	queryParamExtremes  = "extremes"
+	queryParamLatitude  = "lat"
+	queryParamLongitude = "lon"
+	queryParamStart     = "start"
+	queryParamLength    = "length"
+	queryParamKey       = "key"
+
+	// lengthDay is a length of the fetched period in seconds.
+	lengthDay = 24 * 60 * 60
+
+	typeHigh = "High"
+	typeLow  = "Low"
+
+	metricRequestDurationSeconds = "worldtides_request_duration_seconds"
+	metricRequestErrorsTotal     = "worldtides_request_errors_total"
+)
+
+// WorldTides is an adapter for communicating with the WorldTides API.
+type WorldTides struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+
+	requestDurations *metrics.Histogram
+	requestErrors    *metrics.Counter
+}
+
+// Config holds configuration for connecting to the WorldTides API.
+type Config struct {
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// New returns a new *WorldTides using the given configuration and various options.
+func New(cfg Config, opts ...Option) *WorldTides {
+	wt := &WorldTides{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		baseURL: cfg.BaseURL,
+		apiKey:  cfg.APIKey,
+	}
+
+	for _, opt := range opts {
+		opt(wt)
+	}
+
+	return wt
+}
+
+// Option is an optional function for WorldTides.
+type Option func(*WorldTides)
+
+// WithMetrics enables instrumentation of requests sent to the WorldTides API
+// using the given registry.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(wt *WorldTides) {
+		wt.requestDurations = reg.Histogram(
+			metricRequestDurationSeconds,
+			"Duration of requests to the WorldTides API in seconds.",
+			nil,
+		)
+		wt.requestErrors = reg.Counter(
+			metricRequestErrorsTotal,
+			"Total number of failed requests to the WorldTides API.",
+		)
+	}
+}
+
+// Extremes implements tide.Source interface and fetches high and low tides at the
+// given coordinates within a day starting from the given time. Extremes of unknown
+// types or without time are skipped, and missing heights are left nil, so that
+// partial data doesn't fail the whole response.
+func (wt *WorldTides) Extremes(ctx context.Context, c geo.Coordinates, start time.Time) ([]tide.Extreme, error) {
+	begin := time.Now()
+
+	extremes, err := wt.extremes(ctx, c, start)
+
+	wt.requestDurations.Observe(time.Since(begin).Seconds())
+	if err != nil {
+		wt.requestErrors.Inc()
+	}
+
+	return extremes, err
+}
+
+func (wt *WorldTides) extremes(ctx context.Context, c geo.Coordinates, start time.Time) ([]tide.Extreme, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wt.baseURL+endpointTides, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	// The extremes parameter is a flag without a value, which url.Values can't
+	// express, so it is prepended to the encoded query.
+	q := url.Values{
+		queryParamLatitude:  []string{floatToString(c.Latitude)},
+		queryParamLongitude: []string{floatToString(c.Longitude)},
+		queryParamStart:     []string{strconv.FormatInt(start.Unix(), 10)},
+		queryParamLength:    []string{strconv.Itoa(lengthDay)},
+		queryParamKey:       []string{wt.apiKey},
+	}
+	req.URL.RawQuery = queryParamExtremes + "&" + q.Encode()
+
+	resp, err := wt.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", redactKey(err, wt.apiKey))
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unsuccessful response: %s %s", resp.Status, string(body))
+	}
+
+	var r tidesResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if r.hasError() {
+		return nil, fmt.Errorf("unsuccessful response: %d %s", r.Status, r.Error)
+	}
+
+	return r.toExtremes(), nil
+}
+
+// redactKey replaces the given API key in the error's message, since errors of
+// the HTTP client include the requested URL.
+func redactKey(err error, key string) error {
+	if key == "" || !strings.Contains(err.Error(), key) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), key, "redacted"))
+}
+
+func floatToString(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type tidesResponse struct {
+	Status   int                    `json:"status"`
+	Error    string                 `json:"error"`
+	Extremes []tidesExtremeResponse `json:"extremes"`
+}
+
+func (r tidesResponse) hasError() bool {
+	return r.Error != "" || (r.Status != 0 && r.Status != http.StatusOK)
+}
+
+func (r tidesResponse) toExtremes() []tide.Extreme {
+	extremes := make([]tide.Extreme, 0, len(r.Extremes))
+	for _, e := range r.Extremes {
+		var t string
+		switch e.Type {
+		case typeHigh:
+			t = tide.TypeHigh
+		case typeLow:
+			t = tide.TypeLow
+		default:
+			continue
+		}
+
+		if e.DT == nil {
+			continue
+		}
+
+		extremes = append(extremes, tide.Extreme{
+			Time:   time.Unix(*e.DT, 0).UTC(),
+			Type:   t,
+			Height: e.Height,
+		})
+	}
+	return extremes
+}
+
+type tidesExtremeResponse struct {
+	DT     *int64   `json:"dt"`
+	Height *float64 `json:"height"`
+	Type   string   `json:"type"`
+}
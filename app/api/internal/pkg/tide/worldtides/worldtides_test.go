@@ -0,0 +1,191 @@
+package worldtides
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/tide"
+	"github.com/ztimes2/tolqin/app/api/pkg/metrics"
+	"github.com/ztimes2/tolqin/app/api/pkg/pconv"
+)
+
+func TestWorldTides_Extremes(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		handlerFn        func(t *testing.T) http.HandlerFunc
+		expectedExtremes []tide.Extreme
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for response with non-200 http status code",
+			handlerFn: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name: "return error for response with unexpected body",
+			handlerFn: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(`<html></html>`))
+				}
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name: "return error for response with error body",
+			handlerFn: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(`{"status":400,"error":"Invalid key"}`))
+				}
+			},
+			expectedExtremes: nil,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name: "return extremes for recorded response without error",
+			handlerFn: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					b, err := ioutil.ReadFile("testdata/extremes.json")
+					if !assert.NoError(t, err) {
+						return
+					}
+					_, _ = w.Write(b)
+				}
+			},
+			expectedExtremes: []tide.Extreme{
+				{
+					Time:   time.Date(2021, 1, 1, 0, 39, 9, 0, time.UTC),
+					Type:   tide.TypeHigh,
+					Height: pconv.Float64(0.913),
+				},
+				{
+					Time:   time.Date(2021, 1, 1, 6, 44, 33, 0, time.UTC),
+					Type:   tide.TypeLow,
+					Height: pconv.Float64(-0.872),
+				},
+				{
+					Time:   time.Date(2021, 1, 1, 12, 46, 50, 0, time.UTC),
+					Type:   tide.TypeHigh,
+					Height: nil,
+				},
+				{
+					Time:   time.Date(2021, 1, 1, 18, 55, 11, 0, time.UTC),
+					Type:   tide.TypeLow,
+					Height: pconv.Float64(-0.541),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "skip extremes of unknown types and without time",
+			handlerFn: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(
+						`{
+							"status": 200,
+							"extremes": [
+								{"dt": 1609461549, "height": 0.913, "type": "Slack"},
+								{"height": -0.872, "type": "Low"},
+								{"dt": 1609505210, "height": 0.701, "type": "High"}
+							]
+						}`,
+					))
+				}
+			},
+			expectedExtremes: []tide.Extreme{
+				{
+					Time:   time.Date(2021, 1, 1, 12, 46, 50, 0, time.UTC),
+					Type:   tide.TypeHigh,
+					Height: pconv.Float64(0.701),
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, endpointTides, r.URL.Path)
+				assert.Contains(t, r.URL.Query(), queryParamExtremes)
+				assert.Equal(t, "-8.8149", r.URL.Query().Get(queryParamLatitude))
+				assert.Equal(t, "115.0884", r.URL.Query().Get(queryParamLongitude))
+				assert.Equal(t, "1609459200", r.URL.Query().Get(queryParamStart))
+				assert.Equal(t, "86400", r.URL.Query().Get(queryParamLength))
+				assert.Equal(t, "secret", r.URL.Query().Get(queryParamKey))
+				test.handlerFn(t)(w, r)
+			}))
+			defer server.Close()
+
+			wt := New(Config{
+				BaseURL: server.URL,
+				APIKey:  "secret",
+			})
+
+			extremes, err := wt.Extremes(context.Background(), geo.Coordinates{
+				Latitude:  -8.8149,
+				Longitude: 115.0884,
+			}, start)
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedExtremes, extremes)
+		})
+	}
+}
+
+func TestWorldTides_Extremes_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	wt := New(Config{
+		BaseURL: server.URL,
+		APIKey:  "secret",
+		Timeout: 10 * time.Millisecond,
+	})
+
+	_, err := wt.Extremes(context.Background(), geo.Coordinates{}, time.Now())
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.False(t, strings.Contains(err.Error(), "secret"))
+}
+
+func TestWorldTides_Extremes_Metrics(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"status":200,"extremes":[]}`))
+	}))
+	defer server.Close()
+
+	reg := metrics.NewRegistry()
+	wt := New(Config{
+		BaseURL: server.URL,
+	}, WithMetrics(reg))
+
+	_, err := wt.Extremes(context.Background(), geo.Coordinates{}, time.Now())
+	assert.Error(t, err)
+
+	statusCode = http.StatusOK
+	_, err = wt.Extremes(context.Background(), geo.Coordinates{}, time.Now())
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(2), reg.Histogram(metricRequestDurationSeconds, "", nil).Count())
+	assert.Equal(t, float64(1), reg.Counter(metricRequestErrorsTotal, "").Value())
+}
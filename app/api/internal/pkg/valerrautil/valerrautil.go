@@ -1,7 +1,11 @@
 package valerrautil
 
 import (
+	"errors"
 	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/auth"
 	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
@@ -10,6 +14,7 @@ import (
 
 const (
 	maxEmailLength = 254
+	maxPort        = 65535
 )
 
 // IsCountry returns a valerra.Condition that checks if the given string is a
@@ -36,6 +41,14 @@ func IsLongitude(lon float64) valerra.Condition {
 	}
 }
 
+// AreConsistentBounds returns a valerra.Condition that checks if the given bounds
+// are internally consistent.
+func AreConsistentBounds(b geo.Bounds) valerra.Condition {
+	return func() bool {
+		return b.Validate() == nil
+	}
+}
+
 // IsEmail returns a valerra.Condition that checks if the given string is a valid
 // e-mail address.
 func IsEmail(email string) valerra.Condition {
@@ -75,3 +88,95 @@ func IsRoleIn(role auth.Role, in ...auth.Role) valerra.Condition {
 		return false
 	}
 }
+
+// IsPort returns a valerra.Condition that checks if the given string is a valid
+// TCP port number.
+func IsPort(port string) valerra.Condition {
+	return func() bool {
+		n, err := strconv.Atoi(port)
+		return err == nil && n > 0 && n <= maxPort
+	}
+}
+
+// IsURL returns a valerra.Condition that checks if the given string is an absolute
+// URL with one of the given schemes.
+func IsURL(s string, schemes ...string) valerra.Condition {
+	return func() bool {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return false
+		}
+		for _, scheme := range schemes {
+			if u.Scheme == scheme {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IsOneOf returns a valerra.Condition that checks if the given string matches one
+// of the expected values.
+func IsOneOf(s string, in ...string) valerra.Condition {
+	return func() bool {
+		for _, v := range in {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FieldError describes a named field whose value failed validation.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// NewFieldError returns a new *FieldError.
+func NewFieldError(field, reason string) *FieldError {
+	return &FieldError{
+		Field:  field,
+		Reason: reason,
+	}
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// FieldErrors wraps *valerra.Errors and, unlike it, lists all of the underlying
+// errors in its message. It is suitable for reporting errors to operators rather
+// than to API clients, e.g. when validating configuration.
+type FieldErrors struct {
+	*valerra.Errors
+}
+
+// Error implements the error interface.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e.Errors.Errors()))
+	for i, err := range e.Errors.Errors() {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the underlying *valerra.Errors.
+func (e FieldErrors) Unwrap() error {
+	return e.Errors
+}
+
+// ValidateFields validates all the conditions registered to the given validator
+// and returns errors for the failed ones as FieldErrors.
+func ValidateFields(v *valerra.Validator) error {
+	if err := v.Validate(); err != nil {
+		var vErr *valerra.Errors
+		if errors.As(err, &vErr) {
+			return FieldErrors{Errors: vErr}
+		}
+		return err
+	}
+	return nil
+}
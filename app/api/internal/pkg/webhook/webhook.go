@@ -0,0 +1,278 @@
+// Package webhook provides notifying downstream systems about changes of spots
+// by sending signed events to their HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
+)
+
+const (
+	// HeaderSignature holds a hex-encoded HMAC-SHA256 of a request body prefixed
+	// with "sha256=", which receivers can verify using the shared secret.
+	HeaderSignature = "X-Tolqin-Signature"
+
+	// HeaderEvent holds a type of the delivered event.
+	HeaderEvent = "X-Tolqin-Event"
+
+	signaturePrefix = "sha256="
+
+	defaultQueueSize   = 100
+	defaultWorkers     = 2
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+	defaultTimeout     = 10 * time.Second
+)
+
+// Sign returns a value of the signature header for the given body signed with the
+// given secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher is an eventbus.Publisher that delivers events to endpoints in the
+// background. Events are queued onto a bounded queue, which is consumed by a pool
+// of workers that retry failed deliveries with exponential backoff. Failures are
+// logged and never returned to publishers.
+type Dispatcher struct {
+	client      *http.Client
+	urls        []string
+	secret      []byte
+	maxAttempts int
+	backoff     time.Duration
+	logger      *logrus.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan eventbus.Event
+	wg     sync.WaitGroup
+
+	// ctx is cancelled when Close gives up waiting for queued events, which
+	// interrupts deliveries that are in flight or backing off.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Config holds configuration of Dispatcher. Zero values of optional fields fall
+// back to defaults.
+type Config struct {
+	// URLs are endpoints that every event is delivered to.
+	URLs []string
+
+	// Secret is used for signing request bodies.
+	Secret string
+
+	// QueueSize caps a number of events waiting for delivery. Events published
+	// while the queue is full are dropped. It defaults to 100.
+	QueueSize int
+
+	// Workers is a number of events delivered concurrently. It defaults to 2.
+	Workers int
+
+	// MaxAttempts is a number of attempts to deliver an event to an endpoint. It
+	// defaults to 5.
+	MaxAttempts int
+
+	// Backoff is a delay before the first retry, which doubles with every next
+	// one. It defaults to 1 second.
+	Backoff time.Duration
+
+	// Timeout is a timeout of a single delivery attempt. It defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// NewDispatcher returns a new *Dispatcher using the given configuration and
+// various options, and starts its workers. Close must be called in order to stop
+// them.
+func NewDispatcher(cfg Config, opts ...Option) *Dispatcher {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	d := &Dispatcher{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		urls:        cfg.URLs,
+		secret:      []byte(cfg.Secret),
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     cfg.Backoff,
+		queue:       make(chan eventbus.Event, cfg.QueueSize),
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	d.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go d.work()
+	}
+
+	return d
+}
+
+// Option is an optional function for Dispatcher.
+type Option func(*Dispatcher)
+
+// WithLogger sets a logger for reporting failed deliveries and dropped events.
+func WithLogger(l *logrus.Logger) Option {
+	return func(d *Dispatcher) {
+		d.logger = l
+	}
+}
+
+// Publish implements eventbus.Publisher interface. It queues the given event for
+// delivery, and drops it if the queue is full or the dispatcher is closed.
+func (d *Dispatcher) Publish(e eventbus.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		d.logf(logrus.WarnLevel, e, "webhook event dropped: dispatcher is closed")
+		return
+	}
+
+	select {
+	case d.queue <- e:
+	default:
+		d.logf(logrus.WarnLevel, e, "webhook event dropped: queue is full")
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered
+// until the given context is done. After that, deliveries in flight get
+// interrupted, events left in the queue get dropped, and the context's error is
+// returned.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+
+	for e := range d.queue {
+		if d.ctx.Err() != nil {
+			d.logf(logrus.WarnLevel, e, "webhook event dropped: dispatcher is closed")
+			continue
+		}
+
+		body, err := json.Marshal(e)
+		if err != nil {
+			d.logf(logrus.ErrorLevel, e, "failed to marshal webhook event: %s", err)
+			continue
+		}
+
+		for _, u := range d.urls {
+			if err := d.deliver(u, e.Type, body); err != nil {
+				d.logf(logrus.ErrorLevel, e, "failed to deliver webhook event to %s: %s", u, err)
+			}
+		}
+	}
+}
+
+// deliver sends the given body to the given URL, and retries with exponential
+// backoff until it succeeds, runs out of attempts, or the dispatcher stops waiting
+// for deliveries.
+func (d *Dispatcher) deliver(url string, t eventbus.EventType, body []byte) error {
+	var (
+		backoff = d.backoff
+		err     error
+	)
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if err = d.send(url, t, body); err == nil {
+			return nil
+		}
+
+		if attempt < d.maxAttempts {
+			select {
+			case <-time.After(backoff):
+			case <-d.ctx.Done():
+				return fmt.Errorf("interrupted after %d attempts: %w", attempt, err)
+			}
+			backoff *= 2
+		}
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", d.maxAttempts, err)
+}
+
+func (d *Dispatcher) send(url string, t eventbus.EventType, body []byte) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(t))
+	req.Header.Set(HeaderSignature, Sign(d.secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unsuccessful response: %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *Dispatcher) logf(level logrus.Level, e eventbus.Event, format string, args ...interface{}) {
+	if d.logger == nil {
+		return
+	}
+	d.logger.
+		WithFields(logrus.Fields{
+			"event_type": e.Type,
+			"spot_id":    e.Spot.ID,
+		}).
+		Logf(level, format, args...)
+}
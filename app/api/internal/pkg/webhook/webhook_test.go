@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/eventbus"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/geo"
+	"github.com/ztimes2/tolqin/app/api/internal/pkg/surf"
+)
+
+func TestDispatcher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int32
+		received []map[string]interface{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, string(eventbus.EventSpotCreated), r.Header.Get(HeaderEvent))
+		assert.Equal(t, Sign([]byte("secret"), body), r.Header.Get(HeaderSignature))
+
+		// Fail the first two attempts in order to be retried.
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &payload))
+
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(Config{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		Workers:     1,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	})
+
+	d.Publish(eventbus.Event{
+		Type:    eventbus.EventSpotCreated,
+		ActorID: "42",
+		Spot: surf.Spot{
+			ID:   "1",
+			Name: "Spot 1",
+			Location: geo.Location{
+				Coordinates: geo.Coordinates{
+					Latitude:  1.23,
+					Longitude: 3.21,
+				},
+				Locality:    "Aktau",
+				CountryCode: "kz",
+			},
+		},
+		OccurredAt: time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC),
+	})
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"type":        "spot.created",
+			"actor_id":    "42",
+			"occurred_at": "2021-01-01T01:01:01Z",
+			"spot": map[string]interface{}{
+				"id":           "1",
+				"name":         "Spot 1",
+				"latitude":     1.23,
+				"longitude":    3.21,
+				"locality":     "Aktau",
+				"country_code": "kz",
+			},
+		},
+	}, received)
+}
+
+func TestDispatcher_GivesUp(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	d := NewDispatcher(Config{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+	}, WithLogger(logger))
+
+	d.Publish(eventbus.Event{
+		Type: eventbus.EventSpotDeleted,
+		Spot: surf.Spot{ID: "1"},
+	})
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.Contains(t, buf.String(), "failed to deliver webhook event")
+	assert.Contains(t, buf.String(), "gave up after 2 attempts")
+}
+
+func TestDispatcher_DropsEventsWhenClosed(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	d := NewDispatcher(Config{}, WithLogger(logger))
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.NotPanics(t, func() {
+		d.Publish(eventbus.Event{Type: eventbus.EventSpotUpdated})
+	})
+	assert.Contains(t, buf.String(), "dispatcher is closed")
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(
+		t,
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign([]byte("key"), []byte("The quick brown fox jumps over the lazy dog")),
+	)
+}
+
+func TestDispatcher_CloseInterruptsDeliveries(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	d := NewDispatcher(Config{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		Workers:     1,
+		MaxAttempts: 5,
+		Backoff:     time.Hour,
+	}, WithLogger(logger))
+
+	d.Publish(eventbus.Event{
+		Type: eventbus.EventSpotDeleted,
+		Spot: surf.Spot{ID: "1"},
+	})
+	d.Publish(eventbus.Event{
+		Type: eventbus.EventSpotDeleted,
+		Spot: surf.Spot{ID: "2"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := d.Close(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	assert.Contains(t, buf.String(), "interrupted after 1 attempts")
+	assert.Contains(t, buf.String(), "webhook event dropped: dispatcher is closed")
+}
@@ -0,0 +1 @@
+ALTER TABLE spots DROP COLUMN IF EXISTS region;
\ No newline at end of file
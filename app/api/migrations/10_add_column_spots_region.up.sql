@@ -0,0 +1 @@
+ALTER TABLE spots ADD COLUMN region TEXT NOT NULL DEFAULT '';
\ No newline at end of file
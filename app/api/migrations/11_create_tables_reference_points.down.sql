@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS spot_reference_distances;
+DROP TABLE IF EXISTS reference_points;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE reference_points (
+	reference_set TEXT NOT NULL,
+	name TEXT NOT NULL,
+	latitude DOUBLE PRECISION NOT NULL,
+	longitude DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (reference_set, name)
+);
+CREATE TABLE spot_reference_distances (
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	reference_set TEXT NOT NULL,
+	reference_name TEXT NOT NULL,
+	distance_km DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (spot_id, reference_set)
+);
+CREATE INDEX spot_reference_distances_reference_set_distance_km_idx ON spot_reference_distances (reference_set, distance_km);
\ No newline at end of file
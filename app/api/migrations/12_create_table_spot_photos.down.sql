@@ -0,0 +1 @@
+DROP TABLE IF EXISTS spot_photos;
\ No newline at end of file
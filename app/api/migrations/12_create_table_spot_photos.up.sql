@@ -0,0 +1,8 @@
+CREATE TABLE spot_photos (
+	id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	content_type TEXT NOT NULL,
+	size BIGINT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX spot_photos_spot_id_created_at_idx ON spot_photos (spot_id, created_at);
\ No newline at end of file
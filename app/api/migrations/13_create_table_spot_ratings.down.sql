@@ -0,0 +1 @@
+DROP TABLE IF EXISTS spot_ratings;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE spot_ratings (
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 5),
+	rater_key TEXT NOT NULL,
+	rated_on DATE NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (spot_id, rater_key, rated_on)
+);
\ No newline at end of file
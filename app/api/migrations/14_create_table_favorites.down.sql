@@ -0,0 +1 @@
+DROP TABLE IF EXISTS favorites;
\ No newline at end of file
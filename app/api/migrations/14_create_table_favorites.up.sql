@@ -0,0 +1,7 @@
+CREATE TABLE favorites (
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	favorited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, spot_id)
+);
+CREATE INDEX favorites_user_id_favorited_at_idx ON favorites (user_id, favorited_at);
\ No newline at end of file
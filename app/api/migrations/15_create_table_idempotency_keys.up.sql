@@ -0,0 +1,5 @@
+CREATE TABLE idempotency_keys (
+	key TEXT PRIMARY KEY NOT NULL,
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
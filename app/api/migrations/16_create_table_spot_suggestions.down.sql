@@ -0,0 +1 @@
+DROP TABLE IF EXISTS spot_suggestions;
\ No newline at end of file
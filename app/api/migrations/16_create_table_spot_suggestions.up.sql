@@ -0,0 +1,15 @@
+CREATE TABLE spot_suggestions (
+	id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'rejected')),
+	name TEXT,
+	latitude NUMERIC,
+	longitude NUMERIC,
+	locality TEXT,
+	country_code TEXT,
+	region TEXT,
+	note TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	resolved_at TIMESTAMP
+);
+CREATE INDEX spot_suggestions_status_created_at_idx ON spot_suggestions (status, created_at);
\ No newline at end of file
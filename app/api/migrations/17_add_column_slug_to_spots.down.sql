@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS spots_slug_key;
+ALTER TABLE spots DROP COLUMN IF EXISTS slug;
\ No newline at end of file
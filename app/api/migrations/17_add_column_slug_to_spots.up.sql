@@ -0,0 +1,2 @@
+ALTER TABLE spots ADD COLUMN slug TEXT;
+CREATE UNIQUE INDEX spots_slug_key ON spots (slug);
\ No newline at end of file
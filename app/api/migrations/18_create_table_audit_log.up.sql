@@ -0,0 +1,10 @@
+CREATE TABLE audit_log (
+	id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	actor_id TEXT NOT NULL,
+	action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+	spot_id UUID NOT NULL,
+	changes JSONB NOT NULL DEFAULT '[]',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX audit_log_spot_id_created_at_idx ON audit_log (spot_id, created_at);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
\ No newline at end of file
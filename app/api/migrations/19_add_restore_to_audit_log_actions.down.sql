@@ -0,0 +1,3 @@
+DELETE FROM audit_log WHERE action = 'restore';
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check CHECK (action IN ('create', 'update', 'delete'));
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS spots_slug_key;
+UPDATE spots SET slug = NULL WHERE deleted_at IS NOT NULL AND EXISTS (SELECT 1 FROM spots AS other WHERE other.slug = spots.slug AND other.id <> spots.id);
+CREATE UNIQUE INDEX spots_slug_key ON spots (slug);
\ No newline at end of file
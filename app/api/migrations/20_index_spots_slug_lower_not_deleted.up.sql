@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS spots_slug_key;
+CREATE UNIQUE INDEX spots_slug_key ON spots (lower(slug)) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE spots ADD COLUMN external_ref TEXT UNIQUE;
@@ -0,0 +1 @@
+ALTER TABLE spots DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1 @@
+ALTER TABLE spots ADD COLUMN deleted_at TIMESTAMP;
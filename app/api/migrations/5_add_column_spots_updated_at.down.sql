@@ -0,0 +1 @@
+ALTER TABLE spots DROP COLUMN IF EXISTS updated_at;
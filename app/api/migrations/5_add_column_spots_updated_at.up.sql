@@ -0,0 +1,2 @@
+ALTER TABLE spots ADD COLUMN updated_at TIMESTAMP;
+ALTER TABLE spots ALTER COLUMN updated_at SET DEFAULT NOW();
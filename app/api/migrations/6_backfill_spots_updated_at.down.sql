@@ -0,0 +1 @@
+ALTER TABLE spots ALTER COLUMN updated_at DROP NOT NULL;
@@ -0,0 +1,2 @@
+UPDATE spots SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE spots ALTER COLUMN updated_at SET NOT NULL;
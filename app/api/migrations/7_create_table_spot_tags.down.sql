@@ -0,0 +1 @@
+DROP TABLE IF EXISTS spot_tags;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE spot_tags (
+	spot_id UUID NOT NULL REFERENCES spots (id) ON DELETE CASCADE,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (spot_id, tag)
+);
+CREATE INDEX spot_tags_tag_idx ON spot_tags (tag);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE spots ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+	to_tsvector('simple', name || ' ' || locality)
+) STORED;
+CREATE INDEX spots_search_vector_idx ON spots USING GIN (search_vector);
\ No newline at end of file
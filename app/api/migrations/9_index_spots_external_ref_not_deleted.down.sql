@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS spots_external_ref_key;
+ALTER TABLE spots ADD CONSTRAINT spots_external_ref_key UNIQUE (external_ref);
\ No newline at end of file
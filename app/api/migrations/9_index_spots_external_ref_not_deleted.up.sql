@@ -0,0 +1,2 @@
+ALTER TABLE spots DROP CONSTRAINT IF EXISTS spots_external_ref_key;
+CREATE UNIQUE INDEX spots_external_ref_key ON spots (external_ref) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,9 @@
+// Package migrations embeds SQL migrations of the database schema.
+package migrations
+
+import "embed"
+
+// FS holds migration files named {version}_{title}.up.sql and {version}_{title}.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,264 @@
+// Package client provides a typed client for the HTTP API of the application.
+//
+// The client expects successful responses to be wrapped in the data envelope,
+// which is the default behavior of the API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	endpointToken           = "/auth/v1/token"
+	endpointSurfingSpots    = "/surfing/v1/spots"
+	endpointManagementSpots = "/management/v1/spots"
+	endpointLocation        = "/management/v1/geo/location"
+
+	headerAuthorization = "Authorization"
+	headerContentType   = "Content-Type"
+
+	contentTypeJSON = "application/json"
+)
+
+// Client is a client of the HTTP API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// Config holds configuration for connecting to the HTTP API.
+type Config struct {
+	BaseURL string
+
+	// Token is an optional JWT that is sent as a bearer token with every request.
+	// It is required for management endpoints.
+	Token string
+
+	Timeout time.Duration
+}
+
+// New returns a new *Client using the given configuration.
+func New(cfg Config) *Client {
+	return &Client{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		token:   cfg.Token,
+	}
+}
+
+// Token issues an access token for a user with the given credentials.
+func (c *Client) Token(ctx context.Context, email, password string) (string, error) {
+	payload := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{
+		Email:    email,
+		Password: password,
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := c.do(ctx, http.MethodPost, endpointToken, nil, payload, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.AccessToken, nil
+}
+
+// Spot returns a spot by the given ID.
+func (c *Client) Spot(ctx context.Context, id string) (Spot, error) {
+	var s Spot
+	if err := c.do(ctx, http.MethodGet, endpointSurfingSpots+"/"+url.PathEscape(id), nil, nil, &s); err != nil {
+		return Spot{}, err
+	}
+	return s, nil
+}
+
+// SpotsOptions holds optional parameters for listing spots. Zero values are
+// omitted, so that the API falls back to its defaults.
+type SpotsOptions struct {
+	Limit       int
+	Offset      int
+	CountryCode string
+	Query       string
+
+	// SearchMode is either "substring" or "fulltext".
+	SearchMode string
+
+	Tags []string
+
+	// TagsMatch is either "all" or "any".
+	TagsMatch string
+}
+
+func (o SpotsOptions) values() url.Values {
+	q := make(url.Values)
+	if o.Limit != 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset != 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.CountryCode != "" {
+		q.Set("country", o.CountryCode)
+	}
+	if o.Query != "" {
+		q.Set("query", o.Query)
+	}
+	if o.SearchMode != "" {
+		q.Set("search_mode", o.SearchMode)
+	}
+	if len(o.Tags) > 0 {
+		q.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if o.TagsMatch != "" {
+		q.Set("tags_match", o.TagsMatch)
+	}
+	return q
+}
+
+// Spots returns a list of spots using the given options.
+func (c *Client) Spots(ctx context.Context, o SpotsOptions) ([]Spot, error) {
+	var resp struct {
+		Items []Spot `json:"items"`
+	}
+	if err := c.do(ctx, http.MethodGet, endpointSurfingSpots, o.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// CreateSpotParams holds parameters for creating a spot.
+type CreateSpotParams struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Locality    string  `json:"locality"`
+	CountryCode string  `json:"country_code"`
+}
+
+// CreateSpot creates a spot using the given parameters. It requires a token of
+// an admin.
+func (c *Client) CreateSpot(ctx context.Context, p CreateSpotParams) (Spot, error) {
+	var s Spot
+	if err := c.do(ctx, http.MethodPost, endpointManagementSpots, nil, p, &s); err != nil {
+		return Spot{}, err
+	}
+	return s, nil
+}
+
+// UpdateSpotParams holds parameters for updating a spot. Nil fields are left
+// unchanged.
+type UpdateSpotParams struct {
+	Name        *string  `json:"name,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Locality    *string  `json:"locality,omitempty"`
+	CountryCode *string  `json:"country_code,omitempty"`
+	Region      *string  `json:"region,omitempty"`
+}
+
+// UpdateSpot updates a spot by the given ID using the given parameters. It
+// requires a token of an admin.
+func (c *Client) UpdateSpot(ctx context.Context, id string, p UpdateSpotParams) (Spot, error) {
+	var s Spot
+	if err := c.do(ctx, http.MethodPatch, endpointManagementSpots+"/"+url.PathEscape(id), nil, p, &s); err != nil {
+		return Spot{}, err
+	}
+	return s, nil
+}
+
+// DeleteSpot deletes a spot by the given ID. It requires a token of an admin.
+func (c *Client) DeleteSpot(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, endpointManagementSpots+"/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Location looks up a location by the given coordinates using the geocoder of
+// the API. It requires a token of an admin.
+func (c *Client) Location(ctx context.Context, lat, lon float64) (Location, error) {
+	q := url.Values{
+		"lat": []string{strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lon": []string{strconv.FormatFloat(lon, 'f', -1, 64)},
+	}
+
+	var l Location
+	if err := c.do(ctx, http.MethodGet, endpointLocation, q, nil, &l); err != nil {
+		return Location{}, err
+	}
+	return l, nil
+}
+
+// do sends a request with the given method, path, query, and payload, and decodes
+// data of a successful response into dest unless it is nil. An unsuccessful
+// response is returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, q url.Values, payload, dest interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := c.baseURL + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	if payload != nil {
+		req.Header.Set(headerContentType, contentTypeJSON)
+	}
+	if c.token != "" {
+		req.Header.Set(headerAuthorization, "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if dest == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: dest,
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(token string, h http.HandlerFunc) (*Client, func()) {
+	server := httptest.NewServer(h)
+	return New(Config{
+		BaseURL: server.URL + "/",
+		Token:   token,
+	}), server.Close
+}
+
+func writeBody(w http.ResponseWriter, status int, body string) {
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+func TestClient_Token(t *testing.T) {
+	c, closeFn := newTestClient("", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, endpointToken, r.URL.Path)
+		assert.Equal(t, contentTypeJSON, r.Header.Get(headerContentType))
+		assert.Empty(t, r.Header.Get(headerAuthorization))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"email":"admin@tolqin.kz","password":"secret"}`, string(body))
+
+		writeBody(w, http.StatusOK, `{"data":{"access_token":"token"}}`)
+	})
+	defer closeFn()
+
+	token, err := c.Token(context.Background(), "admin@tolqin.kz", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
+
+func TestClient_Spots(t *testing.T) {
+	c, closeFn := newTestClient("", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, endpointSurfingSpots, r.URL.Path)
+		assert.Equal(t, "country=kz&limit=5&tags=reef%2Cpoint", r.URL.RawQuery)
+
+		writeBody(w, http.StatusOK, `{
+			"data": {
+				"items": [
+					{
+						"id": "1",
+						"name": "Spot 1",
+						"latitude": 1.23,
+						"longitude": 3.21,
+						"locality": "Aktau",
+						"country_code": "kz",
+						"country_name": "Kazakhstan",
+						"tags": ["reef"]
+					}
+				]
+			}
+		}`)
+	})
+	defer closeFn()
+
+	spots, err := c.Spots(context.Background(), SpotsOptions{
+		Limit:       5,
+		CountryCode: "kz",
+		Tags:        []string{"reef", "point"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Spot{
+		{
+			ID:          "1",
+			Name:        "Spot 1",
+			Latitude:    1.23,
+			Longitude:   3.21,
+			Locality:    "Aktau",
+			CountryCode: "kz",
+			CountryName: "Kazakhstan",
+			Tags:        []string{"reef"},
+		},
+	}, spots)
+}
+
+func TestClient_Spot(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedSpot  Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return api error for unexisting spot",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeBody(w, http.StatusNotFound, `{
+					"error": {
+						"code": "not_found",
+						"description": "Such spot doesn't exist."
+					}
+				}`)
+			},
+			expectedSpot: Spot{},
+			expectedErrFn: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var apiErr *APIError
+				if !assert.True(t, errors.As(err, &apiErr)) {
+					return false
+				}
+				return assert.True(t, apiErr.IsNotFound()) && assert.Equal(t, &APIError{
+					StatusCode:  http.StatusNotFound,
+					Code:        CodeNotFound,
+					Description: "Such spot doesn't exist.",
+				}, apiErr)
+			},
+		},
+		{
+			name: "return spot without error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, endpointSurfingSpots+"/1", r.URL.Path)
+				writeBody(w, http.StatusOK, `{"data":{"id":"1","name":"Spot 1","updated_at":"2021-01-01T01:01:01Z"}}`)
+			},
+			expectedSpot: Spot{
+				ID:        "1",
+				Name:      "Spot 1",
+				UpdatedAt: "2021-01-01T01:01:01Z",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, closeFn := newTestClient("", test.handler)
+			defer closeFn()
+
+			spot, err := c.Spot(context.Background(), "1")
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestClient_CreateSpot(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedSpot  Spot
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return api error with fields for invalid input",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeBody(w, http.StatusBadRequest, `{
+					"error": {
+						"code": "invalid_input",
+						"description": "Invalid input parameters.",
+						"fields": [
+							{
+								"key": "latitude",
+								"reason": "Must be a valid latitude."
+							}
+						]
+					}
+				}`)
+			},
+			expectedSpot: Spot{},
+			expectedErrFn: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var apiErr *APIError
+				if !assert.True(t, errors.As(err, &apiErr)) {
+					return false
+				}
+				return assert.True(t, apiErr.IsInvalidInput()) && assert.Equal(t, &APIError{
+					StatusCode:  http.StatusBadRequest,
+					Code:        CodeInvalidInput,
+					Description: "Invalid input parameters.",
+					Fields: []FieldError{
+						{
+							Key:    "latitude",
+							Reason: "Must be a valid latitude.",
+						},
+					},
+				}, apiErr)
+			},
+		},
+		{
+			name: "return api error for unauthorized request",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeBody(w, http.StatusUnauthorized, `{
+					"error": {
+						"code": "unauthorized",
+						"description": "Invalid token."
+					}
+				}`)
+			},
+			expectedSpot: Spot{},
+			expectedErrFn: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var apiErr *APIError
+				if !assert.True(t, errors.As(err, &apiErr)) {
+					return false
+				}
+				return assert.Equal(t, &APIError{
+					StatusCode:  http.StatusUnauthorized,
+					Code:        "unauthorized",
+					Description: "Invalid token.",
+				}, apiErr)
+			},
+		},
+		{
+			name: "return api error for response without error envelope",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte("Unauthorized"))
+			},
+			expectedSpot: Spot{},
+			expectedErrFn: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var apiErr *APIError
+				if !assert.True(t, errors.As(err, &apiErr)) {
+					return false
+				}
+				return assert.Equal(t, &APIError{
+					StatusCode:  http.StatusUnauthorized,
+					Description: "Unauthorized",
+				}, apiErr)
+			},
+		},
+		{
+			name: "return created spot without error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, endpointManagementSpots, r.URL.Path)
+				assert.Equal(t, "Bearer token", r.Header.Get(headerAuthorization))
+
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(
+					t,
+					`{"name":"Spot 1","latitude":1.23,"longitude":3.21,"locality":"Aktau","country_code":"kz"}`,
+					string(body),
+				)
+
+				writeBody(w, http.StatusCreated, `{"data":{"id":"1","name":"Spot 1"}}`)
+			},
+			expectedSpot: Spot{
+				ID:   "1",
+				Name: "Spot 1",
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, closeFn := newTestClient("token", test.handler)
+			defer closeFn()
+
+			spot, err := c.CreateSpot(context.Background(), CreateSpotParams{
+				Name:        "Spot 1",
+				Latitude:    1.23,
+				Longitude:   3.21,
+				Locality:    "Aktau",
+				CountryCode: "kz",
+			})
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedSpot, spot)
+		})
+	}
+}
+
+func TestClient_UpdateSpot(t *testing.T) {
+	c, closeFn := newTestClient("token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, endpointManagementSpots+"/1", r.URL.Path)
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Spot 2"}`, string(body))
+
+		writeBody(w, http.StatusOK, `{"data":{"id":"1","name":"Spot 2"}}`)
+	})
+	defer closeFn()
+
+	name := "Spot 2"
+	spot, err := c.UpdateSpot(context.Background(), "1", UpdateSpotParams{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, Spot{ID: "1", Name: "Spot 2"}, spot)
+}
+
+func TestClient_DeleteSpot(t *testing.T) {
+	c, closeFn := newTestClient("token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, endpointManagementSpots+"/1", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer closeFn()
+
+	assert.NoError(t, c.DeleteSpot(context.Background(), "1"))
+}
+
+func TestClient_Location(t *testing.T) {
+	c, closeFn := newTestClient("token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, endpointLocation, r.URL.Path)
+		assert.Equal(t, "lat=43.65&lon=51.17", r.URL.RawQuery)
+
+		writeBody(w, http.StatusOK, `{
+			"data": {
+				"latitude": 43.65,
+				"longitude": 51.17,
+				"locality": "Aktau",
+				"country_code": "kz"
+			}
+		}`)
+	})
+	defer closeFn()
+
+	l, err := c.Location(context.Background(), 43.65, 51.17)
+	assert.NoError(t, err)
+	assert.Equal(t, Location{
+		Latitude:    43.65,
+		Longitude:   51.17,
+		Locality:    "Aktau",
+		CountryCode: "kz",
+	}, l)
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Spot is a surfing spot.
+type Spot struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+	Locality    string   `json:"locality"`
+	CountryCode string   `json:"country_code"`
+	CountryName string   `json:"country_name"`
+	Region      string   `json:"region"`
+	Tags        []string `json:"tags"`
+
+	// Slug is empty for spots that have none.
+	Slug string `json:"slug"`
+
+	// UpdatedAt and DeletedAt are formatted in RFC3339. They are empty when unknown
+	// or not applicable.
+	UpdatedAt string `json:"updated_at"`
+	DeletedAt string `json:"deleted_at"`
+
+	// AverageRating and RatingsCount are zero when the spot has no ratings.
+	AverageRating float64 `json:"average_rating"`
+	RatingsCount  int     `json:"ratings_count"`
+
+	// Favorited is only reported for clients that carry an access token.
+	Favorited bool `json:"favorited"`
+}
+
+// Location is a geographical location.
+type Location struct {
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Locality    string  `json:"locality"`
+	CountryCode string  `json:"country_code"`
+	CountryName string  `json:"country_name"`
+}
+
+// Error codes returned by the API.
+const (
+	CodeInvalidInput = "invalid_input"
+	CodeNotFound     = "not_found"
+	CodeUnavailable  = "unavailable"
+	CodeUnexpected   = "unexpected"
+)
+
+// APIError is an error returned by the API.
+type APIError struct {
+	// StatusCode is an HTTP status code of the response.
+	StatusCode int
+
+	Code        string
+	Description string
+
+	// Fields are only populated for validation errors that concern specific input
+	// fields.
+	Fields []FieldError
+}
+
+// FieldError describes why a certain input field is invalid.
+type FieldError struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// Error implements error interface.
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("api error: %d %s", e.StatusCode, e.Description)
+	}
+	return fmt.Sprintf("api error: %d %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+// IsInvalidInput reports whether the error is caused by invalid input.
+func (e *APIError) IsInvalidInput() bool {
+	return e.Code == CodeInvalidInput
+}
+
+// IsNotFound reports whether the error is caused by a missing resource.
+func (e *APIError) IsNotFound() bool {
+	return e.Code == CodeNotFound
+}
+
+// newAPIError returns a new *APIError decoded from the given response body. Bodies
+// without the error envelope, such as ones written by proxies, are described with
+// the status text.
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+	}
+
+	var resp struct {
+		Error *struct {
+			Code        string       `json:"code"`
+			Description string       `json:"description"`
+			Fields      []FieldError `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error == nil {
+		e.Description = http.StatusText(statusCode)
+		return e
+	}
+
+	e.Code = resp.Error.Code
+	e.Description = resp.Error.Description
+	e.Fields = resp.Error.Fields
+	return e
+}
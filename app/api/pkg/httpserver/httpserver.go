@@ -2,6 +2,9 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,15 +13,28 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme/autocert"
 )
 
+// ErrIncompleteTLSConfig is used when only one of TLS certificate and key files
+// is provided.
+var ErrIncompleteTLSConfig = errors.New("both tls certificate and key files must be provided")
+
 // Server is a lightweight wrapper around a standard http.Server that additionally
 // enables automatic graceful shutdown during server errors and logging.
 type Server struct {
 	logger          *logrus.Logger
 	shutdownTimeout time.Duration
+	shutdownFuncs   []func(context.Context) error
+
+	tlsCertFile string
+	tlsKeyFile  string
+	autoTLS     bool
+
+	http2Disabled bool
 
 	server   *http.Server
+	conns    *connTracker
 	isClosed *syncBool
 	closeCh  chan struct{}
 }
@@ -35,14 +51,22 @@ func New(port string, h http.Handler, opts ...Option) *Server {
 			// TODO configure timeouts
 		},
 		logger:   logrus.StandardLogger(),
+		conns:    newConnTracker(),
 		closeCh:  make(chan struct{}, 1),
 		isClosed: newBool(false),
 	}
+	s.server.ConnState = s.conns.track
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	// HTTP/2 is disabled only after all options are applied, since TLS options
+	// may replace the TLS config that has to stop advertising it.
+	if s.http2Disabled {
+		s.disableHTTP2()
+	}
+
 	return s
 }
 
@@ -50,13 +74,26 @@ func New(port string, h http.Handler, opts ...Option) *Server {
 type Option func(*Server)
 
 // WithShutdownTimeout sets a shutdown timeout for Server as long as the duration
-// is greater than 0. Values less than 1 are interpreted as if no shutdown is desired.
+// is greater than 0. Values less than 1 are interpreted as if no shutdown timeout
+// is desired.
+//
+// Once the timeout elapses, connections that are still active get forcibly closed.
 func WithShutdownTimeout(d time.Duration) Option {
 	return func(s *Server) {
 		s.shutdownTimeout = d
 	}
 }
 
+// WithShutdownFunc registers a function that is called once Server has stopped
+// serving requests, e.g. in order to flush background work. The function is given
+// the context of the shutdown, so it shares whatever is left of the shutdown
+// timeout. Functions are called in the order of registration.
+func WithShutdownFunc(fn func(context.Context) error) Option {
+	return func(s *Server) {
+		s.shutdownFuncs = append(s.shutdownFuncs, fn)
+	}
+}
+
 // WithLogger sets a custom *logrus.Logger for Server.
 func WithLogger(l *logrus.Logger) Option {
 	return func(s *Server) {
@@ -64,7 +101,101 @@ func WithLogger(l *logrus.Logger) Option {
 	}
 }
 
+// WithTLS makes Server serve HTTPS requests using the given certificate and key
+// files. Server refuses to start if only one of them is provided.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tlsCertFile = certFile
+		s.tlsKeyFile = keyFile
+	}
+}
+
+// WithAutoTLS makes Server serve HTTPS requests using certificates that are
+// automatically obtained from Let's Encrypt for the given hosts. Certificates
+// get cached in the given directory so that they survive restarts.
+//
+// Certificates are obtained via the TLS-ALPN-01 challenge, therefore Server must
+// be reachable on port 443.
+func WithAutoTLS(cacheDir string, hosts ...string) Option {
+	return func(s *Server) {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cacheDir),
+			HostPolicy: autocert.HostWhitelist(hosts...),
+		}
+		s.server.TLSConfig = m.TLSConfig()
+		s.autoTLS = true
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 for Server. HTTP/2 is negotiated only over
+// TLS and is enabled by default, as in the standard http.Server.
+//
+// During a graceful shutdown, HTTP/2 connections are notified to stop opening new
+// streams and get closed once they become idle.
+func WithHTTP2(enabled bool) Option {
+	return func(s *Server) {
+		s.http2Disabled = !enabled
+	}
+}
+
+// WithKeepAlives enables or disables HTTP keep-alives for Server. Keep-alives are
+// enabled by default. Disabling them makes every connection serve a single request,
+// which evens out load across instances behind a connection-level load balancer at
+// the cost of extra handshakes.
+func WithKeepAlives(enabled bool) Option {
+	return func(s *Server) {
+		s.server.SetKeepAlivesEnabled(enabled)
+	}
+}
+
+// WithIdleTimeout sets the maximum amount of time Server waits for the next request
+// on an idle keep-alive connection before closing it, for both HTTP/1.1 and HTTP/2.
+// Values less than 1 leave the standard behavior, which falls back to the read
+// timeout.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		if d > 0 {
+			s.server.IdleTimeout = d
+		}
+	}
+}
+
+// disableHTTP2 stops the server from both serving and advertising HTTP/2 during
+// TLS handshakes. A non-nil empty TLSNextProto map is the standard way of turning
+// off the automatic HTTP/2 support of http.Server.
+func (s *Server) disableHTTP2() {
+	s.server.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+
+	if s.server.TLSConfig == nil {
+		return
+	}
+
+	cfg := s.server.TLSConfig.Clone()
+	protos := make([]string, 0, len(cfg.NextProtos))
+	for _, p := range cfg.NextProtos {
+		if p != "h2" {
+			protos = append(protos, p)
+		}
+	}
+	cfg.NextProtos = protos
+	s.server.TLSConfig = cfg
+}
+
+func (s *Server) isTLS() bool {
+	return s.autoTLS || s.tlsCertFile != "" || s.tlsKeyFile != ""
+}
+
+func (s *Server) listenAndServe() error {
+	if s.isTLS() {
+		return s.server.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+	}
+	return s.server.ListenAndServe()
+}
+
 // ListenAndServe spins up a server and starts accepting/serving HTTP requests.
+// HTTPS requests are served instead when TLS is configured via WithTLS or
+// WithAutoTLS.
 //
 // It keeps running until a server error is caught, syscall.SIGTERM/syscall.SIGINT
 // signals are triggered, or Close() method is envoked. The server gracefully
@@ -74,10 +205,20 @@ func (s *Server) ListenAndServe() error {
 		return http.ErrServerClosed
 	}
 
-	errCh := make(chan error)
+	if !s.autoTLS && (s.tlsCertFile == "") != (s.tlsKeyFile == "") {
+		return ErrIncompleteTLSConfig
+	}
+
+	return s.run(func() error {
+		s.logger.WithField("tls", s.isTLS()).Infof("server is listening on %s", s.server.Addr)
+		return s.listenAndServe()
+	})
+}
+
+func (s *Server) run(serveFn func() error) error {
+	errCh := make(chan error, 1)
 	go func() {
-		s.logger.Infof("server is listening on %s", s.server.Addr)
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := serveFn(); err != nil && err != http.ErrServerClosed {
 			errCh <- err
 		}
 	}()
@@ -114,10 +255,20 @@ func (s *Server) shutdown() error {
 	}
 	defer cancel()
 
-	if err := s.server.Shutdown(ctx); err != nil {
-		return err
+	err := s.server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.logger.
+			WithField("active_connections", s.conns.active()).
+			Warnf("shutdown timeout of %s elapsed, forcibly closing remaining connections", s.shutdownTimeout)
+	}
+
+	for _, fn := range s.shutdownFuncs {
+		if fnErr := fn(ctx); fnErr != nil {
+			s.logger.WithError(fnErr).Errorf("failed to run shutdown function: %v", fnErr)
+		}
 	}
-	return nil
+
+	return err
 }
 
 // Close tells the server to gracefully shut itself down if it's running.
@@ -131,6 +282,44 @@ func (s *Server) Close() {
 	}
 }
 
+// connTracker keeps track of states of connections accepted by the server.
+type connTracker struct {
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{
+		states: make(map[net.Conn]http.ConnState),
+	}
+}
+
+func (ct *connTracker) track(c net.Conn, state http.ConnState) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(ct.states, c)
+	default:
+		ct.states[c] = state
+	}
+}
+
+// active returns the number of connections that are currently serving requests.
+func (ct *connTracker) active() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	var n int
+	for _, state := range ct.states {
+		if state == http.StateActive {
+			n++
+		}
+	}
+	return n
+}
+
 type syncBool struct {
 	b  bool
 	mu sync.Mutex
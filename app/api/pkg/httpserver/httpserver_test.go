@@ -0,0 +1,256 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	const shutdownTimeout = 100 * time.Millisecond
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		assert.Fail(t, err.Error())
+		return
+	}
+
+	startedCh := make(chan struct{})
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+
+	slowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(startedCh)
+		<-releaseCh
+	})
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+
+	s := New("0", slowHandler, WithShutdownTimeout(shutdownTimeout), WithLogger(logger))
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- s.run(func() error {
+			return s.server.Serve(l)
+		})
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + l.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	select {
+	case <-startedCh:
+	case <-time.After(time.Second):
+		assert.Fail(t, "request did not reach handler")
+		return
+	}
+
+	start := time.Now()
+	s.Close()
+
+	select {
+	case err := <-doneCh:
+		assert.NoError(t, err)
+	case <-time.After(shutdownTimeout + time.Second):
+		assert.Fail(t, "server did not shut down within timeout")
+		return
+	}
+
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(shutdownTimeout))
+	assert.Contains(t, logs.String(), "active_connections=1")
+}
+
+func TestServer_ShutdownFunc(t *testing.T) {
+	const shutdownTimeout = 100 * time.Millisecond
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		assert.Fail(t, err.Error())
+		return
+	}
+
+	var (
+		calls    []string
+		deadline time.Time
+	)
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+
+	s := New(
+		"0",
+		http.NotFoundHandler(),
+		WithShutdownTimeout(shutdownTimeout),
+		WithLogger(logger),
+		WithShutdownFunc(func(ctx context.Context) error {
+			calls = append(calls, "first")
+			deadline, _ = ctx.Deadline()
+			return errors.New("something went wrong")
+		}),
+		WithShutdownFunc(func(ctx context.Context) error {
+			calls = append(calls, "second")
+			return nil
+		}),
+	)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- s.run(func() error {
+			return s.server.Serve(l)
+		})
+	}()
+
+	start := time.Now()
+	s.Close()
+
+	select {
+	case err := <-doneCh:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "server did not shut down")
+		return
+	}
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+	assert.WithinDuration(t, start.Add(shutdownTimeout), deadline, shutdownTimeout)
+	assert.Contains(t, logs.String(), "failed to run shutdown function: something went wrong")
+}
+
+func TestServer_ListenAndServe_IncompleteTLSConfig(t *testing.T) {
+	s := New("0", http.NotFoundHandler(), WithTLS("cert.pem", ""))
+
+	err := s.ListenAndServe()
+	assert.ErrorIs(t, err, ErrIncompleteTLSConfig)
+}
+
+func TestNew_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		assertFn func(t *testing.T, s *http.Server)
+	}{
+		{
+			name: "keep standard behavior by default",
+			opts: nil,
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.Nil(t, s.TLSNextProto)
+				assert.Zero(t, s.IdleTimeout)
+			},
+		},
+		{
+			name: "keep http/2 enabled",
+			opts: []Option{WithHTTP2(true)},
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.Nil(t, s.TLSNextProto)
+			},
+		},
+		{
+			name: "disable http/2",
+			opts: []Option{WithHTTP2(false)},
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.NotNil(t, s.TLSNextProto)
+				assert.Empty(t, s.TLSNextProto)
+			},
+		},
+		{
+			name: "stop advertising http/2 regardless of order of options",
+			opts: []Option{WithHTTP2(false), WithAutoTLS(t.TempDir(), "example.com")},
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.NotNil(t, s.TLSNextProto)
+				assert.NotContains(t, s.TLSConfig.NextProtos, "h2")
+				assert.Contains(t, s.TLSConfig.NextProtos, "http/1.1")
+			},
+		},
+		{
+			name: "set idle timeout",
+			opts: []Option{WithIdleTimeout(5 * time.Second)},
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.Equal(t, 5*time.Second, s.IdleTimeout)
+			},
+		},
+		{
+			name: "ignore non positive idle timeout",
+			opts: []Option{WithIdleTimeout(-time.Second)},
+			assertFn: func(t *testing.T, s *http.Server) {
+				assert.Zero(t, s.IdleTimeout)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := New("0", http.NotFoundHandler(), test.opts...)
+			test.assertFn(t, s.server)
+		})
+	}
+}
+
+func TestServer_KeepAlives(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		expectedClose bool
+	}{
+		{
+			name:          "keep connections alive by default",
+			opts:          nil,
+			expectedClose: false,
+		},
+		{
+			name:          "close connections after every request",
+			opts:          []Option{WithKeepAlives(false)},
+			expectedClose: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				assert.Fail(t, err.Error())
+				return
+			}
+
+			logger := logrus.New()
+			logger.SetOutput(ioutil.Discard)
+
+			opts := append([]Option{WithLogger(logger)}, test.opts...)
+			s := New("0", http.NotFoundHandler(), opts...)
+
+			doneCh := make(chan error, 1)
+			go func() {
+				doneCh <- s.run(func() error {
+					return s.server.Serve(l)
+				})
+			}()
+			defer func() {
+				s.Close()
+				<-doneCh
+			}()
+
+			resp, err := http.Get("http://" + l.Addr().String())
+			if err != nil {
+				assert.Fail(t, err.Error())
+				return
+			}
+			resp.Body.Close()
+
+			assert.Equal(t, test.expectedClose, resp.Close)
+		})
+	}
+}
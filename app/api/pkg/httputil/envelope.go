@@ -0,0 +1,23 @@
+package httputil
+
+import "context"
+
+type dataEnvelopeKey struct{}
+
+// ContextWithDataEnvelope returns a copy of the given context that controls
+// whether successful responses are wrapped in the top-level {"data": ...} object.
+// Error responses always keep their {"error": ...} shape.
+func ContextWithDataEnvelope(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, dataEnvelopeKey{}, enabled)
+}
+
+// DataEnvelopeFromContext reports whether successful responses should be wrapped
+// in the top-level {"data": ...} object. It defaults to true when the context
+// doesn't say otherwise.
+func DataEnvelopeFromContext(ctx context.Context) bool {
+	enabled, ok := ctx.Value(dataEnvelopeKey{}).(bool)
+	if !ok {
+		return true
+	}
+	return enabled
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // QueryParam retrieves a query parameter from the given request by the given key.
@@ -51,3 +53,48 @@ func QueryParamFloat(r *http.Request, key string) (float64, error) {
 
 	return f, nil
 }
+
+// QueryParamBool retrieves a query parameter from the given request by the given
+// key and parses it as a boolean. ErrParamNotFound error is returned if the query
+// parameter is not found.
+func QueryParamBool(r *http.Request, key string) (bool, error) {
+	v := QueryParam(r, key)
+	if v == "" {
+		return false, ErrParamNotFound
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, err
+	}
+
+	return b, nil
+}
+
+// QueryParamTime retrieves a query parameter from the given request by the given
+// key and parses it as a time formatted in RFC3339. ErrParamNotFound error is
+// returned if the query parameter is not found.
+func QueryParamTime(r *http.Request, key string) (time.Time, error) {
+	v := QueryParam(r, key)
+	if v == "" {
+		return time.Time{}, ErrParamNotFound
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t, nil
+}
+
+// QueryParamList retrieves a query parameter from the given request by the given
+// key and splits it into a list of comma-separated values. Nil is returned if the
+// query parameter is not found.
+func QueryParamList(r *http.Request, key string) []string {
+	v := QueryParam(r, key)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
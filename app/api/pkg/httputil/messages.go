@@ -0,0 +1,21 @@
+package httputil
+
+import "context"
+
+type messagesKey struct{}
+
+// ContextWithMessages returns a copy of the given context that carries translations
+// of error descriptions and field reasons, keyed by their original English text.
+// Error responses written with such a context use the translations, whereas
+// messages without a translation are written in English as is.
+func ContextWithMessages(ctx context.Context, messages map[string]string) context.Context {
+	return context.WithValue(ctx, messagesKey{}, messages)
+}
+
+func translate(ctx context.Context, msg string) string {
+	messages, _ := ctx.Value(messagesKey{}).(map[string]string)
+	if translated, ok := messages[msg]; ok {
+		return translated
+	}
+	return msg
+}
@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -25,7 +26,19 @@ func write(w http.ResponseWriter, r *http.Request, statusCode int, resp response
 }
 
 func writeData(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) {
-	write(w, r, statusCode, response{Data: data})
+	if DataEnvelopeFromContext(r.Context()) {
+		write(w, r, statusCode, response{Data: data})
+		return
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		WriteUnexpectedError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
 }
 
 func writeError(w http.ResponseWriter, r *http.Request, statusCode int, errResp interface{}) {
@@ -35,19 +48,47 @@ func writeError(w http.ResponseWriter, r *http.Request, statusCode int, errResp
 // WriteError writes an error to the response using the given HTTP status code,
 // error code, and error description.
 func WriteError(w http.ResponseWriter, r *http.Request, statusCode int, errCode, errDesc string) {
-	writeError(w, r, statusCode, newErrorResponse(errCode, errDesc))
+	writeError(w, r, statusCode, newErrorResponse(errCode, translate(r.Context(), errDesc)))
 }
 
+// StatusClientClosedRequest is a non-standard HTTP status code used when a client
+// closes a request before a response is written.
+const StatusClientClosedRequest = 499
+
 // WriteUnexpectedError writes a 500 Internal Server Error HTTP status code and
 // an error using 'unexpected' error code and the static unexpected error description
 // to the response. The given error gets additionally logged.
+//
+// Context errors are not treated as unexpected since they are usually caused by a
+// client disconnecting or by a request running out of time. context.Canceled is
+// written as 499 Client Closed Request and logged at debug level, whereas
+// context.DeadlineExceeded is written as 503 Service Unavailable and logged at
+// warning level.
+//
+// Fields of errors that implement log.FieldsError, such as SQLSTATE of failed
+// queries, are logged along with them.
 func WriteUnexpectedError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		if logger := log.FromContext(r.Context()); logger != nil {
+			logger.WithError(err).Debugf("request canceled: %s", err)
+		}
+		writeError(w, r, StatusClientClosedRequest, newErrorResponse("canceled", translate(r.Context(), "Request was canceled.")))
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		if logger := log.FromContext(r.Context()); logger != nil {
+			logger.WithError(err).Warnf("request timed out: %s", err)
+		}
+		writeError(w, r, http.StatusServiceUnavailable, newErrorResponse("timeout", translate(r.Context(), "Request timed out.")))
+		return
+	}
+
 	if logger := log.FromContext(r.Context()); logger != nil {
-		logger.WithError(err).Errorf("unexpected error: %s", err)
+		log.WithError(logger, err).Errorf("unexpected error: %s", err)
 	}
 
 	body, _ := json.Marshal(response{
-		Error: newErrorResponse("unexpected", "Something went wrong..."),
+		Error: newErrorResponse("unexpected", translate(r.Context(), "Something went wrong...")),
 	})
 
 	w.WriteHeader(http.StatusInternalServerError)
@@ -70,17 +111,39 @@ func WriteCreated(w http.ResponseWriter, r *http.Request, data interface{}) {
 	writeData(w, r, http.StatusCreated, data)
 }
 
+// WriteMultiStatus writes a 207 Multi-Status HTTP status code and the given data
+// to the response. It is meant for operations on multiple items whose outcomes
+// differ.
+func WriteMultiStatus(w http.ResponseWriter, r *http.Request, data interface{}) {
+	writeData(w, r, http.StatusMultiStatus, data)
+}
+
+// NewFieldErrorsBody returns an error body using 'invalid_input' error code, the
+// static invalid input error description, and the given invalid fields. It is
+// meant for embedding errors of individual items into response data of the given
+// request.
+func NewFieldErrorsBody(r *http.Request, f *InvalidFields) interface{} {
+	return newFieldErrorResponse(r.Context(), f)
+}
+
+// NewErrorBody returns an error body using the given error code and error
+// description. Like NewFieldErrorsBody, it is meant for embedding errors of
+// individual items into response data of the given request.
+func NewErrorBody(r *http.Request, errCode, errDesc string) interface{} {
+	return newErrorResponse(errCode, translate(r.Context(), errDesc))
+}
+
 // WriteValidationError writes a 400 Bad Request HTTP status code and an error
 // using 'invalid_input' error code and the given description to the response.
 func WriteValidationError(w http.ResponseWriter, r *http.Request, desc string) {
-	writeError(w, r, http.StatusBadRequest, newValidationErrorResponse(desc))
+	writeError(w, r, http.StatusBadRequest, newValidationErrorResponse(translate(r.Context(), desc)))
 }
 
 // WriteFieldErrors writes a 400 Bad Request HTTP status code and an error using
 // 'invalid_input' error code, the static invalid input error description, and
 // the given invalid fields to the response.
 func WriteFieldErrors(w http.ResponseWriter, r *http.Request, f *InvalidFields) {
-	writeError(w, r, http.StatusBadRequest, newFieldErrorResponse(f))
+	writeError(w, r, http.StatusBadRequest, newFieldErrorResponse(r.Context(), f))
 }
 
 // WriteFieldError writes a 400 Bad Request HTTP status code and an error using
@@ -98,12 +161,49 @@ func WritePayloadError(w http.ResponseWriter, r *http.Request) {
 	WriteValidationError(w, r, "Invalid payload.")
 }
 
+// WritePayloadTooLargeError writes a 413 Request Entity Too Large HTTP status code
+// and an error using 'payload_too_large' error code and the static payload too
+// large error description.
+func WritePayloadTooLargeError(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, http.StatusRequestEntityTooLarge, "payload_too_large", "Payload is too large.")
+}
+
+// WriteUnauthorizedError writes a 401 Unauthorized HTTP status code and an error
+// using 'unauthorized' error code and the given error description to the response.
+func WriteUnauthorizedError(w http.ResponseWriter, r *http.Request, desc string) {
+	WriteError(w, r, http.StatusUnauthorized, "unauthorized", desc)
+}
+
 // WriteNotFoundError writes a 404 Not Found HTTP status code and an error using
 // 'not_found' error code and the given error description to the response.
 func WriteNotFoundError(w http.ResponseWriter, r *http.Request, desc string) {
 	WriteError(w, r, http.StatusNotFound, "not_found", desc)
 }
 
+// WriteConflictError writes a 409 Conflict HTTP status code and an error using
+// 'conflict' error code and the given error description to the response.
+func WriteConflictError(w http.ResponseWriter, r *http.Request, desc string) {
+	WriteError(w, r, http.StatusConflict, "conflict", desc)
+}
+
+// WriteUpstreamTimeoutError writes a 504 Gateway Timeout HTTP status code and an
+// error using 'upstream_timeout' error code and the given error description to the
+// response. It is meant for dependencies failing to respond in time, as opposed
+// to the request itself running out of time.
+func WriteUpstreamTimeoutError(w http.ResponseWriter, r *http.Request, desc string) {
+	WriteError(w, r, http.StatusGatewayTimeout, "upstream_timeout", desc)
+}
+
+// WriteUnavailableError writes a 503 Service Unavailable HTTP status code and an
+// error using 'unavailable' error code, the given error description, and the given
+// names of unavailable dependencies to the response.
+func WriteUnavailableError(w http.ResponseWriter, r *http.Request, desc string, dependencies []string) {
+	writeError(w, r, http.StatusServiceUnavailable, unavailableErrorResponse{
+		errorResponse: newErrorResponse("unavailable", translate(r.Context(), desc)),
+		Dependencies:  dependencies,
+	})
+}
+
 type errorResponse struct {
 	Code        string `json:"code"`
 	Description string `json:"description"`
@@ -116,6 +216,11 @@ func newErrorResponse(code, desc string) errorResponse {
 	}
 }
 
+type unavailableErrorResponse struct {
+	errorResponse
+	Dependencies []string `json:"dependencies"`
+}
+
 type validationErrorResponse struct {
 	errorResponse
 	Fields []validationErrorResponseField `json:"fields"`
@@ -133,11 +238,14 @@ func newValidationErrorResponse(desc string) validationErrorResponse {
 	}
 }
 
-func newFieldErrorResponse(f *InvalidFields) validationErrorResponse {
-	resp := newValidationErrorResponse("Invalid input parameters.")
+func newFieldErrorResponse(ctx context.Context, f *InvalidFields) validationErrorResponse {
+	resp := newValidationErrorResponse(translate(ctx, "Invalid input parameters."))
 
 	for _, field := range f.fields {
-		resp.Fields = append(resp.Fields, validationErrorResponseField(field))
+		resp.Fields = append(resp.Fields, validationErrorResponseField{
+			Key:    field.Key,
+			Reason: translate(ctx, field.Reason),
+		})
 	}
 
 	return resp
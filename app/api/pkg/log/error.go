@@ -0,0 +1,28 @@
+package log
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldsError is implemented by errors that carry details worth logging as separate
+// fields, such as a code of a failed database query.
+type FieldsError interface {
+	error
+	LogFields() map[string]interface{}
+}
+
+// WithError returns a copy of the given log entry with the given error attached
+// to it. Fields of the first error in the chain of the given one that implements
+// FieldsError are attached as well.
+func WithError(l *logrus.Entry, err error) *logrus.Entry {
+	l = l.WithError(err)
+
+	var fErr FieldsError
+	if errors.As(err, &fErr) {
+		l = l.WithFields(fErr.LogFields())
+	}
+
+	return l
+}
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldsError struct {
+	code string
+}
+
+func (e fieldsError) Error() string {
+	return "something went wrong"
+}
+
+func (e fieldsError) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"code": e.code,
+	}
+}
+
+func TestWithError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedFields logrus.Fields
+	}{
+		{
+			name: "attach error",
+			err:  errors.New("something went wrong"),
+			expectedFields: logrus.Fields{
+				logrus.ErrorKey: errors.New("something went wrong"),
+			},
+		},
+		{
+			name: "attach error along with its fields",
+			err:  fmt.Errorf("failed to execute query: %w", fieldsError{code: "23505"}),
+			expectedFields: logrus.Fields{
+				logrus.ErrorKey: fmt.Errorf("failed to execute query: %w", fieldsError{code: "23505"}),
+				"code":          "23505",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := WithError(logrus.NewEntry(logrus.New()), test.err)
+			assert.Equal(t, test.expectedFields, entry.Data)
+		})
+	}
+}
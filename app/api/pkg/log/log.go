@@ -16,8 +16,8 @@ const (
 	FormatText = "text"
 )
 
-// New initializes a new logger using the given level and format.
-func New(level, format string) (*logrus.Logger, error) {
+// New initializes a new logger using the given level, format, and various options.
+func New(level, format string, opts ...Option) (*logrus.Logger, error) {
 	logger := logrus.New()
 
 	lvl, err := logrus.ParseLevel(level)
@@ -41,11 +41,51 @@ func New(level, format string) (*logrus.Logger, error) {
 		return nil, fmt.Errorf("invalid log format: %q", format)
 	}
 
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.sampling != nil {
+		formatter = newSamplingFormatter(formatter, o.sampling.limit, o.sampling.interval)
+	}
+
 	logger.SetFormatter(formatter)
 
 	return logger, nil
 }
 
+// Option is an optional function for New.
+type Option func(*options)
+
+type options struct {
+	sampling *samplingOptions
+}
+
+type samplingOptions struct {
+	limit    int
+	interval time.Duration
+}
+
+// WithWarnSampling limits identical warning-level entries to the given number per
+// the given interval in order to prevent bursts of them from flooding the output.
+// Entries are identical when they share a message and names of their fields. The
+// first entry written after suppression carries the number of entries suppressed
+// before it in the "suppressed" field. Entries of other levels are never sampled.
+// A non-positive limit disables sampling.
+func WithWarnSampling(limit int, interval time.Duration) Option {
+	return func(o *options) {
+		if limit <= 0 || interval <= 0 {
+			o.sampling = nil
+			return
+		}
+		o.sampling = &samplingOptions{
+			limit:    limit,
+			interval: interval,
+		}
+	}
+}
+
 type contextKey struct{}
 
 var keyLogEntry contextKey = struct{}{}
@@ -14,6 +14,7 @@ func TestNewLogger(t *testing.T) {
 		name             string
 		level            string
 		format           string
+		opts             []Option
 		expectedLoggerFn func(*testing.T, *logrus.Logger)
 		expectedErrFn    assert.ErrorAssertionFunc
 	}{
@@ -51,6 +52,48 @@ func TestNewLogger(t *testing.T) {
 			},
 			expectedErrFn: assert.NoError,
 		},
+		{
+			name:   "return logger with sampling json formatter",
+			level:  logrus.InfoLevel.String(),
+			format: FormatJSON,
+			opts: []Option{
+				WithWarnSampling(10, time.Second),
+			},
+			expectedLoggerFn: func(t *testing.T, l *logrus.Logger) {
+				f, ok := l.Formatter.(*samplingFormatter)
+				if !assert.True(t, ok) {
+					return
+				}
+				assert.Equal(t, 10, f.limit)
+				assert.Equal(t, time.Second, f.interval)
+				assert.Equal(
+					t,
+					&logrus.JSONFormatter{
+						TimestampFormat: time.RFC3339Nano,
+					},
+					f.formatter,
+				)
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:   "return logger without sampling for zero limit",
+			level:  logrus.InfoLevel.String(),
+			format: FormatText,
+			opts: []Option{
+				WithWarnSampling(0, time.Second),
+			},
+			expectedLoggerFn: func(t *testing.T, l *logrus.Logger) {
+				assert.Equal(
+					t,
+					&logrus.TextFormatter{
+						TimestampFormat: time.RFC3339Nano,
+					},
+					l.Formatter,
+				)
+			},
+			expectedErrFn: assert.NoError,
+		},
 		{
 			name:   "return logger with text formatter",
 			level:  logrus.InfoLevel.String(),
@@ -71,7 +114,7 @@ func TestNewLogger(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			logger, err := New(test.level, test.format)
+			logger, err := New(test.level, test.format, test.opts...)
 			test.expectedErrFn(t, err)
 			test.expectedLoggerFn(t, logger)
 		})
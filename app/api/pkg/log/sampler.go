@@ -0,0 +1,124 @@
+package log
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldSuppressed holds a number of identical entries that were suppressed by
+// sampling before the entry that carries it.
+const FieldSuppressed = "suppressed"
+
+// samplingFormatter is a logrus.Formatter that formats warning-level entries
+// using another formatter only until their limit per interval is reached, and
+// formats the rest into nothing, which is how they get suppressed since logrus
+// has no other means to drop entries.
+type samplingFormatter struct {
+	formatter logrus.Formatter
+	limit     int
+	interval  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	samples   map[string]*sample
+	lastPrune time.Time
+}
+
+type sample struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+func newSamplingFormatter(f logrus.Formatter, limit int, interval time.Duration) *samplingFormatter {
+	return &samplingFormatter{
+		formatter: f,
+		limit:     limit,
+		interval:  interval,
+		now:       time.Now,
+		samples:   make(map[string]*sample),
+	}
+}
+
+// Format implements logrus.Formatter interface.
+func (f *samplingFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	if e.Level != logrus.WarnLevel {
+		return f.formatter.Format(e)
+	}
+
+	suppressed, ok := f.allow(signature(e))
+	if !ok {
+		return nil, nil
+	}
+
+	if suppressed > 0 {
+		withSuppressed := *e
+		withSuppressed.Data = make(logrus.Fields, len(e.Data)+1)
+		for k, v := range e.Data {
+			withSuppressed.Data[k] = v
+		}
+		withSuppressed.Data[FieldSuppressed] = suppressed
+		return f.formatter.Format(&withSuppressed)
+	}
+
+	return f.formatter.Format(e)
+}
+
+// allow reports whether an entry with the given signature can be written along
+// with a number of identical entries suppressed during the previous interval.
+func (f *samplingFormatter) allow(key string) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	f.prune(now)
+
+	var suppressed int
+	s, ok := f.samples[key]
+	if !ok || now.Sub(s.windowStart) >= f.interval {
+		if ok {
+			suppressed = s.suppressed
+		}
+		s = &sample{windowStart: now}
+		f.samples[key] = s
+	}
+
+	s.count++
+	if s.count > f.limit {
+		s.suppressed++
+		return 0, false
+	}
+
+	return suppressed, true
+}
+
+// prune forgets signatures whose intervals have passed without suppressing any
+// entries, so that the samples don't grow indefinitely.
+func (f *samplingFormatter) prune(now time.Time) {
+	if now.Sub(f.lastPrune) < f.interval {
+		return
+	}
+	f.lastPrune = now
+
+	for key, s := range f.samples {
+		if s.suppressed == 0 && now.Sub(s.windowStart) >= f.interval {
+			delete(f.samples, key)
+		}
+	}
+}
+
+// signature returns a key that identical entries share regardless of values of
+// their fields.
+func signature(e *logrus.Entry) string {
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return e.Message + "\x00" + strings.Join(keys, ",")
+}
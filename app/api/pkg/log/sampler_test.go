@@ -0,0 +1,80 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingFormatter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+
+	f := newSamplingFormatter(&logrus.JSONFormatter{DisableTimestamp: true}, 2, time.Second)
+	f.now = func() time.Time { return now }
+	logger.SetFormatter(f)
+
+	for i := 0; i < 5; i++ {
+		logger.WithField("path", i).Warn("bad request")
+		logger.WithField("id", i).Warn("bad request")
+		logger.WithField("path", i).Error("bad request")
+	}
+
+	now = now.Add(time.Second)
+	logger.WithField("path", 5).Warn("bad request")
+
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e map[string]interface{}
+		if !assert.NoError(t, dec.Decode(&e)) {
+			return
+		}
+		entries = append(entries, e)
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "warning", "msg": "bad request", "path": float64(0)},
+		{"level": "warning", "msg": "bad request", "id": float64(0)},
+		{"level": "error", "msg": "bad request", "path": float64(0)},
+		{"level": "warning", "msg": "bad request", "path": float64(1)},
+		{"level": "warning", "msg": "bad request", "id": float64(1)},
+		{"level": "error", "msg": "bad request", "path": float64(1)},
+		{"level": "error", "msg": "bad request", "path": float64(2)},
+		{"level": "error", "msg": "bad request", "path": float64(3)},
+		{"level": "error", "msg": "bad request", "path": float64(4)},
+		{"level": "warning", "msg": "bad request", "path": float64(5), "suppressed": float64(3)},
+	}, entries)
+}
+
+func TestSamplingFormatter_Prune(t *testing.T) {
+	now := time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	f := newSamplingFormatter(&logrus.TextFormatter{}, 1, time.Second)
+	f.now = func() time.Time { return now }
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	logger.SetFormatter(f)
+
+	logger.Warn("a")
+	logger.Warn("b")
+	logger.Warn("b")
+	assert.Len(t, f.samples, 2)
+
+	// Signatures that suppressed nothing are forgotten once their interval passes,
+	// whereas the rest are kept until their suppressed entries are reported.
+	now = now.Add(time.Second)
+	logger.Info("c")
+	logger.Warn("d")
+	assert.Len(t, f.samples, 2)
+	assert.Contains(t, f.samples, "b\x00")
+	assert.Contains(t, f.samples, "d\x00")
+}
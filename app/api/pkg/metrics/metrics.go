@@ -0,0 +1,431 @@
+/*
+Package metrics provides a minimal registry of counters and histograms that can
+be exposed in the Prometheus text exposition format.
+
+Metrics are registered in a *Registry rather than in a global one, so that the
+registry can be injected into components and inspected in tests:
+
+	reg := metrics.NewRegistry()
+	requests := reg.Counter("requests_total", "Total number of requests.", "method")
+	requests.Inc("GET")
+
+	http.Handle("/metrics", metrics.Handler(reg))
+
+Methods of a nil *Counter and a nil *Histogram are no-ops, which allows
+components to be instrumented optionally without additional checks.
+*/
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets holds default upper bounds of histogram buckets that are suitable
+// for measuring durations in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const (
+	kindCounter   = "counter"
+	kindHistogram = "histogram"
+)
+
+// Registry holds registered metrics.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	kind() string
+	labelNames() []string
+	write(w io.Writer, name string) error
+}
+
+// NewRegistry returns a new empty *Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]metric),
+	}
+}
+
+// Counter registers a counter with the given name and label names, and returns it.
+// If a counter with the same name and label names is already registered, the
+// existing one is returned. It panics if the name is already taken by a different
+// metric.
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return r.register(name, kindCounter, labelNames, func() metric {
+		return &Counter{
+			help:   help,
+			labels: labelNames,
+			values: make(map[string]*counterValue),
+		}
+	}).(*Counter)
+}
+
+// Histogram registers a histogram with the given name, bucket upper bounds and
+// label names, and returns it. DefaultBuckets are used when buckets are empty.
+// If a histogram with the same name and label names is already registered, the
+// existing one is returned. It panics if the name is already taken by a different
+// metric.
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return r.register(name, kindHistogram, labelNames, func() metric {
+		return &Histogram{
+			help:    help,
+			labels:  labelNames,
+			buckets: buckets,
+			values:  make(map[string]*histogramValue),
+		}
+	}).(*Histogram)
+}
+
+func (r *Registry) register(name, kind string, labelNames []string, newFn func() metric) metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		if m.kind() != kind || !equalStrings(m.labelNames(), labelNames) {
+			panic(fmt.Sprintf("metrics: %q is already registered with a different type or labels", name))
+		}
+		return m
+	}
+
+	m := newFn()
+	r.metrics[name] = m
+	return m
+}
+
+// Write writes all registered metrics to the given writer in the Prometheus text
+// exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make(map[string]metric, len(r.metrics))
+	for name, m := range r.metrics {
+		metrics[name] = m
+	}
+	r.mu.Unlock()
+
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		if err := metrics[name].write(bw, name); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns an HTTP handler that exposes metrics of the given registry in
+// the Prometheus text exposition format.
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.Write(w)
+	})
+}
+
+// Counter is a metric that holds monotonically increasing values partitioned by
+// labels.
+type Counter struct {
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// Inc increments the counter with the given label values by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter with the given label values by the given value. It
+// panics if the value is negative or the number of label values does not match
+// the number of label names.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if c == nil {
+		return
+	}
+	if v < 0 {
+		panic("metrics: counter cannot be decreased")
+	}
+	checkLabelValues(c.labels, labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labelKey(labelValues)
+	cv, ok := c.values[key]
+	if !ok {
+		cv = &counterValue{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = cv
+	}
+	cv.value += v
+}
+
+// Value returns the current value of the counter with the given label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cv, ok := c.values[labelKey(labelValues)]; ok {
+		return cv.value
+	}
+	return 0
+}
+
+func (c *Counter) kind() string         { return kindCounter }
+func (c *Counter) labelNames() []string { return c.labels }
+
+func (c *Counter) write(w io.Writer, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := writeHeader(w, name, c.help, kindCounter); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(c.values) {
+		cv := c.values[key]
+		_, err := fmt.Fprintf(w, "%s%s %s\n",
+			name, formatLabels(c.labels, cv.labelValues, "", ""), formatFloat(cv.value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Histogram is a metric that samples observations into configurable buckets
+// partitioned by labels.
+type Histogram struct {
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	values map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// Observe adds a single observation to the histogram with the given label values.
+// It panics if the number of label values does not match the number of label
+// names.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+	checkLabelValues(h.labels, labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelKey(labelValues)
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = hv
+	}
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+// Count returns the total number of observations of the histogram with the given
+// label values.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	if h == nil {
+		return 0
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hv, ok := h.values[labelKey(labelValues)]; ok {
+		return hv.count
+	}
+	return 0
+}
+
+func (h *Histogram) kind() string         { return kindHistogram }
+func (h *Histogram) labelNames() []string { return h.labels }
+
+func (h *Histogram) write(w io.Writer, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := writeHeader(w, name, h.help, kindHistogram); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(h.values) {
+		hv := h.values[key]
+
+		for i, upper := range h.buckets {
+			_, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+				name, formatLabels(h.labels, hv.labelValues, "le", formatFloat(upper)), hv.counts[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+			name, formatLabels(h.labels, hv.labelValues, "le", "+Inf"), hv.count)
+		if err != nil {
+			return err
+		}
+
+		labels := formatLabels(h.labels, hv.labelValues, "", "")
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(hv.sum)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count%s %d\n", name, labels, hv.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name, help, kind string) error {
+	if help != "" {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+	return err
+}
+
+func checkLabelValues(names, values []string) {
+	if len(names) != len(values) {
+		panic(fmt.Sprintf("metrics: expected %d label value(s), got %d", len(names), len(values)))
+	}
+}
+
+func labelKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*counterValue:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*histogramValue:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(values[i]))
+		b.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraName)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(extraValue))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
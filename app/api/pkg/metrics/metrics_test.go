@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.Counter("requests_total", "Total number of requests.", "method")
+
+	c.Inc("GET")
+	c.Inc("GET")
+	c.Add(3, "POST")
+
+	assert.Equal(t, float64(2), c.Value("GET"))
+	assert.Equal(t, float64(3), c.Value("POST"))
+	assert.Equal(t, float64(0), c.Value("DELETE"))
+
+	assert.Panics(t, func() { c.Add(-1, "GET") })
+	assert.Panics(t, func() { c.Inc() })
+}
+
+func TestHistogram(t *testing.T) {
+	reg := NewRegistry()
+	h := reg.Histogram("duration_seconds", "Duration.", []float64{1, 0.5}, "method")
+
+	h.Observe(0.2, "GET")
+	h.Observe(0.7, "GET")
+	h.Observe(2, "GET")
+
+	assert.Equal(t, uint64(3), h.Count("GET"))
+	assert.Equal(t, uint64(0), h.Count("POST"))
+	assert.Panics(t, func() { h.Observe(1) })
+}
+
+func TestNilMetrics(t *testing.T) {
+	var (
+		c *Counter
+		h *Histogram
+	)
+
+	assert.NotPanics(t, func() {
+		c.Inc("GET")
+		h.Observe(1, "GET")
+	})
+	assert.Equal(t, float64(0), c.Value("GET"))
+	assert.Equal(t, uint64(0), h.Count("GET"))
+}
+
+func TestRegistry_Register(t *testing.T) {
+	reg := NewRegistry()
+
+	c1 := reg.Counter("requests_total", "", "method")
+	c2 := reg.Counter("requests_total", "", "method")
+	assert.Same(t, c1, c2)
+
+	assert.Panics(t, func() { reg.Counter("requests_total", "", "route") })
+	assert.Panics(t, func() { reg.Histogram("requests_total", "", nil, "method") })
+}
+
+func TestRegistry_Write(t *testing.T) {
+	reg := NewRegistry()
+	reg.Counter("requests_total", "Total number of requests.", "method", "path").
+		Inc("GET", `/a"b\c`)
+	reg.Histogram("duration_seconds", "Duration.", []float64{0.5, 1}).
+		Observe(0.7)
+
+	var buf bytes.Buffer
+	assert.NoError(t, reg.Write(&buf))
+
+	expected := `# HELP duration_seconds Duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{le="0.5"} 0
+duration_seconds_bucket{le="1"} 1
+duration_seconds_bucket{le="+Inf"} 1
+duration_seconds_sum 0.7
+duration_seconds_count 1
+# HELP requests_total Total number of requests.
+# TYPE requests_total counter
+requests_total{method="GET",path="/a\"b\\c"} 1
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	reg.Counter("requests_total", "").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "# TYPE requests_total counter\nrequests_total 1\n", rec.Body.String())
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+// Handler returns an http.Handler that serves the given document as JSON. The
+// document is encoded once, so it must not be modified afterwards.
+func Handler(d *Document) http.Handler {
+	body, err := json.Marshal(d)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
+
+// swaggerUIVersion is the version of the swagger-ui-dist package that DocsHandler
+// loads from a CDN.
+const swaggerUIVersion = "5.17.14"
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "{{.SpecURL}}", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// DocsHandler returns an http.Handler that serves a minimal Swagger UI page with
+// the given title, which renders the document served at the given URL. Assets of
+// Swagger UI are loaded from a CDN by the browser.
+func DocsHandler(title, specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = docsTemplate.Execute(w, struct {
+			Title   string
+			Version string
+			SpecURL string
+		}{
+			Title:   title,
+			Version: swaggerUIVersion,
+			SpecURL: specURL,
+		})
+	})
+}
@@ -0,0 +1,279 @@
+// Package openapi provides types for building OpenAPI 3 documents in Go, along
+// with HTTP handlers for serving them.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification that documents conform to.
+const Version = "3.0.3"
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+}
+
+// NewDocument returns a new *Document with the given title and version of an API.
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]PathItem),
+	}
+}
+
+// AddOperation describes an operation of the given HTTP method at the given path.
+// Path parameters are expected in the {name} form.
+func (d *Document) AddOperation(method, path string, op Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Operation returns an operation of the given HTTP method at the given path, and
+// reports whether the document describes it.
+func (d *Document) Operation(method, path string) (Operation, bool) {
+	op, ok := d.Paths[path][strings.ToLower(method)]
+	return op, ok
+}
+
+// AddSecurityScheme adds a security scheme with the given name that operations
+// can refer to in their security requirements.
+func (d *Document) AddSecurityScheme(name string, s SecurityScheme) {
+	if d.Components == nil {
+		d.Components = &Components{}
+	}
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = make(map[string]SecurityScheme)
+	}
+	d.Components.SecuritySchemes[name] = s
+}
+
+// Info holds metadata of an API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds operations available at a single path keyed by lowercase HTTP
+// methods.
+type PathItem map[string]Operation
+
+// Operation describes a single API operation at a path.
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Parameter locations.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+// Parameter describes a single parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes a request body of an operation.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds a schema of a certain media type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// JSON returns content of the application/json media type with the given schema.
+func JSON(s *Schema) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+// Components holds reusable objects of a document.
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes a security scheme that operations can use.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement lists names of security schemes with their scopes that are
+// required by an operation.
+type SecurityRequirement map[string][]string
+
+// Schema describes a data type.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// String returns a schema of a string.
+func String() *Schema { return &Schema{Type: "string"} }
+
+// Integer returns a schema of an integer.
+func Integer() *Schema { return &Schema{Type: "integer"} }
+
+// Number returns a schema of a floating point number.
+func Number() *Schema { return &Schema{Type: "number", Format: "double"} }
+
+// Boolean returns a schema of a boolean.
+func Boolean() *Schema { return &Schema{Type: "boolean"} }
+
+// ArrayOf returns a schema of an array of the given items.
+func ArrayOf(items *Schema) *Schema { return &Schema{Type: "array", Items: items} }
+
+// Object returns a schema of an object with the given required properties.
+func Object(properties map[string]*Schema) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: properties,
+	}
+	for name := range properties {
+		s.Required = append(s.Required, name)
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// SchemaOf returns a schema of the JSON representation of the given value, which
+// is derived from its type the same way encoding/json does. Fields of structs
+// become required properties unless they are tagged with omitempty, pointer fields
+// become nullable, and fields of embedded structs are inlined. Interfaces and
+// json.RawMessage are described with an empty schema that allows any value.
+func SchemaOf(v interface{}) *Schema {
+	return schemaOf(reflect.TypeOf(v))
+}
+
+func schemaOf(t reflect.Type) *Schema {
+	if t == nil || t == rawMessageType {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := schemaOf(t.Elem())
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.Slice, reflect.Array:
+		return ArrayOf(schemaOf(t.Elem()))
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: schemaOf(t.Elem()),
+		}
+	case reflect.Struct:
+		s := &Schema{
+			Type:       "object",
+			Properties: make(map[string]*Schema),
+		}
+		addProperties(s, t)
+		sort.Strings(s.Required)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+func addProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addProperties(s, f.Type)
+			continue
+		}
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = schemaOf(f.Type)
+		if !opts.omitEmpty {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+type tagOptions struct {
+	omitEmpty bool
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+
+	var opts tagOptions
+	for _, p := range parts[1:] {
+		if p == "omitempty" {
+			opts.omitEmpty = true
+		}
+	}
+	return parts[0], opts
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_Operation(t *testing.T) {
+	d := NewDocument("API", "v1")
+	d.AddOperation(http.MethodGet, "/spots/{id}", Operation{Summary: "Read a spot"})
+
+	op, ok := d.Operation(http.MethodGet, "/spots/{id}")
+	assert.True(t, ok)
+	assert.Equal(t, "Read a spot", op.Summary)
+
+	_, ok = d.Operation(http.MethodPost, "/spots/{id}")
+	assert.False(t, ok)
+
+	_, ok = d.Operation(http.MethodGet, "/spots")
+	assert.False(t, ok)
+}
+
+func TestSchemaOf(t *testing.T) {
+	type embedded struct {
+		ID string `json:"id"`
+	}
+
+	type value struct {
+		embedded
+
+		Name     string            `json:"name"`
+		Rating   float64           `json:"rating"`
+		Count    int               `json:"count"`
+		Active   bool              `json:"active"`
+		Tags     []string          `json:"tags,omitempty"`
+		Note     *string           `json:"note"`
+		Labels   map[string]string `json:"labels"`
+		Extra    json.RawMessage   `json:"extra,omitempty"`
+		Any      interface{}       `json:"any,omitempty"`
+		Skipped  string            `json:"-"`
+		Untagged string
+		hidden   string
+	}
+
+	expected := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":     String(),
+			"name":   String(),
+			"rating": Number(),
+			"count":  Integer(),
+			"active": Boolean(),
+			"tags":   ArrayOf(String()),
+			"note":   {Type: "string", Nullable: true},
+			"labels": {
+				Type:                 "object",
+				AdditionalProperties: String(),
+			},
+			"extra":    {},
+			"any":      {},
+			"Untagged": String(),
+		},
+		Required: []string{"Untagged", "active", "count", "id", "labels", "name", "note", "rating"},
+	}
+
+	assert.Equal(t, expected, SchemaOf(value{hidden: ""}))
+	assert.Equal(t, ArrayOf(String()), SchemaOf([]string{}))
+}
+
+func TestObject(t *testing.T) {
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"b": String(),
+			"a": Integer(),
+		},
+		Required: []string{"a", "b"},
+	}, Object(map[string]*Schema{
+		"b": String(),
+		"a": Integer(),
+	}))
+}
+
+func TestHandler(t *testing.T) {
+	d := NewDocument("API", "v1")
+	d.AddOperation(http.MethodGet, "/spots", Operation{
+		Responses: map[string]Response{
+			"200": {Description: "OK"},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	Handler(d).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(
+		t,
+		`{"openapi":"3.0.3","info":{"title":"API","version":"v1"},"paths":{"/spots":{"get":{"responses":{"200":{"description":"OK"}}}}}}`,
+		rec.Body.String(),
+	)
+}
+
+func TestDocsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DocsHandler("API", "/openapi.json").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<title>API</title>")
+	assert.Contains(t, rec.Body.String(), `url: "\/openapi.json"`)
+}
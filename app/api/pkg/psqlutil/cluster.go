@@ -0,0 +1,153 @@
+package psqlutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Queryer executes read-only queries.
+type Queryer interface {
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+}
+
+// Cluster is a database handle that consists of a primary and zero or more read
+// replicas. Queries of Cluster are routed to the replicas in round-robin order,
+// whereas statements and transactions always go to the primary.
+//
+// Replicas are expected to lag behind the primary, so reads that must observe
+// preceding writes should be made through Primary.
+type Cluster struct {
+	primary  *sqlx.DB
+	replicas []*sqlx.DB
+	next     uint32
+}
+
+// NewCluster opens a new *Cluster using the given database driver name, primary
+// and replica configurations, and other options. The options are applied to the
+// primary and every replica as in NewDB.
+func NewCluster(driverName string, primary Config, replicas []Config, opts ...Option) (*Cluster, error) {
+	return NewClusterContext(context.Background(), driverName, primary, replicas, opts...)
+}
+
+// NewClusterContext is the same as NewCluster, except that retries of connecting
+// to the databases stop once the given context is done.
+func NewClusterContext(
+	ctx context.Context,
+	driverName string,
+	primary Config,
+	replicas []Config,
+	opts ...Option) (*Cluster, error) {
+
+	p, err := NewDBContext(ctx, driverName, primary, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cluster{
+		primary: p,
+	}
+
+	for _, cfg := range replicas {
+		r, err := NewDBContext(ctx, driverName, cfg, opts...)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.replicas = append(c.replicas, r)
+	}
+
+	return c, nil
+}
+
+// Primary returns a handle of the primary database.
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// QueryxContext executes a query on the next replica. Replicas that are unreachable
+// are skipped, and the primary is used once all of them have been tried.
+func (c *Cluster) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	for _, r := range c.nextReplicas() {
+		rows, err := r.QueryxContext(ctx, query, args...)
+		if isUnreachable(err) {
+			continue
+		}
+		return rows, err
+	}
+	return c.primary.QueryxContext(ctx, query, args...)
+}
+
+// QueryRowxContext executes a query that is expected to return at most one row on
+// the next replica. Replicas are tried and skipped the same way as in QueryxContext.
+func (c *Cluster) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	for _, r := range c.nextReplicas() {
+		row := r.QueryRowxContext(ctx, query, args...)
+		if isUnreachable(row.Err()) {
+			continue
+		}
+		return row
+	}
+	return c.primary.QueryRowxContext(ctx, query, args...)
+}
+
+// ExecContext executes a statement on the primary.
+func (c *Cluster) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.primary.ExecContext(ctx, query, args...)
+}
+
+// BeginTxx begins a transaction on the primary.
+func (c *Cluster) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return c.primary.BeginTxx(ctx, opts)
+}
+
+// PingContext verifies that the primary is reachable. Replicas are not pinged since
+// reads fall back to the primary when they are unreachable.
+func (c *Cluster) PingContext(ctx context.Context) error {
+	return c.primary.PingContext(ctx)
+}
+
+// Close closes the primary and all of the replicas, returning the first error that
+// occurs.
+func (c *Cluster) Close() error {
+	err := c.primary.Close()
+	for _, r := range c.replicas {
+		if rErr := r.Close(); rErr != nil && err == nil {
+			err = rErr
+		}
+	}
+	return err
+}
+
+// nextReplicas returns all of the replicas starting with the one whose turn it is.
+func (c *Cluster) nextReplicas() []*sqlx.DB {
+	if len(c.replicas) == 0 {
+		return nil
+	}
+
+	start := int(atomic.AddUint32(&c.next, 1)-1) % len(c.replicas)
+
+	ordered := make([]*sqlx.DB, 0, len(c.replicas))
+	ordered = append(ordered, c.replicas[start:]...)
+	ordered = append(ordered, c.replicas[:start]...)
+	return ordered
+}
+
+// isUnreachable reports whether the error means that a connection to the database
+// could not be established or has been broken.
+func isUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,213 @@
+package psqlutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCluster(t *testing.T) {
+	errUnreachable := &net.OpError{
+		Op:  "dial",
+		Net: "tcp",
+		Err: errors.New("connection refused"),
+	}
+
+	query := func(c *Cluster) error {
+		rows, err := c.QueryxContext(context.Background(), "SELECT 1")
+		if err != nil {
+			return err
+		}
+		return rows.Close()
+	}
+
+	queryRow := func(c *Cluster) error {
+		var n int
+		return c.QueryRowxContext(context.Background(), "SELECT 1").Scan(&n)
+	}
+
+	tests := []struct {
+		name          string
+		dbName        string
+		replicas      int
+		mockFn        func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock)
+		fn            func(*Cluster) error
+		calls         int
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "route queries to primary without replicas",
+			dbName:   "cluster_no_replicas",
+			replicas: 0,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				primary.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            query,
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "route queries to replicas in round-robin order",
+			dbName:   "cluster_round_robin",
+			replicas: 2,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+				replicas[1].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+				replicas[0].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            query,
+			calls:         3,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "route single row queries to replicas in round-robin order",
+			dbName:   "cluster_round_robin_row",
+			replicas: 2,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+				replicas[1].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            queryRow,
+			calls:         2,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "skip unreachable replica",
+			dbName:   "cluster_skip_replica",
+			replicas: 2,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnError(errUnreachable)
+				replicas[1].ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            query,
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "fall back to primary when all replicas are unreachable",
+			dbName:   "cluster_fallback",
+			replicas: 2,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnError(errUnreachable)
+				replicas[1].ExpectQuery("SELECT 1").WillReturnError(errUnreachable)
+				primary.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            query,
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "fall back to primary for single row query when all replicas are unreachable",
+			dbName:   "cluster_fallback_row",
+			replicas: 1,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnError(errUnreachable)
+				primary.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+			},
+			fn:            queryRow,
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "return error of reachable replica without fallback",
+			dbName:   "cluster_replica_error",
+			replicas: 2,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				replicas[0].ExpectQuery("SELECT 1").WillReturnError(errors.New("syntax error"))
+			},
+			fn:            query,
+			calls:         1,
+			expectedErrFn: assert.Error,
+		},
+		{
+			name:     "route statements to primary",
+			dbName:   "cluster_exec",
+			replicas: 1,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				primary.ExpectExec("DELETE FROM spots").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			fn: func(c *Cluster) error {
+				_, err := c.ExecContext(context.Background(), "DELETE FROM spots")
+				return err
+			},
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name:     "route transactions to primary",
+			dbName:   "cluster_tx",
+			replicas: 1,
+			mockFn: func(primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+				primary.ExpectBegin()
+				primary.ExpectCommit()
+			},
+			fn: func(c *Cluster) error {
+				tx, err := c.BeginTxx(context.Background(), nil)
+				if err != nil {
+					return err
+				}
+				return tx.Commit()
+			},
+			calls:         1,
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primaryCfg := Config{
+				DatabaseName: test.dbName + "_primary",
+			}
+			primaryDB, primaryMock, err := sqlmock.NewWithDSN(primaryCfg.String())
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer primaryDB.Close()
+
+			var (
+				replicaCfgs  []Config
+				replicaMocks []sqlmock.Sqlmock
+			)
+			for i := 0; i < test.replicas; i++ {
+				cfg := Config{
+					DatabaseName: test.dbName + "_replica_" + string(rune('a'+i)),
+				}
+				replicaDB, replicaMock, err := sqlmock.NewWithDSN(cfg.String())
+				if err != nil {
+					assert.Fail(t, err.Error())
+				}
+				defer replicaDB.Close()
+
+				replicaCfgs = append(replicaCfgs, cfg)
+				replicaMocks = append(replicaMocks, replicaMock)
+			}
+
+			test.mockFn(primaryMock, replicaMocks)
+
+			c, err := NewCluster(DriverNameSQLMock, primaryCfg, replicaCfgs)
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer c.Close()
+
+			for i := 0; i < test.calls; i++ {
+				test.expectedErrFn(t, test.fn(c))
+			}
+
+			assert.NoError(t, primaryMock.ExpectationsWereMet())
+			for _, m := range replicaMocks {
+				assert.NoError(t, m.ExpectationsWereMet())
+			}
+		})
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	assert.False(t, isUnreachable(nil))
+	assert.False(t, isUnreachable(errors.New("syntax error")))
+	assert.True(t, isUnreachable(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+}
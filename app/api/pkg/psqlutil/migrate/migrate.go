@@ -0,0 +1,338 @@
+// Package migrate applies SQL migrations to a PostgreSQL database.
+//
+// Migrations are read from files named {version}_{title}.up.sql and
+// {version}_{title}.down.sql. The current version is tracked in the same
+// schema_migrations table that github.com/golang-migrate/migrate uses, so that
+// both tools can be used against the same database interchangeably.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNoChange is used when there is no migration to revert.
+	ErrNoChange = errors.New("no change")
+
+	// ErrDirty is used when a previous migration failed half-way, which leaves
+	// the database in an unknown state that needs to be fixed manually.
+	ErrDirty = errors.New("database is dirty")
+
+	// ErrAhead is used when the database has a version that is newer than any of
+	// the known migrations, which happens when an older binary runs against a
+	// database migrated by a newer one.
+	ErrAhead = errors.New("database is ahead of migrations")
+)
+
+const (
+	createTableSQL = "CREATE TABLE IF NOT EXISTS schema_migrations " +
+		"(version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"
+
+	// lockID identifies the advisory lock that prevents multiple processes from
+	// migrating the same database at once.
+	lockID = 7365328
+
+	lockSQL          = "SELECT pg_advisory_xact_lock($1)"
+	selectVersionSQL = "SELECT version, dirty FROM schema_migrations LIMIT 1"
+	deleteVersionSQL = "DELETE FROM schema_migrations"
+	insertVersionSQL = "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)"
+)
+
+var fileNameRegexp = regexp.MustCompile(`^([0-9]+)_(.+)\.(up|down)\.sql$`)
+
+// Migration holds SQL statements for applying and reverting a single version of
+// a database schema.
+type Migration struct {
+	Version uint
+	Title   string
+	Up      string
+	Down    string
+}
+
+// Migrator applies and reverts migrations.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	logger     *logrus.Logger
+}
+
+// Option is an optional function for Migrator.
+type Option func(*Migrator)
+
+// WithLogger sets a logger for reporting every applied and reverted migration.
+// Nothing is logged by default.
+func WithLogger(l *logrus.Logger) Option {
+	return func(m *Migrator) {
+		m.logger = l
+	}
+}
+
+// New returns a new *Migrator that reads migrations from the root directory of the
+// given file system using various options. Files that do not follow the naming
+// convention are ignored.
+func New(db *sqlx.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		matches := fileNameRegexp.FindStringSubmatch(e.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version of %s: %w", e.Name(), err)
+		}
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", e.Name(), err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{
+				Version: uint(version),
+				Title:   matches[2],
+			}
+			byVersion[uint(version)] = m
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d is missing an up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	m := &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+// Up applies all migrations that are newer than the current version. Every
+// migration is applied in a separate transaction, so a failed migration leaves
+// the database at the version of the last successful one. Applying migrations to
+// an up-to-date database changes nothing. ErrAhead is returned when the database
+// has a version newer than any of the known migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	for {
+		var (
+			done    bool
+			applied Migration
+		)
+		err := m.step(ctx, func(tx *sqlx.Tx, current uint) error {
+			if current > m.latest() {
+				return fmt.Errorf("%w: version %d is newer than %d", ErrAhead, current, m.latest())
+			}
+
+			next, ok := m.next(current)
+			if !ok {
+				done = true
+				return nil
+			}
+
+			if _, err := tx.ExecContext(ctx, next.Up); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", next.Version, err)
+			}
+
+			applied = next
+			return setVersion(ctx, tx, next.Version)
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		m.logf("applied migration %d_%s", applied.Version, applied.Title)
+	}
+}
+
+// Down reverts the most recently applied migration. ErrNoChange is returned when
+// no migration has been applied.
+func (m *Migrator) Down(ctx context.Context) error {
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	var reverted Migration
+	err := m.step(ctx, func(tx *sqlx.Tx, current uint) error {
+		if current == 0 {
+			return ErrNoChange
+		}
+
+		i := sort.Search(len(m.migrations), func(i int) bool {
+			return m.migrations[i].Version >= current
+		})
+		if i == len(m.migrations) || m.migrations[i].Version != current {
+			return fmt.Errorf("migration %d is not found", current)
+		}
+		if m.migrations[i].Down == "" {
+			return fmt.Errorf("migration %d is missing a down file", current)
+		}
+
+		if _, err := tx.ExecContext(ctx, m.migrations[i].Down); err != nil {
+			return fmt.Errorf("failed to revert migration %d: %w", current, err)
+		}
+
+		var previous uint
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+
+		reverted = m.migrations[i]
+		return setVersion(ctx, tx, previous)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logf("reverted migration %d_%s", reverted.Version, reverted.Title)
+	return nil
+}
+
+// Version returns the version of the most recently applied migration, or zero if
+// none has been applied yet.
+func (m *Migrator) Version(ctx context.Context) (uint, error) {
+	if err := m.createTable(ctx); err != nil {
+		return 0, err
+	}
+
+	var version uint
+	err := m.step(ctx, func(_ *sqlx.Tx, current uint) error {
+		version = current
+		return nil
+	})
+	return version, err
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, createTableSQL); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// step calls the given function within a transaction that holds the migration lock,
+// passing it the current version.
+func (m *Migrator) step(ctx context.Context, fn func(tx *sqlx.Tx, current uint) error) error {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := m.stepTx(ctx, tx, fn); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) stepTx(ctx context.Context, tx *sqlx.Tx, fn func(tx *sqlx.Tx, current uint) error) error {
+	if _, err := tx.ExecContext(ctx, lockSQL, lockID); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	var (
+		version uint
+		dirty   bool
+	)
+	if err := tx.QueryRowxContext(ctx, selectVersionSQL).Scan(&version, &dirty); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to read version: %w", err)
+		}
+	}
+	if dirty {
+		return fmt.Errorf("%w: version %d", ErrDirty, version)
+	}
+
+	return fn(tx, version)
+}
+
+// latest returns the version of the newest known migration, or zero if there are
+// no migrations.
+func (m *Migrator) latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) logf(format string, args ...interface{}) {
+	if m.logger == nil {
+		return
+	}
+	m.logger.Infof(format, args...)
+}
+
+func (m *Migrator) next(current uint) (Migration, bool) {
+	for _, mg := range m.migrations {
+		if mg.Version > current {
+			return mg, true
+		}
+	}
+	return Migration{}, false
+}
+
+func setVersion(ctx context.Context, tx *sqlx.Tx, version uint) error {
+	if _, err := tx.ExecContext(ctx, deleteVersionSQL); err != nil {
+		return fmt.Errorf("failed to delete version: %w", err)
+	}
+
+	if version == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, insertVersionSQL, version); err != nil {
+		return fmt.Errorf("failed to insert version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
+)
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"1_create_table_a.up.sql":   {Data: []byte("CREATE TABLE a")},
+		"1_create_table_a.down.sql": {Data: []byte("DROP TABLE a")},
+		"2_create_table_b.up.sql":   {Data: []byte("CREATE TABLE b")},
+		"2_create_table_b.down.sql": {Data: []byte("DROP TABLE b")},
+		"migrations.go":             {Data: []byte("package migrations")},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name               string
+		fsys               fstest.MapFS
+		expectedMigrations []Migration
+		expectedErrFn      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for migration without up file",
+			fsys: fstest.MapFS{
+				"1_create_table_a.down.sql": {Data: []byte("DROP TABLE a")},
+			},
+			expectedMigrations: nil,
+			expectedErrFn:      assert.Error,
+		},
+		{
+			name: "return migrations sorted by version without error",
+			fsys: fstest.MapFS{
+				"10_create_table_c.up.sql":  {Data: []byte("CREATE TABLE c")},
+				"2_create_table_b.up.sql":   {Data: []byte("CREATE TABLE b")},
+				"2_create_table_b.down.sql": {Data: []byte("DROP TABLE b")},
+				"README.md":                 {Data: []byte("readme")},
+			},
+			expectedMigrations: []Migration{
+				{
+					Version: 2,
+					Title:   "create_table_b",
+					Up:      "CREATE TABLE b",
+					Down:    "DROP TABLE b",
+				},
+				{
+					Version: 10,
+					Title:   "create_table_c",
+					Up:      "CREATE TABLE c",
+				},
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := New(nil, test.fsys)
+			test.expectedErrFn(t, err)
+
+			if m == nil {
+				assert.Nil(t, test.expectedMigrations)
+				return
+			}
+			assert.Equal(t, test.expectedMigrations, m.migrations)
+		})
+	}
+}
+
+func expectStep(mock sqlmock.Sqlmock, version uint, dirty bool) {
+	mock.ExpectBegin()
+	mock.
+		ExpectExec(regexp.QuoteMeta(lockSQL)).
+		WithArgs(lockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+	mock.
+		ExpectQuery(regexp.QuoteMeta(selectVersionSQL)).
+		WillReturnRows(rows)
+}
+
+func expectSetVersion(mock sqlmock.Sqlmock, version uint) {
+	mock.
+		ExpectExec(regexp.QuoteMeta(deleteVersionSQL)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.
+			ExpectExec(regexp.QuoteMeta(insertVersionSQL)).
+			WithArgs(version).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func expectCreateTable(mock sqlmock.Sqlmock) {
+	mock.
+		ExpectExec(regexp.QuoteMeta(createTableSQL)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for dirty database",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 1, true)
+				m.ExpectRollback()
+			},
+			expectedErrFn: testutil.IsError(ErrDirty),
+		},
+		{
+			name: "return error for database ahead of migrations",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 3, false)
+				m.ExpectRollback()
+			},
+			expectedErrFn: testutil.IsError(ErrAhead),
+		},
+		{
+			name: "return error when migration fails",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 1, false)
+				m.
+					ExpectExec(regexp.QuoteMeta("CREATE TABLE b")).
+					WillReturnError(errors.New("something went wrong"))
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "apply all pending migrations without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+
+				expectStep(m, 0, false)
+				m.
+					ExpectExec(regexp.QuoteMeta("CREATE TABLE a")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSetVersion(m, 1)
+				m.ExpectCommit()
+
+				expectStep(m, 1, false)
+				m.
+					ExpectExec(regexp.QuoteMeta("CREATE TABLE b")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSetVersion(m, 2)
+				m.ExpectCommit()
+
+				expectStep(m, 2, false)
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			m, err := New(sqlx.NewDb(db, "sqlmock"), newTestFS())
+			assert.NoError(t, err)
+
+			err = m.Up(context.Background())
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Up_Logging(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer db.Close()
+
+	expectCreateTable(mock)
+	expectStep(mock, 1, false)
+	mock.
+		ExpectExec(regexp.QuoteMeta("CREATE TABLE b")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSetVersion(mock, 2)
+	mock.ExpectCommit()
+	expectStep(mock, 2, false)
+	mock.ExpectCommit()
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	m, err := New(sqlx.NewDb(db, "sqlmock"), newTestFS(), WithLogger(logger))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, m.Up(context.Background()))
+	assert.Equal(t, "level=info msg=\"applied migration 2_create_table_b\"\n", buf.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		expectedErrFn assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error when no migration has been applied",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 0, false)
+				m.ExpectRollback()
+			},
+			expectedErrFn: testutil.IsError(ErrNoChange),
+		},
+		{
+			name: "return error for unknown version",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 3, false)
+				m.ExpectRollback()
+			},
+			expectedErrFn: assert.Error,
+		},
+		{
+			name: "revert latest migration without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 2, false)
+				m.
+					ExpectExec(regexp.QuoteMeta("DROP TABLE b")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSetVersion(m, 1)
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+		{
+			name: "revert first migration without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 1, false)
+				m.
+					ExpectExec(regexp.QuoteMeta("DROP TABLE a")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectSetVersion(m, 0)
+				m.ExpectCommit()
+			},
+			expectedErrFn: assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			m, err := New(sqlx.NewDb(db, "sqlmock"), newTestFS())
+			assert.NoError(t, err)
+
+			err = m.Down(context.Background())
+			test.expectedErrFn(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Version(t *testing.T) {
+	tests := []struct {
+		name            string
+		mockFn          func(sqlmock.Sqlmock)
+		expectedVersion uint
+		expectedErrFn   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for dirty database",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 2, true)
+				m.ExpectRollback()
+			},
+			expectedVersion: 0,
+			expectedErrFn:   testutil.IsError(ErrDirty),
+		},
+		{
+			name: "return zero for fresh database without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 0, false)
+				m.ExpectCommit()
+			},
+			expectedVersion: 0,
+			expectedErrFn:   assert.NoError,
+		},
+		{
+			name: "return current version without error",
+			mockFn: func(m sqlmock.Sqlmock) {
+				expectCreateTable(m)
+				expectStep(m, 2, false)
+				m.ExpectCommit()
+			},
+			expectedVersion: 2,
+			expectedErrFn:   assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer db.Close()
+
+			test.mockFn(mock)
+
+			m, err := New(sqlx.NewDb(db, "sqlmock"), newTestFS())
+			assert.NoError(t, err)
+
+			version, err := m.Version(context.Background())
+			test.expectedErrFn(t, err)
+			assert.Equal(t, test.expectedVersion, version)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
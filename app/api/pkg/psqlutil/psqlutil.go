@@ -1,11 +1,15 @@
 package psqlutil
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -20,13 +24,137 @@ const (
 	sslModeNameDisable = "disable"
 )
 
+// ErrInvalidPoolConfig is used when connection pool settings contradict each other.
+var ErrInvalidPoolConfig = errors.New("invalid pool config")
+
 // NewDB opens a new github.com/jmoiron/sqlx *sqlx.DB using the given database
-// driver name and configuration.
+// driver name, configuration, and other options, and applies connection pool
+// settings of the configuration to it.
 //
 // The caller is expected to register a PostgreSQL driver to the standard database/sql
 // package prior to envoking this function.
-func NewDB(driverName string, cfg Config) (*sqlx.DB, error) {
-	return sqlx.Open(driverName, cfg.String())
+func NewDB(driverName string, cfg Config, opts ...Option) (*sqlx.DB, error) {
+	return NewDBContext(context.Background(), driverName, cfg, opts...)
+}
+
+// NewDBContext is the same as NewDB, except that retries of connecting to the
+// database stop once the given context is done.
+func NewDBContext(ctx context.Context, driverName string, cfg Config, opts ...Option) (*sqlx.DB, error) {
+	if cfg.MaxOpenConns > 0 && cfg.MaxIdleConns > cfg.MaxOpenConns {
+		return nil, fmt.Errorf("%w: max idle connections must not exceed max open connections", ErrInvalidPoolConfig)
+	}
+
+	o := options{
+		logger: logrus.StandardLogger(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	db, err := sqlx.Open(driverName, cfg.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	if o.connectAttempts > 0 {
+		if err := connect(ctx, db, o); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// Option is an optional function for NewDB.
+type Option func(*options)
+
+type options struct {
+	connectAttempts int
+	connectBackoff  time.Duration
+	connectTimeout  time.Duration
+	logger          *logrus.Logger
+}
+
+// WithConnectRetry makes NewDB ping the database up to the given number of attempts
+// before returning it, which is useful when the database might start up later than
+// its clients. The given backoff is waited after the first failed attempt and gets
+// doubled after each subsequent one.
+//
+// By default, NewDB does not connect to the database at all, leaving it up to the
+// first query.
+func WithConnectRetry(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.connectAttempts = attempts
+		o.connectBackoff = backoff
+	}
+}
+
+// WithConnectTimeout limits how long each of the attempts made due to
+// WithConnectRetry waits for the database to respond, so that an unresponsive
+// host fails an attempt instead of hanging. Attempts are only bounded by the
+// context of NewDBContext by default.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.connectTimeout = d
+	}
+}
+
+// WithLogger sets a custom *logrus.Logger for logging failed connection attempts.
+// A default *logrus.Logger is used unless overwritten.
+func WithLogger(l *logrus.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+func connect(ctx context.Context, db *sqlx.DB, o options) error {
+	backoff := o.connectBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx, db, o.connectTimeout)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= o.connectAttempts {
+			return fmt.Errorf("failed to connect after %d attempt(s): %w", attempt, err)
+		}
+
+		o.logger.
+			WithError(err).
+			Warnf("failed to connect to database (attempt %d of %d), retrying in %s", attempt, o.connectAttempts, backoff)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to connect after %d attempt(s): %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+func ping(ctx context.Context, db *sqlx.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return db.PingContext(ctx)
 }
 
 // Config holds configuration for connecting to a PostgreSQL database.
@@ -37,6 +165,14 @@ type Config struct {
 	Password     string
 	DatabaseName string
 	SSLMode      SSLMode
+
+	// MaxOpenConns, MaxIdleConns, ConnMaxLifetime, and ConnMaxIdleTime tune the
+	// connection pool. Zero values keep defaults of the standard database/sql
+	// package. MaxIdleConns must not exceed MaxOpenConns when both are set.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 // String returns the confiration as a DSN string.
@@ -107,6 +243,23 @@ func Wildcard(s string) string {
 	return "%" + s + "%"
 }
 
+// likeEscaper escapes characters that have a special meaning in PostgreSQL's LIKE
+// patterns using the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the given string so that it is matched literally when used
+// within a PostgreSQL's LIKE or ILIKE pattern.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// ILikeEscaped returns a github.com/Masterminds/squirrel expression for PostgreSQL's
+// ILIKE clause with an explicit backslash escape character, which is meant to be
+// used with patterns escaped by EscapeLike.
+func ILikeEscaped(key, pattern string) sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf(`%s ILIKE ? ESCAPE '\'`, key), pattern)
+}
+
 // Between returns a github.com/Masterminds/squirrel expression for PostgreSQL's
 // BETWEEN clause using the given arguments.
 func Between(key string, min, max float64) sq.Sqlizer {
@@ -118,3 +271,32 @@ func Between(key string, min, max float64) sq.Sqlizer {
 func CastAsVarchar(key string) string {
 	return fmt.Sprintf("CAST(%s AS VARCHAR)", key)
 }
+
+// TagQuery prepends a comment with the given key and value to the query, so that
+// the query can be correlated with its origin in PostgreSQL logs and pg_stat_activity.
+// Characters of the value other than letters, digits, and "-_.:" are dropped so
+// that the value cannot terminate the comment. The query is returned as is when
+// nothing is left of the value.
+func TagQuery(query, key, value string) string {
+	value = strings.Map(func(r rune) rune {
+		if isQueryTagRune(r) {
+			return r
+		}
+		return -1
+	}, value)
+	if value == "" {
+		return query
+	}
+	return fmt.Sprintf("/* %s=%s */ %s", key, value, query)
+}
+
+func isQueryTagRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	case r == '-', r == '_', r == '.', r == ':':
+		return true
+	default:
+		return false
+	}
+}
@@ -1,11 +1,195 @@
 package psqlutil
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/ztimes2/tolqin/app/api/pkg/testutil"
 )
 
+func TestNewDB(t *testing.T) {
+	tests := []struct {
+		name                 string
+		config               Config
+		expectedMaxOpenConns int
+		expectedErrFn        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return error for more idle than open connections",
+			config: Config{
+				DatabaseName: "invalid_pool",
+				MaxOpenConns: 5,
+				MaxIdleConns: 10,
+			},
+			expectedErrFn: testutil.IsError(ErrInvalidPoolConfig),
+		},
+		{
+			name: "return db with default pool settings without error",
+			config: Config{
+				DatabaseName: "default_pool",
+			},
+			expectedMaxOpenConns: 0,
+			expectedErrFn:        assert.NoError,
+		},
+		{
+			name: "return db with tuned pool settings without error",
+			config: Config{
+				DatabaseName:    "tuned_pool",
+				MaxOpenConns:    25,
+				MaxIdleConns:    10,
+				ConnMaxLifetime: 30 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+			},
+			expectedMaxOpenConns: 25,
+			expectedErrFn:        assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, _, err := sqlmock.NewWithDSN(test.config.String())
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer mockDB.Close()
+
+			db, err := NewDB(DriverNameSQLMock, test.config)
+			test.expectedErrFn(t, err)
+			if db == nil {
+				return
+			}
+			defer db.Close()
+
+			assert.Equal(t, test.expectedMaxOpenConns, db.Stats().MaxOpenConnections)
+		})
+	}
+}
+
+func TestNewDBContext_ConnectRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbName           string
+		ctxFn            func() context.Context
+		mockFn           func(sqlmock.Sqlmock)
+		expectedAttempts int
+		expectedErrFn    assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "return error after running out of attempts",
+			dbName: "retry_exhausted",
+			ctxFn:  context.Background,
+			mockFn: func(m sqlmock.Sqlmock) {
+				for i := 0; i < 3; i++ {
+					m.ExpectPing().WillReturnError(errors.New("connection refused"))
+				}
+			},
+			expectedAttempts: 2,
+			expectedErrFn:    assert.Error,
+		},
+		{
+			name:   "return error for canceled context",
+			dbName: "retry_canceled",
+			ctxFn: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectPing().WillReturnError(errors.New("connection refused"))
+			},
+			expectedAttempts: 1,
+			expectedErrFn: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, context.Canceled)
+			},
+		},
+		{
+			name:   "return db after successful retry without error",
+			dbName: "retry_succeeded",
+			ctxFn:  context.Background,
+			mockFn: func(m sqlmock.Sqlmock) {
+				m.ExpectPing().WillReturnError(errors.New("connection refused"))
+				m.ExpectPing()
+			},
+			expectedAttempts: 1,
+			expectedErrFn:    assert.NoError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Config{
+				DatabaseName: test.dbName,
+			}
+
+			mockDB, mock, err := sqlmock.NewWithDSN(cfg.String(), sqlmock.MonitorPingsOption(true))
+			if err != nil {
+				assert.Fail(t, err.Error())
+			}
+			defer mockDB.Close()
+
+			test.mockFn(mock)
+
+			var logs bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&logs)
+
+			db, err := NewDBContext(
+				test.ctxFn(),
+				DriverNameSQLMock,
+				cfg,
+				WithConnectRetry(3, time.Millisecond),
+				WithLogger(logger),
+			)
+			test.expectedErrFn(t, err)
+			if db != nil {
+				db.Close()
+			}
+
+			assert.Equal(t, test.expectedAttempts, bytes.Count(logs.Bytes(), []byte("failed to connect to database")))
+		})
+	}
+}
+
+func TestNewDBContext_ConnectTimeout(t *testing.T) {
+	cfg := Config{
+		DatabaseName: "connect_timeout",
+	}
+
+	mockDB, mock, err := sqlmock.NewWithDSN(cfg.String(), sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	defer mockDB.Close()
+
+	mock.ExpectPing().WillDelayFor(time.Minute)
+	mock.ExpectPing()
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+
+	db, err := NewDBContext(
+		context.Background(),
+		DriverNameSQLMock,
+		cfg,
+		WithConnectRetry(2, time.Millisecond),
+		WithConnectTimeout(10*time.Millisecond),
+		WithLogger(logger),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	db.Close()
+
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("failed to connect to database")))
+}
+
 func TestNewSSLMode(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -124,6 +308,18 @@ func TestWildcard(t *testing.T) {
 	assert.Equal(t, "%test%", s)
 }
 
+func TestEscapeLike(t *testing.T) {
+	s := EscapeLike(`50%_off\`)
+	assert.Equal(t, `50\%\_off\\`, s)
+}
+
+func TestILikeEscaped(t *testing.T) {
+	expr, args, err := ILikeEscaped("column", `50\%%`).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `column ILIKE ? ESCAPE '\'`, expr)
+	assert.Equal(t, []interface{}{`50\%%`}, args)
+}
+
 func TestBetween(t *testing.T) {
 	expr, args, err := Between("column", 1, 100).ToSql()
 	assert.NoError(t, err)
@@ -135,3 +331,49 @@ func TestCastAsVarchar(t *testing.T) {
 	s := CastAsVarchar("test")
 	assert.Equal(t, "CAST(test AS VARCHAR)", s)
 }
+
+func TestTagQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		key           string
+		value         string
+		expectedQuery string
+	}{
+		{
+			name:          "prepend comment with value",
+			query:         "SELECT 1",
+			key:           "request_id",
+			value:         "7b0c5b4e-1b2f-4c4a-9d7e-2f1d0b3a4c5d",
+			expectedQuery: "/* request_id=7b0c5b4e-1b2f-4c4a-9d7e-2f1d0b3a4c5d */ SELECT 1",
+		},
+		{
+			name:          "drop characters that could terminate comment",
+			query:         "SELECT 1",
+			key:           "request_id",
+			value:         "abc*/DROP TABLE spots;/*",
+			expectedQuery: "/* request_id=abcDROPTABLEspots */ SELECT 1",
+		},
+		{
+			name:          "return query as is for empty value",
+			query:         "SELECT 1",
+			key:           "request_id",
+			value:         "",
+			expectedQuery: "SELECT 1",
+		},
+		{
+			name:          "return query as is when nothing is left of value",
+			query:         "SELECT 1",
+			key:           "request_id",
+			value:         "*/;",
+			expectedQuery: "SELECT 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := TagQuery(test.query, test.key, test.value)
+			assert.Equal(t, test.expectedQuery, query)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package slug provides functions for making human-friendly identifiers that can
+// be embedded into URLs.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength caps a number of characters of slugs made by Make, so that suffixes
+// appended by Unique still keep slugs reasonably short.
+const MaxLength = 80
+
+// transliterations maps lower-case letters that have a conventional spelling in
+// ASCII to it. Latin letters with diacritics, as well as Russian and Kazakh
+// Cyrillic letters, are covered.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ģ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ř': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s",
+	'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+	'ә': "a", 'ғ': "g", 'қ': "q", 'ң': "n", 'ө': "o", 'ұ': "u", 'ү': "u", 'һ': "h",
+	'і': "i",
+}
+
+// Make joins the given parts into a slug, e.g. "Uluwatu" and "Bali" into
+// "uluwatu-bali". Slugs consist of lower-case letters and digits separated by
+// single hyphens. Letters listed in transliterations are spelled in ASCII, other
+// letters are kept as is, and the rest of the characters are treated as
+// separators. An empty string is returned when the parts contain neither letters
+// nor digits.
+func Make(parts ...string) string {
+	var (
+		b         strings.Builder
+		length    int
+		separated bool
+	)
+
+	write := func(s string) {
+		if separated && length > 0 {
+			b.WriteByte('-')
+			length++
+		}
+		separated = false
+		b.WriteString(s)
+		length += len([]rune(s))
+	}
+
+	for _, part := range parts {
+		separated = true
+
+		for _, r := range strings.ToLower(part) {
+			if length >= MaxLength {
+				break
+			}
+
+			if s, ok := transliterations[r]; ok {
+				if s != "" {
+					write(s)
+				}
+				continue
+			}
+
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				write(string(r))
+				continue
+			}
+
+			separated = true
+		}
+	}
+
+	s := b.String()
+	if runes := []rune(s); len(runes) > MaxLength {
+		s = string(runes[:MaxLength])
+	}
+	return strings.TrimRight(s, "-")
+}
+
+// Unique returns the given slug unless it is among the taken ones. Otherwise, the
+// slug is suffixed with the first of -2, -3, and so on that is not taken.
+func Unique(slug string, taken []string) string {
+	set := make(map[string]struct{}, len(taken))
+	for _, t := range taken {
+		set[t] = struct{}{}
+	}
+
+	if _, ok := set[slug]; !ok {
+		return slug
+	}
+
+	for n := 2; ; n++ {
+		candidate := slug + "-" + strconv.Itoa(n)
+		if _, ok := set[candidate]; !ok {
+			return candidate
+		}
+	}
+}
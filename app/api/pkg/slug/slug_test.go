@@ -0,0 +1,125 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name         string
+		parts        []string
+		expectedSlug string
+	}{
+		{
+			name:         "return empty string for no parts",
+			parts:        nil,
+			expectedSlug: "",
+		},
+		{
+			name:         "return empty string for parts without letters and digits",
+			parts:        []string{"  ", "!?-", ""},
+			expectedSlug: "",
+		},
+		{
+			name:         "join parts with hyphens",
+			parts:        []string{"Uluwatu", "Bali"},
+			expectedSlug: "uluwatu-bali",
+		},
+		{
+			name:         "skip empty parts",
+			parts:        []string{"Uluwatu", "", " "},
+			expectedSlug: "uluwatu",
+		},
+		{
+			name:         "collapse whitespace and punctuation into single hyphens",
+			parts:        []string{"  Pipeline -- (Banzai)!", "O'ahu, Hawaii "},
+			expectedSlug: "pipeline-banzai-o-ahu-hawaii",
+		},
+		{
+			name:         "keep digits",
+			parts:        []string{"Spot #42", "Zone 3"},
+			expectedSlug: "spot-42-zone-3",
+		},
+		{
+			name:         "transliterate latin letters with diacritics",
+			parts:        []string{"Praia do Guincho", "Cascais, Lisboa Região"},
+			expectedSlug: "praia-do-guincho-cascais-lisboa-regiao",
+		},
+		{
+			name:         "transliterate ligatures",
+			parts:        []string{"Straße Æbeltoft"},
+			expectedSlug: "strasse-aebeltoft",
+		},
+		{
+			name:         "transliterate russian letters",
+			parts:        []string{"Щучье Озеро", "Карелия"},
+			expectedSlug: "shchuche-ozero-kareliya",
+		},
+		{
+			name:         "transliterate kazakh letters",
+			parts:        []string{"Қапшағай", "Алматы облысы"},
+			expectedSlug: "qapshagay-almaty-oblysy",
+		},
+		{
+			name:         "keep letters without transliteration",
+			parts:        []string{"湘南", "Kanagawa"},
+			expectedSlug: "湘南-kanagawa",
+		},
+		{
+			name:         "cap length",
+			parts:        []string{strings.Repeat("a", MaxLength-1), "bcd"},
+			expectedSlug: strings.Repeat("a", MaxLength-1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slug := Make(test.parts...)
+			assert.Equal(t, test.expectedSlug, slug)
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name         string
+		slug         string
+		taken        []string
+		expectedSlug string
+	}{
+		{
+			name:         "return slug when nothing is taken",
+			slug:         "uluwatu-bali",
+			taken:        nil,
+			expectedSlug: "uluwatu-bali",
+		},
+		{
+			name:         "return slug when it is not taken",
+			slug:         "uluwatu-bali",
+			taken:        []string{"uluwatu-bali-2"},
+			expectedSlug: "uluwatu-bali",
+		},
+		{
+			name:         "append suffix when slug is taken",
+			slug:         "uluwatu-bali",
+			taken:        []string{"uluwatu-bali"},
+			expectedSlug: "uluwatu-bali-2",
+		},
+		{
+			name:         "append first suffix that is not taken",
+			slug:         "uluwatu-bali",
+			taken:        []string{"uluwatu-bali-3", "uluwatu-bali", "uluwatu-bali-2", "uluwatu-bali-5"},
+			expectedSlug: "uluwatu-bali-4",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slug := Unique(test.slug, test.taken)
+			assert.Equal(t, test.expectedSlug, slug)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package valerra
 
+import "unicode/utf8"
+
 // StringNotEmpty returns a condition that checks if the given string is not empty.
 func StringNotEmpty(s string) Condition {
 	return func() bool {
@@ -14,3 +16,11 @@ func StringLessOrEqual(s string, size int) Condition {
 		return len(s) <= size
 	}
 }
+
+// StringGreaterOrEqual returns a condition that checks if the number of characters
+// in the given string is greater or equal to the given size.
+func StringGreaterOrEqual(s string, size int) Condition {
+	return func() bool {
+		return utf8.RuneCountInString(s) >= size
+	}
+}
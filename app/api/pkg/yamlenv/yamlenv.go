@@ -0,0 +1,92 @@
+package yamlenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/heetch/confita/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLEnv implements Backend interface of the github.com/heetch/confita/backend
+// package and provides functionality for loading and fetching configuration
+// variables from a YAML file. The file is expected to be a flat mapping of the
+// same keys as environment variables, e.g.:
+//
+//	DB_HOST: localhost
+//	CORS_ALLOWED_ORIGINS:
+//	  - http://localhost:3000
+//	  - http://localhost:8080
+//
+// Sequences are joined with commas, which is how confita splits values of slices.
+type YAMLEnv struct {
+	path   string
+	values map[string]string
+}
+
+// NewBackend initializes a new *YAMLEnv backend by reading the file at the given
+// path.
+func NewBackend(path string) (*YAMLEnv, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		v, err := stringify(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+		values[key] = v
+	}
+
+	return &YAMLEnv{
+		path:   path,
+		values: values,
+	}, nil
+}
+
+func stringify(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := stringify(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("nested mappings are not supported")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// Get fetches a configuration variable by its key from a YAML file.
+func (y *YAMLEnv) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := y.values[key]
+	if !ok {
+		return nil, backend.ErrNotFound
+	}
+
+	return []byte(value), nil
+}
+
+// Name returns a name of this specific Backend's implementation, which is a path
+// of the file.
+func (y *YAMLEnv) Name() string {
+	return y.path
+}
@@ -0,0 +1,117 @@
+package yamlenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heetch/confita/backend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLEnv_Get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(
+		"DB_HOST: localhost\n"+
+			"DB_PORT: 5432\n"+
+			"ACCESS_LOG_ENABLED: false\n"+
+			"SHUTDOWN_TIMEOUT: 10s\n"+
+			"DB_PASSWORD:\n"+
+			"CORS_ALLOWED_ORIGINS:\n"+
+			"  - http://localhost:3000\n"+
+			"  - http://localhost:8080\n",
+	), 0o600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	y, err := NewBackend(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name          string
+		key           string
+		expectedValue string
+		expectedErr   error
+	}{
+		{
+			name:          "return string",
+			key:           "DB_HOST",
+			expectedValue: "localhost",
+		},
+		{
+			name:          "return number as string",
+			key:           "DB_PORT",
+			expectedValue: "5432",
+		},
+		{
+			name:          "return bool as string",
+			key:           "ACCESS_LOG_ENABLED",
+			expectedValue: "false",
+		},
+		{
+			name:          "return duration",
+			key:           "SHUTDOWN_TIMEOUT",
+			expectedValue: "10s",
+		},
+		{
+			name:          "return empty value",
+			key:           "DB_PASSWORD",
+			expectedValue: "",
+		},
+		{
+			name:          "return sequence joined with commas",
+			key:           "CORS_ALLOWED_ORIGINS",
+			expectedValue: "http://localhost:3000,http://localhost:8080",
+		},
+		{
+			name:        "return error for missing key",
+			key:         "DB_NAME",
+			expectedErr: backend.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := y.Get(context.Background(), test.key)
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expectedValue, string(value))
+			}
+		})
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "return error for nested mapping",
+			content: "DB:\n  HOST: localhost\n",
+		},
+		{
+			name:    "return error for malformed yaml",
+			content: "DB_HOST: [localhost\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if !assert.NoError(t, os.WriteFile(path, []byte(test.content), 0o600)) {
+				return
+			}
+
+			_, err := NewBackend(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewBackend(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}